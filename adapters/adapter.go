@@ -1,10 +1,14 @@
 package adapters
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 )
@@ -15,30 +19,115 @@ type Adapter interface {
 	Perform(models.RunResult, *store.Store) models.RunResult
 }
 
+// ContextAdapter is an Adapter whose work, such as an HTTP request, stops
+// once the context is done.
+type ContextAdapter interface {
+	Adapter
+	PerformContext(context.Context, models.RunResult, *store.Store) models.RunResult
+}
+
+// PerformContext performs the adapter's work, returning an error result
+// once the context is done if the work has not finished by then. Only a
+// ContextAdapter's work is cancelled; any other adapter is left to finish
+// in the background, with its result dropped.
+func PerformContext(
+	ctx context.Context,
+	adapter Adapter,
+	input models.RunResult,
+	store *store.Store,
+) models.RunResult {
+	results := make(chan models.RunResult, 1)
+	go func() {
+		if ca, ok := adapter.(ContextAdapter); ok {
+			results <- ca.PerformContext(ctx, input, store)
+		} else {
+			results <- adapter.Perform(input, store)
+		}
+	}()
+
+	select {
+	case rr := <-results:
+		return rr
+	case <-ctx.Done():
+		return input.WithError(ctx.Err())
+	}
+}
+
 // For determines the adapter type to use for a given task
 func For(task models.Task, store *store.Store) (ac Adapter, err error) {
 	switch strings.ToLower(task.Type) {
 	case "httpget":
-		ac = &HttpGet{}
-		err = unmarshalParams(task.Params, ac)
+		hg := &HttpGet{}
+		if err = unmarshalParams(task.Params, hg); err == nil {
+			err = hg.Auth.validate(store)
+		}
+		ac = hg
 	case "httppost":
-		ac = &HttpPost{}
-		err = unmarshalParams(task.Params, ac)
+		hp := &HttpPost{}
+		if err = unmarshalParams(task.Params, hp); err == nil {
+			err = hp.Auth.validate(store)
+		}
+		ac = hp
 	case "jsonparse":
 		ac = &JsonParse{}
 		err = unmarshalParams(task.Params, ac)
+	case "paginate":
+		ac = &Paginate{}
+		err = unmarshalParams(task.Params, ac)
+	case "copy":
+		ac = &Copy{}
+		err = unmarshalParams(task.Params, ac)
+	case "parsenum":
+		ac = &ParseNum{}
+		err = unmarshalParams(task.Params, ac)
 	case "ethbytes32":
 		ac = &EthBytes32{}
 		err = unmarshalParams(task.Params, ac)
 	case "ethuint256":
 		ac = &EthUint256{}
 		err = unmarshalParams(task.Params, ac)
+	case "ethint256":
+		ac = &EthInt256{}
+		err = unmarshalParams(task.Params, ac)
+	case "ethcall":
+		ec := &EthCall{}
+		if err = unmarshalParams(task.Params, ec); err == nil {
+			err = ec.validate()
+		}
+		ac = ec
 	case "ethtx":
 		ac = &EthTx{}
 		err = unmarshalParams(task.Params, ac)
 	case "multiply":
 		ac = &Multiply{}
 		err = unmarshalParams(task.Params, ac)
+	case "aggregate":
+		ac = &Aggregate{}
+		err = unmarshalParams(task.Params, ac)
+	case "twap":
+		ac = &TWAP{}
+		err = unmarshalParams(task.Params, ac)
+	case "random":
+		ac = &Random{}
+		err = unmarshalParams(task.Params, ac)
+	case "metric":
+		m := &Metric{}
+		if err = unmarshalParams(task.Params, m); err == nil {
+			err = m.validate()
+		}
+		ac = m
+	case "parallel":
+		p := &Parallel{}
+		if err = unmarshalParams(task.Params, p); err == nil {
+			err = p.validate(store)
+		}
+		ac = p
+	case "forward":
+		f := &Forward{}
+		if err = unmarshalParams(task.Params, f); err == nil {
+			err = f.validate(store)
+		}
+		ac = f
 	case "noop":
 		ac = &NoOp{}
 		err = unmarshalParams(task.Params, ac)
@@ -47,7 +136,7 @@ func For(task models.Task, store *store.Store) (ac Adapter, err error) {
 		err = unmarshalParams(task.Params, ac)
 	default:
 		if bt, err := store.BridgeTypeFor(task.Type); err != nil {
-			return nil, fmt.Errorf("%s is not a supported adapter type", task.Type)
+			return nil, unsupportedAdapterError(task.Type)
 		} else {
 			ac = &Bridge{bt}
 		}
@@ -55,6 +144,14 @@ func For(task models.Task, store *store.Store) (ac Adapter, err error) {
 	return ac, err
 }
 
+// unsupportedAdapterError is returned by For when the task's type is
+// neither a core adapter nor a bridge.
+type unsupportedAdapterError string
+
+func (e unsupportedAdapterError) Error() string {
+	return fmt.Sprintf("%s is not a supported adapter type", string(e))
+}
+
 func unmarshalParams(params models.JSON, dst interface{}) error {
 	bytes, err := params.MarshalJSON()
 	if err != nil {
@@ -63,20 +160,196 @@ func unmarshalParams(params models.JSON, dst interface{}) error {
 	return json.Unmarshal(bytes, dst)
 }
 
-// Validate that there were no errors in any of the tasks of a job
+// FieldError is a problem with one field of a job spec, named by its path
+// in the spec, such as tasks[1].onError[0].
+type FieldError struct {
+	Field  string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// ValidationError lists the problems with the fields of a job spec.
+type ValidationError []FieldError
+
+// Error returns the reasons of every problem.
+func (ve ValidationError) Error() string {
+	reasons := make([]string, len(ve))
+	for i, fe := range ve {
+		reasons[i] = fe.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Validate that there were no errors in any of the tasks of a job, that
+// their params fit their adapters' schema for the job's schemaVersion,
+// that its webhooks are URLs, that its domain lists hold host names, that
+// its external initiators are registered, that its fluxmonitor initiators
+// can poll, that the address lists of its log initiators exist, and that
+// the events of its ethlog initiators can be decoded. The error is a
+// ValidationError naming each invalid field.
 func Validate(job models.Job, store *store.Store) error {
-	var err error
-	for _, task := range job.Tasks {
-		err = validateTask(task, store)
-		if err != nil {
-			break
+	ve := ValidationError{}
+	if job.DebugSampleRate < 0 || job.DebugSampleRate > 100 {
+		ve = append(ve, FieldError{
+			Field:  "debugSampleRate",
+			Reason: fmt.Sprintf("debugSampleRate must be a percentage between 0 and 100, got %v", job.DebugSampleRate),
+		})
+	}
+	if job.MinPayment != nil && job.MinPayment.ToInt().Sign() < 0 {
+		ve = append(ve, FieldError{
+			Field:  "minPayment",
+			Reason: fmt.Sprintf("minPayment must not be negative, got %v", job.MinPayment),
+		})
+	}
+	if job.Timeout < 0 {
+		ve = append(ve, FieldError{
+			Field:  "timeout",
+			Reason: fmt.Sprintf("timeout must not be negative, got %v", time.Duration(job.Timeout)),
+		})
+	}
+	for i, hook := range job.Webhooks {
+		if u, err := url.ParseRequestURI(hook); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			ve = append(ve, FieldError{
+				Field:  fmt.Sprintf("webhooks[%d]", i),
+				Reason: fmt.Sprintf("webhook must be an http or https URL, got %v", hook),
+			})
 		}
 	}
+	if err := (models.DomainLists{AllowedDomains: job.AllowedDomains}).Validate(); err != nil {
+		ve = append(ve, FieldError{Field: "allowedDomains", Reason: err.Error()})
+	}
+	if err := (models.DomainLists{DeniedDomains: job.DeniedDomains}).Validate(); err != nil {
+		ve = append(ve, FieldError{Field: "deniedDomains", Reason: err.Error()})
+	}
+	for i, initr := range job.Initiators {
+		field := fmt.Sprintf("initiators[%d]", i)
+		switch initr.Type {
+		case models.InitiatorExternal:
+			ve = append(ve, validateExternalInitiator(initr, field, store)...)
+		case models.InitiatorFluxMonitor:
+			ve = append(ve, validateFluxMonitor(initr, field, store)...)
+		case models.InitiatorEthLog:
+			if initr.Event != nil {
+				if err := initr.Event.Validate(); err != nil {
+					ve = append(ve, FieldError{Field: field + ".event", Reason: err.Error()})
+				}
+			}
+			ve = append(ve, validateLogAddresses(initr, field, store)...)
+		case models.InitiatorRunLog:
+			ve = append(ve, validateLogAddresses(initr, field, store)...)
+		}
+	}
+	if job.SchemaVersion < 0 || job.SchemaVersion > models.CurrentSchemaVersion {
+		ve = append(ve, FieldError{
+			Field:  "schemaVersion",
+			Reason: fmt.Sprintf("schemaVersion %v is not supported, the latest is %v", job.SchemaVersion, models.CurrentSchemaVersion),
+		})
+	}
+	// The requests of runlog initiators can supply any of a task's params.
+	dataSupplied := len(job.InitiatorsFor(models.InitiatorRunLog)) > 0
+	for i, task := range job.Tasks {
+		ve = append(ve, validateTask(task, fmt.Sprintf("tasks[%d]", i), job.SchemaVersion, dataSupplied, store)...)
+	}
+
+	if len(ve) > 0 {
+		return ve
+	}
+	return nil
+}
+
+func validateExternalInitiator(initr models.Initiator, field string, store *store.Store) ValidationError {
+	field += ".name"
+	if initr.Name == "" {
+		return ValidationError{{Field: field, Reason: "external initiator must have a name"}}
+	}
+	if _, err := store.FindExternalInitiator(initr.Name); err != nil {
+		return ValidationError{{Field: field, Reason: fmt.Sprintf("external initiator %v is not registered", initr.Name)}}
+	}
+	return nil
+}
 
-	return err
+func validateLogAddresses(initr models.Initiator, field string, store *store.Store) ValidationError {
+	ve := ValidationError{}
+	for i, address := range initr.Addresses {
+		if address == (common.Address{}) {
+			ve = append(ve, FieldError{Field: fmt.Sprintf("%v.addresses[%d]", field, i), Reason: "addresses cannot hold the zero address"})
+		}
+	}
+	if initr.AddressList != "" {
+		if _, err := store.FindAddressList(initr.AddressList); err != nil {
+			ve = append(ve, FieldError{Field: field + ".addressList", Reason: fmt.Sprintf("address list %v does not exist", initr.AddressList)})
+		}
+	}
+	return ve
 }
 
-func validateTask(task models.Task, store *store.Store) error {
-	_, err := For(task, store)
-	return err
+func validateFluxMonitor(initr models.Initiator, field string, store *store.Store) ValidationError {
+	ve := ValidationError{}
+	if initr.Address == (common.Address{}) {
+		ve = append(ve, FieldError{Field: field + ".address", Reason: "fluxmonitor must have the address of the contract to read its answer from"})
+	}
+	if len(initr.Feeds) == 0 {
+		ve = append(ve, FieldError{Field: field + ".feeds", Reason: "fluxmonitor must have at least one feed"})
+	}
+	for i, feed := range initr.Feeds {
+		feedField := fmt.Sprintf("%v.feeds[%d]", field, i)
+		if err := feed.Validate(); err != nil {
+			ve = append(ve, FieldError{Field: feedField, Reason: err.Error()})
+		} else if feed.Bridge != "" {
+			if _, err := store.BridgeTypeFor(feed.Bridge); err != nil {
+				ve = append(ve, FieldError{Field: feedField, Reason: fmt.Sprintf("bridge %v does not exist", feed.Bridge)})
+			}
+		}
+	}
+	if initr.PollingInterval < models.Duration(time.Second) {
+		ve = append(ve, FieldError{Field: field + ".pollingInterval", Reason: "fluxmonitor pollingInterval must be at least 1s"})
+	}
+	if initr.IdleThreshold < 0 {
+		ve = append(ve, FieldError{Field: field + ".idleThreshold", Reason: "fluxmonitor idleThreshold must not be negative"})
+	}
+	if initr.Threshold < 0 {
+		ve = append(ve, FieldError{Field: field + ".threshold", Reason: fmt.Sprintf("fluxmonitor threshold must not be negative, got %v", initr.Threshold)})
+	}
+	if initr.Precision > 77 {
+		ve = append(ve, FieldError{Field: field + ".precision", Reason: fmt.Sprintf("fluxmonitor precision %v does not fit in an int256", initr.Precision)})
+	}
+	return ve
+}
+
+func validateTask(task models.Task, field string, version int, dataSupplied bool, store *store.Store) ValidationError {
+	ve := ValidationError{}
+	for i, fallback := range task.OnError {
+		for _, fe := range validateTask(fallback, fmt.Sprintf("%v.onError[%d]", field, i), version, dataSupplied, store) {
+			fe.Reason = fmt.Sprintf("%v onError: %v", task.Type, fe.Reason)
+			ve = append(ve, fe)
+		}
+	}
+	if task.Timeout > 0 && strings.ToLower(task.Type) == "ethtx" {
+		ve = append(ve, FieldError{
+			Field:  field + ".timeout",
+			Reason: fmt.Sprintf("%v (%v) cannot have a timeout, as its transaction is sent even once the timeout passes", field, task.Type),
+		})
+	}
+
+	// Tasks performed by the enclave are checked there.
+	if store.CurrentConfig().EnclaveTaskType(task.Type) {
+		return ve
+	}
+	// The adapter's errors are left out when its params do not fit its
+	// schema, as they would only repeat them without naming the field.
+	if schemaErrors := checkParams(task, field, version, dataSupplied); len(schemaErrors) > 0 {
+		return append(ve, schemaErrors...)
+	}
+	// Templated params are only known when the task runs, so only the
+	// adapter type can be checked up front.
+	if strings.Contains(task.Params.Raw, "{{") {
+		_, err := For(models.Task{Type: task.Type}, store)
+		if _, ok := err.(unsupportedAdapterError); ok {
+			ve = append(ve, FieldError{Field: field, Reason: err.Error()})
+		}
+		return ve
+	}
+	if _, err := For(task, store); err != nil {
+		ve = append(ve, FieldError{Field: field, Reason: err.Error()})
+	}
+	return ve
 }
@@ -1,10 +1,13 @@
 package adapters_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/smartcontractkit/chainlink/adapters"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
 	"github.com/smartcontractkit/chainlink/store/models"
@@ -52,3 +55,160 @@ func TestAdapterFor(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_DebugSampleRate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		rate    float64
+		errored bool
+	}{
+		{"unset", 0, false},
+		{"every run", 100, false},
+		{"some runs", 2.5, false},
+		{"negative", -1, true},
+		{"above 100", 101, true},
+	}
+
+	for _, tt := range cases {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			job := cltest.NewJob()
+			job.DebugSampleRate = test.rate
+			err := adapters.Validate(job, nil)
+			assert.Equal(t, test.errored, err != nil)
+		})
+	}
+}
+
+func TestValidate_FieldErrors(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJob()
+	job.DebugSampleRate = 101
+	job.Tasks = []models.Task{
+		{Type: "noop"},
+		{Type: "idonotexist"},
+		{Type: "noop", OnError: []models.Task{{Type: "nope"}}},
+	}
+
+	err := adapters.Validate(job, store)
+	ve, ok := err.(adapters.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"debugSampleRate", "tasks[1]", "tasks[2].onError[0]"}, []string{ve[0].Field, ve[1].Field, ve[2].Field})
+	assert.Equal(t, "noop onError: nope is not a supported adapter type", ve[2].Reason)
+	assert.Contains(t, err.Error(), "idonotexist is not a supported adapter type")
+}
+
+func TestValidate_EthTxTimeout(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJob()
+	for _, spec := range []string{
+		`{"type":"httpget","url":"https://example.com","timeout":"10s"}`,
+		`{"type":"ethtx","address":"0x356a04bce728ba4c62a30294a55e6a8600a320b3","functionSelector":"0x609ff1bd","timeout":"10s"}`,
+		`{"type":"noop","onError":[{"type":"ethtx","address":"0x356a04bce728ba4c62a30294a55e6a8600a320b3","functionSelector":"0x609ff1bd","timeout":"10s"}]}`,
+	} {
+		var task models.Task
+		assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+		job.Tasks = append(job.Tasks, task)
+	}
+
+	err := adapters.Validate(job, store)
+	ve, ok := err.(adapters.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(ve))
+	assert.Equal(t, []string{"tasks[1].timeout", "tasks[2].onError[0].timeout"}, []string{ve[0].Field, ve[1].Field})
+}
+
+func TestValidate_ExternalInitiator(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	ei, _, err := models.NewExternalInitiator("iot", "")
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&ei))
+
+	job := cltest.NewJob()
+	job.Initiators = []models.Initiator{
+		{Type: models.InitiatorExternal, Name: "iot"},
+		{Type: models.InitiatorExternal, Name: "unregistered"},
+		{Type: models.InitiatorExternal},
+	}
+
+	err = adapters.Validate(job, store)
+	ve, ok := err.(adapters.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(ve))
+	assert.Equal(t, []string{"initiators[1].name", "initiators[2].name"}, []string{ve[0].Field, ve[1].Field})
+}
+
+func TestValidate_LogAddresses(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	list, err := models.NewAddressList("feeds", []common.Address{cltest.NewAddress()})
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&list))
+
+	job := cltest.NewJob()
+	job.Initiators = []models.Initiator{
+		{Type: models.InitiatorEthLog, Addresses: []common.Address{cltest.NewAddress()}, AddressList: "feeds"},
+		{Type: models.InitiatorRunLog, Addresses: []common.Address{{}}},
+		{Type: models.InitiatorEthLog, AddressList: "unknown"},
+	}
+
+	err = adapters.Validate(job, store)
+	ve, ok := err.(adapters.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(ve))
+	assert.Equal(t, []string{"initiators[1].addresses[0]", "initiators[2].addressList"}, []string{ve[0].Field, ve[1].Field})
+}
+
+func TestValidate_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	job := cltest.NewJob()
+	job.Webhooks = []string{"https://hooks.example.com/chainlink", "hooks.example.com", "ftp://hooks.example.com"}
+
+	err := adapters.Validate(job, nil)
+	ve, ok := err.(adapters.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"webhooks[1]", "webhooks[2]"}, []string{ve[0].Field, ve[1].Field})
+}
+
+func TestValidate_FluxMonitor(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJob()
+	job.Initiators = []models.Initiator{{
+		Type:            models.InitiatorFluxMonitor,
+		Address:         cltest.NewAddress(),
+		Feeds:           []models.FluxFeed{{URL: "https://api.example.com/eth", Path: []string{"price"}}},
+		Threshold:       0.5,
+		PollingInterval: models.Duration(time.Minute),
+	}}
+	assert.Nil(t, adapters.Validate(job, store))
+
+	job.Initiators = []models.Initiator{{
+		Type:  models.InitiatorFluxMonitor,
+		Feeds: []models.FluxFeed{{Bridge: "nope"}},
+	}}
+	err := adapters.Validate(job, store)
+	ve, ok := err.(adapters.ValidationError)
+	assert.True(t, ok)
+	fields := []string{}
+	for _, fe := range ve {
+		fields = append(fields, fe.Field)
+	}
+	assert.Equal(t, []string{"initiators[0].address", "initiators[0].feeds[0]", "initiators[0].pollingInterval"}, fields)
+}
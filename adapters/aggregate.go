@@ -0,0 +1,153 @@
+package adapters
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// AggregateMedian returns the middle value, or the mean of the two
+	// middle values when there is an even number of them.
+	AggregateMedian = "median"
+	// AggregateMean returns the average of the values.
+	AggregateMean = "mean"
+	// AggregateMode returns the most common value, preferring the lowest
+	// when there is a tie.
+	AggregateMode = "mode"
+)
+
+const aggregatePrecision = 256
+
+// Aggregate holds the Method used to combine a list of numbers, the
+// MaxDeviation in percent from the median beyond which a number is rejected
+// as an outlier, and the MinResponses that must remain after rejection.
+type Aggregate struct {
+	Method       string  `json:"method"`
+	MaxDeviation float64 `json:"maxDeviation"`
+	MinResponses int     `json:"minResponses"`
+}
+
+// Perform reads the input's "value" field as a JSON array or object of
+// numbers, such as the output of a Parallel group, and returns their
+// aggregate as the "value" field of the result. The method defaults to
+// median, and outliers are only rejected when MaxDeviation is set.
+//
+// For example, with a MaxDeviation of 5, `["100","101","150"]` would have
+// "150" rejected and return a median of "100.5".
+func (a *Aggregate) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	val, err := input.Value()
+	if err != nil {
+		return input.WithError(err)
+	}
+	nums, err := parseNumberList(val)
+	if err != nil {
+		return input.WithError(err)
+	}
+
+	if a.MaxDeviation > 0 && len(nums) > 0 {
+		nums = rejectOutliers(nums, a.MaxDeviation)
+	}
+	required := a.MinResponses
+	if required < 1 {
+		required = 1
+	}
+	if len(nums) < required {
+		return input.WithError(fmt.Errorf("Aggregate: %v values remaining, %v required", len(nums), required))
+	}
+
+	var result *big.Float
+	switch a.Method {
+	case "", AggregateMedian:
+		result = median(nums)
+	case AggregateMean:
+		result = mean(nums)
+	case AggregateMode:
+		result = mode(nums)
+	default:
+		return input.WithError(fmt.Errorf("Aggregate: unknown method %v", a.Method))
+	}
+	return input.WithValue(result.Text('f', -1))
+}
+
+func parseNumberList(val string) ([]*big.Float, error) {
+	if !gjson.Valid(val) {
+		return nil, fmt.Errorf("Aggregate: %v is not a JSON array or object", val)
+	}
+	parsed := gjson.Parse(val)
+	if !parsed.IsArray() && !parsed.IsObject() {
+		return nil, fmt.Errorf("Aggregate: %v is not a JSON array or object", val)
+	}
+
+	nums := []*big.Float{}
+	var err error
+	parsed.ForEach(func(_, v gjson.Result) bool {
+		f, ok := new(big.Float).SetPrec(aggregatePrecision).SetString(v.String())
+		if !ok {
+			err = fmt.Errorf("Aggregate: %v is not a number", v.String())
+			return false
+		}
+		nums = append(nums, f)
+		return true
+	})
+	return nums, err
+}
+
+func sortedCopy(nums []*big.Float) []*big.Float {
+	sorted := make([]*big.Float, len(nums))
+	copy(sorted, nums)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return sorted
+}
+
+func median(nums []*big.Float) *big.Float {
+	sorted := sortedCopy(nums)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return mean(sorted[mid-1 : mid+1])
+}
+
+func mean(nums []*big.Float) *big.Float {
+	sum := new(big.Float).SetPrec(aggregatePrecision)
+	for _, n := range nums {
+		sum.Add(sum, n)
+	}
+	return sum.Quo(sum, new(big.Float).SetInt64(int64(len(nums))))
+}
+
+func mode(nums []*big.Float) *big.Float {
+	sorted := sortedCopy(nums)
+	best, bestCount := sorted[0], 0
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j].Cmp(sorted[i]) == 0 {
+			j++
+		}
+		if j-i > bestCount {
+			best, bestCount = sorted[i], j-i
+		}
+		i = j
+	}
+	return best
+}
+
+func rejectOutliers(nums []*big.Float, maxDeviation float64) []*big.Float {
+	med := median(nums)
+	limit := new(big.Float).Abs(med)
+	limit.Mul(limit, big.NewFloat(maxDeviation/100))
+
+	kept := []*big.Float{}
+	for _, n := range nums {
+		diff := new(big.Float).Sub(n, med)
+		if diff.Abs(diff).Cmp(limit) <= 0 {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,56 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate_Perform(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		method       string
+		maxDeviation float64
+		minResponses int
+		want         string
+		wantErrored  bool
+	}{
+		{"median odd", `["3","1","2"]`, "", 0, 0, "2", false},
+		{"median even", `["4","1","3","2"]`, "median", 0, 0, "2.5", false},
+		{"median numbers", `[100.5,99.5]`, "median", 0, 0, "100", false},
+		{"mean", `["1","2","3","4"]`, "mean", 0, 0, "2.5", false},
+		{"mode", `["2","1","2","3"]`, "mode", 0, 0, "2", false},
+		{"mode tie prefers lowest", `["3","1"]`, "mode", 0, 0, "1", false},
+		{"object values", `{"a":"10","b":"20","c":"30"}`, "mean", 0, 0, "20", false},
+		{"rejects outliers", `["100","101","150"]`, "median", 5, 0, "100.5", false},
+		{"too few after rejection", `["100","101","150"]`, "median", 5, 3, "", true},
+		{"empty", `[]`, "median", 0, 0, "", true},
+		{"not a list", `"100"`, "median", 0, 0, "", true},
+		{"not numbers", `["abc"]`, "median", 0, 0, "", true},
+		{"unknown method", `["1"]`, "max", 0, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			input := cltest.RunResultWithValue(test.value)
+			adapter := adapters.Aggregate{
+				Method:       test.method,
+				MaxDeviation: test.maxDeviation,
+				MinResponses: test.minResponses,
+			}
+			result := adapter.Perform(input, nil)
+
+			assert.Equal(t, test.wantErrored, result.HasError())
+			if !test.wantErrored {
+				val, err := result.Value()
+				assert.Nil(t, err)
+				assert.Equal(t, test.want, val)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -23,11 +24,17 @@ type Bridge struct {
 //
 // If the Perform is resumed with a pending RunResult, the RunResult is marked
 // not pending and the RunResult is returned.
-func (ba *Bridge) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+func (ba *Bridge) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	return ba.PerformContext(context.Background(), input, store)
+}
+
+// PerformContext is Perform, with the request to the external adapter
+// cancelled once the context is done.
+func (ba *Bridge) PerformContext(ctx context.Context, input models.RunResult, store *store.Store) models.RunResult {
 	if input.Pending {
 		return markNotPending(input)
 	}
-	return ba.handleNewRun(input)
+	return ba.handleNewRun(ctx, input, store)
 }
 
 func markNotPending(input models.RunResult) models.RunResult {
@@ -35,25 +42,32 @@ func markNotPending(input models.RunResult) models.RunResult {
 	return input
 }
 
-func (ba *Bridge) handleNewRun(input models.RunResult) models.RunResult {
+func (ba *Bridge) handleNewRun(ctx context.Context, input models.RunResult, store *store.Store) models.RunResult {
 	in, err := json.Marshal(&bridgePayload{input})
 	if err != nil {
 		return baRunResultError(input, "marshaling request body", err)
 	}
 
-	resp, err := http.Post(ba.URL.String(), "application/json", bytes.NewBuffer(in))
+	req, err := http.NewRequest("POST", ba.URL.String(), bytes.NewBuffer(in))
+	if err != nil {
+		return baRunResultError(input, "POST request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	traced := models.TracedRequest{Method: "POST", URL: ba.URL.String(), Body: string(in)}
+	resp, err := httpClient(store).Do(req.WithContext(ctx))
 	if err != nil {
+		tracerFrom(ctx).request(traced, nil, err)
 		return baRunResultError(input, "POST request", err)
 	}
 	defer resp.Body.Close()
 
+	b, err := ioutil.ReadAll(resp.Body)
+	traced.Status = resp.StatusCode
+	tracerFrom(ctx).request(traced, b, err)
 	if resp.StatusCode >= 400 {
-		b, _ := ioutil.ReadAll(resp.Body)
 		err = fmt.Errorf("%v %v", resp.StatusCode, string(b))
 		return baRunResultError(input, "POST reponse", err)
 	}
-
-	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return baRunResultError(input, "reading response body", err)
 	}
@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"golang.org/x/net/http2"
+)
+
+// httpClientOptions are the settings adapters' HTTP clients differ by.
+type httpClientOptions struct {
+	minVersion   uint16
+	proxy        string
+	blockPrivate bool
+}
+
+// httpClients holds a client for each set of options, so that connections
+// to providers are kept open and reused across runs.
+var (
+	httpClients      = map[httpClientOptions]*http.Client{}
+	httpClientsMutex sync.Mutex
+)
+
+// httpClient returns the client that adapters make their requests to
+// services set up by the operator with, such as bridges. It negotiates
+// HTTP/2 with servers that offer it, asks for gzipped responses and decodes
+// them, and refuses to connect with a version of TLS older than
+// ADAPTER_TLS_MIN_VERSION.
+func httpClient(store *store.Store) *http.Client {
+	return cachedHTTPClient(httpClientOptions{minVersion: minTLSVersion(store)})
+}
+
+// externalHTTPClient returns the client that HttpGet, HttpPost, and
+// Paginate make requests to the URLs of job specs with. On top of what
+// httpClient does, it sends them through ADAPTER_PROXY_URL when it is set,
+// and refuses to connect to private addresses when
+// ADAPTER_BLOCK_PRIVATE_IPS is set.
+func externalHTTPClient(store *store.Store) *http.Client {
+	options := httpClientOptions{minVersion: minTLSVersion(store)}
+	if store != nil {
+		options.proxy = store.CurrentConfig().AdapterProxyURL
+		options.blockPrivate = store.CurrentConfig().AdapterBlockPrivateIPs
+	}
+	return cachedHTTPClient(options)
+}
+
+func minTLSVersion(store *store.Store) uint16 {
+	if store == nil {
+		return tls.VersionTLS12
+	}
+	return uint16(store.CurrentConfig().AdapterTLSMinVersion)
+}
+
+func cachedHTTPClient(options httpClientOptions) *http.Client {
+	httpClientsMutex.Lock()
+	defer httpClientsMutex.Unlock()
+	if client, ok := httpClients[options]; ok {
+		return client
+	}
+	client := &http.Client{Transport: newTransport(options)}
+	httpClients[options] = client
+	return client
+}
+
+// newTransport returns a transport like http.DefaultTransport, but with
+// the given options. Setting a TLS config turns off the standard library's
+// automatic HTTP/2, so it is configured explicitly.
+func newTransport(options httpClientOptions) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxyFor(options.proxy),
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       &tls.Config{MinVersion: options.minVersion},
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		DisableCompression:    false,
+	}
+	// Through a proxy, only the proxy is dialed, so the hosts requested are
+	// checked by checkEgress instead.
+	if options.blockPrivate && options.proxy == "" {
+		transport.DialContext = dialPublic(dialer)
+	}
+	if err := http2.ConfigureTransport(transport); err != nil {
+		logger.Warnw("Adapter HTTP client falling back to HTTP/1.1", "err", err)
+	}
+	return transport
+}
+
+// proxyFor returns the proxy at the URL, or those set by the HTTP_PROXY
+// and HTTPS_PROXY environment variables if it is empty.
+func proxyFor(proxy string) func(*http.Request) (*url.URL, error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		logger.Errorw("Invalid ADAPTER_PROXY_URL, adapter requests will fail", "err", err)
+		return func(*http.Request) (*url.URL, error) { return nil, err }
+	}
+	return http.ProxyURL(u)
+}
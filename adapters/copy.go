@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+// Copy holds the path, made up of an array of strings, to the part of the
+// run's data that becomes the new result, and the keys to rename in it.
+type Copy struct {
+	CopyPath []string          `json:"copyPath"`
+	Rename   map[string]string `json:"rename"`
+}
+
+// Perform replaces the run's data with the data found at the CopyPath.
+// Objects become the root of the result, while any other value is stored
+// in the "value" field. Keys at the new root are then renamed.
+//
+// For example, if the run's data looks like this:
+//   {"details": {"last": "11779.99", "high": "11850.00"}}
+//
+// Then a copyPath of ["details"] and a rename of {"last": "value"} would
+// return {"value": "11779.99", "high": "11850.00"}.
+func (c *Copy) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	target := input.Data.Result
+	if len(c.CopyPath) > 0 {
+		target = input.Data.Get(gjsonPath(c.CopyPath))
+	}
+	if !target.Exists() {
+		return input.WithError(fmt.Errorf("Copy: no data at path %v", c.CopyPath))
+	}
+
+	fields := map[string]interface{}{}
+	if target.IsObject() {
+		for k, v := range target.Map() {
+			fields[k] = json.RawMessage(v.Raw)
+		}
+	} else {
+		fields["value"] = target.String()
+	}
+
+	for from, to := range c.Rename {
+		v, ok := fields[from]
+		if !ok {
+			return input.WithError(fmt.Errorf("Copy: cannot rename missing key %v", from))
+		}
+		delete(fields, from)
+		fields[to] = v
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return input.WithError(err)
+	}
+	input.Data = models.JSON{Result: gjson.ParseBytes(b)}
+	input.Pending = false
+	return input
+}
+
+func gjsonPath(path []string) string {
+	escaper := strings.NewReplacer(".", `\.`, "*", `\*`, "?", `\?`)
+	escaped := make([]string, len(path))
+	for i, p := range path {
+		escaped[i] = escaper.Replace(p)
+	}
+	return strings.Join(escaped, ".")
+}
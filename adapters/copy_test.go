@@ -0,0 +1,48 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopy_Perform(t *testing.T) {
+	data := `{"value":"ignored","details":{"last":"11779.99","high":"11850.00","book":{"bid":"1"}},"a.b":"dotted"}`
+
+	tests := []struct {
+		name        string
+		path        []string
+		rename      map[string]string
+		want        string
+		wantErrored bool
+	}{
+		{"object", []string{"details"}, nil,
+			`{"last":"11779.99","high":"11850.00","book":{"bid":"1"}}`, false},
+		{"object with rename", []string{"details"}, map[string]string{"last": "value"},
+			`{"value":"11779.99","high":"11850.00","book":{"bid":"1"}}`, false},
+		{"nested scalar", []string{"details", "book", "bid"}, nil, `{"value":"1"}`, false},
+		{"key with a dot", []string{"a.b"}, nil, `{"value":"dotted"}`, false},
+		{"whole data with rename", []string{}, map[string]string{"value": "old"},
+			`{"old":"ignored","details":{"last":"11779.99","high":"11850.00","book":{"bid":"1"}},"a.b":"dotted"}`, false},
+		{"missing path", []string{"nope"}, nil, "", true},
+		{"missing rename key", []string{"details"}, map[string]string{"low": "value"}, "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			input := models.RunResult{Data: cltest.JSONFromString(data)}
+			adapter := adapters.Copy{CopyPath: test.path, Rename: test.rename}
+			result := adapter.Perform(input, nil)
+
+			assert.Equal(t, test.wantErrored, result.HasError())
+			if !test.wantErrored {
+				assert.JSONEq(t, test.want, result.Data.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package adapters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// valueReaders are the core adapters that act on the "value" field
+// passed in from the previous task.
+var valueReaders = map[string]bool{
+	"jsonparse":  true,
+	"parsenum":   true,
+	"aggregate":  true,
+	"twap":       true,
+	"multiply":   true,
+	"ethbytes32": true,
+	"ethuint256": true,
+	"ethint256":  true,
+	"ethtx":      true,
+}
+
+// passThroughs are the core adapters that return their input unchanged.
+var passThroughs = map[string]bool{
+	"noop":     true,
+	"nooppend": true,
+}
+
+// nonJSONValues are the core adapters whose "value" is never a JSON document.
+var nonJSONValues = map[string]bool{
+	"parsenum":   true,
+	"aggregate":  true,
+	"twap":       true,
+	"multiply":   true,
+	"ethbytes32": true,
+	"ethuint256": true,
+	"ethint256":  true,
+	"ethtx":      true,
+	"random":     true,
+}
+
+// CheckDataPaths looks for tasks that read run data which earlier tasks or
+// the job's initiators are unlikely to produce, such as a JsonParse on the
+// output of a Multiply, or a misspelled onlyIf path, Copy copyPath, or
+// {{ .data.x }} param template. These runs would otherwise complete with
+// empty or errored results, so the returned warnings are meant to be shown
+// when the job is created.
+func CheckDataPaths(job models.Job) []string {
+	warnings := []string{}
+	// Only log initiators pass data into the first task, so until a task
+	// produces a value, or a bridge returns data of its own, there is
+	// nothing to read.
+	dataSupplied := len(job.InitiatorsFor(models.InitiatorRunLog, models.InitiatorEthLog)) > 0
+	valueProduced := dataSupplied
+	prevType := ""
+
+	for i, task := range job.Tasks {
+		if valueReaders[task.Type] && !valueProduced {
+			warnings = append(warnings, fmt.Sprintf(
+				"Task %v (%v) reads the value of the previous task, but no earlier task or initiator produces one",
+				i, task.Type))
+		}
+		if task.Type == "jsonparse" {
+			warnings = append(warnings, checkJSONParse(i, task, prevType)...)
+		}
+		if cond := task.OnlyIf; cond != nil {
+			warnings = append(warnings, checkPath(i, task, "onlyIf path "+cond.Path, cond.Path, true, dataSupplied)...)
+		}
+		if task.Type == "copy" {
+			copyPath := []string{}
+			for _, key := range task.Params.Get("copyPath").Array() {
+				copyPath = append(copyPath, key.String())
+			}
+			if path := strings.Join(copyPath, "."); path != "" {
+				warnings = append(warnings, checkPath(i, task, "copyPath "+path, path, valueProduced, dataSupplied)...)
+			}
+		}
+		for _, path := range templateDataPaths(task) {
+			warnings = append(warnings, checkPath(i, task, ".data."+path+" in a param template", path, valueProduced, dataSupplied)...)
+		}
+
+		if !passThroughs[task.Type] {
+			valueProduced = true
+			prevType = task.Type
+		}
+		if isBridge(task.Type) {
+			dataSupplied = true
+		}
+	}
+	return warnings
+}
+
+func checkJSONParse(i int, task models.Task, prevType string) []string {
+	warnings := []string{}
+	if len(task.Params.Get("path").Array()) == 0 {
+		warnings = append(warnings, fmt.Sprintf("Task %v (jsonparse) has an empty path", i))
+	}
+	if nonJSONValues[prevType] {
+		warnings = append(warnings, fmt.Sprintf(
+			"Task %v (jsonparse) parses the value of a %v task, which is not JSON", i, prevType))
+	}
+	return warnings
+}
+
+// checkPath warns about a path of run data the task reads, described by
+// what, whose root looks like a misspelling of value, or that no earlier
+// task or initiator produces.
+func checkPath(i int, task models.Task, what, path string, valueProduced, dataSupplied bool) []string {
+	root := strings.SplitN(path, ".", 2)[0]
+	switch {
+	case root == "value" && (valueProduced || dataSupplied):
+		return []string{}
+	case root != "value" && levenshtein(root, "value") <= 2:
+		return []string{fmt.Sprintf(
+			"Task %v (%v) %v looks like a typo, did you mean value?", i, task.Type, what)}
+	case !dataSupplied:
+		return []string{fmt.Sprintf(
+			"Task %v (%v) %v is not produced by any earlier task or initiator", i, task.Type, what)}
+	}
+	return []string{}
+}
+
+// templateData matches the paths of run data read by param templates, such
+// as price.last in {{ .data.price.last }}.
+var templateData = regexp.MustCompile(`\.data\.([A-Za-z0-9_]+(?:\.[A-Za-z0-9_]+)*)`)
+
+// templateDataPaths returns the paths of run data the task's params read
+// in their templates.
+func templateDataPaths(task models.Task) []string {
+	paths := []string{}
+	for _, action := range templateActions.FindAllString(task.Params.Raw, -1) {
+		for _, match := range templateData.FindAllStringSubmatch(action, -1) {
+			paths = append(paths, match[1])
+		}
+	}
+	return paths
+}
+
+// templateActions matches the actions of param templates, between {{ and }}.
+var templateActions = regexp.MustCompile(`{{.*?}}`)
+
+func isBridge(taskType string) bool {
+	switch taskType {
+	case "httpget", "httppost", "paginate", "parallel", "copy":
+		return false
+	}
+	return !valueReaders[taskType] && !passThroughs[taskType]
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,73 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDataPaths(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		spec         string
+		wantWarnings int
+	}{
+		{"hello world",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com"},{"type":"jsonparse","path":["last"]},{"type":"ethbytes32"}]}`,
+			0},
+		{"reads value without a producer",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"noop"},{"type":"multiply","times":100}]}`,
+			1},
+		{"runlog supplies value",
+			`{"initiators":[{"type":"runlog"}],"tasks":[{"type":"jsonparse","path":["last"]}]}`,
+			0},
+		{"parses non JSON",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com"},{"type":"multiply","times":100},{"type":"jsonparse","path":["last"]}]}`,
+			1},
+		{"empty jsonparse path",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com"},{"type":"jsonparse","path":[]}]}`,
+			1},
+		{"onlyIf typo",
+			`{"initiators":[{"type":"runlog"}],"tasks":[{"type":"noop","onlyIf":{"path":"vaule","operator":"gt","value":"1"}}]}`,
+			1},
+		{"onlyIf path with no producer",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com"},{"type":"noop","onlyIf":{"path":"price","operator":"gt","value":"1"}}]}`,
+			1},
+		{"onlyIf path from a bridge",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"randomNumber"},{"type":"noop","onlyIf":{"path":"price","operator":"gt","value":"1"}}]}`,
+			0},
+		{"template typo",
+			`{"initiators":[{"type":"runlog"}],"tasks":[{"type":"httppost","url":"https://example.com/{{ .data.vlaue }}"}]}`,
+			1},
+		{"template path with no producer",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httppost","url":"https://example.com/{{ .jobRun.id }}?price={{ .data.price.last }}"}]}`,
+			1},
+		{"template path from a bridge",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"randomNumber"},{"type":"httppost","url":"https://example.com/?price={{ .data.price.last }}"}]}`,
+			0},
+		{"copyPath with no producer",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com"},{"type":"copy","copyPath":["details","last"]}]}`,
+			1},
+		{"copyPath of the value",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com"},{"type":"copy","copyPath":["value"]}]}`,
+			0},
+		{"copyPath typo",
+			`{"initiators":[{"type":"runlog"}],"tasks":[{"type":"copy","copyPath":["valeu","last"]}]}`,
+			1},
+	}
+
+	for _, tt := range cases {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := models.NewJob()
+			assert.Nil(t, json.Unmarshal([]byte(test.spec), &j))
+			warnings := adapters.CheckDataPaths(j)
+			assert.Equal(t, test.wantWarnings, len(warnings), warnings)
+		})
+	}
+}
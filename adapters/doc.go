@@ -4,23 +4,99 @@
 //
 // The HttpGet adapter is used to grab the JSON data from the given URL.
 //  { "type": "HttpGet", "url": "https://some-api-example.net/api" }
+// The response is reused by tasks requesting the same URL for "cacheTTL",
+// if given, or else ADAPTER_CACHE_TTL.
+//  { "type": "HttpGet", "url": "https://some-api-example.net/api", "cacheTTL": "5s" }
 //
 // HttpPost
 //
 // Sends a POST request to the specified URL and will return the response.
 //  { "type": "HttpPost", "url": "https://weiwatchers.com/api" }
+// Like HttpGet's, its responses can be cached with "cacheTTL", and are
+// reused for requests with the same body.
+//
+// HttpGet and HttpPost requests can be authenticated with a credential
+// saved on the node, named by "credential", so that API keys are kept out
+// of job specs. The "header" type sends it in "header", Authorization by
+// default, after "prefix"; "basic" sends it as the password for
+// "username"; and "oauth2" sends it as the client secret for "clientId" to
+// "tokenUrl", and sends the token it is given, refreshing it as it expires.
+//  { "type": "HttpGet", "url": "https://some-api-example.net/api", "auth": {"type": "header", "header": "X-API-Key", "credential": "example-key"} }
+//
+// HttpGet, HttpPost, and Paginate tasks can be limited to some hosts with
+// "allowedDomains", or kept from others with "deniedDomains", on top of
+// those of the job and ADAPTER_ALLOWED_DOMAINS and ADAPTER_DENIED_DOMAINS.
+//  { "type": "HttpGet", "url": "https://some-api-example.net/api", "allowedDomains": ["some-api-example.net"] }
+//
+// Paginate
+//
+// The Paginate adapter will fetch pages from the given URL, following the
+// cursor at "cursorPath" and sending it in the "cursorParam" query parameter,
+// and return the items at "itemsPath" of every page as a JSON array. The last
+// cursor is saved so that the job's next run carries on where it left off.
+//   {
+//     "type": "Paginate",
+//     "url": "https://some-api-example.net/api/events",
+//     "cursorParam": "after",
+//     "cursorPath": "next",
+//     "itemsPath": "events",
+//     "maxPages": 5
+//   }
 //
 // JsonParse
 //
 // The JsonParse adapter will obtain the value(s) for the given field(s).
 //  { "type": "JsonParse", "path": ["someField"] }
 //
+// Setting "numberFormat" normalizes the field as the ParseNum adapter does.
+//
+// Copy
+//
+// The Copy adapter will make the data at the given path the new result,
+// renaming any of its keys as given.
+//  { "type": "Copy", "copyPath": ["details"], "rename": {"last": "value"} }
+//
+// ParseNum
+//
+// The ParseNum adapter will normalize numbers such as "1.234,56", "1,234.56",
+// or "1.23456e3" to a plain decimal. The format can be "auto" (default),
+// "point" for a decimal point, or "comma" for a decimal comma. Numbers
+// such as "1,234", whose only separator could be either, are refused unless
+// the format is given.
+//  { "type": "ParseNum", "format": "comma" }
+//
 // EthBytes32
 //
 // The EthBytes32 adapter will take the given values and format them for
 // the Ethereum blockhain.
 //  { "type": "EthBytes32" }
 //
+// EthUint256
+//
+// The EthUint256 adapter will format the given number as an unsigned
+// 256 bit integer, erroring if it does not fit.
+//  { "type": "EthUint256" }
+//
+// EthInt256
+//
+// The EthInt256 adapter will format the given number as a two's
+// complement signed 256 bit integer, erroring if it does not fit.
+//  { "type": "EthInt256" }
+//
+// EthCall
+//
+// The EthCall adapter will call the "function" of the contract at "address"
+// with the "args" as it stands in the latest block, without sending a
+// transaction, and return what it returned as the value. It "returns" a
+// uint256 by default, or an int256, bool, address, or bytes32. The args
+// and address can be templated from the run's data.
+//   {
+//     "type": "EthCall",
+//     "address": "0x0000000000000000000000000000000000000000",
+//     "function": "balanceOf(address)",
+//     "args": ["{{ .data.owner }}"]
+//   }
+//
 // EthTx
 //
 // The EthTx adapter will write the data to the given address and functionSelector.
@@ -29,5 +105,103 @@
 //     "address": "0x0000000000000000000000000000000000000000",
 //     "functionSelector": "0xffffffff"
 //   }
+// To save gas on stable answers, a "heartbeat" skips sending an answer that
+// is within "tolerance" percent of the last one sent, until the heartbeat
+// has passed since then.
+//   {
+//     "type": "EthTx",
+//     "address": "0x0000000000000000000000000000000000000000",
+//     "functionSelector": "0xffffffff",
+//     "heartbeat": "1h",
+//     "tolerance": "0.5"
+//   }
+// Calls to a contract with a method that takes many calls at once, such as
+// fulfillMany(bytes[]), can be sent together once per block by giving that
+// method's selector as the "batchFunctionSelector". Each block's
+// transaction holds up to ETH_MAX_BATCH_CALLS calls, so that its gas limit
+// stays within the block's, and the rest wait for the next block.
+//   {
+//     "type": "EthTx",
+//     "address": "0x0000000000000000000000000000000000000000",
+//     "functionSelector": "0xffffffff",
+//     "batchFunctionSelector": "0xeeeeeeee"
+//   }
+// With "signFulfillment", or SIGN_FULFILLMENTS, the node signs the hash of
+// the request ID, the "dataPrefix", and the result it sends, and adds the
+// signature to the result's "signature" and its address to "signer".
+//   {
+//     "type": "EthTx",
+//     "address": "0x0000000000000000000000000000000000000000",
+//     "functionSelector": "0xffffffff",
+//     "signFulfillment": true
+//   }
+//
+// Parallel
+//
+// The Parallel adapter performs a group of tasks at the same time and
+// merges their values into a JSON array, or an object keyed by each
+// task's name when "merge" is "object". EthTx, Forward, Parallel, and
+// bridge tasks cannot be grouped.
+//   {
+//     "type": "Parallel",
+//     "tasks": [
+//       { "type": "HttpGet", "url": "https://some-api-example.net/api" },
+//       { "type": "HttpGet", "url": "https://another-api-example.net/api" }
+//     ]
+//   }
+//
+// Aggregate
+//
+// The Aggregate adapter will combine a JSON array or object of numbers,
+// such as the output of a Parallel group, using the "median" (default),
+// "mean", or "mode". Values further than "maxDeviation" percent from the
+// median are rejected, and "minResponses" must remain.
+//  { "type": "Aggregate", "method": "median", "maxDeviation": 5, "minResponses": 2 }
+//
+// TWAP
+//
+// The TWAP adapter will record the given number, and return the average of
+// the numbers it recorded for the job within the "window", weighted by time
+// or by the volume found at "volumePath" when "weight" is "volume".
+//  { "type": "TWAP", "window": "1h" }
+//
+// Random
+//
+// The Random adapter will make a verifiable random value from the "seed",
+// or the "seed" field of the run's data, with the node's VRF key, kept in
+// vrf.key in the root directory encrypted with the keystore password and
+// unlocked when the node starts. The value is a hex uint256, and the proof
+// that the node's key made it from the seed is in the "proof" field, as
+// 256 bytes for a contract to verify: the public key, gamma, c, s, and the
+// seed as uint256s, then the uWitness address.
+//  { "type": "Random" }
+//
+// Metric
+//
+// The Metric adapter will record the number at "path", "value" by default,
+// in the node's metrics as chainlink_custom_<name>, setting a "gauge" or
+// increasing a "counter", and pass its input on unchanged. Each metric can
+// have up to 100 combinations of label values.
+//   {
+//     "type": "Metric",
+//     "name": "reported_price",
+//     "kind": "gauge",
+//     "labels": {"pair": "ETH/USD"}
+//   }
+//
+// Forward
+//
+// The Forward adapter has the secondary node at FORWARD_URL execute its
+// tasks with its data, authenticating with the API token FORWARD_TOKEN,
+// and carries on with the data the secondary node's run ended with. EthTx
+// tasks stay with the node the request was made to, which sends them with
+// its own key.
+//   {
+//     "type": "Forward",
+//     "tasks": [
+//       { "type": "HttpGet", "url": "https://some-api-example.net/api" },
+//       { "type": "JsonParse", "path": ["last"] }
+//     ]
+//   }
 //
 package adapters
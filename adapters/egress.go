@@ -0,0 +1,160 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// privateNetworks are the address ranges ADAPTER_BLOCK_PRIVATE_IPS refuses
+// to connect to: unspecified, loopback, private, link-local, and shared
+// addresses, through which job specs could reach the node's own services
+// or a cloud provider's metadata endpoint.
+var privateNetworks = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// privateIP returns true if the address is in one of privateNetworks.
+func privateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEgress returns an error if a task with the given DomainLists may
+// not send a request to the URL: if the host is not permitted by the
+// task's lists, those of the job the input's run belongs to, or
+// ADAPTER_ALLOWED_DOMAINS and ADAPTER_DENIED_DOMAINS. Requests are refused
+// for runs that cannot be found, since their job's lists cannot be
+// checked. Requests sent through ADAPTER_PROXY_URL are also refused here if
+// the host resolves to a private address and ADAPTER_BLOCK_PRIVATE_IPS is
+// set, since the node only dials the proxy.
+func checkEgress(ctx context.Context, u *url.URL, task models.DomainLists, input models.RunResult, store *store.Store) error {
+	if store == nil {
+		return checkLists(ctx, u.Hostname(), []models.DomainLists{task}, store)
+	}
+	jobID, err := jobIDFor(input, store)
+	if err != nil {
+		return fmt.Errorf("Requests to %v are not allowed, %v to check its job's domain lists", u.Hostname(), err)
+	}
+	job, err := store.FindJob(jobID)
+	if err != nil {
+		return fmt.Errorf("Requests to %v are not allowed, job %v was not found to check its domain lists", u.Hostname(), jobID)
+	}
+	return checkLists(ctx, u.Hostname(), []models.DomainLists{task, job.DomainLists, store.CurrentConfig().AdapterDomainLists()}, store)
+}
+
+// CheckJobEgress returns an error if a request may not be sent to the URL
+// for the job, as checkEgress does for the requests of its tasks. It is for
+// the URLs job authors give outside of their tasks, such as the job's
+// webhooks.
+func CheckJobEgress(ctx context.Context, u *url.URL, job models.Job, store *store.Store) error {
+	return checkLists(ctx, u.Hostname(), []models.DomainLists{job.DomainLists, store.CurrentConfig().AdapterDomainLists()}, store)
+}
+
+func checkLists(ctx context.Context, host string, lists []models.DomainLists, store *store.Store) error {
+	for _, dl := range lists {
+		if !dl.Permits(host) {
+			return fmt.Errorf("Requests to %v are not allowed", host)
+		}
+	}
+	if store != nil && store.CurrentConfig().AdapterBlockPrivateIPs && store.CurrentConfig().AdapterProxyURL != "" {
+		_, err := resolvePublic(ctx, host)
+		return err
+	}
+	return nil
+}
+
+// egressClient returns the client for a task's requests to the URLs of job
+// specs, which checks every redirect with checkEgress too.
+func egressClient(task models.DomainLists, input models.RunResult, store *store.Store) *http.Client {
+	return checkedClient(store, func(req *http.Request) error {
+		return checkEgress(req.Context(), req.URL, task, input, store)
+	})
+}
+
+// JobEgressClient returns the client for requests to the URLs a job gives
+// outside of its tasks, which checks every redirect with CheckJobEgress
+// too.
+func JobEgressClient(job models.Job, store *store.Store) *http.Client {
+	return checkedClient(store, func(req *http.Request) error {
+		return CheckJobEgress(req.Context(), req.URL, job, store)
+	})
+}
+
+func checkedClient(store *store.Store, check func(*http.Request) error) *http.Client {
+	client := *externalHTTPClient(store)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return check(req)
+	}
+	return &client
+}
+
+// dialPublic returns a DialContext like the dialer's that refuses hosts
+// resolving to a private address, and connects to the address it checked
+// so that the host cannot resolve to another in between.
+func dialPublic(dialer *net.Dialer) func(context.Context, string, string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		ip, err := resolvePublic(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+	}
+}
+
+// resolvePublic returns an address of the host, or an error if any of its
+// addresses is private.
+func resolvePublic(ctx context.Context, host string) (net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("%v has no addresses", host)
+	}
+	for _, addr := range addrs {
+		if privateIP(addr.IP) {
+			return nil, fmt.Errorf("Requests to %v are not allowed, it resolves to the private address %v", host, addr.IP)
+		}
+	}
+	return addrs[0].IP, nil
+}
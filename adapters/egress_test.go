@@ -0,0 +1,114 @@
+package adapters_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func okServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+}
+
+func TestHttpGet_Perform_DomainLists(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	server := okServer()
+	defer server.Close()
+	url := cltest.MustParseWebURL(server.URL)
+
+	tests := []struct {
+		name      string
+		allowed   string
+		denied    string
+		task      models.DomainLists
+		wantError bool
+	}{
+		{"no lists", "", "", models.DomainLists{}, false},
+		{"allowed by node", "127.0.0.1", "", models.DomainLists{}, false},
+		{"not allowed by node", "example.com", "", models.DomainLists{}, true},
+		{"denied by node", "", "127.0.0.1", models.DomainLists{}, true},
+		{"not allowed by task", "", "", models.NewDomainLists("example.com", ""), true},
+		{"denied by task", "", "", models.NewDomainLists("", "127.0.0.1"), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.Config.AdapterAllowedDomains = test.allowed
+			store.Config.AdapterDeniedDomains = test.denied
+			hga := adapters.HttpGet{URL: url, DomainLists: test.task}
+			result := hga.Perform(input, store)
+			assert.Equal(t, test.wantError, result.HasError())
+		})
+	}
+}
+
+func TestHttpPost_Perform_JobDomainLists(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	server := okServer()
+	defer server.Close()
+
+	job := cltest.NewJobWithWebInitiator()
+	job.DeniedDomains = []string{"127.0.0.1"}
+	assert.Nil(t, store.SaveJob(&job))
+	run := job.NewRun()
+	assert.Nil(t, store.Save(&run))
+
+	hpa := adapters.HttpPost{URL: cltest.MustParseWebURL(server.URL)}
+	result := hpa.Perform(models.RunResult{JobRunID: run.ID}, store)
+	assert.EqualError(t, result.GetError(), "Requests to 127.0.0.1 are not allowed")
+
+	result = hpa.Perform(input, store)
+	assert.Nil(t, result.GetError(), "other jobs are not affected")
+
+	result = hpa.Perform(models.RunResult{JobRunID: "unsaved"}, store)
+	assert.Contains(t, result.Error(), "run unsaved was not found", "the job's lists cannot be skipped")
+}
+
+func TestHttpGet_Perform_Redirect(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	store.Config.AdapterDeniedDomains = "localhost"
+	target := okServer()
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(
+		"http://localhost:"+cltest.MustParseWebURL(target.URL).Port(), http.StatusFound))
+	defer redirect.Close()
+
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(redirect.URL)}
+	result := hga.Perform(input, store)
+	assert.Contains(t, result.Error(), "Requests to localhost are not allowed")
+}
+
+func TestHttpGet_Perform_BlockPrivateIPs(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	server := okServer()
+	defer server.Close()
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(server.URL)}
+
+	val, err := hga.Perform(input, store).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", val)
+
+	store.Config.AdapterBlockPrivateIPs = true
+	result := hga.Perform(input, store)
+	assert.Contains(t, result.Error(), "private address 127.0.0.1")
+}
@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// ethCallSignature matches a function signature such as
+// "balanceOf(address)", capturing its name and argument types.
+var ethCallSignature = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\((.*)\)$`)
+
+// ethCallIntType matches the integer types EthCall can encode and decode.
+var ethCallIntType = regexp.MustCompile(`^u?int([0-9]*)$`)
+
+// EthCall holds the Address of the contract to call, the signature of the
+// Function to call on it, such as "balanceOf(address)", the Args to call it
+// with, and the type the function Returns, uint256 by default. Args and
+// the address can be templated from the run's data, such as
+// "{{ .data.owner }}".
+type EthCall struct {
+	Address  common.Address `json:"address"`
+	Function string         `json:"function"`
+	Args     []models.JSON  `json:"args"`
+	Returns  string         `json:"returns"`
+
+	signature string
+	types     []string
+}
+
+func (ec *EthCall) validate() error {
+	if ec.Address == (common.Address{}) {
+		return fmt.Errorf("EthCall: address is required")
+	}
+	match := ethCallSignature.FindStringSubmatch(strings.Replace(ec.Function, " ", "", -1))
+	if match == nil {
+		return fmt.Errorf("EthCall: function must be a signature such as balanceOf(address), got %q", ec.Function)
+	}
+	ec.types = []string{}
+	if match[2] != "" {
+		ec.types = strings.Split(match[2], ",")
+	}
+	for i, typ := range ec.types {
+		canonical, err := canonicalABIType(typ)
+		if err != nil {
+			return fmt.Errorf("EthCall: %v", err)
+		}
+		ec.types[i] = canonical
+	}
+	ec.signature = match[1] + "(" + strings.Join(ec.types, ",") + ")"
+	if len(ec.Args) != len(ec.types) {
+		return fmt.Errorf("EthCall: %v takes %d args, got %d", ec.signature, len(ec.types), len(ec.Args))
+	}
+	if ec.Returns == "" {
+		ec.Returns = "uint256"
+	}
+	switch ec.Returns {
+	case "uint256", "int256", "bool", "address", "bytes32":
+	default:
+		return fmt.Errorf("EthCall: returns must be uint256, int256, bool, address, or bytes32, got %q", ec.Returns)
+	}
+	return nil
+}
+
+// canonicalABIType returns the type as it appears in function selectors,
+// such as uint256 for uint, or an error if EthCall cannot encode it.
+func canonicalABIType(typ string) (string, error) {
+	switch typ {
+	case "address", "bool", "bytes32":
+		return typ, nil
+	case "uint", "int":
+		return typ + "256", nil
+	}
+	match := ethCallIntType.FindStringSubmatch(typ)
+	if match == nil {
+		return "", fmt.Errorf("argument type %v is not supported", typ)
+	}
+	bits, _ := strconv.Atoi(match[1])
+	if bits == 0 || bits > 256 || bits%8 != 0 {
+		return "", fmt.Errorf("argument type %v is not a valid integer type", typ)
+	}
+	return typ, nil
+}
+
+// Perform calls the function on the contract against the latest block,
+// without sending a transaction, and returns what it returned as the value:
+// a decimal string for integers, "true" or "false" for a bool, and hex
+// for an address or bytes32.
+func (ec *EthCall) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	args := make([]interface{}, len(ec.Args))
+	for i, arg := range ec.Args {
+		encoded, err := ethCallArg(ec.types[i], arg.String())
+		if err != nil {
+			return input.WithError(fmt.Errorf("EthCall: argument %d: %v", i, err))
+		}
+		args[i] = encoded
+	}
+
+	result, err := store.TxManager.CallContract(ec.Address, ec.signature, args...)
+	if err != nil {
+		return input.WithError(err)
+	}
+	value, err := ec.decode(result)
+	if err != nil {
+		return input.WithError(fmt.Errorf("EthCall: decoding %v: %v", ec.Returns, err))
+	}
+	return input.WithValue(value)
+}
+
+func (ec *EthCall) decode(result models.ABIResult) (string, error) {
+	switch ec.Returns {
+	case "int256":
+		i, err := result.Int256(0)
+		if err != nil {
+			return "", err
+		}
+		return i.String(), nil
+	case "bool":
+		b, err := result.Bool(0)
+		return strconv.FormatBool(b), err
+	case "address":
+		a, err := result.Address(0)
+		return a.Hex(), err
+	case "bytes32":
+		h, err := result.Bytes32(0)
+		return h.Hex(), err
+	}
+	i, err := result.Uint256(0)
+	if err != nil {
+		return "", err
+	}
+	return i.String(), nil
+}
+
+// ethCallArg converts the argument, as a string, to the Go value
+// models.EncodeCall encodes as the type.
+func ethCallArg(typ, str string) (interface{}, error) {
+	switch typ {
+	case "address":
+		if !common.IsHexAddress(str) {
+			return nil, fmt.Errorf("%q is not an address", str)
+		}
+		return common.HexToAddress(str), nil
+	case "bool":
+		return strconv.ParseBool(str)
+	case "bytes32":
+		if len(strings.TrimPrefix(str, "0x")) != 64 {
+			return nil, fmt.Errorf("%q is not 32 bytes of hex", str)
+		}
+		return common.HexToHash(str), nil
+	}
+	i, ok := new(big.Int).SetString(str, 0)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an integer", str)
+	}
+	bits, _ := strconv.Atoi(ethCallIntType.FindStringSubmatch(typ)[1])
+	if strings.HasPrefix(typ, "uint") {
+		if i.Sign() < 0 || i.BitLen() > bits {
+			return nil, fmt.Errorf("%v is out of range for %v", i, typ)
+		}
+		return i, nil
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if i.Cmp(new(big.Int).Neg(limit)) < 0 || i.Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("%v is out of range for %v", i, typ)
+	}
+	return i, nil
+}
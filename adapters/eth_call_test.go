@@ -0,0 +1,147 @@
+package adapters_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthCall_Perform(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.MockEthOnStore(store)
+
+	owner := cltest.NewAddress()
+	expectedData, err := models.EncodeCall("balanceOf(address)", owner)
+	assert.Nil(t, err)
+	eth.Register("eth_call", hexutil.Bytes(common.LeftPadBytes(big.NewInt(1234).Bytes(), 32)),
+		func(_ interface{}, data ...interface{}) error {
+			args := data[0].([]interface{})[0].(map[string]string)
+			assert.Equal(t, hexutil.Encode(expectedData), args["data"])
+			return nil
+		})
+
+	task := models.Task{Type: "ethcall", Params: cltest.JSONFromString(`{
+		"address": "0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f",
+		"function": "balanceOf(address)",
+		"args": ["%v"]
+	}`, owner.Hex())}
+	adapter, err := adapters.For(task, store)
+	assert.Nil(t, err)
+	result := adapter.Perform(models.RunResult{}, store)
+	assert.Nil(t, result.GetError())
+	val, err := result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "1234", val)
+
+	eth.EnsureAllCalled(t)
+}
+
+func TestEthCall_Perform_Returns(t *testing.T) {
+	t.Parallel()
+	address := common.HexToAddress("0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f")
+
+	tests := []struct {
+		name    string
+		returns string
+		word    []byte
+		want    string
+	}{
+		{"int256", "int256", common.LeftPadBytes(nil, 32), "0"},
+		{"negative int256", "int256", common.Hex2Bytes("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff85"), "-123"},
+		{"bool", "bool", common.LeftPadBytes([]byte{1}, 32), "true"},
+		{"address", "address", common.LeftPadBytes(address.Bytes(), 32), address.Hex()},
+		{"bytes32", "bytes32", common.LeftPadBytes([]byte{1}, 32), "0x0000000000000000000000000000000000000000000000000000000000000001"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			eth := cltest.MockEthOnStore(store)
+			eth.Register("eth_call", hexutil.Bytes(test.word))
+
+			task := models.Task{Type: "ethcall", Params: cltest.JSONFromString(`{
+				"address": "0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f",
+				"function": "latestAnswer()",
+				"returns": "%v"
+			}`, test.returns)}
+			adapter, err := adapters.For(task, store)
+			assert.Nil(t, err)
+			result := adapter.Perform(models.RunResult{}, store)
+			assert.Nil(t, result.GetError())
+			val, err := result.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, val)
+		})
+	}
+}
+
+func TestEthCall_Perform_Errors(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.MockEthOnStore(store)
+
+	task := models.Task{Type: "ethcall", Params: cltest.JSONFromString(`{
+		"address": "0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f",
+		"function": "getRoundData(uint80)",
+		"args": ["300"]
+	}`)}
+	adapter, err := adapters.For(task, store)
+	assert.Nil(t, err)
+	result := adapter.Perform(models.RunResult{}, store)
+	assert.NotNil(t, result.GetError())
+
+	task.Params = cltest.JSONFromString(`{
+		"address": "0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f",
+		"function": "latestAnswer()"
+	}`)
+	eth.RegisterError("eth_call", "execution reverted")
+	adapter, err = adapters.For(task, store)
+	assert.Nil(t, err)
+	result = adapter.Perform(models.RunResult{}, store)
+	assert.Contains(t, result.Error(), "execution reverted")
+}
+
+func TestEthCall_Validate(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		params string
+		valid  bool
+	}{
+		{"valid", `{"address":"0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f","function":"balanceOf(address)","args":["0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f"]}`, true},
+		{"uint alias", `{"address":"0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f","function":"getAnswer(uint)","args":[5]}`, true},
+		{"no address", `{"function":"latestAnswer()"}`, false},
+		{"not a signature", `{"address":"0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f","function":"latestAnswer"}`, false},
+		{"unsupported arg", `{"address":"0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f","function":"f(string)","args":["a"]}`, false},
+		{"wrong arg count", `{"address":"0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f","function":"balanceOf(address)"}`, false},
+		{"unsupported return", `{"address":"0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f","function":"latestAnswer()","returns":"string"}`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := adapters.For(models.Task{Type: "ethcall", Params: cltest.JSONFromString(test.params)}, store)
+			assert.Equal(t, test.valid, err == nil, "%v", err)
+		})
+	}
+
+	job := cltest.NewJob()
+	job.Tasks = []models.Task{{Type: "ethcall", Params: cltest.JSONFromString(`{
+		"address": "{{ .data.contract }}",
+		"function": "balanceOf(address)",
+		"args": ["{{ .data.owner }}"]
+	}`)}}
+	assert.Nil(t, adapters.Validate(job, store))
+}
@@ -42,41 +42,74 @@ type EthUint256 struct{}
 
 // Perform returns the hex value of a given string so that it
 // is in the proper format to be written to the blockchain.
+// Values are truncated to integers, and must fall within the range of
+// an unsigned 256 bit integer.
 //
-// For example, after converting the string "16800.00" to hex for
+// For example, after converting the string "123.99" to hex for
 // the blockchain, it would be:
-// "0x31363830302e3030000000000000000000000000000000000000000000000000"
+// "0x000000000000000000000000000000000000000000000000000000000000007b"
 func (*EthUint256) Perform(input models.RunResult, _ *store.Store) models.RunResult {
-	val, err := input.Get("value")
+	i, err := parseBigInt(input)
 	if err != nil {
 		return input.WithError(err)
 	}
-
-	i, ok := (&big.Float{}).SetString(val.String())
-	if !ok {
-		return input.WithError(fmt.Errorf("cannot parse into big.Float: %v", val.String()))
+	if i.Sign() == -1 || i.Cmp(maxUint256) > 0 {
+		return input.WithError(fmt.Errorf("%v is out of range for uint256", i))
 	}
 
-	b, err := utils.HexToBytes(bigToUintHex(i))
+	return input.WithValue(common.ToHex(common.LeftPadBytes(i.Bytes(), evmWordByteLen)))
+}
+
+// EthInt256 holds no fields.
+type EthInt256 struct{}
+
+// Perform returns the two's complement hex value of a given string so that
+// it is in the proper format to be written to the blockchain. Values are
+// truncated to integers, and must fall within the range of a signed 256 bit
+// integer.
+//
+// For example, after converting the string "-123.99" to hex for
+// the blockchain, it would be:
+// "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff85"
+func (*EthInt256) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	i, err := parseBigInt(input)
 	if err != nil {
 		return input.WithError(err)
 	}
-	padded := common.LeftPadBytes(b, evmWordByteLen)
+	if i.Cmp(minInt256) < 0 || i.Cmp(maxInt256) > 0 {
+		return input.WithError(fmt.Errorf("%v is out of range for int256", i))
+	}
+	if i.Sign() == -1 {
+		i.Add(i, twoTo256)
+	}
 
-	return input.WithValue(common.ToHex(padded))
+	return input.WithValue(common.ToHex(common.LeftPadBytes(i.Bytes(), evmWordByteLen)))
 }
 
-func bigToUintHex(f *big.Float) string {
-	i, _ := f.Int(nil)
-	if i.Sign() == -1 {
-		i.Neg(i)
+var (
+	twoTo256   = new(big.Int).Lsh(big.NewInt(1), 256)
+	maxUint256 = new(big.Int).Sub(twoTo256, big.NewInt(1))
+	twoTo255   = new(big.Int).Lsh(big.NewInt(1), 255)
+	maxInt256  = new(big.Int).Sub(twoTo255, big.NewInt(1))
+	minInt256  = new(big.Int).Neg(twoTo255)
+)
+
+// parseBigInt reads the "value" field of the input as an integer, truncating
+// any fractional part.
+func parseBigInt(input models.RunResult) (*big.Int, error) {
+	val, err := input.Get("value")
+	if err != nil {
+		return nil, err
 	}
-	hex := fmt.Sprintf("%x", i)
-	if len(hex)%2 != 0 {
-		hex = "0" + hex
+
+	str := val.String()
+	if i, ok := new(big.Int).SetString(str, 10); ok {
+		return i, nil
 	}
-	if len(hex) > evmWordHexLen {
-		hex = hex[len(hex)-evmWordHexLen:]
+	f, ok := new(big.Float).SetPrec(1024).SetString(str)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse into big.Float: %v", str)
 	}
-	return hex
+	i, _ := f.Int(nil)
+	return i, nil
 }
@@ -59,11 +59,13 @@ func TestEthUint256_Perform(t *testing.T) {
 		{"integer", `{"value":"170141183460469231731687303715884105728"}`, "0x0000000000000000000000000000000080000000000000000000000000000000", false},
 		{"float", `{"value":123.0}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
 		{"rounded float", `{"value":123.99}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
-		{"negative integer", `{"value":-123}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
-		{"negative string", `{"value":"-123"}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
-		{"negative float", `{"value":-123.99}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
+		{"negative integer", `{"value":-123}`, "", true},
+		{"negative string", `{"value":"-123"}`, "", true},
+		{"negative float", `{"value":-123.99}`, "", true},
 		{"object", `{"value":{"a": "b"}}`, "", true},
 		{"odd length result", `{"value":"1234"}`, "0x00000000000000000000000000000000000000000000000000000000000004d2", false},
+		{"max", `{"value":"115792089237316195423570985008687907853269984665640564039457584007913129639935"}`, "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+		{"overflow", `{"value":"115792089237316195423570985008687907853269984665640564039457584007913129639936"}`, "", true},
 	}
 
 	for _, tt := range tests {
@@ -87,3 +89,46 @@ func TestEthUint256_Perform(t *testing.T) {
 		})
 	}
 }
+
+func TestEthInt256_Perform(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		errored bool
+	}{
+		{"string", `{"value":"123"}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
+		{"integer", `{"value":123}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
+		{"rounded float", `{"value":123.99}`, "0x000000000000000000000000000000000000000000000000000000000000007b", false},
+		{"negative integer", `{"value":-123}`, "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff85", false},
+		{"negative float", `{"value":-123.99}`, "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff85", false},
+		{"negative one", `{"value":"-1"}`, "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+		{"zero", `{"value":0}`, "0x0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"max", `{"value":"57896044618658097711785492504343953926634992332820282019728792003956564819967"}`, "0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+		{"min", `{"value":"-57896044618658097711785492504343953926634992332820282019728792003956564819968"}`, "0x8000000000000000000000000000000000000000000000000000000000000000", false},
+		{"overflow", `{"value":"57896044618658097711785492504343953926634992332820282019728792003956564819968"}`, "", true},
+		{"underflow", `{"value":"-57896044618658097711785492504343953926634992332820282019728792003956564819969"}`, "", true},
+		{"object", `{"value":{"a": "b"}}`, "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			input := models.RunResult{
+				Data: cltest.JSONFromString(test.json),
+			}
+			adapter := adapters.EthInt256{}
+			result := adapter.Perform(input, nil)
+
+			if test.errored {
+				assert.NotNil(t, result.GetError())
+			} else {
+				val, err := result.Value()
+				assert.Nil(t, err)
+				assert.Equal(t, test.want, val)
+				assert.Nil(t, result.GetError())
+			}
+		})
+	}
+}
@@ -1,33 +1,163 @@
 package adapters
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/asdine/storm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/utils"
 )
 
 // EthTx holds the Address to send the result to and the FunctionSelector
-// to execute.
+// to execute. When a Heartbeat is given, an answer that is within Tolerance
+// percent of the last answer submitted for the job is not sent again until
+// the heartbeat has passed since that submission. When a
+// BatchFunctionSelector is given, the call is queued and sent with the
+// other calls to the same contract in the next block, as the bytes[]
+// argument of that batch method. When SignFulfillment, or else
+// SIGN_FULFILLMENTS, is set, the node signs the request ID and result it
+// sends, and adds the signature to the run result.
 type EthTx struct {
-	Address          common.Address          `json:"address"`
-	FunctionSelector models.FunctionSelector `json:"functionSelector"`
-	DataPrefix       hexutil.Bytes           `json:"dataPrefix"`
+	Address               common.Address          `json:"address"`
+	FunctionSelector      models.FunctionSelector `json:"functionSelector"`
+	DataPrefix            hexutil.Bytes           `json:"dataPrefix"`
+	Heartbeat             string                  `json:"heartbeat"`
+	Tolerance             string                  `json:"tolerance"`
+	BatchFunctionSelector models.FunctionSelector `json:"batchFunctionSelector"`
+	SignFulfillment       *bool                   `json:"signFulfillment,omitempty"`
+}
+
+// submission is the answer last sent by an EthTx task, and when it was sent.
+type submission struct {
+	Answer string    `json:"answer"`
+	Time   time.Time `json:"time"`
 }
 
 // Perform creates the run result for the transaction if the existing run result
 // is not currently pending. Then it confirms the transaction was confirmed on
-// the blockchain.
+// the blockchain. A suppressed answer returns the input without sending a
+// transaction. Runs of the same job with a heartbeat wait for each other,
+// so that each compares its answer with the last one sent.
 func (etx *EthTx) Perform(input models.RunResult, store *store.Store) models.RunResult {
-	if !input.Pending {
-		return createTxRunResult(etx, input, store)
-	} else {
+	return etx.PerformContext(context.Background(), input, store)
+}
+
+// PerformContext is Perform, recording the call data sent in the
+// context's Tracer, if it has one. Transactions are not cancelled once
+// the context is done.
+func (etx *EthTx) PerformContext(ctx context.Context, input models.RunResult, store *store.Store) models.RunResult {
+	if input.Pending {
 		return ensureTxRunResult(input, store)
 	}
+	if etx.Heartbeat == "" {
+		return createTxRunResult(ctx, etx, input, store)
+	}
+
+	key, err := etx.submissionKey(input, store)
+	if err != nil {
+		return input.WithError(err)
+	}
+	defer lockJobState(key)()
+	unchanged, err := etx.unchanged(key, input, store)
+	if err != nil {
+		return input.WithError(err)
+	} else if unchanged {
+		logger.Infow("Answer unchanged, skipping transaction", "address", etx.Address.Hex(), "jobRun", input.JobRunID)
+		return input
+	}
+
+	output := createTxRunResult(ctx, etx, input, store)
+	if !output.HasError() {
+		answer, _ := input.Value()
+		last := submission{Answer: answer, Time: store.Clock.Now()}
+		if err := store.SetKV(key, last); err != nil {
+			return output.WithError(err)
+		}
+	}
+	return output
+}
+
+// submissionKey scopes the last submission to the run's job and the
+// contract it is sent to.
+func (etx *EthTx) submissionKey(input models.RunResult, store *store.Store) (string, error) {
+	jobID, err := jobIDFor(input, store)
+	if err != nil {
+		return "", fmt.Errorf("EthTx: %v to keep its job's last answer", err)
+	}
+	return fmt.Sprintf("ethtx/%v/%v", jobID, etx.Address.Hex()), nil
+}
+
+// unchanged returns true if the input's answer is within the tolerance of
+// the last submitted answer, saved under the key, and the heartbeat has not
+// passed since.
+func (etx *EthTx) unchanged(key string, input models.RunResult, store *store.Store) (bool, error) {
+	heartbeat, err := time.ParseDuration(etx.Heartbeat)
+	if err != nil {
+		return false, fmt.Errorf("EthTx: invalid heartbeat %v", etx.Heartbeat)
+	}
+	tolerance := new(big.Float)
+	if etx.Tolerance != "" {
+		if _, ok := tolerance.SetString(etx.Tolerance); !ok || tolerance.Sign() < 0 {
+			return false, fmt.Errorf("EthTx: invalid tolerance %v", etx.Tolerance)
+		}
+	}
+
+	var last submission
+	err = store.GetKV(key, &last)
+	if err == storm.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if !store.Clock.Now().Before(last.Time.Add(heartbeat)) {
+		return false, nil
+	}
+
+	answer, err := input.Value()
+	if err != nil {
+		return false, err
+	}
+	cur, ok := parseAnswer(answer)
+	if !ok {
+		return false, fmt.Errorf("EthTx: %v is not a number", answer)
+	}
+	prev, ok := parseAnswer(last.Answer)
+	if !ok {
+		return false, nil
+	}
+	if prev.Sign() == 0 {
+		return cur.Sign() == 0, nil
+	}
+	diff := new(big.Float).Sub(cur, prev)
+	diff.Abs(diff)
+	percent := diff.Quo(diff, new(big.Float).Abs(prev))
+	percent.Mul(percent, big.NewFloat(100))
+	return percent.Cmp(tolerance) <= 0, nil
+}
+
+// parseAnswer reads an answer formatted by EthUint256 as hex, or a plain
+// decimal number.
+func parseAnswer(answer string) (*big.Float, bool) {
+	if utils.RemoveHexPrefix(answer) != answer {
+		i, ok := new(big.Int).SetString(utils.RemoveHexPrefix(answer), 16)
+		if !ok {
+			return nil, false
+		}
+		return new(big.Float).SetInt(i), true
+	}
+	return new(big.Float).SetString(answer)
 }
 
 func createTxRunResult(
+	ctx context.Context,
 	e *EthTx,
 	input models.RunResult,
 	store *store.Store,
@@ -41,17 +171,99 @@ func createTxRunResult(
 	if err != nil {
 		return input.WithError(err)
 	}
+	tracerFrom(ctx).callData(data)
+	if e.signsFulfillment(store) {
+		input, err = signFulfillment(input, data[models.FunctionSelectorLength:], store)
+		if err != nil {
+			return input.WithError(err)
+		}
+	}
+	if e.BatchFunctionSelector != (models.FunctionSelector{}) {
+		return queueCallRunResult(e, input, data, store)
+	}
 
-	attempt, err := store.TxManager.CreateTx(e.Address, data)
+	attempt, err := store.TxManager.CreateTxForRun(input.JobRunID, e.Address, data)
 	if err != nil {
 		return input.WithError(err)
 	}
 
 	sendResult := input.WithValue(attempt.Hash.String())
+	if store.CurrentConfig().DryRun {
+		return sendResult
+	}
 	return ensureTxRunResult(sendResult, store)
 }
 
+func (e *EthTx) signsFulfillment(store *store.Store) bool {
+	if e.SignFulfillment != nil {
+		return *e.SignFulfillment
+	}
+	return store.CurrentConfig().SignFulfillments
+}
+
+// FulfillmentHash returns the hash of a fulfillment's request ID, the
+// dataPrefix, followed by its result, that the node signs.
+func FulfillmentHash(payload []byte) common.Hash {
+	return crypto.Keccak256Hash(payload)
+}
+
+// signFulfillment adds the node's signature of the fulfillment's hash, as
+// eth_sign makes it, to the input's "signature", and the node's address
+// to its "signer", so that consumers can check which node gave an answer
+// with ecrecover.
+func signFulfillment(input models.RunResult, payload []byte, store *store.Store) (models.RunResult, error) {
+	hash := FulfillmentHash(payload)
+	sig, err := store.Signer.SignMessage(hash.Bytes())
+	if err != nil {
+		return input, fmt.Errorf("EthTx: signing fulfillment: %v", err)
+	}
+	data, err := input.Data.Add("signature", hexutil.Encode(sig))
+	if err != nil {
+		return input, err
+	}
+	data, err = data.Add("signer", store.Signer.GetAccount().Address.Hex())
+	if err != nil {
+		return input, err
+	}
+	input.Data = data
+	return input, nil
+}
+
+// queueCallRunResult queues the call to be batched, and leaves the run
+// pending with the call's ID until its batch is sent.
+func queueCallRunResult(
+	e *EthTx,
+	input models.RunResult,
+	data []byte,
+	store *store.Store,
+) models.RunResult {
+	call, err := store.TxManager.QueueCallForRun(input.JobRunID, e.Address, e.BatchFunctionSelector, data)
+	if err != nil {
+		return input.WithError(err)
+	}
+	queued, err := input.Data.Add("batchedCallId", call.ID)
+	if err != nil {
+		return input.WithError(err)
+	}
+	input.Data = queued
+	return input.MarkPending()
+}
+
 func ensureTxRunResult(input models.RunResult, store *store.Store) models.RunResult {
+	if id := input.Data.Get("batchedCallId"); id.Exists() {
+		call := models.BatchedCall{}
+		if err := store.One("ID", id.Uint(), &call); err != nil {
+			return input.WithError(err)
+		}
+		if !call.Sent {
+			return input.MarkPending()
+		}
+		input = input.WithValue(call.TxHash.String())
+		if store.CurrentConfig().DryRun {
+			return input
+		}
+	}
+
 	val, err := input.Value()
 	if err != nil {
 		return input.WithError(err)
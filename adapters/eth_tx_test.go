@@ -3,6 +3,7 @@ package adapters_test
 import (
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/smartcontractkit/chainlink/adapters"
@@ -198,3 +199,175 @@ func TestEthTxAdapter_Perform_WithError(t *testing.T) {
 	assert.True(t, output.HasError())
 	assert.Equal(t, output.Error(), "Cannot connect to nodes")
 }
+
+func TestEthTxAdapter_Perform_DryRun(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	store.Config.DryRun = true
+	store.TxManager.Config.DryRun = true
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", `0x0100`)
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+
+	adapter := adapters.EthTx{
+		Address:          cltest.NewAddress(),
+		FunctionSelector: models.HexToFunctionSelector("0xb3f98adc"),
+	}
+	output := adapter.Perform(cltest.RunResultWithValue("0x9786856756"), store)
+
+	assert.False(t, output.HasError())
+	assert.False(t, output.Pending)
+
+	from := store.KeyStore.GetAccount().Address
+	txs := []models.Tx{}
+	assert.Nil(t, store.Where("From", from, &txs))
+	assert.Equal(t, 1, len(txs))
+	val, err := output.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, txs[0].Hash.String(), val)
+
+	ethMock.EnsureAllCalled(t)
+}
+
+func TestEthTxAdapter_Perform_SignFulfillment(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	store.Config.DryRun = true
+	store.TxManager.Config.DryRun = true
+	store.Config.SignFulfillments = true
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", `0x0100`)
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+
+	requestID := cltest.NewHash()
+	adapter := adapters.EthTx{
+		Address:          cltest.NewAddress(),
+		FunctionSelector: models.HexToFunctionSelector("0xb3f98adc"),
+		DataPrefix:       requestID.Bytes(),
+	}
+	output := adapter.Perform(cltest.RunResultWithValue("0x9786856756"), store)
+	assert.False(t, output.HasError())
+
+	from := store.KeyStore.GetAccount().Address
+	assert.Equal(t, from.Hex(), output.Data.Get("signer").String())
+	sig, err := hexutil.Decode(output.Data.Get("signature").String())
+	assert.Nil(t, err)
+	hash := adapters.FulfillmentHash(append(requestID.Bytes(), hexutil.MustDecode("0x9786856756")...))
+	signer, err := strpkg.RecoverMessageSigner(hash.Bytes(), sig)
+	assert.Nil(t, err)
+	assert.Equal(t, from, signer)
+
+	off := false
+	adapter.SignFulfillment = &off
+	ethMock.Register("eth_getTransactionCount", `0x0101`)
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+	output = adapter.Perform(cltest.RunResultWithValue("0x9786856756"), store)
+	assert.False(t, output.HasError())
+	assert.False(t, output.Data.Get("signature").Exists(), "signFulfillment overrides SIGN_FULFILLMENTS")
+}
+
+func TestEthTxAdapter_Perform_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	store.Config.DryRun = true
+	store.TxManager.Config.DryRun = true
+	clock := cltest.UseSettableClock(store)
+	clock.SetTime(time.Now())
+
+	ethMock := app.MockEthClient()
+	for i := 0; i < 3; i++ {
+		ethMock.Register("eth_getTransactionCount", `0x0100`)
+		ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+	}
+
+	adapter := adapters.EthTx{
+		Address:          cltest.NewAddress(),
+		FunctionSelector: models.HexToFunctionSelector("0xb3f98adc"),
+		Heartbeat:        "1h",
+		Tolerance:        "2",
+	}
+	from := store.KeyStore.GetAccount().Address
+	job := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, store.SaveJob(&job))
+
+	output := adapter.Perform(cltest.RunResultWithValue("0x64"), store)
+	assert.Contains(t, output.Error(), "was not found", "unsaved runs have no job to keep the last answer for")
+
+	tests := []struct {
+		name    string
+		value   string
+		elapsed time.Duration
+		wantTxs int
+	}{
+		{"first answer", "0x64", 0, 1},
+		{"within tolerance", "0x65", time.Minute, 1},
+		{"outside tolerance", "0x6e", time.Minute, 2},
+		{"heartbeat expired", "0x6e", time.Hour, 3},
+	}
+
+	for _, test := range tests {
+		clock.SetTime(clock.Now().Add(test.elapsed))
+		jr := job.NewRun()
+		assert.Nil(t, store.Save(&jr))
+		input := cltest.RunResultWithValue(test.value)
+		input.JobRunID = jr.ID
+		output := adapter.Perform(input, store)
+		assert.False(t, output.HasError(), test.name)
+
+		txs := []models.Tx{}
+		assert.Nil(t, store.Where("From", from, &txs))
+		assert.Equal(t, test.wantTxs, len(txs), test.name)
+	}
+
+	ethMock.EnsureAllCalled(t)
+}
+
+func TestEthTxAdapter_Perform_Batched(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	store.Config.DryRun = true
+	store.TxManager.Config.DryRun = true
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", `0x0100`)
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+
+	adapter := adapters.EthTx{
+		Address:               cltest.NewAddress(),
+		FunctionSelector:      models.HexToFunctionSelector("0xb3f98adc"),
+		BatchFunctionSelector: models.HexToFunctionSelector("0x12345678"),
+	}
+	queued := adapter.Perform(cltest.RunResultWithValue("0x9786856756"), store)
+	assert.False(t, queued.HasError())
+	assert.True(t, queued.Pending)
+
+	output := adapter.Perform(queued, store)
+	assert.True(t, output.Pending, "should stay pending until the batch is sent")
+
+	assert.Nil(t, store.TxManager.SendBatches())
+	output = adapter.Perform(queued, store)
+	assert.False(t, output.HasError())
+	assert.False(t, output.Pending)
+
+	call := models.BatchedCall{}
+	assert.Nil(t, store.One("ID", queued.Data.Get("batchedCallId").Uint(), &call))
+	val, err := output.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, call.TxHash.String(), val)
+
+	ethMock.EnsureAllCalled(t)
+}
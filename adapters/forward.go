@@ -0,0 +1,137 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// ForwardedRunsPath is where a node's API executes the tasks forwarded to
+// it by another node.
+const ForwardedRunsPath = "/v2/forwarded_runs"
+
+// Forward holds tasks that are executed by the secondary node at
+// FORWARD_URL rather than this one, so that a node listening for requests
+// can leave the work of answering them to another machine, while still
+// fulfilling them with its own key.
+type Forward struct {
+	Tasks []models.Task `json:"tasks"`
+}
+
+// ForwardRequest is what is POSTed to the secondary node: the tasks to
+// execute, and the data to start them with.
+type ForwardRequest struct {
+	JobRunID string        `json:"jobRunId"`
+	Data     models.JSON   `json:"data"`
+	Tasks    []models.Task `json:"tasks"`
+}
+
+func (f *Forward) validate(store *store.Store) error {
+	if store.CurrentConfig().ForwardURL == "" {
+		return errors.New("Forward: FORWARD_URL is not set")
+	}
+	if len(f.Tasks) == 0 {
+		return errors.New("Forward: must have tasks")
+	}
+	return ValidateForwarded(f.Tasks)
+}
+
+// forwardDenied are the adapters whose tasks are never forwarded:
+// transactions and randomness are made with the keys of the node the
+// request was made to, and forwarded tasks are not forwarded again.
+var forwardDenied = map[string]bool{
+	"ethtx":   true,
+	"forward": true,
+	"random":  true,
+}
+
+// ValidateForwarded returns an error if any of the tasks, or the tasks of
+// their Parallel groups or onError fallbacks, must be executed by the node
+// the run belongs to. The tasks' adapters are otherwise only known to the
+// secondary node, which may have bridges this one does not.
+func ValidateForwarded(tasks []models.Task) error {
+	for _, task := range tasks {
+		taskType := strings.ToLower(task.Type)
+		if forwardDenied[taskType] {
+			return fmt.Errorf("Forward: %v tasks cannot be forwarded", task.Type)
+		}
+		if err := ValidateForwarded(task.OnError); err != nil {
+			return err
+		}
+		if taskType != "parallel" {
+			continue
+		}
+		group := []models.Task{}
+		if raw := task.Params.Get("tasks").Raw; raw != "" {
+			if err := json.Unmarshal([]byte(raw), &group); err != nil {
+				return fmt.Errorf("Forward: %v", err)
+			}
+		}
+		if err := ValidateForwarded(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Perform POSTs the tasks and the input's data to the secondary node, as
+// a client with FORWARD_TOKEN as its API token, and returns the data its
+// run of them ended with. The forwarded run errors if the secondary node's
+// run errors or is left pending.
+func (f *Forward) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	body, err := json.Marshal(ForwardRequest{
+		JobRunID: input.JobRunID,
+		Data:     input.Data,
+		Tasks:    f.Tasks,
+	})
+	if err != nil {
+		return forwardError(input, "marshaling request body", err)
+	}
+
+	url := strings.TrimRight(store.CurrentConfig().ForwardURL, "/") + ForwardedRunsPath
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return forwardError(input, "building request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := store.CurrentConfig().ForwardToken; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := httpClient(store).Do(req)
+	if err != nil {
+		return forwardError(input, "POST request", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return forwardError(input, "reading response body", err)
+	}
+	if resp.StatusCode >= 400 {
+		return forwardError(input, "POST response", fmt.Errorf("%v %v", resp.StatusCode, string(b)))
+	}
+
+	rr := models.RunResult{}
+	if err := json.Unmarshal(b, &rr); err != nil {
+		return forwardError(input, "unmarshaling JSON", err)
+	}
+	if rr.HasError() {
+		return forwardError(input, "secondary run "+rr.JobRunID, rr.GetError())
+	}
+	if rr.Pending {
+		return forwardError(input, "secondary run "+rr.JobRunID, errors.New("pending tasks are not supported"))
+	}
+	input.Data = rr.Data
+	return input
+}
+
+func forwardError(in models.RunResult, str string, err error) models.RunResult {
+	return in.WithError(fmt.Errorf("Forward %v: %v", str, err))
+}
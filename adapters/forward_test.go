@@ -0,0 +1,109 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForward_Perform(t *testing.T) {
+	cases := []struct {
+		name        string
+		status      int
+		response    string
+		want        string
+		wantErrored bool
+	}{
+		{"success", 200, `{"jobRunId":"remote","data":{"value":"100.1"}}`, "100.1", false},
+		{"run error", 200, `{"jobRunId":"remote","data":{},"error":"overload"}`, "lot 49", true},
+		{"pending", 200, `{"jobRunId":"remote","data":{},"pending":true}`, "lot 49", true},
+		{"server error", 500, `big error`, "lot 49", true},
+		{"JSON parse error", 200, `}`, "lot 49", true},
+	}
+
+	for _, tt := range cases {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var request adapters.ForwardRequest
+			var auth, path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				assert.Nil(t, err)
+				assert.Nil(t, json.Unmarshal(b, &request))
+				auth = r.Header.Get("Authorization")
+				path = r.URL.Path
+				w.WriteHeader(test.status)
+				io.WriteString(w, test.response)
+			}))
+			defer server.Close()
+
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			store.Config.ForwardURL = server.URL + "/"
+			store.Config.ForwardToken = "secret"
+
+			forward := adapters.Forward{Tasks: []models.Task{
+				cltest.NewTask("httpget", `{"url":"https://example.com"}`),
+				cltest.NewTask("jsonparse", `{"path":["last"]}`),
+			}}
+			input := cltest.RunResultWithValue("lot 49")
+			input.JobRunID = "local"
+			result := forward.Perform(input, store)
+
+			assert.Equal(t, adapters.ForwardedRunsPath, path)
+			assert.Equal(t, "Bearer secret", auth)
+			assert.Equal(t, "local", request.JobRunID)
+			assert.Equal(t, "lot 49", request.Data.Get("value").String())
+			assert.Equal(t, 2, len(request.Tasks))
+			assert.Equal(t, "jsonparse", request.Tasks[1].Type)
+
+			val, _ := result.Get("value")
+			assert.Equal(t, test.want, val.String())
+			assert.Equal(t, test.wantErrored, result.HasError())
+			assert.False(t, result.Pending)
+		})
+	}
+}
+
+func TestForward_Validate(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	forward := cltest.NewTask("forward", `{"tasks":[{"type":"noop"}]}`)
+	_, err := adapters.For(forward, store)
+	assert.NotNil(t, err, "FORWARD_URL must be set")
+
+	store.Config.ForwardURL = "https://secondary.example.com"
+	_, err = adapters.For(forward, store)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"no tasks", `{"tasks":[]}`},
+		{"ethtx", `{"tasks":[{"type":"EthTx"}]}`},
+		{"nested forward", `{"tasks":[{"type":"Forward","tasks":[{"type":"NoOp"}]}]}`},
+		{"random", `{"tasks":[{"type":"Random"}]}`},
+		{"ethtx in a group", `{"tasks":[{"type":"Parallel","tasks":[{"type":"NoOp"},{"type":"EthTx"}]}]}`},
+		{"ethtx fallback", `{"tasks":[{"type":"NoOp","onError":[{"type":"EthTx"}]}]}`},
+		{"ethtx fallback in a group", `{"tasks":[{"type":"Parallel","tasks":[{"type":"NoOp","onError":[{"type":"EthTx"}]}]}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := adapters.For(cltest.NewTask("forward", test.params), store)
+			assert.NotNil(t, err)
+		})
+	}
+}
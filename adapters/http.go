@@ -1,68 +1,151 @@
 package adapters
 
 import (
-	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 )
 
 // HttpGet requires a URL which is used for a GET request when the adapter is called.
+// Successful responses are reused for CacheTTL, if set, or else
+// ADAPTER_CACHE_TTL. Its DomainLists limit the hosts it, or redirects from
+// the URL, can send requests to. Its Auth, if given, authenticates the
+// request with a credential saved on the node.
 type HttpGet struct {
-	URL models.WebURL `json:"url"`
+	URL      models.WebURL    `json:"url"`
+	CacheTTL *models.Duration `json:"cacheTTL,omitempty"`
+	Auth     *HTTPAuth        `json:"auth,omitempty"`
+	models.DomainLists
 }
 
 // Perform ensures that the adapter's URL responds to a GET request without
 // errors and returns the response body as the "value" field of the result.
-func (hga *HttpGet) Perform(input models.RunResult, _ *store.Store) models.RunResult {
-	response, err := http.Get(hga.URL.String())
-	if err != nil {
+func (hga *HttpGet) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	return hga.PerformContext(context.Background(), input, store)
+}
+
+// PerformContext is Perform, with the request cancelled once the context
+// is done.
+func (hga *HttpGet) PerformContext(ctx context.Context, input models.RunResult, store *store.Store) models.RunResult {
+	if err := checkEgress(ctx, hga.URL.URL, hga.DomainLists, input, store); err != nil {
 		return input.WithError(err)
 	}
-
-	defer response.Body.Close()
-
-	bytes, err := ioutil.ReadAll(response.Body)
-	body := string(bytes)
-	if err != nil {
+	if err := hga.Auth.checkEgress(ctx, hga.DomainLists, input, store); err != nil {
 		return input.WithError(err)
 	}
-
-	if response.StatusCode >= 400 {
-		return input.WithError(fmt.Errorf(body))
+	client := egressClient(hga.DomainLists, input, store)
+	body, err := sendCached(ctx, client, "GET", hga.URL.String(), "", hga.Auth, cacheTTL(hga.CacheTTL, store), store)
+	if err != nil {
+		return input.WithError(err)
 	}
-
 	return input.WithValue(body)
 }
 
 // HttpPost requires a URL which is used for a POST request when the adapter is called.
+// Successful responses to the same body are reused for CacheTTL, if set,
+// or else ADAPTER_CACHE_TTL. Its DomainLists limit the hosts it, or
+// redirects from the URL, can send requests to. Its Auth, if given,
+// authenticates the request with a credential saved on the node.
 type HttpPost struct {
-	URL models.WebURL `json:"url"`
+	URL      models.WebURL    `json:"url"`
+	CacheTTL *models.Duration `json:"cacheTTL,omitempty"`
+	Auth     *HTTPAuth        `json:"auth,omitempty"`
+	models.DomainLists
 }
 
 // Perform ensures that the adapter's URL responds to a POST request without
 // errors and returns the response body as the "value" field of the result.
-func (hga *HttpPost) Perform(input models.RunResult, _ *store.Store) models.RunResult {
-	reqBody := bytes.NewBufferString(input.Data.String())
-	response, err := http.Post(hga.URL.String(), "application/json", reqBody)
+func (hga *HttpPost) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	return hga.PerformContext(context.Background(), input, store)
+}
+
+// PerformContext is Perform, with the request cancelled once the context
+// is done.
+func (hga *HttpPost) PerformContext(ctx context.Context, input models.RunResult, store *store.Store) models.RunResult {
+	if err := checkEgress(ctx, hga.URL.URL, hga.DomainLists, input, store); err != nil {
+		return input.WithError(err)
+	}
+	if err := hga.Auth.checkEgress(ctx, hga.DomainLists, input, store); err != nil {
+		return input.WithError(err)
+	}
+	client := egressClient(hga.DomainLists, input, store)
+	body, err := sendCached(ctx, client, "POST", hga.URL.String(), input.Data.String(), hga.Auth, cacheTTL(hga.CacheTTL, store), store)
 	if err != nil {
 		return input.WithError(err)
 	}
+	return input.WithValue(body)
+}
 
-	defer response.Body.Close()
+// sendCached returns the body of the response to the request, reusing the
+// response to the same request with the same auth if it was fetched
+// within the last ttl.
+func sendCached(ctx context.Context, client *http.Client, method, url, body string, auth *HTTPAuth, ttl time.Duration, store *store.Store) (string, error) {
+	key := method + " " + url + "\n" + auth.cacheKey() + "\n" + body
+	fetched := false
+	response, err := responseCache.get(ctx, key, ttl, cacheClock(store), func() (string, error) {
+		fetched = true
+		return sendRequest(ctx, client, method, url, body, auth, store)
+	})
+	if !fetched && err == nil {
+		req := models.TracedRequest{Method: method, URL: url, Body: body, Cached: true}
+		tracerFrom(ctx).request(req, []byte(response), nil)
+	}
+	return response, err
+}
 
-	bytes, err := ioutil.ReadAll(response.Body)
-	body := string(bytes)
+// sendRequest makes the request, with the body as JSON unless it is a
+// GET, and returns the body of the response, or an error if its status is
+// 400 or above. A request refused with a 401 while using an OAuth2 token
+// is retried once with a new token, in case the token was revoked.
+func sendRequest(ctx context.Context, client *http.Client, method, url, body string, auth *HTTPAuth, store *store.Store) (string, error) {
+	client = auth.guardRedirects(client)
+	status, bytes, err := doRequest(ctx, client, method, url, body, auth, store)
+	if err == nil && status == http.StatusUnauthorized && auth.refreshable() {
+		auth.forgetToken()
+		status, bytes, err = doRequest(ctx, client, method, url, body, auth, store)
+	}
 	if err != nil {
-		return input.WithError(err)
+		return "", err
+	}
+	if status >= 400 {
+		return "", fmt.Errorf("%v %v: %v", status, http.StatusText(status), string(bytes))
 	}
+	return string(bytes), nil
+}
 
-	if response.StatusCode >= 400 {
-		return input.WithError(fmt.Errorf(body))
+func doRequest(ctx context.Context, client *http.Client, method, url, body string, auth *HTTPAuth, store *store.Store) (int, []byte, error) {
+	var reqBody io.Reader
+	if method != "GET" {
+		reqBody = strings.NewReader(body)
+	}
+	request, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return 0, nil, err
+	}
+	if method != "GET" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if err := auth.authorize(ctx, request, store); err != nil {
+		return 0, nil, err
+	}
+	traced := models.TracedRequest{Method: method, URL: url, Body: body}
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		tracerFrom(ctx).request(traced, nil, err)
+		return 0, nil, err
 	}
 
-	return input.WithValue(body)
+	defer response.Body.Close()
+
+	bytes, err := ioutil.ReadAll(response.Body)
+	traced.Status = response.StatusCode
+	tracerFrom(ctx).request(traced, bytes, err)
+	return response.StatusCode, bytes, err
 }
@@ -0,0 +1,236 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// The HTTPAuth types.
+const (
+	// AuthHeader sends the credential in a header, such as X-API-Key.
+	AuthHeader = "header"
+	// AuthBasic sends the username and the credential as the password with
+	// HTTP basic auth.
+	AuthBasic = "basic"
+	// AuthOAuth2 sends a bearer token fetched from TokenURL with the OAuth2
+	// client credentials grant, with the credential as the client secret.
+	AuthOAuth2 = "oauth2"
+)
+
+// tokenExpiryMargin is how long before it expires an OAuth2 token is
+// refreshed, so that it does not expire while a request is in flight.
+const tokenExpiryMargin = 30 * time.Second
+
+// HTTPAuth is how HttpGet and HttpPost tasks authenticate their requests.
+// Credential names a credential saved on the node, whose value is only
+// read when the request is made, so that the job spec holds no secrets.
+type HTTPAuth struct {
+	Type       string   `json:"type"`
+	Credential string   `json:"credential"`
+	Header     string   `json:"header,omitempty"`
+	Prefix     string   `json:"prefix,omitempty"`
+	Username   string   `json:"username,omitempty"`
+	TokenURL   string   `json:"tokenUrl,omitempty"`
+	ClientID   string   `json:"clientId,omitempty"`
+	Scopes     []string `json:"scopes,omitempty"`
+}
+
+// oauth2Token is an access token fetched for a client, and when it expires.
+type oauth2Token struct {
+	value   string
+	expires time.Time
+}
+
+// oauth2Tokens holds the tokens fetched for each client, so that they are
+// reused by runs until they are about to expire.
+var (
+	oauth2Tokens      = map[string]oauth2Token{}
+	oauth2TokensMutex sync.Mutex
+)
+
+// validate returns an error if the auth is missing what its type needs,
+// or its credential has not been saved.
+func (a *HTTPAuth) validate(store *store.Store) error {
+	if a == nil {
+		return nil
+	}
+	switch a.Type {
+	case AuthHeader, AuthBasic:
+	case AuthOAuth2:
+		if u, err := url.ParseRequestURI(a.TokenURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("auth: tokenUrl must be an http or https URL, got %v", a.TokenURL)
+		}
+		if a.ClientID == "" {
+			return fmt.Errorf("auth: oauth2 needs a clientId")
+		}
+	default:
+		return fmt.Errorf("auth: unknown type %v, must be header, basic, or oauth2", a.Type)
+	}
+	if a.Credential == "" {
+		return fmt.Errorf("auth: must name a credential")
+	}
+	if store != nil {
+		if _, err := store.FindCredential(a.Credential); err != nil {
+			return fmt.Errorf("auth: credential %v not found", a.Credential)
+		}
+	}
+	return nil
+}
+
+// checkEgress checks that the token URL may be sent requests, as the
+// task's URL is, since the credential is sent to it.
+func (a *HTTPAuth) checkEgress(ctx context.Context, task models.DomainLists, input models.RunResult, store *store.Store) error {
+	if a == nil || a.Type != AuthOAuth2 {
+		return nil
+	}
+	u, err := url.Parse(a.TokenURL)
+	if err != nil {
+		return err
+	}
+	return checkEgress(ctx, u, task, input, store)
+}
+
+// cacheKey distinguishes cached responses to requests made with different
+// auth, so that a response is only reused by tasks using the same
+// credential.
+func (a *HTTPAuth) cacheKey() string {
+	if a == nil {
+		return ""
+	}
+	return a.Type + " " + a.Credential + " " + a.Username + " " + a.ClientID
+}
+
+// authorize adds the auth to the request.
+func (a *HTTPAuth) authorize(ctx context.Context, request *http.Request, store *store.Store) error {
+	if a == nil {
+		return nil
+	}
+	if store == nil {
+		return fmt.Errorf("auth: credential %v not found", a.Credential)
+	}
+	secret, err := store.CredentialValue(a.Credential)
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case AuthHeader:
+		header := a.Header
+		if header == "" {
+			header = "Authorization"
+		}
+		request.Header.Set(header, a.Prefix+secret)
+	case AuthBasic:
+		request.SetBasicAuth(a.Username, secret)
+	case AuthOAuth2:
+		token, err := a.token(ctx, secret, store)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return fmt.Errorf("auth: unknown type %v", a.Type)
+	}
+	return nil
+}
+
+// guardRedirects returns the client, made to drop the credential's header
+// from requests redirected to another host. The standard library only
+// drops Authorization and cookies itself.
+func (a *HTTPAuth) guardRedirects(client *http.Client) *http.Client {
+	if a == nil || a.Type != AuthHeader || a.Header == "" {
+		return client
+	}
+	guarded := *client
+	guarded.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host {
+			req.Header.Del(a.Header)
+		}
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+		return nil
+	}
+	return &guarded
+}
+
+// refreshable returns true if a request refused with a 401 should be
+// retried with a new token.
+func (a *HTTPAuth) refreshable() bool {
+	return a != nil && a.Type == AuthOAuth2
+}
+
+func (a *HTTPAuth) tokenKey() string {
+	return a.TokenURL + "\n" + a.ClientID + "\n" + a.Credential + "\n" + strings.Join(a.Scopes, " ")
+}
+
+// forgetToken drops the client's token, so that the next request fetches
+// a new one.
+func (a *HTTPAuth) forgetToken() {
+	oauth2TokensMutex.Lock()
+	defer oauth2TokensMutex.Unlock()
+	delete(oauth2Tokens, a.tokenKey())
+}
+
+// token returns the client's access token, fetching a new one if there is
+// none or it is about to expire.
+func (a *HTTPAuth) token(ctx context.Context, secret string, store *store.Store) (string, error) {
+	key := a.tokenKey()
+	now := store.Clock.Now()
+	oauth2TokensMutex.Lock()
+	cached, ok := oauth2Tokens[key]
+	oauth2TokensMutex.Unlock()
+	if ok && now.Add(tokenExpiryMargin).Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.Scopes, " "))
+	}
+	request, err := http.NewRequest("POST", a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(a.ClientID), url.QueryEscape(secret))
+	response, err := externalHTTPClient(store).Do(request.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("auth: fetching token: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("auth: fetching token: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("auth: token endpoint responded %v", response.Status)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("auth: token endpoint gave no access_token")
+	}
+	token := oauth2Token{value: result.AccessToken}
+	if result.ExpiresIn > 0 {
+		token.expires = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+		oauth2TokensMutex.Lock()
+		oauth2Tokens[key] = token
+		oauth2TokensMutex.Unlock()
+	}
+	return token.value, nil
+}
@@ -0,0 +1,146 @@
+package adapters_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpGet_Perform_Auth(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	_, err := store.SaveCredential("prices-api", "s3cret")
+	assert.Nil(t, err)
+
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+	url := cltest.MustParseWebURL(server.URL)
+
+	hga := adapters.HttpGet{URL: url, Auth: &adapters.HTTPAuth{
+		Type: adapters.AuthHeader, Credential: "prices-api", Header: "X-API-Key",
+	}}
+	assert.Nil(t, hga.Perform(input, store).GetError())
+	assert.Equal(t, "s3cret", received.Header.Get("X-API-Key"))
+
+	hga.Auth = &adapters.HTTPAuth{Type: adapters.AuthHeader, Credential: "prices-api", Prefix: "Bearer "}
+	assert.Nil(t, hga.Perform(input, store).GetError())
+	assert.Equal(t, "Bearer s3cret", received.Header.Get("Authorization"))
+
+	hga.Auth = &adapters.HTTPAuth{Type: adapters.AuthBasic, Credential: "prices-api", Username: "node"}
+	assert.Nil(t, hga.Perform(input, store).GetError())
+	username, password, ok := received.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "node", username)
+	assert.Equal(t, "s3cret", password)
+
+	hga.Auth = &adapters.HTTPAuth{Type: adapters.AuthBasic, Credential: "missing"}
+	assert.True(t, hga.Perform(input, store).HasError())
+}
+
+func TestHttpGet_Perform_AuthOAuth2(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	_, err := store.SaveCredential("client-secret", "s3cret")
+	assert.Nil(t, err)
+
+	var issued int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		assert.Equal(t, "node", clientID)
+		assert.Equal(t, "s3cret", secret)
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "prices", r.PostForm.Get("scope"))
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token%d","expires_in":3600}`, n)
+	}))
+	defer tokenServer.Close()
+	revoked := "token1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+revoked {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(server.URL), Auth: &adapters.HTTPAuth{
+		Type:       adapters.AuthOAuth2,
+		Credential: "client-secret",
+		TokenURL:   tokenServer.URL,
+		ClientID:   "node",
+		Scopes:     []string{"prices"},
+	}}
+	val, err := hga.Perform(input, store).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer token2", val, "a refused token is refreshed")
+	val, err = hga.Perform(input, store).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer token2", val, "the token is reused until it expires")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued))
+}
+
+func TestHttpGet_Perform_AuthRedirect(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	_, err := store.SaveCredential("prices-api", "s3cret")
+	assert.Nil(t, err)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Header.Get("X-API-Key"))
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(
+		"http://localhost:"+cltest.MustParseWebURL(target.URL).Port(), http.StatusFound))
+	defer redirect.Close()
+
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(redirect.URL), Auth: &adapters.HTTPAuth{
+		Type: adapters.AuthHeader, Credential: "prices-api", Header: "X-API-Key",
+	}}
+	val, err := hga.Perform(input, store).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "", val, "the credential is not sent on to another host")
+}
+
+func TestAdapterFor_HttpAuth(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	_, err := store.SaveCredential("prices-api", "s3cret")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{"header", `{"url":"https://a.com","auth":{"type":"header","credential":"prices-api"}}`, false},
+		{"unknown credential", `{"url":"https://a.com","auth":{"type":"header","credential":"other"}}`, true},
+		{"unknown type", `{"url":"https://a.com","auth":{"type":"digest","credential":"prices-api"}}`, true},
+		{"oauth2 without tokenUrl", `{"url":"https://a.com","auth":{"type":"oauth2","credential":"prices-api","clientId":"a"}}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := adapters.For(cltest.NewTask("httpget", test.params), store)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// maxCachedResponses caps how many responses are cached, so that jobs
+// requesting many different URLs do not grow the cache without bound.
+const maxCachedResponses = 1000
+
+// responseCache holds the bodies of HttpGet and HttpPost responses, so
+// that jobs requesting the same URL with the same body at about the same
+// time share one response.
+var responseCache = &httpCache{entries: map[string]*cachedResponse{}}
+
+// cachedResponse is a response that has been, or is being, fetched. Its
+// fields are set before ready is closed.
+type cachedResponse struct {
+	body    string
+	err     error
+	fetched time.Time
+	expires time.Time
+	ready   chan struct{}
+}
+
+type httpCache struct {
+	entries map[string]*cachedResponse
+	mutex   sync.Mutex
+}
+
+// cacheTTL returns how long a task's responses are reused for: the task's
+// cacheTTL if it has one, or else ADAPTER_CACHE_TTL.
+func cacheTTL(override *models.Duration, store *store.Store) time.Duration {
+	if override != nil {
+		return time.Duration(*override)
+	}
+	if store == nil {
+		return 0
+	}
+	return store.CurrentConfig().AdapterCacheTTL
+}
+
+// cacheClock returns the clock responses expire by.
+func cacheClock(s *store.Store) store.AfterNower {
+	if s == nil {
+		return store.Clock{}
+	}
+	return s.Clock
+}
+
+// get returns the body fetched for the key within the last ttl, calling
+// fetch for it if there is none. Requests for a key being fetched wait for
+// it rather than fetching it again. Only successful responses are kept, and
+// nothing is cached if ttl is 0.
+func (c *httpCache) get(ctx context.Context, key string, ttl time.Duration, clock store.AfterNower, fetch func() (string, error)) (string, error) {
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	c.mutex.Lock()
+	now := clock.Now()
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.ready:
+			if now.Before(entry.fetched.Add(ttl)) {
+				c.mutex.Unlock()
+				metrics.AdapterCacheHits.Inc()
+				return entry.body, nil
+			}
+		default:
+			c.mutex.Unlock()
+			select {
+			case <-entry.ready:
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+			if entry.err != nil {
+				metrics.AdapterCacheMisses.Inc()
+				return fetch()
+			}
+			metrics.AdapterCacheHits.Inc()
+			return entry.body, nil
+		}
+	}
+	if len(c.entries) >= maxCachedResponses {
+		c.sweep(now)
+	}
+	if len(c.entries) >= maxCachedResponses {
+		c.mutex.Unlock()
+		metrics.AdapterCacheMisses.Inc()
+		return fetch()
+	}
+	entry := &cachedResponse{ready: make(chan struct{})}
+	c.entries[key] = entry
+	c.mutex.Unlock()
+
+	metrics.AdapterCacheMisses.Inc()
+	body, err := fetch()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry.body, entry.err = body, err
+	entry.fetched = clock.Now()
+	entry.expires = entry.fetched.Add(ttl)
+	if err != nil && c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	close(entry.ready)
+	return body, err
+}
+
+// sweep removes the responses that have expired. The mutex must be held.
+func (c *httpCache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		select {
+		case <-entry.ready:
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		default:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package adapters_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingServer responds with the number of requests it has received,
+// and with a 500 to those whose body mentions "fail".
+func countingServer() (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "fail") {
+			w.WriteHeader(500)
+		}
+		w.Write([]byte{byte('0' + n)})
+	}))
+	return server, &requests
+}
+
+func duration(d time.Duration) *models.Duration {
+	md := models.Duration(d)
+	return &md
+}
+
+func TestHttpGet_Perform_CacheTTL(t *testing.T) {
+	t.Parallel()
+	server, requests := countingServer()
+	defer server.Close()
+	url := cltest.MustParseWebURL(server.URL)
+
+	cached := adapters.HttpGet{URL: url, CacheTTL: duration(time.Minute)}
+	for i := 0; i < 2; i++ {
+		val, err := cached.Perform(models.RunResult{}, nil).Value()
+		assert.Nil(t, err)
+		assert.Equal(t, "1", val)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+
+	uncached := adapters.HttpGet{URL: url, CacheTTL: duration(0)}
+	val, err := uncached.Perform(models.RunResult{}, nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "2", val)
+}
+
+func TestHttpGet_Perform_AdapterCacheTTL(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+	store.Config.AdapterCacheTTL = time.Minute
+	clock := cltest.UseSettableClock(store)
+	now := time.Now()
+	clock.SetTime(now)
+	server, requests := countingServer()
+	defer server.Close()
+
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(server.URL)}
+	hga.Perform(input, store)
+	val, err := hga.Perform(input, store).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "1", val)
+
+	clock.SetTime(now.Add(time.Minute))
+	val, err = hga.Perform(input, store).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "2", val, "the cached response expires after the TTL")
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestHttpPost_Perform_CacheTTL(t *testing.T) {
+	t.Parallel()
+	server, requests := countingServer()
+	defer server.Close()
+	hpa := adapters.HttpPost{URL: cltest.MustParseWebURL(server.URL), CacheTTL: duration(time.Minute)}
+
+	assert.True(t, hpa.Perform(cltest.RunResultWithValue("fail"), nil).HasError())
+	assert.True(t, hpa.Perform(cltest.RunResultWithValue("fail"), nil).HasError())
+
+	val, err := hpa.Perform(cltest.RunResultWithValue("a"), nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "3", val, "errors are not cached")
+	val, err = hpa.Perform(cltest.RunResultWithValue("b"), nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "4", val, "requests with different bodies are cached apart")
+	val, err = hpa.Perform(cltest.RunResultWithValue("a"), nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "3", val)
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
@@ -1,7 +1,13 @@
 package adapters_test
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/adapters"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
@@ -102,3 +108,58 @@ func TestHttpPost_Perform(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpGet_PerformContext_Cancelled(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(server.URL)}
+	result := adapters.PerformContext(ctx, &hga, models.RunResult{}, nil)
+	assert.True(t, result.HasError())
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestHttpGet_Perform_Gzip(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept-Encoding"), "gzip")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		gz.Write([]byte(`{"price": 100}`))
+	}))
+	defer server.Close()
+
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(server.URL)}
+	result := hga.Perform(cltest.RunResultWithValue("inputValue"), nil)
+
+	assert.Nil(t, result.GetError())
+	val, err := result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"price": 100}`, val)
+}
+
+func TestHttpGet_Perform_TLSMinVersion(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS11}
+	server.StartTLS()
+	defer server.Close()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.AdapterTLSMinVersion = tls.VersionTLS12
+
+	hga := adapters.HttpGet{URL: cltest.MustParseWebURL(server.URL)}
+	result := hga.Perform(cltest.SavedRunResult(t, store), store)
+
+	assert.True(t, result.HasError())
+	assert.Contains(t, result.Error(), "protocol version")
+}
@@ -0,0 +1,44 @@
+package adapters
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// jobIDFor returns the ID of the job that the input's run belongs to.
+// Adapters keep the state they carry between runs under their job's ID,
+// so a run that has not been saved, and so has no job, is an error rather
+// than sharing the state of every other such run.
+func jobIDFor(input models.RunResult, store *store.Store) (string, error) {
+	jr, err := store.FindJobRun(input.JobRunID)
+	if err != nil {
+		return "", fmt.Errorf("run %v was not found", input.JobRunID)
+	}
+	return jr.JobID, nil
+}
+
+// jobStateLocks keeps the runs of a job that read some of its state, act
+// on it, and then write it, such as a paginated API's cursor, from doing so
+// at once. What they do in between, such as sending requests, cannot be
+// done in a bolt transaction.
+var jobStateLocks = struct {
+	sync.Mutex
+	keys map[string]*sync.Mutex
+}{keys: map[string]*sync.Mutex{}}
+
+// lockJobState locks the state under the key, and returns the function
+// that unlocks it.
+func lockJobState(key string) func() {
+	jobStateLocks.Lock()
+	lock, ok := jobStateLocks.keys[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		jobStateLocks.keys[key] = lock
+	}
+	jobStateLocks.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
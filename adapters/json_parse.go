@@ -11,9 +11,11 @@ import (
 )
 
 // JsonParse holds a path to the desired field in a JSON object,
-// made up of an array of strings.
+// made up of an array of strings, and an optional NumberFormat
+// used to normalize the field the same way as the ParseNum adapter.
 type JsonParse struct {
-	Path []string `json:"path"`
+	Path         []string `json:"path"`
+	NumberFormat string   `json:"numberFormat"`
 }
 
 // Perform returns the value associated to the desired field for a
@@ -57,6 +59,11 @@ func (jpa *JsonParse) Perform(input models.RunResult, _ *store.Store) models.Run
 	if err != nil {
 		return input.WithError(err)
 	}
+	if jpa.NumberFormat != "" {
+		if result, err = normalizeNumber(result, jpa.NumberFormat); err != nil {
+			return input.WithError(err)
+		}
+	}
 	return input.WithValue(result)
 }
 
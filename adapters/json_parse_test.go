@@ -46,3 +46,12 @@ func TestJsonParse_Perform(t *testing.T) {
 		})
 	}
 }
+
+func TestJsonParse_Perform_NumberFormat(t *testing.T) {
+	t.Parallel()
+	input := cltest.RunResultWithValue(`{"last":"11.779,99"}`)
+	adapter := adapters.JsonParse{Path: []string{"last"}, NumberFormat: "comma"}
+	result := adapter.Perform(input, nil)
+	assert.Nil(t, result.GetError())
+	assert.Equal(t, `{"value":"11779.99"}`, result.Data.String())
+}
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+// Metric holds the Name of a gauge or counter, its Kind, the Path to the
+// number in the run's data to record, "value" by default, and the Labels
+// to record it with.
+type Metric struct {
+	Name   string            `json:"name"`
+	Kind   string            `json:"kind"`
+	Path   string            `json:"path"`
+	Labels map[string]string `json:"labels"`
+}
+
+func (m *Metric) validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("Metric: name is required")
+	}
+	if m.Kind != "" && m.Kind != "gauge" && m.Kind != "counter" {
+		return fmt.Errorf("Metric: kind must be gauge or counter, got %q", m.Kind)
+	}
+	return nil
+}
+
+// Perform records the number at the adapter's path in the node's metrics,
+// as chainlink_custom_<name>, and returns the input unchanged. Gauges,
+// the default kind, are set to the number, and counters are increased by
+// it.
+func (m *Metric) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	path := m.Path
+	if path == "" {
+		path = "value"
+	}
+	val, err := input.Get(path)
+	if err != nil {
+		return input.WithError(err)
+	}
+	if val.Type != gjson.Number && val.Type != gjson.String {
+		return input.WithError(fmt.Errorf("Metric: %v is not a number: %v", path, val.Raw))
+	}
+	f, err := strconv.ParseFloat(val.String(), 64)
+	if err != nil {
+		return input.WithError(fmt.Errorf("Metric: %v is not a number: %v", path, val.String()))
+	}
+
+	kind := m.Kind
+	if kind == "" {
+		kind = "gauge"
+	}
+	if err := metrics.RecordCustom(m.Name, kind, m.Labels, f); err != nil {
+		return input.WithError(err)
+	}
+	return input
+}
@@ -0,0 +1,55 @@
+package adapters_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetric_Perform(t *testing.T) {
+	t.Parallel()
+
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":"1.25","details":{"volume":7}}`)}
+
+	gauge := adapters.Metric{Name: "adapter_test_price", Labels: map[string]string{"pair": "ETH/USD"}}
+	result := gauge.Perform(input, nil)
+	assert.Nil(t, result.GetError())
+	assert.Equal(t, input, result)
+
+	counter := adapters.Metric{Name: "adapter_test_volume", Kind: "counter", Path: "details.volume"}
+	assert.Nil(t, counter.Perform(input, nil).GetError())
+	assert.Nil(t, counter.Perform(input, nil).GetError())
+
+	var buf bytes.Buffer
+	assert.Nil(t, metrics.Write(&buf))
+	assert.Contains(t, buf.String(), `chainlink_custom_adapter_test_price{pair="ETH/USD"} 1.25`+"\n")
+	assert.Contains(t, buf.String(), "chainlink_custom_adapter_test_volume 14\n")
+}
+
+func TestMetric_Perform_NotANumber(t *testing.T) {
+	t.Parallel()
+
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":{"foo":"bar"}}`)}
+	adapter := adapters.Metric{Name: "adapter_test_invalid"}
+	result := adapter.Perform(input, nil)
+	assert.NotNil(t, result.GetError())
+}
+
+func TestMetric_Validate(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	_, err := adapters.For(models.Task{Type: "Metric", Params: cltest.JSONFromString(`{"name":"a"}`)}, store)
+	assert.Nil(t, err)
+	_, err = adapters.For(models.Task{Type: "Metric", Params: cltest.JSONFromString(`{}`)}, store)
+	assert.NotNil(t, err)
+	_, err = adapters.For(models.Task{Type: "Metric", Params: cltest.JSONFromString(`{"name":"a","kind":"summary"}`)}, store)
+	assert.NotNil(t, err)
+}
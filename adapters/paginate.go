@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/asdine/storm"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+const defaultMaxPages = 10
+
+// Paginate holds the URL of a paginated API, the query parameter that the
+// cursor is sent in, and the paths in each page's JSON to its items and to
+// the cursor for the next page. Its DomainLists limit the hosts it, or
+// redirects from the URL, can send requests to.
+type Paginate struct {
+	URL         models.WebURL `json:"url"`
+	CursorParam string        `json:"cursorParam"`
+	CursorPath  string        `json:"cursorPath"`
+	ItemsPath   string        `json:"itemsPath"`
+	MaxPages    int           `json:"maxPages"`
+	models.DomainLists
+}
+
+// Perform fetches pages from the URL, starting from the cursor saved by the
+// job's previous run, until a page has no items or no next cursor, or
+// MaxPages (default 10) have been fetched. The items of every page are
+// returned as a JSON array in the "value" field of the result, and the
+// cursor is saved for the next run. When the last page has no next cursor,
+// the cursor for that page is saved, so the next run starts by fetching it
+// again to pick up any new items. Runs of the same job wait for each other,
+// so that each picks up where the last left off.
+func (p *Paginate) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	key, err := p.cursorKey(input, store)
+	if err != nil {
+		return input.WithError(err)
+	}
+	defer lockJobState(key)()
+	var cursor string
+	if err := store.GetKV(key, &cursor); err != nil && err != storm.ErrNotFound {
+		return input.WithError(err)
+	}
+
+	maxPages := p.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	items := []json.RawMessage{}
+	for page := 0; page < maxPages; page++ {
+		body, err := p.fetch(cursor, input, store)
+		if err != nil {
+			return input.WithError(err)
+		}
+
+		pageItems := gjson.GetBytes(body, p.ItemsPath).Array()
+		for _, item := range pageItems {
+			items = append(items, json.RawMessage(item.Raw))
+		}
+		next := gjson.GetBytes(body, p.CursorPath).String()
+		if len(pageItems) == 0 || next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if err := store.SetKV(key, cursor); err != nil {
+		return input.WithError(err)
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return input.WithError(err)
+	}
+	return input.WithValue(string(b))
+}
+
+func (p *Paginate) fetch(cursor string, input models.RunResult, store *store.Store) ([]byte, error) {
+	u := *p.URL.URL
+	if cursor != "" {
+		param := p.CursorParam
+		if param == "" {
+			param = "cursor"
+		}
+		q := u.Query()
+		q.Set(param, cursor)
+		u.RawQuery = q.Encode()
+	}
+
+	if err := checkEgress(context.Background(), &u, p.DomainLists, input, store); err != nil {
+		return nil, err
+	}
+	response, err := egressClient(p.DomainLists, input, store).Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf("%v: %v", response.Status, string(body))
+	}
+	return body, nil
+}
+
+// cursorKey scopes the saved cursor to the run's job, so that jobs reading
+// the same URL keep their own place.
+func (p *Paginate) cursorKey(input models.RunResult, store *store.Store) (string, error) {
+	jobID, err := jobIDFor(input, store)
+	if err != nil {
+		return "", fmt.Errorf("Paginate: %v to keep its job's cursor", err)
+	}
+	return fmt.Sprintf("paginate/%v/%v", jobID, p.URL.String()), nil
+}
@@ -0,0 +1,103 @@
+package adapters_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate_Perform(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+
+	pages := map[string]string{
+		"":  `{"events":[1,2],"next":"b"}`,
+		"b": `{"events":[3],"next":"c"}`,
+		"c": `{"events":[],"next":""}`,
+	}
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after := r.URL.Query().Get("after")
+		requested = append(requested, after)
+		io.WriteString(w, pages[after])
+	}))
+	defer server.Close()
+
+	adapter := adapters.Paginate{
+		URL:         cltest.MustParseWebURL(server.URL),
+		CursorParam: "after",
+		CursorPath:  "next",
+		ItemsPath:   "events",
+	}
+
+	result := adapter.Perform(input, store)
+	assert.Nil(t, result.GetError())
+	val, err := result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `[1,2,3]`, val)
+	assert.Equal(t, []string{"", "b", "c"}, requested)
+
+	pages["c"] = `{"events":[4],"next":""}`
+	requested = nil
+	result = adapter.Perform(input, store)
+	assert.Nil(t, result.GetError())
+	val, err = result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `[4]`, val)
+	assert.Equal(t, []string{"c"}, requested)
+}
+
+func TestPaginate_Perform_MaxPagesAndErrors(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	input := cltest.SavedRunResult(t, store)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "broken" {
+			w.WriteHeader(503)
+			return
+		}
+		io.WriteString(w, `{"items":["x"],"next":"more"}`)
+	}))
+	defer server.Close()
+
+	adapter := adapters.Paginate{
+		URL:        cltest.MustParseWebURL(server.URL),
+		CursorPath: "next",
+		ItemsPath:  "items",
+		MaxPages:   2,
+	}
+	result := adapter.Perform(input, store)
+	val, err := result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `["x","x"]`, val)
+
+	var cursor string
+	run, err := store.FindJobRun(input.JobRunID)
+	assert.Nil(t, err)
+	key := "paginate/" + run.JobID + "/" + server.URL
+	assert.Nil(t, store.SetKV(key, "broken"))
+	result = adapter.Perform(input, store)
+	assert.True(t, result.HasError())
+	assert.Nil(t, store.GetKV(key, &cursor))
+	assert.Equal(t, "broken", cursor)
+}
+
+func TestPaginate_Perform_UnsavedRun(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	adapter := adapters.Paginate{URL: cltest.MustParseWebURL("https://example.com/items")}
+	result := adapter.Perform(models.RunResult{JobRunID: "unsaved"}, store)
+	assert.Contains(t, result.Error(), "run unsaved was not found", "unsaved runs would share one cursor")
+}
@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+const (
+	// MergeArray collects the values of a Parallel group's tasks into a
+	// JSON array, in the order the tasks were given.
+	MergeArray = "array"
+	// MergeObject collects the values of a Parallel group's tasks into a
+	// JSON object, keyed by each task's "name" param, or its index if it
+	// has none.
+	MergeObject = "object"
+)
+
+// notParallel are the core adapters whose tasks cannot be in a group, as
+// they send transactions or requests whose effects would stand even when
+// the group errors, or go pending, which groups do not support. Bridge
+// tasks cannot be grouped for the same reasons.
+var notParallel = map[string]bool{
+	"ethtx":    true,
+	"forward":  true,
+	"parallel": true,
+}
+
+// Parallel holds a group of tasks that are performed concurrently with the
+// same input, and how their values are merged.
+type Parallel struct {
+	Tasks []models.Task `json:"tasks"`
+	Merge string        `json:"merge"`
+}
+
+func (p *Parallel) validate(store *store.Store) error {
+	if p.Merge != "" && p.Merge != MergeArray && p.Merge != MergeObject {
+		return fmt.Errorf("Parallel: unknown merge %v", p.Merge)
+	}
+	for _, task := range p.Tasks {
+		if notParallel[strings.ToLower(task.Type)] {
+			return fmt.Errorf("Parallel: %v tasks cannot be grouped", task.Type)
+		}
+		adapter, err := For(task, store)
+		if err != nil {
+			return fmt.Errorf("Parallel: %v", err)
+		}
+		if _, ok := adapter.(*Bridge); ok {
+			return fmt.Errorf("Parallel: bridge %v tasks cannot be grouped", task.Type)
+		}
+	}
+	return nil
+}
+
+// Perform runs every task in the group at the same time and returns their
+// merged values, encoded as JSON, as the "value" field of the result. The
+// group errors if any of its tasks errors or is pending.
+//
+// For example, if three HttpGet tasks each return a price, the default
+// array merge would return a value of `["100.1","100.3","99.9"]`.
+func (p *Parallel) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	performers := make([]Adapter, len(p.Tasks))
+	for i, task := range p.Tasks {
+		adapter, err := For(task, store)
+		if err != nil {
+			return input.WithError(err)
+		}
+		performers[i] = adapter
+	}
+
+	results := make([]models.RunResult, len(performers))
+	var wg sync.WaitGroup
+	wg.Add(len(performers))
+	for i, adapter := range performers {
+		go func(i int, adapter Adapter) {
+			defer wg.Done()
+			results[i] = adapter.Perform(input, store)
+		}(i, adapter)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result.HasError() {
+			return input.WithError(fmt.Errorf("Parallel task %v (%v): %v", i, p.Tasks[i].Type, result.Error()))
+		}
+		if result.Pending {
+			return input.WithError(fmt.Errorf("Parallel task %v (%v): pending tasks are not supported", i, p.Tasks[i].Type))
+		}
+	}
+
+	b, err := json.Marshal(p.merge(results))
+	if err != nil {
+		return input.WithError(err)
+	}
+	return input.WithValue(string(b))
+}
+
+func (p *Parallel) merge(results []models.RunResult) interface{} {
+	if p.Merge == MergeObject {
+		merged := map[string]interface{}{}
+		for i, result := range results {
+			key := strconv.Itoa(i)
+			if name := p.Tasks[i].Params.Get("name"); name.Exists() {
+				key = name.String()
+			}
+			merged[key] = result.Data.Get("value").Value()
+		}
+		return merged
+	}
+
+	merged := make([]interface{}, len(results))
+	for i, result := range results {
+		merged[i] = result.Data.Get("value").Value()
+	}
+	return merged
+}
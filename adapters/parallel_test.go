@@ -0,0 +1,96 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallel_Perform(t *testing.T) {
+	t.Parallel()
+
+	first, cleanupFirst := cltest.NewHTTPMockServer(t, 200, "GET", "100.1")
+	defer cleanupFirst()
+	second, cleanupSecond := cltest.NewHTTPMockServer(t, 200, "GET", "99.9")
+	defer cleanupSecond()
+
+	cases := []struct {
+		name  string
+		merge string
+		want  string
+	}{
+		{"default merge", "", `["100.1","99.9"]`},
+		{"array merge", "array", `["100.1","99.9"]`},
+		{"object merge", "object", `{"0":"100.1","second":"99.9"}`},
+	}
+
+	for _, test := range cases {
+		spec := fmt.Sprintf(`{"type":"parallel","merge":"%v","tasks":[
+			{"type":"httpget","url":"%v"},
+			{"type":"httpget","url":"%v","name":"second"}
+		]}`, test.merge, first.URL, second.URL)
+		var task models.Task
+		assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+
+		adapter, err := adapters.For(task, nil)
+		assert.Nil(t, err, test.name)
+		result := adapter.Perform(models.RunResult{}, nil)
+		assert.False(t, result.HasError(), test.name)
+
+		val, err := result.Value()
+		assert.Nil(t, err, test.name)
+		assert.JSONEq(t, test.want, val, test.name)
+	}
+}
+
+func TestParallel_Perform_Error(t *testing.T) {
+	t.Parallel()
+
+	ok, cleanupOk := cltest.NewHTTPMockServer(t, 200, "GET", "100.1")
+	defer cleanupOk()
+	bad, cleanupBad := cltest.NewHTTPMockServer(t, 500, "GET", "down")
+	defer cleanupBad()
+
+	p := adapters.Parallel{Tasks: []models.Task{
+		cltest.NewTask("httpget", fmt.Sprintf(`{"url":"%v"}`, ok.URL)),
+		cltest.NewTask("httpget", fmt.Sprintf(`{"url":"%v"}`, bad.URL)),
+	}}
+	result := p.Perform(models.RunResult{}, nil)
+	assert.True(t, result.HasError())
+	assert.Contains(t, result.Error(), "Parallel task 1 (httpget)")
+}
+
+func TestParallel_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		spec    string
+		errored bool
+	}{
+		{"valid", `{"type":"parallel","tasks":[{"type":"noop"}]}`, false},
+		{"unknown merge", `{"type":"parallel","merge":"median","tasks":[{"type":"noop"}]}`, true},
+		{"unsupported adapter", `{"type":"parallel","tasks":[{"type":"nonExistent"}]}`, true},
+		{"ethtx", `{"type":"parallel","tasks":[{"type":"noop"},{"type":"EthTx"}]}`, true},
+		{"forward", `{"type":"parallel","tasks":[{"type":"forward","tasks":[{"type":"noop"}]}]}`, true},
+		{"nested group", `{"type":"parallel","tasks":[{"type":"parallel","tasks":[{"type":"noop"}]}]}`, true},
+		{"bridge", `{"type":"parallel","tasks":[{"type":"randomNumber"}]}`, true},
+	}
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	bt := cltest.NewBridgeType("randomNumber")
+	assert.Nil(t, store.Save(&bt))
+
+	for _, test := range cases {
+		var task models.Task
+		assert.Nil(t, json.Unmarshal([]byte(test.spec), &task))
+		_, err := adapters.For(task, store)
+		assert.Equal(t, test.errored, err != nil, test.name)
+	}
+}
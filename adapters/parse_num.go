@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+const (
+	// NumberFormatAuto guesses the decimal separator from the input, and
+	// errors if it cannot be told.
+	NumberFormatAuto = "auto"
+	// NumberFormatPoint reads numbers like "1,234.56".
+	NumberFormatPoint = "point"
+	// NumberFormatComma reads numbers like "1.234,56".
+	NumberFormatComma = "comma"
+)
+
+// ParseNum holds a hint for the format of the numbers it reads.
+type ParseNum struct {
+	Format string `json:"format"`
+}
+
+// Perform normalizes the input's "value" field to a plain decimal, so that
+// numbers such as "1.234,56", "1,234.56", and "1.23456e3" can be passed on
+// to Multiply or the Eth formatting adapters. The format defaults to auto.
+//
+// For example, with a format of "comma", "1.234,56" would return a value of
+// "1234.56".
+func (pn *ParseNum) Perform(input models.RunResult, _ *store.Store) models.RunResult {
+	val, err := input.Value()
+	if err != nil {
+		return input.WithError(err)
+	}
+	num, err := normalizeNumber(val, pn.Format)
+	if err != nil {
+		return input.WithError(err)
+	}
+	return input.WithValue(num)
+}
+
+func normalizeNumber(str string, format string) (string, error) {
+	s := strings.TrimSpace(str)
+	s = strings.NewReplacer(" ", "", "_", "", "'", "").Replace(s)
+
+	if !strings.ContainsAny(s, "eE") {
+		var err error
+		if s, err = normalizeSeparators(s, format); err != nil {
+			return "", err
+		}
+	}
+
+	f, ok := new(big.Float).SetPrec(256).SetString(s)
+	if !ok {
+		return "", fmt.Errorf("cannot parse %v as a number", str)
+	}
+	return f.Text('f', -1), nil
+}
+
+func normalizeSeparators(s string, format string) (string, error) {
+	if format == "" || format == NumberFormatAuto {
+		var err error
+		if format, err = guessNumberFormat(s); err != nil {
+			return "", err
+		}
+	}
+	switch format {
+	case NumberFormatPoint:
+		return strings.Replace(s, ",", "", -1), nil
+	case NumberFormatComma:
+		s = strings.Replace(s, ".", "", -1)
+		return strings.Replace(s, ",", ".", -1), nil
+	}
+	return "", fmt.Errorf("unknown number format %v", format)
+}
+
+// guessNumberFormat treats the last separator as the decimal separator,
+// unless it appears more than once, in which case it groups thousands. A
+// lone separator with one to three digits before it, other than a single
+// 0, and exactly three after it, such as in "1,234" or "12.345", could be
+// either, so the format must be given for it.
+func guessNumberFormat(s string) (string, error) {
+	lastComma := strings.LastIndex(s, ",")
+	lastPoint := strings.LastIndex(s, ".")
+	switch {
+	case lastComma < 0 && lastPoint < 0:
+		return NumberFormatPoint, nil
+	case lastComma < 0:
+		if strings.Count(s, ".") > 1 {
+			return NumberFormatComma, nil
+		}
+		if ambiguousSeparator(s, lastPoint) {
+			return "", ambiguousFormatError(s)
+		}
+		return NumberFormatPoint, nil
+	case lastPoint < 0:
+		if strings.Count(s, ",") > 1 {
+			return NumberFormatPoint, nil
+		}
+		if ambiguousSeparator(s, lastComma) {
+			return "", ambiguousFormatError(s)
+		}
+		return NumberFormatComma, nil
+	case lastComma > lastPoint:
+		return NumberFormatComma, nil
+	}
+	return NumberFormatPoint, nil
+}
+
+// ambiguousSeparator returns true if the only separator, at i, could group
+// the thousands of the number as well as separate its decimals.
+func ambiguousSeparator(s string, i int) bool {
+	whole := strings.TrimLeft(s[:i], "+-")
+	return len(s)-i-1 == 3 && len(whole) >= 1 && len(whole) <= 3 && whole != "0"
+}
+
+func ambiguousFormatError(s string) error {
+	return fmt.Errorf("%v could be a decimal or grouped thousands, set format to %v or %v", s, NumberFormatPoint, NumberFormatComma)
+}
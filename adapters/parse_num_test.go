@@ -0,0 +1,54 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNum_Perform(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		format      string
+		want        string
+		wantErrored bool
+	}{
+		{"plain", "1234.56", "", "1234.56", false},
+		{"auto point", "1,234.56", "", "1234.56", false},
+		{"auto comma", "1.234,56", "", "1234.56", false},
+		{"auto comma decimal only", "0,5", "", "0.5", false},
+		{"auto grouped points", "1.234.567", "", "1234567", false},
+		{"auto grouped commas", "1,234,567", "", "1234567", false},
+		{"auto lone comma before three digits", "1,234", "", "", true},
+		{"auto lone point before three digits", "-12.345", "", "", true},
+		{"auto zero before three digits", "0,125", "", "0.125", false},
+		{"auto lone point before four digits", "1234.567", "", "1234.567", false},
+		{"point", "1,234", "point", "1234", false},
+		{"comma", "1.234", "comma", "1234", false},
+		{"scientific", "1.23456e3", "", "1234.56", false},
+		{"negative scientific", "-5E-2", "", "-0.05", false},
+		{"spaces", " 1 234,5 ", "", "1234.5", false},
+		{"unknown format", "1,5", "roman", "", true},
+		{"not a number", "abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			input := cltest.RunResultWithValue(test.value)
+			adapter := adapters.ParseNum{Format: test.format}
+			result := adapter.Perform(input, nil)
+
+			assert.Equal(t, test.wantErrored, result.HasError())
+			if !test.wantErrored {
+				val, err := result.Value()
+				assert.Nil(t, err)
+				assert.Equal(t, test.want, val)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+// dryRunnable are the core adapters that only transform their input, so
+// that a previewed spec's tasks of these types can be performed against
+// sample input without sending requests, writing to the chain, or saving
+// anything.
+var dryRunnable = map[string]bool{
+	"jsonparse":  true,
+	"copy":       true,
+	"parsenum":   true,
+	"multiply":   true,
+	"aggregate":  true,
+	"ethbytes32": true,
+	"ethuint256": true,
+	"ethint256":  true,
+	"noop":       true,
+}
+
+// The statuses of a TaskPreview.
+const (
+	// PreviewCompleted is for a task that was dry run without error.
+	PreviewCompleted = "completed"
+	// PreviewErrored is for a task whose dry run errored.
+	PreviewErrored = "errored"
+	// PreviewSkipped is for a task whose onlyIf condition was not met by
+	// its input.
+	PreviewSkipped = "skipped"
+	// PreviewNotRun is for a task that was not dry run, with the reason.
+	PreviewNotRun = "not_run"
+)
+
+// SpecPreview is what a job spec would be created with: its validation
+// errors, the warnings CheckDataPaths, CheckUnknownParams, and
+// CheckAddressChecksums give about it, and, if it was dry run, how each of
+// its tasks fared.
+type SpecPreview struct {
+	Valid    bool            `json:"valid"`
+	Errors   ValidationError `json:"errors"`
+	Warnings []string        `json:"warnings"`
+	Tasks    []TaskPreview   `json:"tasks,omitempty"`
+}
+
+// TaskPreview is how a task of a dry run spec fared. Output is the data
+// the task ended with, which is passed into the next task.
+type TaskPreview struct {
+	Index  int          `json:"index"`
+	Type   string       `json:"type"`
+	Status string       `json:"status"`
+	Reason string       `json:"reason,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	Output *models.JSON `json:"output,omitempty"`
+}
+
+// Preview validates the job spec as it would be when created, and checks
+// the checksums of the addresses in it. If sample input is given, the
+// spec's tasks are dry run against it with DryRun. Nothing is saved.
+func Preview(spec []byte, sampleInput *models.JSON, store *store.Store) SpecPreview {
+	preview := SpecPreview{Errors: ValidationError{}, Warnings: []string{}}
+	job := models.NewJob()
+	if err := json.Unmarshal(spec, &job); err != nil {
+		preview.Errors = append(preview.Errors, FieldError{Reason: err.Error()})
+		return preview
+	}
+
+	if err := Validate(job, store); err != nil {
+		if ve, ok := err.(ValidationError); ok {
+			preview.Errors = append(preview.Errors, ve...)
+		} else {
+			preview.Errors = append(preview.Errors, FieldError{Reason: err.Error()})
+		}
+	}
+	checksumErrors, checksumWarnings := CheckAddressChecksums(spec)
+	preview.Errors = append(preview.Errors, checksumErrors...)
+	preview.Warnings = append(preview.Warnings, CheckDataPaths(job)...)
+	preview.Warnings = append(preview.Warnings, CheckUnknownParams(job)...)
+	preview.Warnings = append(preview.Warnings, checksumWarnings...)
+	preview.Valid = len(preview.Errors) == 0
+
+	if sampleInput != nil && preview.Valid {
+		preview.Tasks = DryRun(job, *sampleInput, store)
+	}
+	return preview
+}
+
+// DryRun performs the job's tasks in order against the sample input, as
+// a run of the job would, but only up to the first task that is not one
+// of the side effect free core adapters, or whose params are templated,
+// since what it would return is unknown. Tasks after one that errors are
+// not run either. onlyIf conditions are checked against the task's input,
+// with deviation checks always met as on a job's first run.
+func DryRun(job models.Job, sampleInput models.JSON, store *store.Store) []TaskPreview {
+	previews := make([]TaskPreview, len(job.Tasks))
+	input := models.RunResult{Data: sampleInput}
+	stopped := ""
+	for i, task := range job.Tasks {
+		p := TaskPreview{Index: i, Type: task.Type, Status: PreviewNotRun}
+		switch {
+		case stopped != "":
+			p.Reason = stopped
+		case !dryRunnable[task.Type]:
+			p.Reason = fmt.Sprintf("%v tasks are not dry run, as they need outside services or have side effects", task.Type)
+			stopped = fmt.Sprintf("it follows task %v, which was not run", i)
+		case strings.Contains(task.Params.Raw, "{{"):
+			p.Reason = "templated params are only filled in when the job runs"
+			stopped = fmt.Sprintf("it follows task %v, which was not run", i)
+		default:
+			input, p = dryRunTask(task, input, p, store)
+			if p.Status == PreviewErrored {
+				stopped = fmt.Sprintf("it follows task %v, which errored", i)
+			}
+		}
+		previews[i] = p
+	}
+	return previews
+}
+
+func dryRunTask(task models.Task, input models.RunResult, p TaskPreview, store *store.Store) (models.RunResult, TaskPreview) {
+	if task.OnlyIf != nil {
+		met, err := task.OnlyIf.Met(input.Data, models.JSON{})
+		if err != nil {
+			p.Status, p.Error = PreviewErrored, err.Error()
+			return input, p
+		} else if !met {
+			p.Status, p.Output = PreviewSkipped, &input.Data
+			return input, p
+		}
+	}
+
+	adapter, err := For(task, store)
+	if err != nil {
+		p.Status, p.Error = PreviewErrored, err.Error()
+		return input, p
+	}
+	result := adapter.Perform(input, store)
+	if result.HasError() {
+		p.Status, p.Error = PreviewErrored, result.Error()
+		return input, p
+	}
+	p.Status, p.Output = PreviewCompleted, &result.Data
+	return result, p
+}
+
+// CheckAddressChecksums looks through a job spec for addresses, strings of
+// 0x and 40 hex digits, that are not written with their EIP-55 checksum.
+// Mixed case addresses whose checksum does not match are most likely
+// mistyped, so they are returned as errors. Addresses all in lower or
+// upper case have no checksum to check, so they are only warned about.
+func CheckAddressChecksums(spec []byte) (ValidationError, []string) {
+	ve := ValidationError{}
+	warnings := []string{}
+	var walk func(value gjson.Result, field string)
+	walk = func(value gjson.Result, field string) {
+		switch {
+		case value.IsArray():
+			for i, elem := range value.Array() {
+				walk(elem, fmt.Sprintf("%v[%d]", field, i))
+			}
+		case value.IsObject():
+			value.ForEach(func(key, elem gjson.Result) bool {
+				if field == "" {
+					walk(elem, key.String())
+				} else {
+					walk(elem, field+"."+key.String())
+				}
+				return true
+			})
+		case value.Type == gjson.String:
+			s := value.String()
+			if len(s) != 42 || !strings.HasPrefix(s, "0x") || !common.IsHexAddress(s) {
+				return
+			}
+			checksummed := common.HexToAddress(s).Hex()
+			if s == checksummed {
+				return
+			}
+			digits := s[2:]
+			if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+				warnings = append(warnings, fmt.Sprintf("%v: address %v has no checksum, expected %v", field, s, checksummed))
+			} else {
+				ve = append(ve, FieldError{Field: field, Reason: fmt.Sprintf("address %v has an invalid checksum, expected %v", s, checksummed)})
+			}
+		}
+	}
+	walk(gjson.ParseBytes(spec), "")
+	return ve, warnings
+}
@@ -0,0 +1,105 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAddressChecksums(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		address      string
+		wantErrors   int
+		wantWarnings int
+	}{
+		{"checksummed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 0, 0},
+		{"lower case", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", 0, 1},
+		{"upper case", "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", 0, 1},
+		{"mistyped", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", 1, 0},
+		{"not an address", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", 0, 0},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			spec := `{"initiators":[{"type":"runlog","address":"` + test.address + `"}],"tasks":[{"type":"noop"}]}`
+			ve, warnings := adapters.CheckAddressChecksums([]byte(spec))
+			assert.Equal(t, test.wantErrors, len(ve))
+			assert.Equal(t, test.wantWarnings, len(warnings))
+			if len(ve) > 0 {
+				assert.Equal(t, "initiators[0].address", ve[0].Field)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	spec := `{"initiators":[{"type":"web"}],"tasks":[
+		{"type":"NoSuchAdapter"},
+		{"type":"ethtx","address":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD","functionSelector":"0x12345678"}
+	]}`
+	preview := adapters.Preview([]byte(spec), nil, store)
+	assert.False(t, preview.Valid)
+	fields := []string{}
+	for _, fe := range preview.Errors {
+		fields = append(fields, fe.Field)
+	}
+	assert.Equal(t, []string{"tasks[0]", "tasks[1].address"}, fields)
+	assert.Nil(t, preview.Tasks)
+
+	preview = adapters.Preview([]byte(`{"initiators":`), nil, store)
+	assert.False(t, preview.Valid)
+	assert.Equal(t, 1, len(preview.Errors))
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	assert.Nil(t, json.Unmarshal([]byte(`{"initiators":[{"type":"web"}],"tasks":[
+		{"type":"jsonparse","path":["last"]},
+		{"type":"multiply","times":100},
+		{"type":"noop","onlyIf":{"path":"value","operator":"eq","value":"0"}},
+		{"type":"ethtx"},
+		{"type":"ethuint256"}
+	]}`), &j))
+	sample := cltest.JSONFromString(`{"value":"{\"last\":\"1.23\"}"}`)
+
+	previews := adapters.DryRun(j, sample, store)
+	statuses := []string{}
+	for _, p := range previews {
+		statuses = append(statuses, p.Status)
+	}
+	assert.Equal(t, []string{
+		adapters.PreviewCompleted,
+		adapters.PreviewCompleted,
+		adapters.PreviewSkipped,
+		adapters.PreviewNotRun,
+		adapters.PreviewNotRun,
+	}, statuses)
+	assert.Equal(t, "123", previews[1].Output.Get("value").String())
+	assert.Contains(t, previews[3].Reason, "ethtx tasks are not dry run")
+	assert.Contains(t, previews[4].Reason, "task 3")
+
+	j = models.NewJob()
+	assert.Nil(t, json.Unmarshal([]byte(`{"initiators":[{"type":"web"}],"tasks":[{"type":"multiply","times":100},{"type":"noop"}]}`), &j))
+	previews = adapters.DryRun(j, cltest.JSONFromString(`{"value":"not a number"}`), store)
+	assert.Equal(t, adapters.PreviewErrored, previews[0].Status)
+	assert.NotEmpty(t, previews[0].Error)
+	assert.Equal(t, adapters.PreviewNotRun, previews[1].Status)
+	assert.Contains(t, previews[1].Reason, "errored")
+}
@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
+)
+
+// Random holds the Seed to make a verifiable random value from. When it is
+// empty, the seed is read from the "seed" field of the run's data, such as
+// one decoded from a run log. The seed should be chosen by the requester,
+// and not be known in advance to the node.
+type Random struct {
+	Seed string `json:"seed"`
+}
+
+// Perform makes a random value from the seed with the node's VRF key, and
+// returns it as a hex uint256 in the "value" field of the result. The
+// proof that it was made from the seed with the node's key is returned in
+// the "proof" field, as bytes laid out for contracts to verify, and the
+// key's public key in "publicKey".
+func (r *Random) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	seed, err := r.seed(input)
+	if err != nil {
+		return input.WithError(err)
+	}
+	key, err := store.VRFKey()
+	if err != nil {
+		return input.WithError(err)
+	}
+	proof, err := key.Prove(seed)
+	if err != nil {
+		return input.WithError(err)
+	}
+
+	pk := key.PublicKey()
+	data, err := input.Data.Add("proof", hexutil.Encode(proof.Bytes()))
+	if err != nil {
+		return input.WithError(err)
+	}
+	data, err = data.Add("publicKey", fmt.Sprintf("0x%064x%064x", pk.X, pk.Y))
+	if err != nil {
+		return input.WithError(err)
+	}
+	input.Data = data
+	return input.WithValue(fmt.Sprintf("0x%064x", proof.Randomness()))
+}
+
+// seed returns the task's seed, or else the run's, read as a decimal or
+// hex number.
+func (r *Random) seed(input models.RunResult) (*big.Int, error) {
+	s := r.Seed
+	if s == "" {
+		s = input.Data.Get("seed").String()
+	}
+	if s == "" {
+		return nil, fmt.Errorf("Random: no seed given in the task or the run's data")
+	}
+	seed, ok := new(big.Int), false
+	if utils.RemoveHexPrefix(s) != s {
+		seed, ok = seed.SetString(utils.RemoveHexPrefix(s), 16)
+	} else {
+		seed, ok = seed.SetString(s, 10)
+	}
+	if !ok || seed.Sign() < 0 || seed.BitLen() > 256 {
+		return nil, fmt.Errorf("Random: seed %v is not a uint256", s)
+	}
+	return seed, nil
+}
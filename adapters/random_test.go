@@ -0,0 +1,67 @@
+package adapters_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	strpkg "github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandom_Perform(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	locked := (&adapters.Random{Seed: "42"}).Perform(models.RunResult{}, store)
+	assert.True(t, locked.HasError(), "the key is unlocked when the node starts")
+	assert.Nil(t, store.UnlockVRFKey(cltest.Password))
+
+	input := models.RunResult{Data: cltest.JSONFromString(`{"seed":"0x2a"}`)}
+	output := (&adapters.Random{}).Perform(input, store)
+	assert.Nil(t, output.GetError())
+
+	proofBytes, err := hexutil.Decode(output.Data.Get("proof").String())
+	assert.Nil(t, err)
+	proof, err := strpkg.ParseVRFProof(proofBytes)
+	assert.Nil(t, err)
+	assert.Nil(t, proof.Verify())
+	assert.Equal(t, "42", proof.Seed.String())
+	val, err := output.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("0x%064x", proof.Randomness()), val)
+
+	key, err := store.VRFKey()
+	assert.Nil(t, err)
+	pk := key.PublicKey()
+	assert.Equal(t, fmt.Sprintf("0x%064x%064x", pk.X, pk.Y), output.Data.Get("publicKey").String())
+
+	same := (&adapters.Random{Seed: "42"}).Perform(models.RunResult{}, store)
+	assert.Equal(t, val, same.Data.Get("value").String(), "a task's seed is read as decimal")
+}
+
+func TestRandom_Perform_InvalidSeed(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		seed string
+	}{
+		{"missing", ""},
+		{"not a number", "lucky"},
+		{"negative", "-1"},
+		{"too large", "0x1" + fmt.Sprintf("%064x", 0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := (&adapters.Random{Seed: test.seed}).Perform(models.RunResult{}, store)
+			assert.True(t, output.HasError())
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+// paramType is the JSON type a task param must have, described as it is
+// written in errors.
+type paramType string
+
+const (
+	paramString  paramType = "a string"
+	paramNumber  paramType = "a number"
+	paramInteger paramType = "an integer"
+	paramBool    paramType = "a boolean"
+	paramArray   paramType = "an array"
+	paramObject  paramType = "an object"
+)
+
+// paramSpec is a param a core adapter takes, and whether version 1 specs
+// must give it.
+type paramSpec struct {
+	typ      paramType
+	required bool
+}
+
+// taskParams are the params every task can have, read by the runner
+// rather than its adapter.
+var taskParams = map[string]paramSpec{
+	"type":       {typ: paramString},
+	"maxRetries": {typ: paramInteger},
+	"backoff":    {typ: paramString},
+	"retryable":  {typ: paramArray},
+	"onlyIf":     {typ: paramObject},
+	"onError":    {typ: paramArray},
+	"timeout":    {typ: paramString},
+}
+
+// domainListParams are the params of the adapters embedding
+// models.DomainLists.
+var domainListParams = map[string]paramSpec{
+	"allowedDomains": {typ: paramArray},
+	"deniedDomains":  {typ: paramArray},
+}
+
+// adapterParams are the params each core adapter takes, on top of
+// taskParams, as its struct unmarshals them. Bridges and enclave tasks
+// take any params, so they have none here.
+var adapterParams = map[string]map[string]paramSpec{
+	"httpget": withDomainLists(map[string]paramSpec{
+		"url":      {typ: paramString, required: true},
+		"cacheTTL": {typ: paramString},
+		"auth":     {typ: paramObject},
+	}),
+	"httppost": withDomainLists(map[string]paramSpec{
+		"url":      {typ: paramString, required: true},
+		"cacheTTL": {typ: paramString},
+		"auth":     {typ: paramObject},
+	}),
+	"paginate": withDomainLists(map[string]paramSpec{
+		"url":         {typ: paramString, required: true},
+		"cursorParam": {typ: paramString},
+		"cursorPath":  {typ: paramString},
+		"itemsPath":   {typ: paramString},
+		"maxPages":    {typ: paramInteger},
+	}),
+	"jsonparse": {
+		"path":         {typ: paramArray, required: true},
+		"numberFormat": {typ: paramString},
+	},
+	"copy": {
+		"copyPath": {typ: paramArray, required: true},
+		"rename":   {typ: paramObject},
+	},
+	"parsenum": {
+		"format": {typ: paramString},
+	},
+	"ethbytes32": {},
+	"ethuint256": {},
+	"ethint256":  {},
+	"ethcall": {
+		"address":  {typ: paramString, required: true},
+		"function": {typ: paramString, required: true},
+		"args":     {typ: paramArray},
+		"returns":  {typ: paramString},
+	},
+	"ethtx": {
+		"address":               {typ: paramString, required: true},
+		"functionSelector":      {typ: paramString, required: true},
+		"dataPrefix":            {typ: paramString},
+		"heartbeat":             {typ: paramString},
+		"tolerance":             {typ: paramString},
+		"batchFunctionSelector": {typ: paramString},
+		"signFulfillment":       {typ: paramBool},
+	},
+	"multiply": {
+		"times": {typ: paramInteger, required: true},
+	},
+	"aggregate": {
+		"method":       {typ: paramString},
+		"maxDeviation": {typ: paramNumber},
+		"minResponses": {typ: paramInteger},
+	},
+	"twap": {
+		"window":     {typ: paramString, required: true},
+		"weight":     {typ: paramString},
+		"volumePath": {typ: paramString},
+	},
+	"random": {
+		"seed": {typ: paramString},
+	},
+	"metric": {
+		"name":   {typ: paramString, required: true},
+		"kind":   {typ: paramString},
+		"path":   {typ: paramString},
+		"labels": {typ: paramObject},
+	},
+	"parallel": {
+		"tasks": {typ: paramArray, required: true},
+		"merge": {typ: paramString},
+	},
+	"forward": {
+		"tasks": {typ: paramArray, required: true},
+	},
+	"noop":     {},
+	"nooppend": {},
+}
+
+func withDomainLists(params map[string]paramSpec) map[string]paramSpec {
+	for name, spec := range domainListParams {
+		params[name] = spec
+	}
+	return params
+}
+
+// paramFor returns the spec of the named param of a core adapter's task.
+func paramFor(taskType, name string) (paramSpec, bool) {
+	if spec, ok := taskParams[name]; ok {
+		return spec, true
+	}
+	spec, ok := adapterParams[taskType][name]
+	return spec, ok
+}
+
+// checkParams checks a task's params against its core adapter's schema,
+// returning an error for each param of the wrong type. Unknown params,
+// and, unless its run's data can supply them, missing required ones, are
+// only errors in specs of schema version 1 and above; the unknown params
+// of older specs are left to CheckUnknownParams to warn about.
+func checkParams(task models.Task, field string, version int, dataSupplied bool) ValidationError {
+	schema, ok := adapterParams[task.Type]
+	if !ok {
+		return nil
+	}
+	ve := ValidationError{}
+	task.Params.ForEach(func(key, value gjson.Result) bool {
+		name := key.String()
+		spec, ok := paramFor(task.Type, name)
+		if !ok {
+			if version >= 1 {
+				ve = append(ve, FieldError{Field: field + "." + name, Reason: unknownParamReason(field, task.Type, name)})
+			}
+		} else if !spec.typ.matches(value) {
+			ve = append(ve, FieldError{
+				Field:  field + "." + name,
+				Reason: fmt.Sprintf("%v (%v) param %v must be %v, got %v", field, task.Type, name, spec.typ, value.Raw),
+			})
+		}
+		return true
+	})
+	if version >= 1 && !dataSupplied {
+		for _, name := range sortedParams(schema) {
+			if schema[name].required && !task.Params.Get(name).Exists() {
+				ve = append(ve, FieldError{
+					Field:  field + "." + name,
+					Reason: fmt.Sprintf("%v (%v) is missing the required param %v", field, task.Type, name),
+				})
+			}
+		}
+	}
+	return ve
+}
+
+// CheckUnknownParams looks for params that the adapters of a version 0
+// job spec's tasks do not take, such as a misspelled path, which would
+// otherwise be ignored. Specs of later versions are refused for them
+// instead.
+func CheckUnknownParams(job models.Job) []string {
+	warnings := []string{}
+	if job.SchemaVersion >= 1 {
+		return warnings
+	}
+	for i, task := range job.Tasks {
+		if _, ok := adapterParams[task.Type]; !ok {
+			continue
+		}
+		task.Params.ForEach(func(key, _ gjson.Result) bool {
+			if _, ok := paramFor(task.Type, key.String()); !ok {
+				warnings = append(warnings, unknownParamReason(fmt.Sprintf("tasks[%d]", i), task.Type, key.String()))
+			}
+			return true
+		})
+	}
+	return warnings
+}
+
+func unknownParamReason(field, taskType, name string) string {
+	reason := fmt.Sprintf("%v (%v) has no param %v", field, taskType, name)
+	best, distance := "", 3
+	for _, known := range append(sortedParams(adapterParams[taskType]), sortedParams(taskParams)...) {
+		if d := levenshtein(strings.ToLower(name), strings.ToLower(known)); d < distance {
+			best, distance = known, d
+		}
+	}
+	if best != "" {
+		reason += fmt.Sprintf(", did you mean %v?", best)
+	}
+	return reason
+}
+
+func sortedParams(params map[string]paramSpec) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matches returns true if the value has the type. Nulls match any type,
+// as they unmarshal to the zero value, and templated strings match any
+// type, since they are only filled in when the task runs.
+func (t paramType) matches(value gjson.Result) bool {
+	if value.Type == gjson.Null || (value.Type == gjson.String && strings.Contains(value.Str, "{{")) {
+		return true
+	}
+	switch t {
+	case paramString:
+		return value.Type == gjson.String
+	case paramNumber:
+		return value.Type == gjson.Number
+	case paramInteger:
+		return value.Type == gjson.Number && !strings.ContainsAny(value.Raw, ".eE")
+	case paramBool:
+		return value.Type == gjson.True || value.Type == gjson.False
+	case paramArray:
+		return value.IsArray()
+	case paramObject:
+		return value.IsObject()
+	}
+	return true
+}
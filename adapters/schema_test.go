@@ -0,0 +1,109 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Schema(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	cases := []struct {
+		name       string
+		spec       string
+		wantFields []string
+	}{
+		{"valid",
+			`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"HttpGet","url":"https://example.com","maxRetries":2},{"type":"JsonParse","path":["last"]},{"type":"Multiply","times":100}]}`,
+			nil},
+		{"unsupported version",
+			`{"schemaVersion":2,"initiators":[{"type":"web"}],"tasks":[{"type":"NoOp"}]}`,
+			[]string{"schemaVersion"}},
+		{"wrong types in any version",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"NoOp"},{"type":"Multiply","times":"100"},{"type":"JsonParse","path":"last"}]}`,
+			[]string{"tasks[1].times", "tasks[2].path"}},
+		{"fractional integer",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"Multiply","times":1.5}]}`,
+			[]string{"tasks[0].times"}},
+		{"templated param",
+			`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"Multiply","times":"{{ .data.times }}"}]}`,
+			nil},
+		{"unknown param in version 0",
+			`{"initiators":[{"type":"web"}],"tasks":[{"type":"JsonParse","path":["last"],"pth":["last"]}]}`,
+			nil},
+		{"unknown param in version 1",
+			`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"JsonParse","path":["last"],"pth":["last"]}]}`,
+			[]string{"tasks[0].pth"}},
+		{"missing required param",
+			`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"HttpGet"}]}`,
+			[]string{"tasks[0].url"}},
+		{"required param supplied by runlog",
+			`{"schemaVersion":1,"initiators":[{"type":"runlog"}],"tasks":[{"type":"HttpGet"}]}`,
+			nil},
+		{"onError fallback",
+			`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"NoOp","onError":[{"type":"Multiply","tims":100}]}]}`,
+			[]string{"tasks[0].onError[0].tims", "tasks[0].onError[0].times"}},
+		{"bridges take any params",
+			`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"NoSuchAdapter","anything":1}]}`,
+			[]string{"tasks[0]"}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			j := models.NewJob()
+			assert.Nil(t, json.Unmarshal([]byte(test.spec), &j))
+			err := adapters.Validate(j, store)
+			if test.wantFields == nil {
+				assert.Nil(t, err)
+				return
+			}
+			ve, ok := err.(adapters.ValidationError)
+			assert.True(t, ok)
+			fields := []string{}
+			for _, fe := range ve {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, test.wantFields, fields)
+		})
+	}
+}
+
+func TestValidate_SchemaReasons(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	assert.Nil(t, json.Unmarshal([]byte(`{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[
+		{"type":"NoOp"},
+		{"type":"JsonParse","pth":["last"]},
+		{"type":"Multiply","times":"100"}
+	]}`), &j))
+	err := adapters.Validate(j, store)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "tasks[1] (jsonparse) has no param pth, did you mean path?")
+	assert.Contains(t, err.Error(), "tasks[1] (jsonparse) is missing the required param path")
+	assert.Contains(t, err.Error(), `tasks[2] (multiply) param times must be an integer, got "100"`)
+}
+
+func TestCheckUnknownParams(t *testing.T) {
+	t.Parallel()
+
+	j := models.NewJob()
+	assert.Nil(t, json.Unmarshal([]byte(`{"initiators":[{"type":"web"}],"tasks":[
+		{"type":"HttpGet","url":"https://example.com","allowedDomains":["example.com"]},
+		{"type":"JsonParse","path":["last"],"pth":["last"]},
+		{"type":"MyBridge","anything":1}
+	]}`), &j))
+	assert.Equal(t, []string{"tasks[1] (jsonparse) has no param pth, did you mean path?"}, adapters.CheckUnknownParams(j))
+
+	j.SchemaVersion = 1
+	assert.Equal(t, []string{}, adapters.CheckUnknownParams(j))
+}
@@ -0,0 +1,70 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// Tracer records what an adapter sends and receives for a task run
+// sampled for debugging, in full, so that it can be kept with the run.
+// Adapters find it in the context they are performed with. It is safe to
+// read while an adapter that outlived its task's timeout still records.
+type Tracer struct {
+	mutex sync.Mutex
+	trace models.TaskTrace
+}
+
+type tracerKey struct{}
+
+// WithTracer returns a copy of the context that adapters performed with
+// it record what they send and receive in the tracer.
+func WithTracer(ctx context.Context, tracer *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// tracerFrom returns the context's tracer, or nil if it has none. Recording
+// in a nil tracer does nothing.
+func tracerFrom(ctx context.Context) *Tracer {
+	tracer, _ := ctx.Value(tracerKey{}).(*Tracer)
+	return tracer
+}
+
+// Trace returns a copy of what has been recorded, or nil if nothing has.
+func (t *Tracer) Trace() *models.TaskTrace {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.trace.Requests) == 0 && t.trace.CallData == "" {
+		return nil
+	}
+	trace := t.trace
+	trace.Requests = append([]models.TracedRequest{}, t.trace.Requests...)
+	return &trace
+}
+
+func (t *Tracer) request(req models.TracedRequest, response []byte, err error) {
+	if t == nil {
+		return
+	}
+	req.Response = string(response)
+	if err != nil {
+		req.Error = err.Error()
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.trace.Requests = append(t.trace.Requests, req)
+}
+
+func (t *Tracer) callData(data []byte) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.trace.CallData = hexutil.Encode(data)
+}
@@ -0,0 +1,170 @@
+package adapters
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+const (
+	// WeightTime weights each observation by how long it was the latest
+	// price within the window.
+	WeightTime = "time"
+	// WeightVolume weights each observation by its traded volume.
+	WeightVolume = "volume"
+)
+
+// TWAP holds the Window to average over, whether observations are weighted
+// by time or volume, and, for volume weighting, the path to the volume in
+// the run's data.
+type TWAP struct {
+	Window     string `json:"window"`
+	Weight     string `json:"weight"`
+	VolumePath string `json:"volumePath"`
+}
+
+type observation struct {
+	Time   time.Time `json:"time"`
+	Price  string    `json:"price"`
+	Volume string    `json:"volume,omitempty"`
+}
+
+// Perform records the input's "value" field as the latest observation for
+// the job, and returns the average of the observations within the window
+// as the "value" field of the result. Observations are kept between runs,
+// so the average smooths over every run in the window.
+//
+// For example, with a window of "1h" and time weighting, a price of "100"
+// for the first 45 minutes of the hour and "120" for the last 15 would
+// return "105".
+func (tw *TWAP) Perform(input models.RunResult, store *store.Store) models.RunResult {
+	window, err := time.ParseDuration(tw.Window)
+	if err != nil || window <= 0 {
+		return input.WithError(fmt.Errorf("TWAP: invalid window %v", tw.Window))
+	}
+	if tw.Weight != "" && tw.Weight != WeightTime && tw.Weight != WeightVolume {
+		return input.WithError(fmt.Errorf("TWAP: unknown weight %v", tw.Weight))
+	}
+	latest, err := tw.observe(input, store)
+	if err != nil {
+		return input.WithError(err)
+	}
+	jobID, err := jobIDFor(input, store)
+	if err != nil {
+		return input.WithError(fmt.Errorf("TWAP: %v to keep its job's observations", err))
+	}
+
+	now := store.Clock.Now()
+	var obs []observation
+	err = store.UpdateKV(fmt.Sprintf("twap/%v", jobID), &obs, func() error {
+		obs = append(obs, latest)
+		sort.Slice(obs, func(i, j int) bool { return obs[i].Time.Before(obs[j].Time) })
+		obs = withinWindow(obs, now.Add(-window))
+		return nil
+	})
+	if err != nil {
+		return input.WithError(err)
+	}
+
+	var avg *big.Float
+	if tw.Weight == WeightVolume {
+		avg, err = volumeWeightedAverage(obs, now.Add(-window))
+	} else {
+		avg, err = timeWeightedAverage(obs, now.Add(-window), now)
+	}
+	if err != nil {
+		return input.WithError(err)
+	}
+	return input.WithValue(avg.Text('f', -1))
+}
+
+// observe returns the observation of the input's price, and volume if
+// weighted by it.
+func (tw *TWAP) observe(input models.RunResult, store *store.Store) (observation, error) {
+	price, err := input.Value()
+	if err != nil {
+		return observation{}, err
+	}
+	if _, ok := new(big.Float).SetString(price); !ok {
+		return observation{}, fmt.Errorf("TWAP: %v is not a number", price)
+	}
+	latest := observation{Time: store.Clock.Now(), Price: price}
+	if tw.Weight == WeightVolume {
+		path := tw.VolumePath
+		if path == "" {
+			path = "volume"
+		}
+		latest.Volume = input.Data.Get(path).String()
+		if _, ok := new(big.Float).SetString(latest.Volume); !ok {
+			return observation{}, fmt.Errorf("TWAP: volume %v at %v is not a number", latest.Volume, path)
+		}
+	}
+	return latest, nil
+}
+
+// withinWindow drops observations older than the window start, except for
+// the last one before it, which was still the latest price when the window
+// began.
+func withinWindow(obs []observation, start time.Time) []observation {
+	first := 0
+	for i, o := range obs {
+		if !o.Time.After(start) {
+			first = i
+		}
+	}
+	return obs[first:]
+}
+
+func timeWeightedAverage(obs []observation, start, end time.Time) (*big.Float, error) {
+	sum := new(big.Float).SetPrec(aggregatePrecision)
+	var total time.Duration
+	for i, o := range obs {
+		from := o.Time
+		if from.Before(start) {
+			from = start
+		}
+		to := end
+		if i+1 < len(obs) {
+			to = obs[i+1].Time
+		}
+		if !to.After(from) {
+			continue
+		}
+		price, _ := new(big.Float).SetPrec(aggregatePrecision).SetString(o.Price)
+		weight := to.Sub(from)
+		sum.Add(sum, price.Mul(price, new(big.Float).SetInt64(int64(weight))))
+		total += weight
+	}
+
+	if total == 0 {
+		latest, _ := new(big.Float).SetPrec(aggregatePrecision).SetString(obs[len(obs)-1].Price)
+		return latest, nil
+	}
+	return sum.Quo(sum, new(big.Float).SetInt64(int64(total))), nil
+}
+
+func volumeWeightedAverage(obs []observation, start time.Time) (*big.Float, error) {
+	sum := new(big.Float).SetPrec(aggregatePrecision)
+	total := new(big.Float).SetPrec(aggregatePrecision)
+	for _, o := range obs {
+		if o.Time.Before(start) {
+			continue
+		}
+		volume, ok := new(big.Float).SetPrec(aggregatePrecision).SetString(o.Volume)
+		if !ok {
+			continue
+		}
+		price, _ := new(big.Float).SetPrec(aggregatePrecision).SetString(o.Price)
+		sum.Add(sum, price.Mul(price, volume))
+		total.Add(total, volume)
+	}
+
+	if total.Sign() == 0 {
+		return nil, fmt.Errorf("TWAP: no volume within the window")
+	}
+	return sum.Quo(sum, total), nil
+}
@@ -0,0 +1,124 @@
+package adapters_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTWAP_Perform_TimeWeighted(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	clock := cltest.UseSettableClock(store)
+	start := time.Now()
+
+	saved := cltest.SavedRunResult(t, store)
+	adapter := adapters.TWAP{Window: "1h"}
+	observations := []struct {
+		at    time.Duration
+		price string
+		want  string
+	}{
+		{0, "100", "100"},
+		{45 * time.Minute, "120", "100"},
+		{time.Hour, "120", "105"},
+		{2 * time.Hour, "130", "120"},
+	}
+
+	for _, o := range observations {
+		clock.SetTime(start.Add(o.at))
+		input := cltest.RunResultWithValue(o.price)
+		input.JobRunID = saved.JobRunID
+		result := adapter.Perform(input, store)
+		assert.Nil(t, result.GetError())
+		val, err := result.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, o.want, val, o.at.String())
+	}
+}
+
+func TestTWAP_Perform_VolumeWeighted(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	clock := cltest.UseSettableClock(store)
+	start := time.Now()
+
+	saved := cltest.SavedRunResult(t, store)
+	adapter := adapters.TWAP{Window: "1h", Weight: "volume", VolumePath: "vol"}
+	inputs := []string{`{"value":"100","vol":"3"}`, `{"value":"200","vol":"1"}`}
+	var result models.RunResult
+	for i, input := range inputs {
+		clock.SetTime(start.Add(time.Duration(i) * time.Minute))
+		result = adapter.Perform(models.RunResult{JobRunID: saved.JobRunID, Data: cltest.JSONFromString(input)}, store)
+		assert.Nil(t, result.GetError())
+	}
+	val, err := result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "125", val)
+
+	input := cltest.RunResultWithValue("100")
+	input.JobRunID = saved.JobRunID
+	result = adapter.Perform(input, store)
+	assert.True(t, result.HasError())
+}
+
+func TestTWAP_Perform_Concurrent(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	saved := cltest.SavedRunResult(t, store)
+	adapter := adapters.TWAP{Window: "1h"}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := cltest.RunResultWithValue("100")
+			input.JobRunID = saved.JobRunID
+			assert.Nil(t, adapter.Perform(input, store).GetError())
+		}()
+	}
+	wg.Wait()
+
+	jr, err := store.FindJobRun(saved.JobRunID)
+	assert.Nil(t, err)
+	var obs []interface{}
+	assert.Nil(t, store.GetKV("twap/"+jr.JobID, &obs))
+	assert.Equal(t, 10, len(obs), "no run's observation should be lost")
+}
+
+func TestTWAP_Perform_InvalidParams(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		adapter adapters.TWAP
+		value   string
+	}{
+		{"no window", adapters.TWAP{}, "100"},
+		{"bad window", adapters.TWAP{Window: "soon"}, "100"},
+		{"unknown weight", adapters.TWAP{Window: "1h", Weight: "trades"}, "100"},
+		{"not a number", adapters.TWAP{Window: "1h"}, "abc"},
+	}
+
+	saved := cltest.SavedRunResult(t, store)
+	for _, test := range tests {
+		input := cltest.RunResultWithValue(test.value)
+		input.JobRunID = saved.JobRunID
+		result := test.adapter.Perform(input, store)
+		assert.True(t, result.HasError(), test.name)
+	}
+
+	result := (&adapters.TWAP{Window: "1h"}).Perform(cltest.RunResultWithValue("100"), store)
+	assert.Contains(t, result.Error(), "was not found", "unsaved runs have no job to keep observations for")
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"runtime"
 	"strings"
 
 	"github.com/smartcontractkit/chainlink/logger"
@@ -27,29 +29,89 @@ type TerminalAuthenticator struct {
 // Authenticate checks to see if there are accounts present in
 // the KeyStore, and if there are none, a new account will be created
 // by prompting for a password. If there are accounts present, the
-// account which is unlocked by the given password will be used.
+// account which is unlocked by the given password will be used. When the
+// key is held outside the keystore, by a remote signer, Vault, or a KMS,
+// there is nothing to unlock, but the signer must be able to sign. The
+// password then unlocks the node's VRF key, which is generated if there is
+// none.
 func (auth TerminalAuthenticator) Authenticate(store *store.Store, pwd string) {
-	if len(pwd) != 0 {
-		auth.authenticateWithPwd(store, pwd)
+	if store.CurrentConfig().ExternalSigner() {
+		if !auth.checkSigner(store) {
+			return
+		}
+	} else if len(pwd) != 0 {
+		if !auth.authenticateWithPwd(store, pwd) {
+			return
+		}
 	} else {
-		auth.authenticationPrompt(store)
+		pwd = auth.authenticationPrompt(store)
 	}
+	auth.unlockVRFKey(store, pwd)
 }
 
-func (auth TerminalAuthenticator) authenticationPrompt(store *store.Store) {
+// ReadPasswordFile returns the password held in the file, without the
+// newline it may end with. As the password unlocks the node's account, the
+// file must not be readable by anyone but its owner.
+func ReadPasswordFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("Password file %v is not a regular file", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("Password file %v can be read by others, its mode must be 0600 or 0400, not %#o", path, info.Mode().Perm())
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	password := strings.TrimRight(string(b), "\r\n")
+	if len(password) == 0 {
+		return "", fmt.Errorf("Password file %v is empty", path)
+	}
+	return password, nil
+}
+
+func (auth TerminalAuthenticator) authenticationPrompt(store *store.Store) string {
 	if store.KeyStore.HasAccounts() {
-		auth.promptAndCheckPassword(store)
-	} else {
-		auth.promptAndCreateAccount(store)
+		return auth.promptAndCheckPassword(store)
 	}
+	return auth.promptAndCreateAccount(store)
 }
 
-func (auth TerminalAuthenticator) authenticateWithPwd(store *store.Store, pwd string) {
+func (auth TerminalAuthenticator) authenticateWithPwd(store *store.Store, pwd string) bool {
 	if !store.KeyStore.HasAccounts() {
 		fmt.Println("There are no accounts, creating a new account with the specified password")
 		createAccount(store, pwd)
 	} else if err := checkPassword(store, pwd); err != nil {
 		auth.Exiter(1)
+		return false
+	}
+	return true
+}
+
+func (auth TerminalAuthenticator) checkSigner(store *store.Store) bool {
+	if !store.Signer.Unlocked() {
+		fmt.Printf("Signer cannot sign for %v\n", store.Signer.GetAccount().Address.Hex())
+		auth.Exiter(1)
+		return false
+	}
+	return true
+}
+
+// unlockVRFKey unlocks the VRF key with the keystore password. An external
+// signer needs no password, so without one the key stays locked and
+// Random tasks error.
+func (auth TerminalAuthenticator) unlockVRFKey(store *store.Store, pwd string) {
+	if len(pwd) == 0 {
+		logger.Warn("No keystore password was given, so the VRF key is locked and Random tasks will error")
+		return
+	}
+	if err := store.UnlockVRFKey(pwd); err != nil {
+		fmt.Println(err.Error())
+		auth.Exiter(1)
 	}
 }
 
@@ -61,27 +123,33 @@ func checkPassword(store *store.Store, phrase string) error {
 	return nil
 }
 
-func (auth TerminalAuthenticator) promptAndCheckPassword(store *store.Store) {
+func (auth TerminalAuthenticator) promptAndCheckPassword(store *store.Store) string {
 	for {
 		phrase := auth.Prompter.Prompt("Enter Password:")
 		if checkPassword(store, phrase) == nil {
-			break
+			return phrase
 		}
 	}
 }
 
-func (auth TerminalAuthenticator) promptAndCreateAccount(store *store.Store) {
+func (auth TerminalAuthenticator) promptAndCreateAccount(store *store.Store) string {
+	phrase := promptNewPassword(auth.Prompter)
+	createAccount(store, phrase)
+	return phrase
+}
+
+// promptNewPassword prompts for a new password until it is entered the same
+// way twice.
+func promptNewPassword(prompter Prompter) string {
 	for {
-		phrase := auth.Prompter.Prompt("New Password: ")
+		phrase := prompter.Prompt("New Password: ")
 		clearLine()
-		phraseConfirmation := auth.Prompter.Prompt("Confirm Password: ")
+		phraseConfirmation := prompter.Prompt("Confirm Password: ")
 		clearLine()
 		if phrase == phraseConfirmation {
-			createAccount(store, phrase)
-			break
-		} else {
-			fmt.Printf("Passwords don't match. Please try again... ")
+			return phrase
 		}
+		fmt.Printf("Passwords don't match. Please try again... ")
 	}
 }
 
@@ -102,8 +170,12 @@ type Prompter interface {
 type PasswordPrompter struct{}
 
 // Prompt displays the prompt for the user to enter the password and
-// reads their input.
+// reads their input. With no terminal to prompt on, such as when started
+// by systemd or in a container, it exits instead of waiting forever.
 func (pp PasswordPrompter) Prompt(prompt string) string {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		logger.Fatal("No terminal to prompt for a password on, set PASSWORD_FILE or KEYSTORE_PASSWORD to start unattended")
+	}
 	var rval string
 	withTerminalResetter(func() {
 		fmt.Print(prompt)
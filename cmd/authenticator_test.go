@@ -1,6 +1,9 @@
 package cmd_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path"
 	"testing"
 
 	"github.com/smartcontractkit/chainlink/cmd"
@@ -45,6 +48,8 @@ func TestTerminalAuthenticatorWithNoAcctWithInitialPwd(t *testing.T) {
 	assert.True(t, app.Store.KeyStore.HasAccounts())
 	assert.False(t, exited)
 	assert.Equal(t, 1, len(app.Store.KeyStore.Accounts()))
+	_, err := app.Store.VRFKey()
+	assert.Nil(t, err, "the password unlocks the VRF key")
 }
 
 func TestTerminalAuthenticatorWithAcctNoInitialPwd(t *testing.T) {
@@ -107,3 +112,47 @@ func TestTerminalAuthenticatorWithAcctAndPwd(t *testing.T) {
 		})
 	}
 }
+
+func TestReadPasswordFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "password")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		contents string
+		mode     os.FileMode
+		want     string
+		wantErr  bool
+	}{
+		{"owner only", "p4ssword\n", 0600, "p4ssword", false},
+		{"read only", "p4ssword", 0400, "p4ssword", false},
+		{"group readable", "p4ssword", 0640, "", true},
+		{"world readable", "p4ssword", 0644, "", true},
+		{"empty", "\n", 0600, "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			file := path.Join(dir, test.name)
+			assert.Nil(t, ioutil.WriteFile(file, []byte(test.contents), 0600))
+			assert.Nil(t, os.Chmod(file, test.mode))
+
+			password, err := cmd.ReadPasswordFile(file)
+			if test.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, password)
+		})
+	}
+
+	_, err = cmd.ReadPasswordFile(path.Join(dir, "missing"))
+	assert.NotNil(t, err)
+	_, err = cmd.ReadPasswordFile(dir)
+	assert.NotNil(t, err)
+}
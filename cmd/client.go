@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/asdine/storm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gin-gonic/gin"
 	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/services"
@@ -30,38 +42,297 @@ type Client struct {
 
 // RunNode starts the Chainlink core.
 func (cli *Client) RunNode(c *clipkg.Context) error {
+	return cli.runNode(c, func(services.Application) error { return nil })
+}
+
+// RunDev starts the Chainlink core with the dev profile, attached to a
+// local development chain that is already running, such as the one
+// internal/bin/devnet starts. Once the node is up, it funds the node's
+// account from DEV_FUNDER_KEY's, and deploys the LINK and Oracle contracts
+// from the artifacts in DEV_CONTRACTS_DIR, if they are set. A sample job,
+// which needs nothing outside the node, is created if the node does not
+// have any jobs yet.
+func (cli *Client) RunDev(c *clipkg.Context) error {
+	if err := cli.Config.UseProfile("dev"); err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.runNode(c, func(app services.Application) error {
+		if err := setUpDevChain(app.GetStore()); err != nil {
+			return err
+		}
+		return createSampleJob(app)
+	})
+}
+
+func (cli *Client) runNode(c *clipkg.Context, afterStart func(services.Application) error) error {
 	if c.Bool("debug") {
 		cli.Config.LogLevel = strpkg.LogLevel{zapcore.DebugLevel}
 	}
+	if c.Bool("migrate-only") {
+		return cli.errorOut(cli.migrate(func(orm *models.ORM) error {
+			return orm.MigrateUp(models.Migrations)
+		}))
+	}
 	logger.Infow("Starting Chainlink Node " + strpkg.Version + " at commit " + strpkg.Sha)
 	app := cli.AppFactory.NewApplication(cli.Config)
 	store := app.GetStore()
-	cli.Auth.Authenticate(store, c.String("password"))
+	pwd, err := cli.password(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	cli.Auth.Authenticate(store, pwd)
 	if err := app.Start(); err != nil {
 		return cli.errorOut(err)
 	}
 	defer app.Stop()
 	logNodeBalance(store)
+	if err := afterStart(app); err != nil {
+		return cli.errorOut(err)
+	}
 	return cli.errorOut(cli.Runner.Run(app))
 }
 
+// password returns the keystore password given by --password, or else read
+// from --password-file or PASSWORD_FILE, or else from KEYSTORE_PASSWORD,
+// so that the node can start without anyone there to type it. It is empty
+// if the password is to be prompted for.
+func (cli *Client) password(c *clipkg.Context) (string, error) {
+	if pwd := c.String("password"); pwd != "" {
+		return pwd, nil
+	}
+	path := c.String("password-file")
+	if path == "" {
+		path = cli.Config.PasswordFile
+	}
+	if path != "" {
+		return ReadPasswordFile(path)
+	}
+	return cli.Config.KeystorePassword, nil
+}
+
+// Rollback undoes the store migrations applied after the given version,
+// so that the previous release of the node can be run against the store.
+// The node must not be running.
+func (cli *Client) Rollback(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the version to roll back to"))
+	}
+	to, err := strconv.ParseUint(c.Args().First(), 10, 64)
+	if err != nil {
+		return cli.errorOut(fmt.Errorf("Invalid version %v", c.Args().First()))
+	}
+	return cli.errorOut(cli.migrate(func(orm *models.ORM) error {
+		return orm.MigrateDown(models.Migrations, to)
+	}))
+}
+
+// migrate opens the store without starting the node, changes which
+// migrations are applied, and logs the version the store is left at.
+func (cli *Client) migrate(change func(*models.ORM) error) error {
+	orm := models.NewORM(cli.Config.RootDir)
+	defer orm.Close()
+	if err := change(orm); err != nil {
+		return err
+	}
+	version, err := orm.MigrationVersion()
+	if err != nil {
+		return err
+	}
+	logger.Infow(fmt.Sprintf("Store is at migration version %v", version), "version", version)
+	return nil
+}
+
+// sampleJobJSON is the job RunDev creates. It formats the value it is run
+// with, rather than fetching one, so that it runs without outside services.
+const sampleJobJSON = `{
+  "initiators": [{"type": "web"}],
+  "tasks": [
+    {"type": "Multiply", "times": 100},
+    {"type": "EthUint256"}
+  ]
+}`
+
+func createSampleJob(app services.Application) error {
+	jobs, err := app.GetStore().Jobs()
+	if err != nil || len(jobs) > 0 {
+		return err
+	}
+
+	j := models.NewJob()
+	if err := json.Unmarshal([]byte(sampleJobJSON), &j); err != nil {
+		return err
+	}
+	if err := app.AddJob(j); err != nil {
+		return err
+	}
+	logger.Infow(fmt.Sprintf(`Created sample job %v, run it with: chainlink run %v '{"value":"1.23"}'`, j.ID, j.ID), "job", j)
+	return nil
+}
+
+// devFundAmount is how much ether, in wei, RunDev funds the node's account
+// with.
+var devFundAmount = new(big.Int).Mul(big.NewInt(10), big.NewInt(1e18))
+
+const (
+	// devFundTimeout is how long RunDev waits for the funding of the node's
+	// account to be mined.
+	devFundTimeout = time.Minute
+	// devDeployGasLimit is the gas limit of RunDev's contract deployments.
+	devDeployGasLimit uint64 = 4000000
+)
+
+// devContracts are the names of the contracts RunDev deploys, in order,
+// whose truffle artifacts are the name with .json in DEV_CONTRACTS_DIR.
+var devContracts = []string{"LinkToken", "Oracle"}
+
+// setUpDevChain funds the node's account and deploys the contracts in
+// devContracts, as the config says to.
+func setUpDevChain(store *strpkg.Store) error {
+	config := store.CurrentConfig()
+	if config.DevFunderKey != "" {
+		if err := fundNodeAccount(store, config.DevFunderKey); err != nil {
+			return fmt.Errorf("Funding the node's account: %v", err)
+		}
+	}
+	if config.DevContractsDir == "" {
+		return nil
+	}
+	for _, name := range devContracts {
+		if err := deployDevContract(store, config.DevContractsDir, name); err != nil {
+			return fmt.Errorf("Deploying %v: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// fundNodeAccount sends devFundAmount to the node's account from the
+// account of the hex encoded private key, unless the node's account has
+// that much already, and waits for it to arrive.
+func fundNodeAccount(store *strpkg.Store, hexKey string) error {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return errors.New("DEV_FUNDER_KEY must be a hex encoded private key")
+	}
+	txm := store.TxManager
+	funder := crypto.PubkeyToAddress(key.PublicKey)
+	node := txm.Signer.GetAccount().Address
+	if funder == node {
+		return nil
+	}
+	balance, err := txm.GetWeiBalance(node)
+	if err != nil || balance.Cmp(devFundAmount) >= 0 {
+		return err
+	}
+
+	unsigned, err := txm.BuildUnsignedTx(funder, node, nil, devFundAmount, 21000)
+	if err != nil {
+		return err
+	}
+	chainID := new(big.Int).SetUint64(unsigned.ChainID)
+	signed, err := types.SignTx(unsigned.EthTx(), types.NewEIP155Signer(chainID), key)
+	if err != nil {
+		return err
+	}
+	hex, err := utils.EncodeTxToHex(signed)
+	if err != nil {
+		return err
+	}
+	hash, err := txm.BroadcastSignedTx(hex)
+	if err != nil {
+		return err
+	}
+	logger.Infow(fmt.Sprintf("Funding the node's account %v from %v", node.Hex(), funder.Hex()), "hash", hash.Hex())
+
+	deadline := store.Clock.Now().Add(devFundTimeout)
+	for balance.Cmp(devFundAmount) < 0 {
+		if store.Clock.Now().After(deadline) {
+			return fmt.Errorf("tx %v was not mined within %v", hash.Hex(), devFundTimeout)
+		}
+		<-store.Clock.After(time.Second)
+		if balance, err = txm.GetWeiBalance(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deployDevContract deploys the contract whose truffle artifact is the name
+// with .json in dir, from the node's account, unless RunDev has deployed
+// it before, and logs its address.
+func deployDevContract(store *strpkg.Store, dir, name string) error {
+	key := "dev/" + name
+	var address string
+	if err := store.GetKV(key, &address); err == nil {
+		logger.Infow(fmt.Sprintf("%v was deployed at %v", name, address), "address", address)
+		return nil
+	} else if err != storm.ErrNotFound {
+		return err
+	}
+
+	file := filepath.Join(dir, name+".json")
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var artifact struct {
+		Bytecode string `json:"bytecode"`
+	}
+	if err := json.Unmarshal(b, &artifact); err != nil {
+		return fmt.Errorf("Reading %v: %v", file, err)
+	}
+	code := common.FromHex(artifact.Bytecode)
+	if len(code) == 0 {
+		return fmt.Errorf("%v has no bytecode, compile it first", file)
+	}
+
+	tx, contract, err := store.TxManager.DeployContract(code, devDeployGasLimit)
+	if err != nil {
+		return err
+	}
+	if err := store.SetKV(key, contract.Hex()); err != nil {
+		return err
+	}
+	logger.Infow(fmt.Sprintf("Deployed %v at %v", name, contract.Hex()), "address", contract.Hex(), "hash", tx.Hash.Hex())
+	return nil
+}
+
 func logNodeBalance(store *strpkg.Store) {
 	balance, err := presenters.ShowEthBalance(store)
 	logger.WarnIf(err)
 	logger.Infow(balance)
 }
 
+// ShowBalances shows the ether and LINK balances of the node's account,
+// in the units the node is set to show them in.
+func (cli *Client) ShowBalances(c *clipkg.Context) error {
+	resp, err := cli.sessionClient().Get("/v2/balances")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+
+	var balances presenters.Balances
+	return cli.deserializeResponse(resp, &balances)
+}
+
+// ShowConfig shows the node's effective configuration, with where each
+// setting came from, and secrets redacted. It reads the config file and
+// environment as the node would, without connecting to it.
+func (cli *Client) ShowConfig(c *clipkg.Context) error {
+	settings := cli.Config.Settings()
+	return cli.errorOut(cli.Render(&settings))
+}
+
 // ShowJob returns the status of the given JobID to the console.
 func (cli *Client) ShowJob(c *clipkg.Context) error {
-	cfg := cli.Config
 	if !c.Args().Present() {
 		return cli.errorOut(errors.New("Must pass the job id to be shown"))
 	}
-	resp, err := utils.BasicAuthGet(
-		cfg.BasicAuthUsername,
-		cfg.BasicAuthPassword,
-		cfg.ClientNodeURL+"/v2/jobs/"+c.Args().First(),
-	)
+	return cli.showJob(cli.sessionClient(), c.Args().First())
+}
+
+func (cli *Client) showJob(api *SessionClient, id string) error {
+	resp, err := api.Get("/v2/jobs/" + id)
 	if err != nil {
 		return cli.errorOut(err)
 	}
@@ -72,12 +343,7 @@ func (cli *Client) ShowJob(c *clipkg.Context) error {
 
 // GetJobs returns all jobs to the console.
 func (cli *Client) GetJobs(c *clipkg.Context) error {
-	cfg := cli.Config
-	resp, err := utils.BasicAuthGet(
-		cfg.BasicAuthUsername,
-		cfg.BasicAuthPassword,
-		cfg.ClientNodeURL+"/v2/jobs",
-	)
+	resp, err := cli.sessionClient().Get("/v2/jobs")
 	if err != nil {
 		return cli.errorOut(err)
 	}
@@ -87,14 +353,512 @@ func (cli *Client) GetJobs(c *clipkg.Context) error {
 	return cli.deserializeResponse(resp, &jobs)
 }
 
-func (cli *Client) deserializeResponse(resp *http.Response, dst interface{}) error {
+// ExportJobs writes the specs of the node's jobs that are not archived as a
+// bundle, in the --format json or yaml, to the --output file, or prints
+// them.
+func (cli *Client) ExportJobs(c *clipkg.Context) error {
+	format := bundleFormat(c.String("format"), c.String("output"))
+	b, err := readResponse(cli.sessionClient().Get("/v2/job_specs?format=" + url.QueryEscape(format)))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(writeOutput(c.String("output"), strings.TrimSpace(string(b))))
+}
+
+// ImportJobs creates the jobs in the bundle in the given file, as written
+// by ExportJobs, skipping those the node already has. The bundle is read as
+// YAML if the --format is yaml or the file ends in .yaml or .yml.
+func (cli *Client) ImportJobs(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the job bundle file to import"))
+	}
+	file := c.Args().First()
+	bundle, err := ioutil.ReadFile(file)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	format := bundleFormat(c.String("format"), file)
+	b, err := readResponse(cli.sessionClient().Post("/v2/job_specs?format="+url.QueryEscape(format), bytes.NewBuffer(bundle)))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var imported struct {
+		Created []string `json:"created"`
+		Skipped []string `json:"skipped"`
+	}
+	if err := json.Unmarshal(b, &imported); err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Infow(
+		fmt.Sprintf("Imported %v jobs, skipped %v the node already had", len(imported.Created), len(imported.Skipped)),
+		"created", imported.Created, "skipped", imported.Skipped,
+	)
+	return nil
+}
+
+// bundleFormat returns the given format, or else yaml if the file name ends
+// in .yaml or .yml, or else json.
+func bundleFormat(format, file string) string {
+	if format != "" {
+		return format
+	}
+	if strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml") {
+		return models.JobBundleYAML
+	}
+	return models.JobBundleJSON
+}
+
+// CreateJobRun starts a run of the given JobID on the node, using the
+// optional JSON object after it as the data the run starts with, and
+// renders the new run.
+func (cli *Client) CreateJobRun(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the job id to be run"))
+	}
+	api := cli.sessionClient()
+	jobID := c.Args().First()
+	runID, err := postForID(api, "/v2/jobs/"+jobID+"/runs", c.Args().Get(1))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	resp, err := api.Get("/v2/runs?jobId=" + jobID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var runs struct {
+		Runs []models.JobRun `json:"runs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&runs); err != nil {
+		return cli.errorOut(err)
+	}
+	for _, jr := range runs.Runs {
+		if jr.ID == runID {
+			return cli.errorOut(cli.Render(&jr))
+		}
+	}
+	return cli.errorOut(fmt.Errorf("Run %v not found", runID))
+}
+
+// PauseJob stops the given JobID from starting new runs on the node.
+func (cli *Client) PauseJob(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the job id to be paused"))
+	}
+	api := cli.sessionClient()
+	if _, err := postForID(api, "/v2/jobs/"+c.Args().First()+"/pause", ""); err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.showJob(api, c.Args().First())
+}
+
+// ResumeJob lets the given JobID start new runs on the node again, replaying
+// the runs skipped for log events while it was paused if asked to.
+func (cli *Client) ResumeJob(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the job id to be resumed"))
+	}
+	api := cli.sessionClient()
+	path := "/v2/jobs/" + c.Args().First() + "/resume"
+	if c.Bool("replay") {
+		path += "?replay=true"
+	}
+	if _, err := postForID(api, path, ""); err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.showJob(api, c.Args().First())
+}
+
+// PruneRuns deletes finished runs on the node now, those older than the
+// --age flag and those beyond the newest --keep of each job, each defaulting
+// to the node's run retention settings.
+func (cli *Client) PruneRuns(c *clipkg.Context) error {
+	query := url.Values{}
+	if c.IsSet("age") {
+		query.Set("age", c.String("age"))
+	}
+	if c.IsSet("keep") {
+		query.Set("keep", strconv.Itoa(c.Int("keep")))
+	}
+	path := "/v2/runs/prune"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := cli.sessionClient().Post(path, bytes.NewBufferString(""))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
 	if resp.StatusCode >= 400 {
 		return cli.errorOut(errors.New(resp.Status))
 	}
+	var result struct {
+		Pruned int `json:"pruned"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Infow(fmt.Sprintf("Pruned %v runs", result.Pruned), "pruned", result.Pruned)
+	return nil
+}
+
+// BuildTx has the node build a transaction to the --to address with the
+// --data, from the --from address or else the node's account, for signing
+// offline with SignTx. It is written as JSON to the --output file, or
+// printed.
+func (cli *Client) BuildTx(c *clipkg.Context) error {
+	if !c.IsSet("to") {
+		return cli.errorOut(errors.New("Must pass the --to address"))
+	}
+	request := map[string]interface{}{
+		"to":       c.String("to"),
+		"data":     c.String("data"),
+		"gasLimit": c.Uint64("gas-limit"),
+	}
+	if c.IsSet("from") {
+		request["from"] = c.String("from")
+	}
+	if c.IsSet("value") {
+		value, ok := new(big.Int).SetString(c.String("value"), 10)
+		if !ok {
+			return cli.errorOut(fmt.Errorf("Invalid value %v", c.String("value")))
+		}
+		request["value"] = value
+	}
+
+	b, err := cli.postJSON("/v2/txs/unsigned", request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var unsigned bytes.Buffer
+	if err := json.Indent(&unsigned, b, "", "  "); err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(writeOutput(c.String("output"), unsigned.String()))
+}
+
+// SignTx signs the transaction in the --unsigned file, as built by BuildTx,
+// with the account in the keystore. It does not connect to the node or to
+// Ethereum, so that it can be run on an air-gapped machine holding the key.
+// The signed transaction is written as hex encoded RLP to the --output
+// file, or printed.
+func (cli *Client) SignTx(c *clipkg.Context) error {
+	if !c.IsSet("unsigned") {
+		return cli.errorOut(errors.New("Must pass the --unsigned transaction file"))
+	}
+	b, err := ioutil.ReadFile(c.String("unsigned"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var unsigned models.UnsignedTx
+	if err := json.Unmarshal(b, &unsigned); err != nil {
+		return cli.errorOut(fmt.Errorf("Reading unsigned transaction: %v", err))
+	}
+
+	keyStore := strpkg.NewKeyStore(cli.Config.KeysDir())
+	if !keyStore.HasAccounts() {
+		return cli.errorOut(errors.New("No account in the keystore to sign with"))
+	}
+	pwd, err := cli.password(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	cli.Auth.Authenticate(&strpkg.Store{KeyStore: keyStore}, pwd)
+	if account := keyStore.GetAccount().Address; account != unsigned.From {
+		return cli.errorOut(fmt.Errorf("Transaction is from %v, but the keystore holds %v", unsigned.From.Hex(), account.Hex()))
+	}
+
+	signed, err := keyStore.SignTx(unsigned.EthTx(), unsigned.ChainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	hex, err := utils.EncodeTxToHex(signed)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Infow(fmt.Sprintf("Signed tx %v", signed.Hash().String()), "nonce", signed.Nonce())
+	return cli.errorOut(writeOutput(c.String("output"), hex))
+}
+
+// BroadcastTx has the node send the transaction in the --signed file, as
+// written by SignTx.
+func (cli *Client) BroadcastTx(c *clipkg.Context) error {
+	if !c.IsSet("signed") {
+		return cli.errorOut(errors.New("Must pass the --signed transaction file"))
+	}
+	b, err := ioutil.ReadFile(c.String("signed"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	b, err = cli.postJSON("/v2/txs/broadcast", map[string]string{"signed": strings.TrimSpace(string(b))})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var broadcast struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(b, &broadcast); err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Infow(fmt.Sprintf("Broadcast tx %v", broadcast.Hash), "hash", broadcast.Hash)
+	return nil
+}
+
+// ExportKey writes the key of the account with the --address, or else the
+// node's account, to the --output file, or prints it, as geth compatible
+// encrypted JSON. It prompts for the account's password, and for a new
+// password to encrypt the exported key with.
+func (cli *Client) ExportKey(c *clipkg.Context) error {
+	keyStore := strpkg.NewKeyStore(cli.Config.KeysDir())
+	if !keyStore.HasAccounts() {
+		return cli.errorOut(errors.New("No account in the keystore to export"))
+	}
+	address := keyStore.GetAccount().Address
+	if c.IsSet("address") {
+		if !common.IsHexAddress(c.String("address")) {
+			return cli.errorOut(fmt.Errorf("Invalid address %v", c.String("address")))
+		}
+		address = common.HexToAddress(c.String("address"))
+	}
+
+	prompter := cli.prompter()
+	passphrase := prompter.Prompt("Enter Password:")
+	clearLine()
+	fmt.Println("Enter a password to encrypt the exported key with")
+	newPassphrase := promptNewPassword(prompter)
+
+	keyJSON, err := keyStore.ExportKey(address, passphrase, newPassphrase)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Infow(fmt.Sprintf("Exported key for %v", address.Hex()), "address", address.Hex())
+	return cli.errorOut(writeOutput(c.String("output"), string(keyJSON)))
+}
+
+// ImportKey adds the key in the given geth compatible encrypted JSON file to
+// the node's keystore. It prompts for the file's password, and for the
+// node's password, which the key is stored encrypted with so that the node
+// unlocks it along with its other accounts.
+func (cli *Client) ImportKey(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the key file to import"))
+	}
+	keyJSON, err := ioutil.ReadFile(c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	keyStore := strpkg.NewKeyStore(cli.Config.KeysDir())
+	prompter := cli.prompter()
+	passphrase := prompter.Prompt("Enter Password for key file:")
+	clearLine()
+	var nodePassphrase string
+	if keyStore.HasAccounts() {
+		nodePassphrase = prompter.Prompt("Enter the node's Password:")
+		clearLine()
+		if err := keyStore.Unlock(nodePassphrase); err != nil {
+			return cli.errorOut(err)
+		}
+	} else {
+		fmt.Println("Enter a password for the node's keystore")
+		nodePassphrase = promptNewPassword(prompter)
+	}
+
+	account, err := keyStore.ImportKey(keyJSON, passphrase, nodePassphrase)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Infow(fmt.Sprintf("Imported key for %v", account.Address.Hex()), "address", account.Address.Hex())
+	return nil
+}
+
+// prompter returns the Prompter of the client's TerminalAuthenticator, so
+// that commands prompt for passwords the same way the node does.
+func (cli *Client) prompter() Prompter {
+	if auth, ok := cli.Auth.(TerminalAuthenticator); ok {
+		return auth.Prompter
+	}
+	return PasswordPrompter{}
+}
+
+// postJSON posts the request to the node as JSON and returns the body of a
+// successful response.
+func (cli *Client) postJSON(path string, request interface{}) ([]byte, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return readResponse(cli.sessionClient().Post(path, bytes.NewBuffer(body)))
+}
+
+// readResponse returns the body of a successful response, or an error with
+// the status and body of an unsuccessful one.
+func readResponse(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, responseError(resp, b)
+	}
+	return b, nil
+}
+
+// responseError returns the error of a failed response, with the detail of
+// its problem if it has one.
+func responseError(resp *http.Response, b []byte) error {
+	var p web.Problem
+	if json.Unmarshal(b, &p) == nil && p.Code != "" {
+		return fmt.Errorf("%v: %v", resp.Status, p.Error())
+	}
+	return fmt.Errorf("%v: %s", resp.Status, b)
+}
+
+// writeOutput writes s to the file at path, readable only by its owner, or
+// prints it if there is no path.
+func writeOutput(path, s string) error {
+	if path == "" {
+		fmt.Println(s)
+		return nil
+	}
+	return ioutil.WriteFile(path, []byte(s+"\n"), 0600)
+}
+
+const (
+	smokeTestTimeout      = 30 * time.Second
+	smokeTestPollInterval = 500 * time.Millisecond
+)
+
+// SmokeTest runs a temporary job against a live node to check that runs are
+// executed, saved, and presented. The job fetches the node's own health
+// endpoint and parses its status, and is archived once the test is over.
+func (cli *Client) SmokeTest(c *clipkg.Context) error {
+	cfg := cli.Config
+	spec := fmt.Sprintf(`{
+  "initiators": [{"type": "web"}],
+  "tasks": [
+    {"type": "HttpGet", "url": "%v/health"},
+    {"type": "JsonParse", "path": ["status"]}
+  ]
+}`, cfg.ClientNodeURL)
+
+	api := cli.sessionClient()
+	jobID, err := postForID(api, "/v2/jobs", spec)
+	if err != nil {
+		return cli.errorOut(fmt.Errorf("Smoke test: creating job: %v", err))
+	}
+	defer archiveJob(api, jobID)
+	runID, err := postForID(api, "/v2/jobs/"+jobID+"/runs", "")
+	if err != nil {
+		return cli.errorOut(fmt.Errorf("Smoke test: starting run: %v", err))
+	}
+
+	job, err := waitForRun(api, jobID, runID)
+	if err != nil {
+		return cli.errorOut(fmt.Errorf("Smoke test: %v", err))
+	}
+	logger.Infow("Smoke test passed", "job", jobID, "run", runID)
+	return cli.errorOut(cli.Render(&job))
+}
+
+func archiveJob(api *SessionClient, jobID string) {
+	resp, err := api.Delete("/v2/jobs/" + jobID)
+	if err != nil {
+		logger.Warnw("Smoke test: archiving job", "job", jobID, "err", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		logger.Warnw("Smoke test: archiving job", "job", jobID, "status", resp.Status)
+	}
+}
+
+func postForID(api *SessionClient, path string, body string) (string, error) {
+	resp, err := api.Post(path, bytes.NewBufferString(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", errors.New(resp.Status)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func waitForRun(api *SessionClient, jobID, runID string) (presenters.Job, error) {
+	deadline := time.Now().Add(smokeTestTimeout)
+	for time.Now().Before(deadline) {
+		resp, err := api.Get("/v2/jobs/" + jobID)
+		if err != nil {
+			return presenters.Job{}, err
+		}
+		var job presenters.Job
+		err = json.NewDecoder(resp.Body).Decode(&job)
+		resp.Body.Close()
+		if err != nil {
+			return job, err
+		}
+
+		for _, jr := range job.Runs {
+			if jr.ID != runID {
+				continue
+			}
+			if jr.Status == models.StatusErrored {
+				return job, fmt.Errorf("run %v errored: %v", runID, jr.Result.Error())
+			}
+			if jr.Status == models.StatusCompleted {
+				return job, checkSmokeTestResult(jr)
+			}
+		}
+		time.Sleep(smokeTestPollInterval)
+	}
+	return presenters.Job{}, fmt.Errorf("run %v did not complete within %v", runID, smokeTestTimeout)
+}
+
+func checkSmokeTestResult(jr models.JobRun) error {
+	val, err := jr.Result.Value()
+	if err != nil {
+		return fmt.Errorf("run %v result: %v", jr.ID, err)
+	}
+	if val != "ok" {
+		return fmt.Errorf("run %v returned %v, expected ok", jr.ID, val)
+	}
+	return nil
+}
+
+// sessionClient returns a client for the node's API that logs in with the
+// operator's credentials in the config, prompting for their two-factor
+// code if they need one.
+func (cli *Client) sessionClient() *SessionClient {
+	cfg := cli.Config
+	api := NewSessionClient(cfg.ClientNodeURL, cfg.BasicAuthUsername, cfg.BasicAuthPassword)
+	api.Prompter = cli.prompter()
+	return api
+}
+
+func (cli *Client) deserializeResponse(resp *http.Response, dst interface{}) error {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return cli.errorOut(err)
 	}
+	if resp.StatusCode >= 400 {
+		return cli.errorOut(responseError(resp, b))
+	}
 	if err = json.Unmarshal(b, &dst); err != nil {
 		return cli.errorOut(err)
 	}
@@ -130,9 +894,31 @@ type Runner interface {
 type ChainlinkRunner struct{}
 
 // Run sets the log level based on config and starts the web router to listen
-// for input and return data.
+// for input and return data. When TLS is configured the router is also
+// served over HTTPS, and plain HTTP can be redirected to it.
 func (n ChainlinkRunner) Run(app services.Application) error {
-	gin.SetMode(app.GetStore().Config.LogLevel.ForGin())
-	port := app.GetStore().Config.Port
-	return web.Router(app.(*services.ChainlinkApplication)).Run(":" + port)
+	config := app.GetStore().CurrentConfig()
+	gin.SetMode(config.LogLevel.ForGin())
+	router := web.Router(app.(*services.ChainlinkApplication))
+	if !config.TLSEnabled() {
+		return router.Run(":" + config.Port)
+	}
+
+	certFile, keyFile, err := config.CertFiles()
+	if err != nil {
+		return err
+	}
+	var handler http.Handler = router
+	if config.TLSRedirect {
+		handler = web.RedirectToTLS(config.TLSPort)
+	}
+	logger.Infow("Serving HTTPS", "port", config.TLSPort, "redirect", config.TLSRedirect)
+	errs := make(chan error, 2)
+	go func() {
+		errs <- http.ListenAndServeTLS(":"+config.TLSPort, certFile, keyFile, router)
+	}()
+	go func() {
+		errs <- http.ListenAndServe(":"+config.Port, handler)
+	}()
+	return <-errs
 }
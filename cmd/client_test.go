@@ -1,14 +1,25 @@
 package cmd_test
 
 import (
+	"encoding/json"
 	"flag"
+	"io/ioutil"
+	"math/big"
+	"path"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/smartcontractkit/chainlink/cmd"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/store/presenters"
+	"github.com/smartcontractkit/chainlink/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli"
 )
@@ -33,6 +44,124 @@ func TestRunNode(t *testing.T) {
 	assert.True(t, called)
 }
 
+func TestRunNode_PasswordFile(t *testing.T) {
+	app, _ := cltest.NewApplicationWithKeyStore() // cleanup invoked in client.RunNode
+	config := app.Store.Config
+	config.PasswordFile = path.Join(config.RootDir, "password.txt")
+	assert.Nil(t, ioutil.WriteFile(config.PasswordFile, []byte(cltest.Password+"\n"), 0600))
+
+	var pwd string
+	auth := cltest.CallbackAuthenticator{func(_ *store.Store, p string) { pwd = p }}
+	client := cmd.Client{
+		&cltest.RendererMock{},
+		config,
+		cltest.InstanceAppFactory{app},
+		auth,
+		cltest.EmptyRunner{}}
+
+	set := flag.NewFlagSet("test", 0)
+	set.Parse([]string{""})
+	c := cli.NewContext(nil, set, nil)
+
+	client.RunNode(c)
+	assert.Equal(t, cltest.Password, pwd)
+}
+
+func TestRunDev_CreatesSampleJob(t *testing.T) {
+	app, _ := cltest.NewApplicationWithKeyStore() // cleanup invoked in client.RunDev
+	var jobs []models.Job
+	runner := cltest.CallbackRunner{func(app services.Application) error {
+		var err error
+		jobs, err = app.GetStore().Jobs()
+		return err
+	}}
+	client := cmd.Client{
+		&cltest.RendererMock{},
+		app.Store.Config,
+		cltest.InstanceAppFactory{app},
+		cltest.CallbackAuthenticator{func(*store.Store, string) {}},
+		runner,
+	}
+
+	set := flag.NewFlagSet("test", 0)
+	set.Parse([]string{""})
+	c := cli.NewContext(nil, set, nil)
+
+	assert.Nil(t, client.RunDev(c))
+	assert.Equal(t, 1, len(jobs))
+	assert.True(t, jobs[0].WebAuthorized())
+	for _, task := range jobs[0].Tasks {
+		assert.NotEqual(t, "httpget", strings.ToLower(task.Type), "the sample job needs no outside services")
+	}
+}
+
+func TestRunDev_FundsNodeAndDeploysContracts(t *testing.T) {
+	app, _ := cltest.NewApplicationWithKeyStore() // cleanup invoked in client.RunDev
+	funderKey, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	funder := crypto.PubkeyToAddress(funderKey.PublicKey)
+	app.Store.Config.DevFunderKey = common.Bytes2Hex(crypto.FromECDSA(funderKey))
+	app.Store.Config.DevContractsDir = app.Store.Config.RootDir
+	for name, code := range map[string]string{"LinkToken": "0x6001", "Oracle": "0x6002"} {
+		artifact := []byte(`{"contractName": "` + name + `", "bytecode": "` + code + `"}`)
+		assert.Nil(t, ioutil.WriteFile(path.Join(app.Store.Config.RootDir, name+".json"), artifact, 0600))
+	}
+
+	sent := []types.Transaction{}
+	recordTx := func(_ interface{}, data ...interface{}) error {
+		tx, err := utils.DecodeEthereumTx(data[0].([]interface{})[0].(string))
+		sent = append(sent, tx)
+		return err
+	}
+	eth := app.MockEthClient()
+	eth.Register("eth_getBalance", "0x0")
+	eth.Register("eth_getBalance", "0x0")
+	eth.Register("eth_getTransactionCount", utils.Uint64ToHex(0))
+	eth.Register("eth_sendRawTransaction", cltest.NewHash(), recordTx)
+	eth.Register("eth_getBalance", "0x8ac7230489e80000")
+	for nonce := uint64(3); nonce < 5; nonce++ {
+		eth.Register("eth_getTransactionCount", utils.Uint64ToHex(nonce))
+		eth.Register("eth_blockNumber", utils.Uint64ToHex(1))
+		eth.Register("eth_sendRawTransaction", cltest.NewHash(), recordTx)
+	}
+
+	auth := cltest.CallbackAuthenticator{func(s *store.Store, _ string) {
+		assert.Nil(t, s.KeyStore.Unlock(cltest.Password))
+	}}
+	client := cmd.Client{
+		&cltest.RendererMock{},
+		app.Store.Config,
+		cltest.InstanceAppFactory{app},
+		auth,
+		cltest.EmptyRunner{},
+	}
+
+	set := flag.NewFlagSet("test", 0)
+	set.Parse([]string{""})
+	assert.Nil(t, client.RunDev(cli.NewContext(nil, set, nil)))
+	eth.EnsureAllCalled(t)
+
+	node := app.Store.KeyStore.GetAccount().Address
+	chainSigner := types.NewEIP155Signer(big.NewInt(int64(app.Store.Config.ChainID)))
+	assert.Equal(t, 3, len(sent))
+	from, err := types.Sender(chainSigner, &sent[0])
+	assert.Nil(t, err)
+	assert.Equal(t, funder, from)
+	assert.Equal(t, node, *sent[0].To())
+	assert.Equal(t, "10000000000000000000", sent[0].Value().String())
+	for i, code := range []string{"6001", "6002"} {
+		from, err := types.Sender(chainSigner, &sent[i+1])
+		assert.Nil(t, err)
+		assert.Equal(t, node, from)
+		assert.Nil(t, sent[i+1].To(), "contract creations have no recipient")
+		assert.Equal(t, code, common.Bytes2Hex(sent[i+1].Data()))
+	}
+
+	var oracle string
+	assert.Nil(t, app.Store.GetKV("dev/Oracle", &oracle))
+	assert.Equal(t, crypto.CreateAddress(node, 4).Hex(), oracle)
+}
+
 func TestClientGetJobs(t *testing.T) {
 	app, cleanup := cltest.NewApplication()
 	defer cleanup()
@@ -78,3 +207,193 @@ func TestClientShowJobNotFound(t *testing.T) {
 	assert.NotNil(t, client.ShowJob(c))
 	assert.Empty(t, r.Renders)
 }
+
+func TestClientCreateJobRun(t *testing.T) {
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	job := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&job))
+
+	client, r := cltest.NewClientAndRenderer(app.Store.Config)
+
+	set := flag.NewFlagSet("test", 0)
+	set.Parse([]string{job.ID, `{"value":"100"}`})
+	c := cli.NewContext(nil, set, nil)
+	assert.Nil(t, client.CreateJobRun(c))
+	assert.Equal(t, 1, len(r.Renders))
+	jr := *r.Renders[0].(*models.JobRun)
+	assert.Equal(t, job.ID, jr.JobID)
+
+	jr = cltest.WaitForJobRunToComplete(t, app, jr)
+	val, err := jr.Result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "100", val)
+}
+
+func TestClientCreateJobRun_InvalidInput(t *testing.T) {
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	job := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&job))
+
+	client, r := cltest.NewClientAndRenderer(app.Store.Config)
+
+	set := flag.NewFlagSet("test", 0)
+	set.Parse([]string{job.ID, `[1, 2]`})
+	c := cli.NewContext(nil, set, nil)
+	assert.NotNil(t, client.CreateJobRun(c))
+	assert.Empty(t, r.Renders)
+}
+
+func TestClientPauseAndResumeJob(t *testing.T) {
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	job := cltest.NewJob()
+	assert.Nil(t, app.Store.SaveJob(&job))
+
+	client, r := cltest.NewClientAndRenderer(app.Store.Config)
+
+	set := flag.NewFlagSet("test", 0)
+	set.Parse([]string{job.ID})
+	c := cli.NewContext(nil, set, nil)
+	assert.Nil(t, client.PauseJob(c))
+	assert.True(t, r.Renders[0].(*presenters.Job).Paused)
+
+	assert.Nil(t, client.ResumeJob(c))
+	assert.False(t, r.Renders[1].(*presenters.Job).Paused)
+}
+
+func TestClientSmokeTest(t *testing.T) {
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	client, r := cltest.NewClientAndRenderer(app.Store.Config)
+
+	assert.Nil(t, client.SmokeTest(nil))
+	assert.Equal(t, 1, len(r.Renders))
+	job := r.Renders[0].(*presenters.Job)
+	assert.Equal(t, 1, len(job.Runs))
+	assert.Equal(t, models.StatusCompleted, job.Runs[0].Status)
+
+	archived, err := app.Store.FindJob(job.ID)
+	assert.Nil(t, err)
+	assert.True(t, archived.Archived)
+}
+
+func TestClientOfflineSigning(t *testing.T) {
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	config := app.Store.Config
+	account := app.Store.KeyStore.GetAccount().Address
+	to := cltest.NewAddress()
+
+	eth := app.MockEthClient()
+	eth.Register("eth_getTransactionCount", utils.Uint64ToHex(7))
+
+	auth := cltest.CallbackAuthenticator{func(s *store.Store, _ string) {
+		assert.Nil(t, s.KeyStore.Unlock(cltest.Password))
+	}}
+	client := cmd.Client{&cltest.RendererMock{}, config, cltest.EmptyAppFactory{}, auth, cltest.EmptyRunner{}}
+
+	unsignedPath := path.Join(config.RootDir, "unsigned.json")
+	set := flag.NewFlagSet("build", 0)
+	set.String("to", to.Hex(), "")
+	set.String("data", "0xdeadbeef", "")
+	set.String("output", unsignedPath, "")
+	assert.Nil(t, client.BuildTx(cli.NewContext(nil, set, nil)))
+
+	var unsigned models.UnsignedTx
+	b, err := ioutil.ReadFile(unsignedPath)
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(b, &unsigned))
+	assert.Equal(t, account, unsigned.From)
+	assert.Equal(t, to, unsigned.To)
+	assert.Equal(t, uint64(7), unsigned.Nonce)
+	assert.Equal(t, "0xdeadbeef", unsigned.Data.String())
+
+	signedPath := path.Join(config.RootDir, "signed.rlp")
+	set = flag.NewFlagSet("sign", 0)
+	set.String("unsigned", unsignedPath, "")
+	set.String("output", signedPath, "")
+	assert.Nil(t, client.SignTx(cli.NewContext(nil, set, nil)))
+
+	b, err = ioutil.ReadFile(signedPath)
+	assert.Nil(t, err)
+	signed := new(types.Transaction)
+	assert.Nil(t, rlp.DecodeBytes(common.FromHex(strings.TrimSpace(string(b))), signed))
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(int64(config.ChainID))), signed)
+	assert.Nil(t, err)
+	assert.Equal(t, account, sender)
+	assert.Equal(t, uint64(7), signed.Nonce())
+
+	eth.Register("eth_sendRawTransaction", signed.Hash())
+	set = flag.NewFlagSet("broadcast", 0)
+	set.String("signed", signedPath, "")
+	assert.Nil(t, client.BroadcastTx(cli.NewContext(nil, set, nil)))
+	eth.EnsureAllCalled(t)
+}
+
+func TestClientSignTx_WrongAccount(t *testing.T) {
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	config := app.Store.Config
+	client, _ := cltest.NewClientAndRenderer(config)
+
+	unsignedPath := path.Join(config.RootDir, "unsigned.json")
+	unsigned := models.UnsignedTx{From: cltest.NewAddress(), To: cltest.NewAddress(), GasPrice: big.NewInt(1)}
+	b, err := json.Marshal(unsigned)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(unsignedPath, b, 0600))
+
+	set := flag.NewFlagSet("sign", 0)
+	set.String("unsigned", unsignedPath, "")
+	assert.NotNil(t, client.SignTx(cli.NewContext(nil, set, nil)))
+}
+
+func TestClientExportAndImportKey(t *testing.T) {
+	from, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	to, cleanupTo := cltest.NewApplication()
+	defer cleanupTo()
+	address := from.Store.KeyStore.GetAccount().Address
+	keyPath := path.Join(from.Store.Config.RootDir, "key.json")
+
+	prompt := &cltest.MockCountingPrompt{EnteredStrings: []string{cltest.Password, "exported", "exported"}}
+	auth := cmd.TerminalAuthenticator{prompt, func(int) {}}
+	client := cmd.Client{&cltest.RendererMock{}, from.Store.Config, cltest.EmptyAppFactory{}, auth, cltest.EmptyRunner{}}
+	set := flag.NewFlagSet("export", 0)
+	set.String("output", keyPath, "")
+	assert.Nil(t, client.ExportKey(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, 3, prompt.Count)
+
+	prompt = &cltest.MockCountingPrompt{EnteredStrings: []string{"exported", "nodepassword", "nodepassword"}}
+	auth = cmd.TerminalAuthenticator{prompt, func(int) {}}
+	client = cmd.Client{&cltest.RendererMock{}, to.Store.Config, cltest.EmptyAppFactory{}, auth, cltest.EmptyRunner{}}
+	set = flag.NewFlagSet("import", 0)
+	set.Parse([]string{keyPath})
+	assert.Nil(t, client.ImportKey(cli.NewContext(nil, set, nil)))
+
+	keyStore := store.NewKeyStore(to.Store.Config.KeysDir())
+	assert.Equal(t, 1, len(keyStore.Accounts()))
+	assert.Equal(t, address, keyStore.GetAccount().Address)
+	assert.Nil(t, keyStore.Unlock("nodepassword"))
+}
+
+func TestClientImportKey_WrongPassword(t *testing.T) {
+	from, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	keyJSON, err := from.Store.KeyStore.ExportKey(from.Store.KeyStore.GetAccount().Address, cltest.Password, "exported")
+	assert.Nil(t, err)
+	keyPath := path.Join(from.Store.Config.RootDir, "key.json")
+	assert.Nil(t, ioutil.WriteFile(keyPath, keyJSON, 0600))
+
+	to, cleanupTo := cltest.NewApplicationWithKeyStore()
+	defer cleanupTo()
+	prompt := &cltest.MockCountingPrompt{EnteredStrings: []string{"exported", "wrongpassword"}}
+	auth := cmd.TerminalAuthenticator{prompt, func(int) {}}
+	client := cmd.Client{&cltest.RendererMock{}, to.Store.Config, cltest.EmptyAppFactory{}, auth, cltest.EmptyRunner{}}
+	set := flag.NewFlagSet("import", 0)
+	set.Parse([]string{keyPath})
+	assert.NotNil(t, client.ImportKey(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, 1, len(store.NewKeyStore(to.Store.Config.KeysDir()).Accounts()))
+}
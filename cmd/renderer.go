@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/store/presenters"
 	"github.com/smartcontractkit/chainlink/utils"
@@ -45,6 +46,12 @@ func (rt RendererTable) Render(v interface{}) error {
 		rt.renderJobs(*typed)
 	case *presenters.Job:
 		rt.renderJob(*typed)
+	case *models.JobRun:
+		rt.renderJobRun(*typed)
+	case *presenters.Balances:
+		rt.renderBalances(*typed)
+	case *[]store.ConfigSetting:
+		rt.renderConfig(*typed)
 	default:
 		return fmt.Errorf("Unable to render object: %v", typed)
 	}
@@ -150,15 +157,56 @@ func (rt RendererTable) renderJobRuns(j presenters.Job) error {
 	table := tablewriter.NewWriter(rt)
 	table.SetHeader([]string{"ID", "Status", "Created At", "Result", "Error"})
 	for _, jr := range j.Runs {
-		table.Append([]string{
-			jr.ID,
-			jr.Status,
-			utils.ISO8601UTC(jr.CreatedAt),
-			jr.Result.Data.String(),
-			jr.Result.ErrorMessage.String,
-		})
+		table.Append(jobRunRowToStrings(jr))
 	}
 
 	render("Runs", table)
 	return nil
 }
+
+func (rt RendererTable) renderJobRun(jr models.JobRun) error {
+	table := tablewriter.NewWriter(rt)
+	table.SetHeader([]string{"ID", "Status", "Created At", "Result", "Error"})
+	table.Append(jobRunRowToStrings(jr))
+
+	render("Run", table)
+	return nil
+}
+
+func jobRunRowToStrings(jr models.JobRun) []string {
+	errorMessage := jr.Result.ErrorMessage.String
+	if jr.Result.ErrorCode.Valid {
+		errorMessage = fmt.Sprintf("%v (%v)", errorMessage, jr.Result.ErrorCode.String)
+	}
+	return []string{
+		jr.ID,
+		jr.Status,
+		utils.ISO8601UTC(jr.CreatedAt),
+		jr.Result.Data.String(),
+		errorMessage,
+	}
+}
+
+func (rt RendererTable) renderBalances(b presenters.Balances) error {
+	table := tablewriter.NewWriter(rt)
+	table.SetHeader([]string{"Address", "ETH", "LINK"})
+	link := ""
+	if b.Link != nil {
+		link = b.Link.String()
+	}
+	table.Append([]string{b.Address.Hex(), b.Eth.String(), link})
+
+	render("Balances", table)
+	return nil
+}
+
+func (rt RendererTable) renderConfig(settings []store.ConfigSetting) error {
+	table := tablewriter.NewWriter(rt)
+	table.SetHeader([]string{"Setting", "Value", "Source"})
+	for _, s := range settings {
+		table.Append([]string{s.Name, s.Value, s.Source})
+	}
+
+	render("Config", table)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/smartcontractkit/chainlink/cmd"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/store/presenters"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +34,21 @@ func TestRendererTableRenderShowJob(t *testing.T) {
 	assert.Nil(t, r.Render(&p))
 }
 
+func TestRendererTableRenderBalances(t *testing.T) {
+	r := cmd.RendererTable{ioutil.Discard}
+	b := presenters.Balances{
+		Address: cltest.NewAddress(),
+		Eth:     presenters.Amount{Value: "1.5", Unit: "ether"},
+	}
+	assert.Nil(t, r.Render(&b))
+}
+
+func TestRendererTableRenderConfig(t *testing.T) {
+	r := cmd.RendererTable{ioutil.Discard}
+	settings := []store.ConfigSetting{{Name: "PORT", Value: "6688", Source: store.SourceDefault}}
+	assert.Nil(t, r.Render(&settings))
+}
+
 func TestRendererTableRenderUnknown(t *testing.T) {
 	r := cmd.RendererTable{ioutil.Discard}
 	anon := struct{ Name string }{"Romeo"}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/smartcontractkit/chainlink/web"
+)
+
+// SessionClient makes requests to the node's API, logging in with the
+// operator's credentials before the first request and sending the session
+// cookie with every request after that. If the operator has two-factor
+// authentication, their code is asked for with the Prompter, if set.
+type SessionClient struct {
+	BaseURL  string
+	Email    string
+	Password string
+	Prompter Prompter
+	client   *http.Client
+	loggedIn bool
+}
+
+// NewSessionClient returns a client for the node at baseURL that logs in
+// with the given credentials.
+func NewSessionClient(baseURL, email, password string) *SessionClient {
+	jar, _ := cookiejar.New(nil)
+	return &SessionClient{
+		BaseURL:  baseURL,
+		Email:    email,
+		Password: password,
+		client:   &http.Client{Jar: jar},
+	}
+}
+
+// Get sends a GET request to the given path.
+func (sc *SessionClient) Get(path string) (*http.Response, error) {
+	return sc.do("GET", path, nil)
+}
+
+// Post sends a POST request with the given JSON body to the given path.
+func (sc *SessionClient) Post(path string, body io.Reader) (*http.Response, error) {
+	return sc.do("POST", path, body)
+}
+
+// Patch sends a PATCH request with the given JSON body to the given path.
+func (sc *SessionClient) Patch(path string, body io.Reader) (*http.Response, error) {
+	return sc.do("PATCH", path, body)
+}
+
+// Put sends a PUT request with the given JSON body to the given path.
+func (sc *SessionClient) Put(path string, body io.Reader) (*http.Response, error) {
+	return sc.do("PUT", path, body)
+}
+
+// Delete sends a DELETE request to the given path.
+func (sc *SessionClient) Delete(path string) (*http.Response, error) {
+	return sc.do("DELETE", path, nil)
+}
+
+func (sc *SessionClient) do(method, path string, body io.Reader) (*http.Response, error) {
+	if err := sc.login(); err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(method, sc.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return sc.client.Do(request)
+}
+
+func (sc *SessionClient) login() error {
+	if sc.loggedIn {
+		return nil
+	}
+	credentials := map[string]string{
+		"email":    sc.Email,
+		"password": sc.Password,
+	}
+	resp, err := sc.postCredentials(credentials)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized && sc.Prompter != nil && totpRequired(resp) {
+		credentials["totp"] = sc.Prompter.Prompt("Enter two-factor authentication code:")
+		if resp, err = sc.postCredentials(credentials); err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+	}
+	if resp.StatusCode >= 400 {
+		return errors.New("Logging in: " + resp.Status)
+	}
+	sc.loggedIn = true
+	return nil
+}
+
+func (sc *SessionClient) postCredentials(credentials map[string]string) (*http.Response, error) {
+	b, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	return sc.client.Post(sc.BaseURL+"/sessions", "application/json", bytes.NewBuffer(b))
+}
+
+// totpRequired returns true if logging in failed for want of a two-factor
+// code.
+func totpRequired(resp *http.Response) bool {
+	var p web.Problem
+	return json.NewDecoder(resp.Body).Decode(&p) == nil && p.Code == web.CodeTOTPRequired
+}
@@ -72,6 +72,10 @@ func NewConfigWithWSServer(wsserver *httptest.Server) *TestConfig {
 			EthGasBumpWei:       *big.NewInt(5000000000),
 			EthGasBumpThreshold: 3,
 			EthGasPriceDefault:  *big.NewInt(20000000000),
+			EthGasPriceMax:      *big.NewInt(500000000000),
+			MaxRequestBodySize:  65536,
+			RunLogMaxRuns:       1000,
+			RunLogMaxLines:      500,
 		},
 	}
 	config.SetEthereumServer(wsserver)
@@ -227,30 +231,46 @@ func AddPrivateKey(config *TestConfig, src string) {
 	copyFile(src, dst)
 }
 
-func BasicAuthPost(url string, contentType string, body io.Reader) *http.Response {
-	resp, err := utils.BasicAuthPost(
-		Username,
-		Password,
-		url,
-		contentType,
-		body)
+// NewSessionClient returns a client logged in to the node at the given
+// URL with the test operator's credentials.
+func NewSessionClient(rawURL string) *cmd.SessionClient {
+	u, err := url.Parse(rawURL)
+	mustNotErr(err)
+	return cmd.NewSessionClient(u.Scheme+"://"+u.Host, Username, Password)
+}
+
+func requestPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	mustNotErr(err)
+	return u.RequestURI()
+}
+
+func AuthenticatedPost(url string, body io.Reader) *http.Response {
+	resp, err := NewSessionClient(url).Post(requestPath(url), body)
 	mustNotErr(err)
 	return resp
 }
 
-func BasicAuthGet(url string) *http.Response {
-	resp, err := utils.BasicAuthGet(Username, Password, url)
+func AuthenticatedGet(url string) *http.Response {
+	resp, err := NewSessionClient(url).Get(requestPath(url))
 	mustNotErr(err)
 	return resp
 }
 
-func BasicAuthPatch(url string, contentType string, body io.Reader) *http.Response {
-	resp, err := utils.BasicAuthPatch(
-		Username,
-		Password,
-		url,
-		contentType,
-		body)
+func AuthenticatedPatch(url string, body io.Reader) *http.Response {
+	resp, err := NewSessionClient(url).Patch(requestPath(url), body)
+	mustNotErr(err)
+	return resp
+}
+
+func AuthenticatedPut(url string, body io.Reader) *http.Response {
+	resp, err := NewSessionClient(url).Put(requestPath(url), body)
+	mustNotErr(err)
+	return resp
+}
+
+func AuthenticatedDelete(url string) *http.Response {
+	resp, err := NewSessionClient(url).Delete(requestPath(url))
 	mustNotErr(err)
 	return resp
 }
@@ -269,9 +289,8 @@ func ObserveLogs() *observer.ObservedLogs {
 }
 
 func FixtureCreateJobViaWeb(t *testing.T, app *TestApplication, path string) models.Job {
-	resp := BasicAuthPost(
+	resp := AuthenticatedPost(
 		app.Server.URL+"/v2/jobs",
-		"application/json",
 		bytes.NewBuffer(LoadJSON(path)),
 	)
 	defer resp.Body.Close()
@@ -282,10 +301,21 @@ func FixtureCreateJobViaWeb(t *testing.T, app *TestApplication, path string) mod
 	return j
 }
 
+// SavedRunResult saves a job with a web initiator and a run of it, and
+// returns a result of the run, for adapters that look up the run's job.
+func SavedRunResult(t *testing.T, store *store.Store) models.RunResult {
+	t.Helper()
+	j := NewJobWithWebInitiator()
+	assert.Nil(t, store.SaveJob(&j))
+	jr := j.NewRun()
+	assert.Nil(t, store.Save(&jr))
+	return models.RunResult{JobRunID: jr.ID}
+}
+
 func CreateJobRunViaWeb(t *testing.T, app *TestApplication, j models.Job) models.JobRun {
 	t.Helper()
 	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
-	resp := BasicAuthPost(url, "application/json", &bytes.Buffer{})
+	resp := AuthenticatedPost(url, &bytes.Buffer{})
 	defer resp.Body.Close()
 	CheckStatusCode(t, resp, 200)
 	jrID := ParseCommonJSON(resp.Body).ID
@@ -309,7 +339,7 @@ func UpdateJobRunViaWeb(
 ) models.JobRun {
 	t.Helper()
 	url := app.Server.URL + "/v2/runs/" + jr.ID
-	resp := BasicAuthPatch(url, "application/json", bytes.NewBufferString(body))
+	resp := AuthenticatedPatch(url, bytes.NewBufferString(body))
 	defer resp.Body.Close()
 
 	CheckStatusCode(t, resp, 200)
@@ -323,9 +353,8 @@ func CreateBridgeTypeViaWeb(
 	app *TestApplication,
 	payload string,
 ) models.BridgeType {
-	resp := BasicAuthPost(
+	resp := AuthenticatedPost(
 		app.Server.URL+"/v2/bridge_types",
-		"application/json",
 		bytes.NewBufferString(payload),
 	)
 	defer resp.Body.Close()
@@ -375,6 +404,14 @@ func WaitForJobRunToPend(
 	return waitForJobRunInStatus(t, app, jr, models.StatusPending)
 }
 
+func WaitForJobRunToError(
+	t *testing.T,
+	app *TestApplication,
+	jr models.JobRun,
+) models.JobRun {
+	return waitForJobRunInStatus(t, app, jr, models.StatusErrored)
+}
+
 func waitForJobRunInStatus(
 	t *testing.T,
 	app *TestApplication,
@@ -27,7 +27,7 @@ func (ta *TestApplication) MockEthClient() *EthMock {
 
 func MockEthOnStore(s *store.Store) *EthMock {
 	mock := NewMockGethRpc()
-	eth := &store.EthClient{mock}
+	eth := &store.EthClient{CallerSubscriber: mock}
 	s.TxManager.EthClient = eth
 	return mock
 }
@@ -273,6 +273,10 @@ func (a *EmptyApplication) GetStore() *store.Store {
 	return nil
 }
 
+func (a *EmptyApplication) AddJob(job models.Job) error {
+	return nil
+}
+
 type CallbackAuthenticator struct {
 	Callback func(*store.Store, string)
 }
@@ -287,6 +291,14 @@ func (r EmptyRunner) Run(app services.Application) error {
 	return nil
 }
 
+type CallbackRunner struct {
+	Callback func(services.Application) error
+}
+
+func (r CallbackRunner) Run(app services.Application) error {
+	return r.Callback(app)
+}
+
 type MockCountingPrompt struct {
 	EnteredStrings []string
 	Count          int
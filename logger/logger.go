@@ -3,8 +3,11 @@
 package logger
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"path"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -12,6 +15,14 @@ import (
 
 var logger *Logger
 
+// root logs every entry its core is enabled for, before the node's level,
+// or a subsystem's, is applied.
+var root *zap.Logger
+
+// level is the node's log level, which SetLevel changes without replacing
+// the logger.
+var level = zap.NewAtomicLevel()
+
 func init() {
 	zl, err := zap.NewProduction()
 	if err != nil {
@@ -37,32 +48,139 @@ func NewLogger(zl *zap.Logger) *Logger {
 	return &Logger{zl.Sugar()}
 }
 
-// SetLogger sets the internal logger to the given input.
+// SetLogger sets the internal logger to the given input, keeping the lines
+// logged for each run to be read with LogsForRun. The node's level is reset
+// to debug, leaving the given logger to decide what is logged.
 func SetLogger(l *Logger) {
 	if logger != nil {
 		defer logger.Sync()
 	}
-	logger = l
-}
+	level.SetLevel(zapcore.DebugLevel)
+	root = l.Desugar().WithOptions(zap.WrapCore(runLogs.Wrap))
+	logger = NewLogger(root.WithOptions(zap.WrapCore(filterLevel(level))))
+	resetSubsystemLoggers()
+}
+
+// The formats the node can log to stderr in.
+const (
+	// FormatJSON logs each entry as a line of JSON, for log pipelines.
+	FormatJSON = "json"
+	// FormatConsole logs each entry as a line of tab separated text, for
+	// people to read.
+	FormatConsole = "console"
+)
 
-// Reconfigure creates a new log file at the configured directory
-// with the given LogLevel.
-func Reconfigure(dir string, lvl zapcore.Level) {
-	config := generateConfig(dir)
-	config.Level.SetLevel(lvl)
-	zl, err := config.Build(zap.AddCallerSkip(1))
+// Options are how the node logs: the format of its entries on stderr,
+// when its log file is rotated, and the remote collector, if any, its
+// entries are shipped to. The log file and collector are always sent JSON.
+type Options struct {
+	Format      string
+	MaxFileSize int64
+	MaxFileAge  time.Duration
+	MaxBackups  int
+	Sink        SinkOptions
+}
+
+// remoteSink is the sink of the logger last configured, if it ships to a
+// collector, to be closed when it is replaced.
+var remoteSink *RemoteSink
+
+// Reconfigure logs to stderr, to log.jsonl in the given directory, and to
+// the remote collector if one is set, at the given LogLevel. Its core logs
+// every level, so that subsystems can be set to log more than the rest of
+// the node.
+func Reconfigure(dir string, lvl zapcore.Level, opts Options) {
+	zl, sink, err := build(dir, opts)
 	if err != nil {
 		log.Fatal(err)
 	}
 	SetLogger(NewLogger(zl))
+	level.SetLevel(lvl)
+	if remoteSink != nil {
+		remoteSink.Close()
+	}
+	remoteSink = sink
+}
+
+// build returns a logger of every level to stderr in the given format,
+// and to the rotating log file and remote collector in JSON, sampled as
+// zap's production logger is.
+func build(dir string, opts Options) (*zap.Logger, *RemoteSink, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var console zapcore.Encoder
+	switch opts.Format {
+	case "", FormatJSON:
+		console = zapcore.NewJSONEncoder(encoderConfig)
+	case FormatConsole:
+		console = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, nil, fmt.Errorf("Unknown LOG_FORMAT %v, must be json or console", opts.Format)
+	}
+
+	file, err := OpenRotatingFile(path.Join(dir, "log.jsonl"), opts.MaxFileSize, opts.MaxFileAge, opts.MaxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+	stderr := zapcore.Lock(os.Stderr)
+	cores := []zapcore.Core{
+		zapcore.NewCore(console, stderr, zapcore.DebugLevel),
+		zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), file, zapcore.DebugLevel),
+	}
+	var sink *RemoteSink
+	if opts.Sink.URL != "" {
+		if sink, err = NewRemoteSink(opts.Sink); err != nil {
+			return nil, nil, err
+		}
+		cores = append(cores, sink.Core(zapcore.NewJSONEncoder(encoderConfig), zapcore.DebugLevel))
+	}
+	core := zapcore.NewSampler(zapcore.NewTee(cores...), time.Second, 100, 100)
+	return zap.New(
+		core,
+		zap.AddCaller(),
+		zap.AddCallerSkip(1),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.ErrorOutput(zapcore.NewMultiWriteSyncer(stderr, file)),
+	), sink, nil
+}
+
+// SetLevel changes the node's log level, so that it can be changed while
+// the node is running.
+func SetLevel(lvl zapcore.Level) {
+	level.SetLevel(lvl)
 }
 
-func generateConfig(dir string) zap.Config {
-	config := zap.NewProductionConfig()
-	destination := path.Join(dir, "log.jsonl")
-	config.OutputPaths = []string{"stderr", destination}
-	config.ErrorOutputPaths = []string{"stderr", destination}
-	return config
+// Level returns the node's log level.
+func Level() zapcore.Level {
+	return level.Level()
+}
+
+// filterLevel wraps a core so that it only logs the entries the enabler
+// allows, as well as those the core does.
+func filterLevel(enabler zapcore.LevelEnabler) func(zapcore.Core) zapcore.Core {
+	return func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, enabler: enabler}
+	}
+}
+
+type levelCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.enabler.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
 }
 
 // Infow logs an info message and any additional given information.
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files by when they were rotated, so that
+// they sort oldest first. It has no colons, which some filesystems forbid.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile is a log file that is moved aside, and a new one started,
+// once writing to it would take it over MaxSize bytes, or once it has been
+// open for MaxAge. Only the newest MaxBackups moved files are kept. A
+// MaxSize or MaxAge of 0 does not rotate the file by that measure.
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+	file       *os.File
+	size       int64
+	opened     time.Time
+	now        func() time.Time
+	mutex      sync.Mutex
+}
+
+// OpenRotatingFile opens the log file at path, appending to it if it
+// exists.
+func OpenRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		Path:       path,
+		MaxSize:    maxSize,
+		MaxAge:     maxAge,
+		MaxBackups: maxBackups,
+		now:        time.Now,
+	}
+	return rf, rf.open()
+}
+
+// Write writes the entry to the file, first rotating it if it is too big
+// or too old.
+func (rf *RotatingFile) Write(b []byte) (int, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	tooBig := rf.MaxSize > 0 && rf.size > 0 && rf.size+int64(len(b)) > rf.MaxSize
+	tooOld := rf.MaxAge > 0 && rf.now().Sub(rf.opened) >= rf.MaxAge
+	if tooBig || tooOld {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(b)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the file to disk.
+func (rf *RotatingFile) Sync() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	return rf.file.Sync()
+}
+
+// Close closes the file.
+func (rf *RotatingFile) Close() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	return rf.file.Close()
+}
+
+// Backups returns the paths of the rotated files kept, oldest first.
+func (rf *RotatingFile) Backups() ([]string, error) {
+	ext := filepath.Ext(rf.Path)
+	prefix := strings.TrimSuffix(rf.Path, ext) + "."
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	backups := []string{}
+	for _, m := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(m, prefix), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rf.file = file
+	rf.size = info.Size()
+	rf.opened = rf.now()
+	return nil
+}
+
+// rotate moves the file aside, opens a new one in its place, and deletes
+// the oldest rotated files over MaxBackups.
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(rf.Path)
+	backup := strings.TrimSuffix(rf.Path, ext) + "." + rf.now().UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(rf.Path, backup); err != nil {
+		return err
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	backups, err := rf.Backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > rf.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package logger_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile_Size(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "rotate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.jsonl")
+
+	rf, err := logger.OpenRotatingFile(path, 10, 0, 2)
+	assert.Nil(t, err)
+	defer rf.Close()
+
+	for _, entry := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := rf.Write([]byte(entry))
+		assert.Nil(t, err)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	current, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "fourth\n", string(current))
+
+	backups, err := rf.Backups()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(backups))
+	var contents []string
+	for _, b := range backups {
+		data, err := ioutil.ReadFile(b)
+		assert.Nil(t, err)
+		contents = append(contents, string(data))
+	}
+	assert.Equal(t, []string{"second\n", "third\n"}, contents)
+}
+
+func TestRotatingFile_Age(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "rotate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.jsonl")
+
+	rf, err := logger.OpenRotatingFile(path, 0, 5*time.Millisecond, 5)
+	assert.Nil(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("first\n"))
+	assert.Nil(t, err)
+	_, err = rf.Write([]byte("second\n"))
+	assert.Nil(t, err)
+	backups, err := rf.Backups()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(backups))
+
+	time.Sleep(10 * time.Millisecond)
+	_, err = rf.Write([]byte("third\n"))
+	assert.Nil(t, err)
+
+	current, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "third\n", string(current))
+	backups, err = rf.Backups()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(backups))
+}
+
+func TestRotatingFile_Appends(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "rotate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.jsonl")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("earlier\n"), 0600))
+
+	rf, err := logger.OpenRotatingFile(path, 12, 0, 1)
+	assert.Nil(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("later\n"))
+	assert.Nil(t, err)
+
+	current, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "later\n", string(current))
+	backups, err := rf.Backups()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(backups))
+}
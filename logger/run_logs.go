@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RunLogEntry is a line logged with the ID of a run.
+type RunLogEntry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// RunLogs keeps the lines logged for each run, found by their "run" field,
+// so that a single run's logs can be read without searching the node's
+// log. Only the newest lines of the newest runs are kept.
+type RunLogs struct {
+	mutex    sync.Mutex
+	maxRuns  int
+	maxLines int
+	lines    map[string][]RunLogEntry
+	order    []string
+}
+
+// NewRunLogs returns RunLogs keeping up to maxLines lines for each of the
+// maxRuns runs most recently logged about.
+func NewRunLogs(maxRuns, maxLines int) *RunLogs {
+	return &RunLogs{
+		maxRuns:  maxRuns,
+		maxLines: maxLines,
+		lines:    map[string][]RunLogEntry{},
+	}
+}
+
+var runLogs = NewRunLogs(1000, 500)
+
+// SetRunLogLimits changes how many runs, and lines of each run, are kept.
+func SetRunLogLimits(maxRuns, maxLines int) {
+	runLogs.SetLimits(maxRuns, maxLines)
+}
+
+// LogsForRun returns the kept lines logged for the run, oldest first.
+func LogsForRun(runID string) []RunLogEntry {
+	return runLogs.For(runID)
+}
+
+// SetLimits changes how many runs, and lines of each run, are kept,
+// dropping those over the new limits.
+func (rl *RunLogs) SetLimits(maxRuns, maxLines int) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	rl.maxRuns = maxRuns
+	rl.maxLines = maxLines
+	for id, lines := range rl.lines {
+		rl.lines[id] = rl.trim(lines)
+	}
+	rl.evict()
+}
+
+// For returns the kept lines logged for the run, oldest first.
+func (rl *RunLogs) For(runID string) []RunLogEntry {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	return append([]RunLogEntry{}, rl.lines[runID]...)
+}
+
+// Add keeps the line for the run.
+func (rl *RunLogs) Add(runID string, entry RunLogEntry) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	if rl.maxRuns <= 0 || rl.maxLines <= 0 {
+		return
+	}
+	lines, ok := rl.lines[runID]
+	if !ok {
+		rl.order = append(rl.order, runID)
+	}
+	rl.lines[runID] = rl.trim(append(lines, entry))
+	rl.evict()
+}
+
+func (rl *RunLogs) trim(lines []RunLogEntry) []RunLogEntry {
+	if len(lines) > rl.maxLines {
+		return append([]RunLogEntry{}, lines[len(lines)-rl.maxLines:]...)
+	}
+	return lines
+}
+
+func (rl *RunLogs) evict() {
+	for len(rl.order) > rl.maxRuns && len(rl.order) > 0 {
+		delete(rl.lines, rl.order[0])
+		rl.order = rl.order[1:]
+	}
+}
+
+// Wrap returns a core that writes to the given core, and also keeps the
+// entries logged with a run's ID.
+func (rl *RunLogs) Wrap(core zapcore.Core) zapcore.Core {
+	return zapcore.NewTee(core, &runLogCore{LevelEnabler: core, logs: rl})
+}
+
+// runLogCore is a zapcore.Core adding entries with a "run" field to its
+// RunLogs.
+type runLogCore struct {
+	zapcore.LevelEnabler
+	logs   *RunLogs
+	fields []zapcore.Field
+}
+
+func (c *runLogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &runLogCore{
+		LevelEnabler: c.LevelEnabler,
+		logs:         c.logs,
+		fields:       append(append([]zapcore.Field{}, c.fields...), fields...),
+	}
+}
+
+func (c *runLogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *runLogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	runID, ok := enc.Fields["run"].(string)
+	if !ok || runID == "" {
+		return nil
+	}
+	c.logs.Add(runID, RunLogEntry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		Fields:  enc.Fields,
+	})
+	return nil
+}
+
+func (c *runLogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,437 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The kinds of collector a RemoteSink can ship entries to.
+const (
+	// SinkHTTP POSTs each batch as a JSON array of entries.
+	SinkHTTP = "http"
+	// SinkLoki pushes each batch to Loki's push API, in a stream per level.
+	SinkLoki = "loki"
+	// SinkSyslog sends each entry as an RFC 5424 message over udp or tcp.
+	SinkSyslog = "syslog"
+)
+
+// sinkRetryMax is the longest a RemoteSink waits before retrying a
+// collector that is failing.
+const sinkRetryMax = time.Minute
+
+// sinkSyncTimeout is the longest Sync waits for pending entries to be
+// shipped, so that a collector that is down does not hang the node.
+const sinkSyncTimeout = 5 * time.Second
+
+// SinkOptions are where a RemoteSink ships entries, and how many at once.
+type SinkOptions struct {
+	Type        string
+	URL         string
+	BatchSize   int
+	FlushPeriod time.Duration
+	MaxPending  int
+}
+
+type sinkEntry struct {
+	time  time.Time
+	level zapcore.Level
+	line  []byte
+}
+
+// RemoteSink ships log entries as JSON to a remote collector, in batches
+// of up to BatchSize, or every FlushPeriod if fewer are waiting. Logging
+// never waits on the collector: when MaxPending entries are waiting to be
+// shipped, such as while it is down, new entries are dropped, and how many
+// were is logged to it once it catches up.
+type RemoteSink struct {
+	opts     SinkOptions
+	send     func([]sinkEntry) error
+	close    func()
+	entries  chan sinkEntry
+	flushes  chan chan error
+	done     chan struct{}
+	stopped  chan struct{}
+	dropped  uint64
+	total    uint64
+	stopOnce sync.Once
+}
+
+// NewRemoteSink returns a RemoteSink shipping to the collector at the
+// URL, which starts shipping straight away.
+func NewRemoteSink(opts SinkOptions) (*RemoteSink, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushPeriod <= 0 {
+		opts.FlushPeriod = time.Second
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = 10000
+	}
+	rs := &RemoteSink{
+		opts:    opts,
+		close:   func() {},
+		entries: make(chan sinkEntry, opts.MaxPending),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	switch opts.Type {
+	case "", SinkHTTP:
+		rs.send = newHTTPSender(opts.URL)
+	case SinkLoki:
+		rs.send = newLokiSender(opts.URL)
+	case SinkSyslog:
+		syslog, err := newSyslogSender(opts.URL)
+		if err != nil {
+			return nil, err
+		}
+		rs.send, rs.close = syslog.send, syslog.reset
+	default:
+		return nil, fmt.Errorf("Unknown LOG_SINK_TYPE %v, must be http, loki, or syslog", opts.Type)
+	}
+
+	go rs.run()
+	return rs, nil
+}
+
+// Core returns a core encoding the entries it is enabled for with the
+// encoder, and shipping them with the sink.
+func (rs *RemoteSink) Core(enc zapcore.Encoder, enab zapcore.LevelEnabler) zapcore.Core {
+	return &sinkCore{LevelEnabler: enab, enc: enc, sink: rs}
+}
+
+// Dropped returns how many entries have been dropped because too many
+// were waiting to be shipped.
+func (rs *RemoteSink) Dropped() uint64 {
+	return atomic.LoadUint64(&rs.total)
+}
+
+// Sync ships the entries waiting, giving up after a few seconds if the
+// collector is not taking them.
+func (rs *RemoteSink) Sync() error {
+	result := make(chan error, 1)
+	select {
+	case rs.flushes <- result:
+	case <-rs.stopped:
+		return nil
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(sinkSyncTimeout):
+		return fmt.Errorf("Timed out shipping logs to %v", rs.opts.URL)
+	}
+}
+
+// Close ships the entries waiting, once, and stops the sink.
+func (rs *RemoteSink) Close() {
+	rs.stopOnce.Do(func() {
+		close(rs.done)
+		select {
+		case <-rs.stopped:
+		case <-time.After(sinkSyncTimeout):
+		}
+		rs.close()
+	})
+}
+
+func (rs *RemoteSink) enqueue(e sinkEntry) {
+	select {
+	case rs.entries <- e:
+	default:
+		atomic.AddUint64(&rs.dropped, 1)
+		atomic.AddUint64(&rs.total, 1)
+	}
+}
+
+// run batches the entries and ships them. While the collector is failing,
+// it stops taking entries, so that they wait in the queue, and are dropped
+// once it is full, until a retry succeeds.
+func (rs *RemoteSink) run() {
+	defer close(rs.stopped)
+	ticker := time.NewTicker(rs.opts.FlushPeriod)
+	defer ticker.Stop()
+
+	var batch []sinkEntry
+	var err error
+	entries := rs.entries
+	var retry <-chan time.Time
+	backoff := time.Duration(0)
+	ship := func() {
+		failing := entries == nil
+		batch, err = rs.ship(batch)
+		if err == nil {
+			entries, retry, backoff = rs.entries, nil, 0
+			if failing {
+				fmt.Fprintf(os.Stderr, "%v Shipping logs to %v resumed\n", time.Now().Format(time.RFC3339), rs.opts.Type)
+			}
+			return
+		}
+		if !failing {
+			fmt.Fprintf(os.Stderr, "%v Shipping logs to %v: %v\n", time.Now().Format(time.RFC3339), rs.opts.Type, err)
+		}
+		backoff = nextBackoff(backoff)
+		entries, retry = nil, time.After(backoff)
+	}
+
+	for {
+		select {
+		case <-rs.done:
+			batch = rs.drain(batch)
+			rs.ship(batch)
+			return
+		case e := <-entries:
+			batch = append(batch, e)
+			if len(batch) >= rs.opts.BatchSize {
+				ship()
+			}
+		case <-ticker.C:
+			if entries != nil && (len(batch) > 0 || atomic.LoadUint64(&rs.dropped) > 0) {
+				ship()
+			}
+		case <-retry:
+			ship()
+		case result := <-rs.flushes:
+			batch = rs.drain(batch)
+			ship()
+			result <- err
+		}
+	}
+}
+
+// drain adds the entries waiting in the queue to the batch.
+func (rs *RemoteSink) drain(batch []sinkEntry) []sinkEntry {
+	for {
+		select {
+		case e := <-rs.entries:
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+// ship sends the batch in chunks of BatchSize, noting any entries that
+// were dropped, and returns the entries it could not send.
+func (rs *RemoteSink) ship(batch []sinkEntry) ([]sinkEntry, error) {
+	if dropped := atomic.SwapUint64(&rs.dropped, 0); dropped > 0 {
+		batch = append(batch, droppedEntry(dropped))
+	}
+	for len(batch) > 0 {
+		n := rs.opts.BatchSize
+		if n > len(batch) {
+			n = len(batch)
+		}
+		if err := rs.send(batch[:n]); err != nil {
+			return batch, err
+		}
+		batch = batch[n:]
+	}
+	return nil, nil
+}
+
+func nextBackoff(retry time.Duration) time.Duration {
+	if retry < time.Second {
+		return time.Second
+	}
+	if retry*2 > sinkRetryMax {
+		return sinkRetryMax
+	}
+	return retry * 2
+}
+
+// droppedEntry is the entry logged to the collector for the entries that
+// were dropped since it last took any.
+func droppedEntry(dropped uint64) sinkEntry {
+	now := time.Now()
+	line, _ := json.Marshal(map[string]interface{}{
+		"level":   "warn",
+		"ts":      now.Format("2006-01-02T15:04:05.000Z0700"),
+		"msg":     "Dropped log entries while too many were waiting to be shipped",
+		"dropped": dropped,
+	})
+	return sinkEntry{time: now, level: zapcore.WarnLevel, line: line}
+}
+
+// sinkCore is a zapcore.Core encoding entries for a RemoteSink.
+type sinkCore struct {
+	zapcore.LevelEnabler
+	enc  zapcore.Encoder
+	sink *RemoteSink
+}
+
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &sinkCore{LevelEnabler: c.LevelEnabler, enc: enc, sink: c.sink}
+}
+
+func (c *sinkCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *sinkCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	line := append([]byte{}, bytes.TrimRight(buf.Bytes(), "\n")...)
+	buf.Free()
+	c.sink.enqueue(sinkEntry{time: entry.Time, level: entry.Level, line: line})
+	return nil
+}
+
+func (c *sinkCore) Sync() error {
+	return c.sink.Sync()
+}
+
+var sinkClient = &http.Client{Timeout: 30 * time.Second}
+
+func newHTTPSender(url string) func([]sinkEntry) error {
+	return func(entries []sinkEntry) error {
+		lines := make([][]byte, len(entries))
+		for i, e := range entries {
+			lines[i] = e.line
+		}
+		body := append(append([]byte("["), bytes.Join(lines, []byte(","))...), ']')
+		return post(url, body)
+	}
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// newLokiSender pushes entries to Loki in a stream per level, labelled
+// with the app and level, appending the push API's path to the URL if it
+// is not already there.
+func newLokiSender(url string) func([]sinkEntry) error {
+	if !strings.Contains(url, "/loki/api/") {
+		url = strings.TrimRight(url, "/") + "/loki/api/v1/push"
+	}
+	return func(entries []sinkEntry) error {
+		streams := []*lokiStream{}
+		byLevel := map[zapcore.Level]*lokiStream{}
+		for _, e := range entries {
+			s, ok := byLevel[e.level]
+			if !ok {
+				s = &lokiStream{Stream: map[string]string{"app": "chainlink", "level": e.level.String()}}
+				byLevel[e.level] = s
+				streams = append(streams, s)
+			}
+			ts := strconv.FormatInt(e.time.UnixNano(), 10)
+			s.Values = append(s.Values, [2]string{ts, string(e.line)})
+		}
+		body, err := json.Marshal(map[string]interface{}{"streams": streams})
+		if err != nil {
+			return err
+		}
+		return post(url, body)
+	}
+}
+
+func post(url string, body []byte) error {
+	resp, err := sinkClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Log sink responded %v: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// syslogSender sends entries to a syslog server at a udp:// or tcp://
+// URL, dialing it again after it fails.
+type syslogSender struct {
+	network  string
+	address  string
+	hostname string
+	conn     net.Conn
+}
+
+func newSyslogSender(rawURL string) (*syslogSender, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "udp" && u.Scheme != "tcp" {
+		return nil, fmt.Errorf("Syslog LOG_SINK_URL must be udp:// or tcp://, not %v", rawURL)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	return &syslogSender{network: u.Scheme, address: u.Host, hostname: hostname}, nil
+}
+
+func (ss *syslogSender) send(entries []sinkEntry) error {
+	if ss.conn == nil {
+		conn, err := net.DialTimeout(ss.network, ss.address, 30*time.Second)
+		if err != nil {
+			return err
+		}
+		ss.conn = conn
+	}
+	for _, e := range entries {
+		msg := fmt.Sprintf("<%d>1 %s %s chainlink %d - - %s\n",
+			8+syslogSeverity(e.level),
+			e.time.UTC().Format(time.RFC3339Nano),
+			ss.hostname,
+			os.Getpid(),
+			e.line)
+		if _, err := ss.conn.Write([]byte(msg)); err != nil {
+			ss.reset()
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *syslogSender) reset() {
+	if ss.conn != nil {
+		ss.conn.Close()
+		ss.conn = nil
+	}
+}
+
+// syslogSeverity returns the syslog severity of the level, to be logged
+// with the user facility.
+func syslogSeverity(lvl zapcore.Level) int {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,159 @@
+package logger_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newSinkLogger(sink *logger.RemoteSink) *zap.Logger {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zap.New(sink.Core(enc, zapcore.DebugLevel))
+}
+
+func TestRemoteSink_HTTP(t *testing.T) {
+	t.Parallel()
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies <- b
+	}))
+	defer server.Close()
+
+	sink, err := logger.NewRemoteSink(logger.SinkOptions{URL: server.URL, BatchSize: 2, FlushPeriod: time.Hour})
+	assert.Nil(t, err)
+	defer sink.Close()
+	zl := newSinkLogger(sink)
+
+	zl.Info("first", zap.String("job", "1"))
+	zl.Warn("second")
+
+	var entries []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(<-bodies, &entries))
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "first", entries[0]["msg"])
+	assert.Equal(t, "1", entries[0]["job"])
+	assert.Equal(t, "warn", entries[1]["level"])
+
+	zl.Info("third")
+	assert.Nil(t, sink.Sync())
+	assert.Nil(t, json.Unmarshal(<-bodies, &entries))
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "third", entries[0]["msg"])
+}
+
+func TestRemoteSink_Loki(t *testing.T) {
+	t.Parallel()
+	paths := make(chan string, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		paths <- r.URL.Path
+		bodies <- b
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	sink, err := logger.NewRemoteSink(logger.SinkOptions{Type: logger.SinkLoki, URL: server.URL, FlushPeriod: time.Hour})
+	assert.Nil(t, err)
+	defer sink.Close()
+	zl := newSinkLogger(sink)
+
+	zl.Info("first")
+	zl.Error("second")
+	zl.Info("third")
+	assert.Nil(t, sink.Sync())
+
+	assert.Equal(t, "/loki/api/v1/push", <-paths)
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	assert.Nil(t, json.Unmarshal(<-bodies, &push))
+	assert.Equal(t, 2, len(push.Streams))
+	assert.Equal(t, map[string]string{"app": "chainlink", "level": "info"}, push.Streams[0].Stream)
+	assert.Equal(t, 2, len(push.Streams[0].Values))
+	assert.Contains(t, push.Streams[0].Values[1][1], `"msg":"third"`)
+	assert.Equal(t, "error", push.Streams[1].Stream["level"])
+}
+
+func TestRemoteSink_Syslog(t *testing.T) {
+	t.Parallel()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	sink, err := logger.NewRemoteSink(logger.SinkOptions{
+		Type:        logger.SinkSyslog,
+		URL:         "udp://" + conn.LocalAddr().String(),
+		FlushPeriod: time.Hour,
+	})
+	assert.Nil(t, err)
+	defer sink.Close()
+
+	newSinkLogger(sink).Warn("shipped")
+	assert.Nil(t, sink.Sync())
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<12>1 "), msg)
+	assert.Contains(t, msg, " chainlink ")
+	assert.Contains(t, msg, `"msg":"shipped"`)
+}
+
+func TestRemoteSink_DropsWhenFull(t *testing.T) {
+	t.Parallel()
+	requests := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	sink, err := logger.NewRemoteSink(logger.SinkOptions{URL: server.URL, BatchSize: 1, MaxPending: 2})
+	assert.Nil(t, err)
+	defer sink.Close()
+	zl := newSinkLogger(sink)
+
+	zl.Info("failing")
+	<-requests
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		zl.Info("waiting")
+	}
+
+	assert.Equal(t, uint64(3), sink.Dropped())
+}
+
+func TestNewRemoteSink_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts logger.SinkOptions
+	}{
+		{"unknown type", logger.SinkOptions{Type: "kafka", URL: "http://localhost"}},
+		{"syslog over http", logger.SinkOptions{Type: logger.SinkSyslog, URL: "http://localhost:514"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := logger.NewRemoteSink(test.opts)
+			assert.NotNil(t, err)
+		})
+	}
+}
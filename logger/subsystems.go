@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Subsystem is a part of the node whose log level can be set apart from the
+// node's, so that it can be debugged without the debug logs of the rest.
+// Its entries are logged with its name as the logger.
+type Subsystem string
+
+const (
+	// Scheduler logs the starting of runs for cron and run at initiators.
+	Scheduler Subsystem = "scheduler"
+	// Subscription logs the Ethereum log and head subscriptions.
+	Subscription Subsystem = "subscription"
+	// TxManager logs the sending and confirming of transactions.
+	TxManager Subsystem = "txmanager"
+	// Web logs the API's requests.
+	Web Subsystem = "web"
+)
+
+// Subsystems are every Subsystem, in order of their names.
+var Subsystems = []Subsystem{Scheduler, Subscription, TxManager, Web}
+
+var subsystems = struct {
+	sync.RWMutex
+	levels  map[Subsystem]zapcore.Level
+	loggers map[Subsystem]*Logger
+}{
+	levels:  map[Subsystem]zapcore.Level{},
+	loggers: map[Subsystem]*Logger{},
+}
+
+// ParseSubsystem returns the Subsystem with the given name.
+func ParseSubsystem(name string) (Subsystem, error) {
+	for _, s := range Subsystems {
+		if string(s) == name {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("Unknown log subsystem %v", name)
+}
+
+// SetSubsystemLevel sets the subsystem's log level, or if it is nil, has
+// the subsystem log at the node's level again.
+func SetSubsystemLevel(s Subsystem, lvl *zapcore.Level) {
+	subsystems.Lock()
+	defer subsystems.Unlock()
+	if lvl == nil {
+		delete(subsystems.levels, s)
+	} else {
+		subsystems.levels[s] = *lvl
+	}
+}
+
+// SubsystemLevels returns the level of each subsystem whose level is set
+// apart from the node's.
+func SubsystemLevels() map[Subsystem]zapcore.Level {
+	subsystems.RLock()
+	defer subsystems.RUnlock()
+	levels := map[Subsystem]zapcore.Level{}
+	for s, lvl := range subsystems.levels {
+		levels[s] = lvl
+	}
+	return levels
+}
+
+// Enabled returns true if the subsystem logs entries of the given level.
+func (s Subsystem) Enabled(lvl zapcore.Level) bool {
+	subsystems.RLock()
+	own, ok := subsystems.levels[s]
+	subsystems.RUnlock()
+	if ok {
+		return own.Enabled(lvl)
+	}
+	return level.Enabled(lvl)
+}
+
+func (s Subsystem) logger() *Logger {
+	subsystems.RLock()
+	l, ok := subsystems.loggers[s]
+	subsystems.RUnlock()
+	if ok {
+		return l
+	}
+
+	subsystems.Lock()
+	defer subsystems.Unlock()
+	if l, ok = subsystems.loggers[s]; !ok {
+		l = NewLogger(root.Named(string(s)).WithOptions(zap.WrapCore(filterLevel(s))))
+		subsystems.loggers[s] = l
+	}
+	return l
+}
+
+// resetSubsystemLoggers has the subsystems log with the current root.
+func resetSubsystemLoggers() {
+	subsystems.Lock()
+	defer subsystems.Unlock()
+	subsystems.loggers = map[Subsystem]*Logger{}
+}
+
+// Infow logs an info message and any additional given information.
+func (s Subsystem) Infow(msg string, keysAndValues ...interface{}) {
+	s.logger().Infow(msg, keysAndValues...)
+}
+
+// Debugw logs a debug message and any additional given information.
+func (s Subsystem) Debugw(msg string, keysAndValues ...interface{}) {
+	s.logger().Debugw(msg, keysAndValues...)
+}
+
+// Warnw logs a warn message and any additional given information.
+func (s Subsystem) Warnw(msg string, keysAndValues ...interface{}) {
+	s.logger().Warnw(msg, keysAndValues...)
+}
+
+// Errorw logs an error message and any additional given information.
+func (s Subsystem) Errorw(msg string, keysAndValues ...interface{}) {
+	s.logger().Errorw(msg, keysAndValues...)
+}
+
+// Warn logs a message at the warn level.
+func (s Subsystem) Warn(args ...interface{}) {
+	s.logger().Warn(args...)
+}
+
+// Error logs an error message.
+func (s Subsystem) Error(args ...interface{}) {
+	s.logger().Error(args...)
+}
@@ -37,19 +37,198 @@ func Run(client *cmd.Client, args ...string) {
 					Name:  "password, p",
 					Usage: "password for the node's account",
 				},
+				cli.StringFlag{
+					Name:  "password-file",
+					Usage: "file holding the password for the node's account, readable only by its owner",
+				},
 				cli.BoolFlag{
 					Name:  "debug, d",
 					Usage: "set logger level to debug",
 				},
+				cli.BoolFlag{
+					Name:  "migrate-only",
+					Usage: "apply pending store migrations and exit without starting the node",
+				},
 			},
 			Usage:  "Run the chainlink node",
 			Action: client.RunNode,
 		},
+		{
+			Name: "dev",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "password, p",
+					Usage: "password for the node's account",
+				},
+				cli.StringFlag{
+					Name:  "password-file",
+					Usage: "file holding the password for the node's account, readable only by its owner",
+				},
+				cli.BoolFlag{
+					Name:  "debug, d",
+					Usage: "set logger level to debug",
+				},
+			},
+			Usage:  "Run the chainlink node against a running local development chain, funding its account, deploying LINK and Oracle, and creating a sample job",
+			Action: client.RunDev,
+		},
+		{
+			Name:  "admin",
+			Usage: "Commands for node operators",
+			Subcommands: []cli.Command{
+				{
+					Name:   "smoke-test",
+					Usage:  "Run a temporary job on the node and check that it completes",
+					Action: client.SmokeTest,
+				},
+				{
+					Name:      "rollback",
+					Usage:     "Undo store migrations newer than the given version, while the node is stopped",
+					ArgsUsage: "<version>",
+					Action:    client.Rollback,
+				},
+				{
+					Name: "prune",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "age",
+							Usage: "delete finished runs older than this duration, such as 720h",
+						},
+						cli.IntFlag{
+							Name:  "keep",
+							Usage: "delete finished runs beyond the newest this many of each job",
+						},
+					},
+					Usage:  "Delete finished runs now, by default per the node's run retention settings",
+					Action: client.PruneRuns,
+				},
+			},
+		},
+		{
+			Name:  "keys",
+			Usage: "Commands for moving keys between nodes",
+			Subcommands: []cli.Command{
+				{
+					Name: "export",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "address", Usage: "account to export, instead of the node's"},
+						cli.StringFlag{Name: "output, o", Usage: "file to write the encrypted key to"},
+					},
+					Usage:  "Export a key from the keystore as geth compatible encrypted JSON",
+					Action: client.ExportKey,
+				},
+				{
+					Name:      "import",
+					Usage:     "Import a key from geth compatible encrypted JSON into the keystore",
+					ArgsUsage: "<file>",
+					Action:    client.ImportKey,
+				},
+			},
+		},
+		{
+			Name:  "txs",
+			Usage: "Commands for signing transactions offline",
+			Subcommands: []cli.Command{
+				{
+					Name: "build",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "to", Usage: "address the transaction is sent to"},
+						cli.StringFlag{Name: "data", Usage: "hex encoded data of the transaction"},
+						cli.StringFlag{Name: "value", Usage: "wei sent with the transaction"},
+						cli.Uint64Flag{Name: "gas-limit", Usage: "gas limit of the transaction, instead of the default"},
+						cli.StringFlag{Name: "from", Usage: "account that will sign the transaction, instead of the node's"},
+						cli.StringFlag{Name: "output, o", Usage: "file to write the unsigned transaction to"},
+					},
+					Usage:  "Have the node build a transaction to sign offline",
+					Action: client.BuildTx,
+				},
+				{
+					Name: "sign",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "unsigned", Usage: "file holding the unsigned transaction"},
+						cli.StringFlag{Name: "password, p", Usage: "password for the account in the keystore"},
+						cli.StringFlag{Name: "password-file", Usage: "file holding the password for the account in the keystore"},
+						cli.StringFlag{Name: "output, o", Usage: "file to write the signed transaction to"},
+					},
+					Usage:  "Sign a transaction with the keystore, without connecting to the node",
+					Action: client.SignTx,
+				},
+				{
+					Name: "broadcast",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "signed", Usage: "file holding the signed transaction"},
+					},
+					Usage:  "Have the node send a transaction signed offline",
+					Action: client.BroadcastTx,
+				},
+			},
+		},
+		{
+			Name:  "config",
+			Usage: "Commands for the node's configuration",
+			Subcommands: []cli.Command{
+				{
+					Name:   "show",
+					Usage:  "Show the effective configuration, from defaults, the config file, and the environment",
+					Action: client.ShowConfig,
+				},
+			},
+		},
+		{
+			Name:   "balances",
+			Usage:  "Show the ether and LINK balances of the node's account",
+			Action: client.ShowBalances,
+		},
 		{
 			Name:    "jobs",
 			Aliases: []string{"j"},
 			Usage:   "Get all jobs",
 			Action:  client.GetJobs,
+			Subcommands: []cli.Command{
+				{
+					Name: "export",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "format, f", Usage: "json or yaml, by default yaml if the output file ends in .yaml or .yml"},
+						cli.StringFlag{Name: "output, o", Usage: "file to write the job bundle to"},
+					},
+					Usage:  "Export the specs of the node's jobs as a bundle",
+					Action: client.ExportJobs,
+				},
+				{
+					Name: "import",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "format, f", Usage: "json or yaml, by default yaml if the file ends in .yaml or .yml"},
+					},
+					Usage:     "Create the jobs in a bundle, skipping those the node already has",
+					ArgsUsage: "<file>",
+					Action:    client.ImportJobs,
+				},
+			},
+		},
+		{
+			Name:      "pause",
+			Usage:     "Stop a job from starting new runs",
+			ArgsUsage: "<jobID>",
+			Action:    client.PauseJob,
+		},
+		{
+			Name: "resume",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "replay",
+					Usage: "start the runs skipped for log events while the job was paused",
+				},
+			},
+			Usage:     "Let a paused job start new runs again",
+			ArgsUsage: "<jobID>",
+			Action:    client.ResumeJob,
+		},
+		{
+			Name:      "run",
+			Aliases:   []string{"r"},
+			Usage:     "Start a run of a job, with an optional JSON object as its input",
+			ArgsUsage: "<jobID> [input]",
+			Action:    client.CreateJobRun,
 		},
 		{
 			Name:    "show",
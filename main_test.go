@@ -32,7 +32,12 @@ func ExampleRun_Help() {
 	//
 	// COMMANDS:
 	//      node, n  Run the chainlink node
+	//      dev      Run the chainlink node against a local development chain
+	//      admin    Commands for node operators
 	//      jobs, j  Get all jobs
+	//      pause    Stop a job from starting new runs
+	//      resume   Let a paused job start new runs again
+	//      run, r   Start a run of a job, with an optional JSON object as its input
 	//      show, s  Show a specific job
 	//      help, h  Shows a list of commands or help for one command
 	//
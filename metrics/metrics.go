@@ -0,0 +1,477 @@
+// Package metrics keeps counters, gauges, and histograms of what the node
+// is doing, and writes them in the Prometheus text format for scraping.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var registry = &Registry{}
+
+// The node's metrics, labelled by the names given to each.
+var (
+	LogsReceived = NewCounter(
+		"chainlink_logs_received_total",
+		"Logs received from ethereum log subscriptions.",
+		"job_id",
+	)
+	RunsStarted = NewCounter(
+		"chainlink_runs_started_total",
+		"Job runs started.",
+		"job_id",
+	)
+	RunsCompleted = NewCounter(
+		"chainlink_runs_completed_total",
+		"Job runs that completed every task.",
+		"job_id",
+	)
+	RunsErrored = NewCounter(
+		"chainlink_runs_errored_total",
+		"Job runs that errored.",
+		"job_id",
+	)
+	TaskDuration = NewHistogram(
+		"chainlink_task_duration_seconds",
+		"How long each task took to perform.",
+		[]float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+		"task_type",
+	)
+	TxsBroadcast = NewCounter(
+		"chainlink_txs_broadcast_total",
+		"Transaction attempts sent to the ethereum node.",
+	)
+	TxBroadcastDuration = NewHistogram(
+		"chainlink_tx_broadcast_duration_seconds",
+		"How long the ethereum node took to accept a transaction.",
+		[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	)
+	TxConfirmationBlocks = NewHistogram(
+		"chainlink_tx_confirmation_blocks",
+		"Blocks from sending a transaction attempt to it being confirmed.",
+		[]float64{1, 2, 5, 10, 20, 50, 100},
+	)
+	EthBalance = NewGauge(
+		"chainlink_eth_balance",
+		"ETH balance of the node's account.",
+		"address",
+	)
+	LinkBalance = NewGauge(
+		"chainlink_link_balance",
+		"LINK balance of the node's account.",
+		"address",
+	)
+	ActiveSubscriptions = NewGauge(
+		"chainlink_active_subscriptions",
+		"Log subscriptions open to the ethereum node.",
+	)
+	EthRPCCalls = NewCounter(
+		"chainlink_eth_rpc_calls_total",
+		"RPC calls made to the ethereum node, including each call of a batch.",
+		"method",
+	)
+	EthRPCWeight = NewCounter(
+		"chainlink_eth_rpc_weight_total",
+		"Weight of the RPC calls made to the ethereum node, as counted against ETH_RATE_LIMIT.",
+		"method",
+	)
+	EthRPCThrottled = NewCounter(
+		"chainlink_eth_rpc_throttled_total",
+		"RPC calls held back to stay under ETH_RATE_LIMIT.",
+		"method",
+	)
+	EthRPCRateLimited = NewCounter(
+		"chainlink_eth_rpc_rate_limited_total",
+		"RPC calls the ethereum node refused for going over its rate limit.",
+		"method",
+	)
+	RunsQueued = NewGauge(
+		"chainlink_runs_queued",
+		"Runs waiting for a worker to execute them.",
+		"job_id",
+	)
+	OldestQueuedRunAge = NewGauge(
+		"chainlink_oldest_queued_run_seconds",
+		"How long the oldest queued run has waited for a worker.",
+	)
+	RunWorkersBusy = NewGauge(
+		"chainlink_run_workers_busy",
+		"Runs executing at once.",
+	)
+	RunWorkerUtilization = NewGauge(
+		"chainlink_run_worker_utilization",
+		"Fraction of MAX_CONCURRENT_RUNS workers busy, or 0 without a cap.",
+	)
+	FeedAnswer = NewGauge(
+		"chainlink_feed_answer",
+		"Latest answer reported for each feed.",
+		"feed_id",
+	)
+	FeedReports = NewCounter(
+		"chainlink_feed_reports_total",
+		"Answers reported to feed contracts.",
+		"feed_id",
+	)
+	FeedFreshSources = NewGauge(
+		"chainlink_feed_fresh_sources",
+		"Jobs of each feed with a result that is not stale.",
+		"feed_id",
+	)
+	AdapterCacheHits = NewCounter(
+		"chainlink_adapter_cache_hits_total",
+		"HttpGet and HttpPost tasks answered from the response cache.",
+	)
+	AdapterCacheMisses = NewCounter(
+		"chainlink_adapter_cache_misses_total",
+		"HttpGet and HttpPost tasks with a cache TTL that had to make their request.",
+	)
+	// DriftItems is how many of the node's jobs, bridges, keys, and config
+	// differ from its manifest, as of the last check.
+	DriftItems = NewGauge(
+		"chainlink_drift_items",
+		"Differences between the node's state and its manifest, by kind.",
+		"kind",
+	)
+)
+
+// Registry holds metrics in the order they are to be written.
+type Registry struct {
+	metrics []metric
+	mutex   sync.Mutex
+}
+
+type metric interface {
+	write(w io.Writer) error
+}
+
+func (r *Registry) register(m metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// Write writes every metric in the Prometheus text format.
+func Write(w io.Writer) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for _, m := range registry.metrics {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// series holds a value for each combination of label values.
+type series struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+	values map[string]float64
+	mutex  sync.Mutex
+}
+
+func newSeries(name, help, kind string, labels []string) *series {
+	return &series{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: map[string]float64{},
+	}
+}
+
+func (s *series) update(labelValues []string, f func(float64) float64) {
+	key := labelKey(s.name, s.labels, labelValues)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values[key] = f(s.values[key])
+}
+
+// updateBounded updates the series like update, unless it would take the
+// number of label combinations over max.
+func (s *series) updateBounded(max int, labelValues []string, f func(float64) float64) error {
+	key := labelKey(s.name, s.labels, labelValues)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.values[key]; !ok && len(s.values) >= max {
+		return fmt.Errorf("metrics: %v already has %v label combinations", s.name, max)
+	}
+	s.values[key] = f(s.values[key])
+	return nil
+}
+
+func (s *series) write(w io.Writer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", s.name, s.help, s.name, s.kind); err != nil {
+		return err
+	}
+	if len(s.labels) == 0 && len(s.values) == 0 {
+		s.values[""] = 0
+	}
+	for _, key := range sortedKeys(s.values) {
+		if _, err := fmt.Fprintf(w, "%v%v %v\n", s.name, braces(key), formatValue(s.values[key])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Counter is a value that only goes up, such as the number of runs started.
+type Counter struct {
+	*series
+}
+
+// NewCounter registers a counter with the given label names.
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{newSeries(name, help, "counter", labels)}
+	registry.register(c)
+	return c
+}
+
+// Inc adds one to the counter for the given label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.update(labelValues, func(v float64) float64 { return v + 1 })
+}
+
+// Add adds the value, which must not be negative, to the counter for the
+// given label values.
+func (c *Counter) Add(value float64, labelValues ...string) {
+	c.update(labelValues, func(v float64) float64 { return v + value })
+}
+
+// Gauge is a value that can go up and down, such as a balance.
+type Gauge struct {
+	*series
+}
+
+// NewGauge registers a gauge with the given label names.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{newSeries(name, help, "gauge", labels)}
+	registry.register(g)
+	return g
+}
+
+// Set sets the gauge for the given label values.
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.update(labelValues, func(float64) float64 { return value })
+}
+
+// Inc adds one to the gauge for the given label values.
+func (g *Gauge) Inc(labelValues ...string) {
+	g.update(labelValues, func(v float64) float64 { return v + 1 })
+}
+
+// Dec subtracts one from the gauge for the given label values.
+func (g *Gauge) Dec(labelValues ...string) {
+	g.update(labelValues, func(v float64) float64 { return v - 1 })
+}
+
+// Operator-defined metrics, recorded by Metric tasks, are named with this
+// prefix so that they cannot clash with the node's own.
+const customPrefix = "chainlink_custom_"
+
+// The limits on operator-defined metrics, which keep the registry from
+// growing without bound when labels are taken from run data.
+const (
+	maxCustomMetrics = 100
+	maxCustomSeries  = 100
+)
+
+var (
+	customNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	customMetrics     = map[string]*series{}
+	customMutex       sync.Mutex
+)
+
+// RecordCustom records the value for an operator-defined gauge, which is
+// set to it, or counter, which is increased by it, with the given labels.
+// The metric is registered the first time it is recorded, and must be
+// recorded with the same kind and label names from then on.
+func RecordCustom(name, kind string, labels map[string]string, value float64) error {
+	if !customNamePattern.MatchString(name) {
+		return fmt.Errorf("metrics: invalid name %q", name)
+	}
+	if kind != "gauge" && kind != "counter" {
+		return fmt.Errorf("metrics: kind must be gauge or counter, got %q", kind)
+	}
+	if kind == "counter" && value < 0 {
+		return fmt.Errorf("metrics: counter %v cannot be decreased", name)
+	}
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		if !customNamePattern.MatchString(label) {
+			return fmt.Errorf("metrics: invalid label name %q", label)
+		}
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, label := range names {
+		values[i] = labels[label]
+	}
+
+	s, err := customSeries(customPrefix+name, kind, names)
+	if err != nil {
+		return err
+	}
+	if kind == "counter" {
+		return s.updateBounded(maxCustomSeries, values, func(v float64) float64 { return v + value })
+	}
+	return s.updateBounded(maxCustomSeries, values, func(float64) float64 { return value })
+}
+
+func customSeries(name, kind string, labels []string) (*series, error) {
+	customMutex.Lock()
+	defer customMutex.Unlock()
+	if s, ok := customMetrics[name]; ok {
+		if s.kind != kind || strings.Join(s.labels, ",") != strings.Join(labels, ",") {
+			return nil, fmt.Errorf("metrics: %v is a %v labelled %v", name, s.kind, s.labels)
+		}
+		return s, nil
+	}
+	if len(customMetrics) >= maxCustomMetrics {
+		return nil, fmt.Errorf("metrics: already recording %v custom metrics", maxCustomMetrics)
+	}
+	s := newSeries(name, "Recorded by Metric tasks.", kind, labels)
+	customMetrics[name] = s
+	registry.register(s)
+	return s, nil
+}
+
+// Histogram counts observations, such as task durations, in buckets.
+type Histogram struct {
+	name         string
+	help         string
+	labels       []string
+	buckets      []float64
+	observations map[string]*observations
+	mutex        sync.Mutex
+}
+
+type observations struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram registers a histogram with the given upper bounds for its
+// buckets, in increasing order, and label names.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		name:         name,
+		help:         help,
+		labels:       labels,
+		buckets:      buckets,
+		observations: map[string]*observations{},
+	}
+	registry.register(h)
+	return h
+}
+
+// Observe records the value for the given label values.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := labelKey(h.name, h.labels, labelValues)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	o, ok := h.observations[key]
+	if !ok {
+		o = &observations{counts: make([]uint64, len(h.buckets))}
+		h.observations[key] = o
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			o.counts[i]++
+		}
+	}
+	o.sum += value
+	o.count++
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, err := fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v histogram\n", h.name, h.help, h.name); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(h.observations))
+	for key := range h.observations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		o := h.observations[key]
+		for i, bound := range h.buckets {
+			le := withLabel(key, fmt.Sprintf(`le="%v"`, formatValue(bound)))
+			if _, err := fmt.Fprintf(w, "%v_bucket{%v} %v\n", h.name, le, o.counts[i]); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "%v_bucket{%v} %v\n%v_sum%v %v\n%v_count%v %v\n",
+			h.name, withLabel(key, `le="+Inf"`),
+			o.count,
+			h.name, braces(key), formatValue(o.sum),
+			h.name, braces(key), o.count,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelKey joins the label pairs for a metric's label values, which
+// identifies the series and is written between its braces.
+func labelKey(name string, labels, labelValues []string) string {
+	if len(labelValues) != len(labels) {
+		panic(fmt.Sprintf("metrics: %v takes %v label values, got %v", name, len(labels), len(labelValues)))
+	}
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		pairs[i] = fmt.Sprintf(`%v="%v"`, label, escape(labelValues[i]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func withLabel(key, label string) string {
+	if key == "" {
+		return label
+	}
+	return key + "," + label
+}
+
+func braces(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "{" + key + "}"
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprint(v)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
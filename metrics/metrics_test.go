@@ -0,0 +1,83 @@
+package metrics_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	counter := metrics.NewCounter("test_counter_total", "A counter.", "job_id")
+	counter.Inc("a")
+	counter.Inc("a")
+	counter.Inc(`b"c`)
+	gauge := metrics.NewGauge("test_gauge", "A gauge.")
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+	histogram := metrics.NewHistogram("test_seconds", "A histogram.", []float64{0.1, 1}, "task_type")
+	histogram.Observe(0.0625, "noop")
+	histogram.Observe(0.5, "noop")
+	histogram.Observe(5, "noop")
+
+	var buf bytes.Buffer
+	assert.Nil(t, metrics.Write(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, "# HELP test_counter_total A counter.\n# TYPE test_counter_total counter\n")
+	assert.Contains(t, out, `test_counter_total{job_id="a"} 2`+"\n")
+	assert.Contains(t, out, `test_counter_total{job_id="b\"c"} 1`+"\n")
+	assert.Contains(t, out, "# TYPE test_gauge gauge\ntest_gauge 1\n")
+	assert.Contains(t, out, `test_seconds_bucket{task_type="noop",le="0.1"} 1`+"\n")
+	assert.Contains(t, out, `test_seconds_bucket{task_type="noop",le="1"} 2`+"\n")
+	assert.Contains(t, out, `test_seconds_bucket{task_type="noop",le="+Inf"} 3`+"\n")
+	assert.Contains(t, out, `test_seconds_sum{task_type="noop"} 5.5625`+"\n")
+	assert.Contains(t, out, `test_seconds_count{task_type="noop"} 3`+"\n")
+}
+
+func TestCounter_WrongLabels(t *testing.T) {
+	t.Parallel()
+
+	counter := metrics.NewCounter("test_labelled_total", "A labelled counter.", "job_id")
+	assert.Panics(t, func() { counter.Inc() })
+}
+
+func TestRecordCustom(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"pair": "ETH/USD", "source": "a"}
+	assert.Nil(t, metrics.RecordCustom("test_price", "gauge", labels, 250.5))
+	assert.Nil(t, metrics.RecordCustom("test_price", "gauge", labels, 251))
+	assert.Nil(t, metrics.RecordCustom("test_reports", "counter", nil, 1))
+	assert.Nil(t, metrics.RecordCustom("test_reports", "counter", nil, 2))
+
+	var buf bytes.Buffer
+	assert.Nil(t, metrics.Write(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE chainlink_custom_test_price gauge\n")
+	assert.Contains(t, out, `chainlink_custom_test_price{pair="ETH/USD",source="a"} 251`+"\n")
+	assert.Contains(t, out, "chainlink_custom_test_reports 3\n")
+
+	assert.NotNil(t, metrics.RecordCustom("test_price", "counter", labels, 1))
+	assert.NotNil(t, metrics.RecordCustom("test_price", "gauge", map[string]string{"pair": "ETH/USD"}, 1))
+	assert.NotNil(t, metrics.RecordCustom("test_reports", "counter", nil, -1))
+	assert.NotNil(t, metrics.RecordCustom("test-price", "gauge", nil, 1))
+	assert.NotNil(t, metrics.RecordCustom("test_price", "summary", nil, 1))
+}
+
+func TestRecordCustom_BoundedSeries(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 100; i++ {
+		labels := map[string]string{"id": strconv.Itoa(i)}
+		assert.Nil(t, metrics.RecordCustom("test_bounded", "gauge", labels, 1))
+	}
+	err := metrics.RecordCustom("test_bounded", "gauge", map[string]string{"id": "100"}, 1)
+	assert.NotNil(t, err)
+	assert.Nil(t, metrics.RecordCustom("test_bounded", "gauge", map[string]string{"id": "99"}, 2))
+}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
 	"go.uber.org/multierr"
 )
 
@@ -12,14 +16,23 @@ type Application interface {
 	Start() error
 	Stop() error
 	GetStore() *store.Store
+	AddJob(job models.Job) error
 }
 
 // ChainlinkApplication contains fields for the NotificationListener, Scheduler,
-// and Store. The NotificationListener and Scheduler are also available
+// Heartbeat, EventExporter, RunReaper, DriftChecker, JobRegistry,
+// RunNotifier, FluxMonitor, and Store. All but the Store are also available
 // in the services package, but the Store has its own package.
 type ChainlinkApplication struct {
 	NotificationListener *NotificationListener
 	Scheduler            *Scheduler
+	Heartbeat            *Heartbeat
+	EventExporter        *EventExporter
+	RunReaper            *RunReaper
+	DriftChecker         *DriftChecker
+	JobRegistry          *JobRegistry
+	RunNotifier          *RunNotifier
+	FluxMonitor          *FluxMonitor
 	Store                *store.Store
 }
 
@@ -29,19 +42,56 @@ type ChainlinkApplication struct {
 // be used by the node.
 func NewApplication(config store.Config) Application {
 	store := store.NewStore(config)
-	logger.Reconfigure(config.RootDir, config.LogLevel.Level)
+	logger.Reconfigure(config.RootDir, config.LogLevel.Level, logger.Options{
+		Format:      config.LogFormat,
+		MaxFileSize: int64(config.LogFileMaxSize),
+		MaxFileAge:  config.LogFileMaxAge,
+		MaxBackups:  int(config.LogFileMaxBackups),
+		Sink: logger.SinkOptions{
+			Type:        config.LogSinkType,
+			URL:         config.LogSinkURL,
+			BatchSize:   int(config.LogSinkBatchSize),
+			FlushPeriod: config.LogSinkFlushPeriod,
+			MaxPending:  int(config.LogSinkMaxPending),
+		},
+	})
+	logger.SetRunLogLimits(int(config.RunLogMaxRuns), int(config.RunLogMaxLines))
+	notificationListener := &NotificationListener{Store: store}
+	store.OnEthereumSwitch(notificationListener.Resubscribe)
 	return &ChainlinkApplication{
-		NotificationListener: &NotificationListener{Store: store},
+		NotificationListener: notificationListener,
 		Scheduler:            NewScheduler(store),
+		Heartbeat:            NewHeartbeat(store),
+		EventExporter:        NewEventExporter(store),
+		RunReaper:            NewRunReaper(store),
+		DriftChecker:         NewDriftChecker(store),
+		JobRegistry:          NewJobRegistry(store),
+		RunNotifier:          NewRunNotifier(store),
+		FluxMonitor:          NewFluxMonitor(store),
 		Store:                store,
 	}
 }
 
-// Start runs the Store, NotificationListener, and Scheduler. If successful,
-// nil will be returned.
+// Start runs the Store and RunNotifier, recovers the runs left in progress
+// when the node last stopped, then runs the EventExporter,
+// NotificationListener, Scheduler, Heartbeat, RunReaper, DriftChecker,
+// JobRegistry, and FluxMonitor. If successful, nil will be returned.
 func (app *ChainlinkApplication) Start() error {
 	app.Store.Start()
-	return multierr.Combine(app.NotificationListener.Start(), app.Scheduler.Start())
+	app.RunNotifier.Start()
+	if _, err := Recover(app.Store); err != nil {
+		logger.Warnw("Recovering from the last shutdown", "err", err)
+	}
+	return multierr.Combine(
+		app.EventExporter.Start(),
+		app.NotificationListener.Start(),
+		app.Scheduler.Start(),
+		app.Heartbeat.Start(),
+		app.RunReaper.Start(),
+		app.DriftChecker.Start(),
+		app.JobRegistry.Start(),
+		app.FluxMonitor.Start(),
+	)
 }
 
 // Stop allows the application to exit by halting schedules, closing
@@ -49,8 +99,15 @@ func (app *ChainlinkApplication) Start() error {
 func (app *ChainlinkApplication) Stop() error {
 	defer logger.Sync()
 	logger.Info("Gracefully exiting...")
+	app.FluxMonitor.Stop()
+	app.JobRegistry.Stop()
+	app.DriftChecker.Stop()
+	app.RunReaper.Stop()
+	app.Heartbeat.Stop()
 	app.Scheduler.Stop()
 	app.NotificationListener.Stop()
+	app.EventExporter.Stop()
+	app.RunNotifier.Stop()
 	return app.Store.Close()
 }
 
@@ -59,15 +116,171 @@ func (app *ChainlinkApplication) GetStore() *store.Store {
 	return app.Store
 }
 
-// AddJob adds a job to the store and the scheduler. If there was
-// an error from adding the job to the store, the job will not be
-// added to the scheduler.
+// AddJob adds a job to the store, the scheduler, and the flux monitor, and
+// tells the job registry and its external initiators about it. If there was an error from adding the job to the
+// store, the job will not be added to the scheduler.
 func (app *ChainlinkApplication) AddJob(job models.Job) error {
 	err := app.Store.SaveJob(&job)
 	if err != nil {
 		return err
 	}
 
+	app.JobRegistry.Notify(RegistryJobCreated, job)
+	NotifyExternalInitiators(app.Store, ExternalJobCreated, job)
+	app.Scheduler.AddJob(job)
+	app.FluxMonitor.AddJob(job)
+	return app.NotificationListener.AddJob(job)
+}
+
+// ArchiveJob marks the job archived, so that it is no longer run, and stops
+// listening for its logs and polling its feeds. Its runs are kept, and its external initiators
+// are told it was deleted.
+func (app *ChainlinkApplication) ArchiveJob(job models.Job) error {
+	job.Archived = true
+	if err := app.Store.Save(&job); err != nil {
+		return err
+	}
+	app.JobRegistry.Notify(RegistryJobArchived, job)
+	NotifyExternalInitiators(app.Store, ExternalJobDeleted, job)
+	app.NotificationListener.RemoveJob(job.ID)
+	app.FluxMonitor.RemoveJob(job.ID)
+	return nil
+}
+
+// PauseJob stops the job from starting new runs. Runs its initiators would
+// have started are recorded as skipped instead.
+func (app *ChainlinkApplication) PauseJob(job models.Job) error {
+	job.Paused = true
+	if err := app.Store.Save(&job); err != nil {
+		return err
+	}
+	app.JobRegistry.Notify(RegistryJobUpdated, job)
+	return nil
+}
+
+// ResumeJob lets the job start new runs again. If replay is set, the runs
+// skipped for log events while the job was paused are started, in the order
+// the logs were received.
+func (app *ChainlinkApplication) ResumeJob(job models.Job, replay bool) error {
+	job.Paused = false
+	if err := app.Store.Save(&job); err != nil {
+		return err
+	}
+	app.JobRegistry.Notify(RegistryJobUpdated, job)
+
+	skipped, err := app.Store.SkippedRunsFor(job.ID)
+	if err != nil {
+		return err
+	}
+	replays := []models.SkippedRun{}
+	for _, sr := range skipped {
+		sr.Resumed = true
+		if replay && sr.Replayable() {
+			sr.Replayed = true
+			replays = append(replays, sr)
+		}
+		if err := app.Store.Save(&sr); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		for _, sr := range replays {
+			if _, err := BeginRun(job, app.Store, sr.Input); err != nil {
+				logger.Errorw(fmt.Sprintf("Replaying skipped run %v: %v", sr.ID, err), "job", job.ID)
+			}
+		}
+	}()
+	return nil
+}
+
+// UnarchiveJob makes an archived job runnable again, scheduling it and
+// listening for its logs, and telling its external initiators about it as
+// though it were new.
+func (app *ChainlinkApplication) UnarchiveJob(job models.Job) error {
+	job.Archived = false
+	if err := app.Store.Save(&job); err != nil {
+		return err
+	}
+	app.JobRegistry.Notify(RegistryJobUpdated, job)
+	NotifyExternalInitiators(app.Store, ExternalJobCreated, job)
 	app.Scheduler.AddJob(job)
+	app.FluxMonitor.AddJob(job)
 	return app.NotificationListener.AddJob(job)
 }
+
+// SaveAddressList saves the address list, and restarts the log
+// subscriptions of the jobs listening to it, so that they listen to its
+// new addresses.
+func (app *ChainlinkApplication) SaveAddressList(list models.AddressList) error {
+	list.UpdatedAt = models.Time{Time: app.Store.Clock.Now()}
+	if err := app.Store.Save(&list); err != nil {
+		return err
+	}
+	jobs, err := app.JobsUsingAddressList(list.Name)
+	if err != nil {
+		return err
+	}
+	var merr error
+	for _, job := range jobs {
+		app.NotificationListener.RemoveJob(job.ID)
+		merr = multierr.Append(merr, app.NotificationListener.AddJob(job))
+	}
+	return merr
+}
+
+// JobsUsingAddressList returns the jobs that are not archived with a log
+// initiator listening to the named address list.
+func (app *ChainlinkApplication) JobsUsingAddressList(name string) ([]models.Job, error) {
+	jobs, err := app.Store.Jobs()
+	if err != nil {
+		return nil, err
+	}
+	using := []models.Job{}
+	for _, job := range jobs {
+		if job.Archived {
+			continue
+		}
+		for _, initr := range job.InitiatorsFor(models.InitiatorEthLog, models.InitiatorRunLog) {
+			if initr.AddressList == name {
+				using = append(using, job)
+				break
+			}
+		}
+	}
+	return using, nil
+}
+
+// JobsUsingCredential returns the jobs that are not archived with a task,
+// or a task of a Parallel task, authenticating with the named credential.
+func (app *ChainlinkApplication) JobsUsingCredential(name string) ([]models.Job, error) {
+	jobs, err := app.Store.Jobs()
+	if err != nil {
+		return nil, err
+	}
+	using := []models.Job{}
+	for _, job := range jobs {
+		if job.Archived {
+			continue
+		}
+		for _, task := range job.Tasks {
+			if usesCredential(task.Params.Result, name) {
+				using = append(using, job)
+				break
+			}
+		}
+	}
+	return using, nil
+}
+
+func usesCredential(params gjson.Result, name string) bool {
+	if strings.EqualFold(params.Get("auth.credential").String(), name) {
+		return true
+	}
+	for _, task := range params.Get("tasks").Array() {
+		if usesCredential(task, name) {
+			return true
+		}
+	}
+	return false
+}
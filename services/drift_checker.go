@@ -0,0 +1,149 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/asdine/storm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// DriftChecker compares the node's state with its manifest every
+// DRIFT_CHECK_PERIOD, while one is saved. The differences are kept in the
+// chainlink_drift_items metric, and an event is published when new ones
+// are found and when they are all resolved.
+type DriftChecker struct {
+	store *store.Store
+	done  chan struct{}
+	last  models.Drift
+	mutex sync.Mutex
+}
+
+// NewDriftChecker returns a DriftChecker for the store's manifest.
+func NewDriftChecker(store *store.Store) *DriftChecker {
+	return &DriftChecker{store: store}
+}
+
+// Start checks for drift until Stop is called, unless DRIFT_CHECK_PERIOD
+// is 0.
+func (dc *DriftChecker) Start() error {
+	period := dc.store.CurrentConfig().DriftCheckPeriod
+	if period <= 0 {
+		return nil
+	}
+	dc.done = make(chan struct{})
+	go func(done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-dc.store.Clock.After(period):
+				if _, err := dc.Check(); err != nil && err != storm.ErrNotFound {
+					logger.Warnw("Checking for drift", "err", err)
+				}
+			}
+		}
+	}(dc.done)
+	return nil
+}
+
+// Stop stops checking for drift.
+func (dc *DriftChecker) Stop() {
+	if dc.done != nil {
+		close(dc.done)
+		dc.done = nil
+	}
+}
+
+// Check compares the node's state with its manifest now, and reports the
+// differences. It returns storm.ErrNotFound if the node has no manifest.
+func (dc *DriftChecker) Check() (models.Drift, error) {
+	drift, err := CheckDrift(dc.store)
+	if err != nil && err != storm.ErrNotFound {
+		return drift, err
+	}
+
+	metrics.DriftItems.Set(float64(drift.Jobs()), "jobs")
+	metrics.DriftItems.Set(float64(drift.Bridges()), "bridges")
+	metrics.DriftItems.Set(float64(drift.Keys()), "keys")
+	metrics.DriftItems.Set(float64(drift.Config()), "config")
+
+	dc.mutex.Lock()
+	last := dc.last
+	dc.last = drift
+	dc.mutex.Unlock()
+
+	if err != nil {
+		return drift, err
+	} else if drift.Drifted() && !reflect.DeepEqual(drift, last) {
+		logger.Warnw("Node has drifted from its manifest", "drift", drift)
+		dc.store.Events.Publish(models.Event{Type: models.EventDriftDetected, Data: drift})
+	} else if !drift.Drifted() && last.Drifted() {
+		logger.Infow("Node matches its manifest again")
+		dc.store.Events.Publish(models.Event{Type: models.EventDriftResolved})
+	}
+	return drift, nil
+}
+
+// CheckDrift returns how the node's state differs from its manifest, or
+// storm.ErrNotFound if it has none.
+func CheckDrift(store *store.Store) (models.Drift, error) {
+	manifest, err := store.Manifest()
+	if err != nil {
+		return models.Drift{}, err
+	}
+	actual, err := CurrentManifest(store)
+	if err != nil {
+		return models.Drift{}, err
+	}
+	return manifest.Compare(actual), nil
+}
+
+// CurrentManifest returns a manifest of the node's state as it is now: its
+// jobs that are not archived, its bridges, the addresses of its keys, and
+// the digest of its config.
+func CurrentManifest(store *store.Store) (models.Manifest, error) {
+	jobs, err := store.Jobs()
+	if err != nil {
+		return models.Manifest{}, err
+	}
+	jobIDs := []string{}
+	for _, j := range jobs {
+		if !j.Archived {
+			jobIDs = append(jobIDs, j.ID)
+		}
+	}
+	sort.Strings(jobIDs)
+
+	bridges := []models.BridgeType{}
+	if err := store.All(&bridges); err != nil {
+		return models.Manifest{}, err
+	}
+
+	return models.Manifest{
+		Jobs:         jobIDs,
+		Bridges:      bridges,
+		KeyAddresses: keyAddresses(store),
+		ConfigDigest: store.CurrentConfig().Digest(),
+		UpdatedAt:    store.Clock.Now(),
+	}, nil
+}
+
+// keyAddresses returns the addresses of every key in the keystore, or of
+// the signer's account when it keeps its key elsewhere.
+func keyAddresses(s *store.Store) []common.Address {
+	addresses := []common.Address{}
+	if ks, ok := s.Signer.(*store.KeyStore); ok {
+		for _, account := range ks.Accounts() {
+			addresses = append(addresses, account.Address)
+		}
+	} else if s.Signer != nil && s.Signer.HasAccounts() {
+		addresses = append(addresses, s.Signer.GetAccount().Address)
+	}
+	return addresses
+}
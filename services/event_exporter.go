@@ -0,0 +1,223 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// exportBatchSize is the most events sent to the sink in one request.
+const exportBatchSize = 100
+
+// exportRetryMax is the longest the exporter waits before retrying a sink
+// that is failing.
+const exportRetryMax = time.Minute
+
+// EventExporter streams the run and transaction events published by the
+// store as JSON to EVENT_EXPORT_URL, for operators keeping their own record
+// of node activity. Each event is saved to an outbox before it is
+// published, and removed once the sink accepts it, so events are delivered
+// at least once. If EVENT_EXPORT_MAX_PENDING events are waiting, publishing
+// blocks until the sink catches up, slowing runs rather than losing events.
+//
+// Events are POSTed as a JSON array of outbox events, or, when
+// EVENT_EXPORT_KAFKA_TOPIC is set, in the format of the Kafka REST Proxy to
+// the topic's path under EVENT_EXPORT_URL.
+type EventExporter struct {
+	store   *store.Store
+	client  *http.Client
+	pending int
+	room    *sync.Cond
+	wake    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex
+	flushes sync.Mutex
+}
+
+// NewEventExporter returns an EventExporter for the store's events.
+func NewEventExporter(store *store.Store) *EventExporter {
+	ee := &EventExporter{
+		store:  store,
+		client: &http.Client{Timeout: 30 * time.Second},
+		wake:   make(chan struct{}, 1),
+	}
+	ee.room = sync.NewCond(&ee.mutex)
+	return ee
+}
+
+// Start saves the store's events to the outbox and sends them until Stop
+// is called, unless EVENT_EXPORT_URL is not set.
+func (ee *EventExporter) Start() error {
+	if ee.store.CurrentConfig().EventExportURL == "" {
+		return nil
+	}
+	pending, err := ee.store.Count(&models.OutboxEvent{})
+	if err != nil {
+		return err
+	}
+	ee.mutex.Lock()
+	ee.pending = pending
+	ee.done = make(chan struct{})
+	ee.mutex.Unlock()
+
+	ee.store.Events.Export(ee.enqueue)
+	go ee.run(ee.done)
+	return nil
+}
+
+// Stop stops exporting events. Events left in the outbox are sent once the
+// node is started again.
+func (ee *EventExporter) Stop() {
+	ee.mutex.Lock()
+	defer ee.mutex.Unlock()
+	if ee.done != nil {
+		ee.store.Events.Export(nil)
+		close(ee.done)
+		ee.done = nil
+		ee.room.Broadcast()
+	}
+}
+
+// enqueue saves the event to the outbox, first waiting for there to be
+// room for it.
+func (ee *EventExporter) enqueue(event models.Event) {
+	max := int(ee.store.CurrentConfig().EventExportMaxPending)
+	ee.mutex.Lock()
+	for max > 0 && ee.pending >= max && ee.done != nil {
+		ee.room.Wait()
+	}
+	ee.pending++
+	ee.mutex.Unlock()
+
+	b, err := json.Marshal(event)
+	if err == nil {
+		err = ee.store.Save(&models.OutboxEvent{Event: b, CreatedAt: event.CreatedAt})
+	}
+	if err != nil {
+		logger.Errorw("Saving event for export", "type", event.Type, "run", event.RunID, "err", err)
+		ee.sent(1)
+		return
+	}
+
+	select {
+	case ee.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ee *EventExporter) run(done chan struct{}) {
+	retry := time.Duration(0)
+	for {
+		select {
+		case <-done:
+			return
+		case <-ee.wake:
+		case <-ee.store.Clock.After(retry):
+		}
+
+		sent, err := ee.Flush()
+		switch {
+		case err != nil:
+			logger.Warnw("Exporting events", "err", err, "retry", retry)
+			retry = backoff(retry)
+		case sent == exportBatchSize:
+			retry = 0
+		default:
+			retry = exportRetryMax
+		}
+	}
+}
+
+func backoff(retry time.Duration) time.Duration {
+	if retry < time.Second {
+		return time.Second
+	}
+	if retry*2 > exportRetryMax {
+		return exportRetryMax
+	}
+	return retry * 2
+}
+
+// Flush sends the oldest batch of events in the outbox to the sink, and
+// returns how many were sent.
+func (ee *EventExporter) Flush() (int, error) {
+	ee.flushes.Lock()
+	defer ee.flushes.Unlock()
+	events, err := ee.store.OutboxEvents(exportBatchSize)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	if err := ee.send(events); err != nil {
+		return 0, err
+	}
+	if err := ee.store.DeleteOutboxEvents(events); err != nil {
+		return 0, err
+	}
+	ee.sent(len(events))
+	return len(events), nil
+}
+
+// sent makes room for n more events in the outbox.
+func (ee *EventExporter) sent(n int) {
+	ee.mutex.Lock()
+	defer ee.mutex.Unlock()
+	ee.pending -= n
+	if ee.pending < 0 {
+		ee.pending = 0
+	}
+	ee.room.Broadcast()
+}
+
+func (ee *EventExporter) send(events []models.OutboxEvent) error {
+	config := ee.store.CurrentConfig()
+	url := config.EventExportURL
+	contentType := "application/json"
+	var body interface{} = events
+	if config.EventExportKafkaTopic != "" {
+		url = strings.TrimRight(url, "/") + "/topics/" + config.EventExportKafkaTopic
+		contentType = "application/vnd.kafka.json.v2+json"
+		body = kafkaRecords(events)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := ee.client.Post(url, contentType, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Event sink responded %v: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+type kafkaRecord struct {
+	Key   string             `json:"key,omitempty"`
+	Value models.OutboxEvent `json:"value"`
+}
+
+// kafkaRecords keys each event by its run, so that a run's events stay in
+// order on one partition.
+func kafkaRecords(events []models.OutboxEvent) interface{} {
+	records := make([]kafkaRecord, len(events))
+	for i, e := range events {
+		var event models.Event
+		json.Unmarshal(e.Event, &event)
+		records[i] = kafkaRecord{Key: event.RunID, Value: e}
+	}
+	return map[string]interface{}{"records": records}
+}
@@ -0,0 +1,175 @@
+package services_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type eventSink struct {
+	server   *httptest.Server
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+	mutex    sync.Mutex
+}
+
+func newEventSink(status int) *eventSink {
+	sink := &eventSink{status: status}
+	sink.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		sink.mutex.Lock()
+		defer sink.mutex.Unlock()
+		sink.requests = append(sink.requests, r)
+		sink.bodies = append(sink.bodies, body)
+		w.WriteHeader(sink.status)
+	}))
+	return sink
+}
+
+func (sink *eventSink) setStatus(status int) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.status = status
+}
+
+func (sink *eventSink) received() int {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return len(sink.bodies)
+}
+
+func TestEventExporter_Webhook(t *testing.T) {
+	t.Parallel()
+
+	sink := newEventSink(200)
+	defer sink.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.EventExportURL = sink.server.URL
+
+	ee := services.NewEventExporter(store)
+	assert.Nil(t, ee.Start())
+	defer ee.Stop()
+
+	store.Events.Publish(models.Event{Type: models.EventRunCreated, JobID: "job", RunID: "run"})
+
+	gomega.NewGomegaWithT(t).Eventually(sink.received).Should(gomega.Equal(1))
+	assert.Equal(t, "application/json", sink.requests[0].Header.Get("Content-Type"))
+	var sent []models.OutboxEvent
+	assert.Nil(t, json.Unmarshal(sink.bodies[0], &sent))
+	assert.Equal(t, 1, len(sent))
+	var event models.Event
+	assert.Nil(t, json.Unmarshal(sent[0].Event, &event))
+	assert.Equal(t, models.EventRunCreated, event.Type)
+	assert.Equal(t, "run", event.RunID)
+
+	gomega.NewGomegaWithT(t).Eventually(func() int {
+		count, _ := store.Count(&models.OutboxEvent{})
+		return count
+	}).Should(gomega.Equal(0))
+}
+
+func TestEventExporter_Kafka(t *testing.T) {
+	t.Parallel()
+
+	sink := newEventSink(200)
+	defer sink.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.EventExportURL = sink.server.URL
+	store.Config.EventExportKafkaTopic = "node-events"
+
+	ee := services.NewEventExporter(store)
+	assert.Nil(t, ee.Start())
+	defer ee.Stop()
+
+	store.Events.Publish(models.Event{Type: models.EventRunCompleted, RunID: "run"})
+
+	gomega.NewGomegaWithT(t).Eventually(sink.received).Should(gomega.Equal(1))
+	assert.Equal(t, "/topics/node-events", sink.requests[0].URL.Path)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", sink.requests[0].Header.Get("Content-Type"))
+	var body struct {
+		Records []struct {
+			Key   string             `json:"key"`
+			Value models.OutboxEvent `json:"value"`
+		} `json:"records"`
+	}
+	assert.Nil(t, json.Unmarshal(sink.bodies[0], &body))
+	assert.Equal(t, 1, len(body.Records))
+	assert.Equal(t, "run", body.Records[0].Key)
+}
+
+func TestEventExporter_RetriesFailingSink(t *testing.T) {
+	t.Parallel()
+
+	sink := newEventSink(500)
+	defer sink.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.EventExportURL = sink.server.URL
+
+	ee := services.NewEventExporter(store)
+	assert.Nil(t, store.Save(&models.OutboxEvent{Event: []byte(`{"type":"run_created"}`)}))
+	_, err := ee.Flush()
+	assert.NotNil(t, err)
+	count, err := store.Count(&models.OutboxEvent{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	sink.setStatus(200)
+	sent, err := ee.Flush()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	count, err = store.Count(&models.OutboxEvent{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestEventExporter_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	sink := newEventSink(500)
+	defer sink.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.EventExportURL = sink.server.URL
+	store.Config.EventExportMaxPending = 2
+
+	ee := services.NewEventExporter(store)
+	assert.Nil(t, ee.Start())
+	defer ee.Stop()
+
+	store.Events.Publish(models.Event{Type: models.EventRunCreated})
+	store.Events.Publish(models.Event{Type: models.EventRunCreated})
+
+	published := make(chan struct{})
+	go func() {
+		store.Events.Publish(models.Event{Type: models.EventRunCreated})
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatal("Publish should wait for room in the outbox")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	sink.setStatus(200)
+	_, err := ee.Flush()
+	assert.Nil(t, err)
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish should continue once the sink catches up")
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// ExecutionStrategy performs the work of a task run, given its interpolated
+// task, and returns the result along with the attestation of the enclave
+// that did the work, if any. Work still going on once the context is done
+// is cancelled.
+type ExecutionStrategy interface {
+	Perform(
+		ctx context.Context,
+		tr models.TaskRun,
+		input models.RunResult,
+		store *store.Store,
+	) (models.RunResult, *models.Attestation)
+}
+
+// executionStrategyFor returns the strategy for the task: its adapter in
+// the node, or the enclave at ENCLAVE_SOCKET for the task types listed in
+// ENCLAVE_TASK_TYPES.
+func executionStrategyFor(task models.Task, store *store.Store) (ExecutionStrategy, error) {
+	if store.CurrentConfig().EnclaveTaskType(task.Type) {
+		return EnclaveExecution{Socket: store.CurrentConfig().EnclaveSocket}, nil
+	}
+	adapter, err := adapters.For(task, store)
+	if err != nil {
+		return nil, err
+	}
+	return LocalExecution{Adapter: adapter}, nil
+}
+
+// LocalExecution performs tasks with their adapter, in the node.
+type LocalExecution struct {
+	Adapter adapters.Adapter
+}
+
+// Perform performs the task with the adapter. A ContextAdapter is handed
+// the context, for the tracer of a run sampled for debugging, but only its
+// work is cancelled when the context is done.
+func (le LocalExecution) Perform(
+	ctx context.Context,
+	tr models.TaskRun,
+	input models.RunResult,
+	store *store.Store,
+) (models.RunResult, *models.Attestation) {
+	if ctx.Done() == nil {
+		if ca, ok := le.Adapter.(adapters.ContextAdapter); ok {
+			return ca.PerformContext(ctx, input, store), nil
+		}
+		return le.Adapter.Perform(input, store), nil
+	}
+	return adapters.PerformContext(ctx, le.Adapter, input, store), nil
+}
+
+// EnclaveExecution hands tasks to an enclave process listening on a local
+// unix socket, so that their params and input are only seen inside the
+// enclave. Each task is one connection, over which the node writes a line
+// of JSON with the task run's ID, its task, and its input, and the enclave
+// answers with a line of JSON with the result and its attestation.
+type EnclaveExecution struct {
+	Socket string
+}
+
+type enclaveRequest struct {
+	TaskRunID string           `json:"taskRunId"`
+	Task      models.Task      `json:"task"`
+	Input     models.RunResult `json:"input"`
+}
+
+type enclaveResponse struct {
+	Result      models.RunResult    `json:"result"`
+	Attestation *models.Attestation `json:"attestation"`
+}
+
+// Perform sends the task to the enclave and waits for its result. A result
+// without an attestation is errored, since there is nothing to show that
+// the enclave did the work.
+func (ee EnclaveExecution) Perform(
+	ctx context.Context,
+	tr models.TaskRun,
+	input models.RunResult,
+	store *store.Store,
+) (models.RunResult, *models.Attestation) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", ee.Socket)
+	if err != nil {
+		return input.WithError(fmt.Errorf("Enclave: %v", err)), nil
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	request := enclaveRequest{TaskRunID: tr.ID, Task: tr.Task, Input: input}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return input.WithError(fmt.Errorf("Enclave: sending task: %v", err)), nil
+	}
+
+	var response enclaveResponse
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return input.WithError(fmt.Errorf("Enclave: reading result: %v", err)), nil
+	}
+	if err := json.Unmarshal(line, &response); err != nil {
+		return input.WithError(fmt.Errorf("Enclave: reading result: %v", err)), nil
+	}
+	if response.Attestation == nil {
+		return input.WithError(fmt.Errorf("Enclave: result for task %v has no attestation", tr.Task.Type)), nil
+	}
+	response.Result.JobRunID = input.JobRunID
+	return response.Result, response.Attestation
+}
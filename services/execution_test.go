@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeEnclave answers every task sent to the socket with the given
+// response, passing the tasks it was sent to the returned channel.
+func fakeEnclave(t *testing.T, socket string, response string) chan map[string]interface{} {
+	listener, err := net.Listen("unix", socket)
+	assert.Nil(t, err)
+	requests := make(chan map[string]interface{}, 10)
+	go func() {
+		defer listener.Close()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadBytes('\n')
+			var request map[string]interface{}
+			json.Unmarshal(line, &request)
+			requests <- request
+			conn.Write([]byte(response + "\n"))
+			conn.Close()
+		}
+	}()
+	return requests
+}
+
+func TestJobRunner_ExecuteRun_Enclave(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		response        string
+		wantStatus      string
+		wantAttestation bool
+	}{
+		{"attested", `{"result":{"data":{"value":"sealed"}},"attestation":{"type":"sgx","measurement":"ab12","quote":"cXVvdGU="}}`,
+			models.StatusCompleted, true},
+		{"no attestation", `{"result":{"data":{"value":"sealed"}}}`, models.StatusErrored, false},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			dir, err := ioutil.TempDir("", "enclave")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+
+			store.Config.EnclaveSocket = filepath.Join(dir, "enclave.sock")
+			store.Config.EnclaveTaskTypes = "Confidential, other"
+			requests := fakeEnclave(t, store.Config.EnclaveSocket, test.response)
+
+			job := models.NewJob()
+			job.Tasks = []models.Task{cltest.NewTask("confidential", `{"url":"https://example.com"}`)}
+			run, err := services.ExecuteRun(job.NewRun(), store, cltest.RunResultWithValue("100"))
+			assert.Nil(t, err)
+
+			request := <-requests
+			assert.Equal(t, run.TaskRuns[0].ID, request["taskRunId"])
+			assert.Equal(t, "https://example.com", request["task"].(map[string]interface{})["url"])
+
+			assert.Nil(t, store.One("ID", run.ID, &run))
+			assert.Equal(t, test.wantStatus, run.Status)
+			tr := run.TaskRuns[0]
+			if test.wantAttestation {
+				assert.Equal(t, &models.Attestation{Type: "sgx", Measurement: "ab12", Quote: "cXVvdGU="}, tr.Attestation)
+				value, err := tr.Result.Value()
+				assert.Nil(t, err)
+				assert.Equal(t, "sealed", value)
+			} else {
+				assert.Nil(t, tr.Attestation)
+				assert.Contains(t, tr.Result.Error(), "no attestation")
+			}
+		})
+	}
+}
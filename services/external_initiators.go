@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// The changes to a job an external initiator is told about.
+const (
+	// ExternalJobCreated is sent when a job the external initiator starts is
+	// added to the node, or unarchived.
+	ExternalJobCreated = "job_created"
+	// ExternalJobDeleted is sent when a job the external initiator starts is
+	// archived, so that it stops starting runs of it.
+	ExternalJobDeleted = "job_deleted"
+)
+
+// externalNoticeMaxAttempts is how many times a notice is sent to an
+// external initiator before it is given up on.
+const externalNoticeMaxAttempts = 3
+
+// ExternalInitiatorNotice tells an external initiator that a job naming it
+// was created or deleted, with the params of the job's initiator, so that
+// it knows what to watch for and which job to start when it sees it.
+type ExternalInitiatorNotice struct {
+	Event  string       `json:"event"`
+	JobID  string       `json:"jobId"`
+	Type   string       `json:"type"`
+	Params *models.JSON `json:"params,omitempty"`
+}
+
+// NotifyExternalInitiators tells each external initiator the job has an
+// initiator for of the change to it, in the background. External
+// initiators without a URL, or that are no longer registered, are skipped.
+func NotifyExternalInitiators(store *store.Store, event string, job models.Job) {
+	for _, initr := range job.InitiatorsFor(models.InitiatorExternal) {
+		ei, err := store.FindExternalInitiator(initr.Name)
+		if err != nil {
+			logger.Warnw("Notifying external initiator", "name", initr.Name, "job", job.ID, "err", err)
+			continue
+		}
+		if ei.URL == "" {
+			continue
+		}
+		notice := ExternalInitiatorNotice{
+			Event:  event,
+			JobID:  job.ID,
+			Type:   ei.Name,
+			Params: initr.Params,
+		}
+		go deliverExternalNotice(store, ei, notice)
+	}
+}
+
+func deliverExternalNotice(store *store.Store, ei models.ExternalInitiator, notice ExternalInitiatorNotice) {
+	retry := time.Duration(0)
+	for attempt := 1; ; attempt++ {
+		err := SendExternalInitiatorNotice(ei, notice)
+		if err == nil {
+			return
+		}
+		if attempt == externalNoticeMaxAttempts {
+			logger.Errorw("Giving up on notifying external initiator", "name", ei.Name, "event", notice.Event, "job", notice.JobID, "err", err)
+			return
+		}
+		retry = backoff(retry)
+		logger.Warnw("Notifying external initiator", "name", ei.Name, "event", notice.Event, "job", notice.JobID, "err", err, "retry", retry)
+		<-store.Clock.After(retry)
+	}
+}
+
+// SendExternalInitiatorNotice POSTs the notice to the external initiator's
+// URL, with its outgoing token as a bearer token.
+func SendExternalInitiatorNotice(ei models.ExternalInitiator, notice ExternalInitiatorNotice) error {
+	b, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", ei.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ei.OutgoingToken)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("External initiator %v responded %v: %s", ei.Name, resp.StatusCode, msg)
+	}
+	return nil
+}
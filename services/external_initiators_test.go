@@ -0,0 +1,65 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyExternalInitiators(t *testing.T) {
+	t.Parallel()
+
+	sink := newEventSink(200)
+	defer sink.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	ei, _, err := models.NewExternalInitiator("iot", sink.server.URL)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&ei))
+	silent, _, err := models.NewExternalInitiator("exchange", "")
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&silent))
+
+	params := cltest.JSONFromString(`{"sensor":"thermometer-1"}`)
+	job := cltest.NewJob()
+	job.Initiators = []models.Initiator{
+		{Type: models.InitiatorExternal, Name: "iot", Params: &params},
+		{Type: models.InitiatorExternal, Name: "exchange"},
+	}
+	services.NotifyExternalInitiators(store, services.ExternalJobCreated, job)
+
+	gomega.NewGomegaWithT(t).Eventually(sink.received).Should(gomega.Equal(1))
+	assert.Equal(t, "Bearer "+ei.OutgoingToken, sink.requests[0].Header.Get("Authorization"))
+
+	var notice struct {
+		Event  string `json:"event"`
+		JobID  string `json:"jobId"`
+		Type   string `json:"type"`
+		Params struct {
+			Sensor string `json:"sensor"`
+		} `json:"params"`
+	}
+	assert.Nil(t, json.Unmarshal(sink.bodies[0], &notice))
+	assert.Equal(t, services.ExternalJobCreated, notice.Event)
+	assert.Equal(t, job.ID, notice.JobID)
+	assert.Equal(t, "iot", notice.Type)
+	assert.Equal(t, "thermometer-1", notice.Params.Sensor)
+}
+
+func TestSendExternalInitiatorNotice_Error(t *testing.T) {
+	t.Parallel()
+
+	sink := newEventSink(500)
+	defer sink.server.Close()
+
+	ei, _, err := models.NewExternalInitiator("iot", sink.server.URL)
+	assert.Nil(t, err)
+	notice := services.ExternalInitiatorNotice{Event: services.ExternalJobDeleted, JobID: "1", Type: "iot"}
+	assert.NotNil(t, services.SendExternalInitiatorNotice(ei, notice))
+}
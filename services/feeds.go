@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// feedsMutex keeps runs completing at once from overwriting each other's
+// observations, or reporting the same answer twice.
+var feedsMutex sync.Mutex
+
+// ObserveFeeds records the completed run's value as the latest result of
+// its job, for each feed the job is a source of, and reports each of those
+// feeds' answers if one is due.
+func ObserveFeeds(store *store.Store, run models.JobRun) error {
+	feedsMutex.Lock()
+	defer feedsMutex.Unlock()
+
+	feeds, err := store.FeedsForJob(run.JobID)
+	if err != nil || len(feeds) == 0 {
+		return err
+	}
+	s, err := run.Result.Value()
+	if err != nil {
+		return fmt.Errorf("Feed source %v: %v", run.JobID, err)
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("Feed source %v: value %v is not a number", run.JobID, s)
+	}
+
+	now := store.Clock.Now()
+	for _, feed := range feeds {
+		if feed.Observations == nil {
+			feed.Observations = map[string]models.FeedObservation{}
+		}
+		feed.Observations[run.JobID] = models.FeedObservation{
+			Value:      value,
+			RunID:      run.ID,
+			ObservedAt: models.Time{Time: now},
+		}
+		if err := reportFeed(store, &feed); err != nil {
+			logger.Warnw("Reporting feed", "feed", feed.ID, "err", err)
+		}
+		if err := store.Save(&feed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportFeed sends the feed's answer to its contract if a report is due.
+func reportFeed(store *store.Store, feed *models.Feed) error {
+	now := store.Clock.Now()
+	metrics.FeedFreshSources.Set(float64(len(feed.Fresh(now))), feed.ID)
+	answer, err := feed.Answer(now)
+	if err != nil {
+		return err
+	}
+	if !feed.ReportDue(answer, now) {
+		return nil
+	}
+	data, err := feed.ReportData(answer)
+	if err != nil {
+		return err
+	}
+	tx, err := store.TxManager.CreateTx(feed.Address, data)
+	if err != nil {
+		return err
+	}
+
+	feed.LatestAnswer = answer
+	feed.LatestTxHash = tx.Hash
+	feed.LatestReportedAt = models.Time{Time: now}
+	metrics.FeedAnswer.Set(answer, feed.ID)
+	metrics.FeedReports.Inc(feed.ID)
+	logger.Infow("Reported feed answer", "feed", feed.ID, "answer", answer, "tx", tx.Hash.Hex())
+	return nil
+}
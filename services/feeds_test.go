@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveFeeds(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	clock := cltest.UseSettableClock(store)
+	now := time.Now()
+	clock.SetTime(now)
+
+	feed := models.NewFeed()
+	feed.JobIDs = []string{"source1", "source2"}
+	feed.Address = cltest.NewAddress()
+	feed.FunctionSelector = models.HexToFunctionSelector("0x9a6fc8f5")
+	feed.Deviation = 1
+	assert.Nil(t, store.Save(&feed))
+
+	complete := func(jobID, value string) {
+		run := models.JobRun{ID: utils.NewBytes32ID(), JobID: jobID, Result: cltest.RunResultWithValue(value)}
+		assert.Nil(t, services.ObserveFeeds(store, run))
+	}
+
+	complete("source1", "100")
+	assert.Nil(t, store.One("ID", feed.ID, &feed))
+	assert.Equal(t, 100.0, feed.Observations["source1"].Value)
+	assert.True(t, feed.LatestReportedAt.IsZero(), "waits for every source")
+
+	eth := app.MockEthClient()
+	hash := cltest.NewHash()
+	eth.Register("eth_getTransactionCount", utils.Uint64ToHex(0))
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(100))
+	eth.Register("eth_sendRawTransaction", hash,
+		func(_ interface{}, data ...interface{}) error {
+			tx, err := utils.DecodeEthereumTx(data[0].([]interface{})[0].(string))
+			assert.Nil(t, err)
+			assert.Equal(t, feed.Address, *tx.To())
+			assert.Equal(t, "0x9a6fc8f5"+"0000000000000000000000000000000000000000000000000000000000000066", hexutil.Encode(tx.Data()))
+			return nil
+		})
+	complete("source2", "104")
+	eth.EnsureAllCalled(t)
+	assert.Nil(t, store.One("ID", feed.ID, &feed))
+	assert.Equal(t, 102.0, feed.LatestAnswer)
+	assert.Equal(t, now.Unix(), feed.LatestReportedAt.Unix())
+
+	complete("source1", "101")
+	assert.Nil(t, store.One("ID", feed.ID, &feed))
+	assert.Equal(t, 102.0, feed.LatestAnswer, "within the deviation")
+
+	run := models.JobRun{ID: utils.NewBytes32ID(), JobID: "source2", Result: cltest.RunResultWithValue("nonsense")}
+	assert.NotNil(t, services.ObserveFeeds(store, run))
+	assert.Nil(t, store.One("ID", feed.ID, &feed))
+	assert.Equal(t, 104.0, feed.Observations["source2"].Value)
+}
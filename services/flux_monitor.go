@@ -0,0 +1,194 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/tidwall/gjson"
+)
+
+// FluxMonitor polls the feeds of jobs with a "fluxmonitor" initiator, and
+// starts a run of the job, with the median of the feeds as its value, only
+// when that has moved far enough from the answer on chain or the job has
+// been idle too long, so that push based price feeds are only updated when
+// they need to be.
+type FluxMonitor struct {
+	store   *store.Store
+	pollers map[string]chan struct{}
+	started bool
+	mutex   sync.Mutex
+}
+
+// NewFluxMonitor returns a FluxMonitor for the store's jobs.
+func NewFluxMonitor(store *store.Store) *FluxMonitor {
+	return &FluxMonitor{store: store, pollers: map[string]chan struct{}{}}
+}
+
+// Start polls the feeds of every job with a fluxmonitor initiator.
+func (fm *FluxMonitor) Start() error {
+	fm.mutex.Lock()
+	fm.started = true
+	fm.mutex.Unlock()
+
+	jobs, err := fm.store.Jobs()
+	if err != nil {
+		return fmt.Errorf("FluxMonitor: %v", err)
+	}
+	for _, job := range jobs {
+		fm.AddJob(job)
+	}
+	return nil
+}
+
+// Stop stops polling every job's feeds.
+func (fm *FluxMonitor) Stop() {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+	fm.started = false
+	for id, done := range fm.pollers {
+		close(done)
+		delete(fm.pollers, id)
+	}
+}
+
+// AddJob starts polling the feeds of the job's fluxmonitor initiators,
+// unless it is archived or already being polled.
+func (fm *FluxMonitor) AddJob(job models.Job) {
+	initrs := job.InitiatorsFor(models.InitiatorFluxMonitor)
+	if job.Archived || len(initrs) == 0 {
+		return
+	}
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+	if !fm.started || fm.pollers[job.ID] != nil {
+		return
+	}
+	done := make(chan struct{})
+	fm.pollers[job.ID] = done
+	for _, initr := range initrs {
+		go fm.poll(job, initr, done)
+	}
+}
+
+// RemoveJob stops polling the job's feeds.
+func (fm *FluxMonitor) RemoveJob(jobID string) {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+	if done, ok := fm.pollers[jobID]; ok {
+		close(done)
+		delete(fm.pollers, jobID)
+	}
+}
+
+func (fm *FluxMonitor) poll(job models.Job, initr models.Initiator, done chan struct{}) {
+	lastRun := fm.store.Clock.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case <-fm.store.Clock.After(time.Duration(initr.PollingInterval)):
+		}
+
+		value, due, err := CheckFlux(initr, fm.store, lastRun)
+		if err != nil {
+			logger.Warnw("Polling flux monitor feeds", "job", job.ID, "err", err)
+			continue
+		}
+		if !due {
+			continue
+		}
+		lastRun = fm.store.Clock.Now()
+		input := models.RunResult{}.WithValue(strconv.FormatFloat(value, 'f', -1, 64))
+		if _, err := TriggerRun(job, models.InitiatorFluxMonitor, fm.store, input); err != nil {
+			logger.Errorw("Flux monitor run", "job", job.ID, "err", err)
+		}
+	}
+}
+
+// CheckFlux fetches the initiator's feeds and returns their median, and
+// whether a run is due: when it has moved by at least the initiator's
+// Threshold percent from the answer on chain, when there is no answer on
+// chain yet, or when IdleThreshold has passed since lastRun.
+func CheckFlux(initr models.Initiator, store *store.Store, lastRun time.Time) (float64, bool, error) {
+	value, err := fetchFluxFeeds(initr.Feeds, store)
+	if err != nil {
+		return 0, false, err
+	}
+	if idle := time.Duration(initr.IdleThreshold); idle > 0 && !store.Clock.Now().Before(lastRun.Add(idle)) {
+		return value, true, nil
+	}
+
+	latest, err := store.TxManager.GetLatestAnswer(initr.Address)
+	if err != nil {
+		return 0, false, err
+	}
+	answer := models.ScaleFluxAnswer(latest, initr.Precision)
+	if answer == 0 {
+		return value, value != 0, nil
+	}
+	deviation := math.Abs(value-answer) / math.Abs(answer) * 100
+	return value, deviation >= initr.Threshold, nil
+}
+
+// fetchFluxFeeds returns the median of the values of the feeds that could
+// be fetched, or an error if none could be.
+func fetchFluxFeeds(feeds []models.FluxFeed, store *store.Store) (float64, error) {
+	values := []float64{}
+	for _, feed := range feeds {
+		value, err := fetchFluxFeed(feed, store)
+		if err != nil {
+			logger.Warnw("Fetching flux monitor feed", "url", feed.URL, "bridge", feed.Bridge, "err", err)
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("None of the %v feeds could be fetched", len(feeds))
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2, nil
+	}
+	return values[mid], nil
+}
+
+func fetchFluxFeed(feed models.FluxFeed, store *store.Store) (float64, error) {
+	tasks, err := feed.Tasks()
+	if err != nil {
+		return 0, err
+	}
+	result := models.RunResult{}
+	for _, task := range tasks {
+		adapter, err := adapters.For(task, store)
+		if err != nil {
+			return 0, err
+		}
+		result = adapter.Perform(result, store)
+		if result.HasError() {
+			return 0, result.GetError()
+		}
+		if result.Pending {
+			return 0, fmt.Errorf("%v left the result pending", task.Type)
+		}
+	}
+	val, err := result.Get("value")
+	if err != nil {
+		return 0, err
+	}
+	switch val.Type {
+	case gjson.Number:
+		return val.Num, nil
+	case gjson.String:
+		return strconv.ParseFloat(val.Str, 64)
+	}
+	return 0, fmt.Errorf("Feed value %v is not a number", val.Raw)
+}
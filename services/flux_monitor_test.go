@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func fluxAnswer(answer int64) hexutil.Bytes {
+	return common.LeftPadBytes(big.NewInt(answer).Bytes(), 32)
+}
+
+func TestCheckFlux(t *testing.T) {
+	t.Parallel()
+
+	prices := []string{`{"price":"100.5"}`, `{"price":"101.5"}`, `{"price":"130"}`}
+	feeds := []models.FluxFeed{}
+	for _, price := range prices {
+		body := price
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+		feeds = append(feeds, models.FluxFeed{URL: server.URL, Path: []string{"price"}})
+	}
+
+	initr := models.Initiator{
+		Type:            models.InitiatorFluxMonitor,
+		Address:         cltest.NewAddress(),
+		Feeds:           feeds,
+		Threshold:       1,
+		IdleThreshold:   models.Duration(time.Hour),
+		PollingInterval: models.Duration(time.Minute),
+		Precision:       2,
+	}
+
+	tests := []struct {
+		name     string
+		answer   int64
+		lastRun  time.Duration
+		wantDue  bool
+		wantCall bool
+	}{
+		{"within threshold", 10100, 0, false, true},
+		{"past threshold", 10000, 0, true, true},
+		{"no answer yet", 0, 0, true, true},
+		{"idle", 10150, -2 * time.Hour, true, false},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			eth := cltest.MockEthOnStore(store)
+			if test.wantCall {
+				eth.Register("eth_call", fluxAnswer(test.answer))
+			}
+
+			value, due, err := services.CheckFlux(initr, store, time.Now().Add(test.lastRun))
+			assert.Nil(t, err)
+			assert.Equal(t, 101.5, value)
+			assert.Equal(t, test.wantDue, due)
+			eth.EnsureAllCalled(t)
+		})
+	}
+}
+
+func TestCheckFlux_NoFeeds(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	initr := models.Initiator{
+		Type:    models.InitiatorFluxMonitor,
+		Address: cltest.NewAddress(),
+		Feeds:   []models.FluxFeed{{URL: server.URL, Path: []string{"price"}}},
+	}
+	_, _, err := services.CheckFlux(initr, store, time.Now())
+	assert.NotNil(t, err)
+}
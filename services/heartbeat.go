@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// heartbeatGasLimit is the gas used by a transfer with no data.
+const heartbeatGasLimit uint64 = 21000
+
+// heartbeatSpendWindow is the period HEARTBEAT_SPEND_LIMIT applies to.
+const heartbeatSpendWindow = 24 * time.Hour
+
+// Heartbeat sends a transaction with no value or data from the node's
+// account to itself every HEARTBEAT_PERIOD, so that monitoring and
+// reputation systems can see on chain that the node was live and held its
+// key. A heartbeat is skipped if it would take what heartbeats have cost
+// in the last 24 hours over HEARTBEAT_SPEND_LIMIT wei, costing each at
+// ETH_GAS_PRICE_DEFAULT.
+type Heartbeat struct {
+	store *store.Store
+	done  chan struct{}
+}
+
+// NewHeartbeat returns a Heartbeat for the store's account.
+func NewHeartbeat(store *store.Store) *Heartbeat {
+	return &Heartbeat{store: store}
+}
+
+// Start sends heartbeats until Stop is called, unless HEARTBEAT_PERIOD is
+// not set.
+func (hb *Heartbeat) Start() error {
+	period := hb.store.CurrentConfig().HeartbeatPeriod
+	if period <= 0 {
+		return nil
+	}
+	hb.done = make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hb.done:
+				return
+			case <-hb.store.Clock.After(period):
+				if _, err := hb.Beat(); err != nil {
+					logger.Warnw("Sending heartbeat", "err", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sending heartbeats.
+func (hb *Heartbeat) Stop() {
+	if hb.done != nil {
+		close(hb.done)
+		hb.done = nil
+	}
+}
+
+// Beat sends a heartbeat now, unless the spend limit does not allow it.
+func (hb *Heartbeat) Beat() (models.Heartbeat, error) {
+	s := hb.store
+	if !s.Signer.HasAccounts() {
+		return models.Heartbeat{}, fmt.Errorf("Heartbeat: no account to send from")
+	}
+
+	now := s.Clock.Now()
+	sent, err := s.HeartbeatsSince(now.Add(-heartbeatSpendWindow))
+	if err != nil {
+		return models.Heartbeat{}, err
+	}
+	config := s.CurrentConfig()
+	cost := new(big.Int).Mul(&config.EthGasPriceDefault, new(big.Int).SetUint64(heartbeatGasLimit))
+	spent := new(big.Int).Set(cost)
+	for _, h := range sent {
+		spent.Add(spent, h.Cost)
+	}
+	if spent.Cmp(&config.HeartbeatSpendLimit) > 0 {
+		return models.Heartbeat{}, fmt.Errorf(
+			"Heartbeat: sending would spend %v in %v, over the limit of %v",
+			config.EthDisplayUnit.Format(spent), heartbeatSpendWindow, config.EthDisplayUnit.Format(&config.HeartbeatSpendLimit),
+		)
+	}
+
+	address := s.Signer.GetAccount().Address
+	tx, err := s.TxManager.CreateTxWithGasLimit(address, []byte{}, heartbeatGasLimit)
+	if err != nil {
+		return models.Heartbeat{}, err
+	}
+	h := models.Heartbeat{
+		TxID:      tx.ID,
+		Hash:      tx.Hash,
+		Cost:      cost,
+		CreatedAt: now,
+	}
+	if err := s.Save(&h); err != nil {
+		return h, err
+	}
+	logger.Infow("Sent heartbeat", "hash", h.Hash.Hex(), "address", address.Hex())
+	return h, nil
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeat_Beat(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	// Two heartbeats, at 21000 gas and the default gas price of 20 gwei.
+	store.Config.HeartbeatSpendLimit = *big.NewInt(840000000000000)
+	clock := cltest.UseSettableClock(store)
+	now := time.Now()
+	clock.SetTime(now)
+
+	eth := app.MockEthClient()
+	hb := services.NewHeartbeat(store)
+	account := store.KeyStore.GetAccount().Address
+	for i := uint64(0); i < 2; i++ {
+		eth.Register("eth_getTransactionCount", utils.Uint64ToHex(i))
+		eth.Register("eth_blockNumber", utils.Uint64ToHex(100))
+		eth.Register("eth_sendRawTransaction", cltest.NewHash())
+
+		h, err := hb.Beat()
+		assert.Nil(t, err)
+		assert.Equal(t, big.NewInt(420000000000000), h.Cost)
+		tx := models.Tx{}
+		assert.Nil(t, store.One("ID", h.TxID, &tx))
+		assert.Equal(t, account, tx.To)
+		assert.Equal(t, uint64(21000), tx.GasLimit)
+	}
+	eth.EnsureAllCalled(t)
+
+	_, err := hb.Beat()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "over the limit")
+
+	clock.SetTime(now.Add(25 * time.Hour))
+	eth.Register("eth_getTransactionCount", utils.Uint64ToHex(2))
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(200))
+	eth.Register("eth_sendRawTransaction", cltest.NewHash())
+	_, err = hb.Beat()
+	assert.Nil(t, err)
+	eth.EnsureAllCalled(t)
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// The changes to a job the registry is told about.
+const (
+	// RegistryJobCreated is sent when a job is added to the node.
+	RegistryJobCreated = "job_created"
+	// RegistryJobUpdated is sent when a job is paused, resumed, or
+	// unarchived.
+	RegistryJobUpdated = "job_updated"
+	// RegistryJobArchived is sent when a job is archived, so that it is no
+	// longer listed.
+	RegistryJobArchived = "job_archived"
+)
+
+// registryQueueSize is how many notices can wait to be sent before more
+// are dropped.
+const registryQueueSize = 100
+
+// registryMaxAttempts is how many times a notice is sent before it is
+// given up on.
+const registryMaxAttempts = 5
+
+// RegistryNotice tells the registry that a job changed.
+type RegistryNotice struct {
+	Event  string      `json:"event"`
+	Job    RegistryJob `json:"job"`
+	SentAt time.Time   `json:"sentAt"`
+}
+
+// RegistryJob is the public part of a job, which listing services need to
+// show what the node offers and how to request it. Task params are left
+// out, as they can hold credentials.
+type RegistryJob struct {
+	ID         string              `json:"id"`
+	Oracle     *common.Address     `json:"oracle,omitempty"`
+	Initiators []RegistryInitiator `json:"initiators"`
+	Tasks      []string            `json:"tasks"`
+	MinPayment *models.Link        `json:"minPayment,omitempty"`
+	StartAt    null.Time           `json:"startAt"`
+	EndAt      null.Time           `json:"endAt"`
+	Paused     bool                `json:"paused"`
+	Archived   bool                `json:"archived"`
+	CreatedAt  models.Time         `json:"createdAt"`
+}
+
+// RegistryInitiator is the type of a job's initiator, and the contract
+// addresses it listens to, if any.
+type RegistryInitiator struct {
+	Type      string           `json:"type"`
+	Address   *common.Address  `json:"address,omitempty"`
+	Addresses []common.Address `json:"addresses,omitempty"`
+}
+
+// NewRegistryJob returns the public part of the job, with the node's
+// account as the oracle if it has one.
+func NewRegistryJob(job models.Job, store *store.Store) RegistryJob {
+	rj := RegistryJob{
+		ID:         job.ID,
+		Initiators: []RegistryInitiator{},
+		Tasks:      []string{},
+		MinPayment: job.MinPayment,
+		StartAt:    job.StartAt,
+		EndAt:      job.EndAt,
+		Paused:     job.Paused,
+		Archived:   job.Archived,
+		CreatedAt:  job.CreatedAt,
+	}
+	if store.Signer != nil && store.Signer.HasAccounts() {
+		address := store.Signer.GetAccount().Address
+		rj.Oracle = &address
+	}
+	for _, initr := range job.Initiators {
+		ri := RegistryInitiator{Type: initr.Type, Addresses: initr.Addresses}
+		if initr.Address != (common.Address{}) {
+			address := initr.Address
+			ri.Address = &address
+		}
+		rj.Initiators = append(rj.Initiators, ri)
+	}
+	for _, task := range job.Tasks {
+		rj.Tasks = append(rj.Tasks, task.Type)
+	}
+	return rj
+}
+
+// JobRegistry tells a marketplace or listing service at REGISTRY_URL when
+// the node's jobs are created, updated, or archived, so that its listing
+// of the node's jobs stays in sync. Notices are POSTed as JSON in the order
+// the changes were made, with REGISTRY_TOKEN as a bearer token if set, and
+// retried with backoff when the registry fails. The registry is not relied
+// on, so notices are given up on after a few attempts, and are not kept
+// across restarts.
+type JobRegistry struct {
+	store  *store.Store
+	client *http.Client
+	queue  chan RegistryNotice
+	done   chan struct{}
+}
+
+// NewJobRegistry returns a JobRegistry for the store's jobs.
+func NewJobRegistry(store *store.Store) *JobRegistry {
+	return &JobRegistry{
+		store:  store,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Start sends notices until Stop is called, unless REGISTRY_URL is not
+// set.
+func (jr *JobRegistry) Start() error {
+	if jr.store.CurrentConfig().RegistryURL == "" {
+		return nil
+	}
+	jr.queue = make(chan RegistryNotice, registryQueueSize)
+	jr.done = make(chan struct{})
+	go jr.run(jr.queue, jr.done)
+	return nil
+}
+
+// Stop stops sending notices. Notices still waiting are dropped.
+func (jr *JobRegistry) Stop() {
+	if jr.done != nil {
+		close(jr.done)
+		jr.done = nil
+	}
+}
+
+// Notify queues a notice of the change to the job to be sent, unless the
+// registry is not started.
+func (jr *JobRegistry) Notify(event string, job models.Job) {
+	if jr == nil || jr.queue == nil {
+		return
+	}
+	notice := RegistryNotice{
+		Event:  event,
+		Job:    NewRegistryJob(job, jr.store),
+		SentAt: jr.store.Clock.Now(),
+	}
+	select {
+	case jr.queue <- notice:
+	default:
+		logger.Warnw("Job registry fell behind, dropping notice", "event", event, "job", job.ID)
+	}
+}
+
+func (jr *JobRegistry) run(queue chan RegistryNotice, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case notice := <-queue:
+			jr.deliver(notice, done)
+		}
+	}
+}
+
+// deliver sends the notice until the registry accepts it, or it has been
+// tried registryMaxAttempts times.
+func (jr *JobRegistry) deliver(notice RegistryNotice, done chan struct{}) {
+	retry := time.Duration(0)
+	for attempt := 1; ; attempt++ {
+		err := jr.Send(notice)
+		if err == nil {
+			return
+		}
+		if attempt == registryMaxAttempts {
+			logger.Errorw("Giving up on notifying job registry", "event", notice.Event, "job", notice.Job.ID, "err", err)
+			return
+		}
+		retry = backoff(retry)
+		logger.Warnw("Notifying job registry", "event", notice.Event, "job", notice.Job.ID, "err", err, "retry", retry)
+		select {
+		case <-done:
+			return
+		case <-jr.store.Clock.After(retry):
+		}
+	}
+}
+
+// Send POSTs the notice to the registry.
+func (jr *JobRegistry) Send(notice RegistryNotice) error {
+	b, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", jr.store.CurrentConfig().RegistryURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := jr.store.CurrentConfig().RegistryToken; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := jr.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Job registry responded %v: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -1,12 +1,19 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/adapters"
 	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/metrics"
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/tidwall/gjson"
+	null "gopkg.in/guregu/null.v3"
 )
 
 // BeginRun creates a new run if the job is valid and starts the job.
@@ -15,12 +22,59 @@ func BeginRun(job models.Job, store *store.Store, input models.RunResult) (model
 	if err != nil {
 		return models.JobRun{}, err
 	}
+	if run, err = QueueRun(run, store, input); err != nil {
+		return run, wrapError(run, err)
+	}
 	return ExecuteRun(run, store, input)
 }
 
+// QueueRun saves the new run as queued, with its input as the run's
+// result, before it waits in the RunQueue for a worker, so that Recover
+// starts it on boot if the node stops first.
+func QueueRun(run models.JobRun, store *store.Store, input models.RunResult) (models.JobRun, error) {
+	run.Result = input
+	run.Result.JobRunID = run.ID
+	return transitionRun(run, models.StatusQueued, store)
+}
+
+// TriggerRun begins a run for one of the job's initiators, unless the job
+// is paused, in which case the run is recorded as skipped so that it can
+// be replayed when the job is resumed.
+func TriggerRun(
+	job models.Job,
+	initiatorType string,
+	store *store.Store,
+	input models.RunResult,
+) (models.JobRun, error) {
+	job = reloadFlags(job, store)
+	if !job.Paused || job.Archived {
+		return BeginRun(job, store, input)
+	}
+
+	logger.Infow(fmt.Sprintf("Job %v paused, skipping %v run", job.ID, initiatorType), "job", job.ID)
+	return models.JobRun{}, skipRun(job, initiatorType, models.SkipReasonPaused, store, input)
+}
+
+// skipRun records that the initiator fired without starting a run, and
+// why.
+func skipRun(job models.Job, initiatorType, reason string, store *store.Store, input models.RunResult) error {
+	now := store.Clock.Now()
+	skipped := models.SkippedRun{
+		JobID:         job.ID,
+		InitiatorType: initiatorType,
+		Reason:        reason,
+		Input:         input,
+		Count:         1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	return store.SaveSkippedRun(&skipped)
+}
+
 // BuildRun checks to ensure the given job has not started or ended before
 // creating a new run for the job.
 func BuildRun(job models.Job, store *store.Store) (models.JobRun, error) {
+	job = reloadFlags(job, store)
 	now := store.Clock.Now()
 	if !job.Started(now) {
 		return models.JobRun{}, JobRunnerError{
@@ -32,15 +86,51 @@ func BuildRun(job models.Job, store *store.Store) (models.JobRun, error) {
 			msg: fmt.Sprintf("Job runner: Job %v ended: %v past job's end time %v", job.ID, now, job.EndAt),
 		}
 	}
-	return job.NewRun(), nil
+	if job.Paused {
+		return models.JobRun{}, JobRunnerError{
+			msg: fmt.Sprintf("Job runner: Job %v paused", job.ID),
+		}
+	}
+	if job.Archived {
+		return models.JobRun{}, JobRunnerError{
+			msg: fmt.Sprintf("Job runner: Job %v archived", job.ID),
+		}
+	}
+	run := job.NewRun()
+	metrics.RunsStarted.Inc(job.ID)
+	store.Events.Publish(runEvent(models.EventRunCreated, run, nil))
+	return run, nil
+}
+
+// reloadFlags reads the flags that operators change from the saved job,
+// since schedulers and subscriptions hold on to the job they were started
+// with.
+func reloadFlags(job models.Job, store *store.Store) models.Job {
+	if saved, err := store.FindJob(job.ID); err == nil {
+		job.Paused = saved.Paused
+		job.Archived = saved.Archived
+	}
+	return job
+}
+
+// jobRunLimit returns the most runs of the run's job that may execute at
+// once, or 0 if the job does not cap them.
+func jobRunLimit(run models.JobRun, store *store.Store) int {
+	if job, err := store.FindJob(run.JobID); err == nil {
+		return int(job.MaxConcurrentRuns)
+	}
+	return 0
 }
 
 // ExecuteRun starts the job and executes task runs within that job in the
 // order defined in the run for as long as they do not return errors. Results
 // are saved in the store (db).
 func ExecuteRun(run models.JobRun, store *store.Store, input models.RunResult) (models.JobRun, error) {
-	run.Status = models.StatusInProgress
-	if err := store.Save(&run); err != nil {
+	done := store.RunQueue.WorkLimited(run, jobRunLimit(run, store))
+	defer done()
+
+	run, err := transitionRun(run, models.StatusInProgress, store)
+	if err != nil {
 		return run, wrapError(run, err)
 	}
 
@@ -60,60 +150,486 @@ func ExecuteRun(run models.JobRun, store *store.Store, input models.RunResult) (
 		if err != nil {
 			return run, wrapError(run, err)
 		}
-		prevRun = startTask(taskRun, prevRun.Result, store)
-		logger.Debugw("Produced task run", "tr", prevRun)
+		taskInput := prevRun.Result
+		met, err := conditionMet(run, i+offset, taskRun, taskInput, store)
+		if err != nil {
+			prevRun = transitionTask(taskRun, models.StatusErrored)
+			prevRun.Result = taskInput.WithError(err)
+		} else if !met {
+			prevRun = skipTask(taskRun, taskInput)
+		} else {
+			started := time.Now()
+			taskRun.RetryAt = null.Time{}
+			prevRun = startTask(run, taskRun, taskInput, store)
+			metrics.TaskDuration.Observe(time.Since(started).Seconds(), taskRun.Task.Type)
+			if prevRun.Errored() && !prevRun.Result.TerminalError() && prevRun.Task.Retry.ShouldRetry(prevRun.Attempts, prevRun.Result.Error()) {
+				return scheduleRetry(run, i+offset, prevRun, taskInput, store)
+			}
+			if prevRun.Errored() && len(prevRun.Task.OnError) > 0 {
+				prevRun = performFallbacks(run, prevRun, taskInput, store)
+			}
+		}
+		if run.Debug {
+			prevRun.Input = &taskInput
+		}
 		run.TaskRuns[i+offset] = prevRun
-		if err := store.Save(&run); err != nil {
+		saved := sanitizeRun(run, store)
+		if err := store.Save(&saved); err != nil {
 			return run, wrapError(run, err)
 		}
 
+		// Only the sanitized copy of the task run is logged and published,
+		// as it is saved.
+		savedTask := saved.TaskRuns[i+offset]
+		logger.Debugw("Produced task run", "run", run.ID, "tr", savedTask)
+		if run.Debug {
+			logger.Infow(
+				fmt.Sprintf("Debug sample for task %v", taskRun.Task.Type),
+				savedTask.ForLogger("run", run.ID, "input", *savedTask.Input, "output", savedTask.Result)...,
+			)
+		}
 		if prevRun.Result.Pending {
-			logger.Infow(fmt.Sprintf("Task %v pending", taskRun.Task.Type), taskRun.ForLogger("task", i, "result", prevRun.Result)...)
+			logger.Infow(fmt.Sprintf("Task %v pending", taskRun.Task.Type), taskRun.ForLogger("run", run.ID, "task", i, "result", savedTask.Result)...)
 			break
 		}
-		logger.Infow(fmt.Sprintf("Task %v finished", taskRun.Task.Type), taskRun.ForLogger("task", i, "result", prevRun.Result)...)
+		logger.Infow(fmt.Sprintf("Task %v finished", taskRun.Task.Type), taskRun.ForLogger("run", run.ID, "task", i, "result", savedTask.Result)...)
 		if prevRun.Result.HasError() {
 			break
 		}
+		store.Events.Publish(runEvent(models.EventTaskCompleted, run, savedTask))
 	}
 
 	run.Result = prevRun.Result
-	if run.Result.HasError() {
-		run.Status = models.StatusErrored
-	} else if run.Result.Pending {
-		run.Status = models.StatusPending
-	} else {
-		run.Status = models.StatusCompleted
+	run, err = transitionRun(run, run.Result.Status(), store)
+	if err != nil {
+		return run, wrapError(run, err)
 	}
-
 	logger.Infow("Finished current job run execution", run.ForLogger()...)
-	return run, wrapError(run, store.Save(&run))
+	return run, wrapError(run, tripCircuitBreaker(run, store))
+}
+
+// ResumeRun continues a pending run with the result reported back by an
+// external adapter. An errored result errors the pending task and the run,
+// otherwise execution continues from the next task.
+func ResumeRun(run models.JobRun, store *store.Store, input models.RunResult) (models.JobRun, error) {
+	if !input.HasError() {
+		return ExecuteRun(run, store, input)
+	}
+
+	i := len(run.TaskRuns) - len(run.UnfinishedTaskRuns())
+	if i >= len(run.TaskRuns) {
+		return run, wrapError(run, fmt.Errorf("no pending task run to resume"))
+	}
+	tr := transitionTask(run.TaskRuns[i], models.StatusErrored)
+	tr.Result = tr.Result.WithError(input.GetError())
+	run.TaskRuns[i] = tr
+	run.Result = tr.Result
+
+	run, err := transitionRun(run, models.StatusErrored, store)
+	if err != nil {
+		return run, wrapError(run, err)
+	}
+	logger.Infow("Job run errored by external adapter", run.ForLogger()...)
+	return run, wrapError(run, tripCircuitBreaker(run, store))
+}
+
+// runHooks are called once a run has moved to the status they are listed
+// under and been saved. They are given the run, and the sanitized copy of
+// it that was saved, which is what is published outside the node.
+var runHooks = map[string][]func(run, saved models.JobRun, store *store.Store){
+	models.StatusCompleted: {
+		func(run, saved models.JobRun, store *store.Store) {
+			metrics.RunsCompleted.Inc(run.JobID)
+			store.Events.Publish(runEvent(models.EventRunCompleted, saved, saved.Result))
+		},
+		func(run, saved models.JobRun, store *store.Store) {
+			if err := ObserveFeeds(store, run); err != nil {
+				logger.Warnw("Observing feeds", "job", run.JobID, "run", run.ID, "err", err)
+			}
+		},
+	},
+	models.StatusErrored: {
+		func(run, saved models.JobRun, store *store.Store) {
+			metrics.RunsErrored.Inc(run.JobID)
+			store.Events.Publish(runEvent(models.EventRunErrored, saved, saved.Result))
+		},
+	},
+}
+
+// transitionRun moves the run to the given status and saves it, then calls
+// the hooks for that status. Completed runs are given their CompletedAt.
+func transitionRun(run models.JobRun, to string, store *store.Store) (models.JobRun, error) {
+	if to == models.StatusCompleted {
+		run.CompletedAt = null.TimeFrom(store.Clock.Now())
+	}
+	saved := sanitizeRun(run, store)
+	if err := store.TransitionJobRun(&saved, to); err != nil {
+		return run, err
+	}
+	run.Status = saved.Status
+	for _, hook := range runHooks[to] {
+		hook(run, saved, store)
+	}
+	return run, nil
+}
+
+// sanitizeRun returns a copy of the run to save, with its results cut to
+// RUN_RESULT_MAX_SIZE and stripped of the keys on RUN_RESULT_DENYLIST. The
+// run itself keeps the full results, so that the next task is given them.
+func sanitizeRun(run models.JobRun, store *store.Store) models.JobRun {
+	maxSize := int(store.CurrentConfig().RunResultMaxSize)
+	denylist := []string{}
+	for _, key := range strings.Split(store.CurrentConfig().RunResultDenylist, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			denylist = append(denylist, key)
+		}
+	}
+	sanitize := func(rr models.RunResult) models.RunResult {
+		sanitized, err := rr.Sanitize(maxSize, denylist)
+		if err != nil {
+			logger.Warnw(fmt.Sprintf("Saving run result unsanitized: %v", err), "run", run.ID)
+		}
+		return sanitized
+	}
+
+	run.Result = sanitize(run.Result)
+	taskRuns := make([]models.TaskRun, len(run.TaskRuns))
+	for i, tr := range run.TaskRuns {
+		tr.Result = sanitize(tr.Result)
+		if tr.Input != nil {
+			input := sanitize(*tr.Input)
+			tr.Input = &input
+		}
+		if tr.Trace != nil {
+			tr.Trace = sanitizeTrace(*tr.Trace, denylist)
+		}
+		taskRuns[i] = tr
+	}
+	run.TaskRuns = taskRuns
+	return run
+}
+
+// sanitizeTrace returns a copy of the trace to save, with the values of
+// the environment variables templates read taken out, and the keys on
+// RUN_RESULT_DENYLIST stripped from the bodies that are JSON. Traces are
+// kept whole otherwise, as they are what a run is sampled for.
+func sanitizeTrace(trace models.TaskTrace, denylist []string) *models.TaskTrace {
+	body := func(s string) string {
+		s = models.RedactTemplateEnv(s)
+		if !gjson.Valid(s) {
+			return s
+		}
+		sanitized, err := models.JSON{Result: gjson.Parse(s)}.Sanitize(0, denylist)
+		if err != nil {
+			return s
+		}
+		return string(sanitized.Bytes())
+	}
+	requests := make([]models.TracedRequest, len(trace.Requests))
+	for i, req := range trace.Requests {
+		req.URL = models.RedactTemplateEnv(req.URL)
+		req.Body = body(req.Body)
+		req.Response = body(req.Response)
+		req.Error = models.RedactTemplateEnv(req.Error)
+		requests[i] = req
+	}
+	trace.Requests = requests
+	trace.CallData = models.RedactTemplateEnv(trace.CallData)
+	return &trace
+}
+
+// transitionTask moves the task run to the given status. The runner only
+// moves task runs that have not completed, so a transition that is not
+// allowed is a bug, and errors the task run instead.
+func transitionTask(tr models.TaskRun, to string) models.TaskRun {
+	if err := tr.Transition(to); err != nil {
+		logger.Errorw(err.Error(), tr.ForLogger()...)
+		tr.Status = models.StatusErrored
+		tr.Result = tr.Result.WithError(err)
+	}
+	return tr
+}
+
+// runEvent returns an event about the run, with the given data.
+func runEvent(eventType string, run models.JobRun, data interface{}) models.Event {
+	return models.Event{
+		Type:  eventType,
+		JobID: run.JobID,
+		RunID: run.ID,
+		Data:  data,
+	}
+}
+
+// tripCircuitBreaker pauses the run's job once its most recent runs have
+// errored JOB_FAILURE_THRESHOLD times in a row, so that a broken job stops
+// using API quota and gas until an operator looks into it.
+func tripCircuitBreaker(run models.JobRun, store *store.Store) error {
+	threshold := store.CurrentConfig().JobFailureThreshold
+	if threshold == 0 || run.Status != models.StatusErrored {
+		return nil
+	}
+
+	runs, err := store.JobRunsFor(run.JobID)
+	if err != nil {
+		return err
+	}
+	var failures uint64
+	for _, jr := range runs {
+		if jr.Status != models.StatusErrored {
+			break
+		}
+		failures++
+	}
+	if failures < threshold {
+		return nil
+	}
+
+	job, err := store.FindJob(run.JobID)
+	if err != nil || job.Paused {
+		return err
+	}
+	job.Paused = true
+	if err := store.Save(&job); err != nil {
+		return err
+	}
+	logger.Errorw(
+		fmt.Sprintf("Job %v paused after %v consecutive failed runs", job.ID, failures),
+		run.ForLogger("failures", failures)...,
+	)
+	return nil
+}
+
+// conditionMet checks a task's onlyIf condition against its input and, for
+// deviation checks, the input it was given the last time it was performed.
+func conditionMet(
+	run models.JobRun,
+	index int,
+	tr models.TaskRun,
+	input models.RunResult,
+	store *store.Store,
+) (bool, error) {
+	cond := tr.Task.OnlyIf
+	if cond == nil {
+		return true, nil
+	}
+	var last models.JSON
+	if cond.Operator == models.ConditionDeviates {
+		var err error
+		if last, err = lastPerformedInput(run, index, store); err != nil {
+			return false, err
+		}
+	}
+	return cond.Met(input.Data, last)
+}
+
+// lastPerformedInput returns the data passed into the task at the given
+// index the last time it was performed in a completed run of the same job.
+func lastPerformedInput(run models.JobRun, index int, store *store.Store) (models.JSON, error) {
+	if index == 0 {
+		return models.JSON{}, nil
+	}
+	runs, err := store.JobRunsFor(run.JobID)
+	if err != nil {
+		return models.JSON{}, err
+	}
+	for _, jr := range runs {
+		if jr.ID == run.ID || jr.Status != models.StatusCompleted || len(jr.TaskRuns) <= index {
+			continue
+		}
+		if tr := jr.TaskRuns[index]; !tr.Skipped {
+			return jr.TaskRuns[index-1].Result.Data, nil
+		}
+	}
+	return models.JSON{}, nil
+}
+
+func skipTask(tr models.TaskRun, input models.RunResult) models.TaskRun {
+	logger.Infow(fmt.Sprintf("Task %v skipped, onlyIf condition not met", tr.Task.Type), tr.ForLogger()...)
+	tr = transitionTask(tr, models.StatusCompleted)
+	tr.Skipped = true
+	tr.Result = input
+	return tr
+}
+
+// scheduleRetry puts a failed task run back in progress with the input it
+// was given and executes the run again once the task's backoff has passed.
+func scheduleRetry(
+	run models.JobRun,
+	index int,
+	tr models.TaskRun,
+	input models.RunResult,
+	store *store.Store,
+) (models.JobRun, error) {
+	delay := tr.Task.Retry.Delay(tr.Attempts)
+	logger.Infow(
+		fmt.Sprintf("Task %v failed, retrying in %v", tr.Task.Type, delay),
+		tr.ForLogger("run", run.ID, "attempts", tr.Attempts)...,
+	)
+
+	tr = transitionTask(tr, models.StatusInProgress)
+	tr.Result = input
+	tr.RetryAt = null.TimeFrom(store.Clock.Now().Add(delay))
+	run.TaskRuns[index] = tr
+	saved := sanitizeRun(run, store)
+	if err := store.Save(&saved); err != nil {
+		return run, wrapError(run, err)
+	}
+
+	executeRetryAfter(run, delay, store)
+	return run, nil
+}
+
+// executeRetryAfter executes the run again once the delay has passed.
+func executeRetryAfter(run models.JobRun, delay time.Duration, store *store.Store) {
+	go func() {
+		<-store.Clock.After(delay)
+		if _, err := ExecuteRun(run, store, models.RunResult{}); err != nil {
+			logger.Errorw(fmt.Sprintf("Retrying task: %v", err.Error()))
+		}
+	}()
+}
+
+// performFallbacks performs a failed task's onError tasks in order, starting
+// from the input the failed task was given. If they all complete, the last
+// one's result stands in for the failed task's and the run carries on.
+func performFallbacks(
+	jr models.JobRun,
+	tr models.TaskRun,
+	input models.RunResult,
+	store *store.Store,
+) models.TaskRun {
+	cause := tr.Result.Error()
+	logger.Infow(
+		fmt.Sprintf("Task %v failed, performing onError tasks", tr.Task.Type),
+		tr.ForLogger("run", jr.ID, "fallbacks", len(tr.Task.OnError))...,
+	)
+
+	result := input
+	for i, task := range tr.Task.OnError {
+		fallback := startTask(jr, models.TaskRun{Task: task}, result, store)
+		if !fallback.Completed() {
+			msg := fallback.Result.Error()
+			if fallback.Result.Pending {
+				msg = "pending tasks are not supported"
+			}
+			tr.Result = tr.Result.WithError(fmt.Errorf("%v; onError task %v (%v): %v", cause, i, task.Type, msg))
+			return tr
+		}
+		result = fallback.Result
+	}
+
+	tr = transitionTask(tr, models.StatusCompleted)
+	tr.Result = result
+	tr.RecoveredFrom = cause
+	return tr
 }
 
 func startTask(
+	jr models.JobRun,
 	run models.TaskRun,
 	input models.RunResult,
 	store *store.Store,
 ) models.TaskRun {
-	run.Status = models.StatusInProgress
+	run = transitionTask(run, models.StatusInProgress)
+	run.Attempts++
 
-	adapter, err := adapters.For(run.Task, store)
+	// The interpolated task is only handed to the adapter, so that values
+	// read from the environment are not saved with the run.
+	task, err := run.Task.Interpolate(templateVars(jr, input))
 	if err != nil {
-		run.Status = models.StatusErrored
+		run = transitionTask(run, models.StatusErrored)
+		run.Result = input.WithError(err)
+		return run
+	}
+
+	strategy, err := executionStrategyFor(task, store)
+	if err != nil {
+		run = transitionTask(run, models.StatusErrored)
 		run.Result.SetError(err)
 		return run
 	}
 
-	run.Result = adapter.Perform(input, store)
-	if run.Result.HasError() {
-		run.Status = models.StatusErrored
-	} else if run.Result.Pending {
-		run.Status = models.StatusPending
-	} else {
-		run.Status = models.StatusCompleted
+	// Adapters such as EthTx key what they send on the run's ID, so that
+	// it is not sent again when the run is resumed.
+	input.JobRunID = jr.ID
+	interpolated := run
+	interpolated.Task = task
+	ctx := context.Background()
+	var tracer *adapters.Tracer
+	if jr.Debug {
+		tracer = &adapters.Tracer{}
+		ctx = adapters.WithTracer(ctx, tracer)
+	}
+	run.Result, run.Attestation = performTask(ctx, jr, interpolated, strategy, input, store)
+	// A resumed task sends nothing, so it keeps the trace of its first
+	// attempt.
+	if trace := tracer.Trace(); trace != nil {
+		run.Trace = trace
 	}
+	return transitionTask(run, run.Result.Status())
+}
 
-	return run
+// performTask performs the task run with the strategy, cancelling its work
+// and erroring the result with a TimeoutError once the task's timeout, or
+// what is left of its run's, has passed. A transaction is sent even once
+// its task times out, so EthTx tasks are only refused once their run has
+// timed out, and are otherwise waited on.
+func performTask(
+	ctx context.Context,
+	jr models.JobRun,
+	tr models.TaskRun,
+	strategy ExecutionStrategy,
+	input models.RunResult,
+	store *store.Store,
+) (models.RunResult, *models.Attestation) {
+	timeout := tr.Task.Timeout
+	timeoutErr := &models.TimeoutError{Timeout: tr.Task.Timeout}
+	if job, err := store.FindJob(jr.JobID); err == nil && job.Timeout > 0 {
+		left := jr.CreatedAt.Add(time.Duration(job.Timeout)).Sub(store.Clock.Now())
+		if left <= 0 {
+			return input.WithError(&models.TimeoutError{Timeout: time.Duration(job.Timeout), Run: true}), nil
+		}
+		if timeout == 0 || left < timeout {
+			timeout = left
+			timeoutErr = &models.TimeoutError{Timeout: time.Duration(job.Timeout), Run: true}
+		}
+	}
+	if timeout == 0 || sendsTransaction(tr.Task) {
+		return strategy.Perform(ctx, tr, input, store)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	rr, attestation := strategy.Perform(ctx, tr, input, store)
+	if rr.HasError() && ctx.Err() == context.DeadlineExceeded {
+		return input.WithError(timeoutErr), nil
+	}
+	return rr, attestation
+}
+
+// sendsTransaction returns true if the task's adapter sends a transaction,
+// which cannot be taken back once the task's work has started.
+func sendsTransaction(task models.Task) bool {
+	return strings.ToLower(task.Type) == "ethtx"
+}
+
+// templateVars are the variables that task params can reference, such as
+// {{ .jobRun.id }} or {{ .data.value }}.
+func templateVars(jr models.JobRun, input models.RunResult) map[string]interface{} {
+	data := input.Data.Value()
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"job": map[string]interface{}{
+			"id": jr.JobID,
+		},
+		"jobRun": map[string]interface{}{
+			"id":        jr.ID,
+			"createdAt": utils.ISO8601UTC(jr.CreatedAt),
+		},
+		"data": data,
+	}
 }
 
 func wrapError(run models.JobRun, err error) error {
@@ -1,13 +1,21 @@
 package services_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/onsi/gomega"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
 	"github.com/smartcontractkit/chainlink/services"
 	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
 	"github.com/stretchr/testify/assert"
 	null "gopkg.in/guregu/null.v3"
 )
@@ -60,6 +68,7 @@ func TestJobRunner_ExecuteRun(t *testing.T) {
 			store.One("ID", run.ID, &run)
 			assert.Equal(t, test.wantStatus, run.Status)
 			assert.Equal(t, test.wantData, run.Result.Data.String())
+			assert.Equal(t, test.wantStatus == models.StatusCompleted, run.CompletedAt.Valid)
 
 			tr1 := run.TaskRuns[0]
 			assert.Equal(t, test.wantStatus, tr1.Status)
@@ -171,3 +180,478 @@ func TestJobRunner_BuildRun(t *testing.T) {
 		})
 	}
 }
+
+func TestJobRunner_BuildRun_ArchivedOrPausedSinceScheduled(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		update func(*models.Job)
+	}{
+		{"archived", func(j *models.Job) { j.Archived = true }},
+		{"paused", func(j *models.Job) { j.Paused = true }},
+	}
+
+	for _, test := range tests {
+		scheduled := cltest.NewJob()
+		assert.Nil(t, store.SaveJob(&scheduled))
+		saved := scheduled
+		test.update(&saved)
+		assert.Nil(t, store.Save(&saved))
+
+		_, err := services.BuildRun(scheduled, store)
+		assert.NotNil(t, err, test.name)
+	}
+}
+
+func TestJobRunner_TriggerRun_Paused(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJobWithLogInitiator()
+	job.Paused = true
+	assert.Nil(t, store.SaveJob(&job))
+
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":"100"}`)}
+	_, err := services.TriggerRun(job, models.InitiatorEthLog, store, input)
+	assert.Nil(t, err)
+
+	runs, err := store.JobRunsFor(job.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, runs)
+	skipped, err := store.SkippedRunsFor(job.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(skipped))
+	assert.Equal(t, models.InitiatorEthLog, skipped[0].InitiatorType)
+	assert.Equal(t, "100", skipped[0].Input.Data.Get("value").String())
+}
+
+func TestJobRunner_ExecuteRun_RetriesTask(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	cltest.UseSettableClock(store)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(503)
+			io.WriteString(w, "try again")
+			return
+		}
+		io.WriteString(w, "100")
+	}))
+	defer server.Close()
+
+	var task models.Task
+	spec := fmt.Sprintf(`{"type":"httpget","url":"%v","maxRetries":2,"retryable":["^503 "]}`, server.URL)
+	assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+	job := models.NewJob()
+	job.Tasks = []models.Task{task, {Type: "noop"}}
+	assert.Nil(t, store.SaveJob(&job))
+
+	run, err := services.ExecuteRun(job.NewRun(), store, models.RunResult{})
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusInProgress, run.Status)
+	assert.Equal(t, uint(1), run.TaskRuns[0].Attempts)
+	assert.True(t, run.TaskRuns[0].RetryAt.Valid)
+
+	gomega.NewGomegaWithT(t).Eventually(func() string {
+		assert.Nil(t, store.One("ID", run.ID, &run))
+		return run.Status
+	}).Should(gomega.Equal(models.StatusCompleted))
+	assert.Equal(t, uint(2), run.TaskRuns[0].Attempts)
+	assert.False(t, run.TaskRuns[0].RetryAt.Valid)
+	value, err := run.Result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "100", value)
+}
+
+func TestJobRunner_ExecuteRun_NonRetryableError(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	server, cleanupServer := cltest.NewHTTPMockServer(t, 400, "GET", "bad request")
+	defer cleanupServer()
+
+	var task models.Task
+	spec := fmt.Sprintf(`{"type":"httpget","url":"%v","maxRetries":2,"retryable":["^503 "]}`, server.URL)
+	assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+	job := models.NewJob()
+	job.Tasks = []models.Task{task}
+	assert.Nil(t, store.SaveJob(&job))
+
+	run, err := services.ExecuteRun(job.NewRun(), store, models.RunResult{})
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusErrored, run.Status)
+	assert.Equal(t, uint(1), run.TaskRuns[0].Attempts)
+}
+
+func TestJobRunner_ExecuteRun_Timeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		taskTimeout  string
+		runTimeout   time.Duration
+		wantAttempts uint
+		wantCode     string
+	}{
+		{"task", `"timeout":"20ms","maxRetries":1,"backoff":"1ms",`, 0, 2, models.ErrorCodeTimeout},
+		{"run", `"maxRetries":1,`, 20 * time.Millisecond, 1, models.ErrorCodeRunTimeout},
+		{"run before task", `"timeout":"1h",`, 20 * time.Millisecond, 1, models.ErrorCodeRunTimeout},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			}))
+			defer server.Close()
+
+			var task models.Task
+			spec := fmt.Sprintf(`{"type":"httpget",%v"url":"%v"}`, test.taskTimeout, server.URL)
+			assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+			job := models.NewJob()
+			job.Tasks = []models.Task{task}
+			job.Timeout = models.Duration(test.runTimeout)
+			assert.Nil(t, store.SaveJob(&job))
+
+			run, err := services.ExecuteRun(job.NewRun(), store, models.RunResult{})
+			assert.Nil(t, err)
+			gomega.NewGomegaWithT(t).Eventually(func() string {
+				assert.Nil(t, store.One("ID", run.ID, &run))
+				return run.Status
+			}).Should(gomega.Equal(models.StatusErrored))
+			assert.Equal(t, test.wantAttempts, run.TaskRuns[0].Attempts)
+			assert.Equal(t, test.wantCode, run.Result.ErrorCode.String)
+			assert.Contains(t, run.Result.Error(), "timed out after 20ms")
+		})
+	}
+}
+
+func TestJobRunner_ExecuteRun_RunTimeoutClock(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	clock := cltest.UseSettableClock(store)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		io.WriteString(w, "100")
+	}))
+	defer server.Close()
+
+	var task models.Task
+	assert.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(`{"type":"httpget","url":"%v"}`, server.URL)), &task))
+	job := models.NewJob()
+	job.Tasks = []models.Task{task}
+	job.Timeout = models.Duration(time.Hour)
+	assert.Nil(t, store.SaveJob(&job))
+
+	run := job.NewRun()
+	clock.SetTime(run.CreatedAt.Add(2 * time.Hour))
+	run, err := services.ExecuteRun(run, store, models.RunResult{})
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusErrored, run.Status)
+	assert.Equal(t, models.ErrorCodeRunTimeout, run.Result.ErrorCode.String)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests), "the run's deadline is read from the store's clock")
+}
+
+func TestJobRunner_ExecuteRun_TerminalTxError(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(256))
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+	ethMock.RegisterError("eth_sendRawTransaction", "insufficient funds for gas * price + value")
+
+	var task models.Task
+	spec := fmt.Sprintf(`{"type":"ethtx","address":"%v","functionSelector":"0xb3f98adc","maxRetries":2}`, cltest.NewAddress().Hex())
+	assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+	job := models.NewJob()
+	job.Tasks = []models.Task{task}
+
+	run, err := services.ExecuteRun(job.NewRun(), store, cltest.RunResultWithValue("0x9786856756"))
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusErrored, run.Status)
+	assert.Equal(t, uint(1), run.TaskRuns[0].Attempts)
+	assert.Equal(t, models.TxErrorInsufficientFunds, run.Result.ErrorCode.String)
+	ethMock.EnsureAllCalled(t)
+}
+
+func TestJobRunner_ExecuteRun_OnError(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	primary, cleanupPrimary := cltest.NewHTTPMockServer(t, 500, "GET", "down")
+	defer cleanupPrimary()
+	secondary, cleanupSecondary := cltest.NewHTTPMockServer(t, 200, "GET", `{"last":"42"}`)
+	defer cleanupSecondary()
+	broken, cleanupBroken := cltest.NewHTTPMockServer(t, 500, "GET", "also down")
+	defer cleanupBroken()
+
+	tests := []struct {
+		name       string
+		fallback   string
+		wantStatus string
+		wantValue  string
+		wantError  string
+	}{
+		{"fallback completes", secondary.URL, models.StatusCompleted, "42", ""},
+		{"fallback fails", broken.URL, models.StatusErrored, "", "onError task 0 (httpget)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var task models.Task
+			spec := fmt.Sprintf(`{"type":"httpget","url":"%v","onError":[
+				{"type":"httpget","url":"%v"},
+				{"type":"jsonparse","path":["last"]}
+			]}`, primary.URL, test.fallback)
+			assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+			job := models.NewJob()
+			job.Tasks = []models.Task{task, {Type: "noop"}}
+			assert.Nil(t, store.SaveJob(&job))
+
+			run, err := services.ExecuteRun(job.NewRun(), store, models.RunResult{})
+			assert.Nil(t, err)
+			assert.Equal(t, test.wantStatus, run.Status)
+			if test.wantError != "" {
+				assert.Contains(t, run.Result.Error(), test.wantError)
+				return
+			}
+			assert.Contains(t, run.TaskRuns[0].RecoveredFrom, "500")
+			assert.Equal(t, models.StatusCompleted, run.TaskRuns[1].Status)
+			value, err := run.Result.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, test.wantValue, value)
+		})
+	}
+}
+
+func TestJobRunner_ExecuteRun_OnlyIf(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	var task models.Task
+	spec := `{"type":"noop","onlyIf":{"operator":"deviates","value":"1"}}`
+	assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+	job := models.NewJob()
+	job.Tasks = []models.Task{{Type: "noop"}, task}
+	assert.Nil(t, store.SaveJob(&job))
+
+	tests := []struct {
+		name        string
+		value       string
+		wantSkipped bool
+	}{
+		{"first run", "100", false},
+		{"within threshold", "100.5", true},
+		{"beyond threshold of last performed", "101.5", false},
+	}
+
+	for _, test := range tests {
+		input := models.RunResult{Data: cltest.JSONFromString(fmt.Sprintf(`{"value":"%v"}`, test.value))}
+		run, err := services.ExecuteRun(job.NewRun(), store, input)
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, models.StatusCompleted, run.Status, test.name)
+		assert.Equal(t, test.wantSkipped, run.TaskRuns[1].Skipped, test.name)
+		value, err := run.Result.Value()
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.value, value, test.name)
+	}
+}
+
+func TestJobRunner_ExecuteRun_DebugSampling(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	tests := []struct {
+		name      string
+		rate      float64
+		wantDebug bool
+	}{
+		{"not sampled", 0, false},
+		{"sampled", 100, true},
+	}
+
+	for _, test := range tests {
+		job := models.NewJob()
+		job.Tasks = []models.Task{{Type: "noop"}}
+		job.DebugSampleRate = test.rate
+
+		input := models.RunResult{Data: cltest.JSONFromString(`{"value":"100"}`)}
+		run, err := services.ExecuteRun(job.NewRun(), store, input)
+		assert.Nil(t, err, test.name)
+
+		assert.Nil(t, store.One("ID", run.ID, &run))
+		assert.Equal(t, test.wantDebug, run.Debug, test.name)
+		tr := run.TaskRuns[0]
+		if test.wantDebug {
+			assert.NotNil(t, tr.Input, test.name)
+			assert.Equal(t, `{"value":"100"}`, tr.Input.Data.String(), test.name)
+		} else {
+			assert.Nil(t, tr.Input, test.name)
+		}
+	}
+}
+
+func TestJobRunner_ExecuteRun_SanitizesSavedResults(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.RunResultDenylist = "secret, apiKey"
+
+	events := store.Events.Subscribe()
+	defer store.Events.Unsubscribe(events)
+
+	job := models.NewJob()
+	job.Tasks = []models.Task{{Type: "noop"}, {Type: "noop"}}
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":"100","secret":"s"}`)}
+	run, err := services.ExecuteRun(job.NewRun(), store, input)
+	assert.Nil(t, err)
+	assert.Equal(t, "s", run.Result.Data.Get("secret").String())
+
+	published := []string{}
+	for len(events) > 0 {
+		event := <-events
+		published = append(published, event.Type)
+		switch data := event.Data.(type) {
+		case models.TaskRun:
+			assert.JSONEq(t, `{"value":"100"}`, data.Result.Data.String(), event.Type)
+		case models.RunResult:
+			assert.JSONEq(t, `{"value":"100"}`, data.Data.String(), event.Type)
+		}
+	}
+	assert.Equal(t, []string{models.EventTaskCompleted, models.EventTaskCompleted, models.EventRunCompleted}, published)
+
+	assert.Nil(t, store.One("ID", run.ID, &run))
+	assert.Equal(t, models.StatusCompleted, run.Status)
+	assert.JSONEq(t, `{"value":"100"}`, run.Result.Data.String())
+	for _, tr := range run.TaskRuns {
+		assert.False(t, tr.Result.Data.Get("secret").Exists())
+	}
+}
+
+func TestJobRunner_ExecuteRun_InterpolatesParams(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	job := models.NewJob()
+	job.Tasks = []models.Task{
+		cltest.NewTask("httpget", fmt.Sprintf(`{"url":"%v/{{ .data.symbol }}/{{ .jobRun.id }}"}`, server.URL)),
+		cltest.NewTask("httpget", `{"url":"{{ .data.missing }}"}`),
+	}
+	assert.Nil(t, store.SaveJob(&job))
+	input := models.RunResult{Data: cltest.JSONFromString(`{"symbol":"ETH"}`)}
+
+	run, err := services.ExecuteRun(job.NewRun(), store, input)
+	assert.Nil(t, err)
+	assert.Equal(t, "/ETH/"+run.ID, requestedPath)
+	assert.Equal(t, models.StatusErrored, run.Status)
+	assert.Contains(t, run.Result.Error(), "missing")
+	assert.Contains(t, run.TaskRuns[0].Task.Params.String(), "{{ .jobRun.id }}")
+}
+
+func TestJobRunner_ExecuteRun_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+	config, configCleanup := cltest.NewConfig()
+	defer configCleanup()
+	config.JobFailureThreshold = 2
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+
+	job := cltest.NewJob()
+	job.Tasks = []models.Task{{Type: "ethuint256"}}
+	assert.Nil(t, store.SaveJob(&job))
+	input := cltest.RunResultWithValue("-1")
+
+	run, err := services.BeginRun(job, store, input)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusErrored, run.Status)
+	job, err = store.FindJob(job.ID)
+	assert.Nil(t, err)
+	assert.False(t, job.Paused)
+
+	run, err = services.BeginRun(job, store, input)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusErrored, run.Status)
+	job, err = store.FindJob(job.ID)
+	assert.Nil(t, err)
+	assert.True(t, job.Paused)
+
+	_, err = services.BeginRun(job, store, input)
+	assert.NotNil(t, err)
+	runs, err := store.JobRunsFor(job.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(runs))
+}
+
+func TestJobRunner_ExecuteRun_DebugTrace(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.RunResultDenylist = "secret"
+	store.Config.RunResultMaxSize = 16
+
+	os.Setenv("CL_TEMPLATE_TEST_TRACE_KEY", "tracekey123")
+	defer os.Unsetenv("CL_TEMPLATE_TEST_TRACE_KEY")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"price":"100.000000000000000000","secret":"hunter2"}`)
+	}))
+	defer server.Close()
+
+	var task models.Task
+	spec := fmt.Sprintf(`{"type":"httppost","url":"%v?key={{ env \"CL_TEMPLATE_TEST_TRACE_KEY\" }}"}`, server.URL)
+	assert.Nil(t, json.Unmarshal([]byte(spec), &task))
+	job := models.NewJob()
+	job.Tasks = []models.Task{task}
+	job.DebugSampleRate = 100
+	assert.Nil(t, store.SaveJob(&job))
+
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":"1"}`)}
+	run, err := services.ExecuteRun(job.NewRun(), store, input)
+	assert.Nil(t, err)
+
+	assert.Nil(t, store.One("ID", run.ID, &run))
+	trace := run.TaskRuns[0].Trace
+	if assert.NotNil(t, trace) && assert.Equal(t, 1, len(trace.Requests)) {
+		req := trace.Requests[0]
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, server.URL+`?key={{ env "CL_TEMPLATE_TEST_TRACE_KEY" }}`, req.URL)
+		assert.Equal(t, `{"value":"1"}`, req.Body)
+		assert.Equal(t, 200, req.Status)
+		assert.Equal(t, `{"price":"100.000000000000000000"}`, req.Response)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -18,6 +19,8 @@ type NotificationListener struct {
 	jobSubscriptions  []JobSubscription
 	headNotifications chan models.BlockHeader
 	headSubscription  *rpc.ClientSubscription
+	headPoller        *store.PollingSubscription
+	headErr           error
 	subMutx           sync.Mutex
 	started           bool
 }
@@ -46,8 +49,17 @@ func (nl *NotificationListener) Start() error {
 // Stop gracefully closes its access to the store's EthNotifications.
 func (nl *NotificationListener) Stop() error {
 	nl.started = false
-	if nl.headSubscription != nil && nl.headSubscription.Err() != nil {
-		nl.headSubscription.Unsubscribe()
+	nl.subMutx.Lock()
+	headSubscription := nl.headSubscription
+	nl.headSubscription = nil
+	headPoller := nl.headPoller
+	nl.headPoller = nil
+	nl.subMutx.Unlock()
+	if headSubscription != nil && headSubscription.Err() != nil {
+		headSubscription.Unsubscribe()
+	}
+	if headPoller != nil {
+		headPoller.Unsubscribe()
 	}
 	if nl.headNotifications != nil {
 		close(nl.headNotifications)
@@ -67,7 +79,7 @@ func (nl *NotificationListener) subscribeJobs(jobs []models.Job) error {
 // AddJob looks for "runlog" and "ethlog" Initiators for a given job
 // and watches the Ethereum blockchain for the addresses in the job.
 func (nl *NotificationListener) AddJob(job models.Job) error {
-	if !nl.started || !job.IsLogInitiated() {
+	if !nl.started || job.Archived || !job.IsLogInitiated() {
 		return nil
 	}
 
@@ -79,25 +91,99 @@ func (nl *NotificationListener) AddJob(job models.Job) error {
 	return nil
 }
 
+// subscribeToNewHeads subscribes to new heads, polling for them instead if
+// the ethereum endpoint does not support subscriptions.
 func (nl *NotificationListener) subscribeToNewHeads() error {
 	sub, err := nl.Store.TxManager.SubscribeToNewHeads(nl.headNotifications)
-	if err != nil {
+	if store.NotificationsUnsupported(err) {
+		logger.Infow("Ethereum node does not support subscriptions, polling for new heads", "interval", nl.Store.CurrentConfig().EthPollInterval)
+		poller := nl.Store.TxManager.PollNewHeads(nl.headNotifications, nl.Store.Clock)
+		nl.subMutx.Lock()
+		nl.headPoller = poller
+		nl.headErr = nil
+		nl.subMutx.Unlock()
+		return nil
+	} else if err != nil {
 		return err
 	}
+	nl.subMutx.Lock()
 	nl.headSubscription = sub
+	nl.headErr = nil
+	nl.subMutx.Unlock()
 	go func() {
 		err := <-sub.Err()
+		nl.subMutx.Lock()
+		defer nl.subMutx.Unlock()
+		if nl.headSubscription != sub {
+			return
+		}
 		logger.Errorw("Error in new head subscription", "err", err)
+		if err != nil {
+			nl.headErr = err
+		}
 	}()
 	return nil
 }
 
+// Resubscribe moves the subscriptions to new heads and to each job's logs
+// to the ethereum node the store is now connected to, such as after
+// ETH_URL changes. The old subscriptions are cancelled first, so that no
+// log starts a run twice.
+func (nl *NotificationListener) Resubscribe() error {
+	if !nl.started {
+		return nil
+	}
+	nl.subMutx.Lock()
+	old := nl.jobSubscriptions
+	nl.jobSubscriptions = nil
+	oldHead := nl.headSubscription
+	nl.headSubscription = nil
+	oldPoller := nl.headPoller
+	nl.headPoller = nil
+	nl.subMutx.Unlock()
+
+	if oldHead != nil && oldHead.Err() != nil {
+		oldHead.Unsubscribe()
+	}
+	if oldPoller != nil {
+		oldPoller.Unsubscribe()
+	}
+	for _, sub := range old {
+		sub.Unsubscribe()
+	}
+
+	err := nl.subscribeToNewHeads()
+	for _, sub := range old {
+		err = multierr.Append(err, nl.AddJob(sub.Job))
+	}
+	logger.Infow("Resubscribed to the ethereum node", "jobs", len(old))
+	return err
+}
+
+// Connected returns an error unless the listener has started and its
+// subscription to new heads, which resumes runs waiting on confirmations,
+// is still open.
+func (nl *NotificationListener) Connected() error {
+	nl.subMutx.Lock()
+	defer nl.subMutx.Unlock()
+	if !nl.started {
+		return errors.New("Not listening to the ethereum node")
+	}
+	if nl.headErr != nil {
+		return fmt.Errorf("New head subscription closed: %v", nl.headErr)
+	}
+	return nil
+}
+
 func (nl *NotificationListener) listenToNewHeads() {
 	for head := range nl.headNotifications {
 		logger.Debugw(fmt.Sprintf("Received new blockchain head %v", head.Number.String()), "newHead", head.Number)
 		if err := nl.Store.HeadTracker.Save(&head); err != nil {
 			logger.Error(err.Error())
 		}
+		if err := nl.Store.TxManager.SendBatches(); err != nil {
+			logger.Errorw("Error sending batched calls", "err", err)
+		}
 		pendingRuns, err := nl.Store.PendingJobRuns()
 		if err != nil {
 			logger.Error(err.Error())
@@ -116,6 +202,21 @@ func (nl *NotificationListener) addSubscription(sub JobSubscription) {
 	nl.jobSubscriptions = append(nl.jobSubscriptions, sub)
 }
 
+// RemoveJob stops watching the Ethereum blockchain for the given job's logs.
+func (nl *NotificationListener) RemoveJob(jobID string) {
+	nl.subMutx.Lock()
+	defer nl.subMutx.Unlock()
+	remaining := []JobSubscription{}
+	for _, sub := range nl.jobSubscriptions {
+		if sub.Job.ID == jobID {
+			sub.Unsubscribe()
+		} else {
+			remaining = append(remaining, sub)
+		}
+	}
+	nl.jobSubscriptions = remaining
+}
+
 func (nl *NotificationListener) unsubscribeJobs() {
 	nl.subMutx.Lock()
 	defer nl.subMutx.Unlock()
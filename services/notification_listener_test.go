@@ -51,6 +51,34 @@ func TestNotificationListener_Start_WithJobs(t *testing.T) {
 	eth.EnsureAllCalled(t)
 }
 
+func TestNotificationListener_Resubscribe(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.NewMockGethRpc()
+	ethSwitch := strpkg.NewEthSwitch(eth, nil)
+	store.TxManager.EthClient = &strpkg.EthClient{CallerSubscriber: ethSwitch, LogSubscriber: ethSwitch}
+	nl := services.NotificationListener{Store: store}
+	defer nl.Stop()
+
+	j := cltest.NewJobWithLogInitiator()
+	assert.Nil(t, store.SaveJob(&j))
+	eth.RegisterSubscription("newHeads", make(chan models.BlockHeader))
+	eth.RegisterSubscription("logs", make(chan types.Log))
+	assert.Nil(t, nl.Start())
+	eth.EnsureAllCalled(t)
+
+	next := cltest.NewMockGethRpc()
+	next.RegisterSubscription("newHeads", make(chan models.BlockHeader))
+	next.RegisterSubscription("logs", make(chan types.Log))
+	ethSwitch.Switch(next, nil)
+
+	assert.Nil(t, nl.Resubscribe())
+	next.EnsureAllCalled(t)
+	assert.Nil(t, nl.Connected())
+}
+
 func newAddr() common.Address {
 	return cltest.NewAddress()
 }
@@ -115,6 +143,41 @@ func TestNotificationListener_AddJob_Listening(t *testing.T) {
 	}
 }
 
+func TestNotificationListener_AddJob_SharesSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	cltest.MockEthOnStore(store)
+	nl := services.NotificationListener{Store: store}
+	defer nl.Stop()
+	assert.Nil(t, nl.Start())
+
+	eth := cltest.MockEthOnStore(store)
+	logChan := make(chan types.Log, 1)
+	eth.RegisterSubscription("logs", logChan)
+
+	address := cltest.NewAddress()
+	jobs := []models.Job{cltest.NewJob(), cltest.NewJob()}
+	for i := range jobs {
+		jobs[i].Initiators = []models.Initiator{{Type: models.InitiatorEthLog, Address: address}}
+		assert.Nil(t, store.SaveJob(&jobs[i]))
+		assert.Nil(t, nl.AddJob(jobs[i]))
+	}
+	assert.Equal(t, 1, store.Logs.Filters())
+	eth.EnsureAllCalled(t)
+
+	logChan <- types.Log{Address: address}
+	for _, j := range jobs {
+		cltest.WaitForRuns(t, j, store, 1)
+	}
+
+	nl.RemoveJob(jobs[0].ID)
+	assert.Equal(t, 1, store.Logs.Filters())
+	nl.RemoveJob(jobs[1].ID)
+	assert.Equal(t, 0, store.Logs.Filters())
+}
+
 func TestNotificationListener_newHeadsNotification(t *testing.T) {
 	t.Parallel()
 
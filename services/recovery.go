@@ -0,0 +1,226 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"go.uber.org/multierr"
+)
+
+// The RECOVERY_ACTION values, for what is done on boot with runs that were
+// queued or in progress when the node stopped.
+const (
+	// RecoveryActionResume executes the runs again from their unfinished
+	// task, starting queued runs with the input they were saved with.
+	RecoveryActionResume = "resume"
+	// RecoveryActionError errors the runs, so that they are not resumed.
+	RecoveryActionError = "error"
+	// RecoveryActionNone leaves the runs as they are, for an operator to
+	// look at.
+	RecoveryActionNone = "none"
+)
+
+// RecoveryReport lists what the node found in the store on boot that was
+// left over from before it stopped, and what it did about it.
+type RecoveryReport struct {
+	Action         string
+	QueuedRuns     []string
+	InProgressRuns []string
+	PendingRuns    []string
+	UnconfirmedTxs []uint64
+	Subscriptions  []string
+	OverdueRunAts  []string
+	MissedRunAts   []string
+	Resumed        int
+	Errored        int
+}
+
+// ForLogger returns the report as key value pairs for the logger.
+func (rr RecoveryReport) ForLogger() []interface{} {
+	return []interface{}{
+		"action", rr.Action,
+		"queuedRuns", rr.QueuedRuns,
+		"inProgressRuns", rr.InProgressRuns,
+		"pendingRuns", rr.PendingRuns,
+		"unconfirmedTxs", rr.UnconfirmedTxs,
+		"subscriptions", rr.Subscriptions,
+		"overdueRunAts", rr.OverdueRunAts,
+		"missedRunAts", rr.MissedRunAts,
+		"resumed", rr.Resumed,
+		"errored", rr.Errored,
+	}
+}
+
+// Recover looks through the store for runs that were queued or in progress
+// when the node stopped and deals with them as RECOVERY_ACTION says, then logs a
+// report of those runs along with what the other services will pick up
+// once started: the pending runs resumed on the next head, the unconfirmed
+// transactions they are waiting on, the jobs whose log subscriptions are
+// made again, and the runat jobs which run straight away as their time has
+// passed without a run. Runat initiators whose time passed longer ago than
+// RUN_AT_GRACE_PERIOD are saved as missed instead, so that they never run.
+func Recover(store *store.Store) (RecoveryReport, error) {
+	report := RecoveryReport{Action: store.CurrentConfig().RecoveryAction}
+	if report.Action == "" {
+		report.Action = RecoveryActionResume
+	}
+	switch report.Action {
+	case RecoveryActionResume, RecoveryActionError, RecoveryActionNone:
+	default:
+		return report, fmt.Errorf("Unknown RECOVERY_ACTION %v, must be resume, error, or none", report.Action)
+	}
+
+	queued := []models.JobRun{}
+	if err := store.Where("Status", models.StatusQueued, &queued); err != nil {
+		return report, err
+	}
+	inProgress := []models.JobRun{}
+	if err := store.Where("Status", models.StatusInProgress, &inProgress); err != nil {
+		return report, err
+	}
+	pending, err := store.PendingJobRuns()
+	if err != nil {
+		return report, err
+	}
+	for _, run := range pending {
+		report.PendingRuns = append(report.PendingRuns, run.ID)
+	}
+	if err := findUnfinished(store, &report); err != nil {
+		return report, err
+	}
+
+	var merr error
+	for _, run := range queued {
+		report.QueuedRuns = append(report.QueuedRuns, run.ID)
+		merr = multierr.Append(merr, recoverRun(run, run.Result, store, &report))
+	}
+	for _, run := range inProgress {
+		report.InProgressRuns = append(report.InProgressRuns, run.ID)
+		merr = multierr.Append(merr, recoverRun(run, models.RunResult{}, store, &report))
+	}
+
+	logger.Infow("Recovery report", report.ForLogger()...)
+	return report, merr
+}
+
+// recoverRun resumes or errors the interrupted run as the report's action
+// says, counting it in the report. A run whose task was waiting to be
+// retried is resumed once the rest of the task's backoff has passed.
+func recoverRun(run models.JobRun, input models.RunResult, store *store.Store, report *RecoveryReport) error {
+	switch report.Action {
+	case RecoveryActionResume:
+		if delay := retryDelay(run, store); delay > 0 {
+			executeRetryAfter(run, delay, store)
+			report.Resumed++
+			return nil
+		}
+		run, err := ExecuteRun(run, store, input)
+		if run.Status == models.StatusErrored {
+			report.Errored++
+		} else {
+			report.Resumed++
+		}
+		return err
+	case RecoveryActionError:
+		if err := errorInterruptedRun(run, store); err != nil {
+			return err
+		}
+		report.Errored++
+	}
+	return nil
+}
+
+// retryDelay returns how long is left before the run's next task is
+// retried, or 0 if it is not waiting to be.
+func retryDelay(run models.JobRun, store *store.Store) time.Duration {
+	unfinished := run.UnfinishedTaskRuns()
+	if len(unfinished) == 0 || !unfinished[0].RetryAt.Valid {
+		return 0
+	}
+	if delay := unfinished[0].RetryAt.Time.Sub(store.Clock.Now()); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// findUnfinished adds the unconfirmed transactions, log subscriptions, and
+// overdue runat jobs to the report, marking those overdue by more than
+// RUN_AT_GRACE_PERIOD as missed.
+func findUnfinished(store *store.Store, report *RecoveryReport) error {
+	txs := []models.Tx{}
+	if err := store.All(&txs); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if !tx.Confirmed {
+			report.UnconfirmedTxs = append(report.UnconfirmedTxs, tx.ID)
+		}
+	}
+
+	jobs, err := store.Jobs()
+	if err != nil {
+		return err
+	}
+	now := store.Clock.Now()
+	for _, job := range jobs {
+		if job.Archived {
+			continue
+		}
+		if job.IsLogInitiated() {
+			report.Subscriptions = append(report.Subscriptions, job.ID)
+		}
+		for _, initr := range job.InitiatorsFor(models.InitiatorRunAt) {
+			if initr.Ran || initr.Missed || initr.Time.After(now) {
+				continue
+			}
+			runs, err := store.JobRunsFor(job.ID)
+			if err != nil {
+				return err
+			}
+			if len(runs) > 0 {
+				continue
+			}
+			grace := store.CurrentConfig().RunAtGracePeriod
+			if grace > 0 && now.Sub(initr.Time.Time) > grace {
+				if err := missRunAt(store, initr); err != nil {
+					return err
+				}
+				report.MissedRunAts = append(report.MissedRunAts, job.ID)
+			} else {
+				report.OverdueRunAts = append(report.OverdueRunAts, job.ID)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// missRunAt saves the runat initiator as missed, so that it is not run,
+// and tells the operator.
+func missRunAt(store *store.Store, initr models.Initiator) error {
+	initr.Missed = true
+	if err := store.SaveInitiator(&initr); err != nil {
+		return err
+	}
+	logger.Warnw("Missed runat initiator while the node was down", "job", initr.JobID, "time", initr.Time.Time)
+	store.Events.Publish(models.Event{Type: models.EventRunAtMissed, JobID: initr.JobID, Data: initr})
+	return nil
+}
+
+// errorInterruptedRun errors the run and the task run it stopped on.
+func errorInterruptedRun(run models.JobRun, store *store.Store) error {
+	err := fmt.Errorf("node stopped while the run was %v", run.Status)
+	unfinished := run.UnfinishedTaskRuns()
+	if len(unfinished) > 0 {
+		i := len(run.TaskRuns) - len(unfinished)
+		tr := transitionTask(run.TaskRuns[i], models.StatusErrored)
+		tr.Result = tr.Result.WithError(err)
+		run.TaskRuns[i] = tr
+	}
+	run.Result = run.Result.WithError(err)
+	_, err = transitionRun(run, models.StatusErrored, store)
+	return err
+}
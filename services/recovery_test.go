@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		action      string
+		wantStatus  string
+		wantResumed int
+		wantErrored int
+	}{
+		{"resume", services.RecoveryActionResume, models.StatusCompleted, 1, 0},
+		{"default", "", models.StatusCompleted, 1, 0},
+		{"error", services.RecoveryActionError, models.StatusErrored, 0, 1},
+		{"none", services.RecoveryActionNone, models.StatusInProgress, 0, 0},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			store.Config.RecoveryAction = test.action
+
+			job := cltest.NewJobWithWebInitiator()
+			assert.Nil(t, store.SaveJob(&job))
+			run := job.NewRun()
+			run.Status = models.StatusInProgress
+			run.TaskRuns[0].Status = models.StatusInProgress
+			assert.Nil(t, store.Save(&run))
+
+			pending := job.NewRun()
+			pending.Status = models.StatusPending
+			assert.Nil(t, store.Save(&pending))
+
+			logJob := cltest.NewJobWithLogInitiator()
+			assert.Nil(t, store.SaveJob(&logJob))
+
+			runAt := cltest.NewJob()
+			runAt.Initiators = []models.Initiator{{
+				Type: models.InitiatorRunAt,
+				Time: models.Time{Time: time.Now().Add(-time.Hour)},
+			}}
+			assert.Nil(t, store.SaveJob(&runAt))
+
+			tx := cltest.CreateTxAndAttempt(store, cltest.NewAddress(), 1)
+
+			report, err := services.Recover(store)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{run.ID}, report.InProgressRuns)
+			assert.Equal(t, []string{pending.ID}, report.PendingRuns)
+			assert.Equal(t, []uint64{tx.ID}, report.UnconfirmedTxs)
+			assert.Equal(t, []string{logJob.ID}, report.Subscriptions)
+			assert.Equal(t, []string{runAt.ID}, report.OverdueRunAts)
+			assert.Equal(t, test.wantResumed, report.Resumed)
+			assert.Equal(t, test.wantErrored, report.Errored)
+
+			run, err = store.FindJobRun(run.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, test.wantStatus, run.Status)
+		})
+	}
+}
+
+func TestRecover_QueuedRun(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, store.SaveJob(&job))
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":"100"}`)}
+	run, err := services.QueueRun(job.NewRun(), store, input)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusQueued, run.Status)
+
+	report, err := services.Recover(store)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{run.ID}, report.QueuedRuns)
+	assert.Equal(t, 1, report.Resumed)
+
+	run, err = store.FindJobRun(run.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusCompleted, run.Status)
+	value, err := run.Result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "100", value)
+}
+
+func TestRecover_WaitingRetry(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, store.SaveJob(&job))
+	run := job.NewRun()
+	run.Status = models.StatusInProgress
+	run.TaskRuns[0].Status = models.StatusInProgress
+	run.TaskRuns[0].Attempts = 1
+	run.TaskRuns[0].RetryAt = null.TimeFrom(time.Now().Add(time.Hour))
+	assert.Nil(t, store.Save(&run))
+
+	report, err := services.Recover(store)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Resumed)
+
+	run, err = store.FindJobRun(run.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusInProgress, run.Status, "the retry waits out the rest of its backoff")
+	assert.Equal(t, uint(1), run.TaskRuns[0].Attempts)
+}
+
+func TestRecover_UnknownAction(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.RecoveryAction = "panic"
+
+	_, err := services.Recover(store)
+	assert.NotNil(t, err)
+}
+
+func TestRecover_RunAtGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.RunAtGracePeriod = 30 * time.Minute
+
+	newRunAtJob := func(ago time.Duration, ran bool) models.Job {
+		job := cltest.NewJob()
+		job.Initiators = []models.Initiator{{
+			Type: models.InitiatorRunAt,
+			Time: models.Time{Time: time.Now().Add(-ago)},
+			Ran:  ran,
+		}}
+		assert.Nil(t, store.SaveJob(&job))
+		return job
+	}
+	recent := newRunAtJob(10*time.Minute, false)
+	late := newRunAtJob(2*time.Hour, false)
+	newRunAtJob(2*time.Hour, true)
+
+	report, err := services.Recover(store)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{recent.ID}, report.OverdueRunAts)
+	assert.Equal(t, []string{late.ID}, report.MissedRunAts)
+
+	late, err = store.FindJob(late.ID)
+	assert.Nil(t, err)
+	assert.True(t, late.Initiators[0].Missed)
+	var initr models.Initiator
+	assert.Nil(t, store.One("JobID", late.ID, &initr))
+	assert.True(t, initr.Missed)
+
+	report, err = services.Recover(store)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(report.MissedRunAts))
+}
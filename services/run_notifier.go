@@ -0,0 +1,191 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// RunWebhookSignatureHeader holds the hex HMAC-SHA256 of a webhook's body,
+// keyed with RUN_WEBHOOK_SECRET, prefixed with "sha256=".
+const RunWebhookSignatureHeader = "X-Chainlink-Signature"
+
+// runWebhookMaxAttempts is how many times a webhook is sent before it is
+// given up on.
+const runWebhookMaxAttempts = 5
+
+// runWebhookEvents are the events webhooks are sent for.
+var runWebhookEvents = map[string]bool{
+	models.EventRunCompleted: true,
+	models.EventRunErrored:   true,
+	models.EventTxConfirmed:  true,
+}
+
+// RunNotifier POSTs the events of runs completing or erroring, and of their
+// fulfillment transactions confirming, to RUN_WEBHOOK_URL and to the
+// webhooks of the run's job, so that operators can be alerted without
+// polling. The body is the event as JSON, signed with RUN_WEBHOOK_SECRET if
+// set, and failed webhooks are retried with backoff. Jobs' webhooks are
+// sent like their tasks' requests, through ADAPTER_PROXY_URL and checked
+// against the job's and the node's domain lists and
+// ADAPTER_BLOCK_PRIVATE_IPS, since job authors choose them. Like other event
+// subscribers, it misses events if it falls behind, and webhooks are not
+// kept across restarts.
+type RunNotifier struct {
+	store  *store.Store
+	client *http.Client
+	events chan models.Event
+	done   chan struct{}
+}
+
+// NewRunNotifier returns a RunNotifier for the store's events.
+func NewRunNotifier(store *store.Store) *RunNotifier {
+	return &RunNotifier{
+		store:  store,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Start sends webhooks for the store's events until Stop is called.
+func (rn *RunNotifier) Start() error {
+	rn.events = rn.store.Events.Subscribe()
+	rn.done = make(chan struct{})
+	go rn.run(rn.events, rn.done)
+	return nil
+}
+
+// Stop stops sending webhooks. Those still being retried are dropped.
+func (rn *RunNotifier) Stop() {
+	if rn.done != nil {
+		rn.store.Events.Unsubscribe(rn.events)
+		close(rn.done)
+		rn.done = nil
+	}
+}
+
+func (rn *RunNotifier) run(events chan models.Event, done chan struct{}) {
+	for event := range events {
+		if !runWebhookEvents[event.Type] {
+			continue
+		}
+		for _, hook := range rn.webhooksFor(event) {
+			go rn.deliver(hook, event, done)
+		}
+	}
+}
+
+// runWebhook is a URL events are sent to, and the job that gave it, if it
+// was not set by the operator.
+type runWebhook struct {
+	url string
+	job *models.Job
+}
+
+// webhooksFor returns RUN_WEBHOOK_URL, if set, and the webhooks of the job
+// the event's run belongs to.
+func (rn *RunNotifier) webhooksFor(event models.Event) []runWebhook {
+	hooks := []runWebhook{}
+	if url := rn.store.CurrentConfig().RunWebhookURL; url != "" {
+		hooks = append(hooks, runWebhook{url: url})
+	}
+	jobID := event.JobID
+	if jobID == "" && event.RunID != "" {
+		if run, err := rn.store.FindJobRun(event.RunID); err == nil {
+			jobID = run.JobID
+		}
+	}
+	if jobID == "" {
+		return hooks
+	}
+	job, err := rn.store.FindJob(jobID)
+	if err != nil {
+		logger.Warnw("Finding job for run webhooks", "job", jobID, "err", err)
+		return hooks
+	}
+	for _, url := range job.Webhooks {
+		hooks = append(hooks, runWebhook{url: url, job: &job})
+	}
+	return hooks
+}
+
+// deliver sends the event until the webhook accepts it, or it has been
+// tried runWebhookMaxAttempts times.
+func (rn *RunNotifier) deliver(hook runWebhook, event models.Event, done chan struct{}) {
+	retry := time.Duration(0)
+	for attempt := 1; ; attempt++ {
+		err := rn.send(hook, event)
+		if err == nil {
+			return
+		}
+		if attempt == runWebhookMaxAttempts {
+			logger.Errorw("Giving up on run webhook", "type", event.Type, "run", event.RunID, "err", err)
+			return
+		}
+		retry = backoff(retry)
+		logger.Warnw("Sending run webhook", "type", event.Type, "run", event.RunID, "err", err, "retry", retry)
+		select {
+		case <-done:
+			return
+		case <-rn.store.Clock.After(retry):
+		}
+	}
+}
+
+// Send POSTs the event to a webhook set by the operator.
+func (rn *RunNotifier) Send(url string, event models.Event) error {
+	return rn.send(runWebhook{url: url}, event)
+}
+
+func (rn *RunNotifier) send(hook runWebhook, event models.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", hook.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	client := rn.client
+	if hook.job != nil {
+		if err := adapters.CheckJobEgress(req.Context(), req.URL, *hook.job, rn.store); err != nil {
+			return err
+		}
+		jobClient := *adapters.JobEgressClient(*hook.job, rn.store)
+		jobClient.Timeout = rn.client.Timeout
+		client = &jobClient
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := rn.store.CurrentConfig().RunWebhookSecret; secret != "" {
+		req.Header.Set(RunWebhookSignatureHeader, "sha256="+SignRunWebhook(secret, b))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Run webhook responded %v: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// SignRunWebhook returns the hex HMAC-SHA256 of the body keyed with the
+// secret, which receivers compare with the signature header.
+func SignRunWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunNotifier_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	global := newEventSink(200)
+	defer global.server.Close()
+	perJob := newEventSink(200)
+	defer perJob.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	store.Config.RunWebhookURL = global.server.URL
+	store.Config.RunWebhookSecret = "hunter2"
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Webhooks = []string{perJob.server.URL}
+	assert.Nil(t, store.SaveJob(&job))
+
+	rn := services.NewRunNotifier(store)
+	assert.Nil(t, rn.Start())
+	defer rn.Stop()
+
+	store.Events.Publish(models.Event{Type: models.EventTaskCompleted, JobID: job.ID, RunID: "1"})
+	store.Events.Publish(models.Event{Type: models.EventRunCompleted, JobID: job.ID, RunID: "1"})
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(global.received).Should(gomega.Equal(1))
+	g.Eventually(perJob.received).Should(gomega.Equal(1))
+	g.Consistently(global.received).Should(gomega.Equal(1))
+
+	var event models.Event
+	assert.Nil(t, json.Unmarshal(global.bodies[0], &event))
+	assert.Equal(t, models.EventRunCompleted, event.Type)
+	assert.Equal(t, job.ID, event.JobID)
+	signature := "sha256=" + services.SignRunWebhook("hunter2", global.bodies[0])
+	assert.Equal(t, signature, global.requests[0].Header.Get(services.RunWebhookSignatureHeader))
+}
+
+func TestRunNotifier_TxConfirmed(t *testing.T) {
+	t.Parallel()
+
+	perJob := newEventSink(200)
+	defer perJob.server.Close()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Webhooks = []string{perJob.server.URL}
+	assert.Nil(t, store.SaveJob(&job))
+	run := job.NewRun()
+	assert.Nil(t, store.Save(&run))
+
+	rn := services.NewRunNotifier(store)
+	assert.Nil(t, rn.Start())
+	defer rn.Stop()
+
+	store.Events.Publish(models.Event{Type: models.EventTxConfirmed, RunID: run.ID})
+
+	gomega.NewGomegaWithT(t).Eventually(perJob.received).Should(gomega.Equal(1))
+	assert.Empty(t, perJob.requests[0].Header.Get(services.RunWebhookSignatureHeader))
+}
+
+func TestRunNotifier_JobWebhookEgress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		blockPrivateIPs bool
+		deniedDomains   []string
+	}{
+		{"private address", true, nil},
+		{"denied by job", false, []string{"127.0.0.1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			global := newEventSink(200)
+			defer global.server.Close()
+			perJob := newEventSink(200)
+			defer perJob.server.Close()
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			store.Config.RunWebhookURL = global.server.URL
+			store.Config.AdapterBlockPrivateIPs = test.blockPrivateIPs
+
+			job := cltest.NewJobWithWebInitiator()
+			job.Webhooks = []string{perJob.server.URL}
+			job.DeniedDomains = test.deniedDomains
+			assert.Nil(t, store.SaveJob(&job))
+
+			rn := services.NewRunNotifier(store)
+			assert.Nil(t, rn.Start())
+			defer rn.Stop()
+
+			store.Events.Publish(models.Event{Type: models.EventRunCompleted, JobID: job.ID, RunID: "1"})
+			g := gomega.NewGomegaWithT(t)
+			g.Eventually(global.received).Should(gomega.Equal(1), "the operator's webhook is still sent")
+			g.Consistently(perJob.received).Should(gomega.Equal(0))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store"
+)
+
+// RunReaper deletes finished runs every RUN_REAPER_PERIOD, so that the
+// store of a long-running node does not grow without bound. Runs are kept
+// for RUN_RETENTION_AGE, and each job keeps its newest RUN_RETENTION_COUNT
+// finished runs. Neither limit applies unless it is set. Skipped runs that
+// have been resumed are deleted too, as are those last skipped before
+// RUN_RETENTION_AGE, other than log runs waiting to be replayed.
+type RunReaper struct {
+	store *store.Store
+	done  chan struct{}
+}
+
+// NewRunReaper returns a RunReaper for the store's runs.
+func NewRunReaper(store *store.Store) *RunReaper {
+	return &RunReaper{store: store}
+}
+
+// Start prunes runs until Stop is called, unless RUN_REAPER_PERIOD is 0.
+func (rr *RunReaper) Start() error {
+	period := rr.store.CurrentConfig().RunReaperPeriod
+	if period <= 0 {
+		return nil
+	}
+	rr.done = make(chan struct{})
+	go func(done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-rr.store.Clock.After(period):
+				if _, err := rr.Prune(); err != nil {
+					logger.Warnw("Pruning runs", "err", err)
+				}
+			}
+		}
+	}(rr.done)
+	return nil
+}
+
+// Stop stops pruning runs.
+func (rr *RunReaper) Stop() {
+	if rr.done != nil {
+		close(rr.done)
+		rr.done = nil
+	}
+}
+
+// Prune deletes the skipped runs that are done with, and the runs outside
+// of the configured retention limits, if any are set, now. It returns how
+// many runs were deleted.
+func (rr *RunReaper) Prune() (int, error) {
+	config := rr.store.CurrentConfig()
+	if _, err := PruneSkippedRuns(rr.store, config.RunRetentionAge); err != nil {
+		return 0, err
+	}
+	if config.RunRetentionAge <= 0 && config.RunRetentionCount == 0 {
+		return 0, nil
+	}
+	return PruneRuns(rr.store, config.RunRetentionAge, int(config.RunRetentionCount))
+}
+
+// PruneRuns deletes the finished runs older than age, and those beyond the
+// newest keep of each job, and returns how many were deleted. An age or
+// keep of 0 does not limit runs by that measure.
+func PruneRuns(store *store.Store, age time.Duration, keep int) (int, error) {
+	var before time.Time
+	if age > 0 {
+		before = store.Clock.Now().Add(-age)
+	}
+	pruned, err := store.PruneJobRuns(before, keep)
+	if pruned > 0 {
+		logger.Infow("Pruned runs", "count", pruned, "age", age, "keep", keep)
+	}
+	return pruned, err
+}
+
+// PruneSkippedRuns deletes the skipped runs that have been resumed, and
+// those last skipped more than age ago, other than log runs waiting to be
+// replayed, and returns how many were deleted. An age of 0 keeps skipped
+// runs that have not been resumed.
+func PruneSkippedRuns(store *store.Store, age time.Duration) (int, error) {
+	var before time.Time
+	if age > 0 {
+		before = store.Clock.Now().Add(-age)
+	}
+	pruned, err := store.PruneSkippedRuns(before)
+	if pruned > 0 {
+		logger.Infow("Pruned skipped runs", "count", pruned, "age", age)
+	}
+	return pruned, err
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/mrwonko/cron"
@@ -13,12 +15,15 @@ import (
 
 // Scheduler contains fields for Recurring and OneTime for occurrences,
 // a pointer to the store and a started field to indicate if the Scheduler
-// has started or not.
+// has started or not. Jobs are only scheduled once, even if they are
+// added again after being unarchived.
 type Scheduler struct {
 	Recurring *Recurring
 	OneTime   *OneTime
 	store     *store.Store
 	started   bool
+	scheduled map[string]bool
+	mutex     sync.Mutex
 }
 
 // NewScheduler initializes the Scheduler instances with both Recurring
@@ -49,6 +54,7 @@ func (s *Scheduler) Start() error {
 		return err
 	}
 	s.started = true
+	s.scheduled = map[string]bool{}
 
 	jobs, err := s.store.Jobs()
 	if err != nil {
@@ -74,10 +80,17 @@ func (s *Scheduler) Stop() {
 
 // AddJob is the governing function for Recurring and OneTime,
 // and will only execute if the Scheduler has not already started.
+// Archived jobs are not scheduled.
 func (s *Scheduler) AddJob(job models.Job) {
-	if !s.started {
+	if !s.started || job.Archived {
 		return
 	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.scheduled[job.ID] {
+		return
+	}
+	s.scheduled[job.ID] = true
 	s.Recurring.AddJob(job)
 	s.OneTime.AddJob(job)
 }
@@ -89,6 +102,7 @@ type Recurring struct {
 	Cron  Cron
 	Clock Nower
 	store *store.Store
+	done  chan struct{}
 }
 
 // NewRecurring create a new instance of Recurring, ready to use.
@@ -102,13 +116,19 @@ func NewRecurring(store *store.Store) *Recurring {
 // Start for Recurring types executes tasks with a "cron" initiator
 // based on the configured schedule for the run.
 func (r *Recurring) Start() error {
+	r.done = make(chan struct{})
 	r.Cron = newChainlinkCron()
 	r.Cron.Start()
 	return nil
 }
 
-// Stop stops the cron scheduler and waits for running jobs to finish.
+// Stop stops the cron scheduler and waits for running jobs to finish,
+// dropping the runs still waiting out their jitter.
 func (r *Recurring) Stop() {
+	if r.done != nil {
+		close(r.done)
+		r.done = nil
+	}
 	r.Cron.Stop()
 }
 
@@ -118,16 +138,48 @@ func (r *Recurring) AddJob(job models.Job) {
 	for _, initr := range job.InitiatorsFor(models.InitiatorCron) {
 		cronStr := string(initr.Schedule)
 		if !job.Ended(r.Clock.Now()) {
-			r.Cron.AddFunc(cronStr, func() {
-				_, err := BeginRun(job, r.store, models.RunResult{})
-				if err != nil && !expectedRecurringError(err) {
-					logger.Error(err.Error())
-				}
-			})
+			done := r.done
+			err := r.Cron.AddFunc(cronStr, func() { r.fire(job, done) })
+			if err != nil {
+				logger.Scheduler.Errorw("Scheduling job", "job", job.ID, "schedule", cronStr, "err", err)
+			}
 		}
 	}
 }
 
+// fire starts a run of the job for its cron initiator once a random time
+// up to its CronJitter has passed, unless it skips overlapping runs and
+// its last run is unfinished.
+func (r *Recurring) fire(job models.Job, done chan struct{}) {
+	if job.CronJitter > 0 {
+		select {
+		case <-done:
+			return
+		case <-r.store.Clock.After(time.Duration(rand.Int63n(int64(job.CronJitter)))):
+		}
+	}
+
+	if job.SkipOverlapping {
+		unfinished, err := r.store.UnfinishedRunsFor(job.ID)
+		if err != nil {
+			logger.Scheduler.Errorw("Checking for unfinished runs", "job", job.ID, "err", err)
+			return
+		}
+		if len(unfinished) > 0 {
+			logger.Scheduler.Infow(fmt.Sprintf("Job %v run %v unfinished, skipping cron run", job.ID, unfinished[0].ID), "job", job.ID)
+			if err := skipRun(job, models.InitiatorCron, models.SkipReasonOverlap, r.store, models.RunResult{}); err != nil {
+				logger.Scheduler.Error(err.Error())
+			}
+			return
+		}
+	}
+
+	_, err := TriggerRun(job, models.InitiatorCron, r.store, models.RunResult{})
+	if err != nil && !expectedRecurringError(err) {
+		logger.Scheduler.Error(err.Error())
+	}
+}
+
 // OneTime represents runs that are to be executed only once.
 type OneTime struct {
 	Store *store.Store
@@ -141,10 +193,13 @@ func (ot *OneTime) Start() error {
 	return nil
 }
 
-// AddJob runs the job at the time specified for the "runat" initiator.
+// AddJob runs the job at the time specified for each "runat" initiator
+// that has not already run, or been missed.
 func (ot *OneTime) AddJob(job models.Job) {
 	for _, initr := range job.InitiatorsFor(models.InitiatorRunAt) {
-		go ot.RunJobAt(initr.Time, job)
+		if !initr.Ran && !initr.Missed {
+			go ot.RunJobAt(initr, job)
+		}
 	}
 }
 
@@ -154,14 +209,19 @@ func (ot *OneTime) Stop() {
 }
 
 // RunJobAt wait until the Stop() function has been called on the run
-// or the specified time for the run is after the present time.
-func (ot *OneTime) RunJobAt(t models.Time, job models.Job) {
+// or the specified time for the run is after the present time, then saves
+// the initiator as ran, so that it is not run again on restart.
+func (ot *OneTime) RunJobAt(initr models.Initiator, job models.Job) {
 	select {
 	case <-ot.done:
-	case <-ot.Clock.After(t.DurationFromNow()):
-		_, err := BeginRun(job, ot.Store, models.RunResult{})
+	case <-ot.Clock.After(initr.Time.DurationFromNow()):
+		_, err := TriggerRun(job, models.InitiatorRunAt, ot.Store, models.RunResult{})
 		if err != nil {
-			logger.Error(err.Error())
+			logger.Scheduler.Error(err.Error())
+		}
+		initr.Ran = true
+		if err := ot.Store.SaveInitiator(&initr); err != nil {
+			logger.Scheduler.Errorw("Saving runat initiator as ran", "job", job.ID, "err", err)
 		}
 	}
 }
@@ -177,7 +237,8 @@ func expectedRecurringError(err error) bool {
 
 // Cron is an interface for scheduling recurring functions to run.
 // Cron's schedule format is similar to the standard cron format
-// but with an extra field at the beginning for seconds.
+// but with an extra field at the beginning for seconds, and an optional
+// CRON_TZ=<zone> prefix, as models.Cron describes.
 type Cron interface {
 	Start()
 	Stop()
@@ -192,6 +253,17 @@ func newChainlinkCron() *chainlinkCron {
 	return &chainlinkCron{cron.New()}
 }
 
+// AddFunc schedules the function to run on the spec, in the spec's time
+// zone.
+func (cc *chainlinkCron) AddFunc(spec string, fn func()) error {
+	schedule, err := models.Cron(spec).Schedule()
+	if err != nil {
+		return err
+	}
+	cc.Cron.Schedule(schedule, cron.FuncJob(fn))
+	return nil
+}
+
 func (cc *chainlinkCron) Stop() {
 	cc.Cron.Stop()
 	cc.Cron.Wait()
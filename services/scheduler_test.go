@@ -78,6 +78,76 @@ func TestRecurring_AddJob(t *testing.T) {
 	}
 }
 
+func TestRecurring_AddJob_Jitter(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	cltest.UseSettableClock(store)
+	r := services.NewRecurring(store)
+	cron := cltest.NewMockCron()
+	r.Cron = cron
+	defer r.Stop()
+
+	j := cltest.NewJobWithSchedule("* * * * *")
+	j.CronJitter = models.Duration(time.Minute)
+	assert.Nil(t, store.SaveJob(&j))
+	r.AddJob(j)
+	cron.RunEntries()
+
+	cltest.WaitForRuns(t, j, store, 1)
+}
+
+func TestRecurring_AddJob_SkipOverlapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		skipOverlapping bool
+		lastStatus      string
+		wantRuns        int
+		wantSkipped     int
+	}{
+		{"last run in progress", true, models.StatusInProgress, 1, 1},
+		{"last run pending", true, models.StatusPending, 1, 1},
+		{"last run completed", true, models.StatusCompleted, 2, 0},
+		{"overlapping allowed", false, models.StatusInProgress, 2, 0},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			store, cleanup := cltest.NewStore()
+			defer cleanup()
+			r := services.NewRecurring(store)
+			cron := cltest.NewMockCron()
+			r.Cron = cron
+			defer r.Stop()
+
+			j := cltest.NewJobWithSchedule("* * * * *")
+			j.SkipOverlapping = test.skipOverlapping
+			assert.Nil(t, store.SaveJob(&j))
+			last := j.NewRun()
+			last.Status = test.lastStatus
+			assert.Nil(t, store.Save(&last))
+
+			r.AddJob(j)
+			cron.RunEntries()
+
+			jobRuns := []models.JobRun{}
+			assert.Nil(t, store.Where("JobID", j.ID, &jobRuns))
+			assert.Equal(t, test.wantRuns, len(jobRuns))
+			skipped := []models.SkippedRun{}
+			store.Where("JobID", j.ID, &skipped)
+			assert.Equal(t, test.wantSkipped, len(skipped))
+			for _, sr := range skipped {
+				assert.Equal(t, models.SkipReasonOverlap, sr.Reason)
+			}
+		})
+	}
+}
+
 func TestScheduler_AddJob_WhenStopped(t *testing.T) {
 	t.Parallel()
 
@@ -109,7 +179,7 @@ func TestOneTime_RunJobAt(t *testing.T) {
 
 	var finished bool
 	go func() {
-		ot.RunJobAt(models.Time{time.Now().Add(time.Hour)}, j)
+		ot.RunJobAt(models.Initiator{Type: models.InitiatorRunAt, Time: models.Time{time.Now().Add(time.Hour)}}, j)
 		finished = true
 	}()
 
@@ -123,6 +193,56 @@ func TestOneTime_RunJobAt(t *testing.T) {
 	assert.Equal(t, 0, len(jobRuns))
 }
 
+func TestOneTime_RunJobAt_SavesRan(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	ot := services.OneTime{Clock: store.Clock, Store: store}
+	ot.Start()
+	defer ot.Stop()
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{
+		Type: models.InitiatorRunAt,
+		Time: models.Time{time.Now().Add(-time.Minute)},
+	}}
+	assert.Nil(t, store.SaveJob(&j))
+
+	ot.RunJobAt(j.Initiators[0], j)
+
+	cltest.WaitForRuns(t, j, store, 1)
+	j, err := store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.True(t, j.Initiators[0].Ran)
+}
+
+func TestOneTime_AddJob_SkipsRanAndMissed(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	ot := services.OneTime{Clock: store.Clock, Store: store}
+	ot.Start()
+	defer ot.Stop()
+	j := cltest.NewJob()
+	past := models.Time{time.Now().Add(-time.Minute)}
+	j.Initiators = []models.Initiator{
+		{Type: models.InitiatorRunAt, Time: past, Ran: true},
+		{Type: models.InitiatorRunAt, Time: past, Missed: true},
+	}
+	assert.Nil(t, store.SaveJob(&j))
+
+	ot.AddJob(j)
+
+	gomega.NewGomegaWithT(t).Consistently(func() int {
+		runs, err := store.JobRunsFor(j.ID)
+		assert.Nil(t, err)
+		return len(runs)
+	}).Should(gomega.Equal(0))
+}
+
 func TestScheduler_Start_AddingUnstartedJob(t *testing.T) {
 	logs := cltest.ObserveLogs()
 
@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/metrics"
 	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/store/presenters"
@@ -77,7 +78,7 @@ type Unsubscriber interface {
 	Unsubscribe()
 }
 
-// Encapsulates all functionality needed to wrap an ethereum rpc.ClientSubscription
+// Encapsulates all functionality needed to wrap an ethereum subscription
 // for use with a Chainlink Initiator. Initiator specific functionality is delegated
 // to the ReceiveLog callback using a strategy pattern.
 type RpcLogSubscription struct {
@@ -87,7 +88,7 @@ type RpcLogSubscription struct {
 	store            *store.Store
 	logNotifications chan types.Log
 	errors           chan error
-	rpcSubscription  *rpc.ClientSubscription
+	rpcSubscription  store.EthSubscription
 }
 
 // Create a new RpcLogSubscription that feeds received logs to the callback func parameter.
@@ -96,12 +97,17 @@ func NewRpcLogSubscription(initr models.Initiator, job models.Job, store *store.
 	sub.errors = make(chan error)
 	sub.logNotifications = make(chan types.Log)
 
-	fq := utils.ToFilterQueryFor(store.HeadTracker.Get().ToInt(), []common.Address{initr.Address})
-	rpc, err := store.TxManager.SubscribeToLogs(sub.logNotifications, fq)
+	addresses, err := store.LogAddressesFor(initr)
+	if err != nil {
+		return sub, err
+	}
+	fq := utils.ToFilterQueryFor(store.HeadTracker.Get().ToInt(), addresses)
+	rpc, err := store.Logs.Subscribe(sub.logNotifications, fq)
 	if err != nil {
 		return sub, err
 	}
 	sub.rpcSubscription = rpc
+	metrics.ActiveSubscriptions.Inc()
 	go sub.listenToSubscriptionErrors()
 	go sub.listenToLogs()
 	return sub, nil
@@ -114,16 +120,18 @@ func (sub RpcLogSubscription) Unsubscribe() {
 	}
 	close(sub.logNotifications)
 	close(sub.errors)
+	metrics.ActiveSubscriptions.Dec()
 }
 
 func (sub RpcLogSubscription) listenToSubscriptionErrors() {
 	for err := range sub.errors {
-		logger.Errorw(fmt.Sprintf("Error in log subscription for job %v", sub.Job.ID), "err", err, "initr", sub.Initiator)
+		logger.Subscription.Errorw(fmt.Sprintf("Error in log subscription for job %v", sub.Job.ID), "err", err, "initr", sub.Initiator)
 	}
 }
 
 func (sub RpcLogSubscription) listenToLogs() {
 	for el := range sub.logNotifications {
+		metrics.LogsReceived.Inc(sub.Job.ID)
 		sub.ReceiveLog(RpcLogEvent{
 			Job:       sub.Job,
 			Initiator: sub.Initiator,
@@ -149,9 +157,27 @@ func logListening(initr models.Initiator) {
 	msg := fmt.Sprintf(
 		"Listening for %v from address %v for job %v",
 		initr.Type,
-		presenters.LogListeningAddress(initr.Address),
+		listeningAddresses(initr),
 		initr.JobID)
-	logger.Infow(msg)
+	logger.Subscription.Infow(msg)
+}
+
+// listeningAddresses describes the contracts the initiator listens to.
+func listeningAddresses(initr models.Initiator) string {
+	if len(initr.Addresses) == 0 && initr.AddressList == "" {
+		return presenters.LogListeningAddress(initr.Address)
+	}
+	described := []string{}
+	if initr.Address != (common.Address{}) {
+		described = append(described, initr.Address.String())
+	}
+	for _, address := range initr.Addresses {
+		described = append(described, address.String())
+	}
+	if initr.AddressList != "" {
+		described = append(described, "list "+initr.AddressList)
+	}
+	return strings.Join(described, ", ")
 }
 
 // Parse the log and run the job specific to this initiator log event.
@@ -160,13 +186,13 @@ func ReceiveRunLog(le RpcLogEvent) {
 		return
 	}
 
-	friendlyAddress := presenters.LogListeningAddress(le.Initiator.Address)
+	friendlyAddress := listeningAddresses(le.Initiator)
 	msg := fmt.Sprintf("Received log for address %v for job %v", friendlyAddress, le.Job.ID)
-	logger.Infow(msg, le.ForLogger()...)
+	logger.Subscription.Infow(msg, le.ForLogger()...)
 
 	data, err := le.RunLogJSON()
 	if err != nil {
-		logger.Errorw(err.Error(), le.ForLogger()...)
+		logger.Subscription.Errorw(err.Error(), le.ForLogger()...)
 		return
 	}
 
@@ -174,14 +200,21 @@ func ReceiveRunLog(le RpcLogEvent) {
 }
 
 // Parse the log and run the job specific to this initiator log event.
+// Logs of other events than the initiator's event, if it has one, are
+// skipped.
 func ReceiveEthLog(le RpcLogEvent) {
-	friendlyAddress := presenters.LogListeningAddress(le.Initiator.Address)
+	if event := le.Initiator.Event; event != nil && !le.MatchesEvent() {
+		logger.Subscription.Debugw(fmt.Sprintf("Skipping; log is not a %v event", event.Signature()), le.ForLogger()...)
+		return
+	}
+
+	friendlyAddress := listeningAddresses(le.Initiator)
 	msg := fmt.Sprintf("Received log for address %v for job %v", friendlyAddress, le.Job.ID)
-	logger.Infow(msg, le.ForLogger()...)
+	logger.Subscription.Infow(msg, le.ForLogger()...)
 
 	data, err := le.EthLogJSON()
 	if err != nil {
-		logger.Errorw(err.Error(), le.ForLogger()...)
+		logger.Subscription.Errorw(err.Error(), le.ForLogger()...)
 		return
 	}
 
@@ -190,8 +223,8 @@ func ReceiveEthLog(le RpcLogEvent) {
 
 func runJob(le RpcLogEvent, data models.JSON) {
 	input := models.RunResult{Data: data}
-	if _, err := BeginRun(le.Job, le.store, input); err != nil {
-		logger.Errorw(err.Error(), le.ForLogger()...)
+	if _, err := TriggerRun(le.Job, le.Initiator.Type, le.store, input); err != nil {
+		logger.Subscription.Errorw(err.Error(), le.ForLogger()...)
 	}
 }
 
@@ -220,16 +253,16 @@ func (le RpcLogEvent) ForLogger(kvs ...interface{}) []interface{} {
 func (le RpcLogEvent) ValidateRunLog() bool {
 	el := le.Log
 	if !isRunLog(el) {
-		logger.Debugw("Skipping; Unable to retrieve runlog parameters from log", le.ForLogger()...)
+		logger.Subscription.Debugw("Skipping; Unable to retrieve runlog parameters from log", le.ForLogger()...)
 		return false
 	}
 
 	jid, err := jobIDFromLog(el)
 	if err != nil {
-		logger.Warnw("Failed to retrieve Job ID from log", le.ForLogger("err", err.Error())...)
+		logger.Subscription.Warnw("Failed to retrieve Job ID from log", le.ForLogger("err", err.Error())...)
 		return false
 	} else if jid != le.Job.ID {
-		logger.Warnw(fmt.Sprintf("Run Log didn't have matching job ID: %v != %v", jid, le.Job.ID), le.ForLogger()...)
+		logger.Subscription.Warnw(fmt.Sprintf("Run Log didn't have matching job ID: %v != %v", jid, le.Job.ID), le.ForLogger()...)
 		return false
 	}
 	return true
@@ -257,7 +290,14 @@ func (le RpcLogEvent) RunLogJSON() (models.JSON, error) {
 	return js.Add("functionSelector", "76005c26")
 }
 
-// Reformat the log as JSON.
+// MatchesEvent returns true if the log is of the initiator's event.
+func (le RpcLogEvent) MatchesEvent() bool {
+	return len(le.Log.Topics) > 0 && le.Log.Topics[0] == le.Initiator.Event.Topic()
+}
+
+// Reformat the log as JSON. If the initiator has an event, its parameters
+// are decoded into fields named after them, and the log is kept under
+// "log".
 func (le RpcLogEvent) EthLogJSON() (models.JSON, error) {
 	el := le.Log
 	var out models.JSON
@@ -265,7 +305,21 @@ func (le RpcLogEvent) EthLogJSON() (models.JSON, error) {
 	if err != nil {
 		return out, err
 	}
-	return out, json.Unmarshal(b, &out)
+	if err := json.Unmarshal(b, &out); err != nil || le.Initiator.Event == nil {
+		return out, err
+	}
+
+	fields, err := le.Initiator.Event.Decode(el)
+	if err != nil {
+		return out, err
+	}
+	fields["log"] = json.RawMessage(b)
+	b, err = json.Marshal(fields)
+	if err != nil {
+		return out, err
+	}
+	var decoded models.JSON
+	return decoded, json.Unmarshal(b, &decoded)
 }
 
 func decodeABIToJSON(data hexutil.Bytes) (models.JSON, error) {
@@ -2,9 +2,11 @@ package services_test
 
 import (
 	"encoding/json"
+	"math/big"
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
 	"github.com/smartcontractkit/chainlink/services"
@@ -70,3 +72,33 @@ func TestServices_RunLogTopic_ExpectedEventSignature(t *testing.T) {
 	expected := "0x06f4bf36b4e011a5c499cef1113c2d166800ce4013f6c2509cab1a0e92b83fb2"
 	assert.Equal(t, expected, services.RunLogTopic.Hex())
 }
+
+func TestServices_RpcLogEvent_EthLogJSON_Event(t *testing.T) {
+	t.Parallel()
+
+	event := &models.EventABI{Name: "Transfer", Inputs: []models.EventInput{
+		{Name: "from", Type: "address", Indexed: true},
+		{Name: "to", Type: "address", Indexed: true},
+		{Name: "value", Type: "uint256"},
+	}}
+	from, to := cltest.NewAddress(), cltest.NewAddress()
+	log := types.Log{
+		Address: cltest.NewAddress(),
+		Topics:  []common.Hash{event.Topic(), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+	}
+
+	le := services.RpcLogEvent{Log: log, Initiator: models.Initiator{Type: models.InitiatorEthLog, Event: event}}
+	assert.True(t, le.MatchesEvent())
+	output, err := le.EthLogJSON()
+	assert.Nil(t, err)
+	assert.Equal(t, from.Hex(), output.Get("from").String())
+	assert.Equal(t, to.Hex(), output.Get("to").String())
+	assert.Equal(t, "1000", output.Get("value").String())
+	assert.Equal(t, log.Address.Hex(), common.HexToAddress(output.Get("log.address").String()).Hex())
+
+	le.Log.Topics[0] = services.RunLogTopic
+	assert.False(t, le.MatchesEvent())
+	_, err = le.EthLogJSON()
+	assert.NotNil(t, err)
+}
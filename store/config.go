@@ -1,52 +1,273 @@
 package store
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
 	"path"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	homedir "github.com/mitchellh/go-homedir"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
 	"github.com/smartcontractkit/env"
 	"go.uber.org/zap/zapcore"
 )
 
-// Config holds parameters used by the application which can be overridden
-// by setting environment variables.
+// Config holds parameters used by the application which can be set in a
+// config file, and overridden by setting environment variables. Settings
+// tagged secret are redacted when the config is shown.
 type Config struct {
-	LogLevel            LogLevel `env:"LOG_LEVEL" envDefault:"info"`
-	RootDir             string   `env:"ROOT" envDefault:"~/.chainlink"`
-	Port                string   `env:"PORT" envDefault:"6688"`
-	BasicAuthUsername   string   `env:"USERNAME" envDefault:"chainlink"`
-	BasicAuthPassword   string   `env:"PASSWORD" envDefault:"twochains"`
-	EthereumURL         string   `env:"ETH_URL" envDefault:"ws://localhost:8546"`
-	ChainID             uint64   `env:"ETH_CHAIN_ID" envDefault:"0"`
-	ClientNodeURL       string   `env:"CLIENT_NODE_URL" envDefault:"http://localhost:6688"`
-	EthMinConfirmations uint64   `env:"ETH_MIN_CONFIRMATIONS" envDefault:"12"`
-	EthGasBumpThreshold uint64   `env:"ETH_GAS_BUMP_THRESHOLD" envDefault:"12"`
-	EthGasBumpWei       big.Int  `env:"ETH_GAS_BUMP_WEI" envDefault:"5000000000"`
-	EthGasPriceDefault  big.Int  `env:"ETH_GAS_PRICE_DEFAULT" envDefault:"20000000000"`
+	LogLevel                   LogLevel      `env:"LOG_LEVEL" envDefault:"info"`
+	RootDir                    string        `env:"ROOT" envDefault:"~/.chainlink"`
+	ConfigFile                 string        `env:"CONFIG_FILE"`
+	Port                       string        `env:"PORT" envDefault:"6688"`
+	BasicAuthUsername          string        `env:"USERNAME" envDefault:"chainlink"`
+	BasicAuthPassword          string        `env:"PASSWORD" envDefault:"twochains" secret:"true"`
+	EthereumURL                string        `env:"ETH_URL" envDefault:"ws://localhost:8546"`
+	ChainID                    uint64        `env:"ETH_CHAIN_ID" envDefault:"0"`
+	ClientNodeURL              string        `env:"CLIENT_NODE_URL" envDefault:"http://localhost:6688"`
+	EthMinConfirmations        uint64        `env:"ETH_MIN_CONFIRMATIONS" envDefault:"12"`
+	EthGasBumpThreshold        uint64        `env:"ETH_GAS_BUMP_THRESHOLD" envDefault:"12"`
+	EthGasBumpWei              big.Int       `env:"ETH_GAS_BUMP_WEI" envDefault:"5000000000"`
+	EthGasPriceDefault         big.Int       `env:"ETH_GAS_PRICE_DEFAULT" envDefault:"20000000000"`
+	EthGasPriceMax             big.Int       `env:"ETH_GAS_PRICE_MAX" envDefault:"500000000000"`
+	DryRun                     bool          `env:"NODE_DRY_RUN" envDefault:"false"`
+	Profile                    string        `env:"CHAINLINK_ENV"`
+	JobFailureThreshold        uint64        `env:"JOB_FAILURE_THRESHOLD" envDefault:"0"`
+	StatusPageEnabled          bool          `env:"STATUS_PAGE_ENABLED" envDefault:"false"`
+	TrustedProxies             string        `env:"TRUSTED_PROXIES"`
+	EthSubscriptionConnections uint64        `env:"ETH_SUBSCRIPTION_CONNECTIONS" envDefault:"1"`
+	EthPollInterval            time.Duration `env:"ETH_POLL_INTERVAL" envDefault:"15s"`
+	TLSCertPath                string        `env:"TLS_CERT_PATH"`
+	TLSKeyPath                 string        `env:"TLS_KEY_PATH"`
+	TLSPort                    string        `env:"TLS_PORT" envDefault:"6689"`
+	TLSRedirect                bool          `env:"TLS_REDIRECT" envDefault:"false"`
+	TLSSelfSigned              bool          `env:"TLS_SELF_SIGNED" envDefault:"false"`
+	AllowOrigins               string        `env:"ALLOW_ORIGINS"`
+	MaxRequestBodySize         int64         `env:"MAX_REQUEST_BODY_SIZE" envDefault:"65536"`
+	EthMaxConcurrentRequests   uint64        `env:"ETH_MAX_CONCURRENT_REQUESTS" envDefault:"0"`
+	EthRequestTimeout          time.Duration `env:"ETH_REQUEST_TIMEOUT" envDefault:"30s"`
+	EthMaxBatchSize            uint64        `env:"ETH_MAX_BATCH_SIZE" envDefault:"100"`
+	EthMaxBatchCalls           uint64        `env:"ETH_MAX_BATCH_CALLS" envDefault:"16"`
+	EthRateLimit               uint64        `env:"ETH_RATE_LIMIT" envDefault:"0"`
+	EthRateLimitWeights        MethodWeights `env:"ETH_RATE_LIMIT_WEIGHTS"`
+	LinkContractAddress        string        `env:"LINK_CONTRACT_ADDRESS"`
+	MaxConcurrentRuns          uint64        `env:"MAX_CONCURRENT_RUNS" envDefault:"0"`
+	ArchiveEthereumURL         string        `env:"ARCHIVE_ETH_URL"`
+	ArchiveBlockAge            uint64        `env:"ARCHIVE_BLOCK_AGE" envDefault:"128"`
+	HeartbeatPeriod            time.Duration `env:"HEARTBEAT_PERIOD" envDefault:"0s"`
+	HeartbeatSpendLimit        big.Int       `env:"HEARTBEAT_SPEND_LIMIT" envDefault:"10000000000000000"`
+	EventExportURL             string        `env:"EVENT_EXPORT_URL"`
+	EventExportKafkaTopic      string        `env:"EVENT_EXPORT_KAFKA_TOPIC"`
+	EventExportMaxPending      uint64        `env:"EVENT_EXPORT_MAX_PENDING" envDefault:"10000"`
+	RunRetentionAge            time.Duration `env:"RUN_RETENTION_AGE" envDefault:"0s"`
+	RunRetentionCount          uint64        `env:"RUN_RETENTION_COUNT" envDefault:"0"`
+	RunAtGracePeriod           time.Duration `env:"RUN_AT_GRACE_PERIOD" envDefault:"0s"`
+	RunReaperPeriod            time.Duration `env:"RUN_REAPER_PERIOD" envDefault:"1h"`
+	AdapterTLSMinVersion       TLSVersion    `env:"ADAPTER_TLS_MIN_VERSION" envDefault:"1.2"`
+	AdapterCacheTTL            time.Duration `env:"ADAPTER_CACHE_TTL" envDefault:"0s"`
+	AdapterProxyURL            string        `env:"ADAPTER_PROXY_URL"`
+	AdapterAllowedDomains      string        `env:"ADAPTER_ALLOWED_DOMAINS"`
+	AdapterDeniedDomains       string        `env:"ADAPTER_DENIED_DOMAINS"`
+	AdapterBlockPrivateIPs     bool          `env:"ADAPTER_BLOCK_PRIVATE_IPS" envDefault:"false"`
+	SignFulfillments           bool          `env:"SIGN_FULFILLMENTS" envDefault:"false"`
+	SignerURL                  string        `env:"SIGNER_URL"`
+	SignerAddress              string        `env:"SIGNER_ADDRESS"`
+	KeyBackend                 string        `env:"KEY_BACKEND" envDefault:"keystore"`
+	VaultAddress               string        `env:"VAULT_ADDR"`
+	VaultToken                 string        `env:"VAULT_TOKEN" secret:"true"`
+	VaultKeyPath               string        `env:"VAULT_KEY_PATH"`
+	AWSRegion                  string        `env:"AWS_REGION"`
+	AWSAccessKeyID             string        `env:"AWS_ACCESS_KEY_ID"`
+	AWSSecretAccessKey         string        `env:"AWS_SECRET_ACCESS_KEY" secret:"true"`
+	AWSSessionToken            string        `env:"AWS_SESSION_TOKEN" secret:"true"`
+	AWSKMSKeyID                string        `env:"AWS_KMS_KEY_ID"`
+	AWSKMSEndpoint             string        `env:"AWS_KMS_ENDPOINT"`
+	CredentialsKey             string        `env:"CREDENTIALS_KEY" secret:"true"`
+	RecoveryAction             string        `env:"RECOVERY_ACTION" envDefault:"resume"`
+	RunLogMaxRuns              uint64        `env:"RUN_LOG_MAX_RUNS" envDefault:"1000"`
+	RunLogMaxLines             uint64        `env:"RUN_LOG_MAX_LINES" envDefault:"500"`
+	PasswordFile               string        `env:"PASSWORD_FILE"`
+	KeystorePassword           string        `env:"KEYSTORE_PASSWORD" secret:"true"`
+	EthDisplayUnit             EthUnit       `env:"ETH_DISPLAY_UNIT" envDefault:"ether"`
+	LinkDisplayUnit            LinkUnit      `env:"LINK_DISPLAY_UNIT" envDefault:"link"`
+	RegistryURL                string        `env:"REGISTRY_URL"`
+	RegistryToken              string        `env:"REGISTRY_TOKEN" secret:"true"`
+	ForwardURL                 string        `env:"FORWARD_URL"`
+	ForwardToken               string        `env:"FORWARD_TOKEN" secret:"true"`
+	LDAPURL                    string        `env:"LDAP_URL"`
+	LDAPUserDN                 string        `env:"LDAP_USER_DN"`
+	LDAPGroupAttribute         string        `env:"LDAP_GROUP_ATTRIBUTE" envDefault:"memberOf"`
+	LDAPAllowInsecure          bool          `env:"LDAP_ALLOW_INSECURE" envDefault:"false"`
+	OIDCIssuer                 string        `env:"OIDC_ISSUER"`
+	OIDCClientID               string        `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret           string        `env:"OIDC_CLIENT_SECRET" secret:"true"`
+	OIDCRedirectURL            string        `env:"OIDC_REDIRECT_URL"`
+	OIDCGroupsClaim            string        `env:"OIDC_GROUPS_CLAIM" envDefault:"groups"`
+	AuthGroupRoles             string        `env:"AUTH_GROUP_ROLES"`
+	DriftCheckPeriod           time.Duration `env:"DRIFT_CHECK_PERIOD" envDefault:"1h"`
+	LogFormat                  string        `env:"LOG_FORMAT" envDefault:"json"`
+	LogFileMaxSize             uint64        `env:"LOG_FILE_MAX_SIZE" envDefault:"104857600"`
+	LogFileMaxAge              time.Duration `env:"LOG_FILE_MAX_AGE" envDefault:"0s"`
+	LogFileMaxBackups          uint64        `env:"LOG_FILE_MAX_BACKUPS" envDefault:"5"`
+	LogSinkType                string        `env:"LOG_SINK_TYPE" envDefault:"http"`
+	LogSinkURL                 string        `env:"LOG_SINK_URL" secret:"true"`
+	LogSinkBatchSize           uint64        `env:"LOG_SINK_BATCH_SIZE" envDefault:"100"`
+	LogSinkFlushPeriod         time.Duration `env:"LOG_SINK_FLUSH_PERIOD" envDefault:"1s"`
+	LogSinkMaxPending          uint64        `env:"LOG_SINK_MAX_PENDING" envDefault:"10000"`
+	RunResultMaxSize           uint64        `env:"RUN_RESULT_MAX_SIZE" envDefault:"1048576"`
+	RunResultDenylist          string        `env:"RUN_RESULT_DENYLIST" envDefault:"password,secret,apiKey,api_key,accessToken,access_token,authorization,privateKey,private_key"`
+	EnclaveSocket              string        `env:"ENCLAVE_SOCKET"`
+	EnclaveTaskTypes           string        `env:"ENCLAVE_TASK_TYPES"`
+	RunWebhookURL              string        `env:"RUN_WEBHOOK_URL"`
+	RunWebhookSecret           string        `env:"RUN_WEBHOOK_SECRET" secret:"true"`
+	DevFunderKey               string        `env:"DEV_FUNDER_KEY" secret:"true"`
+	DevContractsDir            string        `env:"DEV_CONTRACTS_DIR"`
+
+	// fileSettings are the settings read from the config file, keyed by
+	// environment variable name, which take precedence over the profile.
+	fileSettings map[string]string
+}
+
+// profiles bundle sensible defaults for the environment a node runs in,
+// selected by CHAINLINK_ENV. Any setting that is explicitly set in the
+// config file or the environment takes precedence over the profile's value.
+var profiles = map[string]map[string]string{
+	"dev": {
+		"LOG_LEVEL":              "debug",
+		"ETH_MIN_CONFIRMATIONS":  "1",
+		"ETH_GAS_BUMP_THRESHOLD": "3",
+		"ETH_GAS_PRICE_DEFAULT":  "1000000000",
+		"ETH_GAS_PRICE_MAX":      "20000000000",
+	},
+	"staging": {
+		"LOG_LEVEL":              "debug",
+		"ETH_MIN_CONFIRMATIONS":  "6",
+		"ETH_GAS_BUMP_THRESHOLD": "6",
+		"ETH_GAS_PRICE_MAX":      "100000000000",
+	},
+	"prod": {
+		"LOG_LEVEL":              "info",
+		"ETH_MIN_CONFIRMATIONS":  "12",
+		"ETH_GAS_BUMP_THRESHOLD": "12",
+		"ETH_GAS_PRICE_MAX":      "500000000000",
+	},
 }
 
 // NewConfig returns the config with the environment variables set to their
-// respective fields, or defaults if not present.
+// respective fields, then the settings in the config file for those that
+// are not set, then defaults. The config file is CONFIG_FILE, or else the
+// first of ConfigFileNames in the root directory.
 func NewConfig() Config {
+	config, err := LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return config
+}
+
+// LoadConfig returns the config as NewConfig does, or an error if it is
+// invalid.
+func LoadConfig() (Config, error) {
 	config := Config{}
 	if err := parseEnv(&config); err != nil {
-		log.Fatal(err)
+		return config, err
+	}
+	if err := applyConfigFile(&config); err != nil {
+		return config, err
+	}
+	if err := applyProfile(&config); err != nil {
+		return config, err
 	}
 	dir, err := homedir.Expand(config.RootDir)
 	if err != nil {
-		log.Fatal(err)
+		return config, err
 	}
 	if err = os.MkdirAll(dir, os.FileMode(0700)); err != nil {
-		log.Fatal(err)
+		return config, err
 	}
 	config.RootDir = dir
-	return config
+	return config, nil
+}
+
+// UseProfile applies the named profile's defaults to any settings not
+// explicitly set in the config file or the environment.
+func (c *Config) UseProfile(name string) error {
+	c.Profile = name
+	return applyProfile(c)
+}
+
+// runtimeSettings are the settings, keyed by environment variable name,
+// that can be changed while the node is running.
+var runtimeSettings = map[string]bool{
+	"LOG_LEVEL":              true,
+	"ETH_URL":                true,
+	"ETH_MIN_CONFIRMATIONS":  true,
+	"ETH_GAS_BUMP_THRESHOLD": true,
+	"ETH_GAS_BUMP_WEI":       true,
+	"ETH_GAS_PRICE_DEFAULT":  true,
+	"ETH_GAS_PRICE_MAX":      true,
+	"JOB_FAILURE_THRESHOLD":  true,
+}
+
+// Change sets the runtime setting with the given environment variable name
+// to the given value, and returns its previous value.
+func (c *Config) Change(key, value string) (string, error) {
+	if !runtimeSettings[key] {
+		return "", fmt.Errorf("%v cannot be changed while the node is running", key)
+	}
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("env") != key {
+			continue
+		}
+		previous := fieldString(v.Field(i))
+		if err := setField(v.Field(i), value); err != nil {
+			return "", fmt.Errorf("%v: %v", key, err)
+		}
+		return previous, nil
+	}
+	return "", fmt.Errorf("Unknown setting %v", key)
+}
+
+// RuntimeSettings returns the names of the settings that can be changed
+// while the node is running, in order.
+func RuntimeSettings() []string {
+	keys := []string{}
+	for key := range runtimeSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setting returns the value of the setting with the given environment
+// variable name, as it would be written in the environment.
+func (c Config) setting(key string) string {
+	v := reflect.ValueOf(c)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("env") == key {
+			return fieldString(v.Field(i))
+		}
+	}
+	return ""
+}
+
+func fieldString(field reflect.Value) string {
+	if i, ok := field.Interface().(big.Int); ok {
+		return i.String()
+	}
+	return fmt.Sprint(field.Interface())
 }
 
 // KeysDir returns the path of the keys directory (used for keystore files).
@@ -54,11 +275,145 @@ func (c Config) KeysDir() string {
 	return path.Join(c.RootDir, "keys")
 }
 
+// TLSEnabled returns true if the web server should also be served over
+// HTTPS, with either the given certificate or a self-signed one.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertPath != "" || c.TLSSelfSigned
+}
+
+// ExternalSigner returns true if the node's key is held outside its
+// keystore, by a remote signer or a KMS, or read from Vault, so there is no
+// keystore password to enter.
+func (c Config) ExternalSigner() bool {
+	return c.SignerURL != "" || (c.KeyBackend != "" && c.KeyBackend != KeyBackendKeyStore)
+}
+
+// EnclaveTaskType returns true if tasks of the given type are performed by
+// the enclave at ENCLAVE_SOCKET, rather than by the node's adapters.
+func (c Config) EnclaveTaskType(taskType string) bool {
+	if c.EnclaveSocket == "" {
+		return false
+	}
+	for _, t := range strings.Split(c.EnclaveTaskTypes, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), taskType) {
+			return true
+		}
+	}
+	return false
+}
+
+// AdapterDomainLists returns the domains HTTP tasks of every job are
+// allowed and denied requests to.
+func (c Config) AdapterDomainLists() models.DomainLists {
+	return models.NewDomainLists(c.AdapterAllowedDomains, c.AdapterDeniedDomains)
+}
+
+// TLSDir returns the path of the directory that self-signed certificates
+// are generated in.
+func (c Config) TLSDir() string {
+	return path.Join(c.RootDir, "tls")
+}
+
+var customParsers = env.CustomParsers{
+	reflect.TypeOf(big.Int{}):        bigIntParser,
+	reflect.TypeOf(LogLevel{}):       levelParser,
+	reflect.TypeOf(time.Duration(0)): durationParser,
+	reflect.TypeOf(TLSVersion(0)):    tlsVersionParser,
+	reflect.TypeOf(EthUnit("")):      ethUnitParser,
+	reflect.TypeOf(LinkUnit("")):     linkUnitParser,
+	reflect.TypeOf(MethodWeights{}):  methodWeightsParser,
+}
+
 func parseEnv(cfg interface{}) error {
-	return env.ParseWithFuncs(cfg, env.CustomParsers{
-		reflect.TypeOf(big.Int{}):  bigIntParser,
-		reflect.TypeOf(LogLevel{}): levelParser,
-	})
+	return env.ParseWithFuncs(cfg, customParsers)
+}
+
+func applyConfigFile(cfg *Config) error {
+	p, err := findConfigFile(cfg)
+	if err != nil || p == "" {
+		return err
+	}
+	settings, err := readConfigFile(p)
+	if err != nil {
+		return err
+	}
+	cfg.fileSettings = settings
+	return setUnsetFields(cfg, settings)
+}
+
+func applyProfile(cfg *Config) error {
+	if cfg.Profile == "" {
+		return nil
+	}
+	profile, ok := profiles[strings.ToLower(cfg.Profile)]
+	if !ok {
+		return fmt.Errorf("Unknown CHAINLINK_ENV profile %v", cfg.Profile)
+	}
+	values := map[string]string{}
+	for key, value := range profile {
+		if _, inFile := cfg.fileSettings[key]; !inFile {
+			values[key] = value
+		}
+	}
+	return setUnsetFields(cfg, values)
+}
+
+// setUnsetFields assigns the given values, keyed by environment variable
+// name, to the Config fields whose variable is not set in the environment.
+func setUnsetFields(cfg *Config, values map[string]string) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("env")
+		str, ok := values[key]
+		if !ok {
+			continue
+		}
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := setField(v.Field(i), str); err != nil {
+			return fmt.Errorf("%v: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, str string) error {
+	if parser, ok := customParsers[field.Type()]; ok {
+		val, err := parser(str)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(val))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Uint64:
+		u, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Int64:
+		i, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	default:
+		return fmt.Errorf("unsupported config type %v", field.Type())
+	}
+	return nil
 }
 
 func bigIntParser(str string) (interface{}, error) {
@@ -69,6 +424,10 @@ func bigIntParser(str string) (interface{}, error) {
 	return *i, nil
 }
 
+func durationParser(str string) (interface{}, error) {
+	return time.ParseDuration(str)
+}
+
 func levelParser(str string) (interface{}, error) {
 	var lvl LogLevel
 	err := lvl.Set(str)
@@ -89,3 +448,138 @@ func (ll LogLevel) ForGin() string {
 		return gin.ReleaseMode
 	}
 }
+
+// TLSVersion is the lowest version of TLS to accept when connecting to
+// external adapters and data providers, written as 1.0, 1.1, or 1.2.
+type TLSVersion uint16
+
+var tlsVersions = map[string]TLSVersion{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+}
+
+// String returns the version as it is written in the environment.
+func (v TLSVersion) String() string {
+	for s, version := range tlsVersions {
+		if version == v {
+			return s
+		}
+	}
+	return ""
+}
+
+func tlsVersionParser(str string) (interface{}, error) {
+	version, ok := tlsVersions[str]
+	if !ok {
+		return version, fmt.Errorf("Unsupported TLS version %v, must be 1.0, 1.1, or 1.2", str)
+	}
+	return version, nil
+}
+
+// EthUnit is the unit amounts of ether, such as balances and gas prices,
+// are shown in: wei, gwei, or ether.
+type EthUnit string
+
+var ethUnits = map[EthUnit]int{
+	"wei":   0,
+	"gwei":  9,
+	"ether": 18,
+}
+
+// String returns the unit's name, ether if it is not set.
+func (u EthUnit) String() string {
+	if u == "" {
+		return "ether"
+	}
+	return string(u)
+}
+
+// Decimals returns how many decimal places of wei make up one of the unit.
+func (u EthUnit) Decimals() int {
+	return ethUnits[EthUnit(u.String())]
+}
+
+// Format writes the amount of wei in the unit, such as 1.5 ether.
+func (u EthUnit) Format(wei *big.Int) string {
+	return utils.FormatUnits(wei, u.Decimals()) + " " + u.String()
+}
+
+func ethUnitParser(str string) (interface{}, error) {
+	unit := EthUnit(strings.ToLower(str))
+	if _, ok := ethUnits[unit]; !ok {
+		return unit, fmt.Errorf("Unknown ether unit %v, must be wei, gwei, or ether", str)
+	}
+	return unit, nil
+}
+
+// LinkUnit is the unit amounts of LINK are shown in: juels, the token's
+// smallest unit, or link.
+type LinkUnit string
+
+var linkUnits = map[LinkUnit]int{
+	"juels": 0,
+	"link":  18,
+}
+
+// String returns the unit's name, link if it is not set.
+func (u LinkUnit) String() string {
+	if u == "" {
+		return "link"
+	}
+	return string(u)
+}
+
+// Decimals returns how many decimal places of juels make up one of the
+// unit.
+func (u LinkUnit) Decimals() int {
+	return linkUnits[LinkUnit(u.String())]
+}
+
+// Format writes the amount of juels in the unit, such as 1.5 link.
+func (u LinkUnit) Format(juels *big.Int) string {
+	return utils.FormatUnits(juels, u.Decimals()) + " " + u.String()
+}
+
+func linkUnitParser(str string) (interface{}, error) {
+	unit := LinkUnit(strings.ToLower(str))
+	if _, ok := linkUnits[unit]; !ok {
+		return unit, fmt.Errorf("Unknown LINK unit %v, must be juels or link", str)
+	}
+	return unit, nil
+}
+
+// MethodWeights are how much of ETH_RATE_LIMIT a call to each ethereum RPC
+// method uses, written as method=weight pairs separated by commas, such as
+// eth_getLogs=5,eth_call=2.
+type MethodWeights map[string]uint64
+
+// String returns the weights as they are written in the environment.
+func (w MethodWeights) String() string {
+	pairs := []string{}
+	for method, weight := range w {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", method, weight))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func methodWeightsParser(str string) (interface{}, error) {
+	weights := MethodWeights{}
+	for _, pair := range strings.Split(str, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.Index(pair, "=")
+		if i <= 0 {
+			return weights, fmt.Errorf("%v is not method=weight", pair)
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(pair[i+1:]), 10, 64)
+		if err != nil {
+			return weights, fmt.Errorf("Invalid weight for %v: %v", pair[:i], err)
+		}
+		weights[strings.TrimSpace(pair[:i])] = weight
+	}
+	return weights, nil
+}
@@ -0,0 +1,262 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ConfigFileNames are the files looked for in the root directory when
+// CONFIG_FILE is not set, in order.
+var ConfigFileNames = []string{"chainlink.toml", "chainlink.yaml", "chainlink.yml"}
+
+// The sources a setting's value can come from, from lowest precedence to
+// highest.
+const (
+	SourceDefault = "default"
+	SourceProfile = "profile"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// redacted is shown in place of secret settings that are set.
+const redacted = "[REDACTED]"
+
+// ConfigSetting is the effective value of one setting, and where it came
+// from.
+type ConfigSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// findConfigFile returns the path of the config file to read, or an empty
+// string if there is none.
+func findConfigFile(cfg *Config) (string, error) {
+	if cfg.ConfigFile != "" {
+		return homedir.Expand(cfg.ConfigFile)
+	}
+	dir, err := homedir.Expand(cfg.RootDir)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range ConfigFileNames {
+		p := path.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", nil
+}
+
+// readConfigFile returns the settings in the TOML or YAML file, keyed by
+// environment variable name.
+func readConfigFile(p string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".toml":
+		values, err = parseTOML(b)
+	case ".yaml", ".yml":
+		values, err = parseConfigYAML(b)
+	default:
+		return nil, fmt.Errorf("Config file %v must be .toml, .yaml, or .yml", p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", p, err)
+	}
+
+	settings := map[string]string{}
+	for key, value := range values {
+		key = strings.ToUpper(key)
+		if key == "CONFIG_FILE" || !isSetting(key) {
+			return nil, fmt.Errorf("%v: unknown setting %v", p, key)
+		}
+		settings[key] = value
+	}
+	return settings, nil
+}
+
+// parseTOML reads the top level keys of a TOML document. Settings are
+// flat, so tables and arrays are not supported.
+func parseTOML(b []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf("line %v: tables are not supported", n)
+		}
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %v: expected key = value", n)
+		}
+		key := strings.Trim(strings.TrimSpace(line[:i]), `"`)
+		value, err := tomlValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", n, err)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// tomlValue returns a basic string, literal string, number, or boolean,
+// without any comment after it.
+func tomlValue(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		end := 1
+		for ; end < len(s); end++ {
+			if s[end] == '\\' {
+				end++
+			} else if s[end] == '"' {
+				break
+			}
+		}
+		if end >= len(s) {
+			return "", fmt.Errorf("unterminated string %v", s)
+		}
+		if err := checkTrailing(s[end+1:]); err != nil {
+			return "", err
+		}
+		return strconv.Unquote(s[:end+1])
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %v", s)
+		}
+		if err := checkTrailing(s[end+2:]); err != nil {
+			return "", err
+		}
+		return s[1 : end+1], nil
+	case strings.HasPrefix(s, "["), strings.HasPrefix(s, "{"):
+		return "", fmt.Errorf("arrays and tables are not supported")
+	}
+	if i := strings.Index(s, "#"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	if s == "" {
+		return "", fmt.Errorf("missing value")
+	}
+	return strings.Replace(s, "_", "", -1), nil
+}
+
+func checkTrailing(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" && !strings.HasPrefix(s, "#") {
+		return fmt.Errorf("unexpected %v after value", s)
+	}
+	return nil
+}
+
+func parseConfigYAML(b []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for key, value := range raw {
+		switch value.(type) {
+		case map[interface{}]interface{}, []interface{}:
+			return nil, fmt.Errorf("%v must be a single value", key)
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
+
+// isSetting returns true if the key is the environment variable name of
+// a setting.
+func isSetting(key string) bool {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("env") == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings returns the effective value of every setting, in the order they
+// are declared, with where each came from. The values of secrets, and the
+// passwords in URLs, are redacted.
+func (c Config) Settings() []ConfigSetting {
+	profile := profiles[strings.ToLower(c.Profile)]
+	v := reflect.ValueOf(c)
+	t := v.Type()
+	settings := []ConfigSetting{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		value := fieldString(v.Field(i))
+		if field.Tag.Get("secret") == "true" && value != "" {
+			value = redacted
+		} else {
+			value = redactURL(value)
+		}
+
+		source := SourceDefault
+		if _, set := os.LookupEnv(key); set {
+			source = SourceEnv
+		} else if _, ok := c.fileSettings[key]; ok {
+			source = SourceFile
+		} else if _, ok := profile[key]; ok {
+			source = SourceProfile
+		}
+		settings = append(settings, ConfigSetting{Name: key, Value: value, Source: source})
+	}
+	return settings
+}
+
+// Digest returns the SHA-256 of the effective settings, as hex, so that
+// the config of nodes can be compared without revealing it. Secrets are
+// redacted before hashing, so changing one does not change the digest.
+func (c Config) Digest() string {
+	h := sha256.New()
+	for _, s := range c.Settings() {
+		fmt.Fprintf(h, "%s=%s\n", s.Name, s.Value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// redactURL hides the password of a URL with one.
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); !ok {
+		return value
+	}
+	u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	return u.String()
+}
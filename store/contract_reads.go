@@ -0,0 +1,54 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// CallContract calls the function with the given signature, such as
+// "latestAnswer()", on the contract with the args ABI encoded, against the
+// latest block, and returns what it returned. Nothing is sent on chain.
+func (txm *TxManager) CallContract(address common.Address, signature string, args ...interface{}) (models.ABIResult, error) {
+	data, err := models.EncodeCall(signature, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := txm.CallContractAt(address, hexutil.Encode(data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Calling %v on %v: %v", signature, address.Hex(), err)
+	}
+	return models.ABIResult(result), nil
+}
+
+// GetLatestAnswer returns the current answer of the aggregator contract at
+// the address.
+func (txm *TxManager) GetLatestAnswer(address common.Address) (*big.Int, error) {
+	result, err := txm.CallContract(address, "latestAnswer()")
+	if err != nil {
+		return nil, err
+	}
+	return result.Int256(0)
+}
+
+// GetLatestRound returns the ID of the aggregator contract's current round.
+func (txm *TxManager) GetLatestRound(address common.Address) (*big.Int, error) {
+	result, err := txm.CallContract(address, "latestRound()")
+	if err != nil {
+		return nil, err
+	}
+	return result.Uint256(0)
+}
+
+// GetAuthorizationStatus returns true if the oracle contract at the address
+// lets the node's account fulfill its requests.
+func (txm *TxManager) GetAuthorizationStatus(address, node common.Address) (bool, error) {
+	result, err := txm.CallContract(address, "getAuthorizationStatus(address)", node)
+	if err != nil {
+		return false, err
+	}
+	return result.Bool(0)
+}
@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxManager_GetLatestAnswer(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.MockEthOnStore(store)
+	contract := cltest.NewAddress()
+
+	answer := common.LeftPadBytes(big.NewInt(31415).Bytes(), 32)
+	eth.Register("eth_call", hexutil.Bytes(answer),
+		func(_ interface{}, data ...interface{}) error {
+			args := data[0].([]interface{})[0].(map[string]string)
+			assert.Equal(t, contract.Hex(), args["to"])
+			assert.Equal(t, "0x50d25bcd", args["data"])
+			return nil
+		})
+	latest, err := store.TxManager.GetLatestAnswer(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(31415), latest)
+
+	eth.Register("eth_call", hexutil.Bytes(common.LeftPadBytes(big.NewInt(7).Bytes(), 32)))
+	round, err := store.TxManager.GetLatestRound(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(7), round)
+
+	eth.EnsureAllCalled(t)
+}
+
+func TestTxManager_GetAuthorizationStatus(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.MockEthOnStore(store)
+	node := cltest.NewAddress()
+
+	eth.Register("eth_call", hexutil.Bytes(common.LeftPadBytes([]byte{1}, 32)),
+		func(_ interface{}, data ...interface{}) error {
+			args := data[0].([]interface{})[0].(map[string]string)
+			assert.Equal(t, common.Bytes2Hex(common.LeftPadBytes(node.Bytes(), 32)), args["data"][10:])
+			return nil
+		})
+	authorized, err := store.TxManager.GetAuthorizationStatus(cltest.NewAddress(), node)
+	assert.Nil(t, err)
+	assert.True(t, authorized)
+
+	eth.Register("eth_call", hexutil.Bytes{})
+	_, err = store.TxManager.GetAuthorizationStatus(cltest.NewAddress(), node)
+	assert.NotNil(t, err)
+
+	eth.EnsureAllCalled(t)
+}
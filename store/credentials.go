@@ -0,0 +1,149 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// credentialsKeyLength is the length of the AES-256 key credentials are
+// encrypted with.
+const credentialsKeyLength = 32
+
+// CredentialsKeyBytes returns the key credentials are encrypted with:
+// CREDENTIALS_KEY, as 64 hex characters, when it is set, so that the key
+// can be kept off the node's disk, or else the key in credentials.key in
+// the root directory, generated there by NewStore.
+func (c Config) CredentialsKeyBytes() ([]byte, error) {
+	if c.CredentialsKey != "" {
+		key, err := hex.DecodeString(strings.TrimPrefix(c.CredentialsKey, "0x"))
+		if err != nil || len(key) != credentialsKeyLength {
+			return nil, errors.New("CREDENTIALS_KEY must be 32 bytes as hex")
+		}
+		return key, nil
+	}
+
+	keyPath := c.credentialsKeyPath()
+	b, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(key) != credentialsKeyLength {
+		return nil, fmt.Errorf("Invalid credentials key in %v", keyPath)
+	}
+	return key, nil
+}
+
+func (c Config) credentialsKeyPath() string {
+	return path.Join(c.RootDir, "credentials.key")
+}
+
+// createCredentialsKey generates the key in credentials.key, unless
+// CREDENTIALS_KEY is set or there is a key there already. It is called
+// once as the store opens, and the file is created exclusively, so that a
+// key is never written over the one values were saved with.
+func createCredentialsKey(c Config) error {
+	if c.CredentialsKey != "" {
+		return nil
+	}
+	keyPath := c.credentialsKeyPath()
+	file, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		_, err = c.CredentialsKeyBytes()
+		return err
+	} else if err != nil {
+		return err
+	}
+	key := make([]byte, credentialsKeyLength)
+	_, err = io.ReadFull(rand.Reader, key)
+	if err == nil {
+		_, err = file.Write([]byte(hex.EncodeToString(key)))
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(keyPath)
+	}
+	return err
+}
+
+// SaveCredential encrypts the value and saves it under the name, replacing
+// the value of a credential already saved under it.
+func (s *Store) SaveCredential(name, value string) (models.Credential, error) {
+	name, err := models.NormalizeCredentialName(name)
+	if err != nil {
+		return models.Credential{}, err
+	}
+	if value == "" {
+		return models.Credential{}, errors.New("Credential must have a value")
+	}
+	gcm, err := s.credentialsCipher()
+	if err != nil {
+		return models.Credential{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return models.Credential{}, err
+	}
+
+	now := models.Time{Time: time.Now()}
+	credential, err := s.FindCredential(name)
+	if err != nil {
+		credential = models.Credential{Name: name, CreatedAt: now}
+	}
+	// Sealing with the name as additional data keeps a value from being
+	// moved to another credential in the database.
+	credential.EncryptedValue = gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	credential.UpdatedAt = now
+	return credential, s.Save(&credential)
+}
+
+// CredentialValue returns the decrypted value of the credential with the
+// name. Values are only decrypted when a task needs them.
+func (s *Store) CredentialValue(name string) (string, error) {
+	credential, err := s.FindCredential(name)
+	if err != nil {
+		return "", fmt.Errorf("Credential %v not found", name)
+	}
+	gcm, err := s.credentialsCipher()
+	if err != nil {
+		return "", err
+	}
+	sealed := credential.EncryptedValue
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("Credential %v is corrupt", name)
+	}
+	value, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(credential.Name))
+	if err != nil {
+		return "", fmt.Errorf("Credential %v cannot be decrypted with the credentials key", name)
+	}
+	return string(value), nil
+}
+
+func (s *Store) credentialsCipher() (cipher.AEAD, error) {
+	key, err := s.CurrentConfig().CredentialsKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,89 @@
+package store_test
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_SaveCredential(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	credential, err := store.SaveCredential(" Prices-API ", "s3cret")
+	assert.Nil(t, err)
+	assert.Equal(t, "prices-api", credential.Name)
+	assert.NotContains(t, string(credential.EncryptedValue), "s3cret")
+
+	value, err := store.CredentialValue("prices-api")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	rotated, err := store.SaveCredential("prices-api", "n3w")
+	assert.Nil(t, err)
+	assert.Equal(t, credential.CreatedAt.Unix(), rotated.CreatedAt.Unix())
+	value, err = store.CredentialValue("PRICES-API")
+	assert.Nil(t, err)
+	assert.Equal(t, "n3w", value)
+
+	_, err = store.CredentialValue("other")
+	assert.NotNil(t, err)
+	_, err = store.SaveCredential("no spaces", "value")
+	assert.NotNil(t, err)
+	_, err = store.SaveCredential("empty", "")
+	assert.NotNil(t, err)
+}
+
+func TestStore_CredentialValue_OtherKey(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	store.Config.CredentialsKey = strings.Repeat("ab", 32)
+	_, err := store.SaveCredential("prices-api", "s3cret")
+	assert.Nil(t, err)
+
+	store.Config.CredentialsKey = strings.Repeat("cd", 32)
+	_, err = store.CredentialValue("prices-api")
+	assert.NotNil(t, err, "values cannot be read without the key they were encrypted with")
+
+	store.Config.CredentialsKey = "short"
+	_, err = store.SaveCredential("prices-api", "s3cret")
+	assert.NotNil(t, err)
+}
+
+func TestStore_SaveCredential_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	_, err := os.Stat(path.Join(store.Config.RootDir, "credentials.key"))
+	assert.Nil(t, err, "the key is generated when the store opens")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.SaveCredential(fmt.Sprintf("api-%d", i), "s3cret")
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 5; i++ {
+		value, err := store.CredentialValue(fmt.Sprintf("api-%d", i))
+		assert.Nil(t, err)
+		assert.Equal(t, "s3cret", value)
+	}
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/smartcontractkit/chainlink/utils"
+)
+
+// BatchCaller sends several calls to the ethereum node in one JSON-RPC
+// batch request, such as an *rpc.Client does. The error of each call is
+// set on its BatchElem, and the error returned is only for the request as
+// a whole.
+type BatchCaller interface {
+	BatchCall([]rpc.BatchElem) error
+}
+
+// batchCall sends the calls through caller in one request if it can batch
+// them, and one at a time otherwise.
+func batchCall(caller CallerSubscriber, elems []rpc.BatchElem) error {
+	if bc, ok := caller.(BatchCaller); ok {
+		return bc.BatchCall(elems)
+	}
+	for i := range elems {
+		elems[i].Error = caller.Call(elems[i].Result, elems[i].Method, elems[i].Args...)
+	}
+	return nil
+}
+
+// BatchCall sends the calls to the ethereum node in batches of at most
+// MaxBatchSize, or all in one batch if it is 0, so that each takes a single
+// round trip and counts once against providers' rate limits.
+func (eth *EthClient) BatchCall(elems []rpc.BatchElem) error {
+	return eth.batchCallOn(eth.CallerSubscriber, elems)
+}
+
+func (eth *EthClient) batchCallOn(caller CallerSubscriber, elems []rpc.BatchElem) error {
+	size := eth.MaxBatchSize
+	if size <= 0 {
+		size = len(elems)
+	}
+	for start := 0; start < len(elems); start += size {
+		end := start + size
+		if end > len(elems) {
+			end = len(elems)
+		}
+		if err := batchCall(caller, elems[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTxReceipts returns the receipts of the transactions, in the same
+// order, fetched in one batch.
+func (eth *EthClient) GetTxReceipts(hashes []common.Hash) ([]*TxReceipt, error) {
+	receipts := make([]*TxReceipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		receipts[i] = &TxReceipt{}
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash.String()},
+			Result: receipts[i],
+		}
+	}
+	if err := eth.BatchCall(elems); err != nil {
+		return nil, err
+	}
+	for _, elem := range elems {
+		if elem.Error != nil {
+			return nil, elem.Error
+		}
+	}
+	return receipts, nil
+}
+
+// GetBalances returns the balance of the given address in wei, and in the
+// smallest unit of the ERC20 token at contractAddress, fetched in one
+// batch.
+func (eth *EthClient) GetBalances(address, contractAddress common.Address) (*big.Int, *big.Int, error) {
+	wei, tokens := "", ""
+	elems := []rpc.BatchElem{
+		{Method: "eth_getBalance", Args: []interface{}{address.Hex(), "latest"}, Result: &wei},
+		{Method: "eth_call", Args: []interface{}{erc20BalanceOfArgs(address, contractAddress), "latest"}, Result: &tokens},
+	}
+	if err := eth.BatchCall(elems); err != nil {
+		return nil, nil, err
+	}
+	balances := []*big.Int{}
+	for i, result := range []string{wei, tokens} {
+		if elems[i].Error != nil {
+			return nil, nil, elems[i].Error
+		}
+		balance, ok := new(big.Int).SetString(result, 0)
+		if !ok {
+			return nil, nil, fmt.Errorf("Unable to parse balance %v", result)
+		}
+		balances = append(balances, balance)
+	}
+	return balances[0], balances[1], nil
+}
+
+// GetLogsBatch returns the logs matching each of the queries, fetched in
+// one batch. If a query fails, the logs of the queries before it are
+// returned with its error. The batch is sent to the archive node if any
+// query starts from a block that pruned nodes no longer have.
+func (eth *EthClient) GetLogsBatch(qs []ethereum.FilterQuery) ([][]types.Log, error) {
+	results := make([][]types.Log, len(qs))
+	elems := make([]rpc.BatchElem, len(qs))
+	var from *big.Int
+	for i, q := range qs {
+		results[i] = []types.Log{}
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getLogs",
+			Args:   []interface{}{utils.ToFilterArg(q)},
+			Result: &results[i],
+		}
+		start := q.FromBlock
+		if start == nil {
+			start = big.NewInt(0)
+		}
+		if from == nil || start.Cmp(from) < 0 {
+			from = start
+		}
+	}
+	if len(qs) == 0 {
+		return results, nil
+	}
+	caller, err := eth.callerFor(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := eth.batchCallOn(caller, elems); err != nil {
+		return nil, err
+	}
+	for i, elem := range elems {
+		if elem.Error != nil {
+			return results[:i], elem.Error
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,108 @@
+package store_test
+
+import (
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// batchingCaller answers calls from its EthMock, recording the size of
+// each batch it is sent.
+type batchingCaller struct {
+	*cltest.EthMock
+	batches []int
+}
+
+func (bc *batchingCaller) BatchCall(elems []rpc.BatchElem) error {
+	bc.batches = append(bc.batches, len(elems))
+	for i := range elems {
+		elems[i].Error = bc.Call(elems[i].Result, elems[i].Method, elems[i].Args...)
+	}
+	return nil
+}
+
+func TestEthClient_BatchCall(t *testing.T) {
+	t.Parallel()
+
+	caller := &batchingCaller{EthMock: cltest.NewMockGethRpc()}
+	ec := &store.EthClient{CallerSubscriber: caller, MaxBatchSize: 2}
+
+	results := make([]string, 5)
+	elems := make([]rpc.BatchElem, 5)
+	for i := range elems {
+		caller.Register("eth_blockNumber", utils.Uint64ToHex(uint64(i)))
+		elems[i] = rpc.BatchElem{Method: "eth_blockNumber", Result: &results[i]}
+	}
+
+	assert.Nil(t, ec.BatchCall(elems))
+	assert.Equal(t, []int{2, 2, 1}, caller.batches)
+	assert.Equal(t, []string{"0x0", "0x1", "0x2", "0x3", "0x4"}, results)
+	caller.EnsureAllCalled(t)
+}
+
+func TestEthClient_GetTxReceipts(t *testing.T) {
+	t.Parallel()
+
+	eth := cltest.NewMockGethRpc()
+	ec := &store.EthClient{CallerSubscriber: eth}
+	confirmed := store.TxReceipt{Hash: cltest.NewHash(), BlockNumber: cltest.BigHexInt(10)}
+
+	eth.Register("eth_getTransactionReceipt", store.TxReceipt{})
+	eth.Register("eth_getTransactionReceipt", confirmed)
+	receipts, err := ec.GetTxReceipts([]common.Hash{cltest.NewHash(), confirmed.Hash})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(receipts))
+	assert.True(t, receipts[0].Unconfirmed())
+	assert.Equal(t, confirmed, *receipts[1])
+
+	eth.RegisterError("eth_getTransactionReceipt", "connection refused")
+	_, err = ec.GetTxReceipts([]common.Hash{cltest.NewHash()})
+	assert.NotNil(t, err)
+	eth.EnsureAllCalled(t)
+}
+
+func TestEthClient_GetBalances(t *testing.T) {
+	t.Parallel()
+
+	caller := &batchingCaller{EthMock: cltest.NewMockGethRpc()}
+	ec := &store.EthClient{CallerSubscriber: caller}
+
+	caller.Register("eth_getBalance", "0x0100")
+	caller.Register("eth_call", "0x00000000000000000000000000000000000000000000000014d1120d7b160000")
+	wei, juels, err := ec.GetBalances(cltest.NewAddress(), cltest.NewAddress())
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(256), wei)
+	assert.Equal(t, big.NewInt(1500000000000000000), juels)
+	assert.Equal(t, []int{2}, caller.batches)
+	caller.EnsureAllCalled(t)
+}
+
+func TestEthClient_GetLogsBatch(t *testing.T) {
+	t.Parallel()
+
+	caller := &batchingCaller{EthMock: cltest.NewMockGethRpc()}
+	ec := &store.EthClient{CallerSubscriber: caller}
+	first := []types.Log{{BlockNumber: 5}}
+	qs := []ethereum.FilterQuery{
+		{FromBlock: big.NewInt(1), ToBlock: big.NewInt(10)},
+		{FromBlock: big.NewInt(11), ToBlock: big.NewInt(20)},
+		{FromBlock: big.NewInt(21), ToBlock: big.NewInt(30)},
+	}
+
+	caller.Register("eth_getLogs", first)
+	caller.RegisterError("eth_getLogs", "query timeout exceeded")
+	caller.Register("eth_getLogs", []types.Log{})
+	results, err := ec.GetLogsBatch(qs)
+	assert.NotNil(t, err)
+	assert.Equal(t, [][]types.Log{first}, results)
+	assert.Equal(t, []int{3}, caller.batches)
+	caller.EnsureAllCalled(t)
+}
@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"math/big"
 
@@ -15,8 +17,17 @@ import (
 )
 
 // EthClient holds the CallerSubscriber interface for the Ethereum blockchain.
+// Log subscriptions go through LogSubscriber when it is set, such as to
+// spread them across a SubscriptionPool. Queries about blocks more than
+// ArchiveBlockAge behind the head go to Archive when it is set, since
+// pruned nodes no longer have their state. Batched calls are sent at most
+// MaxBatchSize at a time, or all at once if it is 0.
 type EthClient struct {
 	CallerSubscriber
+	LogSubscriber   Subscriber
+	Archive         CallerSubscriber
+	ArchiveBlockAge uint64
+	MaxBatchSize    int
 }
 
 // CallerSubscriber implements the Call and EthSubscribe functions. Call performs
@@ -55,6 +66,88 @@ func (eth *EthClient) GetEthBalance(address common.Address) (float64, error) {
 	return utils.WeiToEth(numWei), nil
 }
 
+// erc20BalanceOf is the function selector of balanceOf(address).
+const erc20BalanceOf = "0x70a08231"
+
+// GetERC20Balance returns the balance of the given address in the smallest
+// unit of the ERC20 token at contractAddress, such as LINK.
+func (eth *EthClient) GetERC20Balance(address, contractAddress common.Address) (*big.Int, error) {
+	result := ""
+	balance := new(big.Int)
+	args := erc20BalanceOfArgs(address, contractAddress)
+	if err := eth.Call(&result, "eth_call", args, "latest"); err != nil {
+		return balance, err
+	}
+	if _, ok := balance.SetString(result, 0); !ok {
+		return balance, fmt.Errorf("Unable to parse balance %v", result)
+	}
+	return balance, nil
+}
+
+// erc20BalanceOfArgs returns the eth_call args for the balance of the
+// given address of the ERC20 token at contractAddress.
+func erc20BalanceOfArgs(address, contractAddress common.Address) map[string]string {
+	return map[string]string{
+		"to":   contractAddress.Hex(),
+		"data": utils.HexConcat(erc20BalanceOf, common.BytesToHash(address.Bytes()).Hex()),
+	}
+}
+
+// GetLogs returns the logs matching the query. Queries starting from a
+// block that pruned nodes no longer have are sent to the archive node.
+func (eth *EthClient) GetLogs(q ethereum.FilterQuery) ([]types.Log, error) {
+	logs := []types.Log{}
+	from := q.FromBlock
+	if from == nil {
+		from = big.NewInt(0)
+	}
+	caller, err := eth.callerFor(from)
+	if err != nil {
+		return logs, err
+	}
+	err = caller.Call(&logs, "eth_getLogs", utils.ToFilterArg(q))
+	return logs, err
+}
+
+// CallContractAt calls the contract with the given data against the state
+// at the block, or the latest block if it is nil, and returns what it
+// returned. Calls against blocks that pruned nodes no longer have are sent
+// to the archive node.
+func (eth *EthClient) CallContractAt(contractAddress common.Address, data string, block *big.Int) (hexutil.Bytes, error) {
+	result := hexutil.Bytes{}
+	caller, err := eth.callerFor(block)
+	if err != nil {
+		return result, err
+	}
+	args := map[string]string{
+		"to":   contractAddress.Hex(),
+		"data": data,
+	}
+	blockArg := "latest"
+	if block != nil {
+		blockArg = hexutil.EncodeBig(block)
+	}
+	err = caller.Call(&result, "eth_call", args, blockArg)
+	return result, err
+}
+
+// callerFor returns the archive node for queries about blocks more than
+// ArchiveBlockAge behind the head, and the ethereum node otherwise, which
+// includes queries about the latest block, given as nil.
+func (eth *EthClient) callerFor(block *big.Int) (CallerSubscriber, error) {
+	if eth.Archive == nil || block == nil {
+		return eth.CallerSubscriber, nil
+	}
+	head, err := eth.GetBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	if head > eth.ArchiveBlockAge && block.Cmp(new(big.Int).SetUint64(head-eth.ArchiveBlockAge)) < 0 {
+		return eth.Archive, nil
+	}
+	return eth.CallerSubscriber, nil
+}
+
 // SendRawTx sends a signed transaction to the transaction pool.
 func (eth *EthClient) SendRawTx(hex string) (common.Hash, error) {
 	result := common.Hash{}
@@ -78,16 +171,31 @@ func (eth *EthClient) GetBlockNumber() (uint64, error) {
 	return utils.HexToUint64(result)
 }
 
+// GetChainID returns the ID of the chain the ethereum node is on, or its
+// network ID if it does not support eth_chainId, which is the same for the
+// main network and the public test networks.
+func (eth *EthClient) GetChainID() (uint64, error) {
+	result := ""
+	if err := eth.Call(&result, "eth_chainId"); err == nil {
+		return utils.HexToUint64(result)
+	}
+	if err := eth.Call(&result, "net_version"); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(result, 10, 64)
+}
+
 // SubscribeToLogs registers a subscription for push notifications of logs
 // from a given address.
 func (eth *EthClient) SubscribeToLogs(
 	channel chan<- types.Log,
 	q ethereum.FilterQuery,
-) (*rpc.ClientSubscription, error) {
+) (EthSubscription, error) {
 	// https://github.com/ethereum/go-ethereum/blob/762f3a48a00da02fe58063cb6ce8dc2d08821f15/ethclient/ethclient.go#L359
-	ctx := context.Background()
-	sub, err := eth.EthSubscribe(ctx, channel, "logs", utils.ToFilterArg(q))
-	return sub, err
+	if eth.LogSubscriber != nil {
+		return eth.LogSubscriber.Subscribe(channel, "logs", utils.ToFilterArg(q))
+	}
+	return rpcSubscriber{eth.CallerSubscriber}.Subscribe(channel, "logs", utils.ToFilterArg(q))
 }
 
 // SubscribeToNewHeads registers a subscription for push notifications of new blocks.
@@ -101,12 +209,19 @@ func (eth *EthClient) SubscribeToNewHeads(
 
 // TxReceipt holds the block number and the transaction hash of a signed
 // transaction that has been written to the blockchain.
+// Status is only set by nodes past the Byzantium fork.
 type TxReceipt struct {
-	BlockNumber hexutil.Big `json:"blockNumber"`
-	Hash        common.Hash `json:"transactionHash"`
+	BlockNumber hexutil.Big     `json:"blockNumber"`
+	Hash        common.Hash     `json:"transactionHash"`
+	Status      *hexutil.Uint64 `json:"status"`
 }
 
 // Unconfirmed returns true if the transaction is not confirmed.
 func (txr *TxReceipt) Unconfirmed() bool {
 	return common.EmptyHash(txr.Hash)
 }
+
+// Reverted returns true if the transaction was confirmed but failed.
+func (txr *TxReceipt) Reverted() bool {
+	return txr.Status != nil && *txr.Status == 0
+}
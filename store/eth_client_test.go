@@ -2,11 +2,17 @@ package store_test
 
 import (
 	"encoding/json"
+	"math/big"
 	"testing"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/gjson"
 )
@@ -83,6 +89,54 @@ func TestEthGetBalance(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestEthClient_GetLogs_Archive(t *testing.T) {
+	t.Parallel()
+
+	eth := cltest.NewMockGethRpc()
+	archive := cltest.NewMockGethRpc()
+	ec := &store.EthClient{CallerSubscriber: eth, Archive: archive, ArchiveBlockAge: 100}
+	recent := []types.Log{{BlockNumber: 950}}
+	old := []types.Log{{BlockNumber: 10}}
+
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(1000))
+	eth.Register("eth_getLogs", recent)
+	logs, err := ec.GetLogs(ethereum.FilterQuery{FromBlock: big.NewInt(900)})
+	assert.Nil(t, err)
+	assert.Equal(t, recent, logs)
+
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(1000))
+	archive.Register("eth_getLogs", old)
+	logs, err = ec.GetLogs(ethereum.FilterQuery{FromBlock: big.NewInt(899)})
+	assert.Nil(t, err)
+	assert.Equal(t, old, logs)
+
+	eth.EnsureAllCalled(t)
+	archive.EnsureAllCalled(t)
+}
+
+func TestEthClient_CallContractAt_Archive(t *testing.T) {
+	t.Parallel()
+
+	eth := cltest.NewMockGethRpc()
+	archive := cltest.NewMockGethRpc()
+	ec := &store.EthClient{CallerSubscriber: eth, Archive: archive, ArchiveBlockAge: 100}
+	contract := cltest.NewAddress()
+
+	eth.Register("eth_call", hexutil.Bytes{1})
+	result, err := ec.CallContractAt(contract, "0x70a08231", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, hexutil.Bytes{1}, result)
+
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(1000))
+	archive.Register("eth_call", hexutil.Bytes{2})
+	result, err = ec.CallContractAt(contract, "0x70a08231", big.NewInt(5))
+	assert.Nil(t, err)
+	assert.Equal(t, hexutil.Bytes{2}, result)
+
+	eth.EnsureAllCalled(t)
+	archive.EnsureAllCalled(t)
+}
+
 func TestBlockHeader_UnmarshalJSON(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EthSwitch sends calls and subscriptions to the ethereum node it is
+// connected to, and can be switched to another node while the node is
+// running, such as when ETH_URL changes. Subscriptions go through logs
+// when it is set, such as to spread them across a SubscriptionPool.
+type EthSwitch struct {
+	caller CallerSubscriber
+	logs   Subscriber
+	mutex  sync.RWMutex
+}
+
+// NewEthSwitch returns an EthSwitch connected to the given node.
+func NewEthSwitch(caller CallerSubscriber, logs Subscriber) *EthSwitch {
+	return &EthSwitch{caller: caller, logs: logs}
+}
+
+// Call performs the call on the node currently connected to.
+func (es *EthSwitch) Call(result interface{}, method string, args ...interface{}) error {
+	caller, _ := es.current()
+	return caller.Call(result, method, args...)
+}
+
+// BatchCall sends the calls in one batch to the node currently connected
+// to, or one at a time if it cannot batch them.
+func (es *EthSwitch) BatchCall(elems []rpc.BatchElem) error {
+	caller, _ := es.current()
+	return batchCall(caller, elems)
+}
+
+// EthSubscribe registers the subscription on the node currently
+// connected to.
+func (es *EthSwitch) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	caller, _ := es.current()
+	return caller.EthSubscribe(ctx, channel, args...)
+}
+
+// Subscribe registers the subscription on the node currently connected
+// to, through its subscription pool if it has one.
+func (es *EthSwitch) Subscribe(channel interface{}, args ...interface{}) (EthSubscription, error) {
+	caller, logs := es.current()
+	if logs != nil {
+		return logs.Subscribe(channel, args...)
+	}
+	return rpcSubscriber{caller}.Subscribe(channel, args...)
+}
+
+// Switch connects to the given node in place of the current one, and
+// returns the previous connection so that it can be closed once nothing
+// is subscribed through it.
+func (es *EthSwitch) Switch(caller CallerSubscriber, logs Subscriber) (CallerSubscriber, Subscriber) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	oldCaller, oldLogs := es.caller, es.logs
+	es.caller, es.logs = caller, logs
+	return oldCaller, oldLogs
+}
+
+func (es *EthSwitch) current() (CallerSubscriber, Subscriber) {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+	return es.caller, es.logs
+}
+
+// closer is implemented by connections to an ethereum node, such as
+// *rpc.Client.
+type closer interface {
+	Close()
+}
+
+// closeEthereum closes the connections, if they can be.
+func closeEthereum(connections ...interface{}) {
+	for _, c := range connections {
+		if c, ok := c.(closer); ok {
+			c.Close()
+		}
+	}
+}
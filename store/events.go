@@ -0,0 +1,84 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// eventBufferSize is how many events a subscriber can fall behind by
+// before events are dropped for it.
+const eventBufferSize = 100
+
+// EventBroadcaster sends every published models.Event to each of its
+// subscribers. Publishing never blocks on subscribers, so a subscriber that
+// falls behind misses events rather than holding up runs. Events are also
+// passed to the exporter, if one is set, which may block.
+type EventBroadcaster struct {
+	subscribers map[chan models.Event]bool
+	exporter    func(models.Event)
+	mutex       sync.Mutex
+}
+
+// NewEventBroadcaster returns an EventBroadcaster with no subscribers.
+func NewEventBroadcaster() *EventBroadcaster {
+	return &EventBroadcaster{subscribers: map[chan models.Event]bool{}}
+}
+
+// Subscribe returns a channel that receives every Event published from
+// now on, until it is unsubscribed.
+func (eb *EventBroadcaster) Subscribe() chan models.Event {
+	events := make(chan models.Event, eventBufferSize)
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.subscribers[events] = true
+	return events
+}
+
+// Unsubscribe stops sending events to the channel and closes it.
+func (eb *EventBroadcaster) Unsubscribe(events chan models.Event) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	if eb.subscribers[events] {
+		delete(eb.subscribers, events)
+		close(events)
+	}
+}
+
+// Export passes every event published from now on to the given function
+// before it is sent to subscribers, so that it cannot be missed.
+func (eb *EventBroadcaster) Export(exporter func(models.Event)) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.exporter = exporter
+}
+
+// Publish passes the event to the exporter, then sends it to every
+// subscriber with room for it. Publishing to a nil EventBroadcaster does
+// nothing.
+func (eb *EventBroadcaster) Publish(event models.Event) {
+	if eb == nil {
+		return
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	eb.mutex.Lock()
+	exporter := eb.exporter
+	eb.mutex.Unlock()
+	if exporter != nil {
+		exporter(event)
+	}
+
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	for events := range eb.subscribers {
+		select {
+		case events <- event:
+		default:
+			logger.Warnw("Event subscriber fell behind, dropping event", "type", event.Type, "run", event.RunID)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBroadcaster_Publish(t *testing.T) {
+	t.Parallel()
+
+	eb := store.NewEventBroadcaster()
+	first := eb.Subscribe()
+	second := eb.Subscribe()
+
+	eb.Publish(models.Event{Type: models.EventRunCreated, RunID: "1"})
+	assert.Equal(t, "1", (<-first).RunID)
+	event := <-second
+	assert.Equal(t, models.EventRunCreated, event.Type)
+	assert.False(t, event.CreatedAt.IsZero())
+
+	eb.Unsubscribe(first)
+	_, open := <-first
+	assert.False(t, open)
+	eb.Publish(models.Event{Type: models.EventRunErrored, RunID: "2"})
+	assert.Equal(t, "2", (<-second).RunID)
+}
+
+func TestEventBroadcaster_Publish_SlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	eb := store.NewEventBroadcaster()
+	events := eb.Subscribe()
+	for i := 0; i < 150; i++ {
+		eb.Publish(models.Event{Type: models.EventTaskCompleted})
+	}
+	assert.Equal(t, 100, len(events))
+
+	var nilBroadcaster *store.EventBroadcaster
+	nilBroadcaster.Publish(models.Event{Type: models.EventTaskCompleted})
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // KeyStore manages a key storage directory on disk.
@@ -43,6 +45,16 @@ func (ks *KeyStore) Unlock(phrase string) error {
 	return nil
 }
 
+// Unlocked returns true if there is an account and it has been unlocked,
+// so that transactions can be signed.
+func (ks *KeyStore) Unlocked() bool {
+	if !ks.HasAccounts() {
+		return false
+	}
+	_, err := ks.SignHash(ks.GetAccount(), crypto.Keccak256([]byte("unlocked")))
+	return err == nil
+}
+
 // SignTx uses the unlocked account to sign the given transaction.
 func (ks *KeyStore) SignTx(tx *types.Transaction, chainID uint64) (*types.Transaction, error) {
 	return ks.KeyStore.SignTx(
@@ -51,6 +63,34 @@ func (ks *KeyStore) SignTx(tx *types.Transaction, chainID uint64) (*types.Transa
 	)
 }
 
+// SignMessage uses the unlocked account to sign the data as eth_sign does.
+func (ks *KeyStore) SignMessage(data []byte) ([]byte, error) {
+	sig, err := ks.SignHash(ks.GetAccount(), MessageHash(data))
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// ExportKey returns the key of the account with the given address as geth
+// compatible encrypted JSON, decrypting it with passphrase and encrypting
+// it with newPassphrase.
+func (ks *KeyStore) ExportKey(address common.Address, passphrase, newPassphrase string) ([]byte, error) {
+	account, err := ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("Account %v is not in the keystore", address.Hex())
+	}
+	return ks.Export(account, passphrase, newPassphrase)
+}
+
+// ImportKey adds the key in the given geth compatible encrypted JSON to the
+// keystore, decrypting it with passphrase and storing it encrypted with
+// newPassphrase.
+func (ks *KeyStore) ImportKey(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error) {
+	return ks.Import(keyJSON, passphrase, newPassphrase)
+}
+
 // GetAccount returns the unlocked account in the KeyStore object. The client
 // ensures that an account exists during authentication.
 func (ks *KeyStore) GetAccount() accounts.Account {
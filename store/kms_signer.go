@@ -0,0 +1,282 @@
+package store
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KMSSigner signs with an asymmetric ECC_SECG_P256K1 key in AWS KMS, which
+// never leaves KMS. Requests are signed with the access key in
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+type KMSSigner struct {
+	Endpoint     string
+	Region       string
+	KeyID        string
+	AccessKeyID  string
+	SecretKey    string
+	SessionToken string
+	Clock        AfterNower
+	client       *http.Client
+	account      accounts.Account
+}
+
+// NewKMSSigner returns a signer for the AWS_KMS_KEY_ID key, first fetching
+// its public key to find the account it signs for.
+func NewKMSSigner(config Config) (*KMSSigner, error) {
+	if config.AWSRegion == "" || config.AWSKMSKeyID == "" {
+		return nil, errors.New("AWS_REGION and AWS_KMS_KEY_ID must be set to use AWS KMS")
+	}
+	if config.AWSAccessKeyID == "" || config.AWSSecretAccessKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to use AWS KMS")
+	}
+	endpoint := config.AWSKMSEndpoint
+	if endpoint == "" {
+		endpoint = "https://kms." + config.AWSRegion + ".amazonaws.com"
+	}
+	ks := &KMSSigner{
+		Endpoint:     endpoint,
+		Region:       config.AWSRegion,
+		KeyID:        config.AWSKMSKeyID,
+		AccessKeyID:  config.AWSAccessKeyID,
+		SecretKey:    config.AWSSecretAccessKey,
+		SessionToken: config.AWSSessionToken,
+		Clock:        Clock{},
+	}
+	return ks, ks.fetchAccount()
+}
+
+// HasAccounts returns true, as the account was found when the signer was
+// made.
+func (ks *KMSSigner) HasAccounts() bool {
+	return true
+}
+
+// GetAccount returns the account of the KMS key.
+func (ks *KMSSigner) GetAccount() accounts.Account {
+	return ks.account
+}
+
+// Unlocked returns true if KMS still gives the key's public key.
+func (ks *KMSSigner) Unlocked() bool {
+	_, err := ks.publicKey()
+	return err == nil
+}
+
+// SignTx has KMS sign the transaction's hash.
+func (ks *KMSSigner) SignTx(tx *types.Transaction, chainID uint64) (*types.Transaction, error) {
+	signer := types.NewEIP155Signer(big.NewInt(int64(chainID)))
+	sig, err := ks.signDigest(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignMessage has KMS sign the data as eth_sign does.
+func (ks *KMSSigner) SignMessage(data []byte) ([]byte, error) {
+	sig, err := ks.signDigest(MessageHash(data))
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// signDigest has KMS sign the hash, and recovers the signature's recovery
+// ID, which KMS does not give, by checking which one recovers the key's
+// account.
+func (ks *KMSSigner) signDigest(hash []byte) ([]byte, error) {
+	var resp struct {
+		Signature []byte `json:"Signature"`
+	}
+	err := ks.call("Sign", map[string]interface{}{
+		"KeyId":            ks.KeyID,
+		"Message":          hash,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	var der struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(resp.Signature, &der); err != nil {
+		return nil, fmt.Errorf("AWS KMS returned an invalid signature: %v", err)
+	}
+
+	// Ethereum only accepts signatures in the lower half of the curve.
+	n := crypto.S256().Params().N
+	if der.S.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		der.S.Sub(n, der.S)
+	}
+	sig := make([]byte, 65)
+	copy(sig[32-len(der.R.Bytes()):32], der.R.Bytes())
+	copy(sig[64-len(der.S.Bytes()):64], der.S.Bytes())
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.Ecrecover(hash, sig)
+		if err == nil && pubkeyAddress(pub) == ks.account.Address {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("AWS KMS signature does not recover %v", ks.account.Address.Hex())
+}
+
+func (ks *KMSSigner) fetchAccount() error {
+	pub, err := ks.publicKey()
+	if err != nil {
+		return err
+	}
+	ks.account = accounts.Account{Address: pubkeyAddress(pub)}
+	return nil
+}
+
+// publicKey returns the KMS key's public key, uncompressed.
+func (ks *KMSSigner) publicKey() ([]byte, error) {
+	var resp struct {
+		PublicKey []byte `json:"PublicKey"`
+	}
+	if err := ks.call("GetPublicKey", map[string]interface{}{"KeyId": ks.KeyID}, &resp); err != nil {
+		return nil, err
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(resp.PublicKey, &spki); err != nil {
+		return nil, fmt.Errorf("AWS KMS returned an invalid public key: %v", err)
+	}
+	pub := spki.PublicKey.Bytes
+	if len(pub) != 65 || pub[0] != 4 {
+		return nil, errors.New("AWS KMS key is not an uncompressed ECC_SECG_P256K1 key")
+	}
+	return pub, nil
+}
+
+func pubkeyAddress(pub []byte) common.Address {
+	return common.BytesToAddress(crypto.Keccak256(pub[1:])[12:])
+}
+
+// call makes a request to the KMS action, signed with Signature Version 4.
+func (ks *KMSSigner) call(action string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(ks.Endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	ks.sign(req, body)
+
+	if ks.client == nil {
+		ks.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("AWS KMS %v: %v", action, err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("AWS KMS %v: %v", action, err)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("AWS KMS %v responded %v: %s", action, resp.StatusCode, b)
+	}
+	return json.Unmarshal(b, result)
+}
+
+// sign adds the Signature Version 4 authorization for the request.
+func (ks *KMSSigner) sign(req *http.Request, body []byte) {
+	now := ks.Clock.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if ks.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", ks.SessionToken)
+	}
+
+	host := req.URL.Host
+	names := []string{"content-type", "host", "x-amz-date"}
+	if ks.SessionToken != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+	var canonicalHeaders string
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = host
+		}
+		canonicalHeaders += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + ks.Region + "/kms/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+ks.SecretKey), date)
+	key = hmacSHA256(key, ks.Region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		ks.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func canonicalQuery(values url.Values) string {
+	return strings.Replace(values.Encode(), "+", "%20", -1)
+}
+
+func hexSHA256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,204 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/logger"
+)
+
+// LogMultiplexer shares one subscription to the ethereum node's logs among
+// every subscriber with the same addresses and topics, and fans the logs
+// it receives out to each of them, so that a node with many jobs listening
+// to the same contracts keeps one subscription open per contract set rather
+// than one per job. The upstream subscription is opened with the first
+// subscriber's query and cancelled once its last subscriber unsubscribes.
+type LogMultiplexer struct {
+	subscribe func(chan<- types.Log, ethereum.FilterQuery) (EthSubscription, error)
+	filters   map[string]*sharedLogFilter
+	mutex     sync.Mutex
+}
+
+// NewLogMultiplexer returns a LogMultiplexer opening its upstream
+// subscriptions with the given function.
+func NewLogMultiplexer(subscribe func(chan<- types.Log, ethereum.FilterQuery) (EthSubscription, error)) *LogMultiplexer {
+	return &LogMultiplexer{
+		subscribe: subscribe,
+		filters:   map[string]*sharedLogFilter{},
+	}
+}
+
+type sharedLogFilter struct {
+	key       string
+	logs      chan types.Log
+	done      chan struct{}
+	upstream  EthSubscription
+	listeners map[*SharedLogSubscription]bool
+}
+
+// Subscribe sends the logs matching the query to the channel, through the
+// upstream subscription of an earlier subscriber with the same query if
+// there is one.
+func (lm *LogMultiplexer) Subscribe(channel chan<- types.Log, q ethereum.FilterQuery) (EthSubscription, error) {
+	key := logFilterKey(q)
+	sub := &SharedLogSubscription{
+		multiplexer: lm,
+		channel:     channel,
+		errors:      make(chan error, 1),
+		done:        make(chan struct{}),
+	}
+
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+	filter, ok := lm.filters[key]
+	if !ok {
+		filter = &sharedLogFilter{
+			key:       key,
+			logs:      make(chan types.Log),
+			done:      make(chan struct{}),
+			listeners: map[*SharedLogSubscription]bool{},
+		}
+		upstream, err := lm.subscribe(filter.logs, q)
+		if err != nil {
+			return nil, err
+		}
+		filter.upstream = upstream
+		lm.filters[key] = filter
+		go lm.fanOut(filter)
+		if upstream != nil && upstream.Err() != nil {
+			go lm.watch(filter)
+		}
+	}
+	sub.filter = filter
+	filter.listeners[sub] = true
+	return sub, nil
+}
+
+// Filters returns the number of upstream subscriptions open.
+func (lm *LogMultiplexer) Filters() int {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+	return len(lm.filters)
+}
+
+func (lm *LogMultiplexer) fanOut(filter *sharedLogFilter) {
+	for {
+		var log types.Log
+		select {
+		case <-filter.done:
+			return
+		case log = <-filter.logs:
+		}
+
+		lm.mutex.Lock()
+		listeners := make([]*SharedLogSubscription, 0, len(filter.listeners))
+		for sub := range filter.listeners {
+			listeners = append(listeners, sub)
+		}
+		lm.mutex.Unlock()
+
+		for _, sub := range listeners {
+			sub.deliver(log)
+		}
+	}
+}
+
+// watch stops sharing the filter if its upstream subscription fails, so
+// that the next subscriber opens a new one, and passes the error on to its
+// subscribers.
+func (lm *LogMultiplexer) watch(filter *sharedLogFilter) {
+	err, ok := <-filter.upstream.Err()
+	if !ok {
+		return
+	}
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+	if lm.filters[filter.key] != filter {
+		return
+	}
+	logger.Subscription.Warnw("Shared log subscription failed", "err", err, "subscribers", len(filter.listeners))
+	delete(lm.filters, filter.key)
+	for sub := range filter.listeners {
+		select {
+		case sub.errors <- err:
+		default:
+		}
+	}
+}
+
+// unsubscribe removes the subscription from its filter, cancelling the
+// upstream subscription if it was the last one.
+func (lm *LogMultiplexer) unsubscribe(sub *SharedLogSubscription) {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+	filter := sub.filter
+	delete(filter.listeners, sub)
+	if len(filter.listeners) > 0 {
+		return
+	}
+	if lm.filters[filter.key] == filter {
+		delete(lm.filters, filter.key)
+	}
+	close(filter.done)
+	if filter.upstream != nil && filter.upstream.Err() != nil {
+		filter.upstream.Unsubscribe()
+	}
+}
+
+// logFilterKey returns the same key for queries with the same addresses
+// and topics, whatever their order or block range.
+func logFilterKey(q ethereum.FilterQuery) string {
+	addresses := make([]string, len(q.Addresses))
+	for i, address := range q.Addresses {
+		addresses[i] = strings.ToLower(address.Hex())
+	}
+	sort.Strings(addresses)
+	return fmt.Sprintf("%v|%v", addresses, q.Topics)
+}
+
+// SharedLogSubscription is one subscriber's share of a LogMultiplexer's
+// upstream subscription.
+type SharedLogSubscription struct {
+	multiplexer *LogMultiplexer
+	filter      *sharedLogFilter
+	channel     chan<- types.Log
+	errors      chan error
+	done        chan struct{}
+	sending     sync.Mutex
+	stopped     bool
+	once        sync.Once
+}
+
+// Err returns a channel that receives the error of the upstream
+// subscription if it fails.
+func (sub *SharedLogSubscription) Err() <-chan error {
+	return sub.errors
+}
+
+// Unsubscribe stops sending logs to the subscriber. Once it returns,
+// nothing more is sent to its channel, which can then be closed.
+func (sub *SharedLogSubscription) Unsubscribe() {
+	sub.once.Do(func() {
+		close(sub.done)
+		sub.sending.Lock()
+		sub.stopped = true
+		sub.sending.Unlock()
+		sub.multiplexer.unsubscribe(sub)
+	})
+}
+
+func (sub *SharedLogSubscription) deliver(log types.Log) {
+	sub.sending.Lock()
+	defer sub.sending.Unlock()
+	if sub.stopped {
+		return
+	}
+	select {
+	case sub.channel <- log:
+	case <-sub.done:
+	}
+}
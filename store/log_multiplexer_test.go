@@ -0,0 +1,112 @@
+package store_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLogUpstream struct {
+	channel      chan<- types.Log
+	errors       chan error
+	unsubscribed bool
+}
+
+func (fu *fakeLogUpstream) Err() <-chan error { return fu.errors }
+func (fu *fakeLogUpstream) Unsubscribe()      { fu.unsubscribed = true }
+
+type fakeLogSubscriber struct {
+	upstreams []*fakeLogUpstream
+	mutex     sync.Mutex
+}
+
+func (fs *fakeLogSubscriber) subscribe(channel chan<- types.Log, q ethereum.FilterQuery) (store.EthSubscription, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	upstream := &fakeLogUpstream{channel: channel, errors: make(chan error, 1)}
+	fs.upstreams = append(fs.upstreams, upstream)
+	return upstream, nil
+}
+
+func TestLogMultiplexer_Subscribe_SharesFilters(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeLogSubscriber{}
+	lm := store.NewLogMultiplexer(fs.subscribe)
+	a, b := cltest.NewAddress(), cltest.NewAddress()
+
+	first, second, other := make(chan types.Log), make(chan types.Log), make(chan types.Log)
+	firstSub, err := lm.Subscribe(first, ethereum.FilterQuery{Addresses: []common.Address{a, b}})
+	assert.Nil(t, err)
+	secondSub, err := lm.Subscribe(second, ethereum.FilterQuery{Addresses: []common.Address{b, a}})
+	assert.Nil(t, err)
+	otherSub, err := lm.Subscribe(other, ethereum.FilterQuery{Addresses: []common.Address{a}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fs.upstreams))
+	assert.Equal(t, 2, lm.Filters())
+
+	log := types.Log{Address: a, BlockNumber: 7}
+	fs.upstreams[0].channel <- log
+	assert.Equal(t, log, <-first)
+	assert.Equal(t, log, <-second)
+
+	firstSub.Unsubscribe()
+	assert.False(t, fs.upstreams[0].unsubscribed)
+	fs.upstreams[0].channel <- log
+	assert.Equal(t, log, <-second)
+
+	secondSub.Unsubscribe()
+	assert.True(t, fs.upstreams[0].unsubscribed)
+	assert.Equal(t, 1, lm.Filters())
+
+	otherSub.Unsubscribe()
+	assert.Equal(t, 0, lm.Filters())
+}
+
+func TestLogMultiplexer_Unsubscribe_WhileDelivering(t *testing.T) {
+	t.Parallel()
+
+	fs := &fakeLogSubscriber{}
+	lm := store.NewLogMultiplexer(fs.subscribe)
+	q := ethereum.FilterQuery{Addresses: []common.Address{cltest.NewAddress()}}
+
+	idle, busy := make(chan types.Log), make(chan types.Log)
+	idleSub, err := lm.Subscribe(idle, q)
+	assert.Nil(t, err)
+	busySub, err := lm.Subscribe(busy, q)
+	assert.Nil(t, err)
+
+	// Nothing reads idle, so the log waits on it until it unsubscribes.
+	fs.upstreams[0].channel <- types.Log{}
+	idleSub.Unsubscribe()
+	close(idle)
+	<-busy
+	busySub.Unsubscribe()
+}
+
+func TestLogMultiplexer_UpstreamError(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	fs := &fakeLogSubscriber{}
+	lm := store.NewLogMultiplexer(fs.subscribe)
+	q := ethereum.FilterQuery{Addresses: []common.Address{cltest.NewAddress()}}
+
+	sub, err := lm.Subscribe(make(chan types.Log), q)
+	assert.Nil(t, err)
+	fs.upstreams[0].errors <- errors.New("connection dropped")
+	g.Eventually(sub.Err()).Should(gomega.Receive())
+	g.Eventually(lm.Filters).Should(gomega.Equal(0))
+
+	_, err = lm.Subscribe(make(chan types.Log), q)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fs.upstreams))
+}
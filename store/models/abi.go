@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// abiWordLength is the length of each argument to and value returned from
+// a contract call, in bytes.
+const abiWordLength = 32
+
+// FunctionSelectorFor returns the selector of the function with the given
+// signature, such as "getAuthorizationStatus(address)".
+func FunctionSelectorFor(signature string) FunctionSelector {
+	return BytesToFunctionSelector(crypto.Keccak256([]byte(signature)))
+}
+
+// EncodeCall returns the call data calling the function with the given
+// signature with the args, each ABI encoded as a 32 byte word. Args can be
+// a common.Address, common.Hash, bool, uint64, int64, or *big.Int, which is
+// encoded as an int256 if it is negative.
+func EncodeCall(signature string, args ...interface{}) ([]byte, error) {
+	selector := FunctionSelectorFor(signature)
+	data := append([]byte{}, selector[:]...)
+	for i, arg := range args {
+		word, err := abiWord(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%v argument %d: %v", signature, i, err)
+		}
+		data = append(data, word...)
+	}
+	return data, nil
+}
+
+func abiWord(arg interface{}) ([]byte, error) {
+	switch v := arg.(type) {
+	case common.Address:
+		return common.LeftPadBytes(v.Bytes(), abiWordLength), nil
+	case common.Hash:
+		return v.Bytes(), nil
+	case bool:
+		if v {
+			return common.LeftPadBytes([]byte{1}, abiWordLength), nil
+		}
+		return make([]byte, abiWordLength), nil
+	case uint64:
+		return abiWord(new(big.Int).SetUint64(v))
+	case int64:
+		return abiWord(big.NewInt(v))
+	case *big.Int:
+		if v.BitLen() > 256 || (v.Sign() < 0 && v.BitLen() > 255) {
+			return nil, fmt.Errorf("%v does not fit in 256 bits", v)
+		}
+		return math.PaddedBigBytes(math.U256(new(big.Int).Set(v)), abiWordLength), nil
+	}
+	return nil, fmt.Errorf("cannot ABI encode %T", arg)
+}
+
+// ABIResult is what a contract call returned, as ABI encoded 32 byte words.
+type ABIResult []byte
+
+func (r ABIResult) word(i int) ([]byte, error) {
+	start := i * abiWordLength
+	if i < 0 || start+abiWordLength > len(r) {
+		return nil, fmt.Errorf("Contract returned %v bytes, too few for value %d", len(r), i)
+	}
+	return r[start : start+abiWordLength], nil
+}
+
+// Uint256 returns the i-th value returned as an unsigned integer.
+func (r ABIResult) Uint256(i int) (*big.Int, error) {
+	word, err := r.word(i)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(word), nil
+}
+
+// Int256 returns the i-th value returned as a signed integer.
+func (r ABIResult) Int256(i int) (*big.Int, error) {
+	value, err := r.Uint256(i)
+	if err != nil {
+		return nil, err
+	}
+	return math.S256(value), nil
+}
+
+// Bool returns the i-th value returned as a bool.
+func (r ABIResult) Bool(i int) (bool, error) {
+	value, err := r.Uint256(i)
+	if err != nil {
+		return false, err
+	}
+	return value.Sign() != 0, nil
+}
+
+// Address returns the i-th value returned as an address.
+func (r ABIResult) Address(i int) (common.Address, error) {
+	word, err := r.word(i)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(word), nil
+}
+
+// Bytes32 returns the i-th value returned as a bytes32.
+func (r ABIResult) Bytes32(i int) (common.Hash, error) {
+	word, err := r.word(i)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(word), nil
+}
+
+// DynamicBytes returns the i-th value returned as a bytes or string, which
+// is stored after the other values at the offset given by the i-th word.
+func (r ABIResult) DynamicBytes(i int) ([]byte, error) {
+	offset, err := r.Uint256(i)
+	if err != nil {
+		return nil, err
+	}
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(r)) || offset.Uint64()%abiWordLength != 0 {
+		return nil, fmt.Errorf("Value %d has an invalid offset %v", i, offset)
+	}
+	tail := r[offset.Uint64():]
+	length, err := tail.Uint256(0)
+	if err != nil {
+		return nil, err
+	}
+	if !length.IsUint64() || length.Uint64() > uint64(len(tail)-abiWordLength) {
+		return nil, fmt.Errorf("Value %d is %v bytes long, more than was returned", i, length)
+	}
+	return tail[abiWordLength : abiWordLength+length.Uint64()], nil
+}
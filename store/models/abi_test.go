@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionSelectorFor(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0x70a08231", models.FunctionSelectorFor("balanceOf(address)").String())
+	assert.Equal(t, "0x50d25bcd", models.FunctionSelectorFor("latestAnswer()").String())
+}
+
+func TestEncodeCall(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f")
+	data, err := models.EncodeCall("balanceOf(address)", address)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x70a08231"+"0000000000000000000000009ca9d2d5e04012c9ed24c0e513c9bfaa4a2dd77f", hexutil.Encode(data))
+
+	data, err = models.EncodeCall("f(int256,bool,uint64)", big.NewInt(-1), true, uint64(2))
+	assert.Nil(t, err)
+	assert.Equal(t, 4+3*32, len(data))
+	assert.Equal(t, "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", common.Bytes2Hex(data[4:36]))
+	assert.Equal(t, byte(1), data[67])
+	assert.Equal(t, byte(2), data[99])
+
+	_, err = models.EncodeCall("f(string)", "nope")
+	assert.NotNil(t, err)
+	_, err = models.EncodeCall("f(uint256)", new(big.Int).Lsh(big.NewInt(1), 256))
+	assert.NotNil(t, err)
+}
+
+func TestABIResult(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f")
+	data, err := models.EncodeCall("f(int256,bool,address)", big.NewInt(-250), true, address)
+	assert.Nil(t, err)
+	result := models.ABIResult(data[4:])
+
+	answer, err := result.Int256(0)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(-250), answer)
+	unsigned, err := result.Uint256(0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, unsigned.Sign())
+	authorized, err := result.Bool(1)
+	assert.Nil(t, err)
+	assert.True(t, authorized)
+	decoded, err := result.Address(2)
+	assert.Nil(t, err)
+	assert.Equal(t, address, decoded)
+
+	_, err = result.Uint256(3)
+	assert.NotNil(t, err)
+}
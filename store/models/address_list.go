@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddressList is a named list of contract addresses that log initiators
+// can listen to by its name, so that one job serves every contract on the
+// list, such as a set of price feed proxies. When the list changes, the
+// subscriptions of the jobs listening to it are restarted.
+type AddressList struct {
+	Name      string           `json:"name" storm:"id,unique"`
+	Addresses []common.Address `json:"addresses"`
+	UpdatedAt Time             `json:"updatedAt"`
+}
+
+// NewAddressList returns an address list with the given name holding the
+// addresses, without duplicates.
+func NewAddressList(name string, addresses []common.Address) (AddressList, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return AddressList{}, errors.New("Address list must have a name")
+	}
+	unique := []common.Address{}
+	seen := map[common.Address]bool{}
+	for _, address := range addresses {
+		if address == (common.Address{}) {
+			return AddressList{}, errors.New("Address list cannot hold the zero address")
+		}
+		if !seen[address] {
+			seen[address] = true
+			unique = append(unique, address)
+		}
+	}
+	return AddressList{Name: name, Addresses: unique}, nil
+}
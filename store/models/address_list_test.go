@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAddressList(t *testing.T) {
+	t.Parallel()
+
+	a, b := cltest.NewAddress(), cltest.NewAddress()
+	list, err := models.NewAddressList(" Feeds ", []common.Address{a, b, a})
+	assert.Nil(t, err)
+	assert.Equal(t, "feeds", list.Name)
+	assert.Equal(t, []common.Address{a, b}, list.Addresses)
+
+	_, err = models.NewAddressList("", []common.Address{a})
+	assert.NotNil(t, err)
+	_, err = models.NewAddressList("feeds", []common.Address{{}})
+	assert.NotNil(t, err)
+}
+
+func TestORM_LogAddressesFor(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	single, extra, listed := cltest.NewAddress(), cltest.NewAddress(), cltest.NewAddress()
+	list, err := models.NewAddressList("feeds", []common.Address{listed})
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&list))
+	empty, err := models.NewAddressList("empty", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&empty))
+
+	addresses, err := store.LogAddressesFor(models.Initiator{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(addresses))
+
+	addresses, err = store.LogAddressesFor(models.Initiator{Address: single, Addresses: []common.Address{extra}, AddressList: "feeds"})
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Address{single, extra, listed}, addresses)
+
+	_, err = store.LogAddressesFor(models.Initiator{AddressList: "empty"})
+	assert.NotNil(t, err)
+	_, err = store.LogAddressesFor(models.Initiator{AddressList: "unknown"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// The privileged actions recorded in the audit log.
+const (
+	AuditJobCreated               = "job_created"
+	AuditJobDeleted               = "job_deleted"
+	AuditJobUnarchived            = "job_unarchived"
+	AuditJobPaused                = "job_paused"
+	AuditJobResumed               = "job_resumed"
+	AuditJobsImported             = "jobs_imported"
+	AuditRunCreated               = "run_created"
+	AuditBridgeTypeCreated        = "bridge_type_created"
+	AuditConfigChanged            = "config_changed"
+	AuditAPITokenCreated          = "api_token_created"
+	AuditAPITokenDeleted          = "api_token_deleted"
+	AuditUserCreated              = "user_created"
+	AuditUserUpdated              = "user_updated"
+	AuditUserDeleted              = "user_deleted"
+	AuditRunsPruned               = "runs_pruned"
+	AuditTxBroadcast              = "tx_broadcast"
+	AuditFeedCreated              = "feed_created"
+	AuditFeedDeleted              = "feed_deleted"
+	AuditTOTPEnabled              = "totp_enabled"
+	AuditTOTPDisabled             = "totp_disabled"
+	AuditManifestUpdated          = "manifest_updated"
+	AuditManifestDeleted          = "manifest_deleted"
+	AuditExternalInitiatorCreated = "external_initiator_created"
+	AuditExternalInitiatorDeleted = "external_initiator_deleted"
+	AuditAddressListUpdated       = "address_list_updated"
+	AuditAddressListDeleted       = "address_list_deleted"
+	AuditCredentialCreated        = "credential_created"
+	AuditCredentialRotated        = "credential_rotated"
+	AuditCredentialDeleted        = "credential_deleted"
+)
+
+// AuditEntry records a privileged action taken through the API: what it
+// was, who took it and when, the response status, and a SHA-256 hash of
+// the request body, so that a request can be matched against the entry
+// without the log holding secrets such as passwords. Entries are only
+// ever added.
+type AuditEntry struct {
+	ID          uint64    `json:"id" storm:"id,increment"`
+	Action      string    `json:"action" storm:"index"`
+	Actor       string    `json:"actor"`
+	Path        string    `json:"path"`
+	Status      int       `json:"status"`
+	PayloadHash string    `json:"payloadHash"`
+	CreatedAt   time.Time `json:"createdAt" storm:"index"`
+}
@@ -3,7 +3,9 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/araddon/dateparse"
@@ -107,6 +109,40 @@ func (w *WebURL) MarshalJSON() ([]byte, error) {
 	return json.Marshal(w.String())
 }
 
+// Link is an amount of LINK in juels, its smallest unit. It is written in
+// JSON as a decimal string, so that no precision is lost, and read from
+// either a string or a number.
+type Link big.Int
+
+// NewLink returns the amount of juels as Link.
+func NewLink(juels int64) *Link {
+	return (*Link)(big.NewInt(juels))
+}
+
+// ToInt returns the amount of juels.
+func (l *Link) ToInt() *big.Int {
+	return (*big.Int)(l)
+}
+
+// String returns the amount of juels in decimal.
+func (l *Link) String() string {
+	return l.ToInt().String()
+}
+
+// MarshalJSON returns the amount as a JSON string.
+func (l Link) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON reads the amount from a JSON string or number.
+func (l *Link) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if _, ok := l.ToInt().SetString(s, 10); !ok {
+		return fmt.Errorf("Link: unable to parse %v", string(b))
+	}
+	return nil
+}
+
 // Time holds a common field for time.
 type Time struct {
 	time.Time
@@ -138,9 +174,38 @@ func (t *Time) HumanString() string {
 	return utils.ISO8601UTC(t.Time)
 }
 
+// Duration is a time.Duration written to and read from JSON as a string,
+// such as "30s".
+type Duration time.Duration
+
+// MarshalJSON returns the duration as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON parses a JSON string, such as "1m30s", into the duration.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("Duration: %v", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("Duration: %v", err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
 // Cron holds the string that will represent the spec of the cron-job.
 // It uses 6 fields to represent the seconds (1), minutes (2), hours (3),
-// day of the month (4), month (5), and day of the week (6).
+// day of the month (4), month (5), and day of the week (6), which can be
+// left off to run on any day of the week. It can start with
+// CRON_TZ=<zone>, such as "CRON_TZ=America/New_York 0 30 9 * * 1-5", to
+// run in that time zone, including across its daylight saving changes,
+// rather than the node's. Specs with a zone can also be 5 fields without
+// the seconds, as standard cron reads them, such as
+// "CRON_TZ=America/New_York 30 9 * * 1-5".
 type Cron string
 
 // UnmarshalJSON parses the raw spec stored in JSON-encoded
@@ -155,7 +220,7 @@ func (c *Cron) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
-	_, err = cron.Parse(s)
+	_, err = Cron(s).Schedule()
 	if err != nil {
 		return fmt.Errorf("Cron: %v", err)
 	}
@@ -163,6 +228,48 @@ func (c *Cron) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// cronTZPrefix starts a spec that is in a time zone other than the node's.
+const cronTZPrefix = "CRON_TZ="
+
+// Schedule parses the spec into a schedule whose next run times are in
+// its time zone. Five fields are read as standard cron only after a
+// CRON_TZ prefix, so that specs saved without one keep their schedules.
+func (c Cron) Schedule() (cron.Schedule, error) {
+	spec := strings.TrimSpace(string(c))
+	loc := time.Local
+	zoned := strings.HasPrefix(spec, cronTZPrefix)
+	if zoned {
+		i := strings.IndexAny(spec, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("missing fields after %v", spec)
+		}
+		var err error
+		if loc, err = time.LoadLocation(spec[len(cronTZPrefix):i]); err != nil {
+			return nil, fmt.Errorf("unknown time zone %v", spec[len(cronTZPrefix):i])
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+	if zoned && !strings.HasPrefix(spec, "@") && len(strings.Fields(spec)) == 5 {
+		spec = "0 " + spec
+	}
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return zonedSchedule{schedule, loc}, nil
+}
+
+// zonedSchedule computes the next run times of its schedule in its time
+// zone, whatever the zone of the time it is given.
+type zonedSchedule struct {
+	cron.Schedule
+	loc *time.Location
+}
+
+func (zs zonedSchedule) Next(t time.Time) time.Time {
+	return zs.Schedule.Next(t.In(zs.loc))
+}
+
 // String returns the current Cron spec string.
 func (c Cron) String() string {
 	return string(c)
@@ -126,3 +126,86 @@ func TestTimeDurationFromNow(t *testing.T) {
 	duration := future.DurationFromNow()
 	assert.True(t, 0 < duration)
 }
+
+func TestCron_Schedule(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.Nil(t, err)
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"minutes first", "CRON_TZ=UTC 0 * * * *",
+			time.Date(2018, 3, 9, 10, 15, 30, 0, time.UTC),
+			time.Date(2018, 3, 9, 11, 0, 0, 0, time.UTC)},
+		{"seconds first", "CRON_TZ=UTC 30 * * * * *",
+			time.Date(2018, 3, 9, 10, 15, 0, 0, time.UTC),
+			time.Date(2018, 3, 9, 10, 15, 30, 0, time.UTC)},
+		{"seconds first without a time zone", "30 * * * *",
+			time.Date(2018, 3, 9, 10, 15, 0, 0, time.UTC),
+			time.Date(2018, 3, 9, 10, 15, 30, 0, time.UTC)},
+		{"descriptor", "CRON_TZ=UTC @daily",
+			time.Date(2018, 3, 9, 10, 15, 0, 0, time.UTC),
+			time.Date(2018, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"time zone", "CRON_TZ=America/New_York 30 9 * * 1-5",
+			time.Date(2018, 3, 8, 15, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 9, 14, 30, 0, 0, time.UTC)},
+		{"daylight saving", "CRON_TZ=America/New_York 30 9 * * 1-5",
+			time.Date(2018, 3, 9, 15, 0, 0, 0, time.UTC),
+			time.Date(2018, 3, 12, 13, 30, 0, 0, time.UTC)},
+		{"from another zone", "CRON_TZ=America/New_York 30 9 * * *",
+			time.Date(2018, 3, 9, 23, 0, 0, 0, tokyo),
+			time.Date(2018, 3, 9, 14, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := models.Cron(test.spec).Schedule()
+			assert.Nil(t, err)
+			assert.True(t, test.want.Equal(schedule.Next(test.from)), schedule.Next(test.from).UTC().String())
+		})
+	}
+}
+
+func TestCron_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		json      string
+		wantError bool
+	}{
+		{"five fields", `"30 9 * * 1-5"`, false},
+		{"seconds", `"0 30 9 * * 1-5"`, false},
+		{"time zone", `"CRON_TZ=Europe/London 0 8 * * *"`, false},
+		{"unknown time zone", `"CRON_TZ=Mars/Olympus 0 8 * * *"`, true},
+		{"time zone only", `"CRON_TZ=UTC"`, true},
+		{"invalid field", `"! * * * *"`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var c models.Cron
+			err := json.Unmarshal([]byte(test.json), &c)
+			assert.Equal(t, test.wantError, err != nil)
+		})
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	t.Parallel()
+
+	var d models.Duration
+	assert.Nil(t, json.Unmarshal([]byte(`"1m30s"`), &d))
+	assert.Equal(t, 90*time.Second, time.Duration(d))
+
+	b, err := json.Marshal(d)
+	assert.Nil(t, err)
+	assert.Equal(t, `"1m30s"`, string(b))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"soon"`), &d))
+	assert.NotNil(t, json.Unmarshal([]byte(`90`), &d))
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// ConditionEq is met when the value equals the operand.
+	ConditionEq = "eq"
+	// ConditionNe is met when the value does not equal the operand.
+	ConditionNe = "ne"
+	// ConditionGt is met when the value is numerically greater than the operand.
+	ConditionGt = "gt"
+	// ConditionGte is met when the value is numerically greater than or equal
+	// to the operand.
+	ConditionGte = "gte"
+	// ConditionLt is met when the value is numerically less than the operand.
+	ConditionLt = "lt"
+	// ConditionLte is met when the value is numerically less than or equal
+	// to the operand.
+	ConditionLte = "lte"
+	// ConditionDeviates is met when the value differs from the last reported
+	// value by more than the operand, as a percentage of the last value.
+	ConditionDeviates = "deviates"
+)
+
+var conditionOperators = map[string]bool{
+	ConditionEq:       true,
+	ConditionNe:       true,
+	ConditionGt:       true,
+	ConditionGte:      true,
+	ConditionLt:       true,
+	ConditionLte:      true,
+	ConditionDeviates: true,
+}
+
+// Condition decides whether a task is performed, by checking the data
+// passed in from the previous task. The Path selects the field to check,
+// defaulting to "value".
+//
+// For example, the following only performs a task if the value has moved
+// more than 0.5% since the task was last performed:
+//   {"onlyIf": {"operator": "deviates", "value": "0.5"}}
+type Condition struct {
+	Path     string `json:"path"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+func parseCondition(input []byte) (*Condition, error) {
+	var aux struct {
+		OnlyIf *Condition `json:"onlyIf"`
+	}
+	if err := json.Unmarshal(input, &aux); err != nil {
+		return nil, err
+	}
+
+	c := aux.OnlyIf
+	if c == nil {
+		return nil, nil
+	}
+	if c.Path == "" {
+		c.Path = "value"
+	}
+	if !conditionOperators[c.Operator] {
+		return nil, fmt.Errorf("onlyIf: unknown operator %v", c.Operator)
+	}
+	if c.numeric() {
+		if _, ok := new(big.Float).SetString(c.Value); !ok {
+			return nil, fmt.Errorf("onlyIf: %v is not a number", c.Value)
+		}
+	}
+	return c, nil
+}
+
+func (c Condition) numeric() bool {
+	return c.Operator != ConditionEq && c.Operator != ConditionNe
+}
+
+// Met returns true if the given data satisfies the condition. The last
+// data is only used by ConditionDeviates, and when it does not contain
+// the path the condition is met, so that a task is performed the first
+// time around.
+func (c Condition) Met(data JSON, last JSON) (bool, error) {
+	current := data.Get(c.Path).String()
+	switch c.Operator {
+	case ConditionEq:
+		return current == c.Value, nil
+	case ConditionNe:
+		return current != c.Value, nil
+	case ConditionDeviates:
+		prev := last.Get(c.Path)
+		if !prev.Exists() {
+			return true, nil
+		}
+		return deviates(current, prev.String(), c.Value)
+	}
+
+	x, ok := new(big.Float).SetString(current)
+	if !ok {
+		return false, fmt.Errorf("onlyIf: %v at %v is not a number", current, c.Path)
+	}
+	y, _ := new(big.Float).SetString(c.Value)
+	cmp := x.Cmp(y)
+	switch c.Operator {
+	case ConditionGt:
+		return cmp > 0, nil
+	case ConditionGte:
+		return cmp >= 0, nil
+	case ConditionLt:
+		return cmp < 0, nil
+	case ConditionLte:
+		return cmp <= 0, nil
+	}
+	return false, fmt.Errorf("onlyIf: unknown operator %v", c.Operator)
+}
+
+func deviates(current, last, threshold string) (bool, error) {
+	cur, ok := new(big.Float).SetString(current)
+	if !ok {
+		return false, fmt.Errorf("onlyIf: %v is not a number", current)
+	}
+	prev, ok := new(big.Float).SetString(last)
+	if !ok {
+		return false, fmt.Errorf("onlyIf: last value %v is not a number", last)
+	}
+	if prev.Sign() == 0 {
+		return cur.Sign() != 0, nil
+	}
+
+	diff := new(big.Float).Sub(cur, prev)
+	diff.Abs(diff)
+	percent := diff.Quo(diff, new(big.Float).Abs(prev))
+	percent.Mul(percent, big.NewFloat(100))
+	limit, _ := new(big.Float).SetString(threshold)
+	return percent.Cmp(limit) > 0, nil
+}
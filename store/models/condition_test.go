@@ -0,0 +1,82 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_UnmarshalJSON_OnlyIf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		wantErrored bool
+		wantPath    string
+	}{
+		{"no condition", `{"type":"noop"}`, false, ""},
+		{"default path", `{"type":"noop","onlyIf":{"operator":"gt","value":"10"}}`, false, "value"},
+		{"custom path", `{"type":"noop","onlyIf":{"path":"price.last","operator":"eq","value":"up"}}`, false, "price.last"},
+		{"unknown operator", `{"type":"noop","onlyIf":{"operator":"between","value":"10"}}`, true, ""},
+		{"non numeric operand", `{"type":"noop","onlyIf":{"operator":"gt","value":"ten"}}`, true, ""},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			var task models.Task
+			err := json.Unmarshal([]byte(test.input), &task)
+			assert.Equal(t, test.wantErrored, err != nil)
+			if test.wantErrored {
+				return
+			}
+			if test.wantPath == "" {
+				assert.Nil(t, task.OnlyIf)
+			} else {
+				assert.Equal(t, test.wantPath, task.OnlyIf.Path)
+			}
+		})
+	}
+}
+
+func TestCondition_Met(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		operator    string
+		operand     string
+		data        string
+		last        string
+		want        bool
+		wantErrored bool
+	}{
+		{"eq", models.ConditionEq, "up", `{"value":"up"}`, `{}`, true, false},
+		{"eq not met", models.ConditionEq, "up", `{"value":"down"}`, `{}`, false, false},
+		{"ne", models.ConditionNe, "up", `{"value":"down"}`, `{}`, true, false},
+		{"gt", models.ConditionGt, "10", `{"value":"10.5"}`, `{}`, true, false},
+		{"gt equal", models.ConditionGt, "10", `{"value":"10"}`, `{}`, false, false},
+		{"gte equal", models.ConditionGte, "10", `{"value":"10"}`, `{}`, true, false},
+		{"lt", models.ConditionLt, "10", `{"value":9}`, `{}`, true, false},
+		{"lte", models.ConditionLte, "10", `{"value":"11"}`, `{}`, false, false},
+		{"gt not a number", models.ConditionGt, "10", `{"value":"ten"}`, `{}`, false, true},
+		{"deviates without last", models.ConditionDeviates, "1", `{"value":"100"}`, `{}`, true, false},
+		{"deviates within threshold", models.ConditionDeviates, "1", `{"value":"100.5"}`, `{"value":"100"}`, false, false},
+		{"deviates beyond threshold", models.ConditionDeviates, "1", `{"value":"98"}`, `{"value":"100"}`, true, false},
+		{"deviates from zero", models.ConditionDeviates, "1", `{"value":"1"}`, `{"value":"0"}`, true, false},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			c := models.Condition{Path: "value", Operator: test.operator, Value: test.operand}
+			met, err := c.Met(cltest.JSONFromString(test.data), cltest.JSONFromString(test.last))
+			assert.Equal(t, test.wantErrored, err != nil)
+			assert.Equal(t, test.want, met)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ConfigChange records a runtime setting being changed: who changed it,
+// when, and its values before and after the change.
+type ConfigChange struct {
+	ID        uint64    `json:"id" storm:"id,increment"`
+	Setting   string    `json:"setting"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedBy string    `json:"changedBy"`
+	CreatedAt time.Time `json:"createdAt" storm:"index"`
+}
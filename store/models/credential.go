@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var credentialNamePattern = regexp.MustCompile(`^[a-z0-9_\-.]+$`)
+
+// Credential is a named secret, such as an API key, that tasks refer to by
+// its name rather than holding in the job spec. Only its value encrypted
+// with the node's credentials key is stored, and it is never sent back
+// out through the API, where credentials are shown through
+// presenters.Credential.
+type Credential struct {
+	Name           string `json:"name" storm:"id,unique"`
+	EncryptedValue []byte `json:"encryptedValue"`
+	CreatedAt      Time   `json:"createdAt"`
+	UpdatedAt      Time   `json:"updatedAt"`
+}
+
+// NormalizeCredentialName returns the name credentials are saved and
+// looked up under, or an error if it has characters other than letters,
+// digits, '_', '-', and '.'.
+func NormalizeCredentialName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !credentialNamePattern.MatchString(name) {
+		return "", errors.New("Credential name must be letters, digits, '_', '-', or '.'")
+	}
+	return name, nil
+}
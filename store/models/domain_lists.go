@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DomainLists limit the hosts that HTTP tasks can send requests to. Hosts
+// in DeniedDomains, or under one of them, are refused, and when
+// AllowedDomains is not empty, only hosts in it or under one of them are
+// let through. A domain is written as a host name, such as example.com,
+// which also covers api.example.com, or as an IP address.
+type DomainLists struct {
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+	DeniedDomains  []string `json:"deniedDomains,omitempty"`
+}
+
+// NewDomainLists returns the lists of comma separated domains, such as
+// ADAPTER_ALLOWED_DOMAINS.
+func NewDomainLists(allowed, denied string) DomainLists {
+	return DomainLists{AllowedDomains: splitDomains(allowed), DeniedDomains: splitDomains(denied)}
+}
+
+func splitDomains(s string) []string {
+	domains := []string{}
+	for _, domain := range strings.Split(s, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// Permits returns true if requests to the host are let through.
+func (dl DomainLists) Permits(host string) bool {
+	if matchesDomain(host, dl.DeniedDomains) {
+		return false
+	}
+	return len(dl.AllowedDomains) == 0 || matchesDomain(host, dl.AllowedDomains)
+}
+
+// Validate returns an error if a domain is not a bare host name or IP
+// address, such as a URL.
+func (dl DomainLists) Validate() error {
+	for _, domain := range append(append([]string{}, dl.AllowedDomains...), dl.DeniedDomains...) {
+		if net.ParseIP(domain) != nil {
+			continue
+		}
+		if domain == "" || strings.ContainsAny(domain, "/:@ ") {
+			return fmt.Errorf("Domain %q must be a host name, such as example.com", domain)
+		}
+	}
+	return nil
+}
+
+func matchesDomain(host string, domains []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(domain), "*.")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
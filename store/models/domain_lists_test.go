@@ -0,0 +1,44 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainLists_Permits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		lists models.DomainLists
+		host  string
+		want  bool
+	}{
+		{"no lists", models.DomainLists{}, "example.com", true},
+		{"allowed", models.NewDomainLists("example.com", ""), "example.com", true},
+		{"allowed subdomain", models.NewDomainLists("example.com", ""), "API.example.com.", true},
+		{"allowed wildcard", models.NewDomainLists("*.example.com", ""), "api.example.com", true},
+		{"not allowed", models.NewDomainLists("example.com", ""), "badexample.com", false},
+		{"denied", models.NewDomainLists("", " example.com, 10.0.0.1"), "example.com", false},
+		{"denied ip", models.NewDomainLists("", "example.com,10.0.0.1"), "10.0.0.1", false},
+		{"denied wins", models.NewDomainLists("example.com", "internal.example.com"), "db.internal.example.com", false},
+		{"not denied", models.NewDomainLists("", "example.com"), "example.org", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.lists.Permits(test.host))
+		})
+	}
+}
+
+func TestDomainLists_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, models.NewDomainLists("example.com,*.example.org", "10.0.0.1,::1").Validate())
+	assert.NotNil(t, models.DomainLists{AllowedDomains: []string{"https://example.com"}}.Validate())
+	assert.NotNil(t, models.DomainLists{DeniedDomains: []string{"example.com:8080"}}.Validate())
+	assert.NotNil(t, models.DomainLists{DeniedDomains: []string{""}}.Validate())
+}
@@ -11,21 +11,28 @@ import (
 )
 
 // Tx contains fields necessary for an Ethereum transaction with
-// an additional field for the TxAttempt.
+// an additional field for the TxAttempt. JobRunID is set on transactions
+// sent by a run's EthTx task. Transactions that deploy a contract, with
+// its code as their Data, have ContractCreation set and no To.
 type Tx struct {
-	ID       uint64 `storm:"id,increment,index"`
-	From     common.Address
-	To       common.Address
-	Data     []byte
-	Nonce    uint64
-	Value    *big.Int
-	GasLimit uint64
+	ID               uint64 `storm:"id,increment,index"`
+	JobRunID         string `storm:"index"`
+	From             common.Address
+	To               common.Address
+	Data             []byte
+	Nonce            uint64
+	Value            *big.Int
+	GasLimit         uint64
+	ContractCreation bool
 	TxAttempt
 }
 
 // EthTx creates a new Ethereum transaction with a given gasPrice
 // that is ready to be signed.
 func (tx *Tx) EthTx(gasPrice *big.Int) *types.Transaction {
+	if tx.ContractCreation {
+		return types.NewContractCreation(tx.Nonce, tx.Value, tx.GasLimit, gasPrice, tx.Data)
+	}
 	return types.NewTransaction(
 		tx.Nonce,
 		tx.To,
@@ -36,6 +43,28 @@ func (tx *Tx) EthTx(gasPrice *big.Int) *types.Transaction {
 	)
 }
 
+// UnsignedTx is a transaction built by a node for its operator to sign
+// offline, on a machine holding the key, and then broadcast with the node.
+type UnsignedTx struct {
+	From     common.Address `json:"from"`
+	To       common.Address `json:"to"`
+	Nonce    uint64         `json:"nonce"`
+	Value    *big.Int       `json:"value"`
+	GasLimit uint64         `json:"gasLimit"`
+	GasPrice *big.Int       `json:"gasPrice"`
+	Data     hexutil.Bytes  `json:"data"`
+	ChainID  uint64         `json:"chainId"`
+}
+
+// EthTx returns the Ethereum transaction to be signed.
+func (tx UnsignedTx) EthTx() *types.Transaction {
+	value := tx.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return types.NewTransaction(tx.Nonce, tx.To, value, tx.GasLimit, tx.GasPrice, tx.Data)
+}
+
 // TxAttempt is used for keeping track of transactions that
 // have been written to the Ethereum blockchain. This makes
 // it so that if the network is busy, a transaction can be
@@ -49,6 +78,20 @@ type TxAttempt struct {
 	SentAt    uint64
 }
 
+// BatchedCall is a contract call waiting to be sent together with other
+// calls to the same contract, in a single transaction to the contract's
+// batch method. Once sent, TxHash is the hash of that transaction.
+// JobRunID is the run whose EthTx task queued the call.
+type BatchedCall struct {
+	ID            uint64 `storm:"id,increment,index"`
+	JobRunID      string `storm:"index"`
+	To            common.Address
+	BatchSelector FunctionSelector
+	Data          []byte
+	Sent          bool `storm:"index"`
+	TxHash        common.Hash
+}
+
 // FunctionSelector is the first four bytes of the call data for a
 // function call and specifies the function to be called.
 type FunctionSelector [FunctionSelectorLength]byte
@@ -77,6 +120,12 @@ func (f FunctionSelector) WithoutPrefix() string { return f.String()[2:] }
 // SetBytes sets the FunctionSelector to that of the given bytes (will trim).
 func (f *FunctionSelector) SetBytes(b []byte) { copy(f[:], b[:FunctionSelectorLength]) }
 
+// MarshalJSON writes the FunctionSelector as a hex string, so that it can be
+// read back by UnmarshalJSON.
+func (f FunctionSelector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
 // UnmarshalJSON parses the raw FunctionSelector and sets the FunctionSelector
 // type to the given input.
 func (f *FunctionSelector) UnmarshalJSON(input []byte) error {
@@ -108,3 +157,50 @@ func (bh *BlockHeader) ToInt() *big.Int {
 	}
 	return bh.Number.ToInt()
 }
+
+// The kinds of TxError, which are exposed as the error code of a failed
+// run's result.
+const (
+	// TxErrorInsufficientFunds means the node's account cannot pay for the
+	// transaction.
+	TxErrorInsufficientFunds = "insufficient_funds"
+	// TxErrorNonceTooLow means the nonce was already used, such as by
+	// another transaction sent at the same time.
+	TxErrorNonceTooLow = "nonce_too_low"
+	// TxErrorUnderpriced means the gas price was too low for the ethereum
+	// node to accept the transaction.
+	TxErrorUnderpriced = "underpriced"
+	// TxErrorReverted means the transaction was confirmed but failed.
+	TxErrorReverted = "reverted"
+	// TxErrorConnection means the ethereum node could not be reached.
+	TxErrorConnection = "connection"
+)
+
+// TxError is a failure to send or confirm a transaction, classified by
+// Code so that runs can tell failures worth retrying from terminal ones.
+type TxError struct {
+	Code string
+	Err  error
+}
+
+// Error returns the message of the underlying error.
+func (e *TxError) Error() string {
+	return e.Err.Error()
+}
+
+// Retryable returns true if sending the transaction again may succeed
+// without the operator stepping in.
+func (e *TxError) Retryable() bool {
+	return TxErrorRetryable(e.Code)
+}
+
+// TxErrorRetryable returns true if the TxError code is for a failure that
+// may not happen again, such as a dropped connection. Insufficient funds
+// and reverted transactions need the operator or the job to change first.
+func TxErrorRetryable(code string) bool {
+	switch code {
+	case TxErrorNonceTooLow, TxErrorUnderpriced, TxErrorConnection:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// The types of Event published as runs progress, as runs are missed, and
+// as the node's state drifts from its manifest.
+const (
+	// EventRunCreated is published when a run is built for a job.
+	EventRunCreated = "run_created"
+	// EventTaskCompleted is published when a task run finishes without
+	// an error.
+	EventTaskCompleted = "task_completed"
+	// EventRunCompleted is published when every task in a run has finished.
+	EventRunCompleted = "run_completed"
+	// EventRunErrored is published when a run errors.
+	EventRunErrored = "run_errored"
+	// EventTxConfirmed is published when a transaction has enough
+	// confirmations to be considered safe.
+	EventTxConfirmed = "tx_confirmed"
+	// EventDriftDetected is published when the node's state differs from
+	// its manifest in a way it did not at the last check.
+	EventDriftDetected = "drift_detected"
+	// EventDriftResolved is published when the node's state matches its
+	// manifest again.
+	EventDriftResolved = "drift_resolved"
+	// EventRunAtMissed is published when a runat initiator's time passed
+	// while the node was down, longer ago than RUN_AT_GRACE_PERIOD, so it
+	// is not run.
+	EventRunAtMissed = "runat_missed"
+)
+
+// Event is a notification of progress on a job run, sent to clients that
+// want to show it as it happens.
+type Event struct {
+	Type      string      `json:"type"`
+	JobID     string      `json:"jobId,omitempty"`
+	RunID     string      `json:"runId,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// abiSizedType matches the integer and fixed bytes types, capturing their
+// size.
+var abiSizedType = regexp.MustCompile(`^(uint|int|bytes)([0-9]+)$`)
+
+// EventABI is the ABI of a contract event, as found in the contract's ABI
+// JSON, with the event's name and its inputs. Only events whose inputs are
+// value types, string, or bytes are supported.
+type EventABI struct {
+	Name   string       `json:"name"`
+	Inputs []EventInput `json:"inputs"`
+}
+
+// EventInput is a parameter of an event, and whether it is indexed, in
+// which case it is one of the log's topics rather than in its data.
+type EventInput struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+}
+
+// Signature returns the event's signature, such as
+// "Transfer(address,address,uint256)".
+func (e EventABI) Signature() string {
+	types := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		types[i] = canonicalEventType(input.Type)
+	}
+	return e.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// Topic returns the hash of the event's signature, which is the first
+// topic of each of its logs.
+func (e EventABI) Topic() common.Hash {
+	return crypto.Keccak256Hash([]byte(e.Signature()))
+}
+
+// Validate returns an error if the event's inputs cannot be decoded.
+func (e EventABI) Validate() error {
+	if e.Name == "" {
+		return errors.New("event must have a name")
+	}
+	names := map[string]bool{}
+	indexed := 0
+	for i, input := range e.Inputs {
+		if input.Name == "" {
+			return fmt.Errorf("event input %d must have a name", i)
+		}
+		if names[input.Name] {
+			return fmt.Errorf("event input %v is named more than once", input.Name)
+		}
+		names[input.Name] = true
+		if !validEventType(canonicalEventType(input.Type)) {
+			return fmt.Errorf("event input %v has unsupported type %v", input.Name, input.Type)
+		}
+		if input.Indexed {
+			indexed++
+		}
+	}
+	if indexed > 3 {
+		return fmt.Errorf("event has %d indexed inputs, but at most 3 can be", indexed)
+	}
+	return nil
+}
+
+// Decode returns the log's parameters keyed by their names. Integers are
+// decimal strings, so that they do not lose precision, and addresses and
+// bytes are hex. Indexed strings and bytes are only logged as their hash,
+// so that is what they are decoded as.
+func (e EventABI) Decode(log types.Log) (map[string]interface{}, error) {
+	if len(log.Topics) == 0 || log.Topics[0] != e.Topic() {
+		return nil, fmt.Errorf("Log is not a %v event", e.Signature())
+	}
+	fields := map[string]interface{}{}
+	data := ABIResult(log.Data)
+	topic, word := 1, 0
+	for _, input := range e.Inputs {
+		typ := canonicalEventType(input.Type)
+		if input.Indexed {
+			if topic >= len(log.Topics) {
+				return nil, fmt.Errorf("Log is missing the topic for %v", input.Name)
+			}
+			value := log.Topics[topic]
+			topic++
+			if typ == "string" || typ == "bytes" {
+				fields[input.Name] = value.Hex()
+				continue
+			}
+			decoded, err := decodeEventWord(typ, ABIResult(value.Bytes()), 0)
+			if err != nil {
+				return nil, fmt.Errorf("Decoding %v: %v", input.Name, err)
+			}
+			fields[input.Name] = decoded
+			continue
+		}
+		decoded, err := decodeEventWord(typ, data, word)
+		if err != nil {
+			return nil, fmt.Errorf("Decoding %v: %v", input.Name, err)
+		}
+		word++
+		fields[input.Name] = decoded
+	}
+	return fields, nil
+}
+
+func decodeEventWord(typ string, r ABIResult, i int) (interface{}, error) {
+	switch typ {
+	case "address":
+		a, err := r.Address(i)
+		return a.Hex(), err
+	case "bool":
+		return r.Bool(i)
+	case "string":
+		b, err := r.DynamicBytes(i)
+		return string(b), err
+	case "bytes":
+		b, err := r.DynamicBytes(i)
+		return hexutil.Encode(b), err
+	}
+	match := abiSizedType.FindStringSubmatch(typ)
+	switch match[1] {
+	case "bytes":
+		size, _ := strconv.Atoi(match[2])
+		h, err := r.Bytes32(i)
+		return hexutil.Encode(h[:size]), err
+	case "int":
+		n, err := r.Int256(i)
+		if err != nil {
+			return nil, err
+		}
+		return n.String(), nil
+	}
+	n, err := r.Uint256(i)
+	if err != nil {
+		return nil, err
+	}
+	return n.String(), nil
+}
+
+// canonicalEventType returns the type as it appears in signatures, such
+// as uint256 for uint.
+func canonicalEventType(typ string) string {
+	switch typ {
+	case "uint", "int":
+		return typ + "256"
+	}
+	return typ
+}
+
+func validEventType(typ string) bool {
+	switch typ {
+	case "address", "bool", "string", "bytes":
+		return true
+	}
+	match := abiSizedType.FindStringSubmatch(typ)
+	if match == nil {
+		return false
+	}
+	size, _ := strconv.Atoi(match[2])
+	if match[1] == "bytes" {
+		return size >= 1 && size <= 32
+	}
+	return size >= 8 && size <= 256 && size%8 == 0
+}
@@ -0,0 +1,98 @@
+package models_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventABI_Topic(t *testing.T) {
+	t.Parallel()
+
+	event := models.EventABI{Name: "Transfer", Inputs: []models.EventInput{
+		{Name: "from", Type: "address", Indexed: true},
+		{Name: "to", Type: "address", Indexed: true},
+		{Name: "value", Type: "uint"},
+	}}
+	assert.Equal(t, "Transfer(address,address,uint256)", event.Signature())
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", event.Topic().Hex())
+}
+
+func TestEventABI_Decode(t *testing.T) {
+	t.Parallel()
+
+	var event models.EventABI
+	assert.Nil(t, json.Unmarshal([]byte(`{"name": "Reported", "inputs": [
+		{"name": "sender", "type": "address", "indexed": true},
+		{"name": "answer", "type": "int256"},
+		{"name": "symbol", "type": "string"},
+		{"name": "id", "type": "bytes4", "indexed": true},
+		{"name": "final", "type": "bool"}
+	]}`), &event))
+	assert.Nil(t, event.Validate())
+
+	sender := common.HexToAddress("0x9CA9d2D5E04012C9Ed24C0e513C9bfAa4A2dD77f")
+	// answer, the offset of symbol, final, then symbol's length and bytes
+	call, err := models.EncodeCall("f(int256,uint64,bool,uint64)", big.NewInt(-5), uint64(96), true, uint64(7))
+	assert.Nil(t, err)
+	data := call[4:]
+	data = append(data, common.RightPadBytes([]byte("ETH/USD"), 32)...)
+
+	log := types.Log{
+		Topics: []common.Hash{
+			event.Topic(),
+			common.BytesToHash(sender.Bytes()),
+			common.BytesToHash(common.RightPadBytes([]byte{0xde, 0xad, 0xbe, 0xef}, 32)),
+		},
+		Data: data,
+	}
+
+	fields, err := event.Decode(log)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"sender": sender.Hex(),
+		"answer": "-5",
+		"symbol": "ETH/USD",
+		"id":     "0xdeadbeef",
+		"final":  true,
+	}, fields)
+
+	log.Topics[0] = common.Hash{}
+	_, err = event.Decode(log)
+	assert.NotNil(t, err)
+}
+
+func TestEventABI_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		event models.EventABI
+		valid bool
+	}{
+		{"no inputs", models.EventABI{Name: "Ping"}, true},
+		{"no name", models.EventABI{}, false},
+		{"unnamed input", models.EventABI{Name: "A", Inputs: []models.EventInput{{Type: "uint256"}}}, false},
+		{"duplicate input", models.EventABI{Name: "A", Inputs: []models.EventInput{{Name: "a", Type: "bool"}, {Name: "a", Type: "bool"}}}, false},
+		{"array", models.EventABI{Name: "A", Inputs: []models.EventInput{{Name: "a", Type: "uint256[]"}}}, false},
+		{"bytes33", models.EventABI{Name: "A", Inputs: []models.EventInput{{Name: "a", Type: "bytes33"}}}, false},
+		{"too many indexed", models.EventABI{Name: "A", Inputs: []models.EventInput{
+			{Name: "a", Type: "bool", Indexed: true},
+			{Name: "b", Type: "bool", Indexed: true},
+			{Name: "c", Type: "bool", Indexed: true},
+			{Name: "d", Type: "bool", Indexed: true},
+		}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.event.Validate()
+			assert.Equal(t, test.valid, err == nil, "%v", err)
+		})
+	}
+}
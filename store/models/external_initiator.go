@@ -0,0 +1,59 @@
+package models
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/utils"
+)
+
+// ExternalInitiator is a service outside the node, such as an exchange feed
+// or an IoT gateway, that starts runs of the jobs with an "external"
+// initiator naming it. It authenticates with its AccessKey and secret, of
+// which only a hash is stored. If it has a URL, the node tells it there when
+// those jobs are created or archived, sending OutgoingToken so that it can
+// tell the notices come from the node. Both are stored with it, so external
+// initiators are shown through presenters.ExternalInitiator.
+type ExternalInitiator struct {
+	ID            string `json:"id" storm:"id,unique"`
+	Name          string `json:"name" storm:"index,unique"`
+	URL           string `json:"url,omitempty"`
+	AccessKey     string `json:"accessKey" storm:"index,unique"`
+	HashedSecret  string `json:"hashedSecret"`
+	OutgoingToken string `json:"outgoingToken"`
+	CreatedAt     Time   `json:"createdAt"`
+}
+
+// NewExternalInitiator returns an external initiator with the given name and
+// notification URL, along with the secret to be handed to it with its
+// access key.
+func NewExternalInitiator(name, rawURL string) (ExternalInitiator, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ExternalInitiator{}, "", errors.New("External initiator must have a name")
+	}
+	if rawURL != "" {
+		if u, err := url.ParseRequestURI(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return ExternalInitiator{}, "", errors.New("External initiator URL must be an http or https URL")
+		}
+	}
+	secret := utils.NewBytes32ID() + utils.NewBytes32ID()
+	return ExternalInitiator{
+		ID:            utils.NewBytes32ID(),
+		Name:          name,
+		URL:           rawURL,
+		AccessKey:     utils.NewBytes32ID(),
+		HashedSecret:  HashAPIToken(secret),
+		OutgoingToken: utils.NewBytes32ID() + utils.NewBytes32ID(),
+		CreatedAt:     Time{Time: time.Now()},
+	}, secret, nil
+}
+
+// CheckSecret returns true if the secret is the one the external initiator
+// was given.
+func (ei ExternalInitiator) CheckSecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIToken(secret)), []byte(ei.HashedSecret)) == 1
+}
@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExternalInitiator(t *testing.T) {
+	t.Parallel()
+
+	ei, secret, err := models.NewExternalInitiator(" Exchange-Feed ", "https://ei.example.com/jobs")
+	assert.Nil(t, err)
+	assert.Equal(t, "exchange-feed", ei.Name)
+	assert.NotEmpty(t, ei.AccessKey)
+	assert.NotEmpty(t, ei.OutgoingToken)
+	assert.NotEqual(t, secret, ei.HashedSecret)
+	assert.True(t, ei.CheckSecret(secret))
+	assert.False(t, ei.CheckSecret("wrong"))
+	assert.False(t, ei.CheckSecret(""))
+
+	_, _, err = models.NewExternalInitiator("", "")
+	assert.NotNil(t, err)
+	_, _, err = models.NewExternalInitiator("iot", "ftp://ei.example.com")
+	assert.NotNil(t, err)
+}
+
+func TestORM_FindExternalInitiator(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	ei, _, err := models.NewExternalInitiator("iot", "")
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&ei))
+
+	found, err := store.FindExternalInitiator("IoT")
+	assert.Nil(t, err)
+	assert.Equal(t, ei.ID, found.ID)
+
+	found, err = store.FindExternalInitiatorByAccessKey(ei.AccessKey)
+	assert.Nil(t, err)
+	assert.Equal(t, ei.ID, found.ID)
+
+	_, err = store.FindExternalInitiatorByAccessKey("garbage")
+	assert.NotNil(t, err)
+}
+
+func TestJob_ExternallyAuthorized(t *testing.T) {
+	t.Parallel()
+
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{Type: models.InitiatorExternal, Name: "iot"}}
+	assert.True(t, j.ExternallyAuthorized("IoT"))
+	assert.False(t, j.ExternallyAuthorized("exchange"))
+	assert.False(t, cltest.NewJobWithWebInitiator().ExternallyAuthorized("iot"))
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/utils"
+)
+
+// Feed groups jobs that each fetch the same value from a different data
+// source. The node takes the median of the jobs' latest results as the
+// feed's answer, and reports it to the feed's contract in one transaction,
+// rather than each job sending its own.
+//
+// An answer is reported when the feed has none yet, when it has moved by
+// at least Deviation percent from the last one reported, or when Heartbeat
+// has passed since then. With a Heartbeat, results older than it are stale
+// and left out of the answer. At least MinSources fresh results are needed
+// for an answer, or every job's when MinSources is 0.
+type Feed struct {
+	ID               string                     `json:"id" storm:"id,unique"`
+	Name             string                     `json:"name"`
+	JobIDs           []string                   `json:"jobIds"`
+	Address          common.Address             `json:"address"`
+	FunctionSelector FunctionSelector           `json:"functionSelector"`
+	Decimals         uint                       `json:"decimals"`
+	Deviation        float64                    `json:"deviation"`
+	Heartbeat        string                     `json:"heartbeat,omitempty"`
+	MinSources       int                        `json:"minSources,omitempty"`
+	Observations     map[string]FeedObservation `json:"observations"`
+	LatestAnswer     float64                    `json:"latestAnswer"`
+	LatestTxHash     common.Hash                `json:"latestTxHash"`
+	LatestReportedAt Time                       `json:"latestReportedAt"`
+	CreatedAt        Time                       `json:"createdAt"`
+}
+
+// FeedObservation is the latest result of one of a feed's jobs.
+type FeedObservation struct {
+	Value      float64 `json:"value"`
+	RunID      string  `json:"runId"`
+	ObservedAt Time    `json:"observedAt"`
+}
+
+// NewFeed returns a feed with a new ID and no observations.
+func NewFeed() Feed {
+	return Feed{
+		ID:           utils.NewBytes32ID(),
+		Observations: map[string]FeedObservation{},
+		CreatedAt:    Time{Time: time.Now()},
+	}
+}
+
+// Validate returns an error if the feed's settings cannot be used.
+func (f Feed) Validate() error {
+	if len(f.JobIDs) == 0 {
+		return errors.New("Feed must have at least one job")
+	}
+	seen := map[string]bool{}
+	for _, id := range f.JobIDs {
+		if seen[id] {
+			return fmt.Errorf("Feed has job %v more than once", id)
+		}
+		seen[id] = true
+	}
+	if f.Address == (common.Address{}) {
+		return errors.New("Feed must have an address to report to")
+	}
+	if f.FunctionSelector == (FunctionSelector{}) {
+		return errors.New("Feed must have a function selector to report with")
+	}
+	if f.Deviation < 0 {
+		return fmt.Errorf("Feed deviation %v cannot be negative", f.Deviation)
+	}
+	if f.Decimals > 77 {
+		return fmt.Errorf("Feed decimals %v do not fit in a uint256", f.Decimals)
+	}
+	if f.Heartbeat != "" {
+		if d, err := time.ParseDuration(f.Heartbeat); err != nil || d <= 0 {
+			return fmt.Errorf("Feed heartbeat %v is not a positive duration", f.Heartbeat)
+		}
+	}
+	if f.MinSources < 0 || f.MinSources > len(f.JobIDs) {
+		return fmt.Errorf("Feed minSources %v must be between 0 and its %v jobs", f.MinSources, len(f.JobIDs))
+	}
+	return nil
+}
+
+// HasJob returns true if the job is one of the feed's sources.
+func (f Feed) HasJob(jobID string) bool {
+	for _, id := range f.JobIDs {
+		if id == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Feed) heartbeat() time.Duration {
+	d, _ := time.ParseDuration(f.Heartbeat)
+	return d
+}
+
+// Fresh returns the values of the observations that are not stale at the
+// given time.
+func (f Feed) Fresh(now time.Time) []float64 {
+	heartbeat := f.heartbeat()
+	values := []float64{}
+	for _, id := range f.JobIDs {
+		o, ok := f.Observations[id]
+		if !ok || (heartbeat > 0 && !now.Before(o.ObservedAt.Add(heartbeat))) {
+			continue
+		}
+		values = append(values, o.Value)
+	}
+	return values
+}
+
+// Answer returns the median of the fresh observations at the given time,
+// or an error if there are too few of them.
+func (f Feed) Answer(now time.Time) (float64, error) {
+	values := f.Fresh(now)
+	min := f.MinSources
+	if min == 0 {
+		min = len(f.JobIDs)
+	}
+	if len(values) == 0 || len(values) < min {
+		return 0, fmt.Errorf("Feed %v has %v of the %v fresh sources it needs", f.ID, len(values), min)
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2, nil
+	}
+	return values[mid], nil
+}
+
+// ReportDue returns true if the answer should be reported at the given
+// time.
+func (f Feed) ReportDue(answer float64, now time.Time) bool {
+	if f.LatestReportedAt.IsZero() {
+		return true
+	}
+	if heartbeat := f.heartbeat(); heartbeat > 0 && !now.Before(f.LatestReportedAt.Add(heartbeat)) {
+		return true
+	}
+	if f.LatestAnswer == 0 {
+		return answer != 0
+	}
+	change := math.Abs(answer-f.LatestAnswer) / math.Abs(f.LatestAnswer) * 100
+	return change >= f.Deviation
+}
+
+// ReportData returns the call data reporting the answer to the feed's
+// contract, which is the answer times 10^Decimals as a uint256.
+func (f Feed) ReportData(answer float64) ([]byte, error) {
+	if answer < 0 || math.IsNaN(answer) || math.IsInf(answer, 0) {
+		return nil, fmt.Errorf("Feed %v cannot report answer %v as a uint256", f.ID, answer)
+	}
+	// Scale the answer as written in decimal, and round, so that answers
+	// such as 0.29 are not truncated to just below what they look like.
+	scaled, _, err := big.ParseFloat(strconv.FormatFloat(answer, 'f', -1, 64), 10, 512, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(f.Decimals)), nil)
+	scaled.Mul(scaled, new(big.Float).SetInt(scale))
+	scaled.Add(scaled, big.NewFloat(0.5))
+	value, _ := scaled.Int(nil)
+	if value.BitLen() > 256 {
+		return nil, fmt.Errorf("Feed %v answer %v does not fit in a uint256", f.ID, answer)
+	}
+	return append(f.FunctionSelector[:], common.LeftPadBytes(value.Bytes(), 32)...), nil
+}
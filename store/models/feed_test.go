@@ -0,0 +1,114 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFeed(jobIDs ...string) models.Feed {
+	feed := models.NewFeed()
+	feed.JobIDs = jobIDs
+	feed.Address = cltest.NewAddress()
+	feed.FunctionSelector = models.HexToFunctionSelector("0x9a6fc8f5")
+	return feed
+}
+
+func observe(feed models.Feed, jobID string, value float64, at time.Time) {
+	feed.Observations[jobID] = models.FeedObservation{Value: value, ObservedAt: models.Time{Time: at}}
+}
+
+func TestFeed_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		change  func(*models.Feed)
+		wantErr bool
+	}{
+		{"valid", func(*models.Feed) {}, false},
+		{"no jobs", func(f *models.Feed) { f.JobIDs = nil }, true},
+		{"repeated job", func(f *models.Feed) { f.JobIDs = []string{"a", "a"} }, true},
+		{"no address", func(f *models.Feed) { f.Address = [20]byte{} }, true},
+		{"no selector", func(f *models.Feed) { f.FunctionSelector = models.FunctionSelector{} }, true},
+		{"negative deviation", func(f *models.Feed) { f.Deviation = -1 }, true},
+		{"bad heartbeat", func(f *models.Feed) { f.Heartbeat = "often" }, true},
+		{"too many sources", func(f *models.Feed) { f.MinSources = 3 }, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed := newTestFeed("a", "b")
+			test.change(&feed)
+			err := feed.Validate()
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestFeed_Answer(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	feed := newTestFeed("a", "b", "c")
+	feed.Heartbeat = "1h"
+	observe(feed, "a", 100, now)
+	observe(feed, "b", 104, now)
+
+	_, err := feed.Answer(now)
+	assert.NotNil(t, err, "every source is needed without minSources")
+
+	feed.MinSources = 2
+	answer, err := feed.Answer(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 102.0, answer)
+
+	observe(feed, "c", 101, now)
+	answer, err = feed.Answer(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 101.0, answer)
+
+	observe(feed, "a", 100, now.Add(-2*time.Hour))
+	assert.Equal(t, 2, len(feed.Fresh(now)))
+	answer, err = feed.Answer(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 102.5, answer)
+}
+
+func TestFeed_ReportDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	feed := newTestFeed("a")
+	feed.Deviation = 1
+	feed.Heartbeat = "1h"
+	assert.True(t, feed.ReportDue(100, now), "nothing reported yet")
+
+	feed.LatestAnswer = 100
+	feed.LatestReportedAt = models.Time{Time: now}
+	assert.False(t, feed.ReportDue(100.5, now))
+	assert.True(t, feed.ReportDue(101, now))
+	assert.True(t, feed.ReportDue(98, now))
+	assert.True(t, feed.ReportDue(100, now.Add(time.Hour)))
+}
+
+func TestFeed_ReportData(t *testing.T) {
+	t.Parallel()
+
+	feed := newTestFeed("a")
+	feed.Decimals = 2
+	data, err := feed.ReportData(123.45)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x9a6fc8f5"+"0000000000000000000000000000000000000000000000000000000000003039", hexutil.Encode(data))
+
+	data, err = feed.ReportData(0.29)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x9a6fc8f5"+"000000000000000000000000000000000000000000000000000000000000001d", hexutil.Encode(data))
+
+	_, err = feed.ReportData(-1)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+)
+
+// FluxFeed is one of the data sources a "fluxmonitor" initiator polls:
+// either an http or https URL whose JSON response holds the value at Path,
+// or the name of a bridge that responds with it as its value.
+type FluxFeed struct {
+	URL    string   `json:"url,omitempty"`
+	Path   []string `json:"path,omitempty"`
+	Bridge string   `json:"bridge,omitempty"`
+}
+
+// Validate returns an error unless the feed has a URL or a bridge, but not
+// both.
+func (f FluxFeed) Validate() error {
+	if (f.URL == "") == (f.Bridge == "") {
+		return errors.New("feed must have either a url or a bridge")
+	}
+	if f.URL == "" {
+		return nil
+	}
+	if u, err := url.ParseRequestURI(f.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("feed url must be an http or https URL, got %v", f.URL)
+	}
+	return nil
+}
+
+// Tasks returns the tasks that fetch the feed's value: an HttpGet and a
+// JsonParse of its path for a URL, or its bridge.
+func (f FluxFeed) Tasks() ([]Task, error) {
+	if f.Bridge != "" {
+		return []Task{{Type: strings.ToLower(f.Bridge)}}, nil
+	}
+	get, err := JSON{}.Add("url", f.URL)
+	if err != nil {
+		return nil, err
+	}
+	parse, err := JSON{}.Add("path", f.Path)
+	if err != nil {
+		return nil, err
+	}
+	return []Task{{Type: "httpget", Params: get}, {Type: "jsonparse", Params: parse}}, nil
+}
+
+// ScaleFluxAnswer returns a contract's answer divided by 10^precision.
+func ScaleFluxAnswer(answer *big.Int, precision uint) float64 {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(answer), new(big.Float).SetInt(scale)).Float64()
+	return value
+}
@@ -0,0 +1,41 @@
+package models_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFluxFeed_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, models.FluxFeed{URL: "https://api.example.com/eth", Path: []string{"price"}}.Validate())
+	assert.Nil(t, models.FluxFeed{Bridge: "coinmarketcap"}.Validate())
+	assert.NotNil(t, models.FluxFeed{}.Validate())
+	assert.NotNil(t, models.FluxFeed{URL: "https://api.example.com/eth", Bridge: "coinmarketcap"}.Validate())
+	assert.NotNil(t, models.FluxFeed{URL: "api.example.com/eth"}.Validate())
+}
+
+func TestFluxFeed_Tasks(t *testing.T) {
+	t.Parallel()
+
+	tasks, err := models.FluxFeed{URL: "https://api.example.com/eth", Path: []string{"data", "price"}}.Tasks()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"httpget", "jsonparse"}, []string{tasks[0].Type, tasks[1].Type})
+	assert.Equal(t, "https://api.example.com/eth", tasks[0].Params.Get("url").String())
+	assert.Equal(t, `["data","price"]`, tasks[1].Params.Get("path").Raw)
+
+	tasks, err = models.FluxFeed{Bridge: "CoinMarketCap"}.Tasks()
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Task{{Type: "coinmarketcap"}}, tasks)
+}
+
+func TestScaleFluxAnswer(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 314.15, models.ScaleFluxAnswer(big.NewInt(31415), 2))
+	assert.Equal(t, -25.0, models.ScaleFluxAnswer(big.NewInt(-250), 1))
+	assert.Equal(t, 7.0, models.ScaleFluxAnswer(big.NewInt(7), 0))
+}
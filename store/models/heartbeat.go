@@ -0,0 +1,19 @@
+package models
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Heartbeat records a transaction the node sent to its own account to
+// prove on chain that it was live and held its key, along with the most
+// the transaction was expected to cost.
+type Heartbeat struct {
+	ID        uint64      `json:"id" storm:"id,increment"`
+	TxID      uint64      `json:"txId"`
+	Hash      common.Hash `json:"hash"`
+	Cost      *big.Int    `json:"cost"`
+	CreatedAt time.Time   `json:"createdAt" storm:"index"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/tidwall/gjson"
+)
+
+// TemplateEnvPrefix is what the names of the environment variables that
+// templates can read must start with. The rest of the node's environment,
+// such as its keystore password, is kept from job authors.
+const TemplateEnvPrefix = "CL_TEMPLATE_"
+
+// RedactTemplateEnv returns the string with the value of each environment
+// variable that templates can read replaced by the template that reads
+// it, so that what was sent with them can be kept without them.
+func RedactTemplateEnv(s string) string {
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 && parts[1] != "" && strings.HasPrefix(parts[0], TemplateEnvPrefix) {
+			s = strings.Replace(s, parts[1], fmt.Sprintf(`{{ env "%v" }}`, parts[0]), -1)
+		}
+	}
+	return s
+}
+
+var templateFuncs = template.FuncMap{
+	"env": func(name string) (string, error) {
+		if !strings.HasPrefix(name, TemplateEnvPrefix) {
+			return "", fmt.Errorf("environment variable %v cannot be read by templates, only those starting with %v", name, TemplateEnvPrefix)
+		}
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %v is not set", name)
+		}
+		return val, nil
+	},
+}
+
+// Interpolate returns a copy of the task with every string param that
+// contains "{{" executed as a text/template against the given variables.
+// Templates can also read environment variables starting with
+// TemplateEnvPrefix with {{ env "CL_TEMPLATE_NAME" }}. Missing keys, unset
+// environment variables, and other environment variables are errors rather
+// than empty strings.
+func (t Task) Interpolate(vars map[string]interface{}) (Task, error) {
+	if !strings.Contains(t.Params.Raw, "{{") {
+		return t, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(t.Params.Raw))
+	dec.UseNumber()
+	var params interface{}
+	if err := dec.Decode(&params); err != nil {
+		return t, err
+	}
+
+	interpolated, err := interpolateValue(params, vars)
+	if err != nil {
+		return t, fmt.Errorf("Task %v: %v", t.Type, err)
+	}
+	b, err := json.Marshal(interpolated)
+	if err != nil {
+		return t, err
+	}
+	t.Params = JSON{gjson.ParseBytes(b)}
+	return t, nil
+}
+
+func interpolateValue(v interface{}, vars map[string]interface{}) (interface{}, error) {
+	switch typed := v.(type) {
+	case string:
+		return interpolateString(typed, vars)
+	case []interface{}:
+		for i, elem := range typed {
+			val, err := interpolateValue(elem, vars)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = val
+		}
+	case map[string]interface{}:
+		for k, elem := range typed {
+			val, err := interpolateValue(elem, vars)
+			if err != nil {
+				return nil, err
+			}
+			typed[k] = val
+		}
+	}
+	return v, nil
+}
+
+func interpolateString(str string, vars map[string]interface{}) (string, error) {
+	if !strings.Contains(str, "{{") {
+		return str, nil
+	}
+	tmpl, err := template.New("param").Funcs(templateFuncs).Option("missingkey=error").Parse(str)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_Interpolate(t *testing.T) {
+	os.Setenv("CL_TEMPLATE_TEST_API_KEY", "secret")
+	defer os.Unsetenv("CL_TEMPLATE_TEST_API_KEY")
+	os.Setenv("CHAINLINK_TEST_PASSWORD", "hunter2")
+	defer os.Unsetenv("CHAINLINK_TEST_PASSWORD")
+
+	vars := map[string]interface{}{
+		"jobRun": map[string]interface{}{"id": "run1"},
+		"data":   map[string]interface{}{"result": "42"},
+	}
+
+	tests := []struct {
+		name        string
+		params      string
+		want        string
+		wantErrored bool
+	}{
+		{"no templates", `{"url":"https://example.com","times":100}`,
+			`{"url":"https://example.com","times":100,"type":"httpget"}`, false},
+		{"run data", `{"url":"https://example.com/{{ .jobRun.id }}?r={{ .data.result }}"}`,
+			`{"url":"https://example.com/run1?r=42","type":"httpget"}`, false},
+		{"nested and env", `{"headers":{"key":"{{ env \"CL_TEMPLATE_TEST_API_KEY\" }}"},"path":["{{ .data.result }}"]}`,
+			`{"headers":{"key":"secret"},"path":["42"],"type":"httpget"}`, false},
+		{"large numbers kept", `{"times":100000000000000000001,"url":"{{ .jobRun.id }}"}`,
+			`{"times":100000000000000000001,"url":"run1","type":"httpget"}`, false},
+		{"missing key", `{"url":"{{ .data.missing }}"}`, "", true},
+		{"unset env", `{"url":"{{ env \"CL_TEMPLATE_TEST_UNSET\" }}"}`, "", true},
+		{"env without prefix", `{"url":"{{ env \"CHAINLINK_TEST_PASSWORD\" }}"}`, "", true},
+		{"bad template", `{"url":"{{ .data.result "}`, "", true},
+	}
+
+	for _, test := range tests {
+		task := cltest.NewTask("httpget", test.params)
+		interpolated, err := task.Interpolate(vars)
+		assert.Equal(t, test.wantErrored, err != nil, test.name)
+		if !test.wantErrored {
+			assert.JSONEq(t, test.want, interpolated.Params.String(), test.name)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"math/rand"
+	"regexp"
 	"strings"
 	"time"
 
@@ -13,6 +15,10 @@ import (
 )
 
 const (
+	// StatusQueued is used for when a run has been saved with its input
+	// and is waiting for a worker, so that it is started on boot if the
+	// node stops first.
+	StatusQueued = "queued"
 	// StatusInProgress is used for when a run is actively being executed.
 	StatusInProgress = "in progress"
 	// StatusPending is used for when a run is waiting on the completion
@@ -27,15 +33,51 @@ const (
 // Job is the definition for all the work to be carried out by the node
 // for a given contract. It contains the Initiators, Tasks (which are the
 // individual steps to be carried out), StartAt, EndAt, and CreatedAt fields.
+// DebugSampleRate is the percentage of runs for which every task's input
+// and output are persisted and logged, to help track down intermittent
+// failures. MinPayment is the least LINK the operator asks to be paid for
+// each run, advertised to the job registry. Paused jobs do not start new
+// runs. Archived jobs are no longer scheduled or subscribed to, but keep
+// their run history. CronJitter delays each run a cron initiator starts by
+// a random time up to it, so that jobs on the same schedule do not all
+// call out at once, and SkipOverlapping records a cron firing as skipped,
+// rather than starting a run, while the job's last run is unfinished.
+// MaxConcurrentRuns caps how many of the job's runs execute at once, on top
+// of MAX_CONCURRENT_RUNS, so that a burst of logs for one job does not
+// crowd out the rest. Timeout is how long a run may take from when it was
+// created; a task still working when it passes is cancelled and errored,
+// along with the run. Webhooks are URLs told when the job's runs complete
+// or error, or their transactions confirm, along with RUN_WEBHOOK_URL.
+// Its DomainLists limit the hosts its HTTP tasks can send requests to, on
+// top of ADAPTER_ALLOWED_DOMAINS and ADAPTER_DENIED_DOMAINS. SchemaVersion
+// is the version of the job spec schema its tasks' params are checked
+// against, with 0 for specs written before it was versioned.
 type Job struct {
-	ID         string      `json:"id" storm:"id,index,unique"`
-	Initiators []Initiator `json:"initiators"`
-	Tasks      []Task      `json:"tasks" storm:"inline"`
-	StartAt    null.Time   `json:"startAt" storm:"index"`
-	EndAt      null.Time   `json:"endAt" storm:"index"`
-	CreatedAt  Time        `json:"createdAt" storm:"index"`
+	ID                string      `json:"id" storm:"id,index,unique"`
+	Initiators        []Initiator `json:"initiators"`
+	Tasks             []Task      `json:"tasks" storm:"inline"`
+	StartAt           null.Time   `json:"startAt" storm:"index"`
+	EndAt             null.Time   `json:"endAt" storm:"index"`
+	CreatedAt         Time        `json:"createdAt" storm:"index"`
+	DebugSampleRate   float64     `json:"debugSampleRate,omitempty"`
+	MinPayment        *Link       `json:"minPayment,omitempty"`
+	Paused            bool        `json:"paused"`
+	Archived          bool        `json:"archived"`
+	CronJitter        Duration    `json:"cronJitter,omitempty"`
+	SkipOverlapping   bool        `json:"skipOverlapping,omitempty"`
+	MaxConcurrentRuns uint64      `json:"maxConcurrentRuns,omitempty"`
+	Timeout           Duration    `json:"timeout,omitempty"`
+	Webhooks          []string    `json:"webhooks,omitempty"`
+	SchemaVersion     int         `json:"schemaVersion,omitempty"`
+	DomainLists
 }
 
+// CurrentSchemaVersion is the latest job spec schema. Specs of version 1
+// are refused if their tasks have params their adapters do not take, or
+// are missing ones they require; version 0 specs are only warned about
+// the params their adapters do not take.
+const CurrentSchemaVersion = 1
+
 // NewJob initializes a new job by generating a unique ID and setting
 // the CreatedAt field to the time of invokation.
 func NewJob() Job {
@@ -63,6 +105,7 @@ func (j Job) NewRun() JobRun {
 		JobID:     j.ID,
 		CreatedAt: time.Now(),
 		TaskRuns:  taskRuns,
+		Debug:     rand.Float64()*100 < j.DebugSampleRate,
 	}
 }
 
@@ -90,6 +133,17 @@ func (j Job) WebAuthorized() bool {
 	return false
 }
 
+// ExternallyAuthorized returns true if the job has an "external" initiator
+// naming the given external initiator.
+func (j Job) ExternallyAuthorized(name string) bool {
+	for _, initr := range j.InitiatorsFor(InitiatorExternal) {
+		if initr.Name == strings.ToLower(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // Returns true if any of the job's initiators are triggered by event logs.
 func (j Job) IsLogInitiated() bool {
 	for _, initr := range j.Initiators {
@@ -128,28 +182,56 @@ const (
 	InitiatorRunAt = "runat"
 	// InitiatorWeb for tasks in a job making a web request.
 	InitiatorWeb = "web"
+	// InitiatorExternal for tasks in a job started by the named external
+	// initiator.
+	InitiatorExternal = "external"
+	// InitiatorFluxMonitor for tasks in a job to be ran when the value of
+	// its polled feeds moves away from the answer on chain.
+	InitiatorFluxMonitor = "fluxmonitor"
 )
 
 var initiatorWhitelist = map[string]bool{
-	InitiatorRunLog: true,
-	InitiatorCron:   true,
-	InitiatorEthLog: true,
-	InitiatorRunAt:  true,
-	InitiatorWeb:    true,
+	InitiatorRunLog:      true,
+	InitiatorCron:        true,
+	InitiatorEthLog:      true,
+	InitiatorRunAt:       true,
+	InitiatorWeb:         true,
+	InitiatorExternal:    true,
+	InitiatorFluxMonitor: true,
 }
 
 // Initiator could be though of as a trigger, define how a Job can be
 // started, or rather, how a JobRun can be created from a Job.
 // Initiators will have their own unique ID, but will be assocated
-// to a parent JobID.
+// to a parent JobID. Name is the external initiator allowed to start runs
+// of an "external" initiator's job, and Params are passed on to it. A
+// "fluxmonitor" initiator polls its Feeds every PollingInterval, and starts
+// a run with their median as the value when it has moved by at least
+// Threshold percent from the answer of the contract at Address, scaled by
+// 10^Precision, or when IdleThreshold has passed since its last run. An
+// "ethlog" initiator with an Event decodes the logs of that event into
+// fields named after its inputs, and ignores other logs. Log initiators
+// listen to the contracts at Address, at each of Addresses, and on the
+// AddressList with the given name.
 type Initiator struct {
-	ID       int            `json:"id" storm:"id,increment"`
-	JobID    string         `json:"jobId" storm:"index"`
-	Type     string         `json:"type" storm:"index"`
-	Schedule Cron           `json:"schedule,omitempty"`
-	Time     Time           `json:"time,omitempty"`
-	Ran      bool           `json:"ran,omitempty"`
-	Address  common.Address `json:"address,omitempty" storm:"index"`
+	ID              int              `json:"id" storm:"id,increment"`
+	JobID           string           `json:"jobId" storm:"index"`
+	Type            string           `json:"type" storm:"index"`
+	Schedule        Cron             `json:"schedule,omitempty"`
+	Time            Time             `json:"time,omitempty"`
+	Ran             bool             `json:"ran,omitempty"`
+	Missed          bool             `json:"missed,omitempty"`
+	Address         common.Address   `json:"address,omitempty" storm:"index"`
+	Addresses       []common.Address `json:"addresses,omitempty"`
+	AddressList     string           `json:"addressList,omitempty"`
+	Name            string           `json:"name,omitempty"`
+	Params          *JSON            `json:"params,omitempty"`
+	Feeds           []FluxFeed       `json:"feeds,omitempty"`
+	Threshold       float64          `json:"threshold,omitempty"`
+	IdleThreshold   Duration         `json:"idleThreshold,omitempty"`
+	PollingInterval Duration         `json:"pollingInterval,omitempty"`
+	Precision       uint             `json:"precision,omitempty"`
+	Event           *EventABI        `json:"event,omitempty"`
 }
 
 // UnmarshalJSON parses the raw initiator data and updates the
@@ -163,6 +245,8 @@ func (i *Initiator) UnmarshalJSON(input []byte) error {
 
 	*i = Initiator(aux)
 	i.Type = strings.ToLower(aux.Type)
+	i.Name = strings.ToLower(aux.Name)
+	i.AddressList = strings.ToLower(aux.AddressList)
 	if _, valid := initiatorWhitelist[i.Type]; !valid {
 		return fmt.Errorf("Initiator %v does not exist", aux.Type)
 	}
@@ -177,9 +261,19 @@ func (i Initiator) IsLogInitiated() bool {
 // Task is the specific unit of work to be carried out. The
 // Type will be an adapter, and the Params will contain any
 // additional information that adapter would need to operate.
+// The optional "maxRetries", "backoff", and "retryable" params
+// make up the task's Retry policy, and the optional "onlyIf" param
+// is the Condition under which the task is performed. The optional
+// "onError" param lists the tasks performed in its place if it fails.
+// The optional "timeout" param is how long the task may take before its
+// work is cancelled and it is errored.
 type Task struct {
-	Type   string `json:"type" storm:"index"`
-	Params JSON
+	Type    string        `json:"type" storm:"index"`
+	Retry   RetryPolicy   `json:"-"`
+	OnlyIf  *Condition    `json:"-"`
+	OnError []Task        `json:"-"`
+	Timeout time.Duration `json:"-"`
+	Params  JSON
 }
 
 // UnmarshalJSON parses the given input and updates the Task.
@@ -191,6 +285,21 @@ func (t *Task) UnmarshalJSON(input []byte) error {
 	}
 
 	t.Type = strings.ToLower(aux.Type)
+	retry, err := parseRetryPolicy(input)
+	if err != nil {
+		return fmt.Errorf("Task %v: %v", aux.Type, err)
+	}
+	t.Retry = retry
+	if t.OnlyIf, err = parseCondition(input); err != nil {
+		return fmt.Errorf("Task %v: %v", aux.Type, err)
+	}
+	if t.OnError, err = parseFallbacks(input); err != nil {
+		return fmt.Errorf("Task %v: onError: %v", aux.Type, err)
+	}
+	if t.Timeout, err = parseTimeout(input); err != nil {
+		return fmt.Errorf("Task %v: %v", aux.Type, err)
+	}
+
 	var params json.RawMessage
 	if err := json.Unmarshal(input, &params); err != nil {
 		return err
@@ -205,6 +314,115 @@ func (t Task) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.Params)
 }
 
+func parseFallbacks(input []byte) ([]Task, error) {
+	var aux struct {
+		OnError []Task `json:"onError"`
+	}
+	err := json.Unmarshal(input, &aux)
+	return aux.OnError, err
+}
+
+func parseTimeout(input []byte) (time.Duration, error) {
+	var aux struct {
+		Timeout string `json:"timeout"`
+	}
+	if err := json.Unmarshal(input, &aux); err != nil || aux.Timeout == "" {
+		return 0, err
+	}
+	d, err := time.ParseDuration(aux.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout: %v", err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid timeout: %v is negative", aux.Timeout)
+	}
+	return d, nil
+}
+
+const (
+	// defaultRetryBackoff is the delay before the first retry of a task
+	// that sets maxRetries without a backoff.
+	defaultRetryBackoff = time.Second
+	// maxRetryDelay is the longest a doubling backoff grows to.
+	maxRetryDelay = time.Hour
+)
+
+// RetryPolicy holds the number of times a failed task is attempted again,
+// the delay before the first retry, which doubles with every retry after
+// that up to an hour, or the backoff itself if longer, and the error
+// patterns that are worth retrying. When no patterns are given, every
+// error is retried.
+type RetryPolicy struct {
+	MaxRetries uint
+	Backoff    time.Duration
+	Retryable  []*regexp.Regexp
+}
+
+func parseRetryPolicy(input []byte) (RetryPolicy, error) {
+	var aux struct {
+		MaxRetries uint     `json:"maxRetries"`
+		Backoff    string   `json:"backoff"`
+		Retryable  []string `json:"retryable"`
+	}
+	if err := json.Unmarshal(input, &aux); err != nil {
+		return RetryPolicy{}, err
+	}
+
+	rp := RetryPolicy{MaxRetries: aux.MaxRetries}
+	if aux.Backoff != "" {
+		d, err := time.ParseDuration(aux.Backoff)
+		if err != nil {
+			return rp, fmt.Errorf("invalid backoff: %v", err)
+		}
+		rp.Backoff = d
+	}
+	for _, pattern := range aux.Retryable {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return rp, fmt.Errorf("invalid retryable pattern: %v", err)
+		}
+		rp.Retryable = append(rp.Retryable, re)
+	}
+	return rp, nil
+}
+
+// ShouldRetry returns true if a task that has failed with the given error
+// after the given number of attempts should be attempted again.
+func (rp RetryPolicy) ShouldRetry(attempts uint, errMessage string) bool {
+	if attempts > rp.MaxRetries {
+		return false
+	}
+	if len(rp.Retryable) == 0 {
+		return true
+	}
+	for _, re := range rp.Retryable {
+		if re.MatchString(errMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns how long to wait before the next attempt of a task that
+// has already been attempted the given number of times.
+func (rp RetryPolicy) Delay(attempts uint) time.Duration {
+	backoff := rp.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	limit := maxRetryDelay
+	if backoff > limit {
+		limit = backoff
+	}
+	for i := uint(1); i < attempts && backoff < limit; i++ {
+		backoff *= 2
+	}
+	if backoff > limit {
+		return limit
+	}
+	return backoff
+}
+
 // BridgeType is used for external adapters and has fields for
 // the name of the adapter and its URL.
 type BridgeType struct {
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// The formats a JobBundle can be written in.
+const (
+	JobBundleJSON = "json"
+	JobBundleYAML = "yaml"
+)
+
+// JobBundle holds the specs of a set of jobs, so that nodes can be
+// provisioned from version-controlled job definitions. Each spec keeps its
+// job's ID, initiators, tasks, and schedule, but not state particular to
+// the node it came from, such as whether a runat initiator has run.
+type JobBundle struct {
+	Jobs []json.RawMessage `json:"jobs"`
+}
+
+// NewJobBundle returns a bundle of the specs of the given jobs.
+func NewJobBundle(jobs []Job) (JobBundle, error) {
+	bundle := JobBundle{Jobs: []json.RawMessage{}}
+	for _, j := range jobs {
+		spec, err := json.Marshal(newJobSpec(j))
+		if err != nil {
+			return JobBundle{}, err
+		}
+		bundle.Jobs = append(bundle.Jobs, spec)
+	}
+	return bundle, nil
+}
+
+type jobSpec struct {
+	ID              string                   `json:"id"`
+	Initiators      []map[string]interface{} `json:"initiators"`
+	Tasks           []Task                   `json:"tasks"`
+	StartAt         interface{}              `json:"startAt,omitempty"`
+	EndAt           interface{}              `json:"endAt,omitempty"`
+	DebugSampleRate float64                  `json:"debugSampleRate,omitempty"`
+	MinPayment      *Link                    `json:"minPayment,omitempty"`
+}
+
+func newJobSpec(j Job) jobSpec {
+	spec := jobSpec{
+		ID:              j.ID,
+		Initiators:      []map[string]interface{}{},
+		Tasks:           j.Tasks,
+		DebugSampleRate: j.DebugSampleRate,
+		MinPayment:      j.MinPayment,
+	}
+	if j.StartAt.Valid {
+		spec.StartAt = j.StartAt
+	}
+	if j.EndAt.Valid {
+		spec.EndAt = j.EndAt
+	}
+	for _, initr := range j.Initiators {
+		i := map[string]interface{}{"type": initr.Type}
+		if initr.Schedule != "" {
+			i["schedule"] = initr.Schedule
+		}
+		if !initr.Time.IsZero() {
+			i["time"] = initr.Time
+		}
+		if initr.Address != (common.Address{}) {
+			i["address"] = initr.Address
+		}
+		if len(initr.Addresses) > 0 {
+			i["addresses"] = initr.Addresses
+		}
+		if initr.AddressList != "" {
+			i["addressList"] = initr.AddressList
+		}
+		spec.Initiators = append(spec.Initiators, i)
+	}
+	return spec
+}
+
+// Encode writes the bundle in the given format.
+func (jb JobBundle) Encode(format string) ([]byte, error) {
+	b, err := json.MarshalIndent(jb, "", "  ")
+	if err != nil || format == JobBundleJSON {
+		return b, err
+	}
+	if format != JobBundleYAML {
+		return nil, fmt.Errorf("Unknown job bundle format %v", format)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
+// DecodeJobBundle reads a bundle written in the given format.
+func DecodeJobBundle(b []byte, format string) (JobBundle, error) {
+	var bundle JobBundle
+	switch format {
+	case JobBundleJSON:
+	case JobBundleYAML:
+		var v interface{}
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return bundle, err
+		}
+		var err error
+		if b, err = json.Marshal(yamlToJSON(v)); err != nil {
+			return bundle, err
+		}
+	default:
+		return bundle, fmt.Errorf("Unknown job bundle format %v", format)
+	}
+	err := json.Unmarshal(b, &bundle)
+	return bundle, err
+}
+
+// yamlToJSON converts the maps YAML decodes to, which can have keys of any
+// type, to maps with string keys that can be written as JSON.
+func yamlToJSON(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range typed {
+			m[fmt.Sprint(k)] = yamlToJSON(v)
+		}
+		return m
+	case []interface{}:
+		for i, v := range typed {
+			typed[i] = yamlToJSON(v)
+		}
+		return typed
+	default:
+		return v
+	}
+}
+
+// NewJobs returns a new job for each spec in the bundle, with the spec's
+// ID if it has one.
+func (jb JobBundle) NewJobs() ([]Job, error) {
+	jobs := []Job{}
+	for i, spec := range jb.Jobs {
+		j := NewJob()
+		if err := json.Unmarshal(spec, &j); err != nil {
+			return nil, fmt.Errorf("Job %v: %v", i, err)
+		}
+		if j.ID == "" {
+			j.ID = NewJob().ID
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
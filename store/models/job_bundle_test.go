@@ -0,0 +1,78 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const bundledJobSpec = `{
+	"initiators": [{"type": "cron", "schedule": "* * * * *"}],
+	"tasks": [
+		{"type": "HttpGet", "url": "https://example.com/price", "maxRetries": 2},
+		{"type": "JsonParse", "path": ["last"]}
+	],
+	"debugSampleRate": 0.5,
+	"minPayment": 1000000000000000001
+}`
+
+func TestJobBundle_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{models.JobBundleJSON, models.JobBundleYAML} {
+		t.Run(format, func(t *testing.T) {
+			j := models.NewJob()
+			assert.Nil(t, json.Unmarshal([]byte(bundledJobSpec), &j))
+			j.Initiators[0].Ran = true
+			j.Initiators[0].JobID = j.ID
+
+			bundle, err := models.NewJobBundle([]models.Job{j})
+			assert.Nil(t, err)
+			b, err := bundle.Encode(format)
+			assert.Nil(t, err)
+			decoded, err := models.DecodeJobBundle(b, format)
+			assert.Nil(t, err)
+			jobs, err := decoded.NewJobs()
+			assert.Nil(t, err)
+
+			assert.Equal(t, 1, len(jobs))
+			got := jobs[0]
+			assert.Equal(t, j.ID, got.ID)
+			assert.Equal(t, 0.5, got.DebugSampleRate)
+			assert.Equal(t, "1000000000000000001", got.MinPayment.String())
+			assert.Equal(t, 1, len(got.Initiators))
+			assert.Equal(t, models.InitiatorCron, got.Initiators[0].Type)
+			assert.Equal(t, models.Cron("* * * * *"), got.Initiators[0].Schedule)
+			assert.False(t, got.Initiators[0].Ran)
+			assert.Equal(t, "", got.Initiators[0].JobID)
+			assert.Equal(t, 2, len(got.Tasks))
+			assert.Equal(t, "httpget", got.Tasks[0].Type)
+			assert.Equal(t, uint(2), got.Tasks[0].Retry.MaxRetries)
+			assert.Equal(t, "https://example.com/price", got.Tasks[0].Params.Get("url").String())
+			assert.Equal(t, "last", got.Tasks[1].Params.Get("path.0").String())
+		})
+	}
+}
+
+func TestJobBundle_NewJobs_WithoutID(t *testing.T) {
+	t.Parallel()
+
+	bundle, err := models.DecodeJobBundle([]byte("jobs:\n- tasks:\n  - type: NoOp\n"), models.JobBundleYAML)
+	assert.Nil(t, err)
+	jobs, err := bundle.NewJobs()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(jobs))
+	assert.NotEmpty(t, jobs[0].ID)
+	assert.Equal(t, "noop", jobs[0].Tasks[0].Type)
+}
+
+func TestJobBundle_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := models.JobBundle{}.Encode("toml")
+	assert.NotNil(t, err)
+	_, err = models.DecodeJobBundle([]byte(`{}`), "toml")
+	assert.NotNil(t, err)
+}
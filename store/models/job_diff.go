@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+
+	null "gopkg.in/guregu/null.v3"
+)
+
+const (
+	// TaskAdded is a task that only the proposed job has.
+	TaskAdded = "added"
+	// TaskRemoved is a task that only the current job has.
+	TaskRemoved = "removed"
+	// TaskReplaced is a task whose type differs between the two jobs.
+	TaskReplaced = "replaced"
+	// TaskModified is a task of the same type whose params differ.
+	TaskModified = "modified"
+)
+
+// JobDiff describes how a proposed job spec differs from the current one,
+// and what changing it would mean for the running node: log initiators
+// whose subscriptions are started or stopped, and whether the job's
+// schedule changes.
+type JobDiff struct {
+	InitiatorsAdded        []Initiator  `json:"initiatorsAdded"`
+	InitiatorsRemoved      []Initiator  `json:"initiatorsRemoved"`
+	Tasks                  []TaskChange `json:"tasks"`
+	SubscriptionsToRestart []Initiator  `json:"subscriptionsToRestart"`
+	Rescheduled            bool         `json:"rescheduled"`
+}
+
+// Empty returns true if the proposed job is the same as the current one.
+func (d JobDiff) Empty() bool {
+	return len(d.InitiatorsAdded) == 0 && len(d.InitiatorsRemoved) == 0 && len(d.Tasks) == 0
+}
+
+// TaskChange is a difference between the tasks at the same Index of the
+// current and proposed jobs. Params holds each param that differs for
+// modified tasks.
+type TaskChange struct {
+	Index        int                    `json:"index"`
+	Change       string                 `json:"change"`
+	Type         string                 `json:"type"`
+	PreviousType string                 `json:"previousType,omitempty"`
+	Params       map[string]ParamChange `json:"params,omitempty"`
+}
+
+// ParamChange holds a task param's current and proposed values, either of
+// which is nil if the param is not set.
+type ParamChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Diff compares the proposed job against the current one.
+func (j Job) Diff(proposed Job) JobDiff {
+	diff := JobDiff{
+		InitiatorsAdded:        missingInitiators(proposed.Initiators, j.Initiators),
+		InitiatorsRemoved:      missingInitiators(j.Initiators, proposed.Initiators),
+		Tasks:                  []TaskChange{},
+		SubscriptionsToRestart: []Initiator{},
+	}
+
+	for _, initr := range append(diff.InitiatorsAdded, diff.InitiatorsRemoved...) {
+		if initr.IsLogInitiated() {
+			diff.SubscriptionsToRestart = append(diff.SubscriptionsToRestart, initr)
+		} else if initr.Type == InitiatorCron || initr.Type == InitiatorRunAt {
+			diff.Rescheduled = true
+		}
+	}
+	if !sameTime(j.StartAt, proposed.StartAt) || !sameTime(j.EndAt, proposed.EndAt) {
+		diff.Rescheduled = true
+	}
+
+	for i := 0; i < len(j.Tasks) || i < len(proposed.Tasks); i++ {
+		if change, ok := diffTask(i, j.Tasks, proposed.Tasks); ok {
+			diff.Tasks = append(diff.Tasks, change)
+		}
+	}
+	return diff
+}
+
+func sameTime(a, b null.Time) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.Time.Equal(b.Time))
+}
+
+// missingInitiators returns the initiators in from that are not in other,
+// ignoring the IDs and run state that are set once a job is saved.
+func missingInitiators(from []Initiator, other []Initiator) []Initiator {
+	keys := map[string]bool{}
+	for _, initr := range other {
+		keys[initiatorKey(initr)] = true
+	}
+	missing := []Initiator{}
+	for _, initr := range from {
+		if !keys[initiatorKey(initr)] {
+			missing = append(missing, initr)
+		}
+	}
+	return missing
+}
+
+func initiatorKey(initr Initiator) string {
+	params := ""
+	if initr.Params != nil {
+		params = initr.Params.String()
+	}
+	event := ""
+	if initr.Event != nil {
+		event = fmt.Sprintf("%v%v", initr.Event.Signature(), initr.Event.Inputs)
+	}
+	flux := fmt.Sprintf("%v|%v|%v|%v|%v", initr.Feeds, initr.Threshold, initr.IdleThreshold, initr.PollingInterval, initr.Precision)
+	addresses := fmt.Sprintf("%v|%v|%v", initr.Address.Hex(), initr.Addresses, initr.AddressList)
+	return fmt.Sprintf("%v|%v|%v|%v|%v|%v|%v|%v", initr.Type, initr.Schedule, initr.Time.UTC(), addresses, initr.Name, params, flux, event)
+}
+
+func diffTask(index int, current []Task, proposed []Task) (TaskChange, bool) {
+	if index >= len(current) {
+		return TaskChange{Index: index, Change: TaskAdded, Type: proposed[index].Type}, true
+	}
+	if index >= len(proposed) {
+		return TaskChange{Index: index, Change: TaskRemoved, Type: current[index].Type}, true
+	}
+
+	from, to := current[index], proposed[index]
+	if from.Type != to.Type {
+		return TaskChange{Index: index, Change: TaskReplaced, Type: to.Type, PreviousType: from.Type}, true
+	}
+
+	params := map[string]ParamChange{}
+	fromParams, toParams := from.Params.Map(), to.Params.Map()
+	for key, value := range fromParams {
+		if key == "type" {
+			continue
+		}
+		if other, ok := toParams[key]; !ok || !reflect.DeepEqual(value.Value(), other.Value()) {
+			params[key] = ParamChange{From: value.Value(), To: toParams[key].Value()}
+		}
+	}
+	for key, value := range toParams {
+		if _, ok := fromParams[key]; !ok && key != "type" {
+			params[key] = ParamChange{To: value.Value()}
+		}
+	}
+	if len(params) == 0 {
+		return TaskChange{}, false
+	}
+	return TaskChange{Index: index, Change: TaskModified, Type: to.Type, Params: params}, true
+}
@@ -0,0 +1,80 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Diff(t *testing.T) {
+	t.Parallel()
+
+	var current, proposed models.Job
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"initiators": [
+			{"type": "web"},
+			{"type": "ethlog", "address": "0x3cCad4715152693fE3BC4460591e3D3Fbd071b42"}
+		],
+		"tasks": [
+			{"type": "HttpGet", "url": "https://bitstamp.net/api/ticker/"},
+			{"type": "JsonParse", "path": ["last"]},
+			{"type": "EthUint256"}
+		]
+	}`), &current))
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"initiators": [
+			{"type": "web"},
+			{"type": "ethlog", "address": "0x2fCeA879fDC9FE5e90394faf0CA644a1749d0ad6"},
+			{"type": "cron", "schedule": "0 * * * *"}
+		],
+		"tasks": [
+			{"type": "HttpGet", "url": "https://www.bitstamp.net/api/ticker/"},
+			{"type": "Multiply", "times": 100}
+		]
+	}`), &proposed))
+
+	diff := current.Diff(proposed)
+	assert.False(t, diff.Empty())
+
+	assert.Equal(t, 2, len(diff.InitiatorsAdded))
+	assert.Equal(t, models.InitiatorEthLog, diff.InitiatorsAdded[0].Type)
+	assert.Equal(t, models.InitiatorCron, diff.InitiatorsAdded[1].Type)
+	assert.Equal(t, 1, len(diff.InitiatorsRemoved))
+	assert.Equal(t, current.Initiators[1].Address, diff.InitiatorsRemoved[0].Address)
+	assert.Equal(t, 2, len(diff.SubscriptionsToRestart))
+	assert.True(t, diff.Rescheduled)
+
+	assert.Equal(t, []models.TaskChange{
+		{
+			Index:  0,
+			Change: models.TaskModified,
+			Type:   "httpget",
+			Params: map[string]models.ParamChange{
+				"url": {From: "https://bitstamp.net/api/ticker/", To: "https://www.bitstamp.net/api/ticker/"},
+			},
+		},
+		{Index: 1, Change: models.TaskReplaced, Type: "multiply", PreviousType: "jsonparse"},
+		{Index: 2, Change: models.TaskRemoved, Type: "ethuint256"},
+	}, diff.Tasks)
+}
+
+func TestJob_Diff_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	spec := `{
+		"initiators": [{"type": "runlog", "address": "0x3cCad4715152693fE3BC4460591e3D3Fbd071b42"}],
+		"tasks": [{"type": "HttpGet", "url": "https://bitstamp.net/api/ticker/"}]
+	}`
+	var current, proposed models.Job
+	assert.Nil(t, json.Unmarshal([]byte(spec), &current))
+	assert.Nil(t, json.Unmarshal([]byte(spec), &proposed))
+	current.Initiators[0].ID = 1
+	current.Initiators[0].JobID = current.ID
+
+	diff := current.Diff(proposed)
+	assert.True(t, diff.Empty())
+	assert.Empty(t, diff.SubscriptionsToRestart)
+	assert.False(t, diff.Rescheduled)
+}
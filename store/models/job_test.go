@@ -151,3 +151,97 @@ func TestTaskUnmarshalling(t *testing.T) {
 		})
 	}
 }
+
+func TestTask_UnmarshalJSON_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		input          string
+		wantErrored    bool
+		wantMaxRetries uint
+		wantBackoff    time.Duration
+		wantPatterns   int
+	}{
+		{"no policy", `{"type":"httpget"}`, false, 0, 0, 0},
+		{"full policy", `{"type":"httpget","maxRetries":3,"backoff":"2s","retryable":["503","timeout"]}`,
+			false, 3, 2 * time.Second, 2},
+		{"invalid backoff", `{"type":"httpget","maxRetries":3,"backoff":"soon"}`, true, 0, 0, 0},
+		{"invalid pattern", `{"type":"httpget","maxRetries":3,"retryable":["("]}`, true, 0, 0, 0},
+		{"negative retries", `{"type":"httpget","maxRetries":-1}`, true, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			var task models.Task
+			err := json.Unmarshal([]byte(test.input), &task)
+			assert.Equal(t, test.wantErrored, err != nil)
+			if test.wantErrored {
+				return
+			}
+
+			assert.Equal(t, test.wantMaxRetries, task.Retry.MaxRetries)
+			assert.Equal(t, test.wantBackoff, task.Retry.Backoff)
+			assert.Equal(t, test.wantPatterns, len(task.Retry.Retryable))
+		})
+	}
+}
+
+func TestTask_UnmarshalJSON_Timeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		wantErrored bool
+		wantTimeout time.Duration
+	}{
+		{"no timeout", `{"type":"httpget"}`, false, 0},
+		{"timeout", `{"type":"httpget","timeout":"5s"}`, false, 5 * time.Second},
+		{"invalid timeout", `{"type":"httpget","timeout":"soon"}`, true, 0},
+		{"negative timeout", `{"type":"httpget","timeout":"-5s"}`, true, 0},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			var task models.Task
+			err := json.Unmarshal([]byte(test.input), &task)
+			assert.Equal(t, test.wantErrored, err != nil)
+			assert.Equal(t, test.wantTimeout, task.Timeout)
+		})
+	}
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	t.Parallel()
+
+	var task models.Task
+	input := `{"type":"httpget","maxRetries":2,"retryable":["^503 "]}`
+	assert.Nil(t, json.Unmarshal([]byte(input), &task))
+	rp := task.Retry
+
+	assert.True(t, rp.ShouldRetry(1, "503 Service Unavailable: down"))
+	assert.True(t, rp.ShouldRetry(2, "503 Service Unavailable: down"))
+	assert.False(t, rp.ShouldRetry(3, "503 Service Unavailable: down"))
+	assert.False(t, rp.ShouldRetry(1, "400 Bad Request: nope"))
+
+	assert.True(t, models.RetryPolicy{MaxRetries: 1}.ShouldRetry(1, "anything"))
+	assert.False(t, models.RetryPolicy{}.ShouldRetry(1, "anything"))
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	rp := models.RetryPolicy{Backoff: 100 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, rp.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, rp.Delay(2))
+	assert.Equal(t, 400*time.Millisecond, rp.Delay(3))
+	assert.Equal(t, time.Second, models.RetryPolicy{}.Delay(1))
+
+	assert.Equal(t, time.Hour, rp.Delay(100), "the delay stops doubling at an hour")
+	assert.Equal(t, time.Hour, rp.Delay(^uint(0)))
+	long := models.RetryPolicy{Backoff: 2 * time.Hour}
+	assert.Equal(t, 2*time.Hour, long.Delay(5), "a longer backoff is not doubled")
+}
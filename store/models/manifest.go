@@ -0,0 +1,160 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// manifestID is the key the node's manifest is saved under, as a node has
+// at most one.
+const manifestID = 1
+
+// Manifest declares the state a node is meant to be in: the IDs of its
+// active jobs, its bridges, the addresses of its keys, and the digest of
+// its config. A list that is left out, or an empty ConfigDigest, is not
+// checked, so that a manifest can declare only part of the node's state.
+type Manifest struct {
+	ID           int              `json:"-" storm:"id"`
+	Jobs         []string         `json:"jobs,omitempty"`
+	Bridges      []BridgeType     `json:"bridges,omitempty"`
+	KeyAddresses []common.Address `json:"keyAddresses,omitempty"`
+	ConfigDigest string           `json:"configDigest,omitempty"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+}
+
+// Drift lists how a node's actual state differs from its manifest. Jobs,
+// bridges, and keys that the manifest declares but the node does not have
+// are missing, and those the node has but the manifest does not declare
+// are unexpected. ConfigDigest is the node's digest, if it differs.
+type Drift struct {
+	MissingJobs       []string         `json:"missingJobs,omitempty"`
+	UnexpectedJobs    []string         `json:"unexpectedJobs,omitempty"`
+	MissingBridges    []string         `json:"missingBridges,omitempty"`
+	UnexpectedBridges []string         `json:"unexpectedBridges,omitempty"`
+	ChangedBridges    []string         `json:"changedBridges,omitempty"`
+	MissingKeys       []common.Address `json:"missingKeys,omitempty"`
+	UnexpectedKeys    []common.Address `json:"unexpectedKeys,omitempty"`
+	ConfigDigest      string           `json:"configDigest,omitempty"`
+}
+
+// Compare returns how the actual state differs from the manifest, with
+// each list sorted.
+func (m Manifest) Compare(actual Manifest) Drift {
+	var d Drift
+	if m.Jobs != nil {
+		d.MissingJobs, d.UnexpectedJobs = diffStrings(m.Jobs, actual.Jobs)
+	}
+	if m.Bridges != nil {
+		d.MissingBridges, d.UnexpectedBridges = diffStrings(bridgeNames(m.Bridges), bridgeNames(actual.Bridges))
+		urls := map[string]string{}
+		for _, bt := range actual.Bridges {
+			urls[strings.ToLower(bt.Name)] = bridgeURL(bt)
+		}
+		for _, bt := range m.Bridges {
+			url, ok := urls[strings.ToLower(bt.Name)]
+			if ok && url != bridgeURL(bt) {
+				d.ChangedBridges = append(d.ChangedBridges, strings.ToLower(bt.Name))
+			}
+		}
+		sort.Strings(d.ChangedBridges)
+	}
+	if m.KeyAddresses != nil {
+		missing, unexpected := diffStrings(addressStrings(m.KeyAddresses), addressStrings(actual.KeyAddresses))
+		d.MissingKeys = hexAddresses(missing)
+		d.UnexpectedKeys = hexAddresses(unexpected)
+	}
+	if m.ConfigDigest != "" && !strings.EqualFold(m.ConfigDigest, actual.ConfigDigest) {
+		d.ConfigDigest = actual.ConfigDigest
+	}
+	return d
+}
+
+// Jobs returns how many jobs are missing or unexpected.
+func (d Drift) Jobs() int {
+	return len(d.MissingJobs) + len(d.UnexpectedJobs)
+}
+
+// Bridges returns how many bridges are missing, unexpected, or changed.
+func (d Drift) Bridges() int {
+	return len(d.MissingBridges) + len(d.UnexpectedBridges) + len(d.ChangedBridges)
+}
+
+// Keys returns how many keys are missing or unexpected.
+func (d Drift) Keys() int {
+	return len(d.MissingKeys) + len(d.UnexpectedKeys)
+}
+
+// Config returns 1 if the config digest differs, and 0 otherwise.
+func (d Drift) Config() int {
+	if d.ConfigDigest != "" {
+		return 1
+	}
+	return 0
+}
+
+// Drifted returns true if the node's state differs from its manifest in
+// any way.
+func (d Drift) Drifted() bool {
+	return d.Jobs()+d.Bridges()+d.Keys()+d.Config() > 0
+}
+
+// diffStrings returns the sorted values of want that are not in got, and
+// those of got that are not in want.
+func diffStrings(want, got []string) ([]string, []string) {
+	wanted := map[string]bool{}
+	for _, s := range want {
+		wanted[s] = true
+	}
+	have := map[string]bool{}
+	for _, s := range got {
+		have[s] = true
+	}
+	var missing, unexpected []string
+	for s := range wanted {
+		if !have[s] {
+			missing = append(missing, s)
+		}
+	}
+	for s := range have {
+		if !wanted[s] {
+			unexpected = append(unexpected, s)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
+
+func bridgeNames(bridges []BridgeType) []string {
+	names := []string{}
+	for _, bt := range bridges {
+		names = append(names, strings.ToLower(bt.Name))
+	}
+	return names
+}
+
+func bridgeURL(bt BridgeType) string {
+	if bt.URL.URL == nil {
+		return ""
+	}
+	return bt.URL.String()
+}
+
+func addressStrings(addresses []common.Address) []string {
+	hexes := []string{}
+	for _, a := range addresses {
+		hexes = append(hexes, a.Hex())
+	}
+	return hexes
+}
+
+func hexAddresses(hexes []string) []common.Address {
+	var addresses []common.Address
+	for _, h := range hexes {
+		addresses = append(addresses, common.HexToAddress(h))
+	}
+	return addresses
+}
@@ -0,0 +1,80 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest_Compare(t *testing.T) {
+	t.Parallel()
+
+	key1 := common.HexToAddress("0x3cCad4715152693fE3BC4460591e3D3Fbd071b42")
+	key2 := common.HexToAddress("0x2fCeA879fDC9FE5e90394faf0CA644a1749d0ad6")
+	actual := models.Manifest{
+		Jobs: []string{"a", "b"},
+		Bridges: []models.BridgeType{
+			cltest.NewBridgeType("price", "https://price.example.com"),
+			cltest.NewBridgeType("extra", "https://extra.example.com"),
+		},
+		KeyAddresses: []common.Address{key1},
+		ConfigDigest: "abc",
+	}
+
+	tests := []struct {
+		name     string
+		manifest models.Manifest
+		want     models.Drift
+	}{
+		{"empty", models.Manifest{}, models.Drift{}},
+		{"matching", actual, models.Drift{}},
+		{
+			"jobs",
+			models.Manifest{Jobs: []string{"b", "c"}},
+			models.Drift{MissingJobs: []string{"c"}, UnexpectedJobs: []string{"a"}},
+		},
+		{"no jobs", models.Manifest{Jobs: []string{}}, models.Drift{UnexpectedJobs: []string{"a", "b"}}},
+		{
+			"bridges",
+			models.Manifest{Bridges: []models.BridgeType{
+				cltest.NewBridgeType("Price", "https://moved.example.com"),
+				cltest.NewBridgeType("gone", "https://gone.example.com"),
+			}},
+			models.Drift{
+				MissingBridges:    []string{"gone"},
+				UnexpectedBridges: []string{"extra"},
+				ChangedBridges:    []string{"price"},
+			},
+		},
+		{
+			"keys",
+			models.Manifest{KeyAddresses: []common.Address{key2}},
+			models.Drift{MissingKeys: []common.Address{key2}, UnexpectedKeys: []common.Address{key1}},
+		},
+		{"config", models.Manifest{ConfigDigest: "def"}, models.Drift{ConfigDigest: "abc"}},
+		{"config case", models.Manifest{ConfigDigest: "ABC"}, models.Drift{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			drift := test.manifest.Compare(actual)
+			assert.Equal(t, test.want, drift)
+			assert.Equal(t, test.want.Drifted(), drift.Drifted())
+		})
+	}
+}
+
+func TestDrift_Drifted(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, models.Drift{}.Drifted())
+	d := models.Drift{MissingJobs: []string{"a"}, ChangedBridges: []string{"b"}, ConfigDigest: "c"}
+	assert.True(t, d.Drifted())
+	assert.Equal(t, 1, d.Jobs())
+	assert.Equal(t, 1, d.Bridges())
+	assert.Equal(t, 0, d.Keys())
+	assert.Equal(t, 1, d.Config())
+}
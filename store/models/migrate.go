@@ -1,17 +1,32 @@
 package models
 
 import (
+	"fmt"
 	"log"
+
+	"github.com/asdine/storm"
 )
 
 func (orm ORM) migrate() {
 	orm.initializeModel(&Job{})
 	orm.initializeModel(&JobRun{})
+	orm.initializeModel(&SkippedRun{})
 	orm.initializeModel(&Initiator{})
 	orm.initializeModel(&Tx{})
 	orm.initializeModel(&TxAttempt{})
+	orm.initializeModel(&BatchedCall{})
 	orm.initializeModel(&BridgeType{})
 	orm.initializeModel(&BlockHeader{})
+	orm.initializeModel(&User{})
+	orm.initializeModel(&Session{})
+	orm.initializeModel(&APIToken{})
+	orm.initializeModel(&ExternalInitiator{})
+	orm.initializeModel(&AddressList{})
+	orm.initializeModel(&Credential{})
+	orm.initializeModel(&ConfigChange{})
+	orm.initializeModel(&AuditEntry{})
+	orm.initializeModel(&Heartbeat{})
+	orm.initializeModel(&OutboxEvent{})
 }
 
 func (orm ORM) initializeModel(klass interface{}) {
@@ -20,3 +35,113 @@ func (orm ORM) initializeModel(klass interface{}) {
 		log.Fatal(err)
 	}
 }
+
+// migrationsBucket holds the version of the last migration applied.
+const migrationsBucket = "migrations"
+
+// Migration changes how saved data is laid out between releases. Up moves
+// the data to the new layout, and Down moves it back so that the release
+// can be rolled back. Each runs in the same transaction that records the
+// version it leaves the data at, so a failed migration changes nothing.
+type Migration struct {
+	Version     uint64
+	Description string
+	Up          func(tx storm.Node) error
+	Down        func(tx storm.Node) error
+}
+
+// Migrations are the migrations released so far, in order of Version.
+// Released migrations must not be changed or removed, only added to.
+var Migrations = []Migration{}
+
+// MigrationVersion returns the version of the last migration applied, or
+// 0 if none have been.
+func (orm *ORM) MigrationVersion() (uint64, error) {
+	return migrationVersion(orm.DB)
+}
+
+func migrationVersion(node storm.Node) (uint64, error) {
+	var version uint64
+	err := node.Get(migrationsBucket, "version", &version)
+	if err == storm.ErrNotFound {
+		return 0, nil
+	}
+	return version, err
+}
+
+// MigrateUp applies the migrations newer than the last one applied, in
+// order, stopping at the first to fail.
+func (orm *ORM) MigrateUp(migrations []Migration) error {
+	if err := checkMigrations(migrations); err != nil {
+		return err
+	}
+	version, err := orm.MigrationVersion()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := orm.applyMigration(m.Up, m.Version); err != nil {
+			return fmt.Errorf("Migrating up to %v (%v): %v", m.Version, m.Description, err)
+		}
+	}
+	return nil
+}
+
+// MigrateDown rolls back the migrations applied after the given version,
+// newest first, stopping at the first to fail.
+func (orm *ORM) MigrateDown(migrations []Migration, to uint64) error {
+	if err := checkMigrations(migrations); err != nil {
+		return err
+	}
+	version, err := orm.MigrationVersion()
+	if err != nil {
+		return err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= to || m.Version > version {
+			continue
+		}
+		if m.Down == nil {
+			return fmt.Errorf("Migration %v (%v) cannot be rolled back", m.Version, m.Description)
+		}
+		var previous uint64
+		if i > 0 {
+			previous = migrations[i-1].Version
+		}
+		if err := orm.applyMigration(m.Down, previous); err != nil {
+			return fmt.Errorf("Migrating down from %v (%v): %v", m.Version, m.Description, err)
+		}
+	}
+	return nil
+}
+
+func (orm *ORM) applyMigration(change func(storm.Node) error, version uint64) error {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := change(tx); err != nil {
+		return err
+	}
+	if err := tx.Set(migrationsBucket, "version", version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func checkMigrations(migrations []Migration) error {
+	var last uint64
+	for _, m := range migrations {
+		if m.Version <= last {
+			return fmt.Errorf("Migration %v (%v) is out of order", m.Version, m.Description)
+		}
+		last = m.Version
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asdine/storm"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func setKV(value string) func(storm.Node) error {
+	return func(tx storm.Node) error {
+		return tx.Set("migration_test", "value", value)
+	}
+}
+
+func getKV(orm *models.ORM) string {
+	var value string
+	orm.Get("migration_test", "value", &value)
+	return value
+}
+
+func TestORM_MigrateUpAndDown(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	migrations := []models.Migration{
+		{Version: 1, Description: "first", Up: setKV("1"), Down: setKV("")},
+		{Version: 3, Description: "second", Up: setKV("3"), Down: setKV("1")},
+	}
+
+	version, err := store.MigrationVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), version)
+
+	assert.Nil(t, store.MigrateUp(migrations[:1]))
+	version, _ = store.MigrationVersion()
+	assert.Equal(t, uint64(1), version)
+	assert.Equal(t, "1", getKV(store.ORM))
+
+	assert.Nil(t, store.MigrateUp(migrations))
+	version, _ = store.MigrationVersion()
+	assert.Equal(t, uint64(3), version)
+	assert.Equal(t, "3", getKV(store.ORM))
+
+	assert.Nil(t, store.MigrateDown(migrations, 1))
+	version, _ = store.MigrationVersion()
+	assert.Equal(t, uint64(1), version)
+	assert.Equal(t, "1", getKV(store.ORM))
+
+	assert.Nil(t, store.MigrateDown(migrations, 0))
+	version, _ = store.MigrationVersion()
+	assert.Equal(t, uint64(0), version)
+	assert.Equal(t, "", getKV(store.ORM))
+}
+
+func TestORM_MigrateUp_Failure(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	migrations := []models.Migration{
+		{Version: 1, Description: "first", Up: setKV("1")},
+		{Version: 2, Description: "broken", Up: func(tx storm.Node) error {
+			if err := setKV("2")(tx); err != nil {
+				return err
+			}
+			return errors.New("bad data")
+		}},
+	}
+
+	err := store.MigrateUp(migrations)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "bad data")
+	version, _ := store.MigrationVersion()
+	assert.Equal(t, uint64(1), version)
+	assert.Equal(t, "1", getKV(store.ORM))
+
+	err = store.MigrateDown(migrations, 0)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot be rolled back")
+}
+
+func TestORM_MigrateUp_OutOfOrder(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	migrations := []models.Migration{
+		{Version: 2, Description: "second", Up: setKV("2")},
+		{Version: 1, Description: "first", Up: setKV("1")},
+	}
+	assert.NotNil(t, store.MigrateUp(migrations))
+	version, _ := store.MigrationVersion()
+	assert.Equal(t, uint64(0), version)
+}
@@ -1,17 +1,21 @@
 package models
 
 import (
+	"fmt"
 	"log"
 	"math/big"
 	"path"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/asdine/storm"
 	"github.com/asdine/storm/q"
+	bolt "github.com/coreos/bbolt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/smartcontractkit/chainlink/utils"
+	null "gopkg.in/guregu/null.v3"
 )
 
 // ORM contains the database object used by Chainlink.
@@ -36,6 +40,11 @@ func initializeDatabase(path string) *storm.DB {
 	return db
 }
 
+// Ping returns an error unless the database is open and can be read.
+func (orm *ORM) Ping() error {
+	return orm.Bolt.View(func(*bolt.Tx) error { return nil })
+}
+
 // Where fetches multiple objects with "Find" in Storm.
 func (orm *ORM) Where(field string, value interface{}, instance interface{}) error {
 	err := orm.Find(field, value, instance)
@@ -78,6 +87,43 @@ func (orm *ORM) Jobs() ([]Job, error) {
 	return jobs, err
 }
 
+// JobsPage fetches up to limit jobs ordered by when they were created,
+// after skipping the first offset. A limit of 0 fetches every remaining
+// job. When initiatorType is given, only jobs with an initiator of that
+// type are included. The total number of matching jobs is also returned.
+func (orm *ORM) JobsPage(offset, limit int, newestFirst bool, initiatorType string) ([]Job, int, error) {
+	var jobs []Job
+	var err error
+	if newestFirst {
+		err = orm.AllByIndex("CreatedAt", &jobs, storm.Reverse())
+	} else {
+		err = orm.AllByIndex("CreatedAt", &jobs)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if initiatorType != "" {
+		matching := []Job{}
+		for _, j := range jobs {
+			if len(j.InitiatorsFor(strings.ToLower(initiatorType))) > 0 {
+				matching = append(matching, j)
+			}
+		}
+		jobs = matching
+	}
+
+	total := len(jobs)
+	if offset >= total {
+		return []Job{}, total, nil
+	}
+	jobs = jobs[offset:]
+	if limit > 0 && limit < len(jobs) {
+		jobs = jobs[:limit]
+	}
+	return jobs, total, nil
+}
+
 // JobRunsFor fetches all JobRuns with a given Job ID,
 // sorted by their created at time.
 func (orm *ORM) JobRunsFor(jobID string) ([]JobRun, error) {
@@ -89,6 +135,328 @@ func (orm *ORM) JobRunsFor(jobID string) ([]JobRun, error) {
 	return runs, err
 }
 
+// UnfinishedRunsFor fetches the given job's runs that are queued, in
+// progress, or pending.
+func (orm *ORM) UnfinishedRunsFor(jobID string) ([]JobRun, error) {
+	runs := []JobRun{}
+	err := orm.Select(
+		q.Eq("JobID", jobID),
+		q.In("Status", []string{StatusQueued, StatusInProgress, StatusPending}),
+	).Find(&runs)
+	if err == storm.ErrNotFound {
+		return []JobRun{}, nil
+	}
+	return runs, err
+}
+
+// JobRunFilter narrows down the JobRuns fetched by JobRunsPage. Empty
+// fields match every run, and runs created at From are included while
+// runs created at To are not.
+type JobRunFilter struct {
+	JobID  string
+	Status string
+	From   time.Time
+	To     time.Time
+}
+
+// JobRunsPage fetches up to limit JobRuns matching the filter, newest first,
+// after skipping the first offset. A limit of 0 fetches every remaining run.
+// The total number of matching runs is also returned.
+func (orm *ORM) JobRunsPage(filter JobRunFilter, offset, limit int) ([]JobRun, int, error) {
+	matchers := []q.Matcher{}
+	if filter.JobID != "" {
+		matchers = append(matchers, q.Eq("JobID", filter.JobID))
+	}
+	if filter.Status != "" {
+		matchers = append(matchers, q.Eq("Status", filter.Status))
+	}
+
+	runs := []JobRun{}
+	err := orm.Select(matchers...).OrderBy("CreatedAt").Reverse().Find(&runs)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, 0, err
+	}
+
+	matching := []JobRun{}
+	for _, jr := range runs {
+		if !filter.From.IsZero() && jr.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !jr.CreatedAt.Before(filter.To) {
+			continue
+		}
+		matching = append(matching, jr)
+	}
+
+	total := len(matching)
+	if offset >= total {
+		return []JobRun{}, total, nil
+	}
+	matching = matching[offset:]
+	if limit > 0 && limit < len(matching) {
+		matching = matching[:limit]
+	}
+	return matching, total, nil
+}
+
+// LastFulfillment returns when the most recently completed JobRun completed,
+// or an invalid time if no run has completed yet.
+func (orm *ORM) LastFulfillment() (null.Time, error) {
+	runs := []JobRun{}
+	err := orm.Select(q.Eq("Status", StatusCompleted)).Find(&runs)
+	if err != nil && err != storm.ErrNotFound {
+		return null.Time{}, err
+	}
+	last := null.Time{}
+	for _, jr := range runs {
+		if jr.CompletedAt.Valid && (!last.Valid || jr.CompletedAt.Time.After(last.Time)) {
+			last = jr.CompletedAt
+		}
+	}
+	return last, nil
+}
+
+// SkippedRunsFor fetches the runs skipped while the given job was paused
+// that have not been resumed yet, oldest first.
+func (orm *ORM) SkippedRunsFor(jobID string) ([]SkippedRun, error) {
+	skipped := []SkippedRun{}
+	err := orm.Select(
+		q.Eq("JobID", jobID),
+		q.Eq("Resumed", false),
+		q.Not(q.Eq("Reason", SkipReasonOverlap)),
+	).OrderBy("ID").Find(&skipped)
+	if err == storm.ErrNotFound {
+		return []SkippedRun{}, nil
+	}
+	return skipped, err
+}
+
+// SaveSkippedRun saves the skipped run, or, unless it is Replayable, counts
+// it in the job's unresumed SkippedRun of the same initiator type and
+// reason, if there is one, so that an initiator that keeps firing does not
+// add a SkippedRun each time.
+func (orm *ORM) SaveSkippedRun(sr *SkippedRun) error {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if !sr.Replayable() {
+		last := SkippedRun{}
+		err := tx.Select(
+			q.Eq("JobID", sr.JobID),
+			q.Eq("InitiatorType", sr.InitiatorType),
+			q.Eq("Reason", sr.Reason),
+			q.Eq("Resumed", false),
+		).First(&last)
+		if err == nil {
+			last.Count += sr.Count
+			last.Input = sr.Input
+			last.UpdatedAt = sr.UpdatedAt
+			*sr = last
+		} else if err != storm.ErrNotFound {
+			return err
+		}
+	}
+	if err := tx.Save(sr); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PruneSkippedRuns deletes the skipped runs that have been resumed, and
+// those last skipped before the given time, unless it is zero, other than
+// log runs still waiting to be replayed. It returns how many were deleted.
+func (orm *ORM) PruneSkippedRuns(before time.Time) (int, error) {
+	skipped := []SkippedRun{}
+	if err := orm.All(&skipped); err != nil {
+		return 0, err
+	}
+
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	pruned := 0
+	for i, sr := range skipped {
+		waiting := sr.Replayable() && !sr.Resumed
+		tooOld := !before.IsZero() && sr.UpdatedAt.Before(before)
+		if sr.Resumed || (tooOld && !waiting) {
+			if err := tx.DeleteStruct(&skipped[i]); err != nil {
+				return 0, err
+			}
+			pruned++
+		}
+	}
+	return pruned, tx.Commit()
+}
+
+// ConfigChangesPage fetches up to limit ConfigChanges, newest first, after
+// skipping the first offset. A limit of 0 fetches every remaining change.
+// The total number of changes is also returned.
+func (orm *ORM) ConfigChangesPage(offset, limit int) ([]ConfigChange, int, error) {
+	changes := []ConfigChange{}
+	if err := orm.AllByIndex("ID", &changes, storm.Reverse()); err != nil {
+		return nil, 0, err
+	}
+
+	total := len(changes)
+	if offset >= total {
+		return []ConfigChange{}, total, nil
+	}
+	changes = changes[offset:]
+	if limit > 0 && limit < len(changes) {
+		changes = changes[:limit]
+	}
+	return changes, total, nil
+}
+
+// AuditEntriesPage returns a page of the audit log, newest first, and the
+// total number of entries.
+func (orm *ORM) AuditEntriesPage(offset, limit int) ([]AuditEntry, int, error) {
+	entries := []AuditEntry{}
+	if err := orm.AllByIndex("ID", &entries, storm.Reverse()); err != nil {
+		return nil, 0, err
+	}
+
+	total := len(entries)
+	if offset >= total {
+		return []AuditEntry{}, total, nil
+	}
+	entries = entries[offset:]
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries, total, nil
+}
+
+// AuditEntries returns the whole audit log, oldest first.
+func (orm *ORM) AuditEntries() ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+	err := orm.AllByIndex("ID", &entries)
+	return entries, err
+}
+
+// HeartbeatsSince returns the heartbeats sent at or after the given time.
+func (orm *ORM) HeartbeatsSince(t time.Time) ([]Heartbeat, error) {
+	heartbeats := []Heartbeat{}
+	err := orm.Select(q.Gte("CreatedAt", t)).OrderBy("ID").Find(&heartbeats)
+	if err == storm.ErrNotFound {
+		return []Heartbeat{}, nil
+	}
+	return heartbeats, err
+}
+
+// PruneJobRuns deletes the finished runs of every job that were created
+// before the given time, and those beyond the newest keep finished runs of
+// their job, along with their task runs, and returns how many were
+// deleted. A zero time or keep of 0 does not limit runs by that measure.
+// Runs still in progress or pending are never deleted.
+func (orm *ORM) PruneJobRuns(before time.Time, keep int) (int, error) {
+	kept := map[string]int{}
+	pruned := 0
+	first := true
+	var boundary time.Time
+	atBoundary := map[string]bool{}
+	for {
+		runs := []JobRun{}
+		var err error
+		if first {
+			err = orm.AllByIndex("CreatedAt", &runs, storm.Limit(pruneJobRunsPageSize), storm.Reverse())
+		} else {
+			limit := storm.Limit(pruneJobRunsPageSize + len(atBoundary))
+			err = orm.Range("CreatedAt", time.Time{}, boundary, &runs, limit, storm.Reverse())
+		}
+		if err != nil && err != storm.ErrNotFound {
+			return pruned, err
+		}
+		first = false
+
+		page := []JobRun{}
+		for _, jr := range runs {
+			if !atBoundary[jr.ID] {
+				page = append(page, jr)
+			}
+		}
+		if len(page) == 0 {
+			return pruned, nil
+		}
+		if last := page[len(page)-1].CreatedAt; !last.Equal(boundary) {
+			boundary = last
+			atBoundary = map[string]bool{}
+		}
+		for _, jr := range page {
+			if jr.CreatedAt.Equal(boundary) {
+				atBoundary[jr.ID] = true
+			}
+		}
+
+		count, err := orm.pruneJobRuns(page, before, keep, kept)
+		pruned += count
+		if err != nil {
+			return pruned, err
+		}
+	}
+}
+
+// pruneJobRunsPageSize is how many runs PruneJobRuns reads at a time, so
+// that it does not load every run in the store at once.
+const pruneJobRunsPageSize = 500
+
+// pruneJobRuns deletes the runs PruneJobRuns would from a page of runs,
+// newest first, counting each job's finished runs in kept.
+func (orm *ORM) pruneJobRuns(runs []JobRun, before time.Time, keep int, kept map[string]int) (int, error) {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	pruned := 0
+	for i, jr := range runs {
+		if jr.Status != StatusCompleted && jr.Status != StatusErrored {
+			continue
+		}
+		kept[jr.JobID]++
+		tooOld := !before.IsZero() && jr.CreatedAt.Before(before)
+		tooMany := keep > 0 && kept[jr.JobID] > keep
+		if tooOld || tooMany {
+			if err := tx.DeleteStruct(&runs[i]); err != nil {
+				return 0, err
+			}
+			pruned++
+		}
+	}
+	return pruned, tx.Commit()
+}
+
+// OutboxEvents returns up to limit of the events waiting to be exported,
+// oldest first.
+func (orm *ORM) OutboxEvents(limit int) ([]OutboxEvent, error) {
+	events := []OutboxEvent{}
+	err := orm.AllByIndex("ID", &events, storm.Limit(limit))
+	return events, err
+}
+
+// DeleteOutboxEvents removes events that have been exported from the
+// outbox.
+func (orm *ORM) DeleteOutboxEvents(events []OutboxEvent) error {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i := range events {
+		if err := tx.DeleteStruct(&events[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 // SaveJob saves a job to the database.
 func (orm *ORM) SaveJob(job *Job) error {
 	tx, err := orm.Begin(true)
@@ -97,10 +465,9 @@ func (orm *ORM) SaveJob(job *Job) error {
 	}
 	defer tx.Rollback()
 
-	for i, initr := range job.Initiators {
+	for i := range job.Initiators {
 		job.Initiators[i].JobID = job.ID
-		initr.JobID = job.ID
-		if err := tx.Save(&initr); err != nil {
+		if err := tx.Save(&job.Initiators[i]); err != nil {
 			return err
 		}
 	}
@@ -110,6 +477,69 @@ func (orm *ORM) SaveJob(job *Job) error {
 	return tx.Commit()
 }
 
+// SaveInitiator saves the initiator, along with the copy of it kept in its
+// job.
+func (orm *ORM) SaveInitiator(initr *Initiator) error {
+	if initr.ID == 0 {
+		return fmt.Errorf("Initiator of job %v has not been saved", initr.JobID)
+	}
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var job Job
+	if err := tx.One("ID", initr.JobID, &job); err != nil {
+		return err
+	}
+	for i := range job.Initiators {
+		if job.Initiators[i].ID == initr.ID {
+			job.Initiators[i] = *initr
+		}
+	}
+	if err := tx.Save(initr); err != nil {
+		return err
+	}
+	if err := tx.Save(&job); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TransitionJobRun moves the run to the given status and saves it. The
+// saved run is checked in the same transaction, so that a run that has
+// since moved on, such as one resumed twice, is not saved over.
+func (orm *ORM) TransitionJobRun(run *JobRun, to string) error {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var saved JobRun
+	err = tx.One("ID", run.ID, &saved)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	if err == nil && saved.Status != run.Status {
+		return fmt.Errorf("JobRun %v is %q, not %q", run.ID, saved.Status, run.Status)
+	}
+
+	next := *run
+	if err := next.Transition(to); err != nil {
+		return err
+	}
+	if err := tx.Save(&next); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*run = next
+	return nil
+}
+
 // PendingJobRuns returns the JobRuns which have a status of "pending".
 func (orm *ORM) PendingJobRuns() ([]JobRun, error) {
 	runs := []JobRun{}
@@ -209,3 +639,187 @@ func (orm *ORM) BridgeTypeFor(name string) (BridgeType, error) {
 	err := orm.One("Name", strings.ToLower(name), &tt)
 	return tt, err
 }
+
+// FeedsForJob returns the feeds the job is a source of.
+func (orm *ORM) FeedsForJob(jobID string) ([]Feed, error) {
+	var all []Feed
+	if err := orm.All(&all); err != nil {
+		return nil, err
+	}
+	feeds := []Feed{}
+	for _, f := range all {
+		if f.HasJob(jobID) {
+			feeds = append(feeds, f)
+		}
+	}
+	return feeds, nil
+}
+
+// SeedUser creates an admin with the given credentials if the node does
+// not have any users yet. Once created, the stored credentials are used.
+func (orm *ORM) SeedUser(email, password string) error {
+	var users []User
+	if err := orm.All(&users); err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return nil
+	}
+	user, err := NewUser(email, password, RoleAdmin)
+	if err != nil {
+		return err
+	}
+	return orm.Save(&user)
+}
+
+// FindUser looks up a User by their email.
+func (orm *ORM) FindUser(email string) (User, error) {
+	var user User
+	err := orm.One("Email", strings.ToLower(email), &user)
+	return user, err
+}
+
+// DeleteUser deletes the given user along with their sessions.
+func (orm *ORM) DeleteUser(user User) error {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.Select(q.Eq("Email", user.Email)).Delete(&Session{})
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	if err := tx.DeleteStruct(&user); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindSession looks up a Session by its ID.
+func (orm *ORM) FindSession(id string) (Session, error) {
+	var session Session
+	err := orm.One("ID", id, &session)
+	return session, err
+}
+
+// FindAPIToken looks up the APIToken matching the given token.
+func (orm *ORM) FindAPIToken(token string) (APIToken, error) {
+	var apiToken APIToken
+	err := orm.One("HashedToken", HashAPIToken(token), &apiToken)
+	return apiToken, err
+}
+
+// FindExternalInitiator looks up an ExternalInitiator by its name.
+func (orm *ORM) FindExternalInitiator(name string) (ExternalInitiator, error) {
+	var ei ExternalInitiator
+	err := orm.One("Name", strings.ToLower(name), &ei)
+	return ei, err
+}
+
+// FindExternalInitiatorByAccessKey looks up the ExternalInitiator with the
+// given access key.
+func (orm *ORM) FindExternalInitiatorByAccessKey(accessKey string) (ExternalInitiator, error) {
+	var ei ExternalInitiator
+	err := orm.One("AccessKey", accessKey, &ei)
+	return ei, err
+}
+
+// FindAddressList looks up an AddressList by its name.
+func (orm *ORM) FindAddressList(name string) (AddressList, error) {
+	var list AddressList
+	err := orm.One("Name", strings.ToLower(name), &list)
+	return list, err
+}
+
+// FindCredential looks up a Credential by its name.
+func (orm *ORM) FindCredential(name string) (Credential, error) {
+	var credential Credential
+	err := orm.One("Name", strings.ToLower(strings.TrimSpace(name)), &credential)
+	return credential, err
+}
+
+// LogAddressesFor returns the contract addresses the log initiator listens
+// to: its Address, its Addresses, and those of its AddressList. None means
+// it listens to every contract, so an AddressList that is empty is an
+// error rather than none.
+func (orm *ORM) LogAddressesFor(initr Initiator) ([]common.Address, error) {
+	addresses := []common.Address{}
+	if initr.Address != (common.Address{}) {
+		addresses = append(addresses, initr.Address)
+	}
+	addresses = append(addresses, initr.Addresses...)
+	if initr.AddressList == "" {
+		return addresses, nil
+	}
+	list, err := orm.FindAddressList(initr.AddressList)
+	if err != nil {
+		return nil, fmt.Errorf("Finding address list %v: %v", initr.AddressList, err)
+	}
+	if len(list.Addresses) == 0 {
+		return nil, fmt.Errorf("Address list %v is empty", list.Name)
+	}
+	return append(addresses, list.Addresses...), nil
+}
+
+// kvBucket is the bucket used for small pieces of state that adapters
+// keep between runs.
+const kvBucket = "KeyValue"
+
+// GetKV loads the value stored under the given key into value, returning
+// storm.ErrNotFound if nothing has been stored.
+func (orm *ORM) GetKV(key string, value interface{}) error {
+	return orm.Get(kvBucket, key, value)
+}
+
+// SetKV stores the value under the given key, replacing any previous value.
+func (orm *ORM) SetKV(key string, value interface{}) error {
+	return orm.Set(kvBucket, key, value)
+}
+
+// UpdateKV loads the value stored under the given key into value, leaving
+// it as it is if nothing has been stored, and stores it again once update
+// has changed it, in one transaction, so that updates of the same key from
+// runs at the same time are not lost. Nothing is stored if update errors.
+func (orm *ORM) UpdateKV(key string, value interface{}, update func() error) error {
+	tx, err := orm.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Get(kvBucket, key, value); err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	if err := tx.Set(kvBucket, key, value); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Manifest returns the node's manifest, or storm.ErrNotFound if none has
+// been saved.
+func (orm *ORM) Manifest() (Manifest, error) {
+	var m Manifest
+	err := orm.One("ID", manifestID, &m)
+	return m, err
+}
+
+// SaveManifest replaces the node's manifest.
+func (orm *ORM) SaveManifest(m *Manifest) error {
+	m.ID = manifestID
+	return orm.Save(m)
+}
+
+// DeleteManifest deletes the node's manifest, if it has one.
+func (orm *ORM) DeleteManifest() error {
+	err := orm.DeleteStruct(&Manifest{ID: manifestID})
+	if err == storm.ErrNotFound {
+		return nil
+	}
+	return err
+}
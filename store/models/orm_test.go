@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
@@ -79,6 +80,232 @@ func TestPendingJobRuns(t *testing.T) {
 	assert.NotContains(t, pendingIDs, npr.ID)
 }
 
+func TestORM_UnfinishedRunsFor(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	assert.Nil(t, store.SaveJob(&j))
+	ids := map[string]string{}
+	for _, status := range []string{models.StatusInProgress, models.StatusPending, models.StatusCompleted, models.StatusErrored} {
+		jr := j.NewRun()
+		jr.Status = status
+		assert.Nil(t, store.Save(&jr))
+		ids[status] = jr.ID
+	}
+	other := models.NewJob().NewRun()
+	other.Status = models.StatusInProgress
+	assert.Nil(t, store.Save(&other))
+
+	unfinished, err := store.UnfinishedRunsFor(j.ID)
+	assert.Nil(t, err)
+	unfinishedIDs := []string{}
+	for _, jr := range unfinished {
+		unfinishedIDs = append(unfinishedIDs, jr.ID)
+	}
+	assert.ElementsMatch(t, []string{ids[models.StatusInProgress], ids[models.StatusPending]}, unfinishedIDs)
+}
+
+func TestORM_SkippedRunsFor_Paused(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	paused := models.SkippedRun{JobID: j.ID, Reason: models.SkipReasonPaused}
+	assert.Nil(t, store.Save(&paused))
+	overlap := models.SkippedRun{JobID: j.ID, Reason: models.SkipReasonOverlap}
+	assert.Nil(t, store.Save(&overlap))
+
+	skipped, err := store.SkippedRunsFor(j.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(skipped))
+	assert.Equal(t, paused.ID, skipped[0].ID)
+}
+
+func TestORM_SaveSkippedRun(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	skip := func(initiatorType, reason, value string) {
+		sr := models.SkippedRun{
+			JobID:         j.ID,
+			InitiatorType: initiatorType,
+			Reason:        reason,
+			Input:         cltest.RunResultWithValue(value),
+			Count:         1,
+		}
+		assert.Nil(t, store.SaveSkippedRun(&sr))
+	}
+	skip(models.InitiatorCron, models.SkipReasonOverlap, "1")
+	skip(models.InitiatorCron, models.SkipReasonOverlap, "2")
+	skip(models.InitiatorCron, models.SkipReasonPaused, "3")
+	skip(models.InitiatorEthLog, models.SkipReasonPaused, "4")
+	skip(models.InitiatorEthLog, models.SkipReasonPaused, "5")
+
+	skipped := []models.SkippedRun{}
+	assert.Nil(t, store.Where("JobID", j.ID, &skipped))
+	assert.Equal(t, 4, len(skipped))
+	assert.Equal(t, 2, skipped[0].Count)
+	assert.Equal(t, "2", skipped[0].Input.Data.Get("value").String())
+	for _, sr := range skipped[1:] {
+		assert.Equal(t, 1, sr.Count)
+	}
+}
+
+func TestORM_PruneSkippedRuns(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	start := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+	newSkipped := func(initiatorType, reason string, resumed bool, age time.Duration) models.SkippedRun {
+		sr := models.SkippedRun{
+			JobID:         j.ID,
+			InitiatorType: initiatorType,
+			Reason:        reason,
+			Resumed:       resumed,
+			UpdatedAt:     start.Add(-age),
+		}
+		assert.Nil(t, store.Save(&sr))
+		return sr
+	}
+	newSkipped(models.InitiatorEthLog, models.SkipReasonPaused, true, time.Hour)
+	waiting := newSkipped(models.InitiatorEthLog, models.SkipReasonPaused, false, 48*time.Hour)
+	newSkipped(models.InitiatorCron, models.SkipReasonOverlap, false, 48*time.Hour)
+	recent := newSkipped(models.InitiatorCron, models.SkipReasonPaused, false, time.Hour)
+
+	pruned, err := store.PruneSkippedRuns(time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pruned)
+
+	pruned, err = store.PruneSkippedRuns(start.Add(-24 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pruned)
+
+	skipped := []models.SkippedRun{}
+	assert.Nil(t, store.All(&skipped))
+	ids := []uint64{}
+	for _, sr := range skipped {
+		ids = append(ids, sr.ID)
+	}
+	assert.ElementsMatch(t, []uint64{waiting.ID, recent.ID}, ids)
+}
+
+func TestORM_TransitionJobRun(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	assert.Nil(t, store.SaveJob(&j))
+	jr := j.NewRun()
+
+	assert.Nil(t, store.TransitionJobRun(&jr, models.StatusInProgress))
+	assert.Nil(t, store.TransitionJobRun(&jr, models.StatusPending))
+	saved, err := store.FindJobRun(jr.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusPending, saved.Status)
+
+	assert.NotNil(t, store.TransitionJobRun(&jr, models.StatusCompleted))
+	assert.Equal(t, models.StatusPending, jr.Status)
+
+	stale := saved
+	assert.Nil(t, store.TransitionJobRun(&saved, models.StatusInProgress))
+	assert.NotNil(t, store.TransitionJobRun(&stale, models.StatusInProgress))
+
+	saved, err = store.FindJobRun(jr.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusInProgress, saved.Status)
+}
+
+func TestORM_PruneJobRuns(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j1 := models.NewJob()
+	assert.Nil(t, store.SaveJob(&j1))
+	j2 := models.NewJob()
+	assert.Nil(t, store.SaveJob(&j2))
+
+	start := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+	newRun := func(j models.Job, status string, age time.Duration) models.JobRun {
+		jr := j.NewRun()
+		jr.Status = status
+		jr.CreatedAt = start.Add(-age)
+		assert.Nil(t, store.Save(&jr))
+		return jr
+	}
+	oldPending := newRun(j1, models.StatusPending, 72*time.Hour)
+	old := newRun(j1, models.StatusCompleted, 48*time.Hour)
+	older := newRun(j1, models.StatusErrored, 3*time.Hour)
+	recent := newRun(j1, models.StatusCompleted, 2*time.Hour)
+	newest := newRun(j1, models.StatusCompleted, time.Hour)
+	other := newRun(j2, models.StatusCompleted, 3*time.Hour)
+
+	pruned, err := store.PruneJobRuns(start.Add(-24*time.Hour), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pruned)
+
+	pruned, err = store.PruneJobRuns(time.Time{}, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pruned)
+
+	runs := []models.JobRun{}
+	assert.Nil(t, store.All(&runs))
+	ids := []string{}
+	for _, jr := range runs {
+		ids = append(ids, jr.ID)
+	}
+	assert.ElementsMatch(t, []string{oldPending.ID, recent.ID, newest.ID, other.ID}, ids)
+	assert.NotContains(t, ids, old.ID)
+	assert.NotContains(t, ids, older.ID)
+}
+
+func TestORM_PruneJobRuns_ManyRuns(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	j := models.NewJob()
+	assert.Nil(t, store.SaveJob(&j))
+
+	start := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+	tx, err := store.Begin(true)
+	assert.Nil(t, err)
+	newest := []string{}
+	for i := 0; i < 1200; i++ {
+		jr := j.NewRun()
+		jr.Status = models.StatusCompleted
+		jr.CreatedAt = start.Add(-time.Duration(i/2) * time.Minute)
+		if i >= 400 && i < 1000 {
+			jr.CreatedAt = start.Add(-time.Hour)
+		}
+		assert.Nil(t, tx.Save(&jr))
+		if i < 10 {
+			newest = append(newest, jr.ID)
+		}
+	}
+	assert.Nil(t, tx.Commit())
+
+	pruned, err := store.PruneJobRuns(time.Time{}, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1190, pruned)
+
+	runs := []models.JobRun{}
+	assert.Nil(t, store.All(&runs))
+	ids := []string{}
+	for _, jr := range runs {
+		ids = append(ids, jr.ID)
+	}
+	assert.ElementsMatch(t, newest, ids)
+}
+
 func TestCreatingTx(t *testing.T) {
 	store, cleanup := cltest.NewStore()
 	defer cleanup()
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is an Event saved for export to an external sink. It is
+// deleted once the sink has accepted it, so an event the node has
+// published is sent at least once even if the node restarts or the sink is
+// down for a while. Sinks can use the ID to drop events sent twice.
+type OutboxEvent struct {
+	ID        uint64          `json:"id" storm:"id,increment"`
+	Event     json.RawMessage `json:"event"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
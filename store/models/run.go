@@ -9,14 +9,94 @@ import (
 )
 
 // JobRun tracks the status of a job by holding its TaskRuns and the
-// Result of each Run.
+// Result of each Run. While the run is queued, Result holds the input it
+// was started with. Debug is set on runs sampled by the job's
+// DebugSampleRate, and CompletedAt once the run has completed.
 type JobRun struct {
-	ID        string    `json:"id" storm:"id,index,unique"`
-	JobID     string    `json:"jobId" storm:"index"`
-	Status    string    `json:"status" storm:"index"`
-	CreatedAt time.Time `json:"createdAt" storm:"index"`
-	Result    RunResult `json:"result" storm:"inline"`
-	TaskRuns  []TaskRun `json:"taskRuns" storm:"inline"`
+	ID          string    `json:"id" storm:"id,index,unique"`
+	JobID       string    `json:"jobId" storm:"index"`
+	Status      string    `json:"status" storm:"index"`
+	CreatedAt   time.Time `json:"createdAt" storm:"index"`
+	CompletedAt null.Time `json:"completedAt"`
+	Result      RunResult `json:"result" storm:"inline"`
+	TaskRuns    []TaskRun `json:"taskRuns" storm:"inline"`
+	Debug       bool      `json:"debug,omitempty"`
+}
+
+// runTransitions lists the statuses a JobRun can move to from each
+// status. New runs have no status, and completed and errored runs are
+// finished.
+var runTransitions = map[string][]string{
+	"":               {StatusQueued, StatusInProgress},
+	StatusQueued:     {StatusInProgress, StatusErrored},
+	StatusInProgress: {StatusInProgress, StatusPending, StatusErrored, StatusCompleted},
+	StatusPending:    {StatusInProgress, StatusErrored},
+}
+
+// taskRunTransitions lists the statuses a TaskRun can move to from each
+// status. Errored task runs can be retried, or completed by their onError
+// tasks, but completed task runs are finished.
+var taskRunTransitions = map[string][]string{
+	"":               {StatusInProgress, StatusErrored, StatusCompleted},
+	StatusInProgress: {StatusInProgress, StatusPending, StatusErrored, StatusCompleted},
+	StatusPending:    {StatusInProgress, StatusErrored, StatusCompleted},
+	StatusErrored:    {StatusInProgress, StatusCompleted},
+}
+
+func transition(transitions map[string][]string, kind, id, from, to string) error {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v %v cannot move from %q to %q", kind, id, from, to)
+}
+
+// Transition moves the JobRun to the given status, unless its current
+// status does not allow it.
+func (jr *JobRun) Transition(to string) error {
+	if err := transition(runTransitions, "JobRun", jr.ID, jr.Status, to); err != nil {
+		return err
+	}
+	jr.Status = to
+	return nil
+}
+
+// The reasons a SkippedRun was not started.
+const (
+	// SkipReasonPaused is for an initiator that fired while its job was
+	// paused.
+	SkipReasonPaused = "paused"
+	// SkipReasonOverlap is for a cron initiator that fired while its job's
+	// last run was unfinished, and the job skips overlapping runs.
+	SkipReasonOverlap = "overlap"
+)
+
+// SkippedRun records an initiator that fired without starting a run, and
+// why, along with the input the run would have started with. Runs skipped
+// while the job was paused are set Resumed once it has been resumed, and
+// Replayed if the run was started then. Only skipped log runs can be
+// replayed, so each of them is kept; other skips of the same initiator type
+// for the same reason are counted in one SkippedRun, which keeps the input
+// of the last of them.
+type SkippedRun struct {
+	ID            uint64    `json:"id" storm:"id,increment,index"`
+	JobID         string    `json:"jobId" storm:"index"`
+	InitiatorType string    `json:"initiatorType"`
+	Reason        string    `json:"reason"`
+	Input         RunResult `json:"input"`
+	Count         int       `json:"count"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	Resumed       bool      `json:"resumed"`
+	Replayed      bool      `json:"replayed"`
+}
+
+// Replayable returns true if the run was skipped for a log event while its
+// job was paused, so that it can be started when the job is resumed.
+func (sr SkippedRun) Replayable() bool {
+	isLog := sr.InitiatorType == InitiatorRunLog || sr.InitiatorType == InitiatorEthLog
+	return isLog && sr.Reason == SkipReasonPaused
 }
 
 // ForLogger formats the JobRun for a common formatting in the log.
@@ -57,12 +137,54 @@ func (jr JobRun) NextTaskRun() TaskRun {
 }
 
 // TaskRun stores the Task and represents the status of the
-// Task to be ran.
+// Task to be ran. Input and Trace are only kept for runs sampled for
+// debugging. RecoveredFrom holds the error of a task whose onError tasks
+// completed in its place. Attestation is set on task runs performed by an
+// enclave. RetryAt is when a failed task run waiting to be retried is
+// next attempted, so that the wait outlasts a restart.
 type TaskRun struct {
-	Task   Task      `json:"task"`
-	ID     string    `json:"id" storm:"id,index,unique"`
-	Status string    `json:"status"`
-	Result RunResult `json:"result"`
+	Task          Task         `json:"task"`
+	ID            string       `json:"id" storm:"id,index,unique"`
+	Status        string       `json:"status"`
+	Result        RunResult    `json:"result"`
+	Attempts      uint         `json:"attempts"`
+	RetryAt       null.Time    `json:"retryAt"`
+	Skipped       bool         `json:"skipped"`
+	Input         *RunResult   `json:"input,omitempty"`
+	Trace         *TaskTrace   `json:"trace,omitempty"`
+	RecoveredFrom string       `json:"recoveredFrom,omitempty"`
+	Attestation   *Attestation `json:"attestation,omitempty"`
+}
+
+// TaskTrace is what the adapter of a task run sampled for debugging sent
+// and received: the requests of HTTP and bridge tasks, and the call data
+// of EthTx tasks.
+type TaskTrace struct {
+	Requests []TracedRequest `json:"requests,omitempty"`
+	CallData string          `json:"callData,omitempty"`
+}
+
+// TracedRequest is a request sent by a task, and the status and body of
+// its response, or the error that kept it from getting one. Cached is set
+// when a response cached from an earlier request was used instead.
+type TracedRequest struct {
+	Method   string `json:"method"`
+	URL      string `json:"url"`
+	Body     string `json:"body,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Cached   bool   `json:"cached,omitempty"`
+}
+
+// Attestation is the evidence an enclave gives of the code it ran a task
+// with: the kind of enclave, such as "sgx", the measurement of the code
+// loaded in it, and the quote over the result signed by the enclave's
+// platform, for verifiers to check against its vendor.
+type Attestation struct {
+	Type        string `json:"type"`
+	Measurement string `json:"measurement"`
+	Quote       string `json:"quote"`
 }
 
 // Completed returns true if the TaskRun status is StatusCompleted.
@@ -75,6 +197,16 @@ func (tr TaskRun) Errored() bool {
 	return tr.Status == StatusErrored
 }
 
+// Transition moves the TaskRun to the given status, unless its current
+// status does not allow it.
+func (tr *TaskRun) Transition(to string) error {
+	if err := transition(taskRunTransitions, "TaskRun", tr.ID, tr.Status, to); err != nil {
+		return err
+	}
+	tr.Status = to
+	return nil
+}
+
 // String returns info on the TaskRun as "ID,Type,Status,Result".
 func (tr TaskRun) String() string {
 	return fmt.Sprintf("TaskRun(%v,%v,%v,%v)", tr.ID, tr.Task.Type, tr.Status, tr.Result)
@@ -109,11 +241,14 @@ func (tr TaskRun) MergeTaskParams(j JSON) (TaskRun, error) {
 
 // RunResult keeps track of the outcome of a TaskRun. It stores
 // the Data and ErrorMessage, if any of either, and contains
-// a Pending field to track the status.
+// a Pending field to track the status. ErrorCode holds the kind
+// of TxError when a transaction failed, or of TimeoutError when a task
+// took too long.
 type RunResult struct {
 	JobRunID     string      `json:"jobRunId"`
 	Data         JSON        `json:"data"`
 	ErrorMessage null.String `json:"error"`
+	ErrorCode    null.String `json:"errorCode"`
 	Pending      bool        `json:"pending"`
 }
 
@@ -133,6 +268,13 @@ func (rr RunResult) WithValue(val string) RunResult {
 // and setting Pending to false.
 func (rr RunResult) WithError(err error) RunResult {
 	rr.ErrorMessage = null.StringFrom(err.Error())
+	rr.ErrorCode = null.String{}
+	if txErr, ok := err.(*TxError); ok {
+		rr.ErrorCode = null.StringFrom(txErr.Code)
+	}
+	if timeoutErr, ok := err.(*TimeoutError); ok {
+		rr.ErrorCode = null.StringFrom(timeoutErr.Code())
+	}
 	rr.Pending = false
 	return rr
 }
@@ -169,6 +311,60 @@ func (rr RunResult) HasError() bool {
 	return rr.ErrorMessage.Valid
 }
 
+// TerminalError returns true if the error is a TxError that will not go
+// away by trying again, or the run's timeout passing.
+func (rr RunResult) TerminalError() bool {
+	if !rr.ErrorCode.Valid || rr.ErrorCode.String == ErrorCodeTimeout {
+		return false
+	}
+	return !TxErrorRetryable(rr.ErrorCode.String)
+}
+
+// The RunResult ErrorCodes of a TimeoutError.
+const (
+	// ErrorCodeTimeout is for a task that took longer than its timeout,
+	// which its retry policy may try again.
+	ErrorCodeTimeout = "timeout"
+	// ErrorCodeRunTimeout is for a task still working when its run's
+	// timeout passed, which is not retried.
+	ErrorCodeRunTimeout = "run_timeout"
+)
+
+// TimeoutError is the error of a task whose work was cancelled because it
+// took longer than Timeout, which is the run's timeout if Run is set, or
+// else the task's.
+type TimeoutError struct {
+	Timeout time.Duration
+	Run     bool
+}
+
+// Error returns which timeout passed.
+func (e *TimeoutError) Error() string {
+	if e.Run {
+		return fmt.Sprintf("run timed out after %v", e.Timeout)
+	}
+	return fmt.Sprintf("task timed out after %v", e.Timeout)
+}
+
+// Code returns the RunResult ErrorCode for the timeout.
+func (e *TimeoutError) Code() string {
+	if e.Run {
+		return ErrorCodeRunTimeout
+	}
+	return ErrorCodeTimeout
+}
+
+// Status returns the status that a run or task run finishing with the
+// result moves to.
+func (rr RunResult) Status() string {
+	if rr.HasError() {
+		return StatusErrored
+	} else if rr.Pending {
+		return StatusPending
+	}
+	return StatusCompleted
+}
+
 // Error returns the string value of the ErrorMessage field.
 func (rr RunResult) Error() string {
 	return rr.ErrorMessage.String
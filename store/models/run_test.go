@@ -2,6 +2,7 @@ package models_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -50,6 +51,76 @@ func TestJobRun_UnfinishedTaskRuns(t *testing.T) {
 	assert.Equal(t, jr.TaskRuns[1:], jr.UnfinishedTaskRuns())
 }
 
+func TestJobRun_Transition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{"", models.StatusInProgress, true},
+		{"", models.StatusQueued, true},
+		{"", models.StatusCompleted, false},
+		{models.StatusQueued, models.StatusInProgress, true},
+		{models.StatusQueued, models.StatusErrored, true},
+		{models.StatusQueued, models.StatusCompleted, false},
+		{models.StatusInProgress, models.StatusPending, true},
+		{models.StatusInProgress, models.StatusCompleted, true},
+		{models.StatusPending, models.StatusInProgress, true},
+		{models.StatusPending, models.StatusCompleted, false},
+		{models.StatusErrored, models.StatusInProgress, false},
+		{models.StatusCompleted, models.StatusErrored, false},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(fmt.Sprintf("%q to %q", test.from, test.to), func(t *testing.T) {
+			jr := models.JobRun{ID: "1", Status: test.from}
+			err := jr.Transition(test.to)
+			if test.allowed {
+				assert.Nil(t, err)
+				assert.Equal(t, test.to, jr.Status)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, test.from, jr.Status)
+			}
+		})
+	}
+}
+
+func TestTaskRun_Transition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{"", models.StatusCompleted, true},
+		{models.StatusPending, models.StatusErrored, true},
+		{models.StatusErrored, models.StatusInProgress, true},
+		{models.StatusErrored, models.StatusCompleted, true},
+		{models.StatusCompleted, models.StatusInProgress, false},
+		{models.StatusCompleted, models.StatusErrored, false},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(fmt.Sprintf("%q to %q", test.from, test.to), func(t *testing.T) {
+			tr := models.TaskRun{ID: "1", Status: test.from}
+			err := tr.Transition(test.to)
+			if test.allowed {
+				assert.Nil(t, err)
+				assert.Equal(t, test.to, tr.Status)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, test.from, tr.Status)
+			}
+		})
+	}
+}
+
 func TestTaskRun_MergeTaskParams(t *testing.T) {
 	t.Parallel()
 
@@ -149,3 +220,37 @@ func TestRunResult_MergeData(t *testing.T) {
 		})
 	}
 }
+
+func TestRunResult_WithError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		err          error
+		wantCode     string
+		wantTerminal bool
+	}{
+		{"plain error", errors.New("bad request"), "", false},
+		{"retryable tx error", &models.TxError{Code: models.TxErrorConnection, Err: errors.New("EOF")}, models.TxErrorConnection, false},
+		{"terminal tx error", &models.TxError{Code: models.TxErrorInsufficientFunds, Err: errors.New("insufficient funds")}, models.TxErrorInsufficientFunds, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rr := models.RunResult{}.WithError(test.err)
+
+			assert.Equal(t, test.err.Error(), rr.Error())
+			assert.Equal(t, test.wantCode, rr.ErrorCode.String)
+			assert.Equal(t, test.wantCode != "", rr.ErrorCode.Valid)
+			assert.Equal(t, test.wantTerminal, rr.TerminalError())
+		})
+	}
+}
+
+func TestRunResult_Status(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, models.StatusCompleted, models.RunResult{}.Status())
+	assert.Equal(t, models.StatusPending, models.RunResult{}.MarkPending().Status())
+	assert.Equal(t, models.StatusErrored, models.RunResult{}.WithError(errors.New("bad")).Status())
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tidwall/gjson"
+)
+
+// TruncatedMarker ends a string that was cut short to keep a result under
+// RUN_RESULT_MAX_SIZE.
+const TruncatedMarker = "...[truncated]"
+
+// smallestCut is the length below which strings are no longer cut, and
+// the data is replaced instead.
+const smallestCut = 16
+
+// Sanitize returns the JSON with every key on the denylist removed, at any
+// depth and matched case insensitively, and, if it is still longer than
+// maxSize bytes, with its longest strings cut short and ended with
+// TruncatedMarker until it fits. JSON that cannot be made to fit by
+// cutting strings is replaced by {"truncated": <its size in bytes>}. A
+// maxSize of 0 is no limit.
+func (j JSON) Sanitize(maxSize int, denylist []string) (JSON, error) {
+	if !j.Exists() {
+		return j, nil
+	}
+	denied := map[string]bool{}
+	for _, key := range denylist {
+		denied[strings.ToLower(key)] = true
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(j.Bytes()))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return j, err
+	}
+	value, stripped := stripKeys(value, denied)
+	fits := maxSize == 0 || len(j.Bytes()) <= maxSize
+	if !stripped && fits {
+		return j, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return j, err
+	}
+	for limit := longestString(value) / 2; maxSize > 0 && len(b) > maxSize; limit /= 2 {
+		if limit < smallestCut {
+			b, err = json.Marshal(map[string]int{"truncated": len(j.Bytes())})
+			break
+		}
+		if b, err = json.Marshal(cutStrings(value, limit)); err != nil {
+			return j, err
+		}
+	}
+	if err != nil {
+		return j, err
+	}
+
+	var sanitized JSON
+	return sanitized, gjson.Unmarshal(b, &sanitized)
+}
+
+// stripKeys removes the denied keys from the decoded JSON, returning true
+// if any were found.
+func stripKeys(value interface{}, denied map[string]bool) (interface{}, bool) {
+	stripped := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if denied[strings.ToLower(key)] {
+				delete(v, key)
+				stripped = true
+				continue
+			}
+			var s bool
+			v[key], s = stripKeys(child, denied)
+			stripped = stripped || s
+		}
+	case []interface{}:
+		for i, child := range v {
+			var s bool
+			v[i], s = stripKeys(child, denied)
+			stripped = stripped || s
+		}
+	}
+	return value, stripped
+}
+
+func longestString(value interface{}) int {
+	longest := 0
+	switch v := value.(type) {
+	case string:
+		longest = len(v)
+	case map[string]interface{}:
+		for _, child := range v {
+			if l := longestString(child); l > longest {
+				longest = l
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if l := longestString(child); l > longest {
+				longest = l
+			}
+		}
+	}
+	return longest
+}
+
+// cutStrings returns a copy of the decoded JSON with every string longer
+// than limit bytes cut to limit, on a character boundary, and marked.
+func cutStrings(value interface{}, limit int) interface{} {
+	switch v := value.(type) {
+	case string:
+		return CutString(v, limit)
+	case map[string]interface{}:
+		cut := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			cut[key] = cutStrings(child, limit)
+		}
+		return cut
+	case []interface{}:
+		cut := make([]interface{}, len(v))
+		for i, child := range v {
+			cut[i] = cutStrings(child, limit)
+		}
+		return cut
+	}
+	return value
+}
+
+// CutString returns the string cut to at most limit bytes, on a character
+// boundary, and ended with TruncatedMarker, if it is any longer.
+func CutString(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit] + TruncatedMarker
+}
+
+// Sanitize returns a copy of the RunResult with its Data sanitized as
+// JSON.Sanitize does, and its ErrorMessage cut to maxSize.
+func (rr RunResult) Sanitize(maxSize int, denylist []string) (RunResult, error) {
+	data, err := rr.Data.Sanitize(maxSize, denylist)
+	if err != nil {
+		return rr, err
+	}
+	rr.Data = data
+	if maxSize > 0 && rr.ErrorMessage.Valid {
+		rr.ErrorMessage.String = CutString(rr.ErrorMessage.String, maxSize)
+	}
+	return rr, nil
+}
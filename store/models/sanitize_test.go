@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestJSON_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 100)
+	tests := []struct {
+		name     string
+		input    string
+		maxSize  int
+		denylist []string
+		want     string
+	}{
+		{"unchanged", `{"value":"100","nested":{"a":1}}`, 0, []string{"password"}, `{"value":"100","nested":{"a":1}}`},
+		{"denied keys", `{"value":"100","Password":"hunter2","nested":[{"apiKey":"k","b":2}]}`, 0,
+			[]string{"password", "apikey"}, `{"nested":[{"b":2}],"value":"100"}`},
+		{"under the limit", `{"value":"` + long + `"}`, 200, nil, `{"value":"` + long + `"}`},
+		{"long string cut", `{"value":"` + long + `","n":12345678901234567890}`, 90, nil,
+			`{"n":12345678901234567890,"value":"` + strings.Repeat("a", 25) + models.TruncatedMarker + `"}`},
+		{"too many fields", `{"a":1,"b":2,"c":3,"d":4}`, 10, nil, `{"truncated":25}`},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := cltest.JSONFromString(test.input)
+			sanitized, err := j.Sanitize(test.maxSize, test.denylist)
+			assert.Nil(t, err)
+			assert.JSONEq(t, test.want, string(sanitized.Bytes()))
+		})
+	}
+}
+
+func TestRunResult_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	rr := models.RunResult{
+		Data:         cltest.JSONFromString(`{"value":"100","secret":"s"}`),
+		ErrorMessage: null.StringFrom(strings.Repeat("e", 100)),
+	}
+	sanitized, err := rr.Sanitize(50, []string{"secret"})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"value":"100"}`, string(sanitized.Data.Bytes()))
+	assert.Equal(t, strings.Repeat("e", 50)+models.TruncatedMarker, sanitized.ErrorMessage.String)
+	assert.True(t, rr.Data.Get("secret").Exists())
+}
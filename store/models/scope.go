@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The actions an API token scope can allow on a resource.
+const (
+	// ScopeRead allows fetching a resource.
+	ScopeRead = "read"
+	// ScopeWrite allows creating, changing, and deleting a resource.
+	ScopeWrite = "write"
+	// scopeAny matches every resource, action, or ID.
+	scopeAny = "*"
+)
+
+// ScopeResources are the kinds of resource a scope can name. Each is the
+// first part of the API path that acts on it, except that the runs of a job
+// are the runs resource.
+var ScopeResources = []string{
+	"jobs", "job_specs", "runs", "bridge_types", "config", "api_tokens", "users", "audit",
+	"debug", "ws", "metrics", "txs", "feeds",
+}
+
+// Scope allows an API token to take an action on a kind of resource, and,
+// for jobs and runs, only those of one job. It is written as
+// resource:action or resource:action:jobID, where any part can be "*".
+type Scope struct {
+	Resource string
+	Action   string
+	ID       string
+}
+
+// ParseScope returns the scope written in the given string.
+func ParseScope(s string) (Scope, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Scope{}, fmt.Errorf("Scope %v must be resource:action or resource:action:id", s)
+	}
+	scope := Scope{Resource: parts[0], Action: parts[1], ID: scopeAny}
+	if len(parts) == 3 {
+		scope.ID = parts[2]
+	}
+	if scope.Resource != scopeAny && !validScopeResource(scope.Resource) {
+		return Scope{}, fmt.Errorf("Scope %v has unknown resource %v", s, scope.Resource)
+	}
+	if scope.Action != scopeAny && scope.Action != ScopeRead && scope.Action != ScopeWrite {
+		return Scope{}, fmt.Errorf("Scope %v has unknown action %v", s, scope.Action)
+	}
+	if scope.ID == "" {
+		return Scope{}, fmt.Errorf("Scope %v has an empty id", s)
+	}
+	return scope, nil
+}
+
+func validScopeResource(resource string) bool {
+	for _, r := range ScopeResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if the scope allows the action on the resource with
+// the given ID, which is empty for requests not about one job.
+func (s Scope) Allows(resource, action, id string) bool {
+	return (s.Resource == scopeAny || s.Resource == resource) &&
+		(s.Action == scopeAny || s.Action == action) &&
+		(s.ID == scopeAny || s.ID == id)
+}
+
+// ScopesAllow returns true if any of the scopes allow the action on the
+// resource with the given ID. Having no scopes allows everything, so that
+// API tokens without scopes keep all the access of their role.
+func ScopesAllow(scopes []string, resource, action, id string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, s := range scopes {
+		if scope, err := ParseScope(s); err == nil && scope.Allows(resource, action, id) {
+			return true
+		}
+	}
+	return false
+}
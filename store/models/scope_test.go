@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		scope   string
+		want    models.Scope
+		wantErr bool
+	}{
+		{"jobs:read", models.Scope{Resource: "jobs", Action: "read", ID: "*"}, false},
+		{"runs:write:abc", models.Scope{Resource: "runs", Action: "write", ID: "abc"}, false},
+		{"*:*", models.Scope{Resource: "*", Action: "*", ID: "*"}, false},
+		{"keys:read", models.Scope{}, true},
+		{"jobs:delete", models.Scope{}, true},
+		{"jobs", models.Scope{}, true},
+		{"runs:write:", models.Scope{}, true},
+		{"runs:write:a:b", models.Scope{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scope, func(t *testing.T) {
+			scope, err := models.ParseScope(test.scope)
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.want, scope)
+		})
+	}
+}
+
+func TestScopesAllow(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, models.ScopesAllow(nil, "config", "write", ""))
+
+	scopes := []string{"runs:write:abc", "jobs:read"}
+	assert.True(t, models.ScopesAllow(scopes, "runs", "write", "abc"))
+	assert.False(t, models.ScopesAllow(scopes, "runs", "write", "def"))
+	assert.False(t, models.ScopesAllow(scopes, "runs", "read", "abc"))
+	assert.True(t, models.ScopesAllow(scopes, "jobs", "read", "def"))
+	assert.True(t, models.ScopesAllow(scopes, "jobs", "read", ""))
+	assert.False(t, models.ScopesAllow(scopes, "bridge_types", "write", ""))
+}
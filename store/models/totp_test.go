@@ -0,0 +1,84 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	// The SHA1 test vectors of RFC 6238, truncated to six digits.
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		at   int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			code, err := models.TOTPCode(secret, time.Unix(test.at, 0))
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, code)
+		})
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	t.Parallel()
+
+	uri := models.TOTPURI("operator@example.com", "SECRET")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Chainlink:operator@example.com?"))
+	assert.Contains(t, uri, "secret=SECRET")
+	assert.Contains(t, uri, "issuer=Chainlink")
+}
+
+func TestUser_TOTP(t *testing.T) {
+	t.Parallel()
+
+	user, err := models.NewUser("operator@example.com", "correct horse", models.RoleAdmin)
+	assert.Nil(t, err)
+	secret, err := user.ProvisionTOTP()
+	assert.Nil(t, err)
+	assert.False(t, user.TOTPEnabled)
+
+	now := time.Now()
+	_, err = user.EnableTOTP("000000", now.Add(-time.Hour))
+	assert.NotNil(t, err)
+	code, err := models.TOTPCode(secret, now)
+	assert.Nil(t, err)
+	recoveryCodes, err := user.EnableTOTP(code, now)
+	assert.Nil(t, err)
+	assert.True(t, user.TOTPEnabled)
+	assert.Equal(t, models.RecoveryCodeCount, len(recoveryCodes))
+	assert.NotContains(t, user.RecoveryCodes, recoveryCodes[0])
+	_, err = user.ProvisionTOTP()
+	assert.NotNil(t, err)
+
+	assert.False(t, user.CheckTOTP(code, now), "codes can only be used once")
+	next, err := models.TOTPCode(secret, now.Add(30*time.Second))
+	assert.Nil(t, err)
+	assert.True(t, user.CheckTOTP(next, now))
+	stale, err := models.TOTPCode(secret, now.Add(-5*time.Minute))
+	assert.Nil(t, err)
+	assert.False(t, user.CheckTOTP(stale, now))
+
+	assert.True(t, user.UseRecoveryCode(strings.ToUpper(recoveryCodes[3])))
+	assert.False(t, user.UseRecoveryCode(recoveryCodes[3]))
+	assert.False(t, user.UseRecoveryCode("00000-00000"))
+	assert.Equal(t, models.RecoveryCodeCount-1, len(user.RecoveryCodes))
+
+	user.DisableTOTP()
+	assert.False(t, user.TOTPEnabled)
+	assert.Empty(t, user.TOTPSecret)
+	assert.Empty(t, user.RecoveryCodes)
+}
@@ -0,0 +1,291 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/utils"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// RoleView can read jobs, runs, and bridge types.
+	RoleView = "view"
+	// RoleRun can also start and update runs.
+	RoleRun = "run"
+	// RoleEdit can also create, change, and archive jobs and bridge types.
+	RoleEdit = "edit"
+	// RoleAdmin can also change the node's config, and manage users and
+	// API tokens.
+	RoleAdmin = "admin"
+)
+
+// roleRanks orders the roles, each of which can do everything the roles
+// ranked below it can.
+var roleRanks = map[string]int{
+	RoleView:  1,
+	RoleRun:   2,
+	RoleEdit:  3,
+	RoleAdmin: 4,
+}
+
+// ValidRole returns true if the given role exists.
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAllows returns true if the role can do what the required role can.
+// Users and API tokens created before roles were introduced have no role,
+// and keep the access they had as admins.
+func RoleAllows(role, required string) bool {
+	if role == "" {
+		role = RoleAdmin
+	}
+	return roleRanks[role] >= roleRanks[required]
+}
+
+// User holds the credentials and Role of someone using the node's API.
+// The password is only kept as a salted scrypt hash, which is stored with
+// the user, so users are shown through presenters.User.
+//
+// With two-factor authentication, logging in also takes a code from an
+// authenticator app holding TOTPSecret, or one of the user's recovery codes,
+// which are also kept as scrypt hashes and can each be used once.
+// TOTPLastStep is the period of the last code used, so that a code cannot
+// be used twice.
+//
+// Users who log in through an identity provider, such as LDAP or OpenID
+// Connect, have its name as their Provider, and no password on the node.
+type User struct {
+	Email          string   `json:"email" storm:"id,unique"`
+	HashedPassword string   `json:"hashedPassword"`
+	Role           string   `json:"role"`
+	CreatedAt      Time     `json:"createdAt"`
+	TOTPSecret     string   `json:"totpSecret,omitempty"`
+	TOTPEnabled    bool     `json:"totpEnabled"`
+	TOTPLastStep   int64    `json:"totpLastStep,omitempty"`
+	RecoveryCodes  []string `json:"recoveryCodes,omitempty"`
+	Provider       string   `json:"provider,omitempty"`
+}
+
+// NewUser returns a user with the given email and role, and a hash of the
+// password.
+func NewUser(email, password, role string) (User, error) {
+	if email == "" || password == "" {
+		return User{}, errors.New("Must supply an email and a password")
+	}
+	if !ValidRole(role) {
+		return User{}, fmt.Errorf("Role %v does not exist", role)
+	}
+	hashed, err := hashPassword(password)
+	if err != nil {
+		return User{}, err
+	}
+	return User{
+		Email:          strings.ToLower(email),
+		HashedPassword: hashed,
+		Role:           role,
+		CreatedAt:      Time{Time: time.Now()},
+	}, nil
+}
+
+// SetPassword replaces the user's password hash with a hash of the given
+// password.
+func (u *User) SetPassword(password string) error {
+	if password == "" {
+		return errors.New("Must supply a password")
+	}
+	hashed, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	u.HashedPassword = hashed
+	return nil
+}
+
+// CheckPassword returns true if the password matches the user's.
+func (u User) CheckPassword(password string) bool {
+	return checkHash(u.HashedPassword, password)
+}
+
+// ProvisionTOTP gives the user a new two-factor secret, which is not
+// required to log in until EnableTOTP is called with a code from it.
+func (u *User) ProvisionTOTP() (string, error) {
+	if u.TOTPEnabled {
+		return "", errors.New("Two-factor authentication is already enabled")
+	}
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+	u.TOTPSecret = secret
+	u.TOTPLastStep = 0
+	return secret, nil
+}
+
+// EnableTOTP requires a two-factor code to log in from now on, once the
+// given code shows the user's authenticator app has their secret. It
+// returns the user's new recovery codes, which are not kept.
+func (u *User) EnableTOTP(code string, now time.Time) ([]string, error) {
+	if u.TOTPEnabled {
+		return nil, errors.New("Two-factor authentication is already enabled")
+	}
+	if u.TOTPSecret == "" {
+		return nil, errors.New("Two-factor authentication has not been set up")
+	}
+	if !u.CheckTOTP(code, now) {
+		return nil, errors.New("Invalid two-factor authentication code")
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u.TOTPEnabled = true
+	u.RecoveryCodes = hashes
+	return codes, nil
+}
+
+// DisableTOTP stops requiring a two-factor code to log in, and drops the
+// user's secret and recovery codes.
+func (u *User) DisableTOTP() {
+	u.TOTPSecret = ""
+	u.TOTPEnabled = false
+	u.TOTPLastStep = 0
+	u.RecoveryCodes = nil
+}
+
+// CheckTOTP returns true if the code is the user's two-factor code at the
+// given time, or a period either side, and is newer than the last code
+// used, which it then becomes.
+func (u *User) CheckTOTP(code string, now time.Time) bool {
+	key, err := totpEncoding.DecodeString(u.TOTPSecret)
+	if err != nil || u.TOTPSecret == "" {
+		return false
+	}
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= u.TOTPLastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(strings.TrimSpace(code))) == 1 {
+			u.TOTPLastStep = step
+			return true
+		}
+	}
+	return false
+}
+
+// UseRecoveryCode returns true if the code is one of the user's recovery
+// codes, which is then removed so that it cannot be used again.
+func (u *User) UseRecoveryCode(code string) bool {
+	code = normalizeRecoveryCode(code)
+	for i, hashed := range u.RecoveryCodes {
+		if checkHash(hashed, code) {
+			u.RecoveryCodes = append(u.RecoveryCodes[:i:i], u.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// checkHash returns true if the password matches the hash made by
+// hashPassword.
+func checkHash(hashed, password string) bool {
+	parts := strings.SplitN(hashed, "$", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	salt, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+	hash, err := scryptHash(password, salt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(hash)), []byte(parts[1])) == 1
+}
+
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	hash, err := scryptHash(password, salt)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt) + "$" + hex.EncodeToString(hash), nil
+}
+
+func scryptHash(password string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(password), salt, 1<<14, 8, 1, 32)
+}
+
+// Session is a login of the operator, identified by the random ID kept in
+// the client's session cookie. LastUsed is updated on every authenticated
+// request, so that idle sessions can expire.
+type Session struct {
+	ID        string    `json:"id" storm:"id,unique"`
+	Email     string    `json:"email" storm:"index"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+// NewSession returns a new session for the user with the given email.
+func NewSession(email string) Session {
+	now := time.Now()
+	return Session{
+		ID:        utils.NewBytes32ID(),
+		Email:     email,
+		CreatedAt: now,
+		LastUsed:  now,
+	}
+}
+
+// Expired returns true if the session has not been used within timeout
+// of the given time.
+func (s Session) Expired(now time.Time, timeout time.Duration) bool {
+	return now.Sub(s.LastUsed) > timeout
+}
+
+// APIToken is a long-lived credential for programmatic clients, sent in
+// the Authorization header as a bearer token, which grants its Role. If it
+// has Scopes, it is further limited to what they allow, so that it can be
+// handed to an integration that should only, say, start runs of one job.
+// Only a hash of the token is stored, so the token itself is only known
+// when it is created.
+type APIToken struct {
+	ID          string   `json:"id" storm:"id,unique"`
+	Name        string   `json:"name"`
+	HashedToken string   `json:"-" storm:"index,unique"`
+	Role        string   `json:"role"`
+	Scopes      []string `json:"scopes,omitempty"`
+	CreatedAt   Time     `json:"createdAt"`
+}
+
+// NewAPIToken returns an API token with the given name and role, along
+// with the secret token to be handed to the client.
+func NewAPIToken(name, role string) (APIToken, string) {
+	token := utils.NewBytes32ID() + utils.NewBytes32ID()
+	return APIToken{
+		ID:          utils.NewBytes32ID(),
+		Name:        name,
+		HashedToken: HashAPIToken(token),
+		Role:        role,
+		CreatedAt:   Time{Time: time.Now()},
+	}, token
+}
+
+// HashAPIToken returns the hash stored for the given API token.
+func HashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
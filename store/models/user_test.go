@@ -0,0 +1,94 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUser(t *testing.T) {
+	t.Parallel()
+
+	user, err := models.NewUser("Operator@Example.com", "correct horse", models.RoleView)
+	assert.Nil(t, err)
+	assert.Equal(t, "operator@example.com", user.Email)
+	assert.Equal(t, models.RoleView, user.Role)
+	assert.NotContains(t, user.HashedPassword, "correct horse")
+	assert.True(t, user.CheckPassword("correct horse"))
+	assert.False(t, user.CheckPassword("wrong horse"))
+
+	_, err = models.NewUser("operator@example.com", "", models.RoleView)
+	assert.NotNil(t, err)
+	_, err = models.NewUser("operator@example.com", "correct horse", "superuser")
+	assert.NotNil(t, err)
+}
+
+func TestORM_SeedUser(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	user, err := store.FindUser(cltest.Username)
+	assert.Nil(t, err)
+	assert.True(t, user.CheckPassword(cltest.Password))
+	assert.Equal(t, models.RoleAdmin, user.Role)
+
+	assert.Nil(t, store.SeedUser(cltest.Username, "newpassword"))
+	user, err = store.FindUser(cltest.Username)
+	assert.Nil(t, err)
+	assert.True(t, user.CheckPassword(cltest.Password))
+}
+
+func TestRoleAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		role     string
+		required string
+		want     bool
+	}{
+		{models.RoleView, models.RoleView, true},
+		{models.RoleView, models.RoleRun, false},
+		{models.RoleRun, models.RoleView, true},
+		{models.RoleRun, models.RoleEdit, false},
+		{models.RoleEdit, models.RoleRun, true},
+		{models.RoleEdit, models.RoleAdmin, false},
+		{models.RoleAdmin, models.RoleAdmin, true},
+		{"", models.RoleAdmin, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.role+" "+test.required, func(t *testing.T) {
+			assert.Equal(t, test.want, models.RoleAllows(test.role, test.required))
+		})
+	}
+}
+
+func TestORM_DeleteUser(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	user, err := models.NewUser("viewer@example.com", "password", models.RoleView)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Save(&user))
+	session := models.NewSession(user.Email)
+	assert.Nil(t, store.Save(&session))
+
+	assert.Nil(t, store.DeleteUser(user))
+	_, err = store.FindUser(user.Email)
+	assert.NotNil(t, err)
+	_, err = store.FindSession(session.ID)
+	assert.NotNil(t, err)
+}
+
+func TestSession_Expired(t *testing.T) {
+	t.Parallel()
+
+	session := models.NewSession("operator@example.com")
+	assert.False(t, session.Expired(session.LastUsed.Add(time.Minute), time.Hour))
+	assert.True(t, session.Expired(session.LastUsed.Add(2*time.Hour), time.Hour))
+}
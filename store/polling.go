@@ -0,0 +1,195 @@
+package store
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// maxPolledBlockRange is the most blocks asked for in one eth_getLogs
+// request, so that a poller catching up does not exceed providers' limits
+// on the range of a query.
+const maxPolledBlockRange = 1000
+
+// NotificationsUnsupported returns true if the error is from subscribing
+// through an ethereum endpoint that does not support eth_subscribe, such
+// as an HTTP one.
+func NotificationsUnsupported(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "notifications not supported")
+}
+
+// PollingSubscription stands in for a subscription on an ethereum
+// endpoint that only supports requests, by asking for what the
+// subscription would have been sent every ETH_POLL_INTERVAL.
+type PollingSubscription struct {
+	done    chan struct{}
+	errors  chan error
+	sending sync.Mutex
+	stopped bool
+	once    sync.Once
+}
+
+func newPollingSubscription() *PollingSubscription {
+	return &PollingSubscription{
+		done:   make(chan struct{}),
+		errors: make(chan error),
+	}
+}
+
+// Err returns a channel that is closed once the subscription is
+// unsubscribed. Failed requests are retried at the next poll, so no errors
+// are sent.
+func (ps *PollingSubscription) Err() <-chan error {
+	return ps.errors
+}
+
+// Unsubscribe stops polling. Once it returns, nothing more is sent to the
+// subscription's channel, which can then be closed.
+func (ps *PollingSubscription) Unsubscribe() {
+	ps.once.Do(func() {
+		close(ps.done)
+		ps.sending.Lock()
+		ps.stopped = true
+		ps.sending.Unlock()
+		close(ps.errors)
+	})
+}
+
+// wait returns false once the subscription is unsubscribed, or true once
+// the interval has passed.
+func (ps *PollingSubscription) wait(clock AfterNower, interval time.Duration) bool {
+	select {
+	case <-ps.done:
+		return false
+	case <-clock.After(interval):
+		return true
+	}
+}
+
+// sendLog returns false if the subscription was unsubscribed rather than
+// the log sent.
+func (ps *PollingSubscription) sendLog(channel chan<- types.Log, log types.Log) bool {
+	ps.sending.Lock()
+	defer ps.sending.Unlock()
+	if ps.stopped {
+		return false
+	}
+	select {
+	case channel <- log:
+		return true
+	case <-ps.done:
+		return false
+	}
+}
+
+func (ps *PollingSubscription) sendHead(channel chan<- models.BlockHeader, head models.BlockHeader) bool {
+	ps.sending.Lock()
+	defer ps.sending.Unlock()
+	if ps.stopped {
+		return false
+	}
+	select {
+	case channel <- head:
+		return true
+	case <-ps.done:
+		return false
+	}
+}
+
+// PollLogs sends the logs matching the query to the channel, as
+// SubscribeToLogs would, by calling eth_getLogs for the blocks mined since
+// the last poll every ETH_POLL_INTERVAL, in one batch of ranges of at most
+// maxPolledBlockRange blocks. Polling starts at the query's
+// FromBlock, or the chain head if it has none, and blocks whose logs could
+// not be fetched are asked for again at the next poll.
+func (txm *TxManager) PollLogs(channel chan<- types.Log, q ethereum.FilterQuery, clock AfterNower) *PollingSubscription {
+	ps := newPollingSubscription()
+	go func() {
+		var next *big.Int
+		if q.FromBlock != nil {
+			next = new(big.Int).Set(q.FromBlock)
+		}
+		for ps.wait(clock, txm.CurrentConfig().EthPollInterval) {
+			head, err := txm.GetBlockNumber()
+			if err != nil {
+				logger.Subscription.Warnw("Polling for the chain head", "err", err)
+				continue
+			}
+			latest := new(big.Int).SetUint64(head)
+			if next == nil {
+				next = latest
+			}
+			if next.Cmp(latest) > 0 {
+				continue
+			}
+
+			ranges := polledRanges(q, next, latest, txm.MaxBatchSize)
+			results, err := txm.GetLogsBatch(ranges)
+			if err != nil {
+				failed := ranges[len(results)]
+				logger.Subscription.Warnw("Polling for logs", "err", err, "fromBlock", failed.FromBlock, "toBlock", failed.ToBlock)
+			}
+			for i, logs := range results {
+				for _, log := range logs {
+					if !ps.sendLog(channel, log) {
+						return
+					}
+				}
+				next = new(big.Int).Add(ranges[i].ToBlock, big.NewInt(1))
+			}
+		}
+	}()
+	return ps
+}
+
+// polledRanges splits the blocks from next to latest into queries of at
+// most maxPolledBlockRange blocks each, returning no more than max of them,
+// or all of them if max is 0.
+func polledRanges(q ethereum.FilterQuery, next, latest *big.Int, max int) []ethereum.FilterQuery {
+	ranges := []ethereum.FilterQuery{}
+	from := new(big.Int).Set(next)
+	for from.Cmp(latest) <= 0 && (max <= 0 || len(ranges) < max) {
+		to := new(big.Int).Add(from, big.NewInt(maxPolledBlockRange-1))
+		if to.Cmp(latest) > 0 {
+			to = latest
+		}
+		fq := q
+		fq.FromBlock, fq.ToBlock = from, to
+		ranges = append(ranges, fq)
+		from = new(big.Int).Add(to, big.NewInt(1))
+	}
+	return ranges
+}
+
+// PollNewHeads sends the chain head to the channel, as SubscribeToNewHeads
+// would, whenever it has moved on since the last poll every
+// ETH_POLL_INTERVAL.
+func (txm *TxManager) PollNewHeads(channel chan<- models.BlockHeader, clock AfterNower) *PollingSubscription {
+	ps := newPollingSubscription()
+	go func() {
+		var last uint64
+		for ps.wait(clock, txm.CurrentConfig().EthPollInterval) {
+			head, err := txm.GetBlockNumber()
+			if err != nil {
+				logger.Subscription.Warnw("Polling for the chain head", "err", err)
+				continue
+			}
+			if head <= last {
+				continue
+			}
+			last = head
+			number := hexutil.Big(*new(big.Int).SetUint64(head))
+			if !ps.sendHead(channel, models.BlockHeader{Number: number}) {
+				return
+			}
+		}
+	}()
+	return ps
+}
@@ -0,0 +1,93 @@
+package store_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// tickClock fires After whenever the test sends on ticks.
+type tickClock struct {
+	ticks chan time.Time
+}
+
+func (tc tickClock) Now() time.Time                         { return time.Now() }
+func (tc tickClock) After(_ time.Duration) <-chan time.Time { return tc.ticks }
+
+func TestNotificationsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, store.NotificationsUnsupported(errors.New("notifications not supported")))
+	assert.False(t, store.NotificationsUnsupported(errors.New("connection refused")))
+	assert.False(t, store.NotificationsUnsupported(nil))
+}
+
+func TestTxManager_PollLogs(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	s, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.MockEthOnStore(s)
+	clock := tickClock{ticks: make(chan time.Time)}
+
+	blockRange := func(from, to uint64) func(interface{}, ...interface{}) error {
+		return func(_ interface{}, data ...interface{}) error {
+			arg := data[0].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, utils.Uint64ToHex(from), arg["fromBlock"])
+			assert.Equal(t, utils.Uint64ToHex(to), arg["toBlock"])
+			return nil
+		}
+	}
+	first := types.Log{BlockNumber: 16}
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(16))
+	eth.Register("eth_getLogs", []types.Log{first}, blockRange(16, 16))
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(16))
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(18))
+	eth.Register("eth_getLogs", []types.Log{}, blockRange(17, 18))
+
+	logs := make(chan types.Log)
+	sub := s.TxManager.PollLogs(logs, ethereum.FilterQuery{FromBlock: big.NewInt(16)}, clock)
+	clock.ticks <- time.Now()
+	assert.Equal(t, first, <-logs)
+	clock.ticks <- time.Now()
+	clock.ticks <- time.Now()
+	g.Eventually(eth.AllCalled).Should(gomega.BeTrue())
+
+	sub.Unsubscribe()
+	_, open := <-sub.Err()
+	assert.False(t, open)
+}
+
+func TestTxManager_PollNewHeads(t *testing.T) {
+	t.Parallel()
+
+	s, cleanup := cltest.NewStore()
+	defer cleanup()
+	eth := cltest.MockEthOnStore(s)
+	clock := tickClock{ticks: make(chan time.Time)}
+
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(5))
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(5))
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(6))
+
+	heads := make(chan models.BlockHeader)
+	sub := s.TxManager.PollNewHeads(heads, clock)
+	defer sub.Unsubscribe()
+
+	clock.ticks <- time.Now()
+	assert.Equal(t, big.NewInt(5), (<-heads).ToInt())
+	clock.ticks <- time.Now()
+	clock.ticks <- time.Now()
+	assert.Equal(t, big.NewInt(6), (<-heads).ToInt())
+}
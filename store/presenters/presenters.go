@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/smartcontractkit/chainlink/logger"
@@ -25,21 +27,77 @@ func LogListeningAddress(address common.Address) string {
 }
 
 func ShowEthBalance(store *store.Store) (string, error) {
-	if !store.KeyStore.HasAccounts() {
+	if !store.Signer.HasAccounts() {
 		logger.Panic("KeyStore must have an account in order to show balance")
 	}
-	address := store.KeyStore.GetAccount().Address
-	balance, err := store.TxManager.GetEthBalance(address)
+	address := store.Signer.GetAccount().Address
+	balance, err := store.TxManager.GetWeiBalance(address)
 	if err != nil {
 		return "", err
 	}
-	result := fmt.Sprintf("ETH Balance for %v: %v", address.Hex(), balance)
-	if balance == 0 {
+	result := fmt.Sprintf("ETH Balance for %v: %v", address.Hex(), NewEthAmount(balance, store.CurrentConfig().EthDisplayUnit))
+	if balance.Sign() == 0 {
 		return result, errors.New("0 Balance. Chainlink node not fully functional, please deposit eth into your address: " + address.Hex())
 	}
 	return result, nil
 }
 
+// Amount is an amount of ether or LINK in the unit set to show it in.
+type Amount struct {
+	Value string `json:"value"`
+	Unit  string `json:"unit"`
+}
+
+// NewEthAmount returns the amount of wei in the given unit.
+func NewEthAmount(wei *big.Int, unit store.EthUnit) Amount {
+	return Amount{Value: utils.FormatUnits(wei, unit.Decimals()), Unit: unit.String()}
+}
+
+// NewLinkAmount returns the amount of juels in the given unit.
+func NewLinkAmount(juels *big.Int, unit store.LinkUnit) Amount {
+	return Amount{Value: utils.FormatUnits(juels, unit.Decimals()), Unit: unit.String()}
+}
+
+// String returns the amount followed by its unit, such as 1.5 ether.
+func (a Amount) String() string {
+	return a.Value + " " + a.Unit
+}
+
+// Balances holds the balances of the node's account, in the units set by
+// ETH_DISPLAY_UNIT and LINK_DISPLAY_UNIT. The LINK balance is only given
+// when LINK_CONTRACT_ADDRESS is set.
+type Balances struct {
+	Address common.Address `json:"address"`
+	Eth     Amount         `json:"eth"`
+	Link    *Amount        `json:"link,omitempty"`
+}
+
+// NewBalances fetches the balances of the node's account.
+func NewBalances(store *store.Store) (Balances, error) {
+	if !store.Signer.HasAccounts() {
+		return Balances{}, errors.New("The node has no account")
+	}
+	address := store.Signer.GetAccount().Address
+	if store.CurrentConfig().LinkContractAddress == "" {
+		wei, err := store.TxManager.GetWeiBalance(address)
+		if err != nil {
+			return Balances{}, err
+		}
+		return Balances{Address: address, Eth: NewEthAmount(wei, store.CurrentConfig().EthDisplayUnit)}, nil
+	}
+	contract := common.HexToAddress(store.CurrentConfig().LinkContractAddress)
+	wei, juels, err := store.TxManager.GetBalances(address, contract)
+	if err != nil {
+		return Balances{}, err
+	}
+	link := NewLinkAmount(juels, store.CurrentConfig().LinkDisplayUnit)
+	return Balances{
+		Address: address,
+		Eth:     NewEthAmount(wei, store.CurrentConfig().EthDisplayUnit),
+		Link:    &link,
+	}, nil
+}
+
 // Job holds the Job definition and each run associated with that Job.
 type Job struct {
 	models.Job
@@ -132,29 +190,39 @@ func (i Initiator) MarshalJSON() ([]byte, error) {
 		})
 	case models.InitiatorRunAt:
 		return json.Marshal(&struct {
-			Type string      `json:"type"`
-			Time models.Time `json:"time"`
-			Ran  bool        `json:"ran"`
+			Type   string      `json:"type"`
+			Time   models.Time `json:"time"`
+			Ran    bool        `json:"ran"`
+			Missed bool        `json:"missed"`
 		}{
 			models.InitiatorRunAt,
 			i.Time,
 			i.Ran,
+			i.Missed,
 		})
 	case models.InitiatorEthLog:
 		return json.Marshal(&struct {
-			Type    string         `json:"type"`
-			Address common.Address `json:"address"`
+			Type        string           `json:"type"`
+			Address     common.Address   `json:"address"`
+			Addresses   []common.Address `json:"addresses,omitempty"`
+			AddressList string           `json:"addressList,omitempty"`
 		}{
 			models.InitiatorEthLog,
 			i.Address,
+			i.Addresses,
+			i.AddressList,
 		})
 	case models.InitiatorRunLog:
 		return json.Marshal(&struct {
-			Type    string         `json:"type"`
-			Address common.Address `json:"address"`
+			Type        string           `json:"type"`
+			Address     common.Address   `json:"address"`
+			Addresses   []common.Address `json:"addresses,omitempty"`
+			AddressList string           `json:"addressList,omitempty"`
 		}{
 			models.InitiatorRunLog,
 			i.Address,
+			i.Addresses,
+			i.AddressList,
 		})
 	default:
 		return nil, fmt.Errorf("Cannot marshal unsupported initiator type %v", i.Type)
@@ -180,6 +248,36 @@ func (i Initiator) FriendlyAddress() string {
 	return ""
 }
 
+// User is a user of the node's API, without their password hash or
+// two-factor secrets.
+type User struct {
+	Email       string      `json:"email"`
+	Role        string      `json:"role"`
+	CreatedAt   models.Time `json:"createdAt"`
+	TOTPEnabled bool        `json:"totpEnabled"`
+	Provider    string      `json:"provider,omitempty"`
+}
+
+// NewUser returns the user as shown by the API.
+func NewUser(u models.User) User {
+	return User{
+		Email:       u.Email,
+		Role:        u.Role,
+		CreatedAt:   u.CreatedAt,
+		TOTPEnabled: u.TOTPEnabled,
+		Provider:    u.Provider,
+	}
+}
+
+// NewUsers returns the users as shown by the API.
+func NewUsers(us []models.User) []User {
+	users := []User{}
+	for _, u := range us {
+		users = append(users, NewUser(u))
+	}
+	return users
+}
+
 // Task holds a task specified in the Job definition.
 type Task struct {
 	models.Task
@@ -198,3 +296,67 @@ func (t Task) FriendlyParams() (string, string) {
 	})
 	return strings.Join(keys, "\n"), strings.Join(values, "\n")
 }
+
+// Feed holds a feed along with the answer its fresh observations give now,
+// which is only reported once a report is due.
+type Feed struct {
+	models.Feed
+	CurrentAnswer *float64 `json:"currentAnswer"`
+	FreshSources  int      `json:"freshSources"`
+}
+
+// NewFeed returns the feed as it stands at the given time.
+func NewFeed(f models.Feed, now time.Time) Feed {
+	feed := Feed{Feed: f, FreshSources: len(f.Fresh(now))}
+	if answer, err := f.Answer(now); err == nil {
+		feed.CurrentAnswer = &answer
+	}
+	return feed
+}
+
+// ExternalInitiator is an external initiator of the node, without its
+// hashed secret or outgoing token.
+type ExternalInitiator struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	URL       string      `json:"url,omitempty"`
+	AccessKey string      `json:"accessKey"`
+	CreatedAt models.Time `json:"createdAt"`
+}
+
+// NewExternalInitiators returns the external initiators as shown by the
+// API.
+func NewExternalInitiators(eis []models.ExternalInitiator) []ExternalInitiator {
+	presented := []ExternalInitiator{}
+	for _, ei := range eis {
+		presented = append(presented, ExternalInitiator{
+			ID:        ei.ID,
+			Name:      ei.Name,
+			URL:       ei.URL,
+			AccessKey: ei.AccessKey,
+			CreatedAt: ei.CreatedAt,
+		})
+	}
+	return presented
+}
+
+// Credential is a credential saved on the node, without its value.
+type Credential struct {
+	Name      string      `json:"name"`
+	CreatedAt models.Time `json:"createdAt"`
+	UpdatedAt models.Time `json:"updatedAt"`
+}
+
+// NewCredential returns the credential as shown by the API.
+func NewCredential(c models.Credential) Credential {
+	return Credential{Name: c.Name, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
+}
+
+// NewCredentials returns the credentials as shown by the API.
+func NewCredentials(cs []models.Credential) []Credential {
+	credentials := []Credential{}
+	for _, c := range cs {
+		credentials = append(credentials, NewCredential(c))
+	}
+	return credentials
+}
@@ -28,7 +28,7 @@ func TestPresenterInitiatorHasCorrectKeys(t *testing.T) {
 	}{
 		{MI{Type: models.InitiatorWeb}, []string{"type"}},
 		{MI{Type: models.InitiatorCron, Schedule: models.Cron("* * * * *")}, []string{"type", "schedule"}},
-		{MI{Type: models.InitiatorRunAt, Time: models.Time{now}}, []string{"type", "time", "ran"}},
+		{MI{Type: models.InitiatorRunAt, Time: models.Time{now}}, []string{"type", "time", "ran", "missed"}},
 		{MI{Type: models.InitiatorEthLog, Address: address}, []string{"type", "address"}},
 	}
 
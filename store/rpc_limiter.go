@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ContextCallerSubscriber is a CallerSubscriber whose calls can be given a
+// deadline, such as an *rpc.Client.
+type ContextCallerSubscriber interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	EthSubscribe(context.Context, interface{}, ...interface{}) (*rpc.ClientSubscription, error)
+}
+
+// contextBatchCaller is a ContextCallerSubscriber that can also send
+// several calls in one batch request, such as an *rpc.Client.
+type contextBatchCaller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// LimitedCaller caps the number of RPC calls outstanding to the ethereum
+// node at once, so that bursts of runs do not trip hosted providers' rate
+// limits. Calls beyond the limit queue until a call finishes, and give up
+// if their deadline passes while queued. Subscriptions are long lived and
+// are not counted.
+type LimitedCaller struct {
+	caller  ContextCallerSubscriber
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// NewLimitedCaller allows up to max concurrent calls through caller, each
+// with the given timeout unless made with CallContext.
+func NewLimitedCaller(caller ContextCallerSubscriber, max int, timeout time.Duration) *LimitedCaller {
+	return &LimitedCaller{
+		caller:  caller,
+		slots:   make(chan struct{}, max),
+		timeout: timeout,
+	}
+}
+
+// Call performs the call once there is room, timing out after the
+// LimitedCaller's timeout, including time spent queued.
+func (lc *LimitedCaller) Call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lc.timeout)
+	defer cancel()
+	return lc.CallContext(ctx, result, method, args...)
+}
+
+// CallContext performs the call once there is room, unless ctx is done
+// first.
+func (lc *LimitedCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	select {
+	case lc.slots <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("Waiting to call %v: %v", method, ctx.Err())
+	}
+	defer func() { <-lc.slots }()
+	return lc.caller.CallContext(ctx, result, method, args...)
+}
+
+// BatchCall sends the calls in one batch once there is room, counting the
+// batch as a single call, and timing out after the LimitedCaller's timeout.
+// They are made one at a time if the connection cannot batch them.
+func (lc *LimitedCaller) BatchCall(elems []rpc.BatchElem) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lc.timeout)
+	defer cancel()
+	select {
+	case lc.slots <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("Waiting to send a batch of %d calls: %v", len(elems), ctx.Err())
+	}
+	defer func() { <-lc.slots }()
+	if bc, ok := lc.caller.(contextBatchCaller); ok {
+		return bc.BatchCallContext(ctx, elems)
+	}
+	for i := range elems {
+		elems[i].Error = lc.caller.CallContext(ctx, elems[i].Result, elems[i].Method, elems[i].Args...)
+	}
+	return nil
+}
+
+// EthSubscribe registers the subscription without waiting for room.
+func (lc *LimitedCaller) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	return lc.caller.EthSubscribe(ctx, channel, args...)
+}
+
+// Close closes the connection to the ethereum node.
+func (lc *LimitedCaller) Close() {
+	closeEthereum(lc.caller)
+}
+
+// Outstanding returns the number of calls currently being made.
+func (lc *LimitedCaller) Outstanding() int {
+	return len(lc.slots)
+}
@@ -0,0 +1,66 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingCaller struct {
+	release chan struct{}
+}
+
+func (bc blockingCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	select {
+	case <-bc.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (bc blockingCaller) EthSubscribe(context.Context, interface{}, ...interface{}) (*rpc.ClientSubscription, error) {
+	return nil, nil
+}
+
+func TestLimitedCaller_Call(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	caller := blockingCaller{release: make(chan struct{})}
+	lc := store.NewLimitedCaller(caller, 2, time.Minute)
+
+	done := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() { done <- lc.Call(nil, "eth_blockNumber") }()
+	}
+	g.Eventually(lc.Outstanding).Should(gomega.Equal(2))
+	g.Consistently(lc.Outstanding).Should(gomega.Equal(2))
+
+	for i := 0; i < 3; i++ {
+		caller.release <- struct{}{}
+		assert.Nil(t, <-done)
+	}
+	assert.Equal(t, 0, lc.Outstanding())
+}
+
+func TestLimitedCaller_Call_DeadlineWhileQueued(t *testing.T) {
+	t.Parallel()
+
+	caller := blockingCaller{release: make(chan struct{})}
+	lc := store.NewLimitedCaller(caller, 1, 10*time.Millisecond)
+
+	go lc.CallContext(context.Background(), nil, "eth_getBalance")
+	gomega.NewGomegaWithT(t).Eventually(lc.Outstanding).Should(gomega.Equal(1))
+
+	err := lc.Call(nil, "eth_blockNumber")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Waiting to call eth_blockNumber")
+
+	caller.release <- struct{}{}
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/smartcontractkit/chainlink/metrics"
+)
+
+// defaultMethodWeights weigh the RPC methods that cost metered providers
+// more than a simple read, unless ETH_RATE_LIMIT_WEIGHTS weighs them.
+// Every other method weighs 1.
+var defaultMethodWeights = MethodWeights{
+	"eth_getLogs":            5,
+	"eth_sendRawTransaction": 10,
+}
+
+// refusedCallPause is how long every call is held back after the ethereum
+// node refuses one for going over its rate limit.
+const refusedCallPause = time.Second
+
+// RateLimitedCaller keeps the RPC calls made to the ethereum node under a
+// budget of weight per second, so that nodes on metered providers slow
+// down rather than have their calls refused mid-run. Each call weighs its
+// method's weight, and a batch the sum of its calls'. Calls over the budget
+// wait their turn, and fail if they would wait longer than the timeout.
+// When the node refuses a call for going over its own limit, every call is
+// held back for refusedCallPause. Calls are counted in the node's metrics
+// by method whether or not there is a budget. Subscriptions are not
+// counted.
+type RateLimitedCaller struct {
+	caller      CallerSubscriber
+	rate        float64
+	weights     MethodWeights
+	timeout     time.Duration
+	clock       AfterNower
+	budget      float64
+	updated     time.Time
+	pausedUntil time.Time
+	mutex       sync.Mutex
+}
+
+// NewRateLimitedCaller allows calls through caller weighing up to rate per
+// second, or any number of them if it is 0. Methods are weighed by the
+// weights given, then by defaultMethodWeights.
+func NewRateLimitedCaller(
+	caller CallerSubscriber,
+	rate uint64,
+	weights MethodWeights,
+	timeout time.Duration,
+	clock AfterNower,
+) *RateLimitedCaller {
+	return &RateLimitedCaller{
+		caller:  caller,
+		rate:    float64(rate),
+		weights: weights,
+		timeout: timeout,
+		clock:   clock,
+		budget:  float64(rate),
+		updated: clock.Now(),
+	}
+}
+
+// Weight returns how much of the budget a call to the method uses.
+func (rl *RateLimitedCaller) Weight(method string) uint64 {
+	if weight, ok := rl.weights[method]; ok {
+		return weight
+	}
+	if weight, ok := defaultMethodWeights[method]; ok {
+		return weight
+	}
+	return 1
+}
+
+// Call performs the call once it fits in the budget.
+func (rl *RateLimitedCaller) Call(result interface{}, method string, args ...interface{}) error {
+	if err := rl.take(method); err != nil {
+		return err
+	}
+	err := rl.caller.Call(result, method, args...)
+	rl.checkRefused(err, method)
+	return err
+}
+
+// BatchCall sends the calls in one batch once they all fit in the budget,
+// or one at a time if the connection cannot batch them.
+func (rl *RateLimitedCaller) BatchCall(elems []rpc.BatchElem) error {
+	methods := make([]string, len(elems))
+	for i, elem := range elems {
+		methods[i] = elem.Method
+	}
+	if err := rl.take(methods...); err != nil {
+		return err
+	}
+	if err := batchCall(rl.caller, elems); err != nil {
+		rl.checkRefused(err, methods...)
+		return err
+	}
+	for _, elem := range elems {
+		rl.checkRefused(elem.Error, elem.Method)
+	}
+	return nil
+}
+
+// EthSubscribe registers the subscription without spending the budget.
+func (rl *RateLimitedCaller) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	return rl.caller.EthSubscribe(ctx, channel, args...)
+}
+
+// Close closes the connection to the ethereum node.
+func (rl *RateLimitedCaller) Close() {
+	closeEthereum(rl.caller)
+}
+
+// take spends the weight of calls to the methods from the budget, and
+// waits until the budget would have covered them.
+func (rl *RateLimitedCaller) take(methods ...string) error {
+	weight := uint64(0)
+	for _, method := range methods {
+		weight += rl.Weight(method)
+	}
+	delay, err := rl.reserve(float64(weight))
+	if err != nil {
+		if len(methods) == 1 {
+			return fmt.Errorf("Waiting to call %v: %v", methods[0], err)
+		}
+		return fmt.Errorf("Waiting to send a batch of %d calls: %v", len(methods), err)
+	}
+	for _, method := range methods {
+		metrics.EthRPCCalls.Inc(method)
+		metrics.EthRPCWeight.Add(float64(rl.Weight(method)), method)
+		if delay > 0 {
+			metrics.EthRPCThrottled.Inc(method)
+		}
+	}
+	if delay > 0 {
+		<-rl.clock.After(delay)
+	}
+	return nil
+}
+
+// reserve spends the weight from the budget, letting it go negative so
+// that calls waiting for it to refill go in the order they were made, and
+// returns how long to wait until it would have covered the weight.
+func (rl *RateLimitedCaller) reserve(weight float64) (time.Duration, error) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	now := rl.refill()
+	delay := time.Duration(0)
+	if rl.pausedUntil.After(now) {
+		delay = rl.pausedUntil.Sub(now)
+	}
+	if rl.rate > 0 && rl.budget < weight {
+		wait := time.Duration((weight - rl.budget) / rl.rate * float64(time.Second))
+		if wait > delay {
+			delay = wait
+		}
+	}
+	if delay > rl.timeout {
+		return 0, fmt.Errorf("ETH_RATE_LIMIT would hold the call back for %v", delay)
+	}
+	if rl.rate > 0 {
+		rl.budget -= weight
+	}
+	return delay, nil
+}
+
+// refill adds the budget earned since it was last refilled, up to one
+// second's worth, and returns the current time.
+func (rl *RateLimitedCaller) refill() time.Time {
+	now := rl.clock.Now()
+	if elapsed := now.Sub(rl.updated).Seconds(); elapsed > 0 {
+		rl.budget = math.Min(rl.rate, rl.budget+elapsed*rl.rate)
+	}
+	rl.updated = now
+	return now
+}
+
+// checkRefused holds every call back for refusedCallPause if the ethereum
+// node refused the calls to the methods for going over its rate limit.
+func (rl *RateLimitedCaller) checkRefused(err error, methods ...string) {
+	if !refusedForRate(err) {
+		return
+	}
+	for _, method := range methods {
+		metrics.EthRPCRateLimited.Inc(method)
+	}
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	rl.pausedUntil = rl.refill().Add(refusedCallPause)
+}
+
+// refusedForRate returns true if the error is the ethereum node refusing
+// a call for going over its rate limit, such as with HTTP status 429.
+func refusedForRate(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "too many requests") ||
+		strings.Contains(message, "rate limit") ||
+		strings.Contains(message, "rate exceeded")
+}
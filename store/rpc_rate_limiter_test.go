@@ -0,0 +1,118 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// stoppedClock stands still, recording how long it was asked to wait for.
+type stoppedClock struct {
+	now    time.Time
+	waited []time.Duration
+}
+
+func (sc *stoppedClock) Now() time.Time { return sc.now }
+
+func (sc *stoppedClock) After(d time.Duration) <-chan time.Time {
+	sc.waited = append(sc.waited, d)
+	fired := make(chan time.Time, 1)
+	fired <- sc.now.Add(d)
+	return fired
+}
+
+func TestRateLimitedCaller_Weight(t *testing.T) {
+	t.Parallel()
+
+	rl := store.NewRateLimitedCaller(cltest.NewMockGethRpc(), 10, store.MethodWeights{"eth_call": 3}, time.Minute, &stoppedClock{})
+	assert.Equal(t, uint64(3), rl.Weight("eth_call"))
+	assert.Equal(t, uint64(5), rl.Weight("eth_getLogs"))
+	assert.Equal(t, uint64(1), rl.Weight("eth_blockNumber"))
+}
+
+func TestRateLimitedCaller_Call(t *testing.T) {
+	t.Parallel()
+
+	eth := cltest.NewMockGethRpc()
+	clock := &stoppedClock{now: time.Now()}
+	rl := store.NewRateLimitedCaller(eth, 10, nil, time.Minute, clock)
+
+	result := ""
+	for i := 0; i < 10; i++ {
+		eth.Register("eth_blockNumber", utils.Uint64ToHex(1))
+		assert.Nil(t, rl.Call(&result, "eth_blockNumber"))
+	}
+	assert.Empty(t, clock.waited, "a second's worth of calls go straight through")
+
+	eth.Register("eth_getLogs", "")
+	assert.Nil(t, rl.Call(&result, "eth_getLogs"))
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, clock.waited)
+
+	clock.now = clock.now.Add(time.Second)
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(1))
+	assert.Nil(t, rl.Call(&result, "eth_blockNumber"))
+	assert.Equal(t, 1, len(clock.waited), "the budget refills over time")
+	eth.EnsureAllCalled(t)
+}
+
+func TestRateLimitedCaller_Call_WaitPastTimeout(t *testing.T) {
+	t.Parallel()
+
+	eth := cltest.NewMockGethRpc()
+	clock := &stoppedClock{now: time.Now()}
+	rl := store.NewRateLimitedCaller(eth, 1, nil, time.Second, clock)
+
+	result := ""
+	assert.NotNil(t, rl.Call(&result, "eth_sendRawTransaction"))
+	assert.Empty(t, clock.waited)
+
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(1))
+	assert.Nil(t, rl.Call(&result, "eth_blockNumber"), "a refused call does not spend the budget")
+	eth.EnsureAllCalled(t)
+}
+
+func TestRateLimitedCaller_Call_Refused(t *testing.T) {
+	t.Parallel()
+
+	eth := cltest.NewMockGethRpc()
+	clock := &stoppedClock{now: time.Now()}
+	rl := store.NewRateLimitedCaller(eth, 0, nil, time.Minute, clock)
+
+	result := ""
+	eth.RegisterError("eth_call", "429 Too Many Requests")
+	assert.NotNil(t, rl.Call(&result, "eth_call"))
+
+	eth.Register("eth_call", "0x")
+	assert.Nil(t, rl.Call(&result, "eth_call"))
+	assert.Equal(t, []time.Duration{time.Second}, clock.waited)
+	eth.EnsureAllCalled(t)
+}
+
+func TestRateLimitedCaller_BatchCall(t *testing.T) {
+	t.Parallel()
+
+	caller := &batchingCaller{EthMock: cltest.NewMockGethRpc()}
+	clock := &stoppedClock{now: time.Now()}
+	rl := store.NewRateLimitedCaller(caller, 10, nil, time.Minute, clock)
+
+	results := make([]string, 3)
+	elems := make([]rpc.BatchElem, 3)
+	for i := range elems {
+		elems[i] = rpc.BatchElem{Method: "eth_getLogs", Result: &results[i]}
+	}
+	caller.Register("eth_getLogs", "")
+	caller.Register("eth_getLogs", "")
+	caller.RegisterError("eth_getLogs", "project ID request rate exceeded")
+
+	assert.Nil(t, rl.BatchCall(elems))
+	assert.Nil(t, elems[0].Error)
+	assert.EqualError(t, elems[2].Error, "project ID request rate exceeded")
+	assert.Equal(t, []int{3}, caller.batches)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, clock.waited)
+	caller.EnsureAllCalled(t)
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// RunQueue caps the number of runs executing at once, across the node and
+// for each job that sets its own cap, and keeps track of the runs waiting
+// for a worker, so that operators can tell a node that is slow from
+// upstream services that are slow. Without a cap, runs never wait.
+type RunQueue struct {
+	workers chan struct{}
+	queued  map[uint64]queuedRun
+	nextID  uint64
+	busy    int
+	slots   map[string]int
+	freed   *sync.Cond
+	mutex   sync.Mutex
+}
+
+type queuedRun struct {
+	jobID    string
+	queuedAt time.Time
+}
+
+// RunQueueStats is a snapshot of a RunQueue: the number of runs queued for
+// each job, how long the oldest has waited, and how many of the workers are
+// busy. Workers is 0 when the number of runs is not capped.
+type RunQueueStats struct {
+	Queued              map[string]int `json:"queued"`
+	OldestQueuedSeconds float64        `json:"oldestQueuedSeconds"`
+	Workers             int            `json:"workers"`
+	Busy                int            `json:"busy"`
+	Utilization         float64        `json:"utilization"`
+}
+
+// NewRunQueue returns a RunQueue with the given number of workers, or no
+// cap if it is 0.
+func NewRunQueue(workers int) *RunQueue {
+	rq := &RunQueue{queued: map[uint64]queuedRun{}, slots: map[string]int{}}
+	rq.freed = sync.NewCond(&rq.mutex)
+	if workers > 0 {
+		rq.workers = make(chan struct{}, workers)
+	}
+	return rq
+}
+
+// Work waits for a worker to execute the run, and returns a function that
+// frees the worker once the run is done.
+func (rq *RunQueue) Work(run models.JobRun) func() {
+	return rq.WorkLimited(run, 0)
+}
+
+// WorkLimited waits until fewer than jobLimit of the run's job's runs are
+// executing, unless it is 0, and then for a worker to execute the run. It
+// returns a function that frees both once the run is done. A run waiting
+// on its job's cap does not hold a worker, so other jobs' runs go ahead.
+func (rq *RunQueue) WorkLimited(run models.JobRun, jobLimit int) func() {
+	rq.mutex.Lock()
+	id := rq.nextID
+	rq.nextID++
+	rq.queued[id] = queuedRun{jobID: run.JobID, queuedAt: time.Now()}
+	metrics.RunsQueued.Inc(run.JobID)
+	for jobLimit > 0 && rq.slots[run.JobID] >= jobLimit {
+		rq.freed.Wait()
+	}
+	rq.slots[run.JobID]++
+	rq.mutex.Unlock()
+
+	if rq.workers != nil {
+		rq.workers <- struct{}{}
+	}
+
+	rq.mutex.Lock()
+	delete(rq.queued, id)
+	rq.busy++
+	rq.mutex.Unlock()
+	metrics.RunsQueued.Dec(run.JobID)
+
+	return func() {
+		rq.mutex.Lock()
+		rq.busy--
+		if rq.slots[run.JobID]--; rq.slots[run.JobID] <= 0 {
+			delete(rq.slots, run.JobID)
+		}
+		rq.freed.Broadcast()
+		rq.mutex.Unlock()
+		if rq.workers != nil {
+			<-rq.workers
+		}
+	}
+}
+
+// Stats returns a snapshot of the queue.
+func (rq *RunQueue) Stats() RunQueueStats {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	stats := RunQueueStats{
+		Queued:  map[string]int{},
+		Workers: cap(rq.workers),
+		Busy:    rq.busy,
+	}
+	for _, qr := range rq.queued {
+		stats.Queued[qr.jobID]++
+		if age := time.Since(qr.queuedAt).Seconds(); age > stats.OldestQueuedSeconds {
+			stats.OldestQueuedSeconds = age
+		}
+	}
+	if stats.Workers > 0 {
+		stats.Utilization = float64(stats.Busy) / float64(stats.Workers)
+	}
+	return stats
+}
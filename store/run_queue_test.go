@@ -0,0 +1,73 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunQueue_Work(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	rq := store.NewRunQueue(1)
+	run := models.JobRun{ID: "1", JobID: "job"}
+
+	done := rq.Work(run)
+	stats := rq.Stats()
+	assert.Equal(t, 1, stats.Busy)
+	assert.Equal(t, 1.0, stats.Utilization)
+	assert.Equal(t, map[string]int{}, stats.Queued)
+
+	started := make(chan func())
+	go func() { started <- rq.Work(models.JobRun{ID: "2", JobID: "job"}) }()
+	g.Eventually(func() map[string]int { return rq.Stats().Queued }).Should(gomega.Equal(map[string]int{"job": 1}))
+	assert.True(t, rq.Stats().OldestQueuedSeconds > 0)
+
+	done()
+	(<-started)()
+	stats = rq.Stats()
+	assert.Equal(t, 0, stats.Busy)
+	assert.Equal(t, map[string]int{}, stats.Queued)
+	assert.Equal(t, 0.0, stats.OldestQueuedSeconds)
+}
+
+func TestRunQueue_Unlimited(t *testing.T) {
+	t.Parallel()
+
+	rq := store.NewRunQueue(0)
+	done1 := rq.Work(models.JobRun{ID: "1", JobID: "job"})
+	done2 := rq.Work(models.JobRun{ID: "2", JobID: "job"})
+
+	stats := rq.Stats()
+	assert.Equal(t, 0, stats.Workers)
+	assert.Equal(t, 2, stats.Busy)
+	assert.Equal(t, 0.0, stats.Utilization)
+	done1()
+	done2()
+}
+
+func TestRunQueue_WorkLimited(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	rq := store.NewRunQueue(0)
+	done := rq.WorkLimited(models.JobRun{ID: "1", JobID: "capped"}, 1)
+
+	started := make(chan func())
+	go func() { started <- rq.WorkLimited(models.JobRun{ID: "2", JobID: "capped"}, 1) }()
+	g.Eventually(func() map[string]int { return rq.Stats().Queued }).Should(gomega.Equal(map[string]int{"capped": 1}))
+
+	other := rq.WorkLimited(models.JobRun{ID: "3", JobID: "other"}, 1)
+	assert.Equal(t, 2, rq.Stats().Busy)
+	other()
+
+	done()
+	(<-started)()
+	stats := rq.Stats()
+	assert.Equal(t, 0, stats.Busy)
+	assert.Equal(t, map[string]int{}, stats.Queued)
+}
@@ -0,0 +1,189 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Signer holds the key of the account the node sends transactions from,
+// and signs them with it. The KeyStore is a Signer holding the key on the
+// node's host, and a RemoteSigner asks another process to sign, so that
+// the key can be kept elsewhere. SignMessage signs data as eth_sign does,
+// prefixed with "\x19Ethereum Signed Message:\n" and its length, and
+// returns the 65 byte signature with a V of 27 or 28.
+type Signer interface {
+	HasAccounts() bool
+	GetAccount() accounts.Account
+	Unlocked() bool
+	SignTx(tx *types.Transaction, chainID uint64) (*types.Transaction, error)
+	SignMessage(data []byte) ([]byte, error)
+}
+
+// MessageHash returns the hash signed by SignMessage for the data.
+func MessageHash(data []byte) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)))
+}
+
+// RecoverMessageSigner returns the address of the account that made the
+// signature of the data with SignMessage.
+func RecoverMessageSigner(data, sig []byte) (common.Address, error) {
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		return common.Address{}, errors.New("Signature must be 65 bytes with a V of 27 or 28")
+	}
+	rsv := append([]byte{}, sig...)
+	rsv[64] -= 27
+	pub, err := crypto.SigToPub(MessageHash(data), rsv)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// The KEY_BACKEND values selecting where the node's key is kept.
+const (
+	// KeyBackendKeyStore keeps the key encrypted in the keys directory.
+	KeyBackendKeyStore = "keystore"
+	// KeyBackendVault keeps the key in a HashiCorp Vault secret, which the
+	// node reads and signs with itself.
+	KeyBackendVault = "vault"
+	// KeyBackendAWSKMS keeps the key in AWS KMS, which signs with it.
+	KeyBackendAWSKMS = "awskms"
+)
+
+// NewSigner returns the signer for the node's transactions: a remote signer
+// when SIGNER_URL is set, and otherwise the signer for KEY_BACKEND.
+func NewSigner(config Config, keyStore *KeyStore) (Signer, error) {
+	if config.SignerURL != "" {
+		return NewRemoteSigner(config.SignerURL, common.HexToAddress(config.SignerAddress))
+	}
+	switch config.KeyBackend {
+	case "", KeyBackendKeyStore:
+		return keyStore, nil
+	case KeyBackendVault:
+		return NewVaultKeySigner(config.VaultAddress, config.VaultToken, config.VaultKeyPath)
+	case KeyBackendAWSKMS:
+		return NewKMSSigner(config)
+	}
+	return nil, fmt.Errorf("Unknown KEY_BACKEND %v, must be keystore, vault, or awskms", config.KeyBackend)
+}
+
+// Caller makes a JSON-RPC call with the given arguments.
+type Caller interface {
+	Call(result interface{}, method string, args ...interface{}) error
+}
+
+// RemoteSigner signs transactions by calling eth_signTransaction on
+// another process, such as a geth node with a Ledger or Trezor plugged in,
+// which asks for each transaction to be confirmed on the device. The node
+// never holds the account's key.
+type RemoteSigner struct {
+	Caller  Caller
+	Account accounts.Account
+}
+
+// NewRemoteSigner returns a signer for the given account on the JSON-RPC
+// endpoint at url.
+func NewRemoteSigner(url string, address common.Address) (*RemoteSigner, error) {
+	if address == (common.Address{}) {
+		return nil, errors.New("SIGNER_ADDRESS must be set to use SIGNER_URL")
+	}
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteSigner{Caller: client, Account: accounts.Account{Address: address}}, nil
+}
+
+// HasAccounts returns true, as the remote signer always has an account.
+func (rs *RemoteSigner) HasAccounts() bool {
+	return true
+}
+
+// GetAccount returns the account the remote signer signs for.
+func (rs *RemoteSigner) GetAccount() accounts.Account {
+	return rs.Account
+}
+
+// Unlocked returns true if the remote signer lists the account as one it
+// can sign for.
+func (rs *RemoteSigner) Unlocked() bool {
+	var addresses []common.Address
+	if err := rs.Caller.Call(&addresses, "eth_accounts"); err != nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a == rs.Account.Address {
+			return true
+		}
+	}
+	return false
+}
+
+type signTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+}
+
+// SignTx asks the remote signer to sign the transaction, and checks that
+// what it signed is the given transaction, from the account, for the
+// chain.
+func (rs *RemoteSigner) SignTx(tx *types.Transaction, chainID uint64) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:     rs.Account.Address,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Value:    (*hexutil.Big)(tx.Value()),
+		Data:     tx.Data(),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+	}
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := rs.Caller.Call(&result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("Remote signer: %v", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(result.Raw, signed); err != nil {
+		return nil, fmt.Errorf("Remote signer returned an invalid transaction: %v", err)
+	}
+	from, err := types.Sender(types.NewEIP155Signer(big.NewInt(int64(chainID))), signed)
+	if err != nil || from != rs.Account.Address {
+		return nil, fmt.Errorf("Remote signer did not sign for %v on chain %v", rs.Account.Address.Hex(), chainID)
+	}
+	if homestead := (types.HomesteadSigner{}); homestead.Hash(signed) != homestead.Hash(tx) {
+		return nil, errors.New("Remote signer signed a different transaction")
+	}
+	return signed, nil
+}
+
+// SignMessage asks the remote signer to sign the data with eth_sign, and
+// checks that the signature is the account's.
+func (rs *RemoteSigner) SignMessage(data []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := rs.Caller.Call(&sig, "eth_sign", rs.Account.Address, hexutil.Bytes(data)); err != nil {
+		return nil, fmt.Errorf("Remote signer: %v", err)
+	}
+	if len(sig) == 65 && sig[64] < 27 {
+		sig[64] += 27
+	}
+	if from, err := RecoverMessageSigner(data, sig); err != nil || from != rs.Account.Address {
+		return nil, fmt.Errorf("Remote signer did not sign for %v", rs.Account.Address.Hex())
+	}
+	return sig, nil
+}
@@ -0,0 +1,276 @@
+package store_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	strpkg "github.com/smartcontractkit/chainlink/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// signingCaller answers eth_signTransaction the way geth does, signing
+// with its key, and changes the gas price first when tamper is set.
+type signingCaller struct {
+	key     *ecdsa.PrivateKey
+	chainID int64
+	tamper  bool
+}
+
+func (sc signingCaller) Call(result interface{}, method string, args ...interface{}) error {
+	switch method {
+	case "eth_accounts":
+		*result.(*[]common.Address) = []common.Address{crypto.PubkeyToAddress(sc.key.PublicKey)}
+		return nil
+	case "eth_signTransaction":
+		b, err := json.Marshal(args[0])
+		if err != nil {
+			return err
+		}
+		var a struct {
+			To       common.Address `json:"to"`
+			Gas      hexutil.Uint64 `json:"gas"`
+			GasPrice *hexutil.Big   `json:"gasPrice"`
+			Value    *hexutil.Big   `json:"value"`
+			Data     hexutil.Bytes  `json:"data"`
+			Nonce    hexutil.Uint64 `json:"nonce"`
+		}
+		if err := json.Unmarshal(b, &a); err != nil {
+			return err
+		}
+		gasPrice := a.GasPrice.ToInt()
+		if sc.tamper {
+			gasPrice = new(big.Int).Add(gasPrice, big.NewInt(1))
+		}
+		tx := types.NewTransaction(uint64(a.Nonce), a.To, a.Value.ToInt(), uint64(a.Gas), gasPrice, a.Data)
+		signed, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(sc.chainID)), sc.key)
+		if err != nil {
+			return err
+		}
+		raw, err := rlp.EncodeToBytes(signed)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal([]byte(`{"raw":"`+hexutil.Encode(raw)+`"}`), result)
+	case "eth_sign":
+		sig, err := crypto.Sign(strpkg.MessageHash(args[1].(hexutil.Bytes)), sc.key)
+		if err != nil {
+			return err
+		}
+		*result.(*hexutil.Bytes) = sig
+		return nil
+	}
+	return errors.New("unexpected method " + method)
+}
+
+func TestRemoteSigner_SignTx(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0xb70a511baC46ec6442aC6D598eaC327334e634dB")
+	tx := types.NewTransaction(7, to, big.NewInt(0), 500000, big.NewInt(20000000000), []byte{1, 2, 3})
+
+	tests := []struct {
+		name    string
+		caller  signingCaller
+		account common.Address
+		chainID uint64
+		wantErr bool
+	}{
+		{"signed", signingCaller{key: key, chainID: 3}, address, 3, false},
+		{"other account", signingCaller{key: key, chainID: 3}, to, 3, true},
+		{"other chain", signingCaller{key: key, chainID: 1}, address, 3, true},
+		{"changed transaction", signingCaller{key: key, chainID: 3, tamper: true}, address, 3, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signer := &strpkg.RemoteSigner{Caller: test.caller, Account: accounts.Account{Address: test.account}}
+			signed, err := signer.SignTx(tx, test.chainID)
+			if test.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			from, err := types.Sender(types.NewEIP155Signer(big.NewInt(3)), signed)
+			assert.Nil(t, err)
+			assert.Equal(t, address, from)
+			assert.Equal(t, tx.Nonce(), signed.Nonce())
+			assert.Equal(t, tx.Data(), signed.Data())
+		})
+	}
+}
+
+func TestRemoteSigner_SignMessage(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	data := []byte("fulfillment")
+
+	signer := &strpkg.RemoteSigner{Caller: signingCaller{key: key}, Account: accounts.Account{Address: address}}
+	sig, err := signer.SignMessage(data)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(27), sig[64]&^1, "V is 27 or 28, as geth returns")
+	from, err := strpkg.RecoverMessageSigner(data, sig)
+	assert.Nil(t, err)
+	assert.Equal(t, address, from)
+
+	signer.Account = accounts.Account{Address: common.HexToAddress("0xb70a511baC46ec6442aC6D598eaC327334e634dB")}
+	_, err = signer.SignMessage(data)
+	assert.NotNil(t, err)
+}
+
+func TestRemoteSigner_Unlocked(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	caller := signingCaller{key: key}
+
+	signer := &strpkg.RemoteSigner{Caller: caller, Account: accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)}}
+	assert.True(t, signer.Unlocked())
+	signer.Account = accounts.Account{Address: common.HexToAddress("0xb70a511baC46ec6442aC6D598eaC327334e634dB")}
+	assert.False(t, signer.Unlocked())
+}
+
+func TestVaultKeySigner(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	privateKey := hex.EncodeToString(crypto.FromECDSA(key))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(403)
+			return
+		}
+		assert.Equal(t, "/v1/secret/data/chainlink", r.URL.Path)
+		w.Write([]byte(`{"data": {"data": {"private_key": "` + privateKey + `"}}}`))
+	}))
+	defer server.Close()
+
+	_, err = strpkg.NewVaultKeySigner(server.URL, "wrong", "secret/data/chainlink")
+	assert.NotNil(t, err)
+
+	signer, err := strpkg.NewVaultKeySigner(server.URL, "s.token", "secret/data/chainlink")
+	assert.Nil(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	assert.Equal(t, address, signer.GetAccount().Address)
+
+	tx := types.NewTransaction(1, address, big.NewInt(0), 21000, big.NewInt(20000000000), nil)
+	signed, err := signer.SignTx(tx, 3)
+	assert.Nil(t, err)
+	from, err := types.Sender(types.NewEIP155Signer(big.NewInt(3)), signed)
+	assert.Nil(t, err)
+	assert.Equal(t, address, from)
+
+	sig, err := signer.SignMessage([]byte("fulfillment"))
+	assert.Nil(t, err)
+	from, err = strpkg.RecoverMessageSigner([]byte("fulfillment"), sig)
+	assert.Nil(t, err)
+	assert.Equal(t, address, from)
+}
+
+// kmsServer answers GetPublicKey and Sign as AWS KMS does for an
+// ECC_SECG_P256K1 key.
+func kmsServer(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/kms/aws4_request")
+		var req struct {
+			KeyID   string `json:"KeyId"`
+			Message []byte `json:"Message"`
+		}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "alias/chainlink", req.KeyID)
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			spki, err := asn1.Marshal(struct {
+				Algorithm pkix.AlgorithmIdentifier
+				PublicKey asn1.BitString
+			}{
+				pkix.AlgorithmIdentifier{
+					Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+					Parameters: asn1.RawValue{FullBytes: []byte{6, 5, 43, 129, 4, 0, 10}},
+				},
+				asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+			})
+			assert.Nil(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"PublicKey": spki})
+		case "TrentService.Sign":
+			sigR, sigS, err := ecdsa.Sign(rand.Reader, key, req.Message)
+			assert.Nil(t, err)
+			der, err := asn1.Marshal(struct{ R, S *big.Int }{sigR, sigS})
+			assert.Nil(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"Signature": der})
+		default:
+			w.WriteHeader(400)
+		}
+	}))
+}
+
+func TestKMSSigner(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	server := kmsServer(t, key)
+	defer server.Close()
+
+	config := strpkg.Config{
+		AWSRegion:          "us-east-1",
+		AWSAccessKeyID:     "AKID",
+		AWSSecretAccessKey: "secret",
+		AWSKMSKeyID:        "alias/chainlink",
+		AWSKMSEndpoint:     server.URL,
+	}
+	signer, err := strpkg.NewKMSSigner(config)
+	assert.Nil(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	assert.Equal(t, address, signer.GetAccount().Address)
+	assert.True(t, signer.Unlocked())
+
+	// Each signature is random, so sign enough to see both recovery IDs
+	// and high S values.
+	for i := uint64(0); i < 8; i++ {
+		tx := types.NewTransaction(i, address, big.NewInt(0), 21000, big.NewInt(20000000000), nil)
+		signed, err := signer.SignTx(tx, 3)
+		assert.Nil(t, err)
+		from, err := types.Sender(types.NewEIP155Signer(big.NewInt(3)), signed)
+		assert.Nil(t, err)
+		assert.Equal(t, address, from)
+
+		sig, err := signer.SignMessage(tx.Hash().Bytes())
+		assert.Nil(t, err)
+		from, err = strpkg.RecoverMessageSigner(tx.Hash().Bytes(), sig)
+		assert.Nil(t, err)
+		assert.Equal(t, address, from)
+	}
+}
+
+func TestNewSigner_UnknownBackend(t *testing.T) {
+	t.Parallel()
+
+	_, err := strpkg.NewSigner(strpkg.Config{KeyBackend: "floppy"}, nil)
+	assert.NotNil(t, err)
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync"
@@ -9,22 +10,43 @@ import (
 	"time"
 
 	"github.com/asdine/storm"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/store/models"
 )
 
-// Store contains fields for the database, Config, KeyStore, and TxManager
-// for keeping the application state in sync with the database.
+// Store contains fields for the database, Config, KeyStore, the Signer of
+// the node's transactions, and TxManager for keeping the application state
+// in sync with the database, and the Events published as runs progress,
+// the RunQueue of runs waiting to execute, and the Logs subscriptions
+// shared between jobs. Once the store is in use, Config is read with
+// CurrentConfig, as ChangeConfig replaces it.
 type Store struct {
 	*models.ORM
 	Config      Config
 	Clock       AfterNower
 	Exiter      func(int)
 	KeyStore    *KeyStore
+	Signer      Signer
 	TxManager   *TxManager
 	HeadTracker *HeadTracker
-	sigs        chan os.Signal
+	Events      *EventBroadcaster
+	RunQueue    *RunQueue
+	Logs        *LogMultiplexer
+	// EthDialer connects to the ethereum node at the given URL, for
+	// SwitchEthereum.
+	EthDialer func(url string) (CallerSubscriber, Subscriber, error)
+	sigs      chan os.Signal
+	hups      chan os.Signal
+	// configMutex keeps config changes and ethereum switches apart, and
+	// configLock keeps Config from being read while it is replaced.
+	configMutex sync.Mutex
+	configLock  sync.RWMutex
+	switchHooks []func() error
+	vrfKey      *VRFKey
+	vrfMutex    sync.RWMutex
 }
 
 // NewStore will create a new database file at the config's RootDir if
@@ -36,37 +58,120 @@ func NewStore(config Config) *Store {
 		logger.Fatal(err)
 	}
 	orm := models.NewORM(config.RootDir)
-	ethrpc, err := rpc.Dial(config.EthereumURL)
+	if err := orm.MigrateUp(models.Migrations); err != nil {
+		logger.Fatal(err)
+	}
+	if err := createCredentialsKey(config); err != nil {
+		logger.Fatal(err)
+	}
+	if err := orm.SeedUser(config.BasicAuthUsername, config.BasicAuthPassword); err != nil {
+		logger.Fatal(err)
+	}
+	caller, logSubscriber, err := dialEthereum(config, config.EthereumURL)
 	if err != nil {
 		logger.Fatal(err)
 	}
 	keyStore := NewKeyStore(config.KeysDir())
+	signer, err := NewSigner(config, keyStore)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	var archive CallerSubscriber
+	if config.ArchiveEthereumURL != "" {
+		archiverpc, err := rpc.Dial(config.ArchiveEthereumURL)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		archive = limitCalls(archiverpc, config)
+	}
 
 	ht, err := NewHeadTracker(orm)
 	if err != nil {
 		logger.Fatal(err)
 	}
 
+	ethSwitch := NewEthSwitch(caller, logSubscriber)
+	ethClient := &EthClient{
+		CallerSubscriber: ethSwitch,
+		LogSubscriber:    ethSwitch,
+		Archive:          archive,
+		ArchiveBlockAge:  config.ArchiveBlockAge,
+		MaxBatchSize:     int(config.EthMaxBatchSize),
+	}
+	events := NewEventBroadcaster()
 	store := &Store{
 		ORM:         orm,
 		Config:      config,
 		KeyStore:    keyStore,
+		Signer:      signer,
 		Exiter:      os.Exit,
 		Clock:       Clock{},
 		HeadTracker: ht,
+		Events:      events,
+		RunQueue:    NewRunQueue(int(config.MaxConcurrentRuns)),
 		TxManager: &TxManager{
 			Config:    config,
-			EthClient: &EthClient{ethrpc},
-			KeyStore:  keyStore,
+			EthClient: ethClient,
+			Signer:    signer,
 			ORM:       orm,
+			Events:    events,
 		},
 	}
+	store.EthDialer = func(url string) (CallerSubscriber, Subscriber, error) {
+		return dialEthereum(store.CurrentConfig(), url)
+	}
+	store.Logs = NewLogMultiplexer(store.subscribeToLogs)
 	return store
 }
 
+// limitCalls caps the calls outstanding to the client at
+// ETH_MAX_CONCURRENT_REQUESTS, when it is set, and their weight per second
+// at ETH_RATE_LIMIT, when it is set.
+func limitCalls(client *rpc.Client, config Config) CallerSubscriber {
+	var caller CallerSubscriber = client
+	if config.EthMaxConcurrentRequests > 0 {
+		caller = NewLimitedCaller(client, int(config.EthMaxConcurrentRequests), config.EthRequestTimeout)
+	}
+	return NewRateLimitedCaller(caller, config.EthRateLimit, config.EthRateLimitWeights, config.EthRequestTimeout, Clock{})
+}
+
+// dialEthereum connects to the ethereum node at url, and to the pool of
+// connections that log subscriptions are spread across when
+// ETH_SUBSCRIPTION_CONNECTIONS is more than 1.
+func dialEthereum(config Config, url string) (CallerSubscriber, Subscriber, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config.EthSubscriptionConnections <= 1 {
+		return limitCalls(client, config), nil, nil
+	}
+	pool, err := NewSubscriptionPool(int(config.EthSubscriptionConnections), func() (Subscriber, error) {
+		client, err := rpc.Dial(url)
+		return rpcSubscriber{client}, err
+	})
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return limitCalls(client, config), pool, nil
+}
+
+// subscribeToLogs subscribes to the logs matching the query, polling for
+// them instead if the ethereum endpoint does not support subscriptions.
+func (s *Store) subscribeToLogs(channel chan<- types.Log, q ethereum.FilterQuery) (EthSubscription, error) {
+	sub, err := s.TxManager.SubscribeToLogs(channel, q)
+	if !NotificationsUnsupported(err) {
+		return sub, err
+	}
+	logger.Subscription.Infow("Ethereum node does not support subscriptions, polling for logs", "interval", s.CurrentConfig().EthPollInterval)
+	return s.TxManager.PollLogs(channel, q, s.Clock), nil
+}
+
 // Start listens for interrupt signals from the operating system so
 // that the database can be properly closed before the application
-// exits.
+// exits, and for SIGHUP to reload the runtime settings.
 func (s *Store) Start() {
 	s.sigs = make(chan os.Signal, 1)
 	signal.Notify(s.sigs, syscall.SIGINT, syscall.SIGTERM)
@@ -75,6 +180,160 @@ func (s *Store) Start() {
 		s.Close()
 		s.Exiter(1)
 	}()
+
+	s.hups = make(chan os.Signal, 1)
+	signal.Notify(s.hups, syscall.SIGHUP)
+	go func() {
+		for range s.hups {
+			if _, err := s.ReloadConfig("SIGHUP"); err != nil {
+				logger.Errorw("Reloading config", "error", err)
+			}
+		}
+	}()
+}
+
+// ReloadConfig reads the config file and environment again, and changes
+// each runtime setting whose value differs from the node's, as
+// ChangeConfig does. Other settings are left until the node is restarted.
+// Nothing is restarted, so subscriptions and runs in progress carry on
+// with the new values.
+func (s *Store) ReloadConfig(changedBy string) ([]models.ConfigChange, error) {
+	fresh, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if fresh.Profile == "" && s.CurrentConfig().Profile != "" {
+		if err := fresh.UseProfile(s.CurrentConfig().Profile); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := []models.ConfigChange{}
+	for _, key := range RuntimeSettings() {
+		value := fresh.setting(key)
+		if value == s.CurrentConfig().setting(key) {
+			continue
+		}
+		change, err := s.ChangeConfig(key, value, changedBy)
+		if err != nil {
+			return changes, err
+		}
+		changes = append(changes, change)
+	}
+	logger.Infow("Reloaded config", "changes", len(changes), "changedBy", changedBy)
+	return changes, nil
+}
+
+// ChangeConfig changes the given runtime setting for the store and its
+// TxManager, and records who changed it from what in the config history.
+// Changing ETH_URL switches the node to the ethereum node at the new URL,
+// and the setting is left as it was if that fails. Changes last until the
+// node is restarted.
+func (s *Store) ChangeConfig(key, value, changedBy string) (models.ConfigChange, error) {
+	s.configMutex.Lock()
+	defer s.configMutex.Unlock()
+	config := s.CurrentConfig()
+	previous, err := config.Change(key, value)
+	if err != nil {
+		return models.ConfigChange{}, err
+	}
+	if key == "ETH_URL" && value != previous {
+		if err := s.switchEthereum(value); err != nil {
+			return models.ConfigChange{}, err
+		}
+	}
+	change := models.ConfigChange{
+		Setting:   key,
+		From:      redactURL(previous),
+		To:        redactURL(value),
+		ChangedBy: changedBy,
+		CreatedAt: s.Clock.Now(),
+	}
+	if err := s.Save(&change); err != nil {
+		return change, err
+	}
+	s.configLock.Lock()
+	s.Config = config
+	s.configLock.Unlock()
+	s.TxManager.SetConfig(config)
+	if key == "LOG_LEVEL" {
+		logger.SetLevel(config.LogLevel.Level)
+	}
+	logger.Infow("Changed config", "setting", key, "from", change.From, "to", change.To, "changedBy", changedBy)
+	return change, nil
+}
+
+// CurrentConfig returns a copy of the store's config, as last changed by
+// ChangeConfig.
+func (s *Store) CurrentConfig() Config {
+	s.configLock.RLock()
+	defer s.configLock.RUnlock()
+	return s.Config
+}
+
+// OnEthereumSwitch calls f each time the node switches to another ethereum
+// node, once calls go to the new node, so that subscriptions can be moved
+// to it.
+func (s *Store) OnEthereumSwitch(f func() error) {
+	s.configMutex.Lock()
+	defer s.configMutex.Unlock()
+	s.switchHooks = append(s.switchHooks, f)
+}
+
+// switchEthereum connects to the ethereum node at url, and checks that it
+// is on the same chain as ETH_CHAIN_ID, or as the current node if that is
+// not set. Calls then go to the new node, subscriptions are moved to it,
+// and unconfirmed transactions are broadcast to it again, before the
+// connection to the old node is closed.
+func (s *Store) switchEthereum(url string) error {
+	ethSwitch, ok := s.TxManager.CallerSubscriber.(*EthSwitch)
+	if !ok {
+		return errors.New("The connection to the ethereum node cannot be switched")
+	}
+	caller, logs, err := s.EthDialer(url)
+	if err != nil {
+		return fmt.Errorf("Connecting to %v: %v", redactURL(url), err)
+	}
+	if err := s.verifyChainID(caller, url); err != nil {
+		closeEthereum(caller, logs)
+		return err
+	}
+
+	oldCaller, oldLogs := ethSwitch.Switch(caller, logs)
+	logger.Infow("Switched ethereum node", "url", redactURL(url))
+	for _, hook := range s.switchHooks {
+		if err := hook(); err != nil {
+			logger.Errorw("Moving subscriptions to the new ethereum node", "err", err)
+		}
+	}
+	if sent, err := s.TxManager.RebroadcastUnconfirmed(); err != nil {
+		logger.Errorw("Broadcasting unconfirmed transactions to the new ethereum node", "err", err, "sent", sent)
+	} else if sent > 0 {
+		logger.Infow("Broadcast unconfirmed transactions to the new ethereum node", "sent", sent)
+	}
+	closeEthereum(oldCaller, oldLogs)
+	return nil
+}
+
+// verifyChainID returns an error unless the node at url is on ETH_CHAIN_ID,
+// or the same chain as the current node if that is not set. If neither is
+// known, the node at url is trusted.
+func (s *Store) verifyChainID(caller CallerSubscriber, url string) error {
+	chainID, err := (&EthClient{CallerSubscriber: caller}).GetChainID()
+	if err != nil {
+		return fmt.Errorf("Getting the chain ID of %v: %v", redactURL(url), err)
+	}
+	want := s.CurrentConfig().ChainID
+	if want == 0 {
+		if want, err = s.TxManager.GetChainID(); err != nil {
+			logger.Warnw("Could not get the chain ID of the current ethereum node to compare", "err", err)
+			return nil
+		}
+	}
+	if chainID != want {
+		return fmt.Errorf("Ethereum node at %v is on chain %v, not %v", redactURL(url), chainID, want)
+	}
+	return nil
 }
 
 // AfterNower is an interface that fulfills the `After()` and `Now()`
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/logger"
+)
+
+// EthSubscription is a subscription to push notifications from the
+// ethereum node. The channel returned by Err receives an error if the
+// subscription fails, and is closed once it is unsubscribed.
+type EthSubscription interface {
+	Err() <-chan error
+	Unsubscribe()
+}
+
+// Subscriber registers subscriptions for push notifications, such as
+// "logs", sent to the given channel.
+type Subscriber interface {
+	Subscribe(channel interface{}, args ...interface{}) (EthSubscription, error)
+}
+
+// rpcSubscriber subscribes through a single connection to the ethereum node.
+type rpcSubscriber struct {
+	CallerSubscriber
+}
+
+// Subscribe registers the subscription on the connection.
+func (rs rpcSubscriber) Subscribe(channel interface{}, args ...interface{}) (EthSubscription, error) {
+	sub, err := rs.EthSubscribe(context.Background(), channel, args...)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Close closes the connection.
+func (rs rpcSubscriber) Close() {
+	closeEthereum(rs.CallerSubscriber)
+}
+
+// redialBackoff is how long to wait before redialing a dropped connection,
+// doubling with every failed attempt up to maxRedialBackoff.
+const (
+	redialBackoff    = time.Second
+	maxRedialBackoff = time.Minute
+)
+
+// SubscriptionPool spreads subscriptions across a pool of connections to
+// the same ethereum node, so that providers' limits on the number of
+// subscriptions per connection are not hit. Each subscription is placed on
+// the connection with the fewest subscriptions. When a connection drops,
+// its subscriptions are moved to the remaining connections and it is
+// redialed in the background.
+type SubscriptionPool struct {
+	dial        func() (Subscriber, error)
+	connections []*poolConnection
+	waiting     map[*PooledSubscription]bool
+	closed      bool
+	mutex       sync.Mutex
+}
+
+type poolConnection struct {
+	subscriber    Subscriber
+	alive         bool
+	subscriptions map[*PooledSubscription]bool
+}
+
+// NewSubscriptionPool dials size connections with the given function.
+func NewSubscriptionPool(size int, dial func() (Subscriber, error)) (*SubscriptionPool, error) {
+	if size < 1 {
+		return nil, errors.New("A subscription pool needs at least one connection")
+	}
+	pool := &SubscriptionPool{
+		dial:    dial,
+		waiting: map[*PooledSubscription]bool{},
+	}
+	for i := 0; i < size; i++ {
+		subscriber, err := dial()
+		if err != nil {
+			return nil, err
+		}
+		pool.connections = append(pool.connections, &poolConnection{
+			subscriber:    subscriber,
+			alive:         true,
+			subscriptions: map[*PooledSubscription]bool{},
+		})
+	}
+	return pool, nil
+}
+
+// Subscribe registers the subscription on the least used connection.
+func (pool *SubscriptionPool) Subscribe(channel interface{}, args ...interface{}) (EthSubscription, error) {
+	sub := &PooledSubscription{
+		pool:    pool,
+		channel: channel,
+		args:    args,
+		errors:  make(chan error),
+	}
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if err := pool.place(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Close closes every connection in the pool, and stops redialing those
+// that dropped. Subscriptions still in the pool receive nothing more.
+func (pool *SubscriptionPool) Close() {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	pool.closed = true
+	for _, conn := range pool.connections {
+		closeEthereum(conn.subscriber)
+	}
+}
+
+// Load returns the number of subscriptions on each connection, with -1 for
+// connections that are being redialed.
+func (pool *SubscriptionPool) Load() []int {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	load := make([]int, len(pool.connections))
+	for i, conn := range pool.connections {
+		if conn.alive {
+			load[i] = len(conn.subscriptions)
+		} else {
+			load[i] = -1
+		}
+	}
+	return load
+}
+
+// place subscribes on the live connection with the fewest subscriptions,
+// trying the others if that fails. If no connection is alive, the
+// subscription waits for one to be redialed.
+func (pool *SubscriptionPool) place(sub *PooledSubscription) error {
+	sub.connection = nil
+	sub.current = nil
+	tried := map[*poolConnection]bool{}
+	var lastErr error
+	for {
+		var least *poolConnection
+		for _, conn := range pool.connections {
+			if conn.alive && !tried[conn] && (least == nil || len(conn.subscriptions) < len(least.subscriptions)) {
+				least = conn
+			}
+		}
+		if least == nil {
+			break
+		}
+		tried[least] = true
+
+		rpcSub, err := least.subscriber.Subscribe(sub.channel, sub.args...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sub.connection = least
+		sub.current = rpcSub
+		least.subscriptions[sub] = true
+		go pool.watch(sub, least, rpcSub)
+		return nil
+	}
+
+	if lastErr != nil {
+		return lastErr
+	}
+	pool.waiting[sub] = true
+	return nil
+}
+
+// move places a subscription whose connection dropped, leaving it to wait
+// for a redialed connection if none of the others take it.
+func (pool *SubscriptionPool) move(sub *PooledSubscription) {
+	if err := pool.place(sub); err != nil {
+		logger.Subscription.Warnw("Moving subscription", "err", err)
+		pool.waiting[sub] = true
+	}
+}
+
+// watch moves the subscription's connection out of the pool if the
+// subscription fails while it is still current.
+func (pool *SubscriptionPool) watch(sub *PooledSubscription, conn *poolConnection, rpcSub EthSubscription) {
+	err, ok := <-rpcSub.Err()
+	if !ok {
+		return
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if sub.current != rpcSub || sub.unsubscribed || pool.closed {
+		return
+	}
+	logger.Subscription.Warnw("Subscription connection dropped, moving its subscriptions", "err", err)
+	pool.drop(conn)
+}
+
+// drop takes the connection out of the pool, moves its subscriptions to
+// the remaining connections, and redials it.
+func (pool *SubscriptionPool) drop(conn *poolConnection) {
+	if !conn.alive {
+		return
+	}
+	conn.alive = false
+	orphans := conn.subscriptions
+	conn.subscriptions = map[*PooledSubscription]bool{}
+	for sub := range orphans {
+		pool.move(sub)
+	}
+	go pool.redial(conn)
+}
+
+func (pool *SubscriptionPool) redial(conn *poolConnection) {
+	backoff := redialBackoff
+	for {
+		pool.mutex.Lock()
+		closed := pool.closed
+		pool.mutex.Unlock()
+		if closed {
+			return
+		}
+
+		subscriber, err := pool.dial()
+		if err == nil {
+			pool.mutex.Lock()
+			if pool.closed {
+				pool.mutex.Unlock()
+				closeEthereum(subscriber)
+				return
+			}
+			conn.subscriber = subscriber
+			conn.alive = true
+			waiting := pool.waiting
+			pool.waiting = map[*PooledSubscription]bool{}
+			for sub := range waiting {
+				pool.move(sub)
+			}
+			pool.mutex.Unlock()
+			return
+		}
+
+		logger.Subscription.Warnw("Redialing subscription connection", "err", err, "retryIn", backoff)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxRedialBackoff {
+			backoff = maxRedialBackoff
+		}
+	}
+}
+
+// PooledSubscription is a subscription made through a SubscriptionPool,
+// which keeps delivering to the same channel when it is moved to another
+// connection.
+type PooledSubscription struct {
+	pool         *SubscriptionPool
+	channel      interface{}
+	args         []interface{}
+	connection   *poolConnection
+	current      EthSubscription
+	errors       chan error
+	unsubscribed bool
+}
+
+// Err returns a channel that is closed once the subscription is
+// unsubscribed. Errors on the subscription's connection are handled by
+// moving it, so none are sent.
+func (sub *PooledSubscription) Err() <-chan error {
+	return sub.errors
+}
+
+// Unsubscribe cancels the subscription on whichever connection it is on.
+func (sub *PooledSubscription) Unsubscribe() {
+	pool := sub.pool
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if sub.unsubscribed {
+		return
+	}
+	sub.unsubscribed = true
+	delete(pool.waiting, sub)
+	if sub.connection != nil {
+		delete(sub.connection.subscriptions, sub)
+	}
+	if sub.current != nil {
+		sub.current.Unsubscribe()
+	}
+	close(sub.errors)
+}
@@ -0,0 +1,115 @@
+package store_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSubscription struct {
+	errors chan error
+}
+
+func (fs *fakeSubscription) Err() <-chan error { return fs.errors }
+func (fs *fakeSubscription) Unsubscribe()      {}
+
+type fakeSubscriber struct {
+	subscriptions []*fakeSubscription
+	mutex         sync.Mutex
+}
+
+func (fs *fakeSubscriber) Subscribe(channel interface{}, args ...interface{}) (store.EthSubscription, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	sub := &fakeSubscription{errors: make(chan error, 1)}
+	fs.subscriptions = append(fs.subscriptions, sub)
+	return sub, nil
+}
+
+func (fs *fakeSubscriber) drop() {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	for _, sub := range fs.subscriptions {
+		sub.errors <- errors.New("connection dropped")
+	}
+}
+
+func newFakePool(t *testing.T, size int) (*store.SubscriptionPool, *[]*fakeSubscriber) {
+	var mutex sync.Mutex
+	dialed := []*fakeSubscriber{}
+	pool, err := store.NewSubscriptionPool(size, func() (store.Subscriber, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		subscriber := &fakeSubscriber{}
+		dialed = append(dialed, subscriber)
+		return subscriber, nil
+	})
+	assert.Nil(t, err)
+	return pool, &dialed
+}
+
+func TestSubscriptionPool_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := newFakePool(t, 3)
+	for i := 0; i < 7; i++ {
+		_, err := pool.Subscribe(make(chan int), "logs")
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []int{3, 2, 2}, pool.Load())
+}
+
+func TestSubscriptionPool_Drop(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	pool, dialed := newFakePool(t, 2)
+	for i := 0; i < 4; i++ {
+		_, err := pool.Subscribe(make(chan int), "logs")
+		assert.Nil(t, err)
+	}
+	first := (*dialed)[0]
+
+	first.drop()
+	g.Eventually(pool.Load).Should(gomega.Equal([]int{0, 4}))
+
+	sub, err := pool.Subscribe(make(chan int), "logs")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 4}, pool.Load())
+
+	sub.Unsubscribe()
+	assert.Equal(t, []int{0, 4}, pool.Load())
+	_, open := <-sub.Err()
+	assert.False(t, open)
+}
+
+func TestSubscriptionPool_Redial(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	pool, dialed := newFakePool(t, 1)
+	_, err := pool.Subscribe(make(chan int), "logs")
+	assert.Nil(t, err)
+
+	(*dialed)[0].drop()
+	g.Eventually(pool.Load).Should(gomega.Equal([]int{1}))
+	assert.Len(t, (*dialed)[1].subscriptions, 1)
+}
+
+func TestNewSubscriptionPool_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := store.NewSubscriptionPool(0, func() (store.Subscriber, error) {
+		return &fakeSubscriber{}, nil
+	})
+	assert.NotNil(t, err)
+
+	_, err = store.NewSubscriptionPool(2, func() (store.Subscriber, error) {
+		return nil, errors.New("dial failed")
+	})
+	assert.NotNil(t, err)
+}
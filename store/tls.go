@@ -0,0 +1,87 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"time"
+)
+
+// selfSignedValidity is how long a generated self-signed certificate is
+// valid for.
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// CertFiles returns the paths of the certificate and key to serve HTTPS
+// with. When TLSSelfSigned is set, a self-signed certificate for localhost
+// is generated in TLSDir the first time it is needed and reused after that.
+// Self-signed certificates are meant for development only.
+func (c Config) CertFiles() (string, string, error) {
+	if !c.TLSSelfSigned {
+		return c.TLSCertPath, c.TLSKeyPath, nil
+	}
+
+	certPath := path.Join(c.TLSDir(), "server.crt")
+	keyPath := path.Join(c.TLSDir(), "server.key")
+	if _, err := os.Stat(certPath); err == nil {
+		if _, err := os.Stat(keyPath); err == nil {
+			return certPath, keyPath, nil
+		}
+	}
+	if err := os.MkdirAll(c.TLSDir(), os.FileMode(0700)); err != nil {
+		return "", "", err
+	}
+	return certPath, keyPath, generateSelfSignedCert(certPath, keyPath)
+}
+
+func generateSelfSignedCert(certPath, keyPath string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Chainlink Node"}},
+		NotBefore:             now,
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := writePEM(certPath, "CERTIFICATE", der, 0644); err != nil {
+		return err
+	}
+	return writePEM(keyPath, "EC PRIVATE KEY", keyDER, 0600)
+}
+
+func writePEM(filepath, blockType string, bytes []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return pem.Encode(file, &pem.Block{Type: blockType, Bytes: bytes})
+}
@@ -1,57 +1,313 @@
 package store
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/metrics"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/utils"
+	"go.uber.org/multierr"
 )
 
 const defaultGasLimit uint64 = 500000
 
 // TxManager contains fields for the Ethereum client, the KeyStore,
-// the local Config for the application, and the database.
+// the local Config for the application, and the database. Confirmed
+// transactions are published to Events. Once it is in use, Config is
+// read with CurrentConfig and replaced with SetConfig.
 type TxManager struct {
 	*EthClient
-	KeyStore *KeyStore
-	Config   Config
-	ORM      *models.ORM
+	Signer      Signer
+	Config      Config
+	ORM         *models.ORM
+	Events      *EventBroadcaster
+	configMutex sync.RWMutex
+}
+
+// CurrentConfig returns a copy of the TxManager's config.
+func (txm *TxManager) CurrentConfig() Config {
+	txm.configMutex.RLock()
+	defer txm.configMutex.RUnlock()
+	return txm.Config
+}
+
+// SetConfig replaces the TxManager's config, for the transactions it
+// sends and bumps from then on.
+func (txm *TxManager) SetConfig(config Config) {
+	txm.configMutex.Lock()
+	defer txm.configMutex.Unlock()
+	txm.Config = config
 }
 
 // CreateTx signs and sends a transaction to the Ethereum blockchain.
 func (txm *TxManager) CreateTx(to common.Address, data []byte) (*models.Tx, error) {
-	account := txm.KeyStore.GetAccount()
-	nonce, err := txm.GetNonce(account.Address)
+	return txm.createTx("", to, data, defaultGasLimit)
+}
+
+// DeployContract signs and sends a transaction creating a contract with the
+// given code, and returns it with the address the contract will have once
+// it is mined.
+func (txm *TxManager) DeployContract(code []byte, gasLimit uint64) (*models.Tx, common.Address, error) {
+	tx, err := txm.saveAndSendTx(&models.Tx{
+		Data:             code,
+		Value:            big.NewInt(0),
+		GasLimit:         gasLimit,
+		ContractCreation: true,
+	})
 	if err != nil {
+		return tx, common.Address{}, err
+	}
+	return tx, crypto.CreateAddress(tx.From, tx.Nonce), nil
+}
+
+// CreateTxWithGasLimit signs and sends a transaction to the Ethereum
+// blockchain with the given gas limit, such as a plain transfer's 21000.
+func (txm *TxManager) CreateTxWithGasLimit(to common.Address, data []byte, gasLimit uint64) (*models.Tx, error) {
+	return txm.createTx("", to, data, gasLimit)
+}
+
+// CreateTxForRun signs and sends a transaction for the given run, unless
+// the run already created one with the same destination and data, as when
+// it is resumed after the node stopped, in which case that transaction is
+// returned instead of being sent twice. One saved before the node could
+// sign it is signed and sent then.
+func (txm *TxManager) CreateTxForRun(runID string, to common.Address, data []byte) (*models.Tx, error) {
+	if runID == "" {
+		return txm.CreateTx(to, data)
+	}
+	txs := []models.Tx{}
+	if err := txm.ORM.Where("JobRunID", runID, &txs); err != nil {
 		return nil, err
 	}
-	tx, err := txm.ORM.CreateTx(
-		account.Address,
-		nonce,
-		to,
-		data,
-		big.NewInt(0),
-		defaultGasLimit,
-	)
+	for i := range txs {
+		tx := &txs[i]
+		if tx.To != to || !bytes.Equal(tx.Data, data) {
+			continue
+		}
+		if tx.Hash != (common.Hash{}) {
+			return tx, nil
+		}
+		return tx, txm.sendFirstAttempt(tx)
+	}
+	return txm.createTx(runID, to, data, defaultGasLimit)
+}
+
+func (txm *TxManager) createTx(runID string, to common.Address, data []byte, gasLimit uint64) (*models.Tx, error) {
+	return txm.saveAndSendTx(&models.Tx{
+		JobRunID: runID,
+		To:       to,
+		Data:     data,
+		Value:    big.NewInt(0),
+		GasLimit: gasLimit,
+	})
+}
+
+// saveAndSendTx sets the transaction to be from the node's account with its
+// next nonce, and saves and sends it.
+func (txm *TxManager) saveAndSendTx(tx *models.Tx) (*models.Tx, error) {
+	account := txm.Signer.GetAccount()
+	nonce, err := txm.GetNonce(account.Address)
 	if err != nil {
+		return nil, classifyTxError(err)
+	}
+	tx.From = account.Address
+	tx.Nonce = nonce
+	if err := txm.ORM.Save(tx); err != nil {
 		return nil, err
 	}
+	return tx, txm.sendFirstAttempt(tx)
+}
+
+func (txm *TxManager) sendFirstAttempt(tx *models.Tx) error {
 	blkNum, err := txm.GetBlockNumber()
 	if err != nil {
-		return nil, err
+		return classifyTxError(err)
 	}
+	config := txm.CurrentConfig()
+	_, err = txm.createAttempt(tx, &config.EthGasPriceDefault, blkNum)
+	return err
+}
 
-	gasPrice := &txm.Config.EthGasPriceDefault
-	_, err = txm.createAttempt(tx, gasPrice, blkNum)
+// BuildUnsignedTx returns a transaction from the given account, with its
+// next nonce and the default gas price, for the operator to sign offline.
+// A gas limit of 0 uses the default.
+func (txm *TxManager) BuildUnsignedTx(
+	from, to common.Address,
+	data []byte,
+	value *big.Int,
+	gasLimit uint64,
+) (models.UnsignedTx, error) {
+	nonce, err := txm.GetNonce(from)
 	if err != nil {
-		return tx, err
+		return models.UnsignedTx{}, classifyTxError(err)
+	}
+	if gasLimit == 0 {
+		gasLimit = defaultGasLimit
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	config := txm.CurrentConfig()
+	return models.UnsignedTx{
+		From:     from,
+		To:       to,
+		Nonce:    nonce,
+		Value:    value,
+		GasLimit: gasLimit,
+		GasPrice: new(big.Int).Set(&config.EthGasPriceDefault),
+		Data:     data,
+		ChainID:  config.ChainID,
+	}, nil
+}
+
+// BroadcastSignedTx sends a transaction signed offline, given as hex
+// encoded RLP, and returns its hash.
+func (txm *TxManager) BroadcastSignedTx(signed string) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(signed), tx); err != nil {
+		return common.Hash{}, fmt.Errorf("Decoding signed transaction: %v", err)
+	}
+	if err := txm.sendTransaction(tx); err != nil {
+		return common.Hash{}, err
 	}
+	logger.TxManager.Infow(fmt.Sprintf("Broadcast offline signed tx %v", tx.Hash().String()), "nonce", tx.Nonce())
+	return tx.Hash(), nil
+}
 
-	return tx, nil
+// QueueCall saves a call to the given contract, to be sent with the other
+// queued calls to it by the next SendBatches.
+func (txm *TxManager) QueueCall(
+	to common.Address,
+	batchSelector models.FunctionSelector,
+	data []byte,
+) (*models.BatchedCall, error) {
+	return txm.QueueCallForRun("", to, batchSelector, data)
+}
+
+// QueueCallForRun is QueueCall for the given run. Like CreateTxForRun, a
+// call the run already queued with the same destination and data is
+// returned rather than queued twice.
+func (txm *TxManager) QueueCallForRun(
+	runID string,
+	to common.Address,
+	batchSelector models.FunctionSelector,
+	data []byte,
+) (*models.BatchedCall, error) {
+	if runID != "" {
+		calls := []models.BatchedCall{}
+		if err := txm.ORM.Where("JobRunID", runID, &calls); err != nil {
+			return nil, err
+		}
+		for i := range calls {
+			if calls[i].To == to && bytes.Equal(calls[i].Data, data) {
+				return &calls[i], nil
+			}
+		}
+	}
+	call := &models.BatchedCall{JobRunID: runID, To: to, BatchSelector: batchSelector, Data: data}
+	return call, txm.ORM.Save(call)
+}
+
+// SendBatches sends the queued calls, in one transaction per contract and
+// batch method. The calls' data are passed as the batch method's single
+// bytes[] argument, in the order they were queued. As each call adds to the
+// transaction's gas limit, a transaction holds at most ETH_MAX_BATCH_CALLS
+// calls, and the calls after those stay queued for the next SendBatches,
+// as do calls whose transaction fails to send.
+func (txm *TxManager) SendBatches() error {
+	calls := []models.BatchedCall{}
+	if err := txm.ORM.Where("Sent", false, &calls); err != nil {
+		return err
+	}
+
+	batches := map[string][]models.BatchedCall{}
+	keys := []string{}
+	for _, call := range calls {
+		key := call.To.Hex() + call.BatchSelector.String()
+		if _, ok := batches[key]; !ok {
+			keys = append(keys, key)
+		}
+		batches[key] = append(batches[key], call)
+	}
+
+	max := int(txm.CurrentConfig().EthMaxBatchCalls)
+	if max < 1 {
+		max = 1
+	}
+	var merr error
+	for _, key := range keys {
+		batch := batches[key]
+		if len(batch) > max {
+			batch = batch[:max]
+		}
+		merr = multierr.Append(merr, txm.sendBatch(batch))
+	}
+	return merr
+}
+
+func (txm *TxManager) sendBatch(calls []models.BatchedCall) error {
+	datas := make([][]byte, len(calls))
+	for i, call := range calls {
+		datas[i] = call.Data
+	}
+	data := encodeBatch(calls[0].BatchSelector, datas)
+	gasLimit := defaultGasLimit * uint64(len(calls))
+	tx, err := txm.createTx("", calls[0].To, data, gasLimit)
+	if tx == nil || tx.Hash == (common.Hash{}) {
+		return err
+	}
+	// Once an attempt is saved, a failed broadcast is retried by bumping
+	// gas, so the calls must not be sent again in another transaction.
+	logger.TxManager.Infow(
+		fmt.Sprintf("Sent %v batched calls in tx %v", len(calls), tx.Hash.String()),
+		"to", calls[0].To.Hex(),
+	)
+
+	for _, call := range calls {
+		call.Sent = true
+		call.TxHash = tx.Hash
+		if err := txm.ORM.Save(&call); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// encodeBatch ABI encodes the calls as the bytes[] argument of the batch
+// method with the given selector.
+func encodeBatch(selector models.FunctionSelector, calls [][]byte) []byte {
+	word := func(i int) []byte {
+		return common.LeftPadBytes(big.NewInt(int64(i)).Bytes(), 32)
+	}
+
+	head := []byte{}
+	tail := []byte{}
+	for _, call := range calls {
+		head = append(head, word(len(calls)*32+len(tail))...)
+		padded := (len(call) + 31) / 32 * 32
+		tail = append(tail, word(len(call))...)
+		tail = append(tail, common.RightPadBytes(call, padded)...)
+	}
+
+	encoded := append([]byte{}, selector[:]...)
+	encoded = append(encoded, word(32)...)
+	encoded = append(encoded, word(len(calls))...)
+	encoded = append(encoded, head...)
+	return append(encoded, tail...)
 }
 
 // EnsureTxConfirmed returns true if the given transaction hash has been
@@ -59,7 +315,7 @@ func (txm *TxManager) CreateTx(to common.Address, data []byte) (*models.Tx, erro
 func (txm *TxManager) EnsureTxConfirmed(hash common.Hash) (bool, error) {
 	blkNum, err := txm.GetBlockNumber()
 	if err != nil {
-		return false, err
+		return false, classifyTxError(err)
 	}
 	attempts, err := txm.getAttempts(hash)
 	if err != nil {
@@ -73,8 +329,17 @@ func (txm *TxManager) EnsureTxConfirmed(hash common.Hash) (bool, error) {
 		return false, err
 	}
 
-	for _, txat := range attempts {
-		success, err := txm.checkAttempt(&tx, &txat, blkNum)
+	hashes := make([]common.Hash, len(attempts))
+	for i, txat := range attempts {
+		hashes[i] = txat.Hash
+	}
+	receipts, err := txm.GetTxReceipts(hashes)
+	if err != nil {
+		return false, classifyTxError(err)
+	}
+
+	for i, txat := range attempts {
+		success, err := txm.checkAttempt(&tx, &txat, receipts[i], blkNum)
 		if success {
 			return success, err
 		}
@@ -82,13 +347,55 @@ func (txm *TxManager) EnsureTxConfirmed(hash common.Hash) (bool, error) {
 	return false, nil
 }
 
+// RebroadcastUnconfirmed sends the latest attempt of every unconfirmed
+// transaction again, such as to an ethereum node just switched to, whose
+// transaction pool may not have them, and returns how many were sent.
+// Attempts the node already has, or that have been mined, are skipped.
+func (txm *TxManager) RebroadcastUnconfirmed() (int, error) {
+	txs := []models.Tx{}
+	if err := txm.ORM.All(&txs); err != nil {
+		return 0, err
+	}
+	sent := 0
+	var merr error
+	for _, tx := range txs {
+		if tx.Confirmed || tx.Hex == "" {
+			continue
+		}
+		if txm.CurrentConfig().DryRun {
+			logger.TxManager.Infow(fmt.Sprintf("Dry run, not broadcasting tx %v", tx.Hash.String()), "hex", tx.Hex)
+			continue
+		}
+		if _, err := txm.SendRawTx(tx.Hex); err != nil {
+			if !alreadySent(err) {
+				merr = multierr.Append(merr, fmt.Errorf("Broadcasting tx %v: %v", tx.Hash.String(), classifyTxError(err)))
+			}
+			continue
+		}
+		metrics.TxsBroadcast.Inc()
+		logger.TxManager.Infow(fmt.Sprintf("Broadcast tx %v again", tx.Hash.String()), "nonce", tx.Nonce)
+		sent++
+	}
+	return sent, merr
+}
+
+// alreadySent returns true if the ethereum node rejected a transaction
+// because it has it in its pool, or a transaction with its nonce has been
+// mined.
+func alreadySent(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "known transaction") ||
+		strings.Contains(message, "already known") ||
+		strings.Contains(message, "nonce too low")
+}
+
 func (txm *TxManager) createAttempt(
 	tx *models.Tx,
 	gasPrice *big.Int,
 	blkNum uint64,
 ) (*models.TxAttempt, error) {
 	etx := tx.EthTx(gasPrice)
-	etx, err := txm.KeyStore.SignTx(etx, txm.Config.ChainID)
+	etx, err := txm.Signer.SignTx(etx, txm.CurrentConfig().ChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +412,17 @@ func (txm *TxManager) sendTransaction(tx *types.Transaction) error {
 	if err != nil {
 		return err
 	}
-	_, err = txm.SendRawTx(hex)
-	return err
+	if txm.CurrentConfig().DryRun {
+		logger.TxManager.Infow(fmt.Sprintf("Dry run, not broadcasting tx %v", tx.Hash().String()), "hex", hex)
+		return nil
+	}
+	started := time.Now()
+	if _, err = txm.SendRawTx(hex); err != nil {
+		return classifyTxError(err)
+	}
+	metrics.TxsBroadcast.Inc()
+	metrics.TxBroadcastDuration.Observe(time.Since(started).Seconds())
+	return nil
 }
 
 func (txm *TxManager) getAttempts(hash common.Hash) ([]models.TxAttempt, error) {
@@ -124,13 +440,9 @@ func (txm *TxManager) getAttempts(hash common.Hash) ([]models.TxAttempt, error)
 func (txm *TxManager) checkAttempt(
 	tx *models.Tx,
 	txat *models.TxAttempt,
+	receipt *TxReceipt,
 	blkNum uint64,
 ) (bool, error) {
-	receipt, err := txm.GetTxReceipt(txat.Hash)
-	if err != nil {
-		return false, err
-	}
-
 	if receipt.Unconfirmed() {
 		return txm.handleUnconfirmed(tx, txat, blkNum)
 	}
@@ -144,7 +456,7 @@ func (txm *TxManager) handleConfirmed(
 	blkNum uint64,
 ) (bool, error) {
 
-	minConfs := big.NewInt(int64(txm.Config.EthMinConfirmations))
+	minConfs := big.NewInt(int64(txm.CurrentConfig().EthMinConfirmations))
 	rcptBlkNum := big.Int(rcpt.BlockNumber)
 	safeAt := minConfs.Add(&rcptBlkNum, minConfs)
 	if big.NewInt(int64(blkNum)).Cmp(safeAt) == -1 {
@@ -154,7 +466,15 @@ func (txm *TxManager) handleConfirmed(
 	if err := txm.ORM.ConfirmTx(tx, txat); err != nil {
 		return false, err
 	}
-	logger.Infow(fmt.Sprintf("Confirmed tx %v", txat.Hash.String()), "txat", txat, "receipt", rcpt)
+	logger.TxManager.Infow(fmt.Sprintf("Confirmed tx %v", txat.Hash.String()), "txat", txat, "receipt", rcpt)
+	metrics.TxConfirmationBlocks.Observe(float64(blkNum - txat.SentAt))
+	txm.Events.Publish(models.Event{Type: models.EventTxConfirmed, RunID: tx.JobRunID, Data: txat})
+	if rcpt.Reverted() {
+		return true, &models.TxError{
+			Code: models.TxErrorReverted,
+			Err:  fmt.Errorf("Transaction %v reverted", txat.Hash.String()),
+		}
+	}
 	return true, nil
 }
 
@@ -164,7 +484,7 @@ func (txm *TxManager) handleUnconfirmed(
 	blkNum uint64,
 ) (bool, error) {
 	bumpable := tx.Hash == txat.Hash
-	pastThreshold := blkNum >= txat.SentAt+txm.Config.EthGasBumpThreshold
+	pastThreshold := blkNum >= txat.SentAt+txm.CurrentConfig().EthGasBumpThreshold
 	if bumpable && pastThreshold {
 		return false, txm.bumpGas(txat, blkNum)
 	}
@@ -176,8 +496,62 @@ func (txm *TxManager) bumpGas(txat *models.TxAttempt, blkNum uint64) error {
 	if err := txm.ORM.One("ID", txat.TxID, tx); err != nil {
 		return err
 	}
-	gasPrice := new(big.Int).Add(txat.GasPrice, &txm.Config.EthGasBumpWei)
+	config := txm.CurrentConfig()
+	gasPrice := new(big.Int).Add(txat.GasPrice, &config.EthGasBumpWei)
+	if gasPrice.Cmp(&config.EthGasPriceMax) > 0 {
+		if txat.GasPrice.Cmp(&config.EthGasPriceMax) >= 0 {
+			logger.TxManager.Warnw(fmt.Sprintf("Gas price for transaction %v already at ceiling of %v", txat.Hash.String(), txat.GasPrice), "txat", txat)
+			return nil
+		}
+		gasPrice = new(big.Int).Set(&config.EthGasPriceMax)
+	}
 	txat, err := txm.createAttempt(tx, gasPrice, blkNum)
-	logger.Infow(fmt.Sprintf("Bumping gas to %v for transaction %v", gasPrice, txat.Hash.String()), "txat", txat)
+	logger.TxManager.Infow(fmt.Sprintf("Bumping gas to %v for transaction %v", gasPrice, txat.Hash.String()), "txat", txat)
+	return err
+}
+
+// txErrorPatterns match the messages that ethereum nodes reject
+// transactions with to the kind of TxError.
+var txErrorPatterns = []struct {
+	message string
+	code    string
+}{
+	{"insufficient funds", models.TxErrorInsufficientFunds},
+	{"nonce too low", models.TxErrorNonceTooLow},
+	{"underpriced", models.TxErrorUnderpriced},
+	{"execution reverted", models.TxErrorReverted},
+}
+
+// classifyTxError wraps an error from the ethereum node in a TxError when
+// the kind of failure is known, and leaves other errors as they are.
+func classifyTxError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*models.TxError); ok {
+		return err
+	}
+	message := strings.ToLower(err.Error())
+	for _, p := range txErrorPatterns {
+		if strings.Contains(message, p.message) {
+			return &models.TxError{Code: p.code, Err: err}
+		}
+	}
+	if connectionError(err, message) {
+		return &models.TxError{Code: models.TxErrorConnection, Err: err}
+	}
 	return err
 }
+
+func connectionError(err error, message string) bool {
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF, context.DeadlineExceeded, rpc.ErrClientQuit:
+		return true
+	}
+	return strings.Contains(message, "connection refused") ||
+		strings.Contains(message, "connection reset") ||
+		strings.Contains(message, "deadline exceeded")
+}
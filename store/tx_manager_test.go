@@ -2,8 +2,11 @@ package store_test
 
 import (
 	"encoding/hex"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
 	strpkg "github.com/smartcontractkit/chainlink/store"
 	"github.com/smartcontractkit/chainlink/store/models"
@@ -47,6 +50,127 @@ func TestTxManager_CreateTx(t *testing.T) {
 	ethMock.EnsureAllCalled(t)
 }
 
+func TestTxManager_DeployContract(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+
+	code, err := hex.DecodeString("6001")
+	assert.Nil(t, err)
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(5))
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(1))
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash(), func(_ interface{}, data ...interface{}) error {
+		etx, err := utils.DecodeEthereumTx(data[0].([]interface{})[0].(string))
+		assert.Nil(t, err)
+		assert.Nil(t, etx.To())
+		assert.Equal(t, code, etx.Data())
+		return nil
+	})
+
+	tx, address, err := store.TxManager.DeployContract(code, 1000000)
+	assert.Nil(t, err)
+	assert.True(t, tx.ContractCreation)
+	assert.Equal(t, crypto.CreateAddress(tx.From, 5), address)
+	ethMock.EnsureAllCalled(t)
+}
+
+func TestTxManager_CreateTxForRun(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	manager := store.TxManager
+
+	to := cltest.NewAddress()
+	data := []byte{0xab, 0xcd}
+	hash := cltest.NewHash()
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(256))
+	ethMock.Register("eth_sendRawTransaction", hash)
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+
+	first, err := manager.CreateTxForRun("run1", to, data)
+	assert.Nil(t, err)
+	ethMock.EnsureAllCalled(t)
+	assert.Equal(t, "run1", first.JobRunID)
+
+	resumed, err := manager.CreateTxForRun("run1", to, data)
+	assert.Nil(t, err)
+	assert.Equal(t, first.ID, resumed.ID)
+	assert.Equal(t, first.Hash, resumed.Hash)
+
+	txs := []models.Tx{}
+	assert.Nil(t, store.All(&txs))
+	assert.Equal(t, 1, len(txs))
+}
+
+func TestTxManager_CreateTxForRun_Unsigned(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+
+	to := cltest.NewAddress()
+	data := []byte{0xab, 0xcd}
+	saved := models.Tx{JobRunID: "run1", To: to, Data: data, Nonce: 256, Value: big.NewInt(0), GasLimit: 500000}
+	assert.Nil(t, store.Save(&saved))
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+
+	tx, err := store.TxManager.CreateTxForRun("run1", to, data)
+	assert.Nil(t, err)
+	ethMock.EnsureAllCalled(t)
+	assert.Equal(t, saved.ID, tx.ID)
+	assert.Equal(t, uint64(256), tx.Nonce)
+	attempts, err := store.AttemptsFor(tx.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(attempts))
+}
+
+func TestTxManager_CreateTx_TxErrors(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	manager := app.Store.TxManager
+
+	tests := []struct {
+		name    string
+		message string
+		code    string
+	}{
+		{"insufficient funds", "insufficient funds for gas * price + value", models.TxErrorInsufficientFunds},
+		{"nonce too low", "nonce too low", models.TxErrorNonceTooLow},
+		{"underpriced", "replacement transaction underpriced", models.TxErrorUnderpriced},
+		{"connection", "dial tcp 127.0.0.1:8546: connect: connection refused", models.TxErrorConnection},
+		{"unknown", "something else", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ethMock := app.MockEthClient()
+			ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(1))
+			ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+			ethMock.RegisterError("eth_sendRawTransaction", test.message)
+
+			_, err := manager.CreateTx(cltest.NewAddress(), []byte{})
+			assert.NotNil(t, err)
+			assert.Equal(t, test.message, err.Error())
+			txErr, ok := err.(*models.TxError)
+			if test.code == "" {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+				assert.Equal(t, test.code, txErr.Code)
+			}
+			ethMock.EnsureAllCalled(t)
+		})
+	}
+}
+
 func TestTxManager_EnsureTxConfirmed_BeforeThreshold(t *testing.T) {
 	t.Parallel()
 
@@ -144,6 +268,39 @@ func TestTxManager_EnsureTxConfirmed_WhenSafe(t *testing.T) {
 	ethMock.EnsureAllCalled(t)
 }
 
+func TestTxManager_EnsureTxConfirmed_Reverted(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	config := store.Config
+	txm := store.TxManager
+
+	sentAt := uint64(23456)
+	from := store.KeyStore.GetAccount().Address
+	failed := hexutil.Uint64(0)
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionReceipt", strpkg.TxReceipt{
+		Hash:        cltest.NewHash(),
+		BlockNumber: cltest.BigHexInt(sentAt),
+		Status:      &failed,
+	})
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(sentAt+config.EthMinConfirmations))
+
+	tx := cltest.CreateTxAndAttempt(store, from, sentAt)
+
+	confirmed, err := txm.EnsureTxConfirmed(tx.TxAttempt.Hash)
+	assert.True(t, confirmed)
+	txErr, ok := err.(*models.TxError)
+	assert.True(t, ok)
+	assert.Equal(t, models.TxErrorReverted, txErr.Code)
+	assert.False(t, txErr.Retryable())
+
+	ethMock.EnsureAllCalled(t)
+}
+
 func TestTxManager_EnsureTxConfirmed_WhenWithConfsButNotSafe(t *testing.T) {
 	t.Parallel()
 
@@ -176,3 +333,178 @@ func TestTxManager_EnsureTxConfirmed_WhenWithConfsButNotSafe(t *testing.T) {
 
 	ethMock.EnsureAllCalled(t)
 }
+
+func TestTxManager_CreateTx_DryRun(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	manager := store.TxManager
+	manager.Config.DryRun = true
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(256))
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+
+	tx, err := manager.CreateTx(cltest.NewAddress(), []byte{})
+	assert.Nil(t, err)
+	attempts, err := store.AttemptsFor(tx.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(attempts))
+
+	ethMock.EnsureAllCalled(t)
+}
+
+func TestTxManager_EnsureTxConfirmed_AtGasCeiling(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	config := store.Config
+	txm := store.TxManager
+	txm.Config.EthGasPriceMax = *big.NewInt(1)
+
+	sentAt := uint64(23456)
+	from := store.KeyStore.GetAccount().Address
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_getTransactionReceipt", strpkg.TxReceipt{})
+	ethMock.Register("eth_blockNumber", utils.Uint64ToHex(sentAt+config.EthGasBumpThreshold))
+
+	tx := cltest.CreateTxAndAttempt(store, from, sentAt)
+	confirmed, err := txm.EnsureTxConfirmed(tx.Hash)
+	assert.Nil(t, err)
+	assert.False(t, confirmed)
+	attempts, err := store.AttemptsFor(tx.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(attempts), "should not bump past the gas price ceiling")
+
+	ethMock.EnsureAllCalled(t)
+}
+
+func TestTxManager_SendBatches(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	store.TxManager.Config.DryRun = true
+	manager := store.TxManager
+
+	ethMock := app.MockEthClient()
+	for i := 0; i < 2; i++ {
+		ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(256))
+		ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+	}
+
+	batchSelector := models.HexToFunctionSelector("0x12345678")
+	oracle := cltest.NewAddress()
+	c1, err := manager.QueueCall(oracle, batchSelector, []byte{0xaa, 0xbb})
+	assert.Nil(t, err)
+	c2, err := manager.QueueCall(oracle, batchSelector, []byte{0xcc})
+	assert.Nil(t, err)
+	c3, err := manager.QueueCall(cltest.NewAddress(), batchSelector, []byte{0xdd})
+	assert.Nil(t, err)
+
+	assert.Nil(t, manager.SendBatches())
+	ethMock.EnsureAllCalled(t)
+
+	for _, call := range []*models.BatchedCall{c1, c2, c3} {
+		assert.Nil(t, store.One("ID", call.ID, call))
+		assert.True(t, call.Sent)
+	}
+	assert.Equal(t, c1.TxHash, c2.TxHash)
+	assert.NotEqual(t, c1.TxHash, c3.TxHash)
+
+	attempt := models.TxAttempt{}
+	assert.Nil(t, store.One("Hash", c1.TxHash, &attempt))
+	tx := models.Tx{}
+	assert.Nil(t, store.One("ID", attempt.TxID, &tx))
+	assert.Equal(t, oracle, tx.To)
+	want := "0x12345678" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"aabb000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"cc00000000000000000000000000000000000000000000000000000000000000"
+	assert.Equal(t, want, hexutil.Encode(tx.Data))
+
+	assert.Nil(t, manager.SendBatches(), "should not resend sent calls")
+}
+
+func TestTxManager_SendBatches_MaxCalls(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	store.TxManager.Config.DryRun = true
+	store.TxManager.Config.EthMaxBatchCalls = 2
+	manager := store.TxManager
+
+	ethMock := app.MockEthClient()
+	for i := 0; i < 2; i++ {
+		ethMock.Register("eth_getTransactionCount", utils.Uint64ToHex(256))
+		ethMock.Register("eth_blockNumber", utils.Uint64ToHex(23456))
+	}
+
+	batchSelector := models.HexToFunctionSelector("0x12345678")
+	oracle := cltest.NewAddress()
+	calls := []*models.BatchedCall{}
+	for _, data := range [][]byte{{0xaa}, {0xbb}, {0xcc}} {
+		call, err := manager.QueueCall(oracle, batchSelector, data)
+		assert.Nil(t, err)
+		calls = append(calls, call)
+	}
+
+	assert.Nil(t, manager.SendBatches())
+	for _, call := range calls {
+		assert.Nil(t, store.One("ID", call.ID, call))
+	}
+	assert.True(t, calls[0].Sent)
+	assert.True(t, calls[1].Sent)
+	assert.Equal(t, calls[0].TxHash, calls[1].TxHash)
+	assert.False(t, calls[2].Sent, "calls over ETH_MAX_BATCH_CALLS wait for the next batch")
+
+	assert.Nil(t, manager.SendBatches())
+	ethMock.EnsureAllCalled(t)
+	assert.Nil(t, store.One("ID", calls[2].ID, calls[2]))
+	assert.True(t, calls[2].Sent)
+	assert.NotEqual(t, calls[0].TxHash, calls[2].TxHash)
+}
+
+func TestTxManager_RebroadcastUnconfirmed(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	store := app.Store
+	manager := store.TxManager
+	from := cltest.NewAddress()
+
+	txs := []*models.Tx{}
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx := cltest.NewTx(from, 1)
+		tx.Nonce = nonce
+		assert.Nil(t, store.Save(tx))
+		_, err := store.AddAttempt(tx, tx.EthTx(big.NewInt(1)), 1)
+		assert.Nil(t, err)
+		txs = append(txs, tx)
+	}
+	assert.Nil(t, store.ConfirmTx(txs[2], &txs[2].TxAttempt))
+
+	ethMock := app.MockEthClient()
+	ethMock.Register("eth_sendRawTransaction", txs[0].Hash)
+	ethMock.RegisterError("eth_sendRawTransaction", "known transaction: "+txs[1].Hash.Hex())
+
+	sent, err := manager.RebroadcastUnconfirmed()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	ethMock.EnsureAllCalled(t)
+
+	ethMock.RegisterError("eth_sendRawTransaction", "insufficient funds for gas * price + value")
+	ethMock.Register("eth_sendRawTransaction", txs[1].Hash)
+	sent, err = manager.RebroadcastUnconfirmed()
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, sent)
+}
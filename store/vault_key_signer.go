@@ -0,0 +1,103 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tidwall/gjson"
+)
+
+// VaultKeySigner signs in the node's memory with a Vault-sourced key: a
+// private key kept in a HashiCorp Vault secret, under private_key as hex,
+// in either version of the KV secrets engine. Vault only stores the key;
+// it is read into memory when the node starts, and never written to disk,
+// but it does leave Vault, so anyone who can read the node's memory can
+// sign with it. Vault's transit engine, which would sign without giving
+// out the key, has no keys on Ethereum's curve.
+type VaultKeySigner struct {
+	key     *ecdsa.PrivateKey
+	account accounts.Account
+}
+
+// NewVaultKeySigner reads the key from the secret at the given path, such
+// as secret/data/chainlink, of the Vault server at address.
+func NewVaultKeySigner(address, token, path string) (*VaultKeySigner, error) {
+	if address == "" || token == "" || path == "" {
+		return nil, errors.New("VAULT_ADDR, VAULT_TOKEN, and VAULT_KEY_PATH must be set to use Vault")
+	}
+	url := strings.TrimRight(address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Vault: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("Vault: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Vault responded %v reading %v", resp.StatusCode, path)
+	}
+
+	secret := gjson.GetBytes(body, "data.data.private_key")
+	if !secret.Exists() {
+		secret = gjson.GetBytes(body, "data.private_key")
+	}
+	if !secret.Exists() {
+		return nil, fmt.Errorf("Vault secret %v has no private_key", path)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(secret.String(), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("Vault secret %v: invalid private_key", path)
+	}
+	return &VaultKeySigner{
+		key:     key,
+		account: accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)},
+	}, nil
+}
+
+// HasAccounts returns true, as the key was read when the signer was made.
+func (vs *VaultKeySigner) HasAccounts() bool {
+	return true
+}
+
+// GetAccount returns the account of the key.
+func (vs *VaultKeySigner) GetAccount() accounts.Account {
+	return vs.account
+}
+
+// Unlocked returns true, as the key is held in memory.
+func (vs *VaultKeySigner) Unlocked() bool {
+	return true
+}
+
+// SignTx signs the transaction with the key.
+func (vs *VaultKeySigner) SignTx(tx *types.Transaction, chainID uint64) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(big.NewInt(int64(chainID))), vs.key)
+}
+
+// SignMessage signs the data with the key as eth_sign does.
+func (vs *VaultKeySigner) SignMessage(data []byte) ([]byte, error) {
+	sig, err := crypto.Sign(MessageHash(data), vs.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
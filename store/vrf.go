@@ -0,0 +1,301 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pborman/uuid"
+	"github.com/smartcontractkit/chainlink/logger"
+)
+
+// VRFProofLength is the length of a VRFProof's Bytes: eight 32 byte words.
+const VRFProofLength = 8 * 32
+
+var (
+	secp256k1 = crypto.S256()
+	// sqrtPower is (P+1)/4, which gives square roots modulo P as P is 3
+	// modulo 4.
+	sqrtPower = new(big.Int).Rsh(new(big.Int).Add(secp256k1.Params().P, big.NewInt(1)), 2)
+)
+
+// VRFKey is the node's secp256k1 key for the Random adapter. Unlike the
+// node's account, it is always held by the node, since making a proof
+// needs the key itself and not just signatures from it.
+type VRFKey struct {
+	key *ecdsa.PrivateKey
+}
+
+// VRFProof shows that Randomness was made from Seed with the key of
+// PublicKey, without revealing the key. Gamma is the key times the curve
+// point HashToCurve(PublicKey, Seed), and C and S form a Schnorr-style
+// proof that Gamma and PublicKey share the key. UWitness is the address of
+// C*PublicKey + S*G, so that contracts can check it with ecrecover rather
+// than multiplying points themselves.
+type VRFProof struct {
+	PublicKeyX, PublicKeyY *big.Int
+	GammaX, GammaY         *big.Int
+	C, S                   *big.Int
+	Seed                   *big.Int
+	UWitness               common.Address
+}
+
+// UnlockVRFKey decrypts the node's VRF key, kept in vrf.key in the root
+// directory encrypted with the keystore password, generating it first if
+// there is none. It is called once at startup, when the password is
+// given, so that runs never race to generate the key. A key left in plain
+// hex by older versions is encrypted in place.
+func (s *Store) UnlockVRFKey(password string) error {
+	keyPath := path.Join(s.CurrentConfig().RootDir, "vrf.key")
+	key, err := createVRFKey(keyPath, password)
+	if os.IsExist(err) {
+		key, err = readVRFKey(keyPath, password)
+	}
+	if err != nil {
+		return err
+	}
+	s.vrfMutex.Lock()
+	defer s.vrfMutex.Unlock()
+	s.vrfKey = key
+	return nil
+}
+
+// VRFKey returns the key unlocked by UnlockVRFKey, or an error if it has
+// not been.
+func (s *Store) VRFKey() (*VRFKey, error) {
+	s.vrfMutex.RLock()
+	defer s.vrfMutex.RUnlock()
+	if s.vrfKey == nil {
+		return nil, errors.New("VRF key is locked, it is unlocked with the keystore password when the node starts")
+	}
+	return s.vrfKey, nil
+}
+
+// createVRFKey generates a key and writes it encrypted to the path, which
+// is created exclusively, returning an error satisfying os.IsExist if
+// there is a key already.
+func createVRFKey(keyPath, password string) (*VRFKey, error) {
+	file, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.GenerateKey()
+	var encrypted []byte
+	if err == nil {
+		encrypted, err = encryptVRFKey(key, password)
+	}
+	if err == nil {
+		_, err = file.Write(encrypted)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(keyPath)
+		return nil, err
+	}
+	return &VRFKey{key: key}, nil
+}
+
+func readVRFKey(keyPath, password string) (*VRFKey, error) {
+	b, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := crypto.HexToECDSA(strings.TrimSpace(string(b))); err == nil {
+		return &VRFKey{key: key}, encryptPlainVRFKey(keyPath, key, password)
+	}
+	decrypted, err := keystore.DecryptKey(b, password)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decrypt the VRF key in %v: %v", keyPath, err)
+	}
+	return &VRFKey{key: decrypted.PrivateKey}, nil
+}
+
+func encryptPlainVRFKey(keyPath string, key *ecdsa.PrivateKey, password string) error {
+	encrypted, err := encryptVRFKey(key, password)
+	if err != nil {
+		return err
+	}
+	tmpPath := keyPath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, encrypted, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, keyPath); err != nil {
+		return err
+	}
+	logger.Infow("Encrypted the plaintext VRF key with the keystore password", "path", keyPath)
+	return nil
+}
+
+// encryptVRFKey encrypts the key as geth's keystore encrypts accounts.
+func encryptVRFKey(key *ecdsa.PrivateKey, password string) ([]byte, error) {
+	return keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.NewRandom(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, password, keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+// NewVRFKey returns a VRFKey for the given private key.
+func NewVRFKey(key *ecdsa.PrivateKey) *VRFKey {
+	return &VRFKey{key: key}
+}
+
+// PublicKey returns the key's public key, which consumers check proofs
+// against.
+func (k *VRFKey) PublicKey() ecdsa.PublicKey {
+	return k.key.PublicKey
+}
+
+// Prove returns the proof of the randomness for the seed, which must be
+// a uint256.
+func (k *VRFKey) Prove(seed *big.Int) (VRFProof, error) {
+	if seed.Sign() < 0 || seed.BitLen() > 256 {
+		return VRFProof{}, fmt.Errorf("VRF seed %v is not a uint256", seed)
+	}
+	n := secp256k1.Params().N
+	pkX, pkY := k.key.PublicKey.X, k.key.PublicKey.Y
+	hX, hY := HashToCurve(pkX, pkY, seed)
+	gammaX, gammaY := secp256k1.ScalarMult(hX, hY, k.key.D.Bytes())
+
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+	if err != nil {
+		return VRFProof{}, err
+	}
+	nonce.Add(nonce, big.NewInt(1))
+	uX, uY := secp256k1.ScalarBaseMult(nonce.Bytes())
+	vX, vY := secp256k1.ScalarMult(hX, hY, nonce.Bytes())
+	uWitness := pointAddress(uX, uY)
+
+	c := hashToScalar(hX, hY, pkX, pkY, gammaX, gammaY, uWitness, vX, vY)
+	s := new(big.Int).Mul(c, k.key.D)
+	s.Sub(nonce, s)
+	s.Mod(s, n)
+	return VRFProof{
+		PublicKeyX: pkX, PublicKeyY: pkY,
+		GammaX: gammaX, GammaY: gammaY,
+		C: c, S: s,
+		Seed:     new(big.Int).Set(seed),
+		UWitness: uWitness,
+	}, nil
+}
+
+// Randomness returns the random value the proof shows, the keccak256 hash
+// of Gamma, as a uint256.
+func (p VRFProof) Randomness() *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256(uint256Word(p.GammaX), uint256Word(p.GammaY)))
+}
+
+// Verify returns an error if the proof does not show that Gamma was made
+// from the seed with the key of PublicKey.
+func (p VRFProof) Verify() error {
+	for _, i := range []*big.Int{p.PublicKeyX, p.PublicKeyY, p.GammaX, p.GammaY, p.C, p.S, p.Seed} {
+		if i == nil || i.Sign() < 0 || i.BitLen() > 256 {
+			return errors.New("VRF proof is incomplete")
+		}
+	}
+	if !secp256k1.IsOnCurve(p.PublicKeyX, p.PublicKeyY) || !secp256k1.IsOnCurve(p.GammaX, p.GammaY) {
+		return errors.New("VRF proof has a point off the curve")
+	}
+	hX, hY := HashToCurve(p.PublicKeyX, p.PublicKeyY, p.Seed)
+
+	// u = c*pk + s*G, and v = c*gamma + s*h
+	cpkX, cpkY := secp256k1.ScalarMult(p.PublicKeyX, p.PublicKeyY, p.C.Bytes())
+	sgX, sgY := secp256k1.ScalarBaseMult(p.S.Bytes())
+	uX, uY := secp256k1.Add(cpkX, cpkY, sgX, sgY)
+	if pointAddress(uX, uY) != p.UWitness {
+		return errors.New("VRF proof's uWitness does not match")
+	}
+	cgX, cgY := secp256k1.ScalarMult(p.GammaX, p.GammaY, p.C.Bytes())
+	shX, shY := secp256k1.ScalarMult(hX, hY, p.S.Bytes())
+	vX, vY := secp256k1.Add(cgX, cgY, shX, shY)
+
+	c := hashToScalar(hX, hY, p.PublicKeyX, p.PublicKeyY, p.GammaX, p.GammaY, p.UWitness, vX, vY)
+	if c.Cmp(p.C) != 0 {
+		return errors.New("VRF proof is invalid")
+	}
+	return nil
+}
+
+// Bytes returns the proof as contracts take it: the words PublicKeyX,
+// PublicKeyY, GammaX, GammaY, C, S, Seed, and UWitness, each 32 bytes and
+// big-endian, as abi.encode(uint256[2], uint256[2], uint256, uint256,
+// uint256, address) lays them out.
+func (p VRFProof) Bytes() []byte {
+	b := make([]byte, 0, VRFProofLength)
+	for _, i := range []*big.Int{p.PublicKeyX, p.PublicKeyY, p.GammaX, p.GammaY, p.C, p.S, p.Seed} {
+		b = append(b, uint256Word(i)...)
+	}
+	return append(b, common.LeftPadBytes(p.UWitness.Bytes(), 32)...)
+}
+
+// ParseVRFProof reads a proof in the layout of VRFProof.Bytes.
+func ParseVRFProof(b []byte) (VRFProof, error) {
+	if len(b) != VRFProofLength {
+		return VRFProof{}, fmt.Errorf("VRF proof must be %v bytes, got %v", VRFProofLength, len(b))
+	}
+	w := func(i int) *big.Int { return new(big.Int).SetBytes(b[i*32 : (i+1)*32]) }
+	return VRFProof{
+		PublicKeyX: w(0), PublicKeyY: w(1),
+		GammaX: w(2), GammaY: w(3),
+		C: w(4), S: w(5),
+		Seed:     w(6),
+		UWitness: common.BytesToAddress(b[7*32:]),
+	}, nil
+}
+
+// HashToCurve returns the curve point for the public key and seed, found
+// by hashing them to an x coordinate, and hashing that again until it is
+// on the curve. Of the point's two y coordinates, the even one is taken.
+func HashToCurve(pkX, pkY, seed *big.Int) (*big.Int, *big.Int) {
+	p := secp256k1.Params().P
+	x := new(big.Int).SetBytes(crypto.Keccak256(uint256Word(pkX), uint256Word(pkY), uint256Word(seed)))
+	for {
+		x.Mod(x, p)
+		// y^2 = x^3 + 7
+		y2 := new(big.Int).Exp(x, big.NewInt(3), p)
+		y2.Add(y2, big.NewInt(7))
+		y2.Mod(y2, p)
+		y := new(big.Int).Exp(y2, sqrtPower, p)
+		if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(y2) == 0 {
+			if y.Bit(0) == 1 {
+				y.Sub(p, y)
+			}
+			return x, y
+		}
+		x.SetBytes(crypto.Keccak256(uint256Word(x)))
+	}
+}
+
+// hashToScalar returns the proof's challenge C.
+func hashToScalar(hX, hY, pkX, pkY, gammaX, gammaY *big.Int, uWitness common.Address, vX, vY *big.Int) *big.Int {
+	c := new(big.Int).SetBytes(crypto.Keccak256(
+		uint256Word(hX), uint256Word(hY),
+		uint256Word(pkX), uint256Word(pkY),
+		uint256Word(gammaX), uint256Word(gammaY),
+		common.LeftPadBytes(uWitness.Bytes(), 32),
+		uint256Word(vX), uint256Word(vY),
+	))
+	return c.Mod(c, secp256k1.Params().N)
+}
+
+func pointAddress(x, y *big.Int) common.Address {
+	return common.BytesToAddress(crypto.Keccak256(uint256Word(x), uint256Word(y))[12:])
+}
+
+func uint256Word(i *big.Int) []byte {
+	return common.LeftPadBytes(i.Bytes(), 32)
+}
@@ -0,0 +1,99 @@
+package store_test
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	strpkg "github.com/smartcontractkit/chainlink/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVRFKey_Prove(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	vrf := strpkg.NewVRFKey(key)
+	seed := big.NewInt(42)
+
+	proof, err := vrf.Prove(seed)
+	assert.Nil(t, err)
+	assert.Nil(t, proof.Verify())
+	again, err := vrf.Prove(seed)
+	assert.Nil(t, err)
+	assert.Equal(t, proof.Randomness(), again.Randomness(), "the randomness depends only on the key and seed")
+	other, err := vrf.Prove(big.NewInt(43))
+	assert.Nil(t, err)
+	assert.NotEqual(t, proof.Randomness(), other.Randomness())
+
+	parsed, err := strpkg.ParseVRFProof(proof.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, parsed.Verify())
+	assert.Equal(t, proof.Randomness(), parsed.Randomness())
+
+	tampered := proof
+	tampered.Seed = big.NewInt(43)
+	assert.NotNil(t, tampered.Verify())
+	tampered = proof
+	tampered.GammaX, tampered.GammaY = other.GammaX, other.GammaY
+	assert.NotNil(t, tampered.Verify())
+
+	_, err = vrf.Prove(new(big.Int).Lsh(big.NewInt(1), 256))
+	assert.NotNil(t, err)
+	_, err = strpkg.ParseVRFProof(proof.Bytes()[1:])
+	assert.NotNil(t, err)
+}
+
+func TestStore_UnlockVRFKey(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	_, err := store.VRFKey()
+	assert.NotNil(t, err, "the key is locked until the node starts")
+
+	assert.Nil(t, store.UnlockVRFKey(cltest.Password))
+	key, err := store.VRFKey()
+	assert.Nil(t, err)
+
+	keyPath := path.Join(store.Config.RootDir, "vrf.key")
+	b, err := ioutil.ReadFile(keyPath)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"ciphertext"`, "the key is encrypted")
+
+	assert.NotNil(t, store.UnlockVRFKey("wrong password"))
+	assert.Nil(t, store.UnlockVRFKey(cltest.Password))
+	again, err := store.VRFKey()
+	assert.Nil(t, err)
+	assert.Equal(t, key.PublicKey(), again.PublicKey(), "the key is kept in the root directory")
+}
+
+func TestStore_UnlockVRFKey_Plaintext(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore()
+	defer cleanup()
+
+	plain, err := crypto.GenerateKey()
+	assert.Nil(t, err)
+	keyPath := path.Join(store.Config.RootDir, "vrf.key")
+	encoded := common.Bytes2Hex(crypto.FromECDSA(plain))
+	assert.Nil(t, ioutil.WriteFile(keyPath, []byte(encoded), 0600))
+
+	assert.Nil(t, store.UnlockVRFKey(cltest.Password))
+	key, err := store.VRFKey()
+	assert.Nil(t, err)
+	assert.Equal(t, plain.PublicKey, key.PublicKey())
+
+	b, err := ioutil.ReadFile(keyPath)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), encoded)
+	assert.Contains(t, string(b), `"ciphertext"`, "the key is encrypted in place")
+	assert.NotNil(t, store.UnlockVRFKey("wrong password"))
+}
@@ -1,6 +1,5 @@
 // Package utils is used for the common functions for dealing with
-// conversion to and from hex, bytes, and strings, formatting time,
-// and basic HTTP authentication.
+// conversion to and from hex, bytes, and strings, and formatting time.
 package utils
 
 import (
@@ -8,8 +7,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -66,38 +63,6 @@ func ISO8601UTC(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
 
-// BasicAuthPost sends a POST request to the HTTP client with the given username
-// and password to authenticate at the url with contentType and returns a response.
-func BasicAuthPost(username, password, url string, contentType string, body io.Reader) (*http.Response, error) {
-	client := &http.Client{}
-	request, _ := http.NewRequest("POST", url, body)
-	request.Header.Set("Content-Type", contentType)
-	request.SetBasicAuth(username, password)
-	resp, err := client.Do(request)
-	return resp, err
-}
-
-// BasicAuthGet uses the given username and password to send a GET request
-// at the given URL and returns a response.
-func BasicAuthGet(username, password, url string) (*http.Response, error) {
-	client := &http.Client{}
-	request, _ := http.NewRequest("GET", url, nil)
-	request.SetBasicAuth(username, password)
-	resp, err := client.Do(request)
-	return resp, err
-}
-
-// BasicAuthPatch sends a PATCH request to the HTTP client with the given username
-// and password to authenticate at the url with contentType and returns a response.
-func BasicAuthPatch(username, password, url string, contentType string, body io.Reader) (*http.Response, error) {
-	client := &http.Client{}
-	request, _ := http.NewRequest("PATCH", url, body)
-	request.Header.Set("Content-Type", contentType)
-	request.SetBasicAuth(username, password)
-	resp, err := client.Do(request)
-	return resp, err
-}
-
 // FormatJSON applies indent to format a JSON response.
 func FormatJSON(v interface{}) ([]byte, error) {
 	return json.MarshalIndent(v, "", "  ")
@@ -180,6 +145,31 @@ func EthToWei(numEth float64) *big.Int {
 	return numWeiBigInt
 }
 
+// FormatUnits writes the integer amount of a token's smallest unit in a
+// unit decimals places larger, such as wei in ether for 18, exactly and
+// without trailing zeros.
+func FormatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	digits := new(big.Int).Abs(amount).String()
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
 // IsEmptyAddress checks that the address is empty, synonymous with the zero
 // account/address. No logs can come from this address, as there is no contract
 // present there.
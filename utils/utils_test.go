@@ -33,6 +33,25 @@ func TestUtils_EthToWei(t *testing.T) {
 	assert.Equal(t, actualNumWei, expectedNumWei)
 }
 
+func TestUtils_FormatUnits(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		amount   *big.Int
+		decimals int
+		want     string
+	}{
+		{big.NewInt(1), 18, "0.000000000000000001"},
+		{big.NewInt(1500000000), 9, "1.5"},
+		{new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18)), 18, "2"},
+		{big.NewInt(-25), 1, "-2.5"},
+		{big.NewInt(42), 0, "42"},
+		{nil, 18, "0"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, utils.FormatUnits(test.amount, test.decimals))
+	}
+}
+
 func TestUtils_IsEmptyAddress(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,94 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asdine/storm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// AddressListsController manages the named lists of contract addresses
+// that log initiators can listen to.
+type AddressListsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index lists the node's address lists.
+// Example:
+//  "<application>/address_lists"
+func (alc *AddressListsController) Index(c *gin.Context) {
+	lists := []models.AddressList{}
+	if err := alc.App.Store.All(&lists); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, lists)
+	}
+}
+
+// Show returns the address list with the given name.
+// Example:
+//  "<application>/address_lists/:Name"
+func (alc *AddressListsController) Show(c *gin.Context) {
+	if list, err := alc.App.Store.FindAddressList(c.Param("Name")); err == storm.ErrNotFound {
+		problem(c, 404, "Address list not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, list)
+	}
+}
+
+// Update creates or replaces the addresses of the address list with the
+// given name, and restarts the subscriptions of the jobs listening to it.
+// Example:
+//  "<application>/address_lists/:Name"
+func (alc *AddressListsController) Update(c *gin.Context) {
+	var request struct {
+		Addresses []common.Address `json:"addresses"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+	} else if list, err := models.NewAddressList(c.Param("Name"), request.Addresses); err != nil {
+		problem(c, 400, err.Error())
+	} else if err := alc.App.SaveAddressList(list); err != nil {
+		problem(c, 500, err.Error())
+	} else if list, err = alc.App.Store.FindAddressList(list.Name); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, list)
+	}
+}
+
+// Destroy removes the address list with the given name, unless a job is
+// listening to it.
+// Example:
+//  "<application>/address_lists/:Name"
+func (alc *AddressListsController) Destroy(c *gin.Context) {
+	list, err := alc.App.Store.FindAddressList(c.Param("Name"))
+	if err == storm.ErrNotFound {
+		problem(c, 404, "Address list not found")
+		return
+	} else if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	jobs, err := alc.App.JobsUsingAddressList(list.Name)
+	if err != nil {
+		problem(c, 500, err.Error())
+	} else if len(jobs) > 0 {
+		ids := make([]string, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+		}
+		problem(c, 409, fmt.Sprintf("Address list %v is used by jobs %v", list.Name, strings.Join(ids, ", ")))
+	} else if err := alc.App.Store.DeleteStruct(&list); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"name": list.Name})
+	}
+}
@@ -0,0 +1,71 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressListsController_Update(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	first, second := cltest.NewAddress(), cltest.NewAddress()
+	body := fmt.Sprintf(`{"addresses":["%v","%v","%v"]}`, first.Hex(), second.Hex(), first.Hex())
+	resp := cltest.AuthenticatedPut(app.Server.URL+"/v2/address_lists/Feeds", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/address_lists/feeds")
+	cltest.CheckStatusCode(t, resp, 200)
+	var list models.AddressList
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &list))
+	assert.Equal(t, "feeds", list.Name)
+	assert.Equal(t, []common.Address{first, second}, list.Addresses)
+
+	resp = cltest.AuthenticatedPut(app.Server.URL+"/v2/address_lists/feeds", bytes.NewBufferString(`{"addresses":["0x0000000000000000000000000000000000000000"]}`))
+	cltest.CheckStatusCode(t, resp, 400)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/address_lists/unknown")
+	cltest.CheckStatusCode(t, resp, 404)
+}
+
+func TestAddressListsController_Update_RestartsSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	eth := app.MockEthClient()
+	eth.RegisterSubscription("logs", make(chan types.Log))
+	eth.RegisterSubscription("logs", make(chan types.Log))
+	app.Start()
+
+	list, err := models.NewAddressList("feeds", []common.Address{cltest.NewAddress()})
+	assert.Nil(t, err)
+	assert.Nil(t, app.Store.Save(&list))
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{Type: models.InitiatorEthLog, AddressList: "feeds"}}
+	assert.Nil(t, app.AddJob(j))
+
+	body := fmt.Sprintf(`{"addresses":["%v"]}`, cltest.NewAddress().Hex())
+	resp := cltest.AuthenticatedPut(app.Server.URL+"/v2/address_lists/feeds", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	eth.EnsureAllCalled(t)
+
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/address_lists/feeds")
+	cltest.CheckStatusCode(t, resp, 409)
+
+	assert.Nil(t, app.ArchiveJob(j))
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/address_lists/feeds")
+	cltest.CheckStatusCode(t, resp, 200)
+	_, err = app.Store.FindAddressList("feeds")
+	assert.NotNil(t, err)
+}
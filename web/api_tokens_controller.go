@@ -0,0 +1,88 @@
+package web
+
+import (
+	"github.com/asdine/storm"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// APITokensController manages the long-lived tokens that programmatic
+// clients authenticate with.
+type APITokensController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index lists the node's API tokens, without the tokens themselves.
+// Example:
+//  "<application>/api_tokens"
+func (atc *APITokensController) Index(c *gin.Context) {
+	tokens := []models.APIToken{}
+	if err := atc.App.Store.All(&tokens); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, tokens)
+	}
+}
+
+// Create adds an API token with the given name and role, which defaults to
+// view, and optionally scopes limiting what it can do, such as
+// "runs:write:<jobID>". The response is the only time the token is shown,
+// as only its hash is stored.
+// Example:
+//  "<application>/api_tokens"
+func (atc *APITokensController) Create(c *gin.Context) {
+	var request struct {
+		Name   string   `json:"name"`
+		Role   string   `json:"role"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	if request.Role == "" {
+		request.Role = models.RoleView
+	}
+	if !models.ValidRole(request.Role) {
+		problem(c, 400, "Role "+request.Role+" does not exist")
+		return
+	}
+	for _, scope := range request.Scopes {
+		if _, err := models.ParseScope(scope); err != nil {
+			problem(c, 400, err.Error())
+			return
+		}
+	}
+
+	apiToken, token := models.NewAPIToken(request.Name, request.Role)
+	apiToken.Scopes = request.Scopes
+	if err := atc.App.Store.Save(&apiToken); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{
+			"id":        apiToken.ID,
+			"name":      apiToken.Name,
+			"role":      apiToken.Role,
+			"scopes":    apiToken.Scopes,
+			"createdAt": apiToken.CreatedAt,
+			"token":     token,
+		})
+	}
+}
+
+// Destroy revokes the API token with the given ID.
+// Example:
+//  "<application>/api_tokens/:TokenID"
+func (atc *APITokensController) Destroy(c *gin.Context) {
+	var apiToken models.APIToken
+	if err := atc.App.Store.One("ID", c.Param("TokenID"), &apiToken); err == storm.ErrNotFound {
+		problem(c, 404, "API token not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if err = atc.App.Store.DeleteStruct(&apiToken); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": apiToken.ID})
+	}
+}
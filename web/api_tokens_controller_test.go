@@ -0,0 +1,138 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokensController_Create(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/api_tokens", bytes.NewBufferString(`{"name":"monitoring"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Role  string `json:"role"`
+		Token string `json:"token"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+	assert.Equal(t, "monitoring", created.Name)
+	assert.Equal(t, models.RoleView, created.Role)
+	assert.NotEmpty(t, created.Token)
+
+	stored, err := app.Store.FindAPIToken(created.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, created.ID, stored.ID)
+	assert.NotEqual(t, created.Token, stored.HashedToken)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/api_tokens")
+	cltest.CheckStatusCode(t, resp, 200)
+	body := string(cltest.ParseResponseBody(resp))
+	assert.Contains(t, body, created.ID)
+	assert.NotContains(t, body, created.Token)
+}
+
+func TestAPITokensController_Authentication(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	apiToken, token := models.NewAPIToken("deploys", models.RoleView)
+	assert.Nil(t, app.Store.Save(&apiToken))
+
+	get := func(token string) *http.Response {
+		req, err := http.NewRequest("GET", app.Server.URL+"/v2/jobs", nil)
+		assert.Nil(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	cltest.CheckStatusCode(t, get(token), 200)
+	cltest.CheckStatusCode(t, get("notatoken"), 401)
+
+	resp := cltest.AuthenticatedDelete(app.Server.URL + "/v2/api_tokens/" + apiToken.ID)
+	cltest.CheckStatusCode(t, resp, 200)
+	cltest.CheckStatusCode(t, get(token), 401)
+}
+
+func TestAPITokensController_Create_InvalidRole(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/api_tokens", bytes.NewBufferString(`{"name":"monitoring","role":"root"}`))
+	cltest.CheckStatusCode(t, resp, 400)
+}
+
+func TestAPITokensController_Destroy_NotFound(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedDelete(app.Server.URL + "/v2/api_tokens/garbage")
+	cltest.CheckStatusCode(t, resp, 404)
+}
+
+func TestAPITokensController_Scopes(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j1 := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j1))
+	j2 := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j2))
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/api_tokens", bytes.NewBufferString(
+		`{"name":"integration","role":"run","scopes":["runs:write:`+j1.ID+`","jobs:read:`+j1.ID+`"]}`,
+	))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created struct {
+		Scopes []string `json:"scopes"`
+		Token  string   `json:"token"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+	assert.Equal(t, []string{"runs:write:" + j1.ID, "jobs:read:" + j1.ID}, created.Scopes)
+
+	do := func(method, path string) *http.Response {
+		req, err := http.NewRequest(method, app.Server.URL+path, bytes.NewBufferString(""))
+		assert.Nil(t, err)
+		req.Header.Set("Authorization", "Bearer "+created.Token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	cltest.CheckStatusCode(t, do("POST", "/v2/jobs/"+j1.ID+"/runs"), 200)
+	cltest.CheckStatusCode(t, do("GET", "/v2/jobs/"+j1.ID), 200)
+	cltest.CheckStatusCode(t, do("POST", "/v2/jobs/"+j2.ID+"/runs"), 403)
+	cltest.CheckStatusCode(t, do("GET", "/v2/jobs/"+j2.ID), 403)
+	cltest.CheckStatusCode(t, do("GET", "/v2/jobs"), 403)
+	cltest.CheckStatusCode(t, do("POST", "/v2/bridge_types"), 403)
+}
+
+func TestAPITokensController_Create_InvalidScope(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/api_tokens", bytes.NewBufferString(`{"name":"monitoring","scopes":["keys:write"]}`))
+	cltest.CheckStatusCode(t, resp, 400)
+}
@@ -0,0 +1,91 @@
+package web_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/pause", &bytes.Buffer{})
+	cltest.CheckStatusCode(t, resp, 200)
+
+	body := `{"JOB_FAILURE_THRESHOLD":"5"}`
+	resp = cltest.AuthenticatedPatch(app.Server.URL+"/v2/config", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/audit?limit=1")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Equal(t, "2", resp.Header.Get("X-Total-Count"))
+	var page struct {
+		Entries []models.AuditEntry `json:"entries"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &page))
+	assert.Equal(t, 1, len(page.Entries))
+	entry := page.Entries[0]
+	assert.Equal(t, models.AuditConfigChanged, entry.Action)
+	assert.Equal(t, cltest.Username, entry.Actor)
+	assert.Equal(t, "/v2/config", entry.Path)
+	assert.Equal(t, 200, entry.Status)
+	hash := sha256.Sum256([]byte(body))
+	assert.Equal(t, hex.EncodeToString(hash[:]), entry.PayloadHash)
+}
+
+func TestAuditController_Export(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/pause", &bytes.Buffer{})
+	cltest.CheckStatusCode(t, resp, 200)
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/resume", &bytes.Buffer{})
+	cltest.CheckStatusCode(t, resp, 200)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/audit/export")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	actions := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(cltest.ParseResponseBody(resp)))
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &entry))
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{models.AuditJobPaused, models.AuditJobResumed}, actions)
+}
+
+func TestAuditController_RequiresAdmin(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	apiToken, token := models.NewAPIToken("viewer", models.RoleView)
+	assert.Nil(t, app.Store.Save(&apiToken))
+
+	req, err := http.NewRequest("GET", app.Server.URL+"/v2/audit", nil)
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 403)
+}
@@ -0,0 +1,25 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/presenters"
+)
+
+// BalancesController shows the balances of the node's account.
+type BalancesController struct {
+	App *services.ChainlinkApplication
+}
+
+// Show returns the node's address and its ether and LINK balances, each
+// with the unit it is given in, set by ETH_DISPLAY_UNIT and
+// LINK_DISPLAY_UNIT.
+// Example:
+//  "<application>/balances"
+func (bc *BalancesController) Show(c *gin.Context) {
+	if balances, err := presenters.NewBalances(bc.App.Store); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, balances)
+	}
+}
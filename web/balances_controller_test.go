@@ -0,0 +1,33 @@
+package web_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/presenters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalancesController_Show(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	app.Store.Config.EthDisplayUnit = "gwei"
+	app.Store.Config.LinkDisplayUnit = "link"
+	app.Store.Config.LinkContractAddress = cltest.NewAddress().Hex()
+
+	eth := app.MockEthClient()
+	eth.Register("eth_getBalance", "0x1bc16d674ec80000")
+	eth.Register("eth_call", "0x00000000000000000000000000000000000000000000000014d1120d7b160000")
+
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/balances")
+	cltest.CheckStatusCode(t, resp, 200)
+	var balances presenters.Balances
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &balances))
+	assert.Equal(t, app.Store.KeyStore.GetAccount().Address, balances.Address)
+	assert.Equal(t, presenters.Amount{Value: "2000000000", Unit: "gwei"}, balances.Eth)
+	assert.Equal(t, &presenters.Amount{Value: "1.5", Unit: "link"}, balances.Link)
+	eth.EnsureAllCalled(t)
+}
@@ -16,13 +16,9 @@ func (btc *BridgeTypesController) Create(c *gin.Context) {
 	bt := &models.BridgeType{}
 
 	if err := c.ShouldBindJSON(bt); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else if err = btc.App.GetStore().Save(bt); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else {
 		c.JSON(200, bt)
 	}
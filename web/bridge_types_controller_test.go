@@ -15,9 +15,8 @@ func TestBridgeTypesController_Create(t *testing.T) {
 	app, cleanup := cltest.NewApplication()
 	defer cleanup()
 
-	resp := cltest.BasicAuthPost(
+	resp := cltest.AuthenticatedPost(
 		app.Server.URL+"/v2/bridge_types",
-		"application/json",
 		bytes.NewBuffer(cltest.LoadJSON("../internal/fixtures/web/create_random_number_bridge_type.json")),
 	)
 	cltest.CheckStatusCode(t, resp, 200)
@@ -35,9 +34,8 @@ func TestBridgeTypesController_Create_BindJSONError(t *testing.T) {
 	app, cleanup := cltest.NewApplication()
 	defer cleanup()
 
-	resp := cltest.BasicAuthPost(
+	resp := cltest.AuthenticatedPost(
 		app.Server.URL+"/v2/bridge_types",
-		"application/json",
 		bytes.NewBufferString("}"),
 	)
 	cltest.CheckStatusCode(t, resp, 500)
@@ -49,9 +47,8 @@ func TestBridgeTypesController_Create_DatabaseError(t *testing.T) {
 	app, cleanup := cltest.NewApplication()
 	defer cleanup()
 
-	resp := cltest.BasicAuthPost(
+	resp := cltest.AuthenticatedPost(
 		app.Server.URL+"/v2/bridge_types",
-		"application/json",
 		bytes.NewBufferString(`{"url":"http://without.a.name"}`),
 	)
 	cltest.CheckStatusCode(t, resp, 500)
@@ -0,0 +1,89 @@
+package web
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// ConfigController changes the node's runtime settings and shows the
+// history of those changes.
+type ConfigController struct {
+	App *services.ChainlinkApplication
+}
+
+// Update changes the given runtime settings, keyed by environment variable
+// name, and returns the recorded changes. Settings are changed in order of
+// their names, and none are changed if any of them is not a runtime setting
+// or has an invalid value.
+// Example:
+//  "<application>/config"
+func (cc *ConfigController) Update(c *gin.Context) {
+	var settings map[string]string
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	keys := []string{}
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	check := cc.App.Store.CurrentConfig()
+	for _, key := range keys {
+		if _, err := check.Change(key, settings[key]); err != nil {
+			problem(c, 400, err.Error())
+			return
+		}
+	}
+
+	changes := []models.ConfigChange{}
+	for _, key := range keys {
+		change, err := cc.App.Store.ChangeConfig(key, settings[key], identity(c))
+		if err != nil {
+			problem(c, 500, err.Error())
+			return
+		}
+		changes = append(changes, change)
+	}
+	c.JSON(200, gin.H{"changes": changes})
+}
+
+// Reload reads the config file and environment again, as the node does on
+// SIGHUP, and changes the runtime settings whose values differ. The
+// response lists the recorded changes.
+// Example:
+//  "<application>/config/reload"
+func (cc *ConfigController) Reload(c *gin.Context) {
+	changes, err := cc.App.Store.ReloadConfig(identity(c))
+	if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	c.JSON(200, gin.H{"changes": changes})
+}
+
+// History returns the changes made to runtime settings, newest first. The
+// "offset" and "limit" query parameters page through the changes, and the
+// total number of changes is returned in the X-Total-Count header.
+// Example:
+//  "<application>/config/history?offset=20&limit=10"
+func (cc *ConfigController) History(c *gin.Context) {
+	offset, limit, err := pagingParams(c)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	changes, total, err := cc.App.Store.ConfigChangesPage(offset, limit)
+	if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.Header("X-Total-Count", strconv.Itoa(total))
+		c.JSON(200, gin.H{"changes": changes})
+	}
+}
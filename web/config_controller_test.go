@@ -0,0 +1,107 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigController_Update(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"ETH_GAS_PRICE_DEFAULT":"30000000000","JOB_FAILURE_THRESHOLD":"5"}`
+	resp := cltest.AuthenticatedPatch(app.Server.URL+"/v2/config", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Equal(t, *big.NewInt(30000000000), app.Store.CurrentConfig().EthGasPriceDefault)
+	assert.Equal(t, uint64(5), app.Store.CurrentConfig().JobFailureThreshold)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/config/history?limit=1")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Equal(t, "2", resp.Header.Get("X-Total-Count"))
+	var history struct {
+		Changes []models.ConfigChange `json:"changes"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &history))
+	assert.Equal(t, 1, len(history.Changes))
+	change := history.Changes[0]
+	assert.Equal(t, "JOB_FAILURE_THRESHOLD", change.Setting)
+	assert.Equal(t, "0", change.From)
+	assert.Equal(t, "5", change.To)
+	assert.Equal(t, cltest.Username, change.ChangedBy)
+}
+
+func TestConfigController_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chainlink_reload")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	toml := `LOG_LEVEL = "debug"
+ETH_MIN_CONFIRMATIONS = 2
+ETH_GAS_BUMP_THRESHOLD = 3
+ETH_GAS_BUMP_WEI = "5000000000"
+ETH_GAS_PRICE_DEFAULT = "30000000000"
+ETH_GAS_PRICE_MAX = "500000000000"
+JOB_FAILURE_THRESHOLD = 0
+PORT = "7000"
+`
+	assert.Nil(t, ioutil.WriteFile(path.Join(dir, "chainlink.toml"), []byte(toml), 0600))
+	os.Setenv("ROOT", dir)
+	defer os.Unsetenv("ROOT")
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	port := app.Store.Config.Port
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/config/reload", &bytes.Buffer{})
+	cltest.CheckStatusCode(t, resp, 200)
+	var reloaded struct {
+		Changes []models.ConfigChange `json:"changes"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &reloaded))
+	assert.Equal(t, 2, len(reloaded.Changes))
+	assert.Equal(t, "ETH_GAS_PRICE_DEFAULT", reloaded.Changes[0].Setting)
+	assert.Equal(t, "ETH_MIN_CONFIRMATIONS", reloaded.Changes[1].Setting)
+	assert.Equal(t, cltest.Username, reloaded.Changes[1].ChangedBy)
+
+	assert.Equal(t, *big.NewInt(30000000000), app.Store.CurrentConfig().EthGasPriceDefault)
+	assert.Equal(t, uint64(2), app.Store.CurrentConfig().EthMinConfirmations)
+	assert.Equal(t, port, app.Store.CurrentConfig().Port, "only runtime settings are reloaded")
+}
+
+func TestConfigController_Update_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not a runtime setting", `{"ETH_GAS_PRICE_MAX":"1","PORT":"8080"}`},
+		{"invalid value", `{"ETH_GAS_PRICE_MAX":"1","JOB_FAILURE_THRESHOLD":"often"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedPatch(app.Server.URL+"/v2/config", bytes.NewBufferString(test.body))
+			cltest.CheckStatusCode(t, resp, 400)
+			assert.Equal(t, *big.NewInt(500000000000), app.Store.CurrentConfig().EthGasPriceMax)
+		})
+	}
+
+	changes, total, err := app.Store.ConfigChangesPage(0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, changes)
+}
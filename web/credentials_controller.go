@@ -0,0 +1,120 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asdine/storm"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/store/presenters"
+)
+
+// CredentialsController manages the named secrets, such as API keys, that
+// HTTP tasks authenticate with. Their values can be set, but are never
+// shown again.
+type CredentialsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index lists the names of the node's credentials, and when they were
+// created and last rotated, without their values.
+// Example:
+//  "<application>/credentials"
+func (cc *CredentialsController) Index(c *gin.Context) {
+	credentials := []models.Credential{}
+	if err := cc.App.Store.All(&credentials); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewCredentials(credentials))
+	}
+}
+
+// Create saves a credential with the given name and value.
+// Example:
+//  "<application>/credentials"
+func (cc *CredentialsController) Create(c *gin.Context) {
+	var request struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	name, err := models.NormalizeCredentialName(request.Name)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	if _, err := cc.App.Store.FindCredential(name); err == nil {
+		problem(c, 409, fmt.Sprintf("Credential %v already exists", name))
+		return
+	} else if err != storm.ErrNotFound {
+		problem(c, 500, err.Error())
+		return
+	}
+	cc.save(c, name, request.Value, 201)
+}
+
+// Update rotates the value of the credential with the given name. Tasks
+// use the new value from their next request.
+// Example:
+//  "<application>/credentials/:Name"
+func (cc *CredentialsController) Update(c *gin.Context) {
+	var request struct {
+		Value string `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+	} else if credential, err := cc.App.Store.FindCredential(c.Param("Name")); err == storm.ErrNotFound {
+		problem(c, 404, "Credential not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		cc.save(c, credential.Name, request.Value, 200)
+	}
+}
+
+func (cc *CredentialsController) save(c *gin.Context, name, value string, status int) {
+	if value == "" {
+		problem(c, 400, "Credential must have a value")
+	} else if credential, err := cc.App.Store.SaveCredential(name, value); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		logger.Web.Infow("Credential saved", "name", name, "actor", identity(c))
+		c.JSON(status, presenters.NewCredential(credential))
+	}
+}
+
+// Destroy removes the credential with the given name, unless a job's
+// tasks authenticate with it.
+// Example:
+//  "<application>/credentials/:Name"
+func (cc *CredentialsController) Destroy(c *gin.Context) {
+	credential, err := cc.App.Store.FindCredential(c.Param("Name"))
+	if err == storm.ErrNotFound {
+		problem(c, 404, "Credential not found")
+		return
+	} else if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	jobs, err := cc.App.JobsUsingCredential(credential.Name)
+	if err != nil {
+		problem(c, 500, err.Error())
+	} else if len(jobs) > 0 {
+		ids := make([]string, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+		}
+		problem(c, 409, fmt.Sprintf("Credential %v is used by jobs %v", credential.Name, strings.Join(ids, ", ")))
+	} else if err := cc.App.Store.DeleteStruct(&credential); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"name": credential.Name})
+	}
+}
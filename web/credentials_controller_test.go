@@ -0,0 +1,116 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialsController(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	url := app.Server.URL + "/v2/credentials"
+
+	resp := cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"name":"Prices-API","value":"s3cret"}`))
+	cltest.CheckStatusCode(t, resp, 201)
+	body := string(cltest.ParseResponseBody(resp))
+	assert.NotContains(t, body, "s3cret")
+	assert.Contains(t, body, `"name":"prices-api"`)
+
+	resp = cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"name":"prices-api","value":"other"}`))
+	cltest.CheckStatusCode(t, resp, 409)
+	resp = cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"name":"bad name","value":"other"}`))
+	cltest.CheckStatusCode(t, resp, 400)
+	resp = cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"name":"empty"}`))
+	cltest.CheckStatusCode(t, resp, 400)
+
+	resp = cltest.AuthenticatedPut(url+"/prices-api", bytes.NewBufferString(`{"value":"n3w"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	value, err := app.Store.CredentialValue("prices-api")
+	assert.Nil(t, err)
+	assert.Equal(t, "n3w", value)
+	resp = cltest.AuthenticatedPut(url+"/unknown", bytes.NewBufferString(`{"value":"n3w"}`))
+	cltest.CheckStatusCode(t, resp, 404)
+
+	resp = cltest.AuthenticatedGet(url)
+	cltest.CheckStatusCode(t, resp, 200)
+	body = string(cltest.ParseResponseBody(resp))
+	assert.NotContains(t, body, "n3w")
+	var credentials []models.Credential
+	assert.Nil(t, json.Unmarshal([]byte(body), &credentials))
+	assert.Equal(t, 1, len(credentials))
+
+	entries, err := app.Store.AuditEntries()
+	assert.Nil(t, err)
+	actions := []string{}
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	assert.Contains(t, actions, models.AuditCredentialCreated)
+	assert.Contains(t, actions, models.AuditCredentialRotated)
+}
+
+func TestCredentialsController_Destroy(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	_, err := app.Store.SaveCredential("prices-api", "s3cret")
+	assert.Nil(t, err)
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.Task{cltest.NewTask("httpget",
+		`{"url":"https://example.com","auth":{"type":"header","credential":"prices-api"}}`)}
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	resp := cltest.AuthenticatedDelete(app.Server.URL + "/v2/credentials/prices-api")
+	cltest.CheckStatusCode(t, resp, 409)
+	assert.True(t, strings.Contains(string(cltest.ParseResponseBody(resp)), j.ID))
+
+	assert.Nil(t, app.ArchiveJob(j))
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/credentials/prices-api")
+	cltest.CheckStatusCode(t, resp, 200)
+	_, err = app.Store.FindCredential("prices-api")
+	assert.NotNil(t, err)
+
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/credentials/prices-api")
+	cltest.CheckStatusCode(t, resp, 404)
+}
+
+func TestCredentialsController_ValueSurvivesRestart(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig()
+	app, _ := cltest.NewApplicationWithConfig(config)
+	url := app.Server.URL + "/v2/credentials"
+
+	resp := cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"name":"prices-api","value":"s3cret"}`))
+	cltest.CheckStatusCode(t, resp, 201)
+	resp = cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"name":"weather-api","value":"0ld"}`))
+	cltest.CheckStatusCode(t, resp, 201)
+	resp = cltest.AuthenticatedPut(url+"/weather-api", bytes.NewBufferString(`{"value":"n3w"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+
+	resp = cltest.AuthenticatedGet(url)
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.NotContains(t, string(cltest.ParseResponseBody(resp)), "encryptedValue")
+
+	app.Server.Close()
+	assert.Nil(t, app.ChainlinkApplication.Stop())
+
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	value, err := store.CredentialValue("prices-api")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cret", value)
+	value, err = store.CredentialValue("weather-api")
+	assert.Nil(t, err)
+	assert.Equal(t, "n3w", value)
+}
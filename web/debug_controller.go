@@ -0,0 +1,22 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+)
+
+// DebugController shows the node's internal state, to help operators find
+// out why it is behaving as it is.
+type DebugController struct {
+	App *services.ChainlinkApplication
+}
+
+// Queue returns the number of runs waiting for a worker for each job, how
+// long the oldest has waited, and how many workers are busy. Runs waiting
+// here point to the node being slow, while runs pending on bridges or
+// confirmations point to upstream services being slow.
+// Example:
+//  "<application>/debug/queue"
+func (dc *DebugController) Queue(c *gin.Context) {
+	c.JSON(200, dc.App.Store.RunQueue.Stats())
+}
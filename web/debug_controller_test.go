@@ -0,0 +1,30 @@
+package web_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestDebugController_Queue(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig()
+	config.MaxConcurrentRuns = 2
+	app, cleanup := cltest.NewApplicationWithConfig(config)
+	defer cleanup()
+
+	done := app.Store.RunQueue.Work(models.JobRun{ID: "1", JobID: "job"})
+	defer done()
+
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/debug/queue")
+	cltest.CheckStatusCode(t, resp, 200)
+	body := cltest.ParseResponseBody(resp)
+	assert.Equal(t, int64(2), gjson.GetBytes(body, "workers").Int())
+	assert.Equal(t, int64(1), gjson.GetBytes(body, "busy").Int())
+	assert.Equal(t, 0.5, gjson.GetBytes(body, "utilization").Float())
+	assert.Equal(t, `{}`, gjson.GetBytes(body, "queued").Raw)
+}
@@ -12,14 +12,104 @@
 // to the node, and shows the current jobs which have already
 // been added.
 //
+// JobSpecsController
+//
+// JobSpecsController exports and imports the specs of the node's jobs as a
+// JSON or YAML bundle, for provisioning nodes from version-controlled job
+// definitions.
+//
 // JobRunsController
 //
 // JobRunsController allows for the creation of JobRuns within
 // a given Job on the node.
 //
+// ForwardedRunsController
+//
+// ForwardedRunsController executes the tasks another node forwards to this
+// one, and responds with their result, so that a node can leave the work
+// of its runs to secondary nodes.
+//
+// FeedsController
+//
+// FeedsController manages feeds, which group jobs fetching the same value
+// from different sources and report the median of their results.
+//
 // BridgeTypesController
 //
 // BridgeTypesController allows for the creation of BridgeTypes
 // on the node. BridgeTypes are the external adapters which add
 // functionality not available in the core, from outside the node.
+//
+// SessionsController
+//
+// SessionsController logs the operator in and out, using a session
+// cookie to authenticate later requests. Operators can also log in with
+// LDAP, or sign in through an OpenID Connect provider.
+//
+// APITokensController
+//
+// APITokensController manages the long-lived tokens that programmatic
+// clients authenticate with instead of a session.
+//
+// UsersController
+//
+// UsersController manages who can log in to the node and the role each
+// of them has.
+//
+// TOTPController
+//
+// TOTPController sets up two-factor authentication for the logged in
+// user, and turns it off again.
+//
+// ConfigController
+//
+// ConfigController changes the settings that can be changed while the
+// node is running, reloads them from the config file, and shows who
+// changed what and when.
+//
+// LogController
+//
+// LogController shows and changes the log level of the node, and of the
+// subsystems whose levels are set apart from it.
+//
+// ManifestController
+//
+// ManifestController registers the state the node is meant to be in, and
+// checks the node's actual state against it for drift.
+//
+// AuditController
+//
+// AuditController shows and exports the audit log of privileged actions,
+// such as creating jobs, starting runs, and managing users and API tokens.
+//
+// TxsController
+//
+// TxsController builds transactions for operators to sign offline, on a
+// machine holding the key, and broadcasts them once they are signed.
+//
+// BalancesController
+//
+// BalancesController shows the ether and LINK balances of the node's
+// account, in the units the node is set to show them in.
+//
+// EventsController
+//
+// EventsController streams events about runs and transactions over a
+// websocket, so that UIs can show progress without polling.
+//
+// DebugController
+//
+// DebugController shows internal state, such as the queue of runs waiting
+// to execute, to help operators track down why the node is slow.
+//
+// HealthController
+//
+// HealthController serves the liveness and readiness probes, with the
+// result of each check, so orchestrators know when to restart the node
+// and when to hold traffic from it.
+//
+// StatusController
+//
+// StatusController serves the optional public status page, with the
+// node's health, job counts, and when it last fulfilled a run.
 package web
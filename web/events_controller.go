@@ -0,0 +1,85 @@
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/services"
+)
+
+// eventWriteTimeout is how long sending an event to a client can take
+// before the client is disconnected.
+const eventWriteTimeout = 10 * time.Second
+
+// EventsController streams job run events to clients over a websocket.
+type EventsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Stream upgrades the request to a websocket and sends every run created,
+// task completed, run completed, run errored, and tx confirmed event as
+// JSON until the client disconnects.
+// Example:
+//  "<application>/ws"
+func (ec *EventsController) Stream(c *gin.Context) {
+	// Subscribing first means the client gets every event published once
+	// it has connected.
+	events := ec.App.Store.Events.Subscribe()
+	defer ec.App.Store.Events.Unsubscribe(events)
+
+	upgrader := websocket.Upgrader{CheckOrigin: ec.checkOrigin}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Web.Warnw("Upgrading event stream", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// Clients do not send anything, but reading is how a closed connection
+	// is noticed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			conn.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// checkOrigin lets browsers connect from the node's own origin, or from
+// one of the ALLOW_ORIGINS, so that other sites cannot read the stream
+// with a logged in operator's session cookie.
+func (ec *EventsController) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return true
+	}
+	for _, allowed := range strings.Split(ec.App.Store.CurrentConfig().AllowOrigins, ",") {
+		if strings.TrimSpace(allowed) == origin {
+			return true
+		}
+	}
+	return false
+}
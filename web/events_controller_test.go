@@ -0,0 +1,54 @@
+package web_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsController_Stream(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	apiToken, token := models.NewAPIToken("dashboard", models.RoleView)
+	assert.Nil(t, app.Store.Save(&apiToken))
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	wsURL := "ws" + strings.TrimPrefix(app.Server.URL, "http") + "/v2/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	jr := cltest.CreateJobRunViaWeb(t, app, j)
+
+	for _, eventType := range []string{models.EventRunCreated, models.EventTaskCompleted, models.EventRunCompleted} {
+		var event models.Event
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		assert.Nil(t, conn.ReadJSON(&event))
+		assert.Equal(t, eventType, event.Type)
+		assert.Equal(t, j.ID, event.JobID)
+		assert.Equal(t, jr.ID, event.RunID)
+	}
+}
+
+func TestEventsController_Stream_RequiresAuth(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	wsURL := "ws" + strings.TrimPrefix(app.Server.URL, "http") + "/v2/ws"
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	assert.NotNil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+}
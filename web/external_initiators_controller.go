@@ -0,0 +1,116 @@
+package web
+
+import (
+	"github.com/asdine/storm"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/store/presenters"
+)
+
+// The headers an external initiator authenticates with.
+const (
+	// ExternalInitiatorAccessKeyHeader holds the external initiator's access
+	// key.
+	ExternalInitiatorAccessKeyHeader = "X-Chainlink-EA-AccessKey"
+	// ExternalInitiatorSecretHeader holds the external initiator's secret.
+	ExternalInitiatorSecretHeader = "X-Chainlink-EA-Secret"
+)
+
+// externalInitiatorKey is the context key holding the name of the external
+// initiator that made the request.
+const externalInitiatorKey = "externalInitiator"
+
+// ExternalInitiatorsController manages the external services that can
+// start runs of jobs with an "external" initiator.
+type ExternalInitiatorsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index lists the node's external initiators, without their secrets.
+// Example:
+//  "<application>/external_initiators"
+func (eic *ExternalInitiatorsController) Index(c *gin.Context) {
+	eis := []models.ExternalInitiator{}
+	if err := eic.App.Store.All(&eis); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewExternalInitiators(eis))
+	}
+}
+
+// Create registers an external initiator with the given name, and
+// optionally the URL it is told about its jobs at. The response is the only
+// time its secret and outgoing token are shown.
+// Example:
+//  "<application>/external_initiators"
+func (eic *ExternalInitiatorsController) Create(c *gin.Context) {
+	var request struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	ei, secret, err := models.NewExternalInitiator(request.Name, request.URL)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	if _, err := eic.App.Store.FindExternalInitiator(ei.Name); err == nil {
+		problem(c, 409, "External initiator "+ei.Name+" already exists")
+	} else if err != storm.ErrNotFound {
+		problem(c, 500, err.Error())
+	} else if err := eic.App.Store.Save(&ei); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{
+			"id":            ei.ID,
+			"name":          ei.Name,
+			"url":           ei.URL,
+			"accessKey":     ei.AccessKey,
+			"secret":        secret,
+			"outgoingToken": ei.OutgoingToken,
+			"createdAt":     ei.CreatedAt,
+		})
+	}
+}
+
+// Destroy removes the external initiator with the given name, so that it
+// can no longer start runs.
+// Example:
+//  "<application>/external_initiators/:Name"
+func (eic *ExternalInitiatorsController) Destroy(c *gin.Context) {
+	if ei, err := eic.App.Store.FindExternalInitiator(c.Param("Name")); err == storm.ErrNotFound {
+		problem(c, 404, "External initiator not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if err = eic.App.Store.DeleteStruct(&ei); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"name": ei.Name})
+	}
+}
+
+// externalInitiatorAuthRequired only lets requests through that carry the
+// access key and secret of a registered external initiator. Its name is
+// kept in the context, and as who made the request.
+func externalInitiatorAuthRequired(store *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessKey := c.GetHeader(ExternalInitiatorAccessKeyHeader)
+		if accessKey == "" {
+			abortProblem(c, 401, "Unauthorized")
+			return
+		}
+		ei, err := store.FindExternalInitiatorByAccessKey(accessKey)
+		if err != nil || !ei.CheckSecret(c.GetHeader(ExternalInitiatorSecretHeader)) {
+			abortProblem(c, 401, "Unauthorized")
+			return
+		}
+		c.Set(identityKey, "External initiator "+ei.Name)
+		c.Set(externalInitiatorKey, ei.Name)
+		c.Next()
+	}
+}
@@ -0,0 +1,134 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalInitiatorsController_Create(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"name":"IoT","url":"https://ei.example.com/jobs"}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/external_initiators", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created struct {
+		Name      string `json:"name"`
+		AccessKey string `json:"accessKey"`
+		Secret    string `json:"secret"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+	assert.Equal(t, "iot", created.Name)
+
+	stored, err := app.Store.FindExternalInitiatorByAccessKey(created.AccessKey)
+	assert.Nil(t, err)
+	assert.True(t, stored.CheckSecret(created.Secret))
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/external_initiators", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 409)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/external_initiators")
+	cltest.CheckStatusCode(t, resp, 200)
+	listed := string(cltest.ParseResponseBody(resp))
+	assert.NotContains(t, listed, created.Secret)
+	assert.NotContains(t, listed, stored.HashedSecret)
+	assert.NotContains(t, listed, stored.OutgoingToken)
+
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/external_initiators/iot")
+	cltest.CheckStatusCode(t, resp, 200)
+	_, err = app.Store.FindExternalInitiator("iot")
+	assert.NotNil(t, err)
+}
+
+func TestExternalInitiatorsController_Create_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/external_initiators", bytes.NewBufferString(`{"url":"https://ei.example.com"}`))
+	cltest.CheckStatusCode(t, resp, 400)
+}
+
+func TestJobRunsController_CreateExternal(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	ei, secret, err := models.NewExternalInitiator("iot", "")
+	assert.Nil(t, err)
+	assert.Nil(t, app.Store.Save(&ei))
+	other, otherSecret, err := models.NewExternalInitiator("exchange", "")
+	assert.Nil(t, err)
+	assert.Nil(t, app.Store.Save(&other))
+
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{Type: models.InitiatorExternal, Name: "iot"}}
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	post := func(accessKey, secret string) *http.Response {
+		url := app.Server.URL + "/v2/specs/" + j.ID + "/runs"
+		req, err := http.NewRequest("POST", url, bytes.NewBufferString(`{"value":"100"}`))
+		assert.Nil(t, err)
+		req.Header.Set(web.ExternalInitiatorAccessKeyHeader, accessKey)
+		req.Header.Set(web.ExternalInitiatorSecretHeader, secret)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	cltest.CheckStatusCode(t, post(ei.AccessKey, "wrong"), 401)
+	cltest.CheckStatusCode(t, post("", ""), 401)
+	cltest.CheckStatusCode(t, post(other.AccessKey, otherSecret), 403)
+
+	resp := post(ei.AccessKey, secret)
+	cltest.CheckStatusCode(t, resp, 200)
+	jr := models.JobRun{ID: cltest.ParseCommonJSON(resp.Body).ID}
+	jr = cltest.WaitForJobRunToComplete(t, app, jr)
+	val, err := jr.Result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "100", val)
+}
+
+func TestJobRunsController_CreateExternal_CreatedThroughAPI(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/external_initiators", bytes.NewBufferString(`{"name":"iot"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created struct {
+		AccessKey     string `json:"accessKey"`
+		Secret        string `json:"secret"`
+		OutgoingToken string `json:"outgoingToken"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+
+	stored, err := app.Store.FindExternalInitiator("iot")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, stored.HashedSecret)
+	assert.Equal(t, created.OutgoingToken, stored.OutgoingToken)
+
+	j := cltest.NewJob()
+	j.Initiators = []models.Initiator{{Type: models.InitiatorExternal, Name: "iot"}}
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	req, err := http.NewRequest("POST", app.Server.URL+"/v2/specs/"+j.ID+"/runs", bytes.NewBufferString(`{"value":"100"}`))
+	assert.Nil(t, err)
+	req.Header.Set(web.ExternalInitiatorAccessKeyHeader, created.AccessKey)
+	req.Header.Set(web.ExternalInitiatorSecretHeader, created.Secret)
+	resp, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+}
@@ -0,0 +1,115 @@
+package web
+
+import (
+	"github.com/asdine/storm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/store/presenters"
+)
+
+// FeedsController manages the feeds that aggregate the results of several
+// jobs into a single reported answer.
+type FeedsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index lists the node's feeds with their current answers.
+// Example:
+//  "<application>/feeds"
+func (fc *FeedsController) Index(c *gin.Context) {
+	feeds := []models.Feed{}
+	if err := fc.App.Store.All(&feeds); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	now := fc.App.Store.Clock.Now()
+	pfs := make([]presenters.Feed, len(feeds))
+	for i, f := range feeds {
+		pfs[i] = presenters.NewFeed(f, now)
+	}
+	c.JSON(200, pfs)
+}
+
+// Show returns the feed with the given ID, with the latest result of each
+// of its jobs.
+// Example:
+//  "<application>/feeds/:FeedID"
+func (fc *FeedsController) Show(c *gin.Context) {
+	var feed models.Feed
+	if err := fc.App.Store.One("ID", c.Param("FeedID"), &feed); err == storm.ErrNotFound {
+		problem(c, 404, "Feed not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewFeed(feed, fc.App.Store.Clock.Now()))
+	}
+}
+
+// Create adds a feed of the given jobs, which must already exist, that
+// reports to the given contract address and function.
+// Example:
+//  "<application>/feeds"
+func (fc *FeedsController) Create(c *gin.Context) {
+	var request struct {
+		Name             string                  `json:"name"`
+		JobIDs           []string                `json:"jobIds"`
+		Address          common.Address          `json:"address"`
+		FunctionSelector models.FunctionSelector `json:"functionSelector"`
+		Decimals         uint                    `json:"decimals"`
+		Deviation        float64                 `json:"deviation"`
+		Heartbeat        string                  `json:"heartbeat"`
+		MinSources       int                     `json:"minSources"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	feed := models.NewFeed()
+	feed.Name = request.Name
+	feed.JobIDs = request.JobIDs
+	feed.Address = request.Address
+	feed.FunctionSelector = request.FunctionSelector
+	feed.Decimals = request.Decimals
+	feed.Deviation = request.Deviation
+	feed.Heartbeat = request.Heartbeat
+	feed.MinSources = request.MinSources
+	if err := feed.Validate(); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	for _, id := range feed.JobIDs {
+		if _, err := fc.App.Store.FindJob(id); err == storm.ErrNotFound {
+			problem(c, 400, "Job "+id+" not found")
+			return
+		} else if err != nil {
+			problem(c, 500, err.Error())
+			return
+		}
+	}
+
+	if err := fc.App.Store.Save(&feed); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewFeed(feed, fc.App.Store.Clock.Now()))
+	}
+}
+
+// Destroy deletes the feed with the given ID. Its jobs are left as they
+// are.
+// Example:
+//  "<application>/feeds/:FeedID"
+func (fc *FeedsController) Destroy(c *gin.Context) {
+	var feed models.Feed
+	if err := fc.App.Store.One("ID", c.Param("FeedID"), &feed); err == storm.ErrNotFound {
+		problem(c, 404, "Feed not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if err = fc.App.Store.DeleteStruct(&feed); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": feed.ID})
+	}
+}
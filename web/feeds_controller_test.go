@@ -0,0 +1,84 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedsController_CreateShowDestroy(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j1 := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j1))
+	j2 := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j2))
+
+	body := fmt.Sprintf(`{
+		"name": "ETH/USD",
+		"jobIds": [%q, %q],
+		"address": "0x0000000000000000000000000000000000000abc",
+		"functionSelector": "0x9a6fc8f5",
+		"decimals": 8,
+		"deviation": 0.5,
+		"heartbeat": "1h"
+	}`, j1.ID, j2.ID)
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/feeds", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created models.Feed
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+	assert.Equal(t, "ETH/USD", created.Name)
+	assert.Equal(t, []string{j1.ID, j2.ID}, created.JobIDs)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/feeds/" + created.ID)
+	cltest.CheckStatusCode(t, resp, 200)
+	var shown struct {
+		models.Feed
+		CurrentAnswer *float64 `json:"currentAnswer"`
+		FreshSources  int      `json:"freshSources"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &shown))
+	assert.Equal(t, models.HexToFunctionSelector("0x9a6fc8f5"), shown.FunctionSelector)
+	assert.Equal(t, "1h", shown.Heartbeat)
+	assert.Nil(t, shown.CurrentAnswer)
+	assert.Equal(t, 0, shown.FreshSources)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/feeds")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Contains(t, string(cltest.ParseResponseBody(resp)), created.ID)
+
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/feeds/" + created.ID)
+	cltest.CheckStatusCode(t, resp, 200)
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/feeds/" + created.ID)
+	cltest.CheckStatusCode(t, resp, 404)
+}
+
+func TestFeedsController_Create_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no jobs", `{"address": "0x0000000000000000000000000000000000000abc", "functionSelector": "0x9a6fc8f5"}`},
+		{"unknown job", `{"jobIds": ["nope"], "address": "0x0000000000000000000000000000000000000abc", "functionSelector": "0x9a6fc8f5"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/feeds", bytes.NewBufferString(test.body))
+			cltest.CheckStatusCode(t, resp, 400)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// ForwardedRunsController executes the tasks that a node forwards to this
+// one with its Forward adapter.
+type ForwardedRunsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Create executes the forwarded tasks with the given data, and responds
+// with the result the run ended with. The run is kept under a job of its
+// own, which is not saved, as the job belongs to the forwarding node.
+// Example:
+//  "<application>/forwarded_runs"
+func (frc *ForwardedRunsController) Create(c *gin.Context) {
+	var request adapters.ForwardRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	job := models.NewJob()
+	job.Tasks = request.Tasks
+	if len(job.Tasks) == 0 {
+		problem(c, 400, "Must have tasks to execute")
+		return
+	}
+	if err := adapters.ValidateForwarded(job.Tasks); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	if err := adapters.Validate(job, frc.App.Store); err != nil {
+		jobSpecProblem(c, err)
+		return
+	}
+
+	logger.Web.Infow("Executing forwarded run", "forwardedRun", request.JobRunID, "job", job.ID)
+	run, err := services.BeginRun(job, frc.App.Store, models.RunResult{Data: request.Data})
+	result := run.Result
+	result.JobRunID = run.ID
+	if err != nil {
+		result = result.WithError(err)
+	}
+	c.JSON(200, result)
+}
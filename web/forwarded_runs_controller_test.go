@@ -0,0 +1,79 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardedRunsController_Create(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"jobRunId":"primary","data":{"details":{"last":"100.1"}},"tasks":[{"type":"Copy","copyPath":["details","last"]}]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/forwarded_runs", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+
+	var result models.RunResult
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &result))
+	assert.False(t, result.HasError())
+	assert.Equal(t, "100.1", result.Data.Get("value").String())
+
+	run, err := app.Store.FindJobRun(result.JobRunID)
+	assert.Nil(t, err)
+	assert.Equal(t, models.StatusCompleted, run.Status)
+}
+
+func TestForwardedRunsController_Create_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no tasks", `{"data":{},"tasks":[]}`},
+		{"ethtx", `{"data":{},"tasks":[{"type":"EthTx"}]}`},
+		{"ethtx in a group", `{"data":{},"tasks":[{"type":"Parallel","tasks":[{"type":"EthTx"}]}]}`},
+		{"ethtx fallback", `{"data":{},"tasks":[{"type":"NoOp","onError":[{"type":"EthTx"}]}]}`},
+		{"unknown adapter", `{"data":{},"tasks":[{"type":"nonexistent"}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/forwarded_runs", bytes.NewBufferString(test.body))
+			cltest.CheckStatusCode(t, resp, 400)
+		})
+	}
+}
+
+func TestForwardedRunsController_Create_RunRole(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/api_tokens", bytes.NewBufferString(`{"name":"runner","role":"run"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created struct {
+		Token string `json:"token"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+
+	body := `{"data":{},"tasks":[{"type":"NoOp"}]}`
+	req, err := http.NewRequest("POST", app.Server.URL+"/v2/forwarded_runs", bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+created.Token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 403)
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+)
+
+// HealthController reports whether the node is alive, and whether it is
+// ready to take work, for load balancers and orchestrators such as
+// Kubernetes to poll.
+type HealthController struct {
+	App *services.ChainlinkApplication
+}
+
+// healthCheck returns an error describing why part of the node is not
+// working.
+type healthCheck func(app *services.ChainlinkApplication) error
+
+// Show returns whether the node is alive: its web server is up and its
+// store can be read. A failing node should be restarted. It does not
+// require authentication so that load balancers and monitoring can poll it.
+// Example:
+//  "<application>/health"
+func (hc *HealthController) Show(c *gin.Context) {
+	hc.respond(c, map[string]healthCheck{
+		"store": checkStore,
+	})
+}
+
+// Readiness returns whether the node is ready to take work: as well as
+// being alive, the ethereum node can be reached, the keystore has been
+// unlocked, and the node is subscribed to new heads. Traffic should be
+// held from a node that is not ready, but it need not be restarted.
+// Example:
+//  "<application>/readiness"
+func (hc *HealthController) Readiness(c *gin.Context) {
+	hc.respond(c, map[string]healthCheck{
+		"store":         checkStore,
+		"ethereum":      checkEthereum,
+		"keystore":      checkKeyStore,
+		"subscriptions": checkSubscriptions,
+	})
+}
+
+// respond runs each check, responding with 200 if they all pass and 503
+// otherwise, along with the status of each.
+func (hc *HealthController) respond(c *gin.Context, checks map[string]healthCheck) {
+	code, status := 200, "ok"
+	results := gin.H{}
+	for name, check := range checks {
+		if err := check(hc.App); err != nil {
+			code, status = 503, "failing"
+			results[name] = gin.H{"status": "failing", "error": err.Error()}
+		} else {
+			results[name] = gin.H{"status": "ok"}
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "checks": results})
+}
+
+func checkStore(app *services.ChainlinkApplication) error {
+	return app.Store.Ping()
+}
+
+func checkEthereum(app *services.ChainlinkApplication) error {
+	_, err := app.Store.TxManager.GetBlockNumber()
+	return err
+}
+
+func checkKeyStore(app *services.ChainlinkApplication) error {
+	if !app.Store.Signer.Unlocked() {
+		return errors.New("No unlocked account")
+	}
+	return nil
+}
+
+func checkSubscriptions(app *services.ChainlinkApplication) error {
+	return app.NotificationListener.Connected()
+}
@@ -0,0 +1,72 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestHealthController_Show(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp, err := http.Get(app.Server.URL + "/health")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.JSONEq(t, `{"status":"ok","checks":{"store":{"status":"ok"}}}`, string(cltest.ParseResponseBody(resp)))
+}
+
+func TestHealthController_Readiness(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	eth := app.MockEthClient()
+	eth.Register("eth_blockNumber", utils.Uint64ToHex(1))
+	app.Start()
+
+	resp, err := http.Get(app.Server.URL + "/readiness")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+	body := cltest.ParseResponseBody(resp)
+	assert.Equal(t, "ok", gjson.GetBytes(body, "status").String())
+	for _, check := range []string{"store", "ethereum", "keystore", "subscriptions"} {
+		assert.Equal(t, "ok", gjson.GetBytes(body, "checks."+check+".status").String(), check)
+	}
+}
+
+func TestHealthController_ReadinessFailing(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	eth := app.MockEthClient()
+	eth.RegisterError("eth_blockNumber", "connection refused")
+
+	resp, err := http.Get(app.Server.URL + "/readiness")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 503)
+	body := cltest.ParseResponseBody(resp)
+	assert.Equal(t, "failing", gjson.GetBytes(body, "status").String())
+	assert.Equal(t, "ok", gjson.GetBytes(body, "checks.store.status").String())
+	assert.Equal(t, "connection refused", gjson.GetBytes(body, "checks.ethereum.error").String())
+	assert.Equal(t, "failing", gjson.GetBytes(body, "checks.keystore.status").String())
+	assert.Equal(t, "failing", gjson.GetBytes(body, "checks.subscriptions.status").String())
+}
+
+func TestHealthController_JobsStillRequireAuth(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp, err := http.Get(app.Server.URL + "/v2/jobs")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/asdine/storm"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// The identity providers that users can come from, other than the node's
+// own users.
+const (
+	// ProviderLDAP users log in with the password of their LDAP entry.
+	ProviderLDAP = "ldap"
+	// ProviderOIDC users log in through an OpenID Connect provider.
+	ProviderOIDC = "oidc"
+)
+
+// Authenticator checks the name and password an operator logs in with
+// against an identity provider, returning the groups the operator is in
+// there, which AUTH_GROUP_ROLES maps to a role.
+type Authenticator interface {
+	Authenticate(name, password string) ([]string, error)
+}
+
+// remoteAuthenticator returns the Authenticator for the identity provider
+// set in the config, or nil if there is none.
+func remoteAuthenticator(config store.Config) Authenticator {
+	if config.LDAPURL != "" {
+		return NewLDAPAuthenticator(config)
+	}
+	return nil
+}
+
+// GroupRoles maps the groups of an identity provider to roles, as given
+// in AUTH_GROUP_ROLES, such as "chainlink-admins=admin,oracle-ops=edit".
+type GroupRoles map[string]string
+
+// ParseGroupRoles returns the mapping of groups to roles in the comma
+// separated list of group=role pairs.
+func ParseGroupRoles(s string) (GroupRoles, error) {
+	gr := GroupRoles{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("AUTH_GROUP_ROLES: %v is not group=role", pair)
+		}
+		group, role := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		if !models.ValidRole(role) {
+			return nil, fmt.Errorf("AUTH_GROUP_ROLES: role %v does not exist", role)
+		}
+		gr[strings.ToLower(group)] = role
+	}
+	return gr, nil
+}
+
+// RoleFor returns the most capable role of the given groups. A group can
+// be given by its full name, such as an LDAP group's DN, or by the value
+// of its first part, such as the DN's cn. Operators in none of the mapped
+// groups are not let in.
+func (gr GroupRoles) RoleFor(groups []string) (string, error) {
+	role := ""
+	for _, group := range groups {
+		for _, name := range groupNames(group) {
+			if r, ok := gr[strings.ToLower(name)]; ok && (role == "" || !models.RoleAllows(role, r)) {
+				role = r
+			}
+		}
+	}
+	if role == "" {
+		return "", errors.New("Not in any group with a role on this node")
+	}
+	return role, nil
+}
+
+// groupNames returns the group, and the value of its first part if it is
+// a DN such as "cn=oracle-ops,ou=groups,dc=example,dc=com".
+func groupNames(group string) []string {
+	names := []string{group}
+	first := strings.SplitN(group, ",", 2)[0]
+	if i := strings.Index(first, "="); i > 0 && first != group {
+		names = append(names, strings.TrimSpace(first[i+1:]))
+	}
+	return names
+}
+
+// saveRemoteUser keeps the operator who logged in through an identity
+// provider as a user, with the role their groups give them, so that their
+// sessions, audit entries, and two-factor authentication work as they do
+// for the node's own users. Their role is updated every time they log in.
+func saveRemoteUser(store *store.Store, email, provider string, groups []string) (models.User, error) {
+	gr, err := ParseGroupRoles(store.CurrentConfig().AuthGroupRoles)
+	if err != nil {
+		return models.User{}, err
+	}
+	role, err := gr.RoleFor(groups)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := store.FindUser(email)
+	if err == nil && user.Provider != provider {
+		return models.User{}, fmt.Errorf("User %v is not from %v", user.Email, provider)
+	} else if err == storm.ErrNotFound {
+		user = models.User{
+			Email:     strings.ToLower(email),
+			Provider:  provider,
+			CreatedAt: models.Time{Time: store.Clock.Now()},
+		}
+	} else if err != nil {
+		return models.User{}, err
+	}
+	user.Role = role
+	return user, store.Save(&user)
+}
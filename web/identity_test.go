@@ -0,0 +1,48 @@
+package web_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupRoles_RoleFor(t *testing.T) {
+	t.Parallel()
+
+	gr, err := web.ParseGroupRoles("Oracle-Ops=edit, viewers=view,cn=admins,ou=groups,dc=example,dc=com=admin")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		groups  []string
+		want    string
+		wantErr bool
+	}{
+		{"by name", []string{"viewers"}, models.RoleView, false},
+		{"ignores case", []string{"oracle-ops"}, models.RoleEdit, false},
+		{"by cn", []string{"cn=Oracle-Ops,ou=groups,dc=example,dc=com"}, models.RoleEdit, false},
+		{"by dn", []string{"cn=admins,ou=groups,dc=example,dc=com"}, models.RoleAdmin, false},
+		{"most capable", []string{"viewers", "oracle-ops", "other"}, models.RoleEdit, false},
+		{"no mapped group", []string{"other"}, "", true},
+		{"no groups", []string{}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			role, err := gr.RoleFor(test.groups)
+			assert.Equal(t, test.want, role)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestParseGroupRoles_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := web.ParseGroupRoles("ops=root")
+	assert.NotNil(t, err)
+	_, err = web.ParseGroupRoles("ops")
+	assert.NotNil(t, err)
+}
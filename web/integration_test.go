@@ -194,7 +194,7 @@ func TestIntegration_EndAt(t *testing.T) {
 	clock.SetTime(endAt.Add(time.Nanosecond))
 
 	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
-	resp := cltest.BasicAuthPost(url, "application/json", &bytes.Buffer{})
+	resp := cltest.AuthenticatedPost(url, &bytes.Buffer{})
 	assert.Equal(t, 500, resp.StatusCode)
 	gomega.NewGomegaWithT(t).Consistently(func() []models.JobRun {
 		jobRuns, err := app.Store.JobRunsFor(j.ID)
@@ -216,7 +216,7 @@ func TestIntegration_StartAt(t *testing.T) {
 	assert.Equal(t, startAt, j.StartAt.Time)
 
 	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
-	resp := cltest.BasicAuthPost(url, "application/json", &bytes.Buffer{})
+	resp := cltest.AuthenticatedPost(url, &bytes.Buffer{})
 	assert.Equal(t, 500, resp.StatusCode)
 	cltest.WaitForRuns(t, j, app.Store, 0)
 
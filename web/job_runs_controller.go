@@ -1,7 +1,12 @@
 package web
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
 
 	"github.com/asdine/storm"
 	"github.com/gin-gonic/gin"
@@ -23,81 +28,229 @@ func (jrc *JobRunsController) Index(c *gin.Context) {
 	id := c.Param("JobID")
 
 	if jobRuns, err := jrc.App.Store.JobRunsFor(id); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else {
 		c.JSON(200, gin.H{"runs": jobRuns})
 	}
 }
 
-// Create starts a new JobRun for the Job specified.
+// All lists the JobRuns of every Job, newest first. The "jobId" and
+// "status" query parameters only list runs of that job or with that
+// status, where the status is one of queued, in_progress, pending,
+// errored, or completed. The "from" and "to" parameters only list runs
+// created within that RFC 3339 time range, and "offset" and "limit" page
+// through the runs. The total number of matching runs is returned in the
+// X-Total-Count header.
+// Example:
+//  "<application>/runs?status=errored&from=2018-05-01T00:00:00Z&limit=20"
+func (jrc *JobRunsController) All(c *gin.Context) {
+	filter, err := jobRunFilter(c)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	offset, limit, err := pagingParams(c)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	if jobRuns, total, err := jrc.App.Store.JobRunsPage(filter, offset, limit); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.Header("X-Total-Count", strconv.Itoa(total))
+		c.JSON(200, gin.H{"runs": jobRuns})
+	}
+}
+
+// Prune deletes finished runs now, rather than waiting for the run reaper.
+// The "age" query parameter, a duration such as 720h, deletes runs older
+// than that, and "keep" deletes those beyond the newest of each job. Each
+// defaults to its retention setting.
+// Example:
+//  "<application>/runs/prune?age=720h&keep=100"
+func (jrc *JobRunsController) Prune(c *gin.Context) {
+	config := jrc.App.Store.CurrentConfig()
+	age := config.RunRetentionAge
+	keep := int(config.RunRetentionCount)
+	var err error
+	if s := c.Query("age"); s != "" {
+		if age, err = time.ParseDuration(s); err != nil {
+			problem(c, 400, "age must be a duration, such as 720h")
+			return
+		}
+	}
+	if s := c.Query("keep"); s != "" {
+		if keep, err = strconv.Atoi(s); err != nil || keep < 0 {
+			problem(c, 400, "keep must be a number of runs")
+			return
+		}
+	}
+	if age <= 0 && keep == 0 {
+		problem(c, 422, "Must set age or keep, or the run retention settings")
+		return
+	}
+
+	if pruned, err := services.PruneRuns(jrc.App.Store, age, keep); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"pruned": pruned})
+	}
+}
+
+var runStatuses = map[string]string{
+	"queued":      models.StatusQueued,
+	"in_progress": models.StatusInProgress,
+	"pending":     models.StatusPending,
+	"errored":     models.StatusErrored,
+	"completed":   models.StatusCompleted,
+}
+
+func jobRunFilter(c *gin.Context) (models.JobRunFilter, error) {
+	filter := models.JobRunFilter{JobID: c.Query("jobId")}
+	if status := c.Query("status"); status != "" {
+		s, ok := runStatuses[status]
+		if !ok {
+			return filter, fmt.Errorf("Unknown status %v", status)
+		}
+		filter.Status = s
+	}
+
+	var err error
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return filter, fmt.Errorf("Invalid from time %v", from)
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return filter, fmt.Errorf("Invalid to time %v", to)
+		}
+	}
+	return filter, nil
+}
+
+// Create starts a new JobRun for the Job specified. An optional JSON object
+// in the request body is used as the data the run starts with.
 // Example:
 //  "<application>/jobs/:JobID/runs"
 func (jrc *JobRunsController) Create(c *gin.Context) {
 	id := c.Param("JobID")
-	if j, err := jrc.App.Store.FindJob(id); err == storm.ErrNotFound {
-		c.JSON(404, gin.H{
-			"errors": []string{"Job not found"},
-		})
+	if input, err := runInput(c); err != nil {
+		problem(c, 400, err.Error())
+	} else if j, err := jrc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found")
 	} else if err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else if !j.WebAuthorized() {
-		c.JSON(403, gin.H{
-			"errors": []string{"Job not available on web API. Recreate with web initiator."},
-		})
-	} else if jr, err := startJob(j, jrc.App.Store); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 403, "Job not available on web API. Recreate with web initiator.")
+	} else if jr, err := startJob(j, jrc.App.Store, input); err != nil {
+		problem(c, 500, err.Error())
 	} else {
 		c.JSON(200, gin.H{"id": jr.ID})
 	}
 }
 
-// Update marks the JobRun no longer pending, and resumes the Job's pipeline.
+// CreateExternal starts a new JobRun for the Job specified on behalf of the
+// external initiator that made the request, as long as the Job has an
+// "external" initiator naming it. An optional JSON object in the request
+// body is used as the data the run starts with.
+// Example:
+//  "<application>/specs/:SpecID/runs"
+func (jrc *JobRunsController) CreateExternal(c *gin.Context) {
+	id := c.Param("SpecID")
+	if input, err := runInput(c); err != nil {
+		problem(c, 400, err.Error())
+	} else if j, err := jrc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if !j.ExternallyAuthorized(c.GetString(externalInitiatorKey)) {
+		problem(c, 403, "Job cannot be started by this external initiator")
+	} else if jr, err := startJob(j, jrc.App.Store, input); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": jr.ID})
+	}
+}
+
+// Update marks the JobRun no longer pending, and resumes the Job's pipeline
+// from the next task. If the external adapter reports an error, the run
+// is marked as errored instead.
 // Example:
 //  "<application>/runs/:RunID"
 func (jrc *JobRunsController) Update(c *gin.Context) {
 	id := c.Param("RunID")
 	var rr models.RunResult
 	if jr, err := jrc.App.Store.FindJobRun(id); err == storm.ErrNotFound {
-		c.JSON(404, gin.H{
-			"errors": []string{"Job Run not found"},
-		})
+		problem(c, 404, "Job Run not found")
 	} else if err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else if !jr.Result.Pending {
-		c.JSON(405, gin.H{
-			"errors": []string{"Cannot resume a job run that isn't pending"},
-		})
+		problem(c, 405, "Cannot resume a job run that isn't pending")
 	} else if err := c.ShouldBindJSON(&rr); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else {
-		executeRun(jr, jrc.App.Store, rr)
+		resumeRun(jr, jrc.App.Store, rr)
 		c.JSON(200, gin.H{"id": jr.ID})
 	}
 }
 
-func startJob(j models.Job, s *store.Store) (models.JobRun, error) {
+// Logs returns the lines the node logged for the JobRun, oldest first. Only
+// the newest RUN_LOG_MAX_LINES lines of the newest RUN_LOG_MAX_RUNS runs are
+// kept, and none survive the node restarting.
+// Example:
+//  "<application>/runs/:RunID/logs"
+func (jrc *JobRunsController) Logs(c *gin.Context) {
+	id := c.Param("RunID")
+	if _, err := jrc.App.Store.FindJobRun(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job Run not found")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"logs": logger.LogsForRun(id)})
+	}
+}
+
+func runInput(c *gin.Context) (models.RunResult, error) {
+	b, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil || len(bytes.TrimSpace(b)) == 0 {
+		return models.RunResult{}, err
+	}
+	var data models.JSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return models.RunResult{}, err
+	}
+	if !data.IsObject() {
+		return models.RunResult{}, fmt.Errorf("Run input must be a JSON object")
+	}
+	return models.RunResult{Data: data}, nil
+}
+
+func startJob(j models.Job, s *store.Store, input models.RunResult) (models.JobRun, error) {
 	jr, err := services.BuildRun(j, s)
 	if err != nil {
 		return jr, err
 	}
-	executeRun(jr, s, models.RunResult{})
+	if jr, err = services.QueueRun(jr, s, input); err != nil {
+		return jr, err
+	}
+	executeRun(jr, s, input)
 	return jr, nil
 }
 
 func executeRun(jr models.JobRun, s *store.Store, rr models.RunResult) {
 	go func() {
 		if _, err := services.ExecuteRun(jr, s, rr); err != nil {
-			logger.Errorw(fmt.Sprintf("Web initiator: %v", err.Error()))
+			logger.Web.Errorw(fmt.Sprintf("Web initiator: %v", err.Error()))
+		}
+	}()
+}
+
+func resumeRun(jr models.JobRun, s *store.Store, rr models.RunResult) {
+	go func() {
+		if _, err := services.ResumeRun(jr, s, rr); err != nil {
+			logger.Web.Errorw(fmt.Sprintf("Web initiator: %v", err.Error()))
 		}
 	}()
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,7 +36,7 @@ func TestJobRunsController_Index(t *testing.T) {
 	jr2.CreatedAt = jr1.CreatedAt.Add(time.Second)
 	assert.Nil(t, app.Store.Save(&jr2))
 
-	resp := cltest.BasicAuthGet(app.Server.URL + "/v2/jobs/" + j.ID + "/runs")
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/jobs/" + j.ID + "/runs")
 	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
 	var respJSON JobRunsJSON
 	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &respJSON))
@@ -45,6 +46,102 @@ func TestJobRunsController_Index(t *testing.T) {
 	assert.Equal(t, jr1.ID, respJSON.Runs[1].ID, "expected runs ordered by created at(descending)")
 }
 
+func TestJobRunsController_All(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j1 := cltest.NewJob()
+	assert.Nil(t, app.Store.SaveJob(&j1))
+	j2 := cltest.NewJob()
+	assert.Nil(t, app.Store.SaveJob(&j2))
+
+	start := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+	jr1 := j1.NewRun()
+	jr1.Status = models.StatusCompleted
+	jr1.CreatedAt = start
+	assert.Nil(t, app.Store.Save(&jr1))
+	jr2 := j1.NewRun()
+	jr2.Status = models.StatusErrored
+	jr2.CreatedAt = start.Add(time.Hour)
+	assert.Nil(t, app.Store.Save(&jr2))
+	jr3 := j2.NewRun()
+	jr3.Status = models.StatusInProgress
+	jr3.CreatedAt = start.Add(2 * time.Hour)
+	assert.Nil(t, app.Store.Save(&jr3))
+
+	tests := []struct {
+		name      string
+		query     string
+		wantIDs   []string
+		wantTotal string
+	}{
+		{"all", "", []string{jr3.ID, jr2.ID, jr1.ID}, "3"},
+		{"by job", "?jobId=" + j1.ID, []string{jr2.ID, jr1.ID}, "2"},
+		{"by status", "?status=in_progress", []string{jr3.ID}, "1"},
+		{"by time", "?from=2018-05-01T01:00:00Z&to=2018-05-01T02:00:00Z", []string{jr2.ID}, "1"},
+		{"paged", "?offset=1&limit=1", []string{jr2.ID}, "3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/runs" + test.query)
+			assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+			assert.Equal(t, test.wantTotal, resp.Header.Get("X-Total-Count"))
+
+			var respJSON JobRunsJSON
+			assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &respJSON))
+			ids := []string{}
+			for _, jr := range respJSON.Runs {
+				ids = append(ids, jr.ID)
+			}
+			assert.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestJobRunsController_All_InvalidParams(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	for _, query := range []string{"?status=done", "?from=yesterday", "?limit=-1"} {
+		resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/runs" + query)
+		assert.Equal(t, 400, resp.StatusCode, query)
+	}
+}
+
+func TestJobRunsController_Prune(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJob()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	old := j.NewRun()
+	old.Status = models.StatusCompleted
+	old.CreatedAt = time.Now().Add(-48 * time.Hour)
+	assert.Nil(t, app.Store.Save(&old))
+	recent := j.NewRun()
+	recent.Status = models.StatusCompleted
+	recent.CreatedAt = time.Now()
+	assert.Nil(t, app.Store.Save(&recent))
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/runs/prune", bytes.NewBufferString(""))
+	assert.Equal(t, 422, resp.StatusCode, "Pruning should need a retention limit")
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/runs/prune?age=soon", bytes.NewBufferString(""))
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/runs/prune?age=24h", bytes.NewBufferString(""))
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+	assert.JSONEq(t, `{"pruned": 1}`, string(cltest.ParseResponseBody(resp)))
+
+	_, err := app.Store.FindJobRun(old.ID)
+	assert.NotNil(t, err)
+	_, err = app.Store.FindJobRun(recent.ID)
+	assert.Nil(t, err)
+}
+
 func TestJobRunsController_Create(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication()
@@ -57,6 +154,40 @@ func TestJobRunsController_Create(t *testing.T) {
 	cltest.WaitForJobRunToComplete(t, app, jr)
 }
 
+func TestJobRunsController_Create_WithInput(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
+	resp := cltest.AuthenticatedPost(url, bytes.NewBufferString(`{"value":"100"}`))
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+	jr := models.JobRun{ID: cltest.ParseCommonJSON(resp.Body).ID}
+
+	jr = cltest.WaitForJobRunToComplete(t, app, jr)
+	val, err := jr.Result.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "100", val)
+}
+
+func TestJobRunsController_Create_InvalidInput(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
+	for _, body := range []string{`{"value":`, `"100"`} {
+		resp := cltest.AuthenticatedPost(url, bytes.NewBufferString(body))
+		assert.Equal(t, 400, resp.StatusCode, body)
+	}
+}
+
 func TestJobRunsController_Create_WithoutWebInitiator(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication()
@@ -66,7 +197,7 @@ func TestJobRunsController_Create_WithoutWebInitiator(t *testing.T) {
 	assert.Nil(t, app.Store.SaveJob(&j))
 
 	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
-	resp := cltest.BasicAuthPost(url, "application/json", bytes.NewBuffer([]byte{}))
+	resp := cltest.AuthenticatedPost(url, bytes.NewBuffer([]byte{}))
 	assert.Equal(t, 403, resp.StatusCode, "Response should be forbidden")
 }
 
@@ -76,7 +207,7 @@ func TestJobRunsController_Create_NotFound(t *testing.T) {
 	defer cleanup()
 
 	url := app.Server.URL + "/v2/jobs/garbageID/runs"
-	resp := cltest.BasicAuthPost(url, "application/json", bytes.NewBuffer([]byte{}))
+	resp := cltest.AuthenticatedPost(url, bytes.NewBuffer([]byte{}))
 	assert.Equal(t, 404, resp.StatusCode, "Response should be not found")
 }
 
@@ -105,7 +236,7 @@ func TestJobRunsController_Update(t *testing.T) {
 
 	url := app.Server.URL + "/v2/runs/" + jr.ID
 	body := fmt.Sprintf(`{"id":"%v","data":{"value": "100"}}`, jr.ID)
-	resp := cltest.BasicAuthPatch(url, "application/json", bytes.NewBufferString(body))
+	resp := cltest.AuthenticatedPatch(url, bytes.NewBufferString(body))
 	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
 	jrID := cltest.ParseCommonJSON(resp.Body).ID
 	assert.Equal(t, jr.ID, jrID)
@@ -116,6 +247,40 @@ func TestJobRunsController_Update(t *testing.T) {
 	assert.Equal(t, "100", val)
 }
 
+func TestJobRunsController_Update_WithError(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	bt := models.BridgeType{
+		Name: "slowcomputation",
+		URL:  cltest.WebURL("http://localhost:12345"),
+	}
+	assert.Nil(t, app.Store.Save(&bt))
+	j := cltest.NewJob()
+	j.Tasks = []models.Task{
+		{Type: bt.Name, Params: cltest.JSONFromString(`{"type":"%v"}`, bt.Name)},
+		{Type: "NoOp"},
+	}
+	assert.Nil(t, app.Store.Save(&j))
+	jr := j.NewRun()
+	jr.Status = models.StatusPending
+	jr.Result.Pending = true
+	jr.TaskRuns[0].Status = models.StatusPending
+	jr.TaskRuns[0].Result.Pending = true
+	assert.Nil(t, app.Store.Save(&jr))
+
+	url := app.Server.URL + "/v2/runs/" + jr.ID
+	body := fmt.Sprintf(`{"id":"%v","error":"unable to compute"}`, jr.ID)
+	resp := cltest.AuthenticatedPatch(url, bytes.NewBufferString(body))
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+
+	jr = cltest.WaitForJobRunToError(t, app, jr)
+	assert.Contains(t, jr.Result.Error(), "unable to compute")
+	assert.Equal(t, models.StatusErrored, jr.TaskRuns[0].Status)
+	assert.Equal(t, "", jr.TaskRuns[1].Status)
+}
+
 func TestJobRunsController_UpdateNotPending(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication()
@@ -137,6 +302,34 @@ func TestJobRunsController_UpdateNotPending(t *testing.T) {
 
 	url := app.Server.URL + "/v2/runs/" + jr.ID
 	body := fmt.Sprintf(`{"id":"%v","data":{"value": "100"}}`, jr.ID)
-	resp := cltest.BasicAuthPatch(url, "application/json", bytes.NewBufferString(body))
+	resp := cltest.AuthenticatedPatch(url, bytes.NewBufferString(body))
 	assert.Equal(t, 405, resp.StatusCode, "Response should be unsuccessful")
 }
+
+func TestJobRunsController_Logs(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	jr := cltest.CreateJobRunViaWeb(t, app, j)
+	cltest.WaitForJobRunToComplete(t, app, jr)
+
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/runs/" + jr.ID + "/logs")
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+	var body struct {
+		Logs []logger.RunLogEntry `json:"logs"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	var messages []string
+	for _, line := range body.Logs {
+		assert.Equal(t, jr.ID, line.Fields["run"])
+		messages = append(messages, line.Message)
+	}
+	assert.Contains(t, messages, "Starting job")
+	assert.Contains(t, messages, "Task noop finished")
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/runs/garbageID/logs")
+	assert.Equal(t, 404, resp.StatusCode, "Response should be not found")
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// JobSpecsController exports and imports the specs of the node's jobs as
+// a bundle, so that fleets of nodes can be provisioned from
+// version-controlled job definitions.
+type JobSpecsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index exports the specs of every job that is not archived, as JSON, or
+// as YAML if the "format" query parameter is yaml.
+// Example:
+//  "<application>/job_specs?format=yaml"
+func (jsc *JobSpecsController) Index(c *gin.Context) {
+	format := c.DefaultQuery("format", models.JobBundleJSON)
+	jobs, err := jsc.App.Store.Jobs()
+	if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	active := []models.Job{}
+	for _, j := range jobs {
+		if !j.Archived {
+			active = append(active, j)
+		}
+	}
+
+	bundle, err := models.NewJobBundle(active)
+	if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	b, err := bundle.Encode(format)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	c.Data(200, bundleContentType(format), b)
+}
+
+// Create imports the jobs in a bundle, as written by Index in the format
+// given by the "format" query parameter. Jobs whose ID the node already has
+// are skipped, so that the same bundle can be imported again. Every job is
+// validated before any are created.
+// Example:
+//  "<application>/job_specs?format=yaml"
+func (jsc *JobSpecsController) Create(c *gin.Context) {
+	format := c.DefaultQuery("format", models.JobBundleJSON)
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	bundle, err := models.DecodeJobBundle(body, format)
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	jobs, err := bundle.NewJobs()
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	store := jsc.App.Store
+	created, skipped, added := []string{}, []string{}, []models.Job{}
+	invalid := adapters.ValidationError{}
+	for i, j := range jobs {
+		if _, err := store.FindJob(j.ID); err == nil {
+			skipped = append(skipped, j.ID)
+		} else if err := adapters.Validate(j, store); err != nil {
+			invalid = append(invalid, bundleFieldErrors(i, j, err)...)
+		} else {
+			added = append(added, j)
+		}
+	}
+	if len(invalid) > 0 {
+		jobSpecProblem(c, invalid)
+		return
+	}
+
+	for _, j := range added {
+		if err := jsc.App.AddJob(j); err != nil {
+			problem(c, 500, fmt.Sprintf("%v, after creating jobs %v", err, created))
+			return
+		}
+		created = append(created, j.ID)
+	}
+	c.JSON(200, gin.H{"created": created, "skipped": skipped})
+}
+
+// bundleFieldErrors returns the problems with the job at the given index
+// of a bundle, with the fields named from the bundle's root.
+func bundleFieldErrors(i int, j models.Job, err error) adapters.ValidationError {
+	ve, ok := err.(adapters.ValidationError)
+	if !ok {
+		ve = adapters.ValidationError{{Reason: err.Error()}}
+	}
+	prefixed := adapters.ValidationError{}
+	for _, fe := range ve {
+		field := fmt.Sprintf("jobs[%d]", i)
+		if fe.Field != "" {
+			field += "." + fe.Field
+		}
+		prefixed = append(prefixed, adapters.FieldError{
+			Field:  field,
+			Reason: fmt.Sprintf("Job %v: %v", j.ID, fe.Reason),
+		})
+	}
+	return prefixed
+}
+
+func bundleContentType(format string) string {
+	if format == models.JobBundleYAML {
+		return "application/x-yaml"
+	}
+	return "application/json"
+}
+
+// previewRequest is the body of a job spec preview: the spec, and the
+// data to dry run its tasks against, if they should be.
+type previewRequest struct {
+	Spec        json.RawMessage `json:"spec"`
+	SampleInput *models.JSON    `json:"sampleInput"`
+}
+
+// Preview validates the job spec in the request body as creating it
+// would, checks the checksums of its addresses, and dry runs its side
+// effect free tasks against the sample input if one is given, returning
+// the errors, warnings, and each task's output. Nothing is saved.
+// Example:
+//  "<application>/specs/preview"
+func (jsc *JobSpecsController) Preview(c *gin.Context) {
+	var request previewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+	} else if len(request.Spec) == 0 {
+		problem(c, 400, "Must give the job spec to preview as spec")
+	} else {
+		c.JSON(200, adapters.Preview(request.Spec, request.SampleInput, jsc.App.Store))
+	}
+}
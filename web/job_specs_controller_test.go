@@ -0,0 +1,115 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobSpecsController_ExportAndImport(t *testing.T) {
+	t.Parallel()
+
+	from, cleanup := cltest.NewApplication()
+	defer cleanup()
+	to, cleanupTo := cltest.NewApplication()
+	defer cleanupTo()
+
+	j := models.NewJob()
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"initiators": [{"type": "web"}],
+		"tasks": [{"type": "HttpGet", "url": "https://example.com/price"}, {"type": "JsonParse", "path": ["last"]}]
+	}`), &j))
+	assert.Nil(t, from.Store.SaveJob(&j))
+	archived := cltest.NewJobWithWebInitiator()
+	archived.Archived = true
+	assert.Nil(t, from.Store.SaveJob(&archived))
+
+	resp := cltest.AuthenticatedGet(from.Server.URL + "/v2/job_specs?format=yaml")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Equal(t, "application/x-yaml", resp.Header.Get("Content-Type"))
+	bundle := cltest.ParseResponseBody(resp)
+	assert.Contains(t, string(bundle), j.ID)
+	assert.NotContains(t, string(bundle), archived.ID)
+
+	var imported struct {
+		Created []string `json:"created"`
+		Skipped []string `json:"skipped"`
+	}
+	resp = cltest.AuthenticatedPost(to.Server.URL+"/v2/job_specs?format=yaml", bytes.NewBuffer(bundle))
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &imported))
+	assert.Equal(t, []string{j.ID}, imported.Created)
+	assert.Equal(t, []string{}, imported.Skipped)
+
+	got, err := to.Store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, models.InitiatorWeb, got.Initiators[0].Type)
+	assert.Equal(t, 2, len(got.Tasks))
+
+	resp = cltest.AuthenticatedPost(to.Server.URL+"/v2/job_specs?format=yaml", bytes.NewBuffer(bundle))
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &imported))
+	assert.Equal(t, []string{}, imported.Created)
+	assert.Equal(t, []string{j.ID}, imported.Skipped)
+}
+
+func TestJobSpecsController_Import_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	bundle := `{"jobs": [
+		{"id": "valid", "initiators": [{"type": "web"}], "tasks": [{"type": "NoOp"}]},
+		{"id": "invalid", "initiators": [{"type": "web"}], "tasks": [{"type": "NoSuchAdapter"}]}
+	]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/job_specs", bytes.NewBufferString(bundle))
+	cltest.CheckStatusCode(t, resp, 400)
+
+	jobs, err := app.Store.Jobs()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(jobs))
+}
+
+func TestJobSpecsController_Preview(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{
+		"spec": {"initiators": [{"type": "web"}], "tasks": [{"type": "JsonParse", "path": ["last"]}, {"type": "Multiply", "times": 100}, {"type": "EthTx"}]},
+		"sampleInput": {"value": "{\"last\": \"1.5\"}"}
+	}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/specs/preview", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	var preview adapters.SpecPreview
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &preview))
+	assert.True(t, preview.Valid)
+	assert.Equal(t, 3, len(preview.Tasks))
+	assert.Equal(t, "150", preview.Tasks[1].Output.Get("value").String())
+	assert.Equal(t, adapters.PreviewNotRun, preview.Tasks[2].Status)
+
+	jobs, err := app.Store.Jobs()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(jobs))
+
+	body = `{"spec": {"initiators": [{"type": "web"}], "tasks": [{"type": "NoSuchAdapter"}]}, "sampleInput": {}}`
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/specs/preview", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	preview = adapters.SpecPreview{}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &preview))
+	assert.False(t, preview.Valid)
+	assert.Equal(t, "tasks[0]", preview.Errors[0].Field)
+	assert.Nil(t, preview.Tasks)
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/specs/preview", bytes.NewBufferString(`{}`))
+	cltest.CheckStatusCode(t, resp, 400)
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/specs/other", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 404)
+}
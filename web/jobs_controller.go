@@ -1,9 +1,13 @@
 package web
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/asdine/storm"
 	"github.com/gin-gonic/gin"
 	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/services"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/store/presenters"
@@ -14,24 +18,49 @@ type JobsController struct {
 	App *services.ChainlinkApplication
 }
 
-// Index adds the root of the Jobs to the given context.
+// Index adds the root of the Jobs to the given context, oldest first. The
+// "offset" and "limit" query parameters page through the jobs, "sort" set to
+// "-createdAt" lists the newest first, and "initiator" only lists jobs with
+// an initiator of that type. The total number of matching jobs is returned
+// in the X-Total-Count header.
 // Example:
-//  "<application>/jobs"
+//  "<application>/jobs?initiator=runlog&sort=-createdAt&offset=20&limit=10"
 func (jrc *JobsController) Index(c *gin.Context) {
-	var jobs []models.Job
-	if err := jrc.App.Store.AllByIndex("CreatedAt", &jobs); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+	offset, limit, err := pagingParams(c)
+	sort := c.DefaultQuery("sort", "createdAt")
+	if err == nil && sort != "createdAt" && sort != "-createdAt" {
+		err = fmt.Errorf("Cannot sort jobs by %v", sort)
+	}
+	if err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	jobs, total, err := jrc.App.Store.JobsPage(offset, limit, sort == "-createdAt", c.Query("initiator"))
+	if err != nil {
+		problem(c, 500, err.Error())
 	} else {
 		pjs := make([]presenters.Job, len(jobs))
 		for i, j := range jobs {
 			pjs[i] = presenters.Job{Job: j}
 		}
+		c.Header("X-Total-Count", strconv.Itoa(total))
 		c.JSON(200, pjs)
 	}
 }
 
+func pagingParams(c *gin.Context) (int, int, error) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("Invalid offset %v", c.Query("offset"))
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		return 0, 0, fmt.Errorf("Invalid limit %v", c.Query("limit"))
+	}
+	return offset, limit, nil
+}
+
 // Create adds the Jobs to the given context.
 // Example:
 //  "<application>/jobs"
@@ -39,17 +68,14 @@ func (jc *JobsController) Create(c *gin.Context) {
 	j := models.NewJob()
 
 	if err := c.ShouldBindJSON(&j); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 400, err.Error())
 	} else if err = adapters.Validate(j, jc.App.Store); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		jobSpecProblem(c, err)
 	} else if err = jc.App.AddJob(j); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
+	} else if warnings := append(adapters.CheckDataPaths(j), adapters.CheckUnknownParams(j)...); len(warnings) > 0 {
+		logger.Web.Warnw("Job created with suspicious data paths or params", "job", j.ID, "warnings", warnings)
+		c.JSON(200, gin.H{"id": j.ID, "warnings": warnings})
 	} else {
 		c.JSON(200, gin.H{"id": j.ID})
 	}
@@ -61,18 +87,107 @@ func (jc *JobsController) Create(c *gin.Context) {
 func (jc *JobsController) Show(c *gin.Context) {
 	id := c.Param("JobID")
 	if j, err := jc.App.Store.FindJob(id); err == storm.ErrNotFound {
-		c.JSON(404, gin.H{
-			"errors": []string{"Job not found."},
-		})
+		problem(c, 404, "Job not found.")
 	} else if err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else if runs, err := jc.App.Store.JobRunsFor(j.ID); err != nil {
-		c.JSON(500, gin.H{
-			"errors": []string{err.Error()},
-		})
+		problem(c, 500, err.Error())
 	} else {
 		c.JSON(200, presenters.Job{j, runs})
 	}
 }
+
+// Diff compares the job spec in the request body with the Job, returning
+// the initiators added and removed, the task changes, and which log
+// subscriptions would be restarted, so they can be reviewed before the
+// job is replaced. Nothing is saved.
+// Example:
+//  "<application>/jobs/:JobID/diff"
+func (jc *JobsController) Diff(c *gin.Context) {
+	id := c.Param("JobID")
+	proposed := models.NewJob()
+	if j, err := jc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found.")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if err := c.ShouldBindJSON(&proposed); err != nil {
+		problem(c, 400, err.Error())
+	} else if err = adapters.Validate(proposed, jc.App.Store); err != nil {
+		jobSpecProblem(c, err)
+	} else {
+		c.JSON(200, j.Diff(proposed))
+	}
+}
+
+// Destroy archives the Job, so that it no longer runs or listens for logs.
+// Its runs are kept, and it can be restored with Unarchive.
+// Example:
+//  "<application>/jobs/:JobID"
+func (jc *JobsController) Destroy(c *gin.Context) {
+	id := c.Param("JobID")
+	if j, err := jc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found.")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if err := jc.App.ArchiveJob(j); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": j.ID})
+	}
+}
+
+// Unarchive restores an archived Job, scheduling it and listening for its
+// logs again.
+// Example:
+//  "<application>/jobs/:JobID/unarchive"
+func (jc *JobsController) Unarchive(c *gin.Context) {
+	id := c.Param("JobID")
+	if j, err := jc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found.")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if !j.Archived {
+		problem(c, 409, "Cannot unarchive a job that isn't archived")
+	} else if err := jc.App.UnarchiveJob(j); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": j.ID})
+	}
+}
+
+// Pause stops the Job from starting new runs. Runs its initiators would
+// have started are recorded as skipped instead.
+// Example:
+//  "<application>/jobs/:JobID/pause"
+func (jc *JobsController) Pause(c *gin.Context) {
+	id := c.Param("JobID")
+	if j, err := jc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found.")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if err := jc.App.PauseJob(j); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": j.ID})
+	}
+}
+
+// Resume lets a paused Job start new runs again. With the "replay" query
+// parameter set to true, the runs skipped for log events while it was
+// paused are started.
+// Example:
+//  "<application>/jobs/:JobID/resume?replay=true"
+func (jc *JobsController) Resume(c *gin.Context) {
+	id := c.Param("JobID")
+	if j, err := jc.App.Store.FindJob(id); err == storm.ErrNotFound {
+		problem(c, 404, "Job not found.")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else if !j.Paused {
+		problem(c, 409, "Cannot resume a job that isn't paused")
+	} else if err := jc.App.ResumeJob(j, c.Query("replay") == "true"); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"id": j.ID})
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/onsi/gomega"
 	"github.com/smartcontractkit/chainlink/adapters"
 	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/services"
 	"github.com/smartcontractkit/chainlink/store/models"
 	"github.com/smartcontractkit/chainlink/store/presenters"
+	"github.com/smartcontractkit/chainlink/web"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,7 +29,7 @@ func TestJobsController_Index(t *testing.T) {
 	j2.Initiators[0].Ran = true
 	app.Store.SaveJob(&j2)
 
-	resp := cltest.BasicAuthGet(app.Server.URL + "/v2/jobs")
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/jobs")
 	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
 
 	var jobs []models.Job
@@ -36,6 +39,61 @@ func TestJobsController_Index(t *testing.T) {
 	assert.NotEqual(t, true, jobs[1].Initiators[0].Ran, "should ignore fields for other initiators")
 }
 
+func TestJobsController_Index_Paginated(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j1 := cltest.NewJobWithSchedule("9 9 9 9 6")
+	j1.CreatedAt = models.Time{time.Now().AddDate(0, 0, -2)}
+	assert.Nil(t, app.Store.SaveJob(&j1))
+	j2 := cltest.NewJobWithWebInitiator()
+	j2.CreatedAt = models.Time{time.Now().AddDate(0, 0, -1)}
+	assert.Nil(t, app.Store.SaveJob(&j2))
+	j3 := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j3))
+
+	tests := []struct {
+		name      string
+		query     string
+		wantIDs   []string
+		wantTotal string
+	}{
+		{"all", "", []string{j1.ID, j2.ID, j3.ID}, "3"},
+		{"paged", "?offset=1&limit=1", []string{j2.ID}, "3"},
+		{"newest first", "?sort=-createdAt&limit=2", []string{j3.ID, j2.ID}, "3"},
+		{"by initiator", "?initiator=web", []string{j2.ID, j3.ID}, "2"},
+		{"past the end", "?offset=5", []string{}, "3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/jobs" + test.query)
+			assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+			assert.Equal(t, test.wantTotal, resp.Header.Get("X-Total-Count"))
+
+			var jobs []models.Job
+			json.Unmarshal(cltest.ParseResponseBody(resp), &jobs)
+			ids := []string{}
+			for _, j := range jobs {
+				ids = append(ids, j.ID)
+			}
+			assert.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestJobsController_Index_InvalidParams(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	for _, query := range []string{"?offset=-1", "?limit=ten", "?sort=id"} {
+		resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/jobs" + query)
+		assert.Equal(t, 400, resp.StatusCode, query)
+	}
+}
+
 func TestJobsController_Create(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication()
@@ -93,16 +151,23 @@ func TestJobsController_Create_InvalidJob(t *testing.T) {
 	defer cleanup()
 
 	jsonStr := cltest.LoadJSON("../internal/fixtures/web/invalid_job.json")
-	resp := cltest.BasicAuthPost(
+	resp := cltest.AuthenticatedPost(
 		app.Server.URL+"/v2/jobs",
-		"application/json",
 		bytes.NewBuffer(jsonStr),
 	)
 
-	assert.Equal(t, 500, resp.StatusCode, "Response should be internal error")
-
-	expected := `{"errors":["idonotexist is not a supported adapter type"]}`
-	assert.Equal(t, expected, string(cltest.ParseResponseBody(resp)))
+	assert.Equal(t, 400, resp.StatusCode, "Response should be a bad request")
+	assert.Equal(t, web.ProblemContentType, resp.Header.Get("Content-Type"))
+
+	var p web.Problem
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &p))
+	assert.Equal(t, web.CodeInvalidJobSpec, p.Code)
+	assert.Equal(t, web.ProblemDocsURL+"#invalid_job_spec", p.Type)
+	assert.Equal(t, "/v2/jobs", p.Instance)
+	assert.Equal(t, "idonotexist is not a supported adapter type", p.Detail)
+	assert.Equal(t, []web.InvalidParam{
+		{Name: "tasks[0]", Reason: "idonotexist is not a supported adapter type"},
+	}, p.InvalidParams)
 }
 
 func TestJobsController_Create_InvalidCron(t *testing.T) {
@@ -111,16 +176,17 @@ func TestJobsController_Create_InvalidCron(t *testing.T) {
 	defer cleanup()
 
 	jsonStr := cltest.LoadJSON("../internal/fixtures/web/invalid_cron.json")
-	resp := cltest.BasicAuthPost(
+	resp := cltest.AuthenticatedPost(
 		app.Server.URL+"/v2/jobs",
-		"application/json",
 		bytes.NewBuffer(jsonStr),
 	)
 
-	assert.Equal(t, 500, resp.StatusCode, "Response should be internal error")
+	assert.Equal(t, 400, resp.StatusCode, "Response should be a bad request")
 
-	expected := `{"errors":["Cron: Failed to parse int from !: strconv.Atoi: parsing \"!\": invalid syntax"]}`
-	assert.Equal(t, expected, string(cltest.ParseResponseBody(resp)))
+	var p web.Problem
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &p))
+	assert.Equal(t, "invalid_request", p.Code)
+	assert.Equal(t, "Cron: Failed to parse int from !: strconv.Atoi: parsing \"!\": invalid syntax", p.Detail)
 }
 
 func TestJobsController_Show(t *testing.T) {
@@ -139,7 +205,7 @@ func TestJobsController_Show(t *testing.T) {
 	jr2.CreatedAt = jr1.CreatedAt.Add(time.Second)
 	assert.Nil(t, app.Store.Save(&jr2))
 
-	resp := cltest.BasicAuthGet(app.Server.URL + "/v2/jobs/" + j.ID)
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/jobs/" + j.ID)
 	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
 
 	var respJob presenters.Job
@@ -154,7 +220,7 @@ func TestJobsController_Show_NotFound(t *testing.T) {
 	app, cleanup := cltest.NewApplication()
 	defer cleanup()
 
-	resp := cltest.BasicAuthGet(app.Server.URL + "/v2/jobs/" + "garbage")
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/jobs/" + "garbage")
 	assert.Equal(t, 404, resp.StatusCode, "Response should be not found")
 }
 
@@ -167,3 +233,174 @@ func TestJobsController_Show_Unauthenticated(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 401, resp.StatusCode, "Response should be forbidden")
 }
+
+func TestJobsController_Create_DataPathWarnings(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"initiators":[{"type":"web"}],"tasks":[{"type":"noop"},{"type":"multiply","times":100}]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+
+	var created struct {
+		ID       string   `json:"id"`
+		Warnings []string `json:"warnings"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, 1, len(created.Warnings))
+}
+
+func TestJobsController_Create_SchemaVersion(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"schemaVersion":1,"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com","cacheTtl":"5s"}]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 400)
+
+	var p web.Problem
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &p))
+	assert.Equal(t, web.CodeInvalidJobSpec, p.Code)
+	assert.Equal(t, []web.InvalidParam{
+		{Name: "tasks[0].cacheTtl", Reason: "tasks[0] (httpget) has no param cacheTtl, did you mean cacheTTL?"},
+	}, p.InvalidParams)
+
+	body = `{"initiators":[{"type":"web"}],"tasks":[{"type":"httpget","url":"https://example.com","cacheTtl":"5s"}]}`
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	var created struct {
+		Warnings []string `json:"warnings"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &created))
+	assert.Equal(t, []string{"tasks[0] (httpget) has no param cacheTtl, did you mean cacheTTL?"}, created.Warnings)
+}
+
+func TestJobsController_Destroy(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	jr := j.NewRun()
+	assert.Nil(t, app.Store.Save(&jr))
+
+	resp := cltest.AuthenticatedDelete(app.Server.URL + "/v2/jobs/" + j.ID)
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+
+	j, err := app.Store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.True(t, j.Archived)
+	runs, err := app.Store.JobRunsFor(j.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(runs), "should keep the job's runs")
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/runs", &bytes.Buffer{})
+	assert.Equal(t, 500, resp.StatusCode, "should not run archived jobs")
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/unarchive", &bytes.Buffer{})
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+	j, err = app.Store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.False(t, j.Archived)
+	cltest.CreateJobRunViaWeb(t, app, j)
+}
+
+func TestJobsController_Destroy_NotFound(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedDelete(app.Server.URL + "/v2/jobs/" + "garbage")
+	assert.Equal(t, 404, resp.StatusCode, "Response should be not found")
+}
+
+func TestJobsController_Unarchive_NotArchived(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/unarchive", &bytes.Buffer{})
+	assert.Equal(t, 409, resp.StatusCode)
+}
+
+func TestJobsController_Diff(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	spec := `{"initiators":[{"type":"web"},{"type":"runlog","address":"0x3cCad4715152693fE3BC4460591e3D3Fbd071b42"}],"tasks":[{"type":"NoOp"}]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/diff", bytes.NewBufferString(spec))
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+
+	var diff models.JobDiff
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &diff))
+	assert.Equal(t, 1, len(diff.InitiatorsAdded))
+	assert.Equal(t, models.InitiatorRunLog, diff.InitiatorsAdded[0].Type)
+	assert.Equal(t, 1, len(diff.SubscriptionsToRestart))
+	assert.Empty(t, diff.InitiatorsRemoved)
+	assert.Empty(t, diff.Tasks)
+
+	unchanged, err := app.Store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(unchanged.Initiators), "should not save the proposed job")
+}
+
+func TestJobsController_Diff_NotFound(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	spec := `{"initiators":[{"type":"web"}],"tasks":[{"type":"NoOp"}]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/garbage/diff", bytes.NewBufferString(spec))
+	assert.Equal(t, 404, resp.StatusCode, "Response should be not found")
+}
+
+func TestJobsController_PauseAndResume(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithLogInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/pause", &bytes.Buffer{})
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+	j, err := app.Store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.True(t, j.Paused)
+
+	input := models.RunResult{Data: cltest.JSONFromString(`{"value":"100"}`)}
+	_, err = services.TriggerRun(j, models.InitiatorEthLog, app.Store, input)
+	assert.Nil(t, err)
+	runs, err := app.Store.JobRunsFor(j.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, runs)
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/resume?replay=true", &bytes.Buffer{})
+	assert.Equal(t, 200, resp.StatusCode, "Response should be successful")
+	j, err = app.Store.FindJob(j.ID)
+	assert.Nil(t, err)
+	assert.False(t, j.Paused)
+
+	gomega.NewGomegaWithT(t).Eventually(func() []models.JobRun {
+		runs, err := app.Store.JobRunsFor(j.ID)
+		assert.Nil(t, err)
+		return runs
+	}).Should(gomega.HaveLen(1))
+	skipped, err := app.Store.SkippedRunsFor(j.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, skipped, "should mark skipped runs resumed")
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs/"+j.ID+"/resume", &bytes.Buffer{})
+	assert.Equal(t, 409, resp.StatusCode, "should not resume a job that isn't paused")
+}
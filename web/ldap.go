@@ -0,0 +1,368 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/smartcontractkit/chainlink/store"
+)
+
+// The parts of LDAP's wire protocol, RFC 4511, used to check a password
+// and read an entry's groups.
+const (
+	ldapBindRequest       = 0
+	ldapBindResponse      = 1
+	ldapSearchRequest     = 3
+	ldapSearchResultEntry = 4
+	ldapSearchResultDone  = 5
+	ldapFilterPresent     = 7
+	ldapSuccess           = 0
+	ldapTimeout           = 10 * time.Second
+	ldapMaxMessageSize    = 1 << 20
+)
+
+// LDAPAuthenticator checks passwords by binding to the LDAP server at
+// LDAP_URL as the operator's entry, whose DN is LDAP_USER_DN with "%s"
+// replaced by the name they log in with, and then reads their groups from
+// the entry's LDAP_GROUP_ATTRIBUTE, memberOf by default. Passwords are
+// not sent to ldap:// servers, in the clear, unless AllowInsecure is set.
+type LDAPAuthenticator struct {
+	URL            string
+	UserDN         string
+	GroupAttribute string
+	AllowInsecure  bool
+}
+
+// NewLDAPAuthenticator returns an LDAPAuthenticator for the LDAP server
+// in the config.
+func NewLDAPAuthenticator(config store.Config) *LDAPAuthenticator {
+	attribute := config.LDAPGroupAttribute
+	if attribute == "" {
+		attribute = "memberOf"
+	}
+	return &LDAPAuthenticator{
+		URL:            config.LDAPURL,
+		UserDN:         config.LDAPUserDN,
+		GroupAttribute: attribute,
+		AllowInsecure:  config.LDAPAllowInsecure,
+	}
+}
+
+// Authenticate binds as the named entry with the password, and returns
+// the entry's groups.
+func (la *LDAPAuthenticator) Authenticate(name, password string) ([]string, error) {
+	// An empty password is an unauthenticated bind, which servers allow
+	// without checking anything.
+	if name == "" || password == "" {
+		return nil, errors.New("LDAP: must supply a name and a password")
+	}
+	if !strings.Contains(la.UserDN, "%s") {
+		return nil, errors.New("LDAP: LDAP_USER_DN must contain %s")
+	}
+	dn := strings.Replace(la.UserDN, "%s", escapeDN(name), -1)
+
+	conn, err := la.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ldapTimeout))
+	r := bufio.NewReader(conn)
+
+	bind := berApplication(ldapBindRequest,
+		berMarshal(3),
+		berMarshal([]byte(dn)),
+		berRaw(asn1.ClassContextSpecific, 0, false, []byte(password)),
+	)
+	if _, err := conn.Write(ldapMessage(1, bind)); err != nil {
+		return nil, err
+	}
+	op, err := readLDAPResponse(r, 1)
+	if err != nil {
+		return nil, err
+	}
+	if op.Tag != ldapBindResponse {
+		return nil, fmt.Errorf("LDAP: unexpected response %v to bind", op.Tag)
+	}
+	if err := ldapResult(op.Content); err != nil {
+		return nil, err
+	}
+
+	search := berApplication(ldapSearchRequest,
+		berMarshal([]byte(dn)),
+		berMarshal(asn1.Enumerated(0)), // baseObject
+		berMarshal(asn1.Enumerated(0)), // neverDerefAliases
+		berMarshal(0),
+		berMarshal(int(ldapTimeout.Seconds())),
+		berMarshal(false),
+		berRaw(asn1.ClassContextSpecific, ldapFilterPresent, false, []byte("objectClass")),
+		berRaw(asn1.ClassUniversal, asn1.TagSequence, true, berMarshal([]byte(la.GroupAttribute))),
+	)
+	if _, err := conn.Write(ldapMessage(2, search)); err != nil {
+		return nil, err
+	}
+	groups := []string{}
+	for {
+		op, err := readLDAPResponse(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Tag {
+		case ldapSearchResultEntry:
+			values, err := ldapAttributeValues(op.Content, la.GroupAttribute)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, values...)
+		case ldapSearchResultDone:
+			return groups, ldapResult(op.Content)
+		}
+	}
+}
+
+func (la *LDAPAuthenticator) dial() (net.Conn, error) {
+	u, err := url.Parse(la.URL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	dialer := &net.Dialer{Timeout: ldapTimeout}
+	switch u.Scheme {
+	case "ldap":
+		if !la.AllowInsecure {
+			return nil, errors.New("LDAP: refusing to send passwords to an ldap:// server unencrypted, use ldaps:// or set LDAP_ALLOW_INSECURE")
+		}
+		if u.Port() == "" {
+			host = net.JoinHostPort(host, "389")
+		}
+		return dialer.Dial("tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(host, "636")
+		}
+		return tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, fmt.Errorf("LDAP: LDAP_URL must be ldap:// or ldaps://, not %v", la.URL)
+	}
+}
+
+// escapeDN escapes the characters that are special in a DN's values, as
+// described in RFC 4514, so that a name cannot change which entry is bound.
+func escapeDN(value string) string {
+	var b bytes.Buffer
+	for i, c := range value {
+		switch {
+		case strings.ContainsRune(`,+"\<>;=`, c),
+			i == 0 && (c == ' ' || c == '#'),
+			i+utf8.RuneLen(c) == len(value) && c == ' ':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		case c == 0:
+			b.WriteString(`\00`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// Requests are DER encoded, which is a form of BER that servers accept.
+func berMarshal(v interface{}) []byte {
+	b, err := asn1.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func berRaw(class, tag int, compound bool, content []byte) []byte {
+	return berMarshal(asn1.RawValue{Class: class, Tag: tag, IsCompound: compound, Bytes: content})
+}
+
+func berApplication(tag int, parts ...[]byte) []byte {
+	return berRaw(asn1.ClassApplication, tag, true, bytes.Join(parts, nil))
+}
+
+func ldapMessage(id int, op []byte) []byte {
+	return berRaw(asn1.ClassUniversal, asn1.TagSequence, true, append(berMarshal(id), op...))
+}
+
+// berElement is a BER encoded element of a response. Servers send BER
+// that is not DER, such as lengths longer than they need to be, which
+// encoding/asn1 rejects, so responses are parsed here.
+type berElement struct {
+	Class    int
+	Tag      int
+	Compound bool
+	Content  []byte
+}
+
+// parseBER returns the first element of b, and what follows it.
+func parseBER(b []byte) (berElement, []byte, error) {
+	if len(b) < 2 {
+		return berElement{}, nil, errors.New("LDAP: truncated message")
+	}
+	el := berElement{
+		Class:    int(b[0] >> 6),
+		Compound: b[0]&0x20 != 0,
+		Tag:      int(b[0] & 0x1f),
+	}
+	if el.Tag == 0x1f {
+		return berElement{}, nil, errors.New("LDAP: unsupported tag")
+	}
+	length, offset := int(b[1]), 2
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 || len(b) < 2+n {
+			return berElement{}, nil, errors.New("LDAP: unsupported length")
+		}
+		length = 0
+		for _, c := range b[2 : 2+n] {
+			length = length<<8 | int(c)
+		}
+		offset += n
+	}
+	if length < 0 || len(b)-offset < length {
+		return berElement{}, nil, errors.New("LDAP: truncated message")
+	}
+	el.Content = b[offset : offset+length]
+	return el, b[offset+length:], nil
+}
+
+// berInt returns the value of an INTEGER or ENUMERATED element.
+func berInt(el berElement) int {
+	if len(el.Content) == 0 {
+		return 0
+	}
+	n := int(int8(el.Content[0]))
+	for _, c := range el.Content[1:] {
+		n = n<<8 | int(c)
+	}
+	return n
+}
+
+// readBER reads one BER encoded element from the connection.
+func readBER(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := int(header[1])
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 {
+			return nil, errors.New("LDAP: unsupported length")
+		}
+		lengthBytes := make([]byte, n)
+		if _, err := io.ReadFull(r, lengthBytes); err != nil {
+			return nil, err
+		}
+		header = append(header, lengthBytes...)
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length < 0 || length > ldapMaxMessageSize {
+		return nil, errors.New("LDAP: message too large")
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return append(header, content...), nil
+}
+
+// readLDAPResponse reads messages until one for the given request, and
+// returns its protocol operation.
+func readLDAPResponse(r *bufio.Reader, id int) (berElement, error) {
+	for {
+		msg, err := readBER(r)
+		if err != nil {
+			return berElement{}, err
+		}
+		envelope, _, err := parseBER(msg)
+		if err != nil {
+			return berElement{}, err
+		}
+		msgID, rest, err := parseBER(envelope.Content)
+		if err != nil {
+			return berElement{}, err
+		}
+		op, _, err := parseBER(rest)
+		if err != nil {
+			return berElement{}, err
+		}
+		if berInt(msgID) == id && op.Class == asn1.ClassApplication {
+			return op, nil
+		}
+	}
+}
+
+// ldapResult returns an error unless the LDAPResult in the response's
+// content is success.
+func ldapResult(content []byte) error {
+	code, rest, err := parseBER(content)
+	if err != nil {
+		return err
+	}
+	if berInt(code) == ldapSuccess {
+		return nil
+	}
+	diagnostic := []byte{}
+	if _, rest, err = parseBER(rest); err == nil {
+		if el, _, err := parseBER(rest); err == nil {
+			diagnostic = el.Content
+		}
+	}
+	return fmt.Errorf("LDAP: result %v %s", berInt(code), diagnostic)
+}
+
+// ldapAttributeValues returns the values of the named attribute in the
+// content of a search result entry.
+func ldapAttributeValues(content []byte, name string) ([]string, error) {
+	_, rest, err := parseBER(content)
+	if err != nil {
+		return nil, err
+	}
+	attributes, _, err := parseBER(rest)
+	if err != nil {
+		return nil, err
+	}
+	values := []string{}
+	for rest := attributes.Content; len(rest) > 0; {
+		var attribute berElement
+		if attribute, rest, err = parseBER(rest); err != nil {
+			return nil, err
+		}
+		attrType, set, err := parseBER(attribute.Content)
+		if err != nil {
+			return nil, err
+		}
+		vals, _, err := parseBER(set)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(string(attrType.Content), name) {
+			continue
+		}
+		for v := vals.Content; len(v) > 0; {
+			var value berElement
+			if value, v, err = parseBER(v); err != nil {
+				return nil, err
+			}
+			values = append(values, string(value.Content))
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,189 @@
+package web_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/asn1"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/web"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLDAP answers binds for one entry and its password, and searches of
+// that entry with its memberOf groups.
+type fakeLDAP struct {
+	listener net.Listener
+	dn       string
+	password string
+	groups   []string
+}
+
+func newFakeLDAP(t *testing.T, dn, password string, groups ...string) *fakeLDAP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	fl := &fakeLDAP{listener: listener, dn: dn, password: password, groups: groups}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go fl.serve(conn)
+		}
+	}()
+	return fl
+}
+
+func (fl *fakeLDAP) URL() string {
+	return "ldap://" + fl.listener.Addr().String()
+}
+
+func ber(class, tag int, compound bool, parts ...[]byte) []byte {
+	b, _ := asn1.Marshal(asn1.RawValue{Class: class, Tag: tag, IsCompound: compound, Bytes: bytes.Join(parts, nil)})
+	return b
+}
+
+func berValue(v interface{}) []byte {
+	b, _ := asn1.Marshal(v)
+	return b
+}
+
+func ldapResponse(id, tag int, parts ...[]byte) []byte {
+	return ber(asn1.ClassUniversal, asn1.TagSequence, true, berValue(id), ber(asn1.ClassApplication, tag, true, parts...))
+}
+
+func ldapResultCode(code int) []byte {
+	return bytes.Join([][]byte{berValue(asn1.Enumerated(code)), berValue([]byte{}), berValue([]byte{})}, nil)
+}
+
+func (fl *fakeLDAP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	bound := false
+	for {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return
+		}
+		length := int(header[1])
+		if length&0x80 != 0 {
+			lengthBytes := make([]byte, length&0x7f)
+			io.ReadFull(r, lengthBytes)
+			length = 0
+			for _, b := range lengthBytes {
+				length = length<<8 | int(b)
+			}
+		}
+		content := make([]byte, length)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return
+		}
+		var id int
+		rest, _ := asn1.Unmarshal(content, &id)
+		var op asn1.RawValue
+		asn1.Unmarshal(rest, &op)
+
+		switch op.Tag {
+		case 0: // bind
+			var version int
+			var dn []byte
+			var password asn1.RawValue
+			rest, _ := asn1.Unmarshal(op.Bytes, &version)
+			rest, _ = asn1.Unmarshal(rest, &dn)
+			asn1.Unmarshal(rest, &password)
+			code := 49 // invalidCredentials
+			if string(dn) == fl.dn && string(password.Bytes) == fl.password {
+				code = 0
+				bound = true
+			}
+			conn.Write(ldapResponse(id, 1, ldapResultCode(code)))
+		case 3: // search
+			var base []byte
+			asn1.Unmarshal(op.Bytes, &base)
+			if bound && string(base) == fl.dn {
+				values := [][]byte{}
+				for _, group := range fl.groups {
+					values = append(values, berValue([]byte(group)))
+				}
+				attribute := ber(asn1.ClassUniversal, asn1.TagSequence, true,
+					berValue([]byte("memberOf")),
+					ber(asn1.ClassUniversal, asn1.TagSet, true, values...),
+				)
+				attributes := ber(asn1.ClassUniversal, asn1.TagSequence, true, attribute)
+				conn.Write(ldapResponse(id, 4, berValue(base), attributes))
+			}
+			conn.Write(ldapResponse(id, 5, ldapResultCode(0)))
+		}
+	}
+}
+
+func TestLDAPAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	groups := []string{"cn=oracle-ops,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"}
+	fl := newFakeLDAP(t, `uid=j\,doe,ou=people,dc=example,dc=com`, "secret", groups...)
+	defer fl.listener.Close()
+
+	la := &web.LDAPAuthenticator{
+		URL:            fl.URL(),
+		UserDN:         "uid=%s,ou=people,dc=example,dc=com",
+		GroupAttribute: "memberOf",
+	}
+	_, err := la.Authenticate("j,doe", "secret")
+	assert.NotNil(t, err, "ldap:// is refused unless insecure is allowed")
+
+	la.AllowInsecure = true
+	got, err := la.Authenticate("j,doe", "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, groups, got)
+
+	_, err = la.Authenticate("j,doe", "wrong")
+	assert.NotNil(t, err)
+	_, err = la.Authenticate("j,doe", "")
+	assert.NotNil(t, err, "empty passwords are unauthenticated binds")
+}
+
+func TestSessionsController_Create_LDAP(t *testing.T) {
+	t.Parallel()
+
+	fl := newFakeLDAP(t, "uid=jdoe,ou=people,dc=example,dc=com", "secret", "cn=oracle-ops,ou=groups,dc=example,dc=com")
+	defer fl.listener.Close()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	app.Store.Config.LDAPURL = fl.URL()
+	app.Store.Config.LDAPAllowInsecure = true
+	app.Store.Config.LDAPUserDN = "uid=%s,ou=people,dc=example,dc=com"
+	app.Store.Config.AuthGroupRoles = "oracle-ops=edit"
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     int
+	}{
+		{"ldap user", "jdoe", "secret", 200},
+		{"wrong password", "jdoe", "wrong", 401},
+		{"local user", cltest.Username, cltest.Password, 200},
+		{"local user not checked against ldap", cltest.Username, "secret", 401},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := `{"email":"` + test.email + `","password":"` + test.password + `"}`
+			resp, err := http.Post(app.Server.URL+"/sessions", "application/json", bytes.NewBufferString(body))
+			assert.Nil(t, err)
+			cltest.CheckStatusCode(t, resp, test.want)
+		})
+	}
+
+	user, err := app.Store.FindUser("jdoe")
+	assert.Nil(t, err)
+	assert.Equal(t, web.ProviderLDAP, user.Provider)
+	assert.Equal(t, models.RoleEdit, user.Role)
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/services"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogController shows and changes the log level of the node, and of each
+// of its subsystems.
+type LogController struct {
+	App *services.ChainlinkApplication
+}
+
+// logLevels are the node's log level, and each subsystem's, where an empty
+// level is the node's.
+type logLevels struct {
+	Level      string            `json:"level"`
+	Subsystems map[string]string `json:"subsystems"`
+}
+
+// Show returns the node's log level, and the level each subsystem logs at
+// if it is set apart from the node's.
+// Example:
+//  "<application>/log"
+func (lc *LogController) Show(c *gin.Context) {
+	c.JSON(200, lc.levels())
+}
+
+// Update changes the node's log level, as the LOG_LEVEL setting, and the
+// levels of the given subsystems. A subsystem given an empty level logs at
+// the node's level again. Nothing is changed if any level or subsystem is
+// invalid, and subsystem levels last until the node is restarted.
+// Example:
+//  "<application>/log"
+func (lc *LogController) Update(c *gin.Context) {
+	var request logLevels
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	if request.Level != "" {
+		check := lc.App.Store.CurrentConfig()
+		if _, err := check.Change("LOG_LEVEL", request.Level); err != nil {
+			problem(c, 400, err.Error())
+			return
+		}
+	}
+	levels := map[logger.Subsystem]*zapcore.Level{}
+	for name, value := range request.Subsystems {
+		s, err := logger.ParseSubsystem(name)
+		if err != nil {
+			problem(c, 400, err.Error())
+			return
+		}
+		if value == "" {
+			levels[s] = nil
+			continue
+		}
+		var lvl zapcore.Level
+		if err := lvl.UnmarshalText([]byte(value)); err != nil {
+			problem(c, 400, err.Error())
+			return
+		}
+		levels[s] = &lvl
+	}
+
+	if request.Level != "" {
+		if _, err := lc.App.Store.ChangeConfig("LOG_LEVEL", request.Level, identity(c)); err != nil {
+			problem(c, 500, err.Error())
+			return
+		}
+	}
+	for s, lvl := range levels {
+		logger.SetSubsystemLevel(s, lvl)
+		logger.Infow("Changed subsystem log level", "subsystem", s, "level", request.Subsystems[string(s)], "changedBy", identity(c))
+	}
+	c.JSON(200, lc.levels())
+}
+
+func (lc *LogController) levels() logLevels {
+	own := logger.SubsystemLevels()
+	subsystems := map[string]string{}
+	for _, s := range logger.Subsystems {
+		subsystems[string(s)] = ""
+		if lvl, ok := own[s]; ok {
+			subsystems[string(s)] = lvl.String()
+		}
+	}
+	return logLevels{
+		Level:      lc.App.Store.CurrentConfig().LogLevel.String(),
+		Subsystems: subsystems,
+	}
+}
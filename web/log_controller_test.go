@@ -0,0 +1,83 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+type logLevels struct {
+	Level      string            `json:"level"`
+	Subsystems map[string]string `json:"subsystems"`
+}
+
+func TestLogController_Update(t *testing.T) {
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	observed := cltest.ObserveLogs()
+	defer logger.SetLevel(zapcore.DebugLevel)
+	defer logger.SetSubsystemLevel(logger.Web, nil)
+
+	body := `{"level":"info","subsystems":{"web":"debug"}}`
+	resp := cltest.AuthenticatedPatch(app.Server.URL+"/v2/log", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	var levels logLevels
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &levels))
+	assert.Equal(t, "info", levels.Level)
+	assert.Equal(t, map[string]string{
+		"scheduler":    "",
+		"subscription": "",
+		"txmanager":    "",
+		"web":          "debug",
+	}, levels.Subsystems)
+	assert.Equal(t, "info", app.Store.CurrentConfig().LogLevel.String())
+
+	logger.Debugw("node debug")
+	logger.TxManager.Debugw("txmanager debug")
+	logger.Web.Debugw("web debug")
+	logger.TxManager.Infow("txmanager info")
+	messages := []string{}
+	for _, entry := range observed.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Contains(t, messages, "web debug")
+	assert.Contains(t, messages, "txmanager info")
+	assert.NotContains(t, messages, "node debug")
+	assert.NotContains(t, messages, "txmanager debug")
+
+	resp = cltest.AuthenticatedPatch(app.Server.URL+"/v2/log", bytes.NewBufferString(`{"subsystems":{"web":""}}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/log")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &levels))
+	assert.Equal(t, "", levels.Subsystems["web"])
+}
+
+func TestLogController_Update_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown subsystem", `{"subsystems":{"web":"debug","eth":"debug"}}`},
+		{"invalid subsystem level", `{"subsystems":{"web":"loud"}}`},
+		{"invalid level", `{"level":"loud","subsystems":{"web":"debug"}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedPatch(app.Server.URL+"/v2/log", bytes.NewBufferString(test.body))
+			cltest.CheckStatusCode(t, resp, 400)
+			assert.Empty(t, logger.SubsystemLevels())
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"github.com/asdine/storm"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// ManifestController registers the state the node is meant to be in, and
+// checks its actual state against it.
+type ManifestController struct {
+	App *services.ChainlinkApplication
+}
+
+// Show returns the node's manifest.
+// Example:
+//  "<application>/manifest"
+func (mc *ManifestController) Show(c *gin.Context) {
+	if manifest, err := mc.App.Store.Manifest(); err == storm.ErrNotFound {
+		problem(c, 404, "No manifest has been registered")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, manifest)
+	}
+}
+
+// Update registers the given manifest in place of the node's last one,
+// and checks the node against it.
+// Example:
+//  "<application>/manifest"
+func (mc *ManifestController) Update(c *gin.Context) {
+	var manifest models.Manifest
+	if err := c.ShouldBindJSON(&manifest); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+	manifest.UpdatedAt = mc.App.Store.Clock.Now()
+	if err := mc.App.Store.SaveManifest(&manifest); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	drift, err := mc.App.DriftChecker.Check()
+	if err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	c.JSON(200, gin.H{"manifest": manifest, "drift": drift})
+}
+
+// Destroy deletes the node's manifest, so that it is no longer checked
+// for drift.
+// Example:
+//  "<application>/manifest"
+func (mc *ManifestController) Destroy(c *gin.Context) {
+	if err := mc.App.Store.DeleteManifest(); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+	if _, err := mc.App.DriftChecker.Check(); err != nil && err != storm.ErrNotFound {
+		problem(c, 500, err.Error())
+		return
+	}
+	c.Status(200)
+}
+
+// Current returns the node's actual state as a manifest, which can be
+// registered as it is, or edited first, on this node or others like it.
+// Example:
+//  "<application>/manifest/current"
+func (mc *ManifestController) Current(c *gin.Context) {
+	if manifest, err := services.CurrentManifest(mc.App.Store); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, manifest)
+	}
+}
+
+// Drift checks the node against its manifest now, and returns how its
+// state differs.
+// Example:
+//  "<application>/manifest/drift"
+func (mc *ManifestController) Drift(c *gin.Context) {
+	drift, err := mc.App.DriftChecker.Check()
+	if err == storm.ErrNotFound {
+		problem(c, 404, "No manifest has been registered")
+	} else if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"drifted": drift.Drifted(), "drift": drift})
+	}
+}
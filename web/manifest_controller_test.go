@@ -0,0 +1,101 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifestController_Drift(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKeyStore()
+	defer cleanup()
+	events := app.Store.Events.Subscribe()
+	defer app.Store.Events.Unsubscribe(events)
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	bt := cltest.NewBridgeType("price", "https://price.example.com")
+	assert.Nil(t, app.Store.Save(&bt))
+
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/v2/manifest/drift")
+	cltest.CheckStatusCode(t, resp, 404)
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/manifest/current")
+	cltest.CheckStatusCode(t, resp, 200)
+	current := cltest.ParseResponseBody(resp)
+	var manifest models.Manifest
+	assert.Nil(t, json.Unmarshal(current, &manifest))
+	assert.Equal(t, []string{j.ID}, manifest.Jobs)
+	assert.Equal(t, app.Store.KeyStore.GetAccount().Address, manifest.KeyAddresses[0])
+	assert.Equal(t, app.Store.Config.Digest(), manifest.ConfigDigest)
+
+	resp = cltest.AuthenticatedPut(app.Server.URL+"/v2/manifest", bytes.NewBuffer(current))
+	cltest.CheckStatusCode(t, resp, 200)
+	var updated struct {
+		Drift models.Drift `json:"drift"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &updated))
+	assert.False(t, updated.Drift.Drifted())
+
+	extra := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&extra))
+	bt.URL = cltest.WebURL("https://moved.example.com")
+	assert.Nil(t, app.Store.Save(&bt))
+
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/manifest/drift")
+	cltest.CheckStatusCode(t, resp, 200)
+	var checked struct {
+		Drifted bool         `json:"drifted"`
+		Drift   models.Drift `json:"drift"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &checked))
+	assert.True(t, checked.Drifted)
+	assert.Equal(t, []string{extra.ID}, checked.Drift.UnexpectedJobs)
+	assert.Equal(t, []string{"price"}, checked.Drift.ChangedBridges)
+	assert.Equal(t, models.EventDriftDetected, nextDriftEvent(t, events).Type)
+
+	assert.Nil(t, app.ArchiveJob(extra))
+	bt.URL = cltest.WebURL("https://price.example.com")
+	assert.Nil(t, app.Store.Save(&bt))
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/manifest/drift")
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.Equal(t, models.EventDriftResolved, nextDriftEvent(t, events).Type)
+
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/manifest")
+	cltest.CheckStatusCode(t, resp, 200)
+	resp = cltest.AuthenticatedGet(app.Server.URL + "/v2/manifest")
+	cltest.CheckStatusCode(t, resp, 404)
+}
+
+func TestManifestController_Update_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPut(app.Server.URL+"/v2/manifest", bytes.NewBufferString(`{"jobs": "nope"}`))
+	cltest.CheckStatusCode(t, resp, 400)
+}
+
+// nextDriftEvent returns the next drift event published, skipping events
+// about runs.
+func nextDriftEvent(t *testing.T, events chan models.Event) models.Event {
+	for {
+		select {
+		case event := <-events:
+			if event.Type == models.EventDriftDetected || event.Type == models.EventDriftResolved {
+				return event
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("No drift event published")
+			return models.Event{}
+		}
+	}
+}
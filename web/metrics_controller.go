@@ -0,0 +1,64 @@
+package web
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/metrics"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/utils"
+)
+
+// MetricsController serves the node's metrics for Prometheus to scrape.
+type MetricsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Show refreshes the account balances and run queue, and writes every metric in the
+// Prometheus text format.
+// Example:
+//  "<application>/metrics"
+func (mc *MetricsController) Show(c *gin.Context) {
+	mc.updateBalances()
+	mc.updateQueue()
+	c.Header("Content-Type", "text/plain; version=0.0.4")
+	c.Status(200)
+	if err := metrics.Write(c.Writer); err != nil {
+		logger.Web.Warnw("Writing metrics", "err", err)
+	}
+}
+
+// updateQueue sets the run queue gauges that change as time passes rather
+// than as runs are queued.
+func (mc *MetricsController) updateQueue() {
+	stats := mc.App.Store.RunQueue.Stats()
+	metrics.OldestQueuedRunAge.Set(stats.OldestQueuedSeconds)
+	metrics.RunWorkersBusy.Set(float64(stats.Busy))
+	metrics.RunWorkerUtilization.Set(stats.Utilization)
+}
+
+// updateBalances sets the balance gauges for the node's account, leaving
+// them as they were if the ethereum node cannot be reached.
+func (mc *MetricsController) updateBalances() {
+	store := mc.App.Store
+	if !store.Signer.HasAccounts() {
+		return
+	}
+	address := store.Signer.GetAccount().Address
+
+	if store.CurrentConfig().LinkContractAddress == "" {
+		if eth, err := store.TxManager.GetEthBalance(address); err != nil {
+			logger.Web.Warnw("Getting ETH balance for metrics", "err", err)
+		} else {
+			metrics.EthBalance.Set(eth, address.Hex())
+		}
+		return
+	}
+	contract := common.HexToAddress(store.CurrentConfig().LinkContractAddress)
+	if wei, link, err := store.TxManager.GetBalances(address, contract); err != nil {
+		logger.Web.Warnw("Getting balances for metrics", "err", err)
+	} else {
+		metrics.EthBalance.Set(utils.WeiToEth(wei), address.Hex())
+		metrics.LinkBalance.Set(utils.WeiToEth(link), address.Hex())
+	}
+}
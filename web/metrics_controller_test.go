@@ -0,0 +1,38 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsController_Show(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	cltest.CreateJobRunViaWeb(t, app, j)
+
+	resp := cltest.AuthenticatedGet(app.Server.URL + "/metrics")
+	cltest.CheckStatusCode(t, resp, 200)
+	body := string(cltest.ParseResponseBody(resp))
+	assert.Contains(t, body, `chainlink_runs_started_total{job_id="`+j.ID+`"} 1`)
+	assert.Contains(t, body, "# TYPE chainlink_task_duration_seconds histogram")
+	assert.Contains(t, body, "chainlink_active_subscriptions")
+}
+
+func TestMetricsController_RequiresAuth(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp, err := http.Get(app.Server.URL + "/metrics")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+}
@@ -0,0 +1,280 @@
+package web
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/store"
+)
+
+// OIDCProvider signs operators in through the OpenID Connect provider at
+// OIDC_ISSUER, with the authorization code flow. The ID token it returns
+// must be signed with RS256 by one of the provider's keys, and its email
+// claim becomes the operator's email, while the OIDC_GROUPS_CLAIM, groups
+// by default, is mapped to a role.
+type OIDCProvider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	GroupsClaim  string
+	client       *http.Client
+}
+
+// NewOIDCProvider returns the OIDCProvider in the config, or nil if there
+// is none.
+func NewOIDCProvider(config store.Config) *OIDCProvider {
+	if config.OIDCIssuer == "" {
+		return nil
+	}
+	claim := config.OIDCGroupsClaim
+	if claim == "" {
+		claim = "groups"
+	}
+	return &OIDCProvider{
+		Issuer:       strings.TrimRight(config.OIDCIssuer, "/"),
+		ClientID:     config.OIDCClientID,
+		ClientSecret: config.OIDCClientSecret,
+		RedirectURL:  config.OIDCRedirectURL,
+		GroupsClaim:  claim,
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// oidcDiscovery is the part of the provider's configuration, found at
+// /.well-known/openid-configuration, used to sign in.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func (op *OIDCProvider) discover() (oidcDiscovery, error) {
+	var d oidcDiscovery
+	if err := op.getJSON(op.Issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return d, err
+	}
+	if strings.TrimRight(d.Issuer, "/") != op.Issuer {
+		return d, fmt.Errorf("OIDC: provider's issuer %v is not %v", d.Issuer, op.Issuer)
+	}
+	return d, nil
+}
+
+// AuthURL returns where to send the operator to sign in, with the state
+// and nonce that must come back with them.
+func (op *OIDCProvider) AuthURL(state, nonce string) (string, error) {
+	d, err := op.discover()
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", op.ClientID)
+	query.Set("redirect_uri", op.RedirectURL)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange trades the code the provider sent the operator back with for
+// their ID token, and returns the email and groups in it once it is
+// verified.
+func (op *OIDCProvider) Exchange(code, nonce string) (string, []string, error) {
+	d, err := op.discover()
+	if err != nil {
+		return "", nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", op.RedirectURL)
+	req, err := http.NewRequest("POST", d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(op.ClientID), url.QueryEscape(op.ClientSecret))
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := op.doJSON(req, &token); err != nil {
+		return "", nil, err
+	}
+	if token.IDToken == "" {
+		return "", nil, errors.New("OIDC: no id_token in token response")
+	}
+
+	claims, err := op.verify(d, token.IDToken)
+	if err != nil {
+		return "", nil, err
+	}
+	if claims["nonce"] != nonce {
+		return "", nil, errors.New("OIDC: ID token nonce does not match")
+	}
+	email, _ := claims["email"].(string)
+	if email == "" {
+		return "", nil, errors.New("OIDC: ID token has no email")
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return "", nil, errors.New("OIDC: email is not verified")
+	}
+	return email, claimStrings(claims[op.GroupsClaim]), nil
+}
+
+// verify checks the ID token's signature against the provider's keys, and
+// that it was issued by the provider, for this node, and has not expired.
+func (op *OIDCProvider) verify(d oidcDiscovery, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("OIDC: malformed ID token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("OIDC: unsupported ID token algorithm %v", header.Alg)
+	}
+	key, err := op.key(d, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig); err != nil {
+		return nil, errors.New("OIDC: invalid ID token signature")
+	}
+
+	claims := map[string]interface{}{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimRight(iss, "/") != op.Issuer {
+		return nil, fmt.Errorf("OIDC: ID token issuer %v is not %v", iss, op.Issuer)
+	}
+	audience := claimStrings(claims["aud"])
+	if !containsString(audience, op.ClientID) {
+		return nil, errors.New("OIDC: ID token is not for this client")
+	}
+	exp, _ := claims["exp"].(float64)
+	if time.Now().After(time.Unix(int64(exp), 0)) {
+		return nil, errors.New("OIDC: ID token has expired")
+	}
+	return claims, nil
+}
+
+// key returns the provider's RSA key with the given ID, or its only key
+// if the token does not name one.
+func (op *OIDCProvider) key(d oidcDiscovery, kid string) (*rsa.PublicKey, error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := op.getJSON(d.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (kid != "" && k.Kid != kid) || (kid == "" && len(jwks.Keys) != 1) {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+	return nil, fmt.Errorf("OIDC: no key %v for ID token", kid)
+}
+
+func (op *OIDCProvider) getJSON(url string, dst interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	return op.doJSON(req, dst)
+}
+
+func (op *OIDCProvider) doJSON(req *http.Request, dst interface{}) error {
+	resp, err := op.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("OIDC: %v responded %v: %s", req.URL.Path, resp.StatusCode, b)
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func decodeJWTPart(part string, dst interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// claimStrings returns a claim that may be a string or a list of them as
+// a list.
+func claimStrings(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return []string{}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
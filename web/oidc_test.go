@@ -0,0 +1,151 @@
+package web_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/web"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOIDC is an OpenID Connect provider that hands out ID tokens for one
+// code, with the nonce it was last sent.
+type fakeOIDC struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]interface{}
+}
+
+func newFakeOIDC(t *testing.T, clientID string, claims map[string]interface{}) *fakeOIDC {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	fo := &fakeOIDC{key: key, claims: claims}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 fo.server.URL,
+			"authorization_endpoint": fo.server.URL + "/authorize",
+			"token_endpoint":         fo.server.URL + "/token",
+			"jwks_uri":               fo.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != clientID || secret != "shh" || r.FormValue("code") != "good" {
+			w.WriteHeader(400)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": fo.sign(t)})
+	})
+	fo.server = httptest.NewServer(mux)
+	fo.claims["iss"] = fo.server.URL
+	fo.claims["aud"] = clientID
+	fo.claims["exp"] = time.Now().Add(time.Hour).Unix()
+	return fo
+}
+
+func (fo *fakeOIDC) sign(t *testing.T) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "key1"})
+	payload, _ := json.Marshal(fo.claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, fo.key, crypto.SHA256, hash[:])
+	assert.Nil(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestSessionsController_OIDC(t *testing.T) {
+	t.Parallel()
+
+	fo := newFakeOIDC(t, "node", map[string]interface{}{
+		"email":  "ops@example.com",
+		"groups": []string{"oracle-ops", "staff"},
+	})
+	defer fo.server.Close()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	app.Store.Config.OIDCIssuer = fo.server.URL
+	app.Store.Config.OIDCClientID = "node"
+	app.Store.Config.OIDCClientSecret = "shh"
+	app.Store.Config.OIDCRedirectURL = app.Server.URL + "/sessions/oidc/callback"
+	app.Store.Config.AuthGroupRoles = "oracle-ops=run"
+
+	jar, err := cookiejar.New(nil)
+	assert.Nil(t, err)
+	client := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(app.Server.URL + "/sessions/oidc")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 302)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	assert.Nil(t, err)
+	assert.Equal(t, fo.server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, "node", location.Query().Get("client_id"))
+	state := location.Query().Get("state")
+	fo.claims["nonce"] = location.Query().Get("nonce")
+
+	resp, err = client.Get(app.Server.URL + "/sessions/oidc/callback?code=good&state=forged")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+
+	resp, err = client.Get(app.Server.URL + "/sessions/oidc")
+	assert.Nil(t, err)
+	location, err = url.Parse(resp.Header.Get("Location"))
+	assert.Nil(t, err)
+	state = location.Query().Get("state")
+	fo.claims["nonce"] = location.Query().Get("nonce")
+
+	resp, err = client.Get(app.Server.URL + "/sessions/oidc/callback?code=good&state=" + state)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 302)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+
+	resp, err = client.Get(app.Server.URL + "/v2/runs")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	user, err := app.Store.FindUser("ops@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, web.ProviderOIDC, user.Provider)
+	assert.Equal(t, models.RoleRun, user.Role)
+}
+
+func TestSessionsController_OIDC_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp, err := http.Get(app.Server.URL + "/sessions/oidc")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 404)
+}
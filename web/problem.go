@@ -0,0 +1,147 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/adapters"
+	"github.com/smartcontractkit/chainlink/logger"
+)
+
+// ProblemContentType is the content type of error responses.
+const ProblemContentType = "application/problem+json"
+
+// ProblemDocsURL is where the error codes are documented. A problem's type
+// is this URL with its code as the fragment.
+const ProblemDocsURL = "https://github.com/smartcontractkit/chainlink/blob/master/docs/errors.md"
+
+// The error codes that are not simply named after their status.
+const (
+	// CodeTOTPRequired is returned when logging in without the two-factor
+	// code of a user that has turned it on.
+	CodeTOTPRequired = "totp_required"
+	// CodeInvalidJobSpec is returned when a job spec has invalid fields,
+	// which are listed in invalidParams.
+	CodeInvalidJobSpec = "invalid_job_spec"
+)
+
+// statusCodes are the error codes of problems that are not given one.
+var statusCodes = map[int]string{
+	400: "invalid_request",
+	401: "unauthorized",
+	403: "forbidden",
+	404: "not_found",
+	405: "not_allowed",
+	409: "conflict",
+	413: "request_too_large",
+	415: "unsupported_media_type",
+	422: "unprocessable",
+	429: "rate_limited",
+	500: "internal_error",
+	502: "bad_gateway",
+}
+
+// Problem is an error response, as described in RFC 7807. Code is a
+// machine readable name for the type of problem, and InvalidParams lists
+// the fields of the request that are invalid, if it was rejected for them.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code"`
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
+}
+
+// InvalidParam is a field of the request, and why it is invalid.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// NewProblem returns the problem with the given status and code, which is
+// named after the status if it is empty.
+func NewProblem(status int, code, detail string) Problem {
+	if code == "" {
+		code = statusCodes[status]
+	}
+	if code == "" {
+		code = fmt.Sprintf("http_%d", status)
+	}
+	return Problem{
+		Type:   ProblemDocsURL + "#" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error returns the problem's detail, or its title if it has none.
+func (p Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// problem responds with a problem of the given status, and the code named
+// after it.
+func problem(c *gin.Context, status int, detail string) {
+	writeProblem(c, NewProblem(status, "", detail))
+}
+
+// abortProblem responds with a problem of the given status, and stops the
+// request from reaching any later handlers.
+func abortProblem(c *gin.Context, status int, detail string) {
+	c.Abort()
+	problem(c, status, detail)
+}
+
+// jobSpecProblem responds to an invalid job spec with 400 Bad Request,
+// listing the invalid fields if the error names them.
+func jobSpecProblem(c *gin.Context, err error) {
+	ve, ok := err.(adapters.ValidationError)
+	if !ok {
+		problem(c, 400, err.Error())
+		return
+	}
+	p := NewProblem(400, CodeInvalidJobSpec, ve.Error())
+	for _, fe := range ve {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: fe.Field, Reason: fe.Reason})
+	}
+	writeProblem(c, p)
+}
+
+func writeProblem(c *gin.Context, p Problem) {
+	p.Instance = c.Request.URL.Path
+	b, err := json.Marshal(p)
+	if err != nil {
+		c.Status(p.Status)
+		return
+	}
+	c.Data(p.Status, ProblemContentType, b)
+}
+
+// notFound responds to requests for paths the node does not serve.
+func notFound(c *gin.Context) {
+	problem(c, 404, "No route for "+c.Request.Method+" "+c.Request.URL.Path)
+}
+
+// recovery responds with 500 Internal Server Error when a handler panics,
+// and logs the panic with its stack.
+func recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Web.Errorw("Web request panicked", "path", c.Request.URL.Path, "error", err, "stack", string(debug.Stack()))
+				abortProblem(c, 500, "Internal error")
+			}
+		}()
+		c.Next()
+	}
+}
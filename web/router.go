@@ -2,64 +2,268 @@ package web
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/smartcontractkit/chainlink/logger"
 	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
 )
 
 // Router listens and responds to requests to the node for valid paths.
 func Router(app *services.ChainlinkApplication) *gin.Engine {
+	config := app.Store.CurrentConfig()
 	engine := gin.New()
-	config := app.Store.Config
-	basicAuth := gin.BasicAuth(gin.Accounts{config.BasicAuthUsername: config.BasicAuthPassword})
-	engine.Use(loggerFunc(), gin.Recovery(), basicAuth)
+	engine.Use(
+		limitBody(config.MaxRequestBodySize),
+		loggerFunc(),
+		recovery(),
+		cors(config.AllowOrigins),
+	)
+	engine.NoRoute(notFound)
 
-	v2 := engine.Group("/v2")
+	h := HealthController{app}
+	engine.GET("/health", h.Show)
+	engine.GET("/readiness", h.Readiness)
+
+	if config.StatusPageEnabled {
+		st := StatusController{app}
+		engine.GET("/status", rateLimit(statusRateLimit, time.Minute, config.TrustedProxies), st.Show)
+	}
+
+	mc := MetricsController{app}
+	engine.GET("/metrics", authRequired(app.Store), mc.Show)
+
+	sc := SessionsController{app}
+	engine.POST("/sessions", sc.Create)
+	engine.DELETE("/sessions", sc.Destroy)
+	engine.GET("/sessions/oidc", sc.OIDCStart)
+	engine.GET("/sessions/oidc/callback", sc.OIDCCallback)
+
+	jr := JobRunsController{app}
+	engine.POST("/v2/specs/:SpecID/runs", externalInitiatorAuthRequired(app.Store), audit(app.Store, models.AuditRunCreated), jr.CreateExternal)
+
+	v2 := engine.Group("/v2", authRequired(app.Store))
 	{
+		run := requireRole(models.RoleRun)
+		edit := requireRole(models.RoleEdit)
+		admin := requireRole(models.RoleAdmin)
+
 		j := JobsController{app}
 		v2.GET("/jobs", j.Index)
-		v2.POST("/jobs", j.Create)
+		v2.POST("/jobs", edit, audit(app.Store, models.AuditJobCreated), j.Create)
 		v2.GET("/jobs/:JobID", j.Show)
+		v2.DELETE("/jobs/:JobID", edit, audit(app.Store, models.AuditJobDeleted), j.Destroy)
+		v2.POST("/jobs/:JobID/unarchive", edit, audit(app.Store, models.AuditJobUnarchived), j.Unarchive)
+		v2.POST("/jobs/:JobID/diff", j.Diff)
+		v2.POST("/jobs/:JobID/pause", edit, audit(app.Store, models.AuditJobPaused), j.Pause)
+		v2.POST("/jobs/:JobID/resume", edit, audit(app.Store, models.AuditJobResumed), j.Resume)
+
+		js := JobSpecsController{app}
+		v2.GET("/job_specs", js.Index)
+		v2.POST("/job_specs", edit, audit(app.Store, models.AuditJobsImported), js.Create)
+		v2.POST("/specs/preview", js.Preview)
 
-		jr := JobRunsController{app}
 		v2.GET("/jobs/:JobID/runs", jr.Index)
-		v2.POST("/jobs/:JobID/runs", jr.Create)
-		v2.PATCH("/runs/:RunID", jr.Update)
+		v2.POST("/jobs/:JobID/runs", run, audit(app.Store, models.AuditRunCreated), jr.Create)
+		v2.GET("/runs", jr.All)
+		v2.PATCH("/runs/:RunID", run, jr.Update)
+		v2.GET("/runs/:RunID/logs", jr.Logs)
+		v2.POST("/runs/prune", admin, audit(app.Store, models.AuditRunsPruned), jr.Prune)
+
+		fr := ForwardedRunsController{app}
+		v2.POST("/forwarded_runs", admin, audit(app.Store, models.AuditRunCreated), fr.Create)
+
+		ec := EventsController{app}
+		v2.GET("/ws", ec.Stream)
+
+		dc := DebugController{app}
+		v2.GET("/debug/queue", dc.Queue)
+
+		fc := FeedsController{app}
+		v2.GET("/feeds", fc.Index)
+		v2.POST("/feeds", edit, audit(app.Store, models.AuditFeedCreated), fc.Create)
+		v2.GET("/feeds/:FeedID", fc.Show)
+		v2.DELETE("/feeds/:FeedID", edit, audit(app.Store, models.AuditFeedDeleted), fc.Destroy)
+
+		al := AddressListsController{app}
+		v2.GET("/address_lists", al.Index)
+		v2.GET("/address_lists/:Name", al.Show)
+		v2.PUT("/address_lists/:Name", edit, audit(app.Store, models.AuditAddressListUpdated), al.Update)
+		v2.DELETE("/address_lists/:Name", edit, audit(app.Store, models.AuditAddressListDeleted), al.Destroy)
 
 		tt := BridgeTypesController{app}
-		v2.POST("/bridge_types", tt.Create)
+		v2.POST("/bridge_types", edit, audit(app.Store, models.AuditBridgeTypeCreated), tt.Create)
+
+		cc := ConfigController{app}
+		v2.PATCH("/config", admin, audit(app.Store, models.AuditConfigChanged), cc.Update)
+		v2.POST("/config/reload", admin, audit(app.Store, models.AuditConfigChanged), cc.Reload)
+		v2.GET("/config/history", admin, cc.History)
+
+		lc := LogController{app}
+		v2.GET("/log", admin, lc.Show)
+		v2.PATCH("/log", admin, audit(app.Store, models.AuditConfigChanged), lc.Update)
+
+		mf := ManifestController{app}
+		v2.GET("/manifest", admin, mf.Show)
+		v2.PUT("/manifest", admin, audit(app.Store, models.AuditManifestUpdated), mf.Update)
+		v2.DELETE("/manifest", admin, audit(app.Store, models.AuditManifestDeleted), mf.Destroy)
+		v2.GET("/manifest/current", admin, mf.Current)
+		v2.GET("/manifest/drift", admin, mf.Drift)
+
+		ei := ExternalInitiatorsController{app}
+		v2.GET("/external_initiators", admin, ei.Index)
+		v2.POST("/external_initiators", admin, audit(app.Store, models.AuditExternalInitiatorCreated), ei.Create)
+		v2.DELETE("/external_initiators/:Name", admin, audit(app.Store, models.AuditExternalInitiatorDeleted), ei.Destroy)
+
+		cr := CredentialsController{app}
+		v2.GET("/credentials", admin, cr.Index)
+		v2.POST("/credentials", admin, audit(app.Store, models.AuditCredentialCreated), cr.Create)
+		v2.PUT("/credentials/:Name", admin, audit(app.Store, models.AuditCredentialRotated), cr.Update)
+		v2.DELETE("/credentials/:Name", admin, audit(app.Store, models.AuditCredentialDeleted), cr.Destroy)
+
+		at := APITokensController{app}
+		v2.GET("/api_tokens", admin, at.Index)
+		v2.POST("/api_tokens", admin, audit(app.Store, models.AuditAPITokenCreated), at.Create)
+		v2.DELETE("/api_tokens/:TokenID", admin, audit(app.Store, models.AuditAPITokenDeleted), at.Destroy)
+
+		u := UsersController{app}
+		v2.GET("/users", admin, u.Index)
+		v2.POST("/users", admin, audit(app.Store, models.AuditUserCreated), u.Create)
+		v2.PATCH("/users/:Email", admin, audit(app.Store, models.AuditUserUpdated), u.Update)
+		v2.DELETE("/users/:Email", admin, audit(app.Store, models.AuditUserDeleted), u.Destroy)
+
+		totp := TOTPController{app}
+		v2.POST("/totp", totp.Create)
+		v2.POST("/totp/confirm", audit(app.Store, models.AuditTOTPEnabled), totp.Confirm)
+		v2.DELETE("/totp", audit(app.Store, models.AuditTOTPDisabled), totp.Destroy)
+
+		bc := BalancesController{app}
+		v2.GET("/balances", bc.Show)
+
+		tx := TxsController{app}
+		v2.POST("/txs/unsigned", admin, tx.Build)
+		v2.POST("/txs/broadcast", admin, audit(app.Store, models.AuditTxBroadcast), tx.Broadcast)
+
+		ac := AuditController{app}
+		v2.GET("/audit", admin, ac.Index)
+		v2.GET("/audit/export", admin, ac.Export)
 	}
 
 	return engine
 }
 
+// RedirectToTLS responds to every request with a permanent redirect to the
+// same path on the given HTTPS port, so that credentials are not sent in
+// plaintext.
+func RedirectToTLS(tlsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		target := url.URL{
+			Scheme:   "https",
+			Host:     net.JoinHostPort(host, tlsPort),
+			Path:     r.URL.Path,
+			RawQuery: r.URL.RawQuery,
+		}
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
+}
+
+// limitBody responds with 413 Request Entity Too Large to requests whose
+// body is more than max bytes, before the body is buffered for logging.
+func limitBody(max int64) gin.HandlerFunc {
+	tooLarge := func(c *gin.Context) {
+		logger.Web.Warnw("Web request body too large", "path", c.Request.URL.Path, "clientIP", c.ClientIP())
+		abortProblem(c, 413, "Request body is larger than "+strconv.FormatInt(max, 10)+" bytes")
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > max {
+			tooLarge(c)
+			return
+		}
+		buf, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, max+1))
+		if err != nil {
+			abortProblem(c, 400, err.Error())
+			return
+		} else if int64(len(buf)) > max {
+			tooLarge(c)
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
+		c.Next()
+	}
+}
+
+// cors lets browsers on the given comma separated origins call the API. A
+// "*" origin allows any site, but without the session cookie, so that other
+// sites cannot act on a logged in operator's behalf.
+func cors(allowOrigins string) gin.HandlerFunc {
+	origins := map[string]bool{}
+	for _, origin := range strings.Split(allowOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins[origin] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		header := c.Writer.Header()
+		if origin != "" && origins[origin] {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+			header.Add("Vary", "Origin")
+		} else if origin != "" && origins["*"] {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Next()
+			return
+		}
+
+		if c.Request.Method == "OPTIONS" {
+			header.Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE")
+			header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			header.Set("Access-Control-Max-Age", "600")
+			c.AbortWithStatus(204)
+			return
+		}
+		c.Next()
+	}
+}
+
 // Inspired by https://github.com/gin-gonic/gin/issues/961
 func loggerFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		buf, err := ioutil.ReadAll(c.Request.Body)
 		if err != nil {
-			logger.Warn(fmt.Sprintf("Web request log error: %v", err.Error()))
+			logger.Web.Warn(fmt.Sprintf("Web request log error: %v", err.Error()))
 			c.Next()
 			return
 		}
-		rdr := bytes.NewBuffer(buf)
 		c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
 
 		start := time.Now()
 		c.Next()
 		end := time.Now()
 
-		logger.Infow("Web request",
+		logger.Web.Infow("Web request",
 			"method", c.Request.Method,
 			"status", c.Writer.Status(),
 			"path", c.Request.URL.Path,
 			"query", c.Request.URL.RawQuery,
-			"body", readBody(rdr),
+			"body", redactBody(buf),
 			"clientIP", c.ClientIP(),
 			"comment", c.Errors.ByType(gin.ErrorTypePrivate).String(),
 			"servedAt", end.Format("2006/01/02 - 15:04:05"),
@@ -68,10 +272,52 @@ func loggerFunc() gin.HandlerFunc {
 	}
 }
 
-func readBody(reader io.Reader) string {
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(reader)
+// redactedKeys are the keys whose values are left out of logged request
+// bodies, at any depth, as they hold passwords, one time codes, and
+// secrets that the logs, and the collectors they are shipped to, must not.
+var redactedKeys = map[string]bool{
+	"password":      true,
+	"totp":          true,
+	"recoverycode":  true,
+	"recoverycodes": true,
+	"value":         true,
+	"secret":        true,
+	"token":         true,
+	"outgoingtoken": true,
+}
+
+// redactBody returns the request body to log, with the values of
+// redactedKeys replaced. Bodies that are not JSON are not logged, as
+// there is no telling what is secret in them.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return fmt.Sprintf("(%v bytes, not JSON)", len(body))
+	}
+	b, err := json.Marshal(redactJSON(parsed))
+	if err != nil {
+		return fmt.Sprintf("(%v bytes)", len(body))
+	}
+	return string(b)
+}
 
-	s := buf.String()
-	return s
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if redactedKeys[strings.ToLower(key)] {
+				v[key] = "[redacted]"
+			} else {
+				v[key] = redactJSON(elem)
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = redactJSON(elem)
+		}
+	}
+	return value
 }
@@ -0,0 +1,127 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectToTLS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"with port", "http://localhost:6688/v2/jobs?page=2", "https://localhost:6689/v2/jobs?page=2"},
+		{"without port", "http://node.example.com/sessions", "https://node.example.com:6689/sessions"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", test.url, nil)
+			w := httptest.NewRecorder()
+			web.RedirectToTLS("6689").ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, test.expected, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestRouter_CORS(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig()
+	config.AllowOrigins = "http://dashboard.example.com, http://localhost:3000"
+	app, cleanup := cltest.NewApplicationWithConfig(config)
+	defer cleanup()
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest("OPTIONS", app.Server.URL+"/v2/jobs", nil)
+		assert.Nil(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	resp := preflight("http://dashboard.example.com")
+	cltest.CheckStatusCode(t, resp, 204)
+	assert.Equal(t, "http://dashboard.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Methods"), "POST")
+
+	resp = preflight("http://evil.example.com")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestRouter_MaxRequestBodySize(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig()
+	config.MaxRequestBodySize = 1024
+	app, cleanup := cltest.NewApplicationWithConfig(config)
+	defer cleanup()
+
+	body := `{"initiators":[{"type":"web"}],"tasks":[{"type":"NoOp","padding":"` + strings.Repeat("a", 1024) + `"}]}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 413)
+	assert.Equal(t, web.ProblemContentType, resp.Header.Get("Content-Type"))
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/jobs", bytes.NewBuffer(cltest.LoadJSON("../internal/fixtures/web/hello_world_job.json")))
+	cltest.CheckStatusCode(t, resp, 200)
+}
+
+func TestRouter_Problems(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		path   string
+		auth   bool
+		status int
+		code   string
+	}{
+		{"unknown route", "/v3/jobs", true, 404, "not_found"},
+		{"not logged in", "/v2/jobs", false, 401, "unauthorized"},
+		{"unknown job", "/v2/jobs/nope", true, 404, "not_found"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			var resp *http.Response
+			if test.auth {
+				resp = cltest.AuthenticatedGet(app.Server.URL + test.path)
+			} else {
+				var err error
+				resp, err = http.Get(app.Server.URL + test.path)
+				assert.Nil(t, err)
+			}
+			cltest.CheckStatusCode(t, resp, test.status)
+			assert.Equal(t, web.ProblemContentType, resp.Header.Get("Content-Type"))
+
+			var p web.Problem
+			assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &p))
+			assert.Equal(t, test.status, p.Status)
+			assert.Equal(t, test.code, p.Code)
+			assert.Equal(t, web.ProblemDocsURL+"#"+test.code, p.Type)
+			assert.Equal(t, http.StatusText(test.status), p.Title)
+			assert.Equal(t, test.path, p.Instance)
+			assert.NotEmpty(t, p.Detail)
+		})
+	}
+}
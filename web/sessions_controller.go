@@ -0,0 +1,332 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/asdine/storm"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/utils"
+)
+
+const (
+	// sessionCookie is the name of the cookie holding the session ID.
+	sessionCookie = "clsession"
+	// sessionTimeout is how long a session lasts without being used.
+	sessionTimeout = 24 * time.Hour
+)
+
+// SessionsController logs the operator in and out of the node.
+type SessionsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Create checks the given email and password against the operator's
+// credentials and starts a session, whose ID is set in an HttpOnly,
+// SameSite=Strict cookie. The cookie is marked Secure when the node is
+// served over TLS. The credentials must be sent as application/json, so
+// that other sites cannot sign browsers in with a form.
+// Names that are not one of the node's own users are checked against the
+// LDAP server, if LDAP_URL is set.
+// Users with two-factor authentication must also give their current code
+// as "totp", or a recovery code as "recoveryCode". Without either, the
+// response's code is totp_required, so that clients know to ask for one.
+// Example:
+//  "<application>/sessions"
+func (sc *SessionsController) Create(c *gin.Context) {
+	var credentials struct {
+		Email        string `json:"email"`
+		Password     string `json:"password"`
+		TOTP         string `json:"totp"`
+		RecoveryCode string `json:"recoveryCode"`
+	}
+	if !sentAsJSON(c) {
+		problem(c, 415, "Content-Type must be application/json")
+		return
+	}
+	if err := c.ShouldBindJSON(&credentials); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	user, ok := sc.checkPassword(credentials.Email, credentials.Password)
+	if !ok {
+		problem(c, 401, "Invalid email or password")
+		return
+	}
+	if user.TOTPEnabled {
+		if credentials.TOTP == "" && credentials.RecoveryCode == "" {
+			writeProblem(c, NewProblem(401, CodeTOTPRequired, "Two-factor authentication code required"))
+			return
+		}
+		if !checkSecondFactor(&user, credentials.TOTP, credentials.RecoveryCode) {
+			problem(c, 401, "Invalid two-factor authentication code")
+			return
+		}
+		if err := sc.App.Store.Save(&user); err != nil {
+			problem(c, 500, err.Error())
+			return
+		}
+	}
+
+	if sc.startSession(c, user) {
+		c.JSON(200, gin.H{"email": user.Email})
+	}
+}
+
+// checkPassword returns the user with the email if the password is theirs.
+// Users from LDAP, and names the node does not know, are checked against
+// the LDAP server, if there is one.
+func (sc *SessionsController) checkPassword(email, password string) (models.User, bool) {
+	store := sc.App.Store
+	user, err := store.FindUser(email)
+	if err == nil && user.Provider == "" {
+		return user, user.CheckPassword(password)
+	}
+	auth := remoteAuthenticator(store.CurrentConfig())
+	if auth == nil || (err != nil && err != storm.ErrNotFound) || (err == nil && user.Provider != ProviderLDAP) {
+		return user, false
+	}
+	groups, err := auth.Authenticate(email, password)
+	if err == nil {
+		user, err = saveRemoteUser(store, email, ProviderLDAP, groups)
+	}
+	if err != nil {
+		logger.Web.Infow("LDAP login failed", "email", email, "err", err)
+		return user, false
+	}
+	return user, true
+}
+
+// startSession starts a session for the user and sets its cookie. If the
+// session cannot be saved, it responds with the error and returns false.
+func (sc *SessionsController) startSession(c *gin.Context, user models.User) bool {
+	session := models.NewSession(user.Email)
+	if err := sc.App.Store.Save(&session); err != nil {
+		problem(c, 500, err.Error())
+		return false
+	}
+	setCookie(c, sessionCookie, session.ID, 0, "/", http.SameSiteStrictMode)
+	return true
+}
+
+// setCookie sets an HttpOnly cookie, marked Secure when the node is served
+// over TLS. Strict cookies are not sent with requests from other sites at
+// all; Lax ones are still sent when another site links the operator to the
+// node, as the OpenID Connect provider does after they sign in.
+func setCookie(c *gin.Context, name, value string, maxAge int, path string, sameSite http.SameSite) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     path,
+		Secure:   c.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+}
+
+// sentAsJSON returns true if the request's body is declared as JSON.
+// Browsers only let other sites send the content types of HTML forms
+// without asking the node first, so requests made with the session cookie
+// that change anything must be sent as JSON.
+func sentAsJSON(c *gin.Context) bool {
+	return c.ContentType() == "application/json"
+}
+
+// oidcCookie holds the state and nonce of a sign in through the OpenID
+// Connect provider, until the provider sends the operator back.
+const oidcCookie = "cloidc"
+
+// OIDCStart sends the operator to sign in with the OpenID Connect provider
+// at OIDC_ISSUER.
+// Example:
+//  "<application>/sessions/oidc"
+func (sc *SessionsController) OIDCStart(c *gin.Context) {
+	op := NewOIDCProvider(sc.App.Store.CurrentConfig())
+	if op == nil {
+		problem(c, 404, "OpenID Connect is not configured")
+		return
+	}
+	state, nonce := utils.NewBytes32ID(), utils.NewBytes32ID()
+	authURL, err := op.AuthURL(state, nonce)
+	if err != nil {
+		problem(c, 502, err.Error())
+		return
+	}
+	setCookie(c, oidcCookie, state+"."+nonce, 600, "/sessions/oidc", http.SameSiteLaxMode)
+	c.Redirect(302, authURL)
+}
+
+// OIDCCallback is where the OpenID Connect provider sends the operator
+// back to once they have signed in. Their ID token's groups give them a
+// role, and a session is started for them before they are sent on to the
+// node's home page.
+// Example:
+//  "<application>/sessions/oidc/callback"
+func (sc *SessionsController) OIDCCallback(c *gin.Context) {
+	op := NewOIDCProvider(sc.App.Store.CurrentConfig())
+	if op == nil {
+		problem(c, 404, "OpenID Connect is not configured")
+		return
+	}
+	cookie, err := c.Cookie(oidcCookie)
+	setCookie(c, oidcCookie, "", -1, "/sessions/oidc", http.SameSiteLaxMode)
+	parts := strings.SplitN(cookie, ".", 2)
+	if err != nil || len(parts) != 2 || c.Query("state") != parts[0] {
+		problem(c, 401, "Sign in was not started by this browser")
+		return
+	}
+	if providerErr := c.Query("error"); providerErr != "" {
+		problem(c, 401, "OpenID Connect: "+providerErr)
+		return
+	}
+
+	email, groups, err := op.Exchange(c.Query("code"), parts[1])
+	if err != nil {
+		logger.Web.Infow("OpenID Connect login failed", "err", err)
+		problem(c, 401, err.Error())
+		return
+	}
+	user, err := saveRemoteUser(sc.App.Store, email, ProviderOIDC, groups)
+	if err != nil {
+		logger.Web.Infow("OpenID Connect login failed", "email", email, "err", err)
+		problem(c, 403, err.Error())
+		return
+	}
+	if sc.startSession(c, user) {
+		c.Redirect(302, "/")
+	}
+}
+
+// Destroy ends the session in the request's cookie.
+// Example:
+//  "<application>/sessions"
+func (sc *SessionsController) Destroy(c *gin.Context) {
+	if id, err := c.Cookie(sessionCookie); err == nil {
+		if session, err := sc.App.Store.FindSession(id); err == nil {
+			if err := sc.App.Store.DeleteStruct(&session); err != nil {
+				problem(c, 500, err.Error())
+				return
+			}
+		}
+	}
+	setCookie(c, sessionCookie, "", -1, "/", http.SameSiteStrictMode)
+	c.JSON(200, gin.H{"email": ""})
+}
+
+const (
+	// identityKey is the context key holding who made an authenticated
+	// request.
+	identityKey = "identity"
+	// roleKey is the context key holding the role they made it with.
+	roleKey = "role"
+)
+
+// authRequired only lets requests through that carry an API token in their
+// Authorization header, or the cookie of a session that has not expired.
+// API tokens with scopes are also only let through to what their scopes
+// allow. Who made the request, the session's email or the API token's name,
+// and their role are kept in the context. Requests made with the session
+// cookie that are not reads must be sent as application/json.
+func authRequired(store *store.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		who, role, scopes, ok := authenticate(c, store)
+		if !ok {
+			abortProblem(c, 401, "Unauthorized")
+			return
+		}
+		if bearerToken(c) == "" && !readOnly(c.Request.Method) && !sentAsJSON(c) {
+			abortProblem(c, 415, "Content-Type must be application/json")
+			return
+		}
+		resource, action, id := requestScope(c)
+		if !models.ScopesAllow(scopes, resource, action, id) {
+			abortProblem(c, 403, "Forbidden")
+			return
+		}
+		c.Set(identityKey, who)
+		c.Set(roleKey, role)
+		c.Next()
+	}
+}
+
+// requireRole only lets authenticated requests through whose role can do
+// what the given role can.
+func requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !models.RoleAllows(c.GetString(roleKey), role) {
+			abortProblem(c, 403, "Forbidden")
+			return
+		}
+		c.Next()
+	}
+}
+
+func authenticate(c *gin.Context, store *store.Store) (string, string, []string, bool) {
+	if token := bearerToken(c); token != "" {
+		apiToken, err := store.FindAPIToken(token)
+		return "API token " + apiToken.Name, apiToken.Role, apiToken.Scopes, err == nil
+	}
+
+	id, err := c.Cookie(sessionCookie)
+	if err != nil {
+		return "", "", nil, false
+	}
+	session, err := store.FindSession(id)
+	if err != nil {
+		return "", "", nil, false
+	}
+	now := time.Now()
+	if session.Expired(now, sessionTimeout) {
+		store.DeleteStruct(&session)
+		return "", "", nil, false
+	}
+	user, err := store.FindUser(session.Email)
+	if err != nil {
+		return "", "", nil, false
+	}
+	session.LastUsed = now
+	return user.Email, user.Role, nil, store.Save(&session) == nil
+}
+
+// requestScope returns the kind of resource the request is for, whether it
+// reads or writes it, and the ID of the job it is about, if any. The runs of
+// a job, under /v2/jobs/:JobID/runs, are runs rather than jobs.
+func requestScope(c *gin.Context) (string, string, string) {
+	action := models.ScopeWrite
+	if readOnly(c.Request.Method) {
+		action = models.ScopeRead
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(c.Request.URL.Path, "/"), "v2/")
+	parts := strings.Split(path, "/")
+	resource := parts[0]
+	if resource == "jobs" && len(parts) > 2 && parts[2] == "runs" {
+		resource = "runs"
+	}
+	return resource, action, c.Param("JobID")
+}
+
+// readOnly returns true for the methods that only read.
+func readOnly(method string) bool {
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
+
+// identity returns who made the authenticated request.
+func identity(c *gin.Context) string {
+	return c.GetString(identityKey)
+}
+
+func bearerToken(c *gin.Context) string {
+	const prefix = "Bearer "
+	header := c.GetHeader("Authorization")
+	if len(header) <= len(prefix) || header[:len(prefix)] != prefix {
+		return ""
+	}
+	return header[len(prefix):]
+}
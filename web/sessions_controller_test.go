@@ -0,0 +1,142 @@
+package web_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsController_Create(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	jar, err := cookiejar.New(nil)
+	assert.Nil(t, err)
+	client := &http.Client{Jar: jar}
+
+	body := `{"email":"` + cltest.Username + `","password":"` + cltest.Password + `"}`
+	resp, err := client.Post(app.Server.URL+"/sessions", "application/json", bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	cookies := resp.Cookies()
+	assert.Equal(t, 1, len(cookies))
+	assert.Equal(t, "clsession", cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+
+	resp, err = client.Get(app.Server.URL + "/v2/jobs")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	req, err := http.NewRequest("DELETE", app.Server.URL+"/sessions", nil)
+	assert.Nil(t, err)
+	resp, err = client.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	resp, err = client.Get(app.Server.URL + "/v2/jobs")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+}
+
+func TestSessionsController_FormRequests(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"email":"` + cltest.Username + `","password":"` + cltest.Password + `"}`
+	resp, err := http.Post(app.Server.URL+"/sessions", "text/plain", bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 415)
+	assert.Empty(t, resp.Cookies())
+
+	jar, err := cookiejar.New(nil)
+	assert.Nil(t, err)
+	client := &http.Client{Jar: jar}
+	resp, err = client.Post(app.Server.URL+"/sessions", "application/json", bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	j := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&j))
+	url := app.Server.URL + "/v2/jobs/" + j.ID + "/runs"
+	resp, err = client.Post(url, "application/x-www-form-urlencoded", bytes.NewBufferString(`{}`))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 415)
+	resp, err = client.Post(url, "text/plain", bytes.NewBufferString(`{}`))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 415)
+
+	runs, err := app.Store.JobRunsFor(j.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestSessionsController_Create_InvalidCredentials(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{"wrong password", cltest.Username, "wrongpassword"},
+		{"unknown email", "nobody@example.com", cltest.Password},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := `{"email":"` + test.email + `","password":"` + test.password + `"}`
+			resp, err := http.Post(app.Server.URL+"/sessions", "application/json", bytes.NewBufferString(body))
+			assert.Nil(t, err)
+			cltest.CheckStatusCode(t, resp, 401)
+			assert.Empty(t, resp.Cookies())
+		})
+	}
+}
+
+func TestSessionsController_ForgedCookie(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	req, err := http.NewRequest("GET", app.Server.URL+"/v2/jobs", nil)
+	assert.Nil(t, err)
+	req.AddCookie(&http.Cookie{Name: "clsession", Value: "notasession"})
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+}
+
+func TestSessionsController_Create_PasswordNotLogged(t *testing.T) {
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+	observed := cltest.ObserveLogs()
+
+	body := `{"email":"` + cltest.Username + `","password":"` + cltest.Password + `"}`
+	resp, err := http.Post(app.Server.URL+"/sessions", "application/json", bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	logged := false
+	for _, entry := range observed.All() {
+		if entry.Message != "Web request" {
+			continue
+		}
+		logged = true
+		assert.Equal(t, `{"email":"`+cltest.Username+`","password":"[redacted]"}`, entry.ContextMap()["body"])
+	}
+	assert.True(t, logged)
+}
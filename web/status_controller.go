@@ -0,0 +1,177 @@
+package web
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/logger"
+	"github.com/smartcontractkit/chainlink/services"
+)
+
+// statusRateLimit is how many requests a client can make to the status
+// page per minute.
+const statusRateLimit = 60
+
+// StatusController serves the public status page, which lets oracle listing
+// services and consumers judge a node's reliability without exposing
+// anything about what the node runs.
+type StatusController struct {
+	App *services.ChainlinkApplication
+}
+
+// Show returns whether the node is up, how many jobs it supports, by
+// initiator type, and when it last fulfilled a run. Archived jobs are not
+// counted.
+// Example:
+//  "<application>/status"
+func (sc *StatusController) Show(c *gin.Context) {
+	jobs, err := sc.App.Store.Jobs()
+	if err != nil {
+		problem(c, 500, "Unable to load status")
+		return
+	}
+	last, err := sc.App.Store.LastFulfillment()
+	if err != nil {
+		problem(c, 500, "Unable to load status")
+		return
+	}
+
+	total, paused := 0, 0
+	initiators := map[string]int{}
+	for _, j := range jobs {
+		if j.Archived {
+			continue
+		}
+		total++
+		if j.Paused {
+			paused++
+		}
+		counted := map[string]bool{}
+		for _, initr := range j.Initiators {
+			if !counted[initr.Type] {
+				counted[initr.Type] = true
+				initiators[initr.Type]++
+			}
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"status": "ok",
+		"jobs": gin.H{
+			"total":      total,
+			"paused":     paused,
+			"initiators": initiators,
+		},
+		"lastFulfillmentAt": last,
+	})
+}
+
+// rateLimit lets each client IP make up to limit requests per window, and
+// responds with 429 Too Many Requests to any more than that. Clients are
+// told apart by the address they connect from, since X-Forwarded-For can
+// say anything; it is only believed when sent by one of the comma
+// separated trusted proxies.
+func rateLimit(limit int, window time.Duration, trustedProxies string) gin.HandlerFunc {
+	type usage struct {
+		start time.Time
+		count int
+	}
+	var mutex sync.Mutex
+	clients := map[string]*usage{}
+	trusted := parseTrustedProxies(trustedProxies)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := clientIP(c, trusted)
+		mutex.Lock()
+		u, ok := clients[ip]
+		if !ok || now.Sub(u.start) >= window {
+			for ip, other := range clients {
+				if now.Sub(other.start) >= window {
+					delete(clients, ip)
+				}
+			}
+			u = &usage{start: now}
+			clients[ip] = u
+		}
+		u.count++
+		exceeded := u.count > limit
+		retryAfter := u.start.Add(window).Sub(now)
+		mutex.Unlock()
+
+		if exceeded {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			abortProblem(c, 429, "Too many requests")
+			return
+		}
+		c.Next()
+	}
+}
+
+// parseTrustedProxies parses a comma separated list of IPs and CIDR
+// ranges, warning about and leaving out any that are invalid.
+func parseTrustedProxies(list string) []*net.IPNet {
+	nets := []*net.IPNet{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			logger.Warnw("Ignoring invalid trusted proxy", "proxy", entry, "error", err)
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+// clientIP returns the IP the request's connection comes from, or, if that
+// is a trusted proxy, the rightmost X-Forwarded-For entry that is not also
+// a trusted proxy, as entries to the left of it were written by the client.
+func clientIP(c *gin.Context, trusted []*net.IPNet) string {
+	ip := c.Request.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip, trusted) {
+		return ip
+	}
+	forwarded := strings.Split(c.GetHeader("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(hop, trusted) {
+			break
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range trusted {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package web_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestStatusController_Show_Disabled(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp, err := http.Get(app.Server.URL + "/status")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 404)
+}
+
+func TestStatusController_Show(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig()
+	config.StatusPageEnabled = true
+	app, cleanup := cltest.NewApplicationWithConfig(config)
+	defer cleanup()
+
+	webJob := cltest.NewJobWithWebInitiator()
+	assert.Nil(t, app.Store.SaveJob(&webJob))
+	paused := cltest.NewJobWithLogInitiator()
+	paused.Paused = true
+	assert.Nil(t, app.Store.SaveJob(&paused))
+	archived := cltest.NewJobWithWebInitiator()
+	archived.Archived = true
+	assert.Nil(t, app.Store.SaveJob(&archived))
+
+	completedAt := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	jr := webJob.NewRun()
+	jr.Status = models.StatusCompleted
+	jr.CompletedAt = null.TimeFrom(completedAt)
+	assert.Nil(t, app.Store.Save(&jr))
+
+	resp, err := http.Get(app.Server.URL + "/status")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	var status struct {
+		Status string `json:"status"`
+		Jobs   struct {
+			Total      int            `json:"total"`
+			Paused     int            `json:"paused"`
+			Initiators map[string]int `json:"initiators"`
+		} `json:"jobs"`
+		LastFulfillmentAt null.Time `json:"lastFulfillmentAt"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &status))
+	assert.Equal(t, "ok", status.Status)
+	assert.Equal(t, 2, status.Jobs.Total)
+	assert.Equal(t, 1, status.Jobs.Paused)
+	assert.Equal(t, map[string]int{"web": 1, "ethlog": 1}, status.Jobs.Initiators)
+	assert.True(t, completedAt.Equal(status.LastFulfillmentAt.Time))
+}
+
+func TestStatusController_Show_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig()
+	config.StatusPageEnabled = true
+	app, cleanup := cltest.NewApplicationWithConfig(config)
+	defer cleanup()
+
+	for i := 0; i < 60; i++ {
+		resp, err := http.Get(app.Server.URL + "/status")
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+
+	resp, err := http.Get(app.Server.URL + "/status")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 429)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+}
+
+func TestStatusController_Show_RateLimitedForwardedFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		trustedProxies string
+		want           int
+	}{
+		{"untrusted proxy", "", 429},
+		{"trusted proxy", "10.0.0.0/8, 127.0.0.1", 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, _ := cltest.NewConfig()
+			config.StatusPageEnabled = true
+			config.TrustedProxies = test.trustedProxies
+			app, cleanup := cltest.NewApplicationWithConfig(config)
+			defer cleanup()
+
+			get := func(forwardedFor string) *http.Response {
+				req, err := http.NewRequest("GET", app.Server.URL+"/status", nil)
+				assert.Nil(t, err)
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				resp, err := http.DefaultClient.Do(req)
+				assert.Nil(t, err)
+				return resp
+			}
+
+			for i := 0; i < 60; i++ {
+				resp := get(fmt.Sprintf("203.0.113.%d", i))
+				resp.Body.Close()
+				assert.Equal(t, 200, resp.StatusCode)
+			}
+
+			resp := get("198.51.100.1, 10.1.2.3")
+			defer resp.Body.Close()
+			assert.Equal(t, test.want, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+)
+
+// TOTPController sets up two-factor authentication for the logged in user.
+type TOTPController struct {
+	App *services.ChainlinkApplication
+}
+
+// Create gives the user a new two-factor secret, along with the otpauth
+// URI to show as a QR code for their authenticator app. It is not required
+// to log in until it is confirmed.
+// Example:
+//  "<application>/totp"
+func (tc *TOTPController) Create(c *gin.Context) {
+	user, ok := tc.currentUser(c)
+	if !ok {
+		return
+	}
+	if user.Provider == ProviderOIDC {
+		problem(c, 400, "Two-factor authentication is left to the OpenID Connect provider")
+		return
+	}
+	secret, err := user.ProvisionTOTP()
+	if err != nil {
+		problem(c, 400, err.Error())
+	} else if err = tc.App.Store.Save(&user); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{
+			"secret": secret,
+			"uri":    models.TOTPURI(user.Email, secret),
+		})
+	}
+}
+
+// Confirm enables two-factor authentication once given a code from the
+// user's authenticator app, and responds with their recovery codes. The
+// response is the only time the recovery codes are shown, as only their
+// hashes are stored.
+// Example:
+//  "<application>/totp/confirm"
+func (tc *TOTPController) Confirm(c *gin.Context) {
+	user, ok := tc.currentUser(c)
+	if !ok {
+		return
+	}
+	var request struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	codes, err := user.EnableTOTP(request.Code, time.Now())
+	if err != nil {
+		problem(c, 400, err.Error())
+	} else if err = tc.App.Store.Save(&user); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"recoveryCodes": codes})
+	}
+}
+
+// Destroy disables two-factor authentication, given a current code or a
+// recovery code, so that a stolen session cannot turn it off.
+// Example:
+//  "<application>/totp"
+func (tc *TOTPController) Destroy(c *gin.Context) {
+	user, ok := tc.currentUser(c)
+	if !ok {
+		return
+	}
+	var request struct {
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recoveryCode"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	if user.TOTPEnabled && !checkSecondFactor(&user, request.Code, request.RecoveryCode) {
+		problem(c, 400, "Invalid two-factor authentication code")
+		return
+	}
+	user.DisableTOTP()
+	if err := tc.App.Store.Save(&user); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"email": user.Email})
+	}
+}
+
+// currentUser returns the user of the request's session. API tokens do not
+// belong to a user, so cannot set up two-factor authentication.
+func (tc *TOTPController) currentUser(c *gin.Context) (models.User, bool) {
+	user, err := tc.App.Store.FindUser(identity(c))
+	if err != nil {
+		problem(c, 403, "Two-factor authentication is set up by logged in users")
+		return user, false
+	}
+	return user, true
+}
+
+// checkSecondFactor returns true if the user gave their current two-factor
+// code, or one of their recovery codes, which is used up.
+func checkSecondFactor(user *models.User, code, recoveryCode string) bool {
+	if code != "" {
+		return user.CheckTOTP(code, time.Now())
+	}
+	return recoveryCode != "" && user.UseRecoveryCode(recoveryCode)
+}
@@ -0,0 +1,103 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func login(t *testing.T, url string, credentials map[string]string) (*http.Client, *http.Response) {
+	jar, err := cookiejar.New(nil)
+	assert.Nil(t, err)
+	client := &http.Client{Jar: jar}
+	credentials["email"] = cltest.Username
+	credentials["password"] = cltest.Password
+	body, err := json.Marshal(credentials)
+	assert.Nil(t, err)
+	resp, err := client.Post(url+"/sessions", "application/json", bytes.NewBuffer(body))
+	assert.Nil(t, err)
+	return client, resp
+}
+
+func TestTOTPController(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/totp", bytes.NewBufferString(`{}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	var provisioned struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &provisioned))
+	assert.Contains(t, provisioned.URI, "secret="+provisioned.Secret)
+
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/totp/confirm", bytes.NewBufferString(`{"code":"abcdef"}`))
+	cltest.CheckStatusCode(t, resp, 400)
+
+	now := time.Now()
+	code, err := models.TOTPCode(provisioned.Secret, now)
+	assert.Nil(t, err)
+	resp = cltest.AuthenticatedPost(app.Server.URL+"/v2/totp/confirm", bytes.NewBufferString(`{"code":"`+code+`"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	var confirmed struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	assert.Nil(t, json.Unmarshal(cltest.ParseResponseBody(resp), &confirmed))
+	assert.Equal(t, models.RecoveryCodeCount, len(confirmed.RecoveryCodes))
+
+	_, resp = login(t, app.Server.URL, map[string]string{})
+	cltest.CheckStatusCode(t, resp, 401)
+	assert.Contains(t, string(cltest.ParseResponseBody(resp)), `"code":"totp_required"`)
+	assert.Empty(t, resp.Cookies())
+
+	_, resp = login(t, app.Server.URL, map[string]string{"totp": code})
+	cltest.CheckStatusCode(t, resp, 401)
+
+	next, err := models.TOTPCode(provisioned.Secret, now.Add(30*time.Second))
+	assert.Nil(t, err)
+	_, resp = login(t, app.Server.URL, map[string]string{"totp": next})
+	cltest.CheckStatusCode(t, resp, 200)
+
+	client, resp := login(t, app.Server.URL, map[string]string{"recoveryCode": confirmed.RecoveryCodes[0]})
+	cltest.CheckStatusCode(t, resp, 200)
+	_, resp = login(t, app.Server.URL, map[string]string{"recoveryCode": confirmed.RecoveryCodes[0]})
+	cltest.CheckStatusCode(t, resp, 401)
+
+	body := bytes.NewBufferString(`{"recoveryCode":"` + confirmed.RecoveryCodes[1] + `"}`)
+	req, err := http.NewRequest("DELETE", app.Server.URL+"/v2/totp", body)
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = client.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	_, resp = login(t, app.Server.URL, map[string]string{})
+	cltest.CheckStatusCode(t, resp, 200)
+}
+
+func TestTOTPController_APIToken(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	apiToken, token := models.NewAPIToken("ci", models.RoleAdmin)
+	assert.Nil(t, app.Store.Save(&apiToken))
+
+	req, err := http.NewRequest("POST", app.Server.URL+"/v2/totp", bytes.NewBufferString(`{}`))
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 403)
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+)
+
+// TxsController builds transactions for the operator to sign offline, on a
+// machine holding the key, and broadcasts them once signed.
+type TxsController struct {
+	App *services.ChainlinkApplication
+}
+
+// Build returns an unsigned transaction to the given address with the given
+// data, from the given account or else the node's, with the account's next
+// nonce and the default gas price.
+// Example:
+//  "<application>/txs/unsigned"
+func (tc *TxsController) Build(c *gin.Context) {
+	var request struct {
+		From     *common.Address `json:"from"`
+		To       common.Address  `json:"to"`
+		Data     hexutil.Bytes   `json:"data"`
+		Value    *big.Int        `json:"value"`
+		GasLimit uint64          `json:"gasLimit"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	store := tc.App.Store
+	if request.From == nil {
+		if !store.Signer.HasAccounts() {
+			problem(c, 400, "Must pass from, as the node has no account")
+			return
+		}
+		address := store.Signer.GetAccount().Address
+		request.From = &address
+	}
+
+	tx, err := store.TxManager.BuildUnsignedTx(*request.From, request.To, request.Data, request.Value, request.GasLimit)
+	if err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, tx)
+	}
+}
+
+// Broadcast sends a transaction signed offline, given as hex encoded RLP.
+// Example:
+//  "<application>/txs/broadcast"
+func (tc *TxsController) Broadcast(c *gin.Context) {
+	var request struct {
+		Signed string `json:"signed"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 400, err.Error())
+		return
+	}
+
+	if hash, err := tc.App.Store.TxManager.BroadcastSignedTx(request.Signed); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"hash": hash})
+	}
+}
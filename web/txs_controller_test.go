@@ -0,0 +1,27 @@
+package web_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+)
+
+func TestTxsController_Build_NoAccount(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"to":"` + cltest.NewAddress().Hex() + `"}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/txs/unsigned", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 400)
+}
+
+func TestTxsController_Broadcast_NotATransaction(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/txs/broadcast", bytes.NewBufferString(`{"signed":"0xdeadbeef"}`))
+	cltest.CheckStatusCode(t, resp, 500)
+}
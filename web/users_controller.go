@@ -0,0 +1,155 @@
+package web
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/asdine/storm"
+	"github.com/gin-gonic/gin"
+	"github.com/smartcontractkit/chainlink/services"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/smartcontractkit/chainlink/store/presenters"
+)
+
+// UsersController manages the users who can log in to the node's API.
+type UsersController struct {
+	App *services.ChainlinkApplication
+}
+
+// Index lists the node's users.
+// Example:
+//  "<application>/users"
+func (uc *UsersController) Index(c *gin.Context) {
+	users := []models.User{}
+	if err := uc.App.Store.All(&users); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewUsers(users))
+	}
+}
+
+// Create adds a user with the given email, password, and role.
+// Example:
+//  "<application>/users"
+func (uc *UsersController) Create(c *gin.Context) {
+	var request struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		Role     string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	user, err := models.NewUser(request.Email, request.Password, request.Role)
+	if err == nil {
+		if _, findErr := uc.App.Store.FindUser(user.Email); findErr == nil {
+			err = errors.New("User " + user.Email + " already exists")
+		}
+	}
+	if err != nil {
+		problem(c, 400, err.Error())
+	} else if err = uc.App.Store.Save(&user); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewUser(user))
+	}
+}
+
+// Update changes the role or password of the user with the given email,
+// or turns off their two-factor authentication with "disableTotp", for
+// users who have lost their authenticator app and recovery codes. The last
+// admin cannot be given another role.
+// Example:
+//  "<application>/users/:Email"
+func (uc *UsersController) Update(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok {
+		return
+	}
+	var request struct {
+		Password    string `json:"password"`
+		Role        string `json:"role"`
+		DisableTOTP bool   `json:"disableTotp"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem(c, 500, err.Error())
+		return
+	}
+
+	if request.Role != "" && !models.ValidRole(request.Role) {
+		problem(c, 400, "Role "+request.Role+" does not exist")
+		return
+	}
+	if request.Role != "" && request.Role != models.RoleAdmin && uc.lastAdmin(c, user) {
+		return
+	}
+	if request.Password != "" {
+		if err := user.SetPassword(request.Password); err != nil {
+			problem(c, 400, err.Error())
+			return
+		}
+	}
+	if request.Role != "" {
+		user.Role = request.Role
+	}
+	if request.DisableTOTP {
+		user.DisableTOTP()
+	}
+
+	if err := uc.App.Store.Save(&user); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, presenters.NewUser(user))
+	}
+}
+
+// Destroy deletes the user with the given email and ends their sessions.
+// The last admin cannot be deleted.
+// Example:
+//  "<application>/users/:Email"
+func (uc *UsersController) Destroy(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok || uc.lastAdmin(c, user) {
+		return
+	}
+
+	if err := uc.App.Store.DeleteUser(user); err != nil {
+		problem(c, 500, err.Error())
+	} else {
+		c.JSON(200, gin.H{"email": user.Email})
+	}
+}
+
+func (uc *UsersController) findUser(c *gin.Context) (models.User, bool) {
+	user, err := uc.App.Store.FindUser(strings.ToLower(c.Param("Email")))
+	if err == storm.ErrNotFound {
+		problem(c, 404, "User not found")
+		return user, false
+	} else if err != nil {
+		problem(c, 500, err.Error())
+		return user, false
+	}
+	return user, true
+}
+
+// lastAdmin responds with an error and returns true if the given user is
+// the node's only admin, so that the node is not left without one.
+func (uc *UsersController) lastAdmin(c *gin.Context, user models.User) bool {
+	if !models.RoleAllows(user.Role, models.RoleAdmin) {
+		return false
+	}
+	users := []models.User{}
+	if err := uc.App.Store.All(&users); err != nil {
+		problem(c, 500, err.Error())
+		return true
+	}
+	for _, other := range users {
+		if other.Email != user.Email && models.RoleAllows(other.Role, models.RoleAdmin) {
+			return false
+		}
+	}
+	problem(c, 405, "Cannot remove the node's last admin")
+	return true
+}
@@ -0,0 +1,99 @@
+package web_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/cmd"
+	"github.com/smartcontractkit/chainlink/internal/cltest"
+	"github.com/smartcontractkit/chainlink/store/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersController_Roles(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	body := `{"email":"monitor@example.com","password":"hunter2","role":"view"}`
+	resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/users", bytes.NewBufferString(body))
+	cltest.CheckStatusCode(t, resp, 200)
+	assert.NotContains(t, string(cltest.ParseResponseBody(resp)), "hashedPassword")
+
+	viewer := cmd.NewSessionClient(app.Server.URL, "monitor@example.com", "hunter2")
+	resp, err := viewer.Get("/v2/runs")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+	resp, err = viewer.Post("/v2/jobs", bytes.NewBuffer(cltest.LoadJSON("../internal/fixtures/web/hello_world_job.json")))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 403)
+	resp, err = viewer.Get("/v2/users")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 403)
+
+	resp = cltest.AuthenticatedPatch(app.Server.URL+"/v2/users/monitor@example.com", bytes.NewBufferString(`{"role":"edit"}`))
+	cltest.CheckStatusCode(t, resp, 200)
+	resp, err = viewer.Post("/v2/jobs", bytes.NewBuffer(cltest.LoadJSON("../internal/fixtures/web/hello_world_job.json")))
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 200)
+
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/users/monitor@example.com")
+	cltest.CheckStatusCode(t, resp, 200)
+	resp, err = viewer.Get("/v2/runs")
+	assert.Nil(t, err)
+	cltest.CheckStatusCode(t, resp, 401)
+}
+
+func TestUsersController_Create_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown role", `{"email":"monitor@example.com","password":"hunter2","role":"root"}`},
+		{"no password", `{"email":"monitor@example.com","role":"view"}`},
+		{"existing user", `{"email":"` + cltest.Username + `","password":"hunter2","role":"view"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := cltest.AuthenticatedPost(app.Server.URL+"/v2/users", bytes.NewBufferString(test.body))
+			cltest.CheckStatusCode(t, resp, 400)
+		})
+	}
+
+	user, err := app.Store.FindUser(cltest.Username)
+	assert.Nil(t, err)
+	assert.True(t, user.CheckPassword(cltest.Password))
+}
+
+func TestUsersController_LastAdmin(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedPatch(app.Server.URL+"/v2/users/"+cltest.Username, bytes.NewBufferString(`{"role":"view"}`))
+	cltest.CheckStatusCode(t, resp, 405)
+	resp = cltest.AuthenticatedDelete(app.Server.URL + "/v2/users/" + cltest.Username)
+	cltest.CheckStatusCode(t, resp, 405)
+
+	user, err := app.Store.FindUser(cltest.Username)
+	assert.Nil(t, err)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+}
+
+func TestUsersController_NotFound(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication()
+	defer cleanup()
+
+	resp := cltest.AuthenticatedDelete(app.Server.URL + "/v2/users/nobody@example.com")
+	cltest.CheckStatusCode(t, resp, 404)
+}